@@ -3,6 +3,7 @@ package baseapp
 import (
 	"fmt"
 	"runtime/debug"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -13,8 +14,8 @@ import (
 
 	//"github.com/cosmos/cosmos-sdk/store"
 	"inschain-tendermint/store"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 )
 
 // Key to store the header in the DB itself.
@@ -26,12 +27,14 @@ var dbHeaderKey = []byte("header")
 // The ABCI application
 type BaseApp struct {
 	// initialized on creation
-	Logger     log.Logger
-	name       string               // application name from abci.Info
-	db         dbm.DB               // common DB backend
-	cms        sdk.CommitMultiStore // Main (uncached) state
-	router     Router               // handle any kind of message
-	codespacer *sdk.Codespacer      // handle module codespacing
+	Logger      log.Logger
+	name        string               // application name from abci.Info
+	db          dbm.DB               // common DB backend
+	cms         sdk.CommitMultiStore // Main (uncached) state
+	router      Router               // handle any kind of message
+	queryRouter QueryRouter          // route custom queries to the modules
+	codespacer  *sdk.Codespacer      // handle module codespacing
+	eventBus    *EventBus            // publish committed tx events
 
 	// must be set
 	txDecoder   sdk.TxDecoder   // unmarshal []byte into sdk.Tx
@@ -52,58 +55,31 @@ type BaseApp struct {
 	checkState   *state           // for CheckTx
 	deliverState *state           // for DeliverTx
 	valUpdates   []abci.Validator // cached validator changes from DeliverTx
-}
-
-var _ abci.Application = (*BaseApp)(nil)
+	txIndex      int              // index of the next tx in the block, reset in BeginBlock
+	blockGasUsed int64            // gas used by the txs of the block, reset in BeginBlock
 
-//Transaction listener extension 
-type TransactionListener struct {
-	listeners map[string][]func(ctx sdk.Context, msg sdk.Msg, result sdk.Result) 
-}
-
-var Listeners TransactionListener
-
-// Add an event listener to the TransactionListener struct instance
-func (tl *TransactionListener) AddListener(event string, handlerFunc func(ctx sdk.Context, msg sdk.Msg, result sdk.Result)) {
-    if tl.listeners == nil {
-        tl.listeners = make(map[string][]func(ctx sdk.Context, msg sdk.Msg, result sdk.Result))
-    }
-    if _, ok := tl.listeners[event]; ok {
-        tl.listeners[event] = append(tl.listeners[event], handlerFunc)
-    } else {
-        tl.listeners[event] = []func(ctx sdk.Context, msg sdk.Msg, result sdk.Result) {handlerFunc}
-    }
-}
+	// maximum gas used by the txs of a block, 0 for no limit
+	maxBlockGas int64
 
-// Remove an event listener from the TransactionListener struct instance
-func (tl *TransactionListener) RemoveListener(event string, handlerFunc func(ctx sdk.Context, msg sdk.Msg, result sdk.Result)) {
-    if _, ok := tl.listeners[event]; ok {
-        for i := range tl.listeners[event] {
-	        tl.listeners[event] = append(tl.listeners[event][:i], tl.listeners[event][i+1:]...)
-        }
-    }
+	// minimum gas prices of this node, checked in CheckTx
+	minGasPrices sdk.GasPrices
 }
 
-// Notify event listeners
-func (tl *TransactionListener) NotifyListeners(ctx sdk.Context, msg sdk.Msg, result sdk.Result) {
-	msgListeners := tl.listeners[msg.Type()]
-	
-    for _, msgListener := range msgListeners {
-	    	msgListener(ctx, msg, result)
-    }
-}
+var _ abci.Application = (*BaseApp)(nil)
 
 // Create and name new BaseApp
 // NOTE: The db is used to store the version number for now.
 func NewBaseApp(name string, cdc *wire.Codec, logger log.Logger, db dbm.DB) *BaseApp {
 	app := &BaseApp{
-		Logger:     logger,
-		name:       name,
-		db:         db,
-		cms:        store.NewCommitMultiStore(db),
-		router:     NewRouter(),
-		codespacer: sdk.NewCodespacer(),
-		txDecoder:  defaultTxDecoder(cdc),
+		Logger:      logger,
+		name:        name,
+		db:          db,
+		cms:         store.NewCommitMultiStore(db),
+		router:      NewRouter(),
+		queryRouter: NewQueryRouter(),
+		codespacer:  sdk.NewCodespacer(),
+		eventBus:    NewEventBus(logger.With("module", "events")),
+		txDecoder:   defaultTxDecoder(cdc),
 	}
 	// Register the undefined & root codespaces, which should not be used by any modules
 	app.codespacer.RegisterOrPanic(sdk.CodespaceUndefined)
@@ -152,7 +128,7 @@ func defaultTxDecoder(cdc *wire.Codec) sdk.TxDecoder {
 			return nil, sdk.ErrTxDecode("txBytes are empty")
 		}
 
-		// StdTx.Msg is an interface. The concrete types
+		// StdTx.Msgs are interfaces. The concrete types
 		// are registered by MakeTxCodec
 		err := cdc.UnmarshalBinary(txBytes, &tx)
 		if err != nil {
@@ -162,6 +138,18 @@ func defaultTxDecoder(cdc *wire.Codec) sdk.TxDecoder {
 	}
 }
 
+// SetMaxBlockGas sets the gas limit of a block, from the consensus params.
+// 0 means no limit.
+func (app *BaseApp) SetMaxBlockGas(maxGas int64) {
+	app.maxBlockGas = maxGas
+}
+
+// SetMinGasPrices sets the minimum gas prices the fee of a tx must pay
+// to be accepted in the mempool of this node. It is not a consensus rule.
+func (app *BaseApp) SetMinGasPrices(prices sdk.GasPrices) {
+	app.minGasPrices = prices
+}
+
 // nolint - Set functions
 func (app *BaseApp) SetInitChainer(initChainer sdk.InitChainer) {
 	app.initChainer = initChainer
@@ -177,6 +165,12 @@ func (app *BaseApp) SetAnteHandler(ah sdk.AnteHandler) {
 }
 func (app *BaseApp) Router() Router { return app.router }
 
+// QueryRouter returns the router of the custom module queries
+func (app *BaseApp) QueryRouter() QueryRouter { return app.queryRouter }
+
+// EventBus returns the bus publishing the events of committed blocks
+func (app *BaseApp) EventBus() *EventBus { return app.eventBus }
+
 // load latest application version
 func (app *BaseApp) LoadLatestVersion(mainKey sdk.StoreKey) error {
 	app.cms.LoadLatestVersion()
@@ -316,8 +310,14 @@ func (app *BaseApp) InitChain(req abci.RequestInitChain) (res abci.ResponseInitC
 }
 
 // Implements ABCI.
-// Delegates to CommitMultiStore if it implements Queryable
+// Queries under /custom are routed to the querier of a module,
+// the others are delegated to CommitMultiStore if it implements Queryable
 func (app *BaseApp) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
+	path := strings.Split(strings.TrimPrefix(req.Path, "/"), "/")
+	if path[0] == "custom" {
+		return app.queryCustom(path[1:], req)
+	}
+
 	queryable, ok := app.cms.(sdk.Queryable)
 	if !ok {
 		msg := "application doesn't support queries"
@@ -326,6 +326,34 @@ func (app *BaseApp) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
 	return queryable.Query(req)
 }
 
+// run the querier of a module against the last committed state
+func (app *BaseApp) queryCustom(path []string, req abci.RequestQuery) (res abci.ResponseQuery) {
+	if len(path) == 0 || path[0] == "" {
+		return sdk.ErrUnknownRequest("No module specified in custom query").QueryResult()
+	}
+	querier := app.queryRouter.Route(path[0])
+	if querier == nil {
+		msg := fmt.Sprintf("No querier found for module %s", path[0])
+		return sdk.ErrUnknownRequest(msg).QueryResult()
+	}
+
+	// the cache is never written, the queries can't change the state
+	var header abci.Header
+	if app.checkState != nil {
+		header = app.checkState.ctx.BlockHeader()
+	}
+	ctx := sdk.NewContext(app.cms.CacheMultiStore(), header, true, nil, app.Logger)
+	value, err := querier(ctx, path[1:], req)
+	if err != nil {
+		return err.QueryResult()
+	}
+	return abci.ResponseQuery{
+		Code:   uint32(sdk.ABCICodeOK),
+		Value:  value,
+		Height: app.LastBlockHeight(),
+	}
+}
+
 // Implements ABCI
 func (app *BaseApp) BeginBlock(req abci.RequestBeginBlock) (res abci.ResponseBeginBlock) {
 	// Initialize the DeliverTx state.
@@ -336,6 +364,9 @@ func (app *BaseApp) BeginBlock(req abci.RequestBeginBlock) (res abci.ResponseBeg
 		app.setDeliverState(req.Header)
 	}
 	app.valUpdates = nil
+	app.txIndex = 0
+	app.blockGasUsed = 0
+	app.eventBus.reset()
 	if app.beginBlocker != nil {
 		res = app.beginBlocker(app.deliverState.ctx, req)
 	}
@@ -376,6 +407,8 @@ func (app *BaseApp) DeliverTx(txBytes []byte) (res abci.ResponseDeliverTx) {
 	} else {
 		result = app.runTx(false, txBytes, tx)
 	}
+	// keep event tx indexes aligned with the txs of the block
+	app.txIndex++
 
 	// After-handler hooks.
 	if result.IsOK() {
@@ -401,84 +434,158 @@ func (app *BaseApp) Check(tx sdk.Tx) (result sdk.Result) {
 	return app.runTx(true, nil, tx)
 }
 func (app *BaseApp) Deliver(tx sdk.Tx) (result sdk.Result) {
-	return app.runTx(false, nil, tx)
+	result = app.runTx(false, nil, tx)
+	app.txIndex++
+	return
 }
 
 // txBytes may be nil in some cases, eg. in tests.
 // Also, in the future we may support "internal" transactions.
 func (app *BaseApp) runTx(isCheckTx bool, txBytes []byte, tx sdk.Tx) (result sdk.Result) {
-	// Handle any panics.
+	// Get the state and the context.
+	// Each tx gets its own gas meter, the ante handler sets its limit.
+	var st *state
+	if isCheckTx {
+		st = app.checkState
+	} else {
+		st = app.deliverState
+	}
+	ctx := st.ctx.WithTxBytes(txBytes).WithGasMeter(sdk.NewInfiniteGasMeter())
+	if isCheckTx {
+		ctx = ctx.WithMinGasPrices(app.minGasPrices)
+	}
+	var gasWanted int64
+
+	// Handle any panics, and report the gas.
 	defer func() {
 		if r := recover(); r != nil {
-			log := fmt.Sprintf("Recovered: %v\nstack:\n%v", r, string(debug.Stack()))
-			result = sdk.ErrInternal(log).Result()
+			switch r := r.(type) {
+			case sdk.ErrorOutOfGas:
+				log := fmt.Sprintf("Out of gas in location: %v", r.Descriptor)
+				result = sdk.ErrOutOfGas(log).Result()
+			default:
+				log := fmt.Sprintf("Recovered: %v\nstack:\n%v", r, string(debug.Stack()))
+				result = sdk.ErrInternal(log).Result()
+			}
+		}
+		result.GasWanted = gasWanted
+		result.GasUsed = ctx.GasMeter().GasConsumed()
+		if !isCheckTx {
+			app.blockGasUsed += result.GasUsed
 		}
 	}()
 
-	// Get the Msg.
-	var msg = tx.GetMsg()
-	if msg == nil {
-		return sdk.ErrInternal("Tx.GetMsg() returned nil").Result()
+	// Get the Msgs.
+	var msgs = tx.GetMsgs()
+	if len(msgs) == 0 {
+		return sdk.ErrInternal("Tx.GetMsgs() returned no msgs").Result()
 	}
 
-	// Validate the Msg.
-	err := msg.ValidateBasic()
-	if err != nil {
-		err = err.WithDefaultCodespace(sdk.CodespaceRoot)
-		return err.Result()
+	// Validate the Msgs.
+	for _, msg := range msgs {
+		if msg == nil {
+			return sdk.ErrInternal("Tx.GetMsgs() returned a nil msg").Result()
+		}
+		err := msg.ValidateBasic()
+		if err != nil {
+			err = err.WithDefaultCodespace(sdk.CodespaceRoot)
+			return err.Result()
+		}
 	}
 
-	// Get the context
-	var ctx sdk.Context
-	if isCheckTx {
-		ctx = app.checkState.ctx.WithTxBytes(txBytes)
-	} else {
-		ctx = app.deliverState.ctx.WithTxBytes(txBytes)
+	// Txs which do not fit in the block are rejected before any work.
+	if !isCheckTx && app.maxBlockGas > 0 && app.blockGasUsed >= app.maxBlockGas {
+		return sdk.ErrBlockGasLimit("No gas left in the block").Result()
 	}
 
 	// Run the ante handler.
+	// It runs in its own cache: its effects, namely fee deductions and
+	// sequence incrementing, are kept even if the msgs fail, but not if
+	// it aborts or runs out of gas itself.
 	if app.anteHandler != nil {
-		newCtx, result, abort := app.anteHandler(ctx, tx)
-		if abort {
-			return result
-		}
+		anteCache := st.CacheMultiStore()
+		newCtx, anteResult, abort := app.anteHandler(ctx.WithMultiStore(anteCache), tx)
 		if !newCtx.IsZero() {
 			ctx = newCtx
 		}
+		gasWanted = anteResult.GasWanted
+		if abort {
+			return anteResult
+		}
+		anteCache.Write()
 	}
-
-	// Match route.
-	msgType := msg.Type()
-	handler := app.router.Route(msgType)
-	if handler == nil {
-		return sdk.ErrUnknownRequest("Unrecognized Msg type: " + msgType).Result()
+	if app.maxBlockGas > 0 && gasWanted > app.maxBlockGas {
+		return sdk.ErrBlockGasLimit(fmt.Sprintf("Gas wanted %d exceeds the block gas limit %d", gasWanted, app.maxBlockGas)).Result()
 	}
 
-	// Get the correct cache
-	var msCache sdk.CacheMultiStore
-	if isCheckTx == true {
-		// CacheWrap app.checkState.ms in case it fails.
-		msCache = app.checkState.CacheMultiStore()
-		ctx = ctx.WithMultiStore(msCache)
-	} else {
-		// CacheWrap app.deliverState.ms in case it fails.
-		msCache = app.deliverState.CacheMultiStore()
-		ctx = ctx.WithMultiStore(msCache)
+	// Match routes.
+	handlers := make([]sdk.Handler, len(msgs))
+	for i, msg := range msgs {
+		msgType := msg.Type()
+		handlers[i] = app.router.Route(msgType)
+		if handlers[i] == nil {
+			return sdk.ErrUnknownRequest("Unrecognized Msg type: " + msgType).Result()
+		}
+	}
 
+	// CacheWrap app.checkState.ms or app.deliverState.ms in case it fails.
+	msCache := st.CacheMultiStore()
+	ctx = ctx.WithMultiStore(msCache)
+
+	// Run the msgs in order in the same cache,
+	// they are either all applied or none of them is.
+	msgResults := make([]sdk.Result, 0, len(msgs))
+	logs := make(sdk.MsgLogs, 0, len(msgs))
+	for i, msg := range msgs {
+		msgResult := handlers[i](ctx, msg)
+		msgResults = append(msgResults, msgResult)
+		logs = append(logs, sdk.MsgLog{
+			MsgIndex:   i,
+			Success:    msgResult.IsOK(),
+			Log:        msgResult.Log,
+			TagCount:   len(msgResult.Tags),
+			DataLength: len(msgResult.Data),
+		})
+		if !msgResult.IsOK() {
+			result = msgResult
+			break
+		}
+		result.Data = append(result.Data, msgResult.Data...)
+		result.Tags = result.Tags.AppendTags(msgResult.Tags)
+		result.ValidatorUpdates = append(result.ValidatorUpdates, msgResult.ValidatorUpdates...)
 	}
+	result.Log = logs.String()
 
-	result = handler(ctx, msg)
+	// The msgs are reverted if the tx does not fit in the block.
+	if result.IsOK() && !isCheckTx && app.maxBlockGas > 0 &&
+		app.blockGasUsed+ctx.GasMeter().GasConsumed() > app.maxBlockGas {
+		result = sdk.ErrBlockGasLimit("Block gas limit exceeded").Result()
+	}
 
-	// If result was successful, write to app.checkState.ms or app.deliverState.ms
+	// If all the msgs were successful, write to app.checkState.ms or app.deliverState.ms
 	if result.IsOK() {
 		msCache.Write()
 	}
 
-	//Notify listeners
+	// Record the events, they are published once the block is committed
 	if !isCheckTx {
-		Listeners.NotifyListeners(ctx, msg, result)
+		for i, msg := range msgs {
+			msgResult := result
+			if result.IsOK() {
+				msgResult = msgResults[i]
+			}
+			app.eventBus.record(TxEvent{
+				Height:   ctx.BlockHeight(),
+				TxIndex:  app.txIndex,
+				MsgIndex: i,
+				MsgType:  MsgTypeName(msg),
+				Module:   msg.Type(),
+				Msg:      msg,
+				Result:   msgResult,
+			})
+		}
 	}
-	
+
 	return result
 }
 
@@ -511,6 +618,9 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 		"commit", commitID,
 	)
 
+	// Publish the events of the committed block
+	app.eventBus.publish()
+
 	// Reset the Check state to the latest committed
 	// NOTE: safe because Tendermint holds a lock on the mempool for Commit.
 	// Use the header from this latest block.
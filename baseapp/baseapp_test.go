@@ -15,7 +15,7 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 func defaultLogger() log.Logger {
@@ -307,6 +307,193 @@ func TestQuery(t *testing.T) {
 	assert.Equal(t, value, res.Value)
 }
 
+// Test that custom queries are routed to the querier of the module,
+// against the committed state
+func TestCustomQuery(t *testing.T) {
+	app := newBaseApp(t.Name())
+
+	capKey := sdk.NewKVStoreKey("main")
+	app.MountStoresIAVL(capKey)
+	err := app.LoadLatestVersion(capKey) // needed to make stores non-nil
+	assert.Nil(t, err)
+
+	key, value := []byte("hello"), []byte("goodbye")
+
+	app.SetAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+	app.Router().AddRoute(msgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		ctx.KVStore(capKey).Set(key, value)
+		return sdk.Result{}
+	})
+	app.QueryRouter().AddRoute("test", func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) != 1 || path[0] != "value" {
+			return nil, sdk.ErrUnknownRequest("unknown test query")
+		}
+		return ctx.KVStore(capKey).Get(req.Data), nil
+	})
+
+	query := abci.RequestQuery{
+		Path: "/custom/test/value",
+		Data: key,
+	}
+
+	// the state is only visible once committed
+	res := app.Query(query)
+	assert.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, 0, len(res.Value))
+	app.BeginBlock(abci.RequestBeginBlock{})
+	app.Deliver(testUpdatePowerTx{})
+	res = app.Query(query)
+	assert.Equal(t, 0, len(res.Value))
+	app.Commit()
+	res = app.Query(query)
+	assert.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, value, res.Value)
+	assert.Equal(t, app.LastBlockHeight(), res.Height)
+
+	// unknown routes and modules are rejected
+	query.Path = "/custom/test/other"
+	res = app.Query(query)
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnknownRequest), sdk.ABCICodeType(res.Code))
+	query.Path = "/custom/other/value"
+	res = app.Query(query)
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnknownRequest), sdk.ABCICodeType(res.Code))
+	query.Path = "/custom"
+	res = app.Query(query)
+	assert.False(t, res.IsOK())
+}
+
+// A mock transaction with many msgs.
+type testMultiTx struct {
+	msgs []sdk.Msg
+}
+
+func (tx testMultiTx) GetMsgs() []sdk.Msg                { return tx.msgs }
+func (tx testMultiTx) GetSignatures() []sdk.StdSignature { return nil }
+
+// A mock msg setting a key, or failing.
+type testSetMsg struct {
+	key  string
+	fail bool
+}
+
+const setMsgType = "testSetMsg"
+
+func (msg testSetMsg) Type() string              { return setMsgType }
+func (msg testSetMsg) GetSignBytes() []byte      { return nil }
+func (msg testSetMsg) ValidateBasic() sdk.Error  { return nil }
+func (msg testSetMsg) GetSigners() []sdk.Address { return nil }
+
+// Test that the msgs of a tx are applied atomically, with a log per msg.
+func TestMultiMsgDeliverTx(t *testing.T) {
+	app := newBaseApp(t.Name())
+
+	// make a cap key and mount the store
+	capKey := sdk.NewKVStoreKey("main")
+	app.MountStoresIAVL(capKey)
+	err := app.LoadLatestVersion(capKey) // needed to make stores non-nil
+	assert.Nil(t, err)
+
+	app.SetAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+	app.Router().AddRoute(setMsgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		setMsg := msg.(testSetMsg)
+		if setMsg.fail {
+			return sdk.ErrUnknownRequest("failing msg").Result()
+		}
+		ctx.KVStore(capKey).Set([]byte(setMsg.key), []byte(setMsg.key))
+		return sdk.Result{Log: setMsg.key, Data: []byte(setMsg.key)}
+	})
+
+	app.BeginBlock(abci.RequestBeginBlock{})
+	store := app.deliverState.ms.GetKVStore(capKey)
+
+	// a failing msg reverts the previous ones
+	res := app.Deliver(testMultiTx{[]sdk.Msg{testSetMsg{key: "a"}, testSetMsg{fail: true}, testSetMsg{key: "b"}}})
+	assert.False(t, res.IsOK())
+	assert.Nil(t, store.Get([]byte("a")))
+	logs, err := sdk.ParseMsgLogs(res.Log)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(logs))
+	assert.True(t, logs[0].Success)
+	assert.False(t, logs[1].Success)
+
+	// all msgs are applied in order
+	res = app.Deliver(testMultiTx{[]sdk.Msg{testSetMsg{key: "a"}, testSetMsg{key: "b"}}})
+	assert.True(t, res.IsOK())
+	assert.Equal(t, []byte("a"), store.Get([]byte("a")))
+	assert.Equal(t, []byte("b"), store.Get([]byte("b")))
+	assert.Equal(t, []byte("ab"), res.Data)
+	logs, err = sdk.ParseMsgLogs(res.Log)
+	assert.Nil(t, err)
+	assert.Equal(t, sdk.MsgLogs{{0, true, "a", 0, 1}, {1, true, "b", 0, 1}}, logs)
+
+	// a tx without msgs is rejected
+	res = app.Deliver(testMultiTx{})
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInternal), res.Code)
+}
+
+// A mock msg consuming gas.
+type testGasMsg struct {
+	gas int64
+}
+
+const gasMsgType = "testGasMsg"
+
+func (msg testGasMsg) Type() string              { return gasMsgType }
+func (msg testGasMsg) GetSignBytes() []byte      { return nil }
+func (msg testGasMsg) ValidateBasic() sdk.Error  { return nil }
+func (msg testGasMsg) GetSigners() []sdk.Address { return nil }
+
+// Test that txs are limited by their gas and by the gas of the block.
+func TestTxGasLimits(t *testing.T) {
+	app := newBaseApp(t.Name())
+
+	capKey := sdk.NewKVStoreKey("main")
+	app.MountStoresIAVL(capKey)
+	err := app.LoadLatestVersion(capKey) // needed to make stores non-nil
+	assert.Nil(t, err)
+
+	// every tx may use 100 gas
+	app.SetAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) {
+		newCtx = ctx.WithGasMeter(sdk.NewGasMeter(100))
+		return newCtx, sdk.Result{GasWanted: 100}, false
+	})
+	app.Router().AddRoute(gasMsgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		ctx.KVStore(capKey).Set([]byte("foo"), []byte("bar"))
+		ctx.GasMeter().ConsumeGas(msg.(testGasMsg).gas, "test")
+		return sdk.Result{}
+	})
+	app.SetMaxBlockGas(250)
+
+	app.BeginBlock(abci.RequestBeginBlock{})
+	store := app.deliverState.ms.GetKVStore(capKey)
+
+	// a tx running out of gas is reverted
+	res := app.Deliver(testMultiTx{[]sdk.Msg{testGasMsg{200}}})
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeOutOfGas), res.Code)
+	assert.Equal(t, int64(100), res.GasWanted)
+	assert.Equal(t, int64(100), res.GasUsed)
+	assert.Nil(t, store.Get([]byte("foo")))
+
+	// the gas used is reported
+	res = app.Deliver(testMultiTx{[]sdk.Msg{testGasMsg{10}}})
+	assert.True(t, res.IsOK(), res.Log)
+	assert.True(t, res.GasUsed > 10)
+	assert.Equal(t, []byte("bar"), store.Get([]byte("foo")))
+
+	// the block is full after 250 gas
+	res = app.Deliver(testMultiTx{[]sdk.Msg{testGasMsg{20}}})
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeBlockGasLimit), res.Code)
+	res = app.Deliver(testMultiTx{[]sdk.Msg{testGasMsg{0}}})
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeBlockGasLimit), res.Code)
+
+	// a new block has room again
+	app.EndBlock(abci.RequestEndBlock{})
+	app.Commit()
+	app.BeginBlock(abci.RequestBeginBlock{})
+	res = app.Deliver(testMultiTx{[]sdk.Msg{testGasMsg{10}}})
+	assert.True(t, res.IsOK(), res.Log)
+}
+
 //----------------------
 // TODO: clean this up
 
@@ -319,7 +506,7 @@ type testUpdatePowerTx struct {
 const msgType = "testUpdatePowerTx"
 
 func (tx testUpdatePowerTx) Type() string                      { return msgType }
-func (tx testUpdatePowerTx) GetMsg() sdk.Msg                   { return tx }
+func (tx testUpdatePowerTx) GetMsgs() []sdk.Msg                { return []sdk.Msg{tx} }
 func (tx testUpdatePowerTx) GetSignBytes() []byte              { return nil }
 func (tx testUpdatePowerTx) ValidateBasic() sdk.Error          { return nil }
 func (tx testUpdatePowerTx) GetSigners() []sdk.Address         { return nil }
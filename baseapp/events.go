@@ -0,0 +1,357 @@
+package baseapp
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/tendermint/tmlibs/log"
+
+	sdk "inschain-tendermint/types"
+)
+
+// default number of events buffered per subscriber
+const DefaultEventBufferSize = 1024
+
+// TxEvent is emitted for every message delivered in a committed block.
+// Events are only published once the block they belong to is committed,
+// so subscribers never observe state that could still be rolled back.
+// The Result is the one of the message if the transaction succeeded,
+// and the failed result of the transaction otherwise.
+type TxEvent struct {
+	Height   int64      `json:"height"`
+	TxIndex  int        `json:"tx_index"`
+	MsgIndex int        `json:"msg_index"`
+	MsgType  string     `json:"msg_type"` // concrete message type, eg. "MutualBondMsg"
+	Module   string     `json:"module"`   // message route, ie. msg.Type()
+	Msg      sdk.Msg    `json:"msg"`
+	Result   sdk.Result `json:"result"`
+}
+
+// Tag returns the value of the result tag with the given key
+func (ev TxEvent) Tag(key string) ([]byte, bool) {
+	for _, tag := range ev.Result.Tags {
+		if string(tag.Key) == key {
+			return tag.Value, true
+		}
+	}
+	return nil, false
+}
+
+// MsgTypeName returns the name of the concrete type of a message,
+// used to tell apart messages sharing the same route
+func MsgTypeName(msg sdk.Msg) string {
+	rt := reflect.TypeOf(msg)
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return rt.Name()
+}
+
+// EventFilter selects the events delivered to a subscriber.
+// Empty fields match everything, non-empty fields must all match.
+type EventFilter struct {
+	MsgTypes []string           `json:"msg_types"`
+	Modules  []string           `json:"modules"`
+	Codes    []sdk.ABCICodeType `json:"codes"`
+	Tags     map[string]string  `json:"tags"`
+}
+
+// Matches returns whether the event passes the filter
+func (f EventFilter) Matches(ev TxEvent) bool {
+	if len(f.MsgTypes) > 0 && !containsString(f.MsgTypes, ev.MsgType) {
+		return false
+	}
+	if len(f.Modules) > 0 && !containsString(f.Modules, ev.Module) {
+		return false
+	}
+	if len(f.Codes) > 0 {
+		found := false
+		for _, code := range f.Codes {
+			if code == ev.Result.Code {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for key, value := range f.Tags {
+		tag, ok := ev.Tag(key)
+		if !ok || !bytes.Equal(tag, []byte(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// EventHandler processes a committed event. A returned error or a panic
+// is recorded against the subscriber and does not affect other subscribers.
+type EventHandler func(ev TxEvent) error
+
+// SubscriberMetrics reports the delivery state of a subscriber
+type SubscriberMetrics struct {
+	Delivered int64 `json:"delivered"` // events handled without error
+	Failed    int64 `json:"failed"`    // events for which the handler returned an error
+	Panics    int64 `json:"panics"`    // events for which the handler panicked
+	Dropped   int64 `json:"dropped"`   // events dropped because the buffer was full
+	Pending   int   `json:"pending"`   // events waiting in the buffer
+	Capacity  int   `json:"capacity"`  // size of the buffer
+	Blocking  bool  `json:"blocking"`  // whether publishing waits for room in the buffer
+}
+
+type subscription struct {
+	name    string
+	filter  EventFilter
+	handler EventHandler
+	queue   chan TxEvent
+	quit    chan struct{} // closed on Unsubscribe, the queue itself is never closed
+	done    chan struct{}
+	logger  log.Logger
+
+	// a blocking subscriber never drops events, publishing waits for
+	// room in its buffer instead
+	blocking bool
+
+	delivered int64
+	failed    int64
+	panics    int64
+	dropped   int64
+}
+
+func (sub *subscription) run() {
+	defer close(sub.done)
+	for {
+		select {
+		case ev := <-sub.queue:
+			sub.handle(ev)
+		case <-sub.quit:
+			// handle what was buffered before unsubscribing
+			for {
+				select {
+				case ev := <-sub.queue:
+					sub.handle(ev)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver queues an event, dropping it when a non-blocking subscriber's
+// buffer is full. Returns without queueing once the subscriber is removed.
+func (sub *subscription) deliver(ev TxEvent) {
+	if sub.blocking {
+		select {
+		case sub.queue <- ev:
+		case <-sub.quit:
+		}
+		return
+	}
+	select {
+	case sub.queue <- ev:
+	case <-sub.quit:
+	default:
+		atomic.AddInt64(&sub.dropped, 1)
+	}
+}
+
+func (sub *subscription) handle(ev TxEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			atomic.AddInt64(&sub.panics, 1)
+			sub.logger.Error("Event subscriber panicked",
+				"subscriber", sub.name, "height", ev.Height, "tx", ev.TxIndex,
+				"err", fmt.Sprintf("%v", r), "stack", string(debug.Stack()))
+		}
+	}()
+	err := sub.handler(ev)
+	if err != nil {
+		atomic.AddInt64(&sub.failed, 1)
+		sub.logger.Error("Event subscriber failed",
+			"subscriber", sub.name, "height", ev.Height, "tx", ev.TxIndex, "err", err)
+		return
+	}
+	atomic.AddInt64(&sub.delivered, 1)
+}
+
+func (sub *subscription) metrics() SubscriberMetrics {
+	return SubscriberMetrics{
+		Delivered: atomic.LoadInt64(&sub.delivered),
+		Failed:    atomic.LoadInt64(&sub.failed),
+		Panics:    atomic.LoadInt64(&sub.panics),
+		Dropped:   atomic.LoadInt64(&sub.dropped),
+		Pending:   len(sub.queue),
+		Capacity:  cap(sub.queue),
+		Blocking:  sub.blocking,
+	}
+}
+
+// EventBus collects the events of the block being delivered and publishes
+// them to its subscribers after Commit. Each subscriber has its own buffer
+// and goroutine, so a slow or failing subscriber never blocks consensus or
+// the other subscribers; events which do not fit in a full buffer are dropped
+// and counted. Subscribers which can't afford to lose events subscribe with
+// SubscribeBlocking instead, and hold Commit back while their buffer is full.
+type EventBus struct {
+	logger log.Logger
+
+	mtx  sync.RWMutex
+	subs map[string]*subscription
+
+	// events of the block currently being delivered.
+	// only accessed from the ABCI connection.
+	pending []TxEvent
+}
+
+// NewEventBus returns an EventBus without subscribers
+func NewEventBus(logger log.Logger) *EventBus {
+	return &EventBus{
+		logger: logger,
+		subs:   make(map[string]*subscription),
+	}
+}
+
+// Subscribe registers a handler under a unique name. Events which do not fit
+// in its full buffer are dropped.
+// A bufferSize <= 0 uses DefaultEventBufferSize.
+func (bus *EventBus) Subscribe(name string, filter EventFilter, bufferSize int, handler EventHandler) error {
+	return bus.subscribe(name, filter, bufferSize, false, handler)
+}
+
+// SubscribeBlocking registers a handler under a unique name, which receives
+// every event: when its buffer is full, publishing waits for the handler to
+// catch up, delaying the Commit of the next block.
+// A bufferSize <= 0 uses DefaultEventBufferSize.
+func (bus *EventBus) SubscribeBlocking(name string, filter EventFilter, bufferSize int, handler EventHandler) error {
+	return bus.subscribe(name, filter, bufferSize, true, handler)
+}
+
+func (bus *EventBus) subscribe(name string, filter EventFilter, bufferSize int, blocking bool, handler EventHandler) error {
+	if handler == nil {
+		return fmt.Errorf("nil handler for subscriber %s", name)
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultEventBufferSize
+	}
+
+	bus.mtx.Lock()
+	defer bus.mtx.Unlock()
+	if _, ok := bus.subs[name]; ok {
+		return fmt.Errorf("subscriber %s already registered", name)
+	}
+	sub := &subscription{
+		name:    name,
+		filter:  filter,
+		handler: handler,
+		queue:   make(chan TxEvent, bufferSize),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+		logger:  bus.logger,
+
+		blocking: blocking,
+	}
+	bus.subs[name] = sub
+	go sub.run()
+	return nil
+}
+
+// Unsubscribe removes a subscriber and waits for its buffered events to be handled
+func (bus *EventBus) Unsubscribe(name string) error {
+	bus.mtx.Lock()
+	sub, ok := bus.subs[name]
+	if !ok {
+		bus.mtx.Unlock()
+		return fmt.Errorf("subscriber %s not registered", name)
+	}
+	delete(bus.subs, name)
+	close(sub.quit)
+	bus.mtx.Unlock()
+
+	<-sub.done
+	return nil
+}
+
+// Stop removes all subscribers, waiting for their buffered events to be handled
+func (bus *EventBus) Stop() {
+	bus.mtx.RLock()
+	names := make([]string, 0, len(bus.subs))
+	for name := range bus.subs {
+		names = append(names, name)
+	}
+	bus.mtx.RUnlock()
+
+	for _, name := range names {
+		bus.Unsubscribe(name) // nolint: errcheck
+	}
+}
+
+// Metrics returns the metrics of a subscriber
+func (bus *EventBus) Metrics(name string) (SubscriberMetrics, bool) {
+	bus.mtx.RLock()
+	defer bus.mtx.RUnlock()
+	sub, ok := bus.subs[name]
+	if !ok {
+		return SubscriberMetrics{}, false
+	}
+	return sub.metrics(), true
+}
+
+// AllMetrics returns the metrics of every subscriber keyed by name
+func (bus *EventBus) AllMetrics() map[string]SubscriberMetrics {
+	bus.mtx.RLock()
+	defer bus.mtx.RUnlock()
+	metrics := make(map[string]SubscriberMetrics, len(bus.subs))
+	for name, sub := range bus.subs {
+		metrics[name] = sub.metrics()
+	}
+	return metrics
+}
+
+// record an event of the block being delivered
+func (bus *EventBus) record(ev TxEvent) {
+	bus.pending = append(bus.pending, ev)
+}
+
+// discard the events of an uncommitted block
+func (bus *EventBus) reset() {
+	bus.pending = nil
+}
+
+// publish the events of the committed block to the subscribers
+func (bus *EventBus) publish() {
+	events := bus.pending
+	bus.pending = nil
+
+	// deliver outside the lock, a blocking subscriber may hold publishing
+	// back while Unsubscribe or Metrics are called
+	bus.mtx.RLock()
+	subs := make([]*subscription, 0, len(bus.subs))
+	for _, sub := range bus.subs {
+		subs = append(subs, sub)
+	}
+	bus.mtx.RUnlock()
+
+	for _, ev := range events {
+		for _, sub := range subs {
+			if !sub.filter.Matches(ev) {
+				continue
+			}
+			sub.deliver(ev)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package baseapp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+	cmn "github.com/tendermint/tmlibs/common"
+
+	sdk "inschain-tendermint/types"
+)
+
+func TestEventFilter(t *testing.T) {
+	ev := TxEvent{
+		MsgType: "testUpdatePowerTx",
+		Module:  msgType,
+		Result: sdk.Result{
+			Tags: []cmn.KVPair{{Key: []byte("policy"), Value: []byte("abcd")}},
+		},
+	}
+
+	cases := []struct {
+		filter EventFilter
+		match  bool
+	}{
+		{EventFilter{}, true},
+		{EventFilter{MsgTypes: []string{"testUpdatePowerTx"}}, true},
+		{EventFilter{MsgTypes: []string{"other"}}, false},
+		{EventFilter{Modules: []string{msgType, "bank"}}, true},
+		{EventFilter{Modules: []string{"bank"}}, false},
+		{EventFilter{Codes: []sdk.ABCICodeType{sdk.ABCICodeOK}}, true},
+		{EventFilter{Codes: []sdk.ABCICodeType{sdk.ABCICodeType(7)}}, false},
+		{EventFilter{Tags: map[string]string{"policy": "abcd"}}, true},
+		{EventFilter{Tags: map[string]string{"policy": "ef"}}, false},
+		{EventFilter{Tags: map[string]string{"member": "abcd"}}, false},
+	}
+
+	for i, tc := range cases {
+		assert.Equal(t, tc.match, tc.filter.Matches(ev), "case %d", i)
+	}
+}
+
+// Test that events are only published once the block is committed,
+// and that a failing subscriber does not affect the others.
+func TestEventBusPublishOnCommit(t *testing.T) {
+	app := newBaseApp(t.Name())
+	capKey := sdk.NewKVStoreKey("main")
+	app.MountStoresIAVL(capKey)
+	err := app.LoadLatestVersion(capKey)
+	require.Nil(t, err)
+
+	app.SetAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+	app.Router().AddRoute(msgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		return sdk.Result{}
+	})
+
+	received := make(chan TxEvent, 10)
+	err = app.EventBus().Subscribe("good", EventFilter{Modules: []string{msgType}}, 0, func(ev TxEvent) error {
+		received <- ev
+		return nil
+	})
+	require.Nil(t, err)
+	err = app.EventBus().Subscribe("bad", EventFilter{}, 0, func(ev TxEvent) error {
+		return errors.New("failed")
+	})
+	require.Nil(t, err)
+	err = app.EventBus().Subscribe("panic", EventFilter{}, 0, func(ev TxEvent) error {
+		panic("boom")
+	})
+	require.Nil(t, err)
+
+	// duplicate names are rejected
+	err = app.EventBus().Subscribe("good", EventFilter{}, 0, func(ev TxEvent) error { return nil })
+	assert.NotNil(t, err)
+
+	header := abci.Header{Height: 1}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	app.Deliver(testUpdatePowerTx{})
+	app.Deliver(testUpdatePowerTx{})
+	app.EndBlock(abci.RequestEndBlock{})
+
+	// nothing is published before commit
+	select {
+	case <-received:
+		t.Fatal("event published before commit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	app.Commit()
+	for i := 0; i < 2; i++ {
+		select {
+		case ev := <-received:
+			assert.Equal(t, int64(1), ev.Height)
+			assert.Equal(t, i, ev.TxIndex)
+			assert.Equal(t, "testUpdatePowerTx", ev.MsgType)
+		case <-time.After(time.Second):
+			t.Fatal("event not published after commit")
+		}
+	}
+
+	app.EventBus().Stop()
+	assert.Equal(t, 0, len(app.EventBus().AllMetrics()))
+}
+
+func TestEventBusMetrics(t *testing.T) {
+	bus := NewEventBus(defaultLogger())
+	block := make(chan struct{})
+	err := bus.Subscribe("slow", EventFilter{}, 1, func(ev TxEvent) error {
+		<-block
+		return nil
+	})
+	require.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		bus.record(TxEvent{TxIndex: i})
+	}
+	bus.publish()
+
+	// one event is being handled, one is buffered, one is dropped
+	metrics, ok := bus.Metrics("slow")
+	require.True(t, ok)
+	assert.Equal(t, 1, metrics.Capacity)
+	assert.True(t, metrics.Dropped >= 1)
+
+	close(block)
+	bus.Stop()
+	_, ok = bus.Metrics("slow")
+	assert.False(t, ok)
+}
+
+func TestEventBusBlocking(t *testing.T) {
+	bus := NewEventBus(defaultLogger())
+	block := make(chan struct{})
+	var handled []int
+	err := bus.SubscribeBlocking("slow", EventFilter{}, 1, func(ev TxEvent) error {
+		<-block
+		handled = append(handled, ev.TxIndex)
+		return nil
+	})
+	require.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		bus.record(TxEvent{TxIndex: i})
+	}
+	published := make(chan struct{})
+	go func() {
+		bus.publish()
+		close(published)
+	}()
+
+	// publishing waits for the handler instead of dropping events
+	select {
+	case <-published:
+		t.Fatal("events published past a full buffer")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(block)
+	<-published
+
+	bus.Stop()
+	assert.Equal(t, []int{0, 1, 2}, handled)
+}
+
+func TestEventBusUnsubscribeBlocked(t *testing.T) {
+	bus := NewEventBus(defaultLogger())
+	block := make(chan struct{})
+	err := bus.SubscribeBlocking("slow", EventFilter{}, 1, func(ev TxEvent) error {
+		<-block
+		return nil
+	})
+	require.Nil(t, err)
+
+	for i := 0; i < 5; i++ {
+		bus.record(TxEvent{TxIndex: i})
+	}
+	published := make(chan struct{})
+	go func() {
+		bus.publish()
+		close(published)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// the bus stays usable while publishing waits for room in the buffer
+	metrics, ok := bus.Metrics("slow")
+	require.True(t, ok)
+	assert.Equal(t, 1, metrics.Pending)
+
+	unsubscribed := make(chan struct{})
+	go func() {
+		assert.Nil(t, bus.Unsubscribe("slow"))
+		close(unsubscribed)
+	}()
+
+	// removing the subscriber releases the publisher without sending on a closed queue
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("publish still blocked on a removed subscriber")
+	}
+	close(block)
+	select {
+	case <-unsubscribed:
+	case <-time.After(time.Second):
+		t.Fatal("unsubscribe did not return")
+	}
+	_, ok = bus.Metrics("slow")
+	assert.False(t, ok)
+}
@@ -0,0 +1,51 @@
+package baseapp
+
+import (
+	sdk "inschain-tendermint/types"
+)
+
+// QueryRouter provides queriers for each module, queried at /custom/<module>/...
+type QueryRouter interface {
+	AddRoute(r string, q sdk.Querier) (rtr QueryRouter)
+	Route(path string) (q sdk.Querier)
+}
+
+type queryRoute struct {
+	r string
+	q sdk.Querier
+}
+
+type queryRouter struct {
+	routes []queryRoute
+}
+
+// nolint
+// NewQueryRouter - create new query router
+func NewQueryRouter() *queryRouter {
+	return &queryRouter{
+		routes: make([]queryRoute, 0),
+	}
+}
+
+// AddRoute - register the querier of a module
+func (rtr *queryRouter) AddRoute(r string, q sdk.Querier) QueryRouter {
+	if !isAlpha(r) {
+		panic("route expressions can only contain alphanumeric characters")
+	}
+	if rtr.Route(r) != nil {
+		panic("route " + r + " has already been registered")
+	}
+	rtr.routes = append(rtr.routes, queryRoute{r, q})
+
+	return rtr
+}
+
+// Route - get the querier of a module
+func (rtr *queryRouter) Route(path string) (q sdk.Querier) {
+	for _, route := range rtr.routes {
+		if route.r == path {
+			return route.q
+		}
+	}
+	return nil
+}
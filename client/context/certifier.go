@@ -0,0 +1,76 @@
+package context
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/lite"
+	liteclient "github.com/tendermint/tendermint/lite/client"
+	"github.com/tendermint/tendermint/lite/files"
+	"github.com/tendermint/tmlibs/cli"
+)
+
+// LiteDirName is the directory under the client home where the light client
+// stores its trusted headers
+const LiteDirName = "lite"
+
+// the trusted headers of the light client, cached in memory
+func trustedProvider() lite.Provider {
+	dir := filepath.Join(viper.GetString(cli.HomeFlag), LiteDirName)
+	return lite.NewCacheProvider(lite.NewMemStoreProvider(), files.NewProvider(dir))
+}
+
+// InitLiteClient stores the first trusted header of the light client,
+// along with the validator set which signed it
+func InitLiteClient(chainID string, fc lite.FullCommit) error {
+	// the commit must be signed by its validator set
+	cert := lite.NewStaticCertifier(chainID, fc.Validators)
+	err := cert.Certify(fc.Commit)
+	if err != nil {
+		return errors.Wrap(err, "Invalid trusted commit")
+	}
+	return trustedProvider().StoreCommit(fc)
+}
+
+// NewLiteCertifier loads the light client of the chain from the client home.
+// Headers are certified from the latest trusted header: the validator set
+// changes are verified through the node by bisection, and every newly
+// certified header is trusted on disk.
+func NewLiteCertifier(chainID, nodeURI string) (lite.Certifier, error) {
+	return newLiteCertifier(chainID, trustedProvider(), liteclient.NewHTTPProvider(nodeURI))
+}
+
+func newLiteCertifier(chainID string, trusted, source lite.Provider) (lite.Certifier, error) {
+	fc, err := trusted.LatestCommit()
+	if err != nil {
+		return nil, errors.Wrap(err, "Light client not initialized, run init")
+	}
+	cert, err := lite.NewInquiringCertifier(chainID, fc, trusted, source)
+	if err != nil {
+		return nil, err
+	}
+	return &lockedCertifier{cert: cert}, nil
+}
+
+// lockedCertifier serializes the certifications of a certifier.
+// An InquiringCertifier updates its validator set and its trusted headers
+// while certifying, and the REST handlers certify concurrently.
+type lockedCertifier struct {
+	mtx  sync.Mutex
+	cert lite.Certifier
+}
+
+// Implements lite.Certifier
+func (c *lockedCertifier) Certify(commit lite.Commit) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.cert.Certify(commit)
+}
+
+// Implements lite.Certifier
+func (c *lockedCertifier) ChainID() string {
+	return c.cert.ChainID()
+}
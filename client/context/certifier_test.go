@@ -0,0 +1,105 @@
+package context
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/lite"
+)
+
+// commits of a chain whose validator set grows by one at every height,
+// too fast to certify the last one from the first one directly
+func growingCommits(chainID string, count int) []lite.FullCommit {
+	keys := lite.GenValKeys(5)
+	commits := make([]lite.FullCommit, count)
+	for i := range commits {
+		keys = keys.Extend(1)
+		vals := keys.ToValidators(10, 0)
+		h := int64(10 * (i + 1))
+		commits[i] = keys.GenFullCommit(chainID, h, nil, vals, []byte(fmt.Sprintf("h=%d", h)),
+			[]byte("params"), []byte("results"), 0, len(keys))
+	}
+	return commits
+}
+
+func TestLiteCertifierBisection(t *testing.T) {
+	chainID := "lite-test"
+	commits := growingCommits(chainID, 20)
+	trusted, source := lite.NewMemStoreProvider(), lite.NewMemStoreProvider()
+
+	// the light client must be initialized
+	_, err := newLiteCertifier(chainID, trusted, source)
+	assert.NotNil(t, err)
+
+	require.Nil(t, trusted.StoreCommit(commits[0]))
+	cert, err := newLiteCertifier(chainID, trusted, source)
+	require.Nil(t, err)
+	last := commits[len(commits)-1]
+
+	// the validator changes can't be verified without the intermediate headers
+	assert.NotNil(t, cert.Certify(last.Commit))
+
+	// they are verified by bisection through the source, and trusted
+	for _, fc := range commits {
+		require.Nil(t, source.StoreCommit(fc))
+	}
+	assert.Nil(t, cert.Certify(last.Commit))
+	fc, err := trusted.LatestCommit()
+	require.Nil(t, err)
+	assert.Equal(t, last.Height(), fc.Height())
+
+	// a header of another chain is rejected
+	other := growingCommits("other-chain", 1)[0]
+	assert.NotNil(t, cert.Certify(other.Commit))
+}
+
+func TestLiteCertifierConcurrent(t *testing.T) {
+	chainID := "lite-test"
+	commits := growingCommits(chainID, 20)
+	trusted, source := lite.NewMemStoreProvider(), lite.NewMemStoreProvider()
+	require.Nil(t, trusted.StoreCommit(commits[0]))
+	for _, fc := range commits {
+		require.Nil(t, source.StoreCommit(fc))
+	}
+	cert, err := newLiteCertifier(chainID, trusted, source)
+	require.Nil(t, err)
+
+	// the handlers of the REST server certify concurrently
+	var wg sync.WaitGroup
+	for i := len(commits) - 1; i > 0; i-- {
+		wg.Add(1)
+		go func(fc lite.FullCommit) {
+			defer wg.Done()
+			assert.Nil(t, cert.Certify(fc.Commit))
+		}(commits[i])
+	}
+	wg.Wait()
+}
+
+func TestCheckVerifiable(t *testing.T) {
+	cert := &lockedCertifier{cert: lite.NewStaticCertifier("lite-test", nil)}
+	cases := []struct {
+		ctx     CoreContext
+		endPath string
+		ok      bool
+	}{
+		{CoreContext{TrustNode: true}, "subspace", true},
+		{CoreContext{TrustNode: true}, "custom", true},
+		{CoreContext{Certifier: cert}, "key", true},
+		{CoreContext{}, "key", false}, // no light client
+		{CoreContext{Certifier: cert}, "subspace", false},
+		{CoreContext{Certifier: cert}, "custom", false},
+	}
+	for i, tc := range cases {
+		err := tc.ctx.checkVerifiable(tc.endPath)
+		assert.Equal(t, tc.ok, err == nil, "%d: %v", i, err)
+	}
+
+	// the custom queries are refused before reaching the node
+	_, err := CoreContext{Certifier: cert}.QueryCustom("stake", "candidates", nil)
+	assert.NotNil(t, err)
+}
@@ -1,19 +1,24 @@
 package context
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/pkg/errors"
 
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/wire"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tendermint/lite/proxy"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	cmn "github.com/tendermint/tmlibs/common"
 
 	//"github.com/cosmos/cosmos-sdk/client"
 	"inschain-tendermint/client"
-	"github.com/cosmos/cosmos-sdk/client/keys"
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	"inschain-tendermint/client/keys"
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/x/auth"
 )
 
 // Broadcast the transaction bytes to Tendermint
@@ -73,23 +78,63 @@ func (ctx CoreContext) Query(key cmn.HexBytes, storeName string) (res []byte, er
 	return ctx.query(key, storeName, "key")
 }
 
-// Query from Tendermint with the provided storename and subspace
-func (ctx CoreContext) QuerySubspace(cdc *wire.Codec, subspace []byte, storeName string) (res []cmn.KVPair, err error) {
-	resRaw, err := ctx.query(subspace, storeName, "subspace")
+// Query a page of the subspace from Tendermint with the provided storename,
+// according to the subspace options of the context
+func (ctx CoreContext) QuerySubspace(cdc *wire.Codec, subspace []byte, storeName string) (res sdk.SubspaceResult, err error) {
+	query := sdk.SubspaceQuery{
+		Subspace:  subspace,
+		Start:     ctx.Subspace.Start,
+		Limit:     ctx.Subspace.Limit,
+		Reverse:   ctx.Subspace.Reverse,
+		KeysOnly:  ctx.Subspace.KeysOnly,
+		CountOnly: ctx.Subspace.CountOnly,
+	}
+	bz, err := cdc.MarshalBinary(query)
+	if err != nil {
+		return res, err
+	}
+	resRaw, err := ctx.query(bz, storeName, "subspace")
 	if err != nil {
 		return res, err
 	}
-	cdc.MustUnmarshalBinary(resRaw, &res)
+	err = cdc.UnmarshalBinary(resRaw, &res)
 	return
 }
 
-// Query from Tendermint with the provided storename and path
+// Query the custom querier of a module, at /custom/<module>/<route>,
+// with the JSON encoded query parameters
+func (ctx CoreContext) QueryCustom(module, route string, data []byte) (res []byte, err error) {
+	err = ctx.checkVerifiable("custom")
+	if err != nil {
+		return res, err
+	}
+	resp, err := ctx.queryABCI(fmt.Sprintf("/custom/%s/%s", module, route), data)
+	return resp.Value, err
+}
+
+// Query from Tendermint with the provided storename and path,
+// the proof of the response is verified unless the node is trusted
 func (ctx CoreContext) query(key cmn.HexBytes, storeName, endPath string) (res []byte, err error) {
+	err = ctx.checkVerifiable(endPath)
+	if err != nil {
+		return res, err
+	}
+	resp, err := ctx.queryABCI(fmt.Sprintf("/%s/%s", storeName, endPath), key)
+	if err != nil || ctx.TrustNode {
+		return resp.Value, err
+	}
+	err = ctx.verifyProof(storeName, key, resp)
+	if err != nil {
+		return res, err
+	}
+	return resp.Value, nil
+}
 
-	path := fmt.Sprintf("/%s/%s", storeName, endPath)
+// Query from Tendermint with the provided path and data
+func (ctx CoreContext) queryABCI(path string, key cmn.HexBytes) (resp abci.ResponseQuery, err error) {
 	node, err := ctx.GetNode()
 	if err != nil {
-		return res, err
+		return resp, err
 	}
 
 	opts := rpcclient.ABCIQueryOptions{
@@ -98,13 +143,48 @@ func (ctx CoreContext) query(key cmn.HexBytes, storeName, endPath string) (res [
 	}
 	result, err := node.ABCIQueryWithOptions(path, key, opts)
 	if err != nil {
-		return res, err
+		return resp, err
 	}
-	resp := result.Response
+	resp = result.Response
 	if resp.Code != uint32(0) {
-		return res, errors.Errorf("Query failed: (%d) %s", resp.Code, resp.Log)
+		return resp, errors.Errorf("Query failed: (%d) %s", resp.Code, resp.Log)
 	}
-	return resp.Value, nil
+	return resp, nil
+}
+
+// Without trust-node, only the queries of a single key are verified, with
+// the light client. The custom queries and the pages of /subspace have no
+// proof, so the commands and the REST routes listing or paging through a
+// store, and those of the module queriers, require trust-node.
+func (ctx CoreContext) checkVerifiable(endPath string) error {
+	if ctx.TrustNode {
+		return nil
+	}
+	if endPath != "key" {
+		return errors.Errorf("Queries of /%s cannot be verified, query with trust-node", endPath)
+	}
+	if ctx.Certifier == nil {
+		return errors.Errorf("No light client to verify the proof, run init or query with trust-node")
+	}
+	return nil
+}
+
+// Verify the proof of a key query against the app hash of a certified header.
+// The state of a height is committed in the header of the next block.
+func (ctx CoreContext) verifyProof(storeName string, key []byte, resp abci.ResponseQuery) error {
+	node, err := ctx.GetNode()
+	if err != nil {
+		return err
+	}
+	commit, err := proxy.GetCertifiedCommit(resp.Height+1, node, ctx.Certifier)
+	if err != nil {
+		return err
+	}
+	err = store.VerifyMultiStoreProof(resp.Proof, storeName, key, resp.Value, commit.Header.AppHash)
+	if err != nil {
+		return errors.Wrap(err, "Failed to verify the query proof")
+	}
+	return nil
 }
 
 // Get the from address from the name flag
@@ -128,47 +208,220 @@ func (ctx CoreContext) GetFromAddress() (from sdk.Address, err error) {
 	return info.PubKey.Address(), nil
 }
 
-// sign and build the transaction from the msg
-func (ctx CoreContext) SignAndBuild(name, passphrase string, msg sdk.Msg, cdc *wire.Codec) ([]byte, error) {
-
-	// build the Sign Messsage from the Standard Message
+// build the msg to sign for the transaction of the msgs, with the sequence
+// of the context for a single signer, or else the next sequence of every signer.
+// The fee is paid by the fee payer of the context if any.
+func (ctx CoreContext) BuildSignMsg(msgs []sdk.Msg) (signMsg sdk.StdSignMsg, err error) {
 	chainID := ctx.ChainID
 	if chainID == "" {
-		return nil, errors.Errorf("Chain ID required but not specified")
+		return signMsg, errors.Errorf("Chain ID required but not specified")
 	}
-	sequence := ctx.Sequence
-	signMsg := sdk.StdSignMsg{
-		ChainID:   chainID,
-		Sequences: []int64{sequence},
-		Msg:       msg,
+	if len(msgs) == 0 {
+		return signMsg, errors.Errorf("No msg to sign")
 	}
 
+	fee, err := sdk.ParseCoins(ctx.Fee)
+	if err != nil {
+		return signMsg, err
+	}
+	var feePayer sdk.Address
+	if ctx.FeePayer != "" {
+		feePayer, err = sdk.GetAddress(ctx.FeePayer)
+		if err != nil {
+			return signMsg, err
+		}
+	}
+
+	signers := sdk.MsgsSigners(msgs)
+	sequences := make([]int64, len(signers))
+	if len(signers) == 1 {
+		sequences[0] = ctx.Sequence
+	} else {
+		for i, signer := range signers {
+			sequences[i], err = ctx.NextSequence(signer)
+			if err != nil {
+				return signMsg, err
+			}
+		}
+	}
+	return sdk.StdSignMsg{
+		ChainID:   chainID,
+		Sequences: sequences,
+		Fee:       sdk.NewStdFee(ctx.Gas, fee...),
+		FeePayer:  feePayer,
+		Msgs:      msgs,
+	}, nil
+}
+
+// sign the msg with the key, in the signature slot of the signer of the key.
+// sigs holds a slot for every signer of the msgs, in order.
+func SignStdSignMsg(name, passphrase string, signMsg sdk.StdSignMsg, sigs []sdk.StdSignature) ([]sdk.StdSignature, error) {
 	keybase, err := keys.GetKeyBase()
 	if err != nil {
 		return nil, err
 	}
+	info, err := keybase.Get(name)
+	if err != nil {
+		return nil, errors.Errorf("No key for: %s", name)
+	}
+	slot, err := signerSlot(signMsg, sigs, info.PubKey.Address())
+	if err != nil {
+		return nil, err
+	}
 
-	// sign and build
-	bz := signMsg.Bytes()
-
-	sig, pubkey, err := keybase.Sign(name, passphrase, bz)
+	sig, pubkey, err := keybase.Sign(name, passphrase, signMsg.Bytes())
 	if err != nil {
 		return nil, err
 	}
-	sigs := []sdk.StdSignature{{
+	sigs[slot] = sdk.StdSignature{
 		PubKey:    pubkey,
 		Signature: sig,
-		Sequence:  sequence,
-	}}
+		Sequence:  signMsg.Sequences[slot],
+	}
+	return sigs, nil
+}
+
+// sign the msg with the key as one of the keys of the multisig pubkey,
+// the signature is added to the multisignature in the slot of the multisig signer
+func SignMultisigStdSignMsg(name, passphrase string, multisig auth.MultisigThresholdPubKey, signMsg sdk.StdSignMsg, sigs []sdk.StdSignature) ([]sdk.StdSignature, error) {
+	keybase, err := keys.GetKeyBase()
+	if err != nil {
+		return nil, err
+	}
+	info, err := keybase.Get(name)
+	if err != nil {
+		return nil, errors.Errorf("No key for: %s", name)
+	}
+	index := -1
+	for i, pubkey := range multisig.PubKeys {
+		if pubkey.Equals(info.PubKey) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, errors.Errorf("Key %s is not a key of the multisig", name)
+	}
+	slot, err := signerSlot(signMsg, sigs, multisig.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	sig, _, err := keybase.Sign(name, passphrase, signMsg.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	multisignature, ok := sigs[slot].Signature.(auth.Multisignature)
+	if !ok {
+		multisignature = auth.NewMultisignature(len(multisig.PubKeys))
+	}
+	sigs[slot] = sdk.StdSignature{
+		PubKey:    multisig,
+		Signature: multisignature.AddSignature(index, sig),
+		Sequence:  signMsg.Sequences[slot],
+	}
+	return sigs, nil
+}
+
+// the signature slot of the signer address
+func signerSlot(signMsg sdk.StdSignMsg, sigs []sdk.StdSignature, addr sdk.Address) (int, error) {
+	signers := sdk.MsgsSigners(signMsg.Msgs)
+	if len(sigs) != len(signers) || len(signMsg.Sequences) != len(signers) {
+		return 0, errors.Errorf("Expected %d signatures and sequences", len(signers))
+	}
+	for i, signer := range signers {
+		if bytes.Equal(signer, addr) {
+			return i, nil
+		}
+	}
+	return 0, errors.Errorf("%s is not a signer of the tx", addr)
+}
+
+// sign and build the transaction from the msgs,
+// all the msgs must be signed by the key only
+func (ctx CoreContext) SignAndBuild(name, passphrase string, msgs []sdk.Msg, cdc *wire.Codec) ([]byte, error) {
+	if len(sdk.MsgsSigners(msgs)) != 1 {
+		return nil, errors.Errorf("The msgs must have a single signer")
+	}
+	signMsg, err := ctx.BuildSignMsg(msgs)
+	if err != nil {
+		return nil, err
+	}
+	sigs, err := SignStdSignMsg(name, passphrase, signMsg, make([]sdk.StdSignature, 1))
+	if err != nil {
+		return nil, err
+	}
 
 	// marshal bytes
-	tx := sdk.NewStdTx(signMsg.Msg, signMsg.Fee, sigs)
+	tx := sdk.NewStdTx(signMsg.Msgs, signMsg.Fee, sigs).WithFeePayer(signMsg.FeePayer)
 
 	return cdc.MarshalBinary(tx)
 }
 
-// sign and build the transaction from the msg
-func (ctx CoreContext) EnsureSignBuildBroadcast(name string, msg sdk.Msg, cdc *wire.Codec) (res *ctypes.ResultBroadcastTxCommit, err error) {
+// print the JSON of the msg to sign for the transaction of the msgs,
+// to sign it offline and broadcast it later
+func (ctx CoreContext) PrintUnsignedTx(msgs []sdk.Msg, cdc *wire.Codec) error {
+	// default to next sequence number if none provided, without any output
+	// as it would not be valid JSON
+	ctx, err := ensureSequence(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	signMsg, err := ctx.BuildSignMsg(msgs)
+	if err != nil {
+		return err
+	}
+	output, err := wire.MarshalJSONIndent(cdc, signMsg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(output))
+	return nil
+}
+
+// check the msg, then print it unsigned with generate-only, or sign it with
+// the key of the context and broadcast it
+func (ctx CoreContext) SendMsg(msg sdk.Msg, cdc *wire.Codec) error {
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+	if ctx.GenerateOnly {
+		return ctx.PrintUnsignedTx([]sdk.Msg{msg}, cdc)
+	}
+	res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+	return nil
+}
+
+// query the custom querier of a module with the params, decode the result
+// into res and print it indented
+func (ctx CoreContext) PrintQueryCustom(module, route string, params interface{}, res interface{}, cdc *wire.Codec) error {
+	data, err := cdc.MarshalJSON(params)
+	if err != nil {
+		return err
+	}
+	bz, err := ctx.QueryCustom(module, route, data)
+	if err != nil {
+		return err
+	}
+	err = cdc.UnmarshalJSON(bz, res)
+	if err != nil {
+		return err
+	}
+	output, err := wire.MarshalJSONIndent(cdc, res)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(output))
+	return nil
+}
+
+// sign and build the transaction from the msgs
+func (ctx CoreContext) EnsureSignBuildBroadcast(name string, msgs []sdk.Msg, cdc *wire.Codec) (res *ctypes.ResultBroadcastTxCommit, err error) {
 
 	// default to next sequence number if none provided
 	ctx, err = EnsureSequence(ctx)
@@ -181,7 +434,7 @@ func (ctx CoreContext) EnsureSignBuildBroadcast(name string, msg sdk.Msg, cdc *w
 		return nil, err
 	}
 
-	txBytes, err := ctx.SignAndBuild(name, passphrase, msg, cdc)
+	txBytes, err := ctx.SignAndBuild(name, passphrase, msgs, cdc)
 	if err != nil {
 		return nil, err
 	}
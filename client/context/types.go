@@ -1,9 +1,14 @@
 package context
 
 import (
+	"encoding/hex"
+	"net/url"
+	"strconv"
+
+	"github.com/tendermint/tendermint/lite"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // typical context created in sdk modules for transactions/queries
@@ -14,9 +19,54 @@ type CoreContext struct {
 	NodeURI         string
 	FromAddressName string
 	Sequence        int64
+	Fee             string // fee coins to pay along with the tx
+	FeePayer        string // hex address paying the fee instead of the first signer
+	Gas             int64
 	Client          rpcclient.Client
 	Decoder         sdk.AccountDecoder
 	AccountStore    string
+	Subspace        SubspaceOptions
+	Certifier       lite.Certifier // certifies the headers to verify the query proofs
+	GenerateOnly    bool           // print the unsigned tx instead of signing and broadcasting it
+}
+
+// SubspaceOptions - paging options of the subspace queries
+type SubspaceOptions struct {
+	Start     []byte
+	Limit     int
+	Reverse   bool
+	KeysOnly  bool
+	CountOnly bool
+}
+
+// SubspaceOptionsFromURL - read the subspace options from the
+// start, limit, reverse, keys_only and count_only URL query parameters
+func SubspaceOptionsFromURL(values url.Values) (opts SubspaceOptions, err error) {
+	if start := values.Get("start"); start != "" {
+		opts.Start, err = hex.DecodeString(start)
+		if err != nil {
+			return opts, err
+		}
+	}
+	if limit := values.Get("limit"); limit != "" {
+		opts.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return opts, err
+		}
+	}
+	for name, flag := range map[string]*bool{
+		"reverse":    &opts.Reverse,
+		"keys_only":  &opts.KeysOnly,
+		"count_only": &opts.CountOnly,
+	} {
+		if value := values.Get(name); value != "" {
+			*flag, err = strconv.ParseBool(value)
+			if err != nil {
+				return opts, err
+			}
+		}
+	}
+	return opts, nil
 }
 
 // WithChainID - return a copy of the context with an updated chainID
@@ -56,6 +106,42 @@ func (c CoreContext) WithSequence(sequence int64) CoreContext {
 	return c
 }
 
+// WithFee - return a copy of the context with an updated fee
+func (c CoreContext) WithFee(fee string) CoreContext {
+	c.Fee = fee
+	return c
+}
+
+// WithFeePayer - return a copy of the context with an updated fee payer
+func (c CoreContext) WithFeePayer(feePayer string) CoreContext {
+	c.FeePayer = feePayer
+	return c
+}
+
+// WithGas - return a copy of the context with an updated gas limit
+func (c CoreContext) WithGas(gas int64) CoreContext {
+	c.Gas = gas
+	return c
+}
+
+// WithSubspaceOptions - return a copy of the context with updated subspace query options
+func (c CoreContext) WithSubspaceOptions(opts SubspaceOptions) CoreContext {
+	c.Subspace = opts
+	return c
+}
+
+// WithCertifier - return a copy of the context with an updated header certifier
+func (c CoreContext) WithCertifier(certifier lite.Certifier) CoreContext {
+	c.Certifier = certifier
+	return c
+}
+
+// WithGenerateOnly - return a copy of the context with an updated GenerateOnly flag
+func (c CoreContext) WithGenerateOnly(generateOnly bool) CoreContext {
+	c.GenerateOnly = generateOnly
+	return c
+}
+
 // WithClient - return a copy of the context with an updated RPC client instance
 func (c CoreContext) WithClient(client rpcclient.Client) CoreContext {
 	c.Client = client
@@ -1,15 +1,18 @@
 package context
 
 import (
+	"encoding/hex"
 	"fmt"
+	"os"
 
 	"github.com/spf13/viper"
 
 	tcmd "github.com/tendermint/tendermint/cmd/tendermint/commands"
+	"github.com/tendermint/tendermint/lite"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	tmtypes "github.com/tendermint/tendermint/types"
 
-	"github.com/cosmos/cosmos-sdk/client"
+	"inschain-tendermint/client"
 )
 
 // NewCoreContextFromViper - return a new context with parameters from the command line
@@ -23,23 +26,57 @@ func NewCoreContextFromViper() CoreContext {
 	// if chain ID is not specified manually, read default chain ID
 	if chainID == "" {
 		def, err := defaultChainID()
-		if err != nil {
+		if err == nil {
 			chainID = def
 		}
 	}
+	gas := viper.GetInt64(client.FlagGas)
+	if gas <= 0 {
+		gas = client.DefaultGasLimit
+	}
+	// verify the query proofs with the light client of the chain, if initialized
+	trustNode := viper.GetBool(client.FlagTrustNode)
+	var certifier lite.Certifier
+	if !trustNode && nodeURI != "" {
+		var err error
+		certifier, err = NewLiteCertifier(chainID, nodeURI)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Queries can't be verified: %v\n", err)
+		}
+	}
 	return CoreContext{
 		ChainID:         chainID,
 		Height:          viper.GetInt64(client.FlagHeight),
-		TrustNode:       viper.GetBool(client.FlagTrustNode),
+		TrustNode:       trustNode,
 		FromAddressName: viper.GetString(client.FlagName),
 		NodeURI:         nodeURI,
 		Sequence:        viper.GetInt64(client.FlagSequence),
+		Fee:             viper.GetString(client.FlagFee),
+		FeePayer:        viper.GetString(client.FlagFeePayer),
+		Gas:             gas,
 		Client:          rpc,
 		Decoder:         nil,
 		AccountStore:    "main",
+		Certifier:       certifier,
+		GenerateOnly:    viper.GetBool(client.FlagGenerateOnly),
 	}
 }
 
+// SubspaceOptionsFromViper - return the subspace query options from the paging flags
+func SubspaceOptionsFromViper() (opts SubspaceOptions, err error) {
+	if start := viper.GetString(client.FlagPageStart); start != "" {
+		opts.Start, err = hex.DecodeString(start)
+		if err != nil {
+			return opts, err
+		}
+	}
+	opts.Limit = viper.GetInt(client.FlagLimit)
+	opts.Reverse = viper.GetBool(client.FlagReverse)
+	opts.KeysOnly = viper.GetBool(client.FlagKeysOnly)
+	opts.CountOnly = viper.GetBool(client.FlagCountOnly)
+	return opts, nil
+}
+
 // read chain ID from genesis file, if present
 func defaultChainID() (string, error) {
 	cfg, err := tcmd.ParseConfig()
@@ -55,6 +92,10 @@ func defaultChainID() (string, error) {
 
 // EnsureSequence - automatically set sequence number if none provided
 func EnsureSequence(ctx CoreContext) (CoreContext, error) {
+	return ensureSequence(ctx, true)
+}
+
+func ensureSequence(ctx CoreContext, verbose bool) (CoreContext, error) {
 	if viper.IsSet(client.FlagSequence) {
 		return ctx, nil
 	}
@@ -66,7 +107,9 @@ func EnsureSequence(ctx CoreContext) (CoreContext, error) {
 	if err != nil {
 		return ctx, err
 	}
-	fmt.Printf("Defaulting to next sequence number: %d\n", seq)
+	if verbose {
+		fmt.Printf("Defaulting to next sequence number: %d\n", seq)
+	}
 	ctx = ctx.WithSequence(seq)
 	return ctx, nil
 }
@@ -11,8 +11,21 @@ const (
 	FlagName      = "name"
 	FlagSequence  = "sequence"
 	FlagFee       = "fee"
+	FlagGas       = "gas"
+
+	FlagPageStart = "page-start"
+	FlagLimit     = "limit"
+	FlagReverse   = "reverse"
+	FlagKeysOnly  = "keys-only"
+	FlagCountOnly = "count-only"
+
+	FlagGenerateOnly = "generate-only"
+	FlagFeePayer     = "fee-payer"
 )
 
+// DefaultGasLimit is the gas limit of a tx when none is provided
+const DefaultGasLimit = 200000
+
 // LineBreak can be included in a command list to provide a blank line
 // to help with readability
 var LineBreak = &cobra.Command{Run: func(*cobra.Command, []string) {}}
@@ -20,8 +33,8 @@ var LineBreak = &cobra.Command{Run: func(*cobra.Command, []string) {}}
 // GetCommands adds common flags to query commands
 func GetCommands(cmds ...*cobra.Command) []*cobra.Command {
 	for _, c := range cmds {
-		// TODO: make this default false when we support proofs
-		c.Flags().Bool(FlagTrustNode, true, "Don't verify proofs for responses")
+		// verifying the proofs requires a light client initialized with init
+		c.Flags().Bool(FlagTrustNode, true, "Don't verify proofs for responses, only the queries of a key can be verified")
 		c.Flags().String(FlagChainID, "", "Chain ID of tendermint node")
 		c.Flags().String(FlagNode, "tcp://localhost:46657", "<host>:<port> to tendermint rpc interface for this chain")
 		c.Flags().Int64(FlagHeight, 0, "block height to query, omit to get most recent provable block")
@@ -29,14 +42,29 @@ func GetCommands(cmds ...*cobra.Command) []*cobra.Command {
 	return cmds
 }
 
+// PageCommands adds the paging flags to query commands over a subspace
+func PageCommands(cmds ...*cobra.Command) []*cobra.Command {
+	for _, c := range cmds {
+		c.Flags().String(FlagPageStart, "", "hex key to start the page from, the next key of the previous page")
+		c.Flags().Int(FlagLimit, 100, "maximum number of results of the page")
+		c.Flags().Bool(FlagReverse, false, "iterate in descending order")
+		c.Flags().Bool(FlagKeysOnly, false, "only return the keys")
+		c.Flags().Bool(FlagCountOnly, false, "only count the results of the page")
+	}
+	return cmds
+}
+
 // PostCommands adds common flags for commands to post tx
 func PostCommands(cmds ...*cobra.Command) []*cobra.Command {
 	for _, c := range cmds {
 		c.Flags().String(FlagName, "", "Name of private key with which to sign")
 		c.Flags().Int64(FlagSequence, 0, "Sequence number to sign the tx")
 		c.Flags().String(FlagFee, "", "Fee to pay along with transaction")
+		c.Flags().Int64(FlagGas, DefaultGasLimit, "Gas limit of the transaction")
 		c.Flags().String(FlagChainID, "", "Chain ID of tendermint node")
 		c.Flags().String(FlagNode, "tcp://localhost:46657", "<host>:<port> to tendermint rpc interface for this chain")
+		c.Flags().String(FlagFeePayer, "", "Address paying the fee out of the fee allowance it granted to the signer")
+		c.Flags().Bool(FlagGenerateOnly, false, "print the unsigned tx to sign offline, instead of signing and broadcasting it")
 	}
 	return cmds
 }
@@ -6,11 +6,11 @@ import (
 	"io/ioutil"
 	"net/http"
 
-	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"inschain-tendermint/client"
 
 	"github.com/tendermint/go-crypto/keys"
 	"github.com/tendermint/tmlibs/cli"
@@ -0,0 +1,161 @@
+package keys
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	crypto "github.com/tendermint/go-crypto"
+	"github.com/tendermint/tmlibs/cli"
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"inschain-tendermint/x/auth"
+)
+
+// MultisigDBName is the db under the keys directory where we store the multisig keys
+const MultisigDBName = "multisig"
+
+// multisigDB is used to make getMultisigDB a singleton
+var multisigDB dbm.DB
+
+// MultisigInfo is a multisig key, the threshold pubkey of several keys.
+// Its keys are signed with their own keybase, see tx sign --multisig.
+type MultisigInfo struct {
+	Name   string                       `json:"name"`
+	PubKey auth.MultisigThresholdPubKey `json:"pub_key"`
+}
+
+func getMultisigDB() (dbm.DB, error) {
+	if multisigDB == nil {
+		rootDir := viper.GetString(cli.HomeFlag)
+		db, err := dbm.NewGoLevelDB(MultisigDBName, filepath.Join(rootDir, "keys"))
+		if err != nil {
+			return nil, err
+		}
+		multisigDB = db
+	}
+	return multisigDB, nil
+}
+
+// used to set the multisig db manually in test
+func SetMultisigDB(db dbm.DB) {
+	multisigDB = db
+}
+
+// GetMultisig returns the multisig key of the name
+func GetMultisig(name string) (info MultisigInfo, err error) {
+	db, err := getMultisigDB()
+	if err != nil {
+		return info, err
+	}
+	bz := db.Get([]byte(name))
+	if bz == nil {
+		return info, errors.Errorf("No multisig key for: %s", name)
+	}
+	err = cdc.UnmarshalBinary(bz, &info)
+	return info, err
+}
+
+// SetMultisig stores the multisig key under its name
+func SetMultisig(info MultisigInfo) error {
+	db, err := getMultisigDB()
+	if err != nil {
+		return err
+	}
+	bz, err := cdc.MarshalBinary(info)
+	if err != nil {
+		return err
+	}
+	db.SetSync([]byte(info.Name), bz)
+	return nil
+}
+
+func addMultisigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-multisig <name> <threshold> <key>...",
+		Short: "Create a multisig key of several keys",
+		Long: `Add a threshold key to the multisig keys, valid when signed by
+at least threshold of the keys. A key is either the name of a local key,
+or the hex public key of another signer as printed by keys show.
+The order of the keys matters: it defines the address of the multisig key.`,
+		Args: cobra.MinimumNArgs(3),
+		RunE: runAddMultisigCmd,
+	}
+	return cmd
+}
+
+func runAddMultisigCmd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	threshold, err := strconv.Atoi(args[1])
+	if err != nil {
+		return err
+	}
+	kb, err := GetKeyBase()
+	if err != nil {
+		return err
+	}
+
+	pubkeys := make([]crypto.PubKey, len(args)-2)
+	for i, key := range args[2:] {
+		if info, err := kb.Get(key); err == nil {
+			pubkeys[i] = info.PubKey
+			continue
+		}
+		bz, err := hex.DecodeString(key)
+		if err != nil {
+			return errors.Errorf("No key for %s, and not a hex public key", key)
+		}
+		err = cdc.UnmarshalBinaryBare(bz, &pubkeys[i])
+		if err != nil {
+			return errors.Wrapf(err, "Invalid public key %s", key)
+		}
+	}
+	if threshold <= 0 || threshold > len(pubkeys) {
+		return errors.Errorf("Threshold must be between 1 and %d", len(pubkeys))
+	}
+
+	info := MultisigInfo{name, auth.NewMultisigThresholdPubKey(threshold, pubkeys)}
+	err = SetMultisig(info)
+	if err != nil {
+		return err
+	}
+	printMultisig(info)
+	return nil
+}
+
+var showMultisigCmd = &cobra.Command{
+	Use:   "show-multisig <name>",
+	Short: "Show the multisig key info for the given name",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := GetMultisig(args[0])
+		if err == nil {
+			printMultisig(info)
+		}
+		return err
+	},
+}
+
+func printMultisig(info MultisigInfo) {
+	switch viper.Get(cli.OutputFlag) {
+	case "text":
+		fmt.Printf("NAME:\tADDRESS:\t\t\t\t\tTHRESHOLD:\n")
+		fmt.Printf("%s\t%s\t%d of %d\n", info.Name, info.PubKey.Address(),
+			info.PubKey.Threshold, len(info.PubKey.PubKeys))
+		for _, pubkey := range info.PubKey.PubKeys {
+			fmt.Printf("\t%s\n", strings.ToUpper(hex.EncodeToString(pubkey.Bytes())))
+		}
+	case "json":
+		out, err := MarshalJSON(info)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(out))
+	}
+}
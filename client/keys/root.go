@@ -1,9 +1,9 @@
 package keys
 
 import (
-	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
+	"inschain-tendermint/client"
 )
 
 // Commands registers a sub-tree of commands to interact with
@@ -23,6 +23,9 @@ func Commands() *cobra.Command {
 		listKeysCmd,
 		showKeysCmd,
 		client.LineBreak,
+		addMultisigCommand(),
+		showMultisigCmd,
+		client.LineBreak,
 		deleteKeyCommand(),
 		updateKeyCommand(),
 	)
@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/gorilla/mux"
 	keys "github.com/tendermint/go-crypto/keys"
+	"inschain-tendermint/client"
 
 	"github.com/spf13/cobra"
 )
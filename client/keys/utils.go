@@ -13,7 +13,7 @@ import (
 	"github.com/tendermint/tmlibs/cli"
 	dbm "github.com/tendermint/tmlibs/db"
 
-	"github.com/cosmos/cosmos-sdk/client"
+	"inschain-tendermint/client"
 )
 
 // KeyDBName is the directory under root where we store the keys
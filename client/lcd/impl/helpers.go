@@ -2,7 +2,7 @@ package impl
 
 import (
 	//cfg "github.com/tendermint/tendermint/config"
-	"github.com/cosmos/cosmos-sdk/client/lcd"
+	"inschain-tendermint/client/lcd"
 	"os"
 	"path/filepath"
 	"strings"
@@ -3,22 +3,22 @@ package impl
 import (
 	"net/http"
 	"github.com/tendermint/tmlibs/log"
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/wire"
 	//"os"
 	"fmt"
 	//"net"
 	//"log"
-	keys "github.com/cosmos/cosmos-sdk/client/keys"
-	rpc "github.com/cosmos/cosmos-sdk/client/rpc"
-	tx "github.com/cosmos/cosmos-sdk/client/tx"
-	auth "github.com/cosmos/cosmos-sdk/x/auth/rest"
-	bank "github.com/cosmos/cosmos-sdk/x/bank/rest"
-	ibc "github.com/cosmos/cosmos-sdk/x/ibc/rest"
-	version "github.com/cosmos/cosmos-sdk/version"
+	keys "inschain-tendermint/client/keys"
+	rpc "inschain-tendermint/client/rpc"
+	tx "inschain-tendermint/client/tx"
+	auth "inschain-tendermint/x/auth/rest"
+	bank "inschain-tendermint/x/bank/rest"
+	ibc "inschain-tendermint/x/ibc/rest"
+	version "inschain-tendermint/version"
 	//tmrpc "github.com/tendermint/tendermint/rpc/lib/server"
 	//ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	//tmtypes "github.com/tendermint/tendermint/types"
-	client "github.com/cosmos/cosmos-sdk/client"
+	client "inschain-tendermint/client"
 	"github.com/gorilla/mux"
 
 	//"time"
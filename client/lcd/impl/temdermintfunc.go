@@ -29,14 +29,14 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	client "github.com/cosmos/cosmos-sdk/client"
-	keys "github.com/cosmos/cosmos-sdk/client/keys"
-	bapp "github.com/cosmos/cosmos-sdk/examples/basecoin/app"
-	btypes "github.com/cosmos/cosmos-sdk/examples/basecoin/types"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/client/lcd"
+	client "inschain-tendermint/client"
+	keys "inschain-tendermint/client/keys"
+	bapp "inschain-tendermint/examples/basecoin/app"
+	btypes "inschain-tendermint/examples/basecoin/types"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/client/lcd"
 )
 
 var (
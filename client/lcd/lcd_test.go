@@ -29,12 +29,12 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	client "github.com/cosmos/cosmos-sdk/client"
-	keys "github.com/cosmos/cosmos-sdk/client/keys"
-	bapp "github.com/cosmos/cosmos-sdk/examples/basecoin/app"
-	btypes "github.com/cosmos/cosmos-sdk/examples/basecoin/types"
-	tests "github.com/cosmos/cosmos-sdk/tests"
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	client "inschain-tendermint/client"
+	keys "inschain-tendermint/client/keys"
+	bapp "inschain-tendermint/examples/basecoin/app"
+	btypes "inschain-tendermint/examples/basecoin/types"
+	tests "inschain-tendermint/tests"
+	sdk "inschain-tendermint/types"
 )
 
 var (
@@ -245,6 +245,42 @@ func TestCoinSend(t *testing.T) {
 	assert.Equal(t, int64(1), mycoins.Amount)
 }
 
+func TestOfflineSend(t *testing.T) {
+
+	acc := getAccount(t, sendAddr)
+	initialBalance := acc.GetCoins()
+
+	// generate the unsigned tx
+	kb := client.MockKeyBase()
+	receiveInfo, _, err := kb.Create("receive_address", "1234567890", cryptoKeys.CryptoAlgo("ed25519"))
+	require.Nil(t, err)
+	receiveAddr := receiveInfo.PubKey.Address().String()
+	jsonStr := []byte(fmt.Sprintf(`{ "name":"%s", "sequence":%d, "generate_only":true, "amount":[{ "denom": "%s", "amount": 1 }] }`, name, acc.GetSequence(), coinDenom))
+	res, unsignedTx := request(t, port, "POST", "/accounts/"+receiveAddr+"/send", jsonStr)
+	require.Equal(t, http.StatusOK, res.StatusCode, unsignedTx)
+
+	// an unsigned tx can't be broadcast
+	res, body := request(t, port, "POST", "/txs/broadcast", []byte(fmt.Sprintf(`{ "tx":%s }`, unsignedTx)))
+	require.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+
+	// sign it
+	jsonStr = []byte(fmt.Sprintf(`{ "name":"%s", "password":"%s", "tx":%s }`, name, password, unsignedTx))
+	res, signedTx := request(t, port, "POST", "/txs/sign", jsonStr)
+	require.Equal(t, http.StatusOK, res.StatusCode, signedTx)
+
+	// broadcast it
+	res, body = request(t, port, "POST", "/txs/broadcast", []byte(fmt.Sprintf(`{ "tx":%s }`, signedTx)))
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var resultTx ctypes.ResultBroadcastTxCommit
+	require.Nil(t, cdc.UnmarshalJSON([]byte(body), &resultTx))
+	tests.WaitForHeight(resultTx.Height+1, port)
+	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
+	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+
+	acc = getAccount(t, sendAddr)
+	assert.Equal(t, initialBalance[0].Amount-1, acc.GetCoins()[0].Amount)
+}
+
 func TestIBCTransfer(t *testing.T) {
 
 	acc := getAccount(t, sendAddr)
@@ -370,6 +406,7 @@ func startTMAndLCD() (*nm.Node, net.Listener, error) {
 	// XXX: need to set this so LCD knows the tendermint node address!
 	viper.Set(client.FlagNode, config.RPC.ListenAddress)
 	viper.Set(client.FlagChainID, genDoc.ChainID)
+	viper.Set(client.FlagTrustNode, true) // no light client is initialized
 
 	node, err := startTM(config, logger, genDoc, privVal, app)
 	if err != nil {
@@ -1,6 +1,6 @@
 package main
 
-import "github.com/cosmos/cosmos-sdk/client/lcd/impl"
+import "inschain-tendermint/client/lcd/impl"
 
 
 func main() {
@@ -1,6 +1,6 @@
 package main
 
-import  f "github.com/cosmos/cosmos-sdk/client/lcd/impl"
+import  f "inschain-tendermint/client/lcd/impl"
 func main() {
 
 	f.StartTMAndLCD()
@@ -12,17 +12,19 @@ import (
 	tmserver "github.com/tendermint/tendermint/rpc/lib/server"
 	cmn "github.com/tendermint/tmlibs/common"
 
-	client "github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/context"
-	keys "github.com/cosmos/cosmos-sdk/client/keys"
-	rpc "github.com/cosmos/cosmos-sdk/client/rpc"
-	tx "github.com/cosmos/cosmos-sdk/client/tx"
-	version "github.com/cosmos/cosmos-sdk/version"
-	"github.com/cosmos/cosmos-sdk/wire"
-	auth "github.com/cosmos/cosmos-sdk/x/auth/client/rest"
-	bank "github.com/cosmos/cosmos-sdk/x/bank/client/rest"
-	ibc "github.com/cosmos/cosmos-sdk/x/ibc/client/rest"
+	client "inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	keys "inschain-tendermint/client/keys"
+	rpc "inschain-tendermint/client/rpc"
+	tx "inschain-tendermint/client/tx"
+	version "inschain-tendermint/version"
+	"inschain-tendermint/wire"
+	auth "inschain-tendermint/x/auth/client/rest"
+	bank "inschain-tendermint/x/bank/client/rest"
+	gov "inschain-tendermint/x/gov/client/rest"
+	ibc "inschain-tendermint/x/ibc/client/rest"
 	mutual "inschain-tendermint/x/mutual/client/rest"
+	stake "inschain-tendermint/x/stake/client/rest"
 )
 
 const (
@@ -43,6 +45,7 @@ func ServeCommand(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().String(flagCORS, "", "Set to domains that can make CORS requests (* for all)")
 	cmd.Flags().StringP(client.FlagChainID, "c", "", "ID of chain we connect to")
 	cmd.Flags().StringP(client.FlagNode, "n", "tcp://localhost:46657", "Node to connect to")
+	cmd.Flags().Bool(client.FlagTrustNode, true, "Don't verify the proofs of the queries with the light client, which only serves the queries of a key")
 	return cmd
 }
 
@@ -84,6 +87,8 @@ func createHandler(cdc *wire.Codec) http.Handler {
 	auth.RegisterRoutes(ctx, r, cdc, "acc")
 	bank.RegisterRoutes(ctx, r, cdc, kb)
 	ibc.RegisterRoutes(ctx, r, cdc, kb)
+	stake.RegisterRoutes(ctx, r, cdc, kb)
 	mutual.RegisterRoutes(ctx, r, cdc, kb)
+	gov.RegisterRoutes(ctx, r, cdc, kb)
 	return r
 }
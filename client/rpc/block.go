@@ -8,8 +8,8 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
 
-	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/context"
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
 )
 
 const (
@@ -1,12 +1,22 @@
 package rpc
 
 import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/lite"
+	liteclient "github.com/tendermint/tendermint/lite/client"
+	"github.com/tendermint/tendermint/lite/files"
+	tmtypes "github.com/tendermint/tendermint/types"
 
-	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/context"
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
 )
 
 const (
@@ -16,11 +26,6 @@ const (
 	flagValHash = "validator-set"
 )
 
-// XXX: remove this when not needed
-func todoNotImplemented(_ *cobra.Command, _ []string) error {
-	return errors.New("TODO: Command not yet implemented")
-}
-
 // AddCommands adds a number of rpc-related subcommands
 func AddCommands(cmd *cobra.Command) {
 	cmd.AddCommand(
@@ -35,7 +40,12 @@ func initClientCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize light client",
-		RunE:  todoNotImplemented,
+		Long: `Initialize the light client with a trusted header of the chain.
+The queries without trust-node are then verified against the headers certified
+by the light client, so that the node queried doesn't need to be trusted.
+Only the queries of a single key, such as an account, can be verified: the
+listings, the paged queries and the module queries still require trust-node.`,
+		RunE: initClient,
 	}
 	cmd.Flags().StringP(client.FlagChainID, "c", "", "ID of chain we connect to")
 	cmd.Flags().StringP(client.FlagNode, "n", "tcp://localhost:46657", "Node to connect to")
@@ -45,6 +55,78 @@ func initClientCommand() *cobra.Command {
 	return cmd
 }
 
+func initClient(cmd *cobra.Command, args []string) error {
+	chainID := viper.GetString(client.FlagChainID)
+	if chainID == "" {
+		return errors.New("Must provide the chain ID")
+	}
+	source := liteclient.NewHTTPProvider(viper.GetString(client.FlagNode))
+	fc, err := trustedCommit(chainID, source)
+	if err != nil {
+		return err
+	}
+	err = context.InitLiteClient(chainID, fc)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Light client initialized at height %d\n", fc.Height())
+	return nil
+}
+
+// the first trusted commit of the light client, verified with the
+// genesis, commit or validator set flag
+func trustedCommit(chainID string, source lite.Provider) (fc lite.FullCommit, err error) {
+	switch {
+	case viper.GetString(flagCommit) != "":
+		// a trusted commit with its validator set
+		return files.LoadFullCommitJSON(viper.GetString(flagCommit))
+	case viper.GetString(flagGenesis) != "":
+		// the first commit of the chain, signed by the genesis validators
+		genDoc, err := tmtypes.GenesisDocFromFile(viper.GetString(flagGenesis))
+		if err != nil {
+			return fc, err
+		}
+		if genDoc.ChainID != chainID {
+			return fc, fmt.Errorf("Genesis is of chain %s, not %s", genDoc.ChainID, chainID)
+		}
+		fc, err = source.GetByHeight(1)
+		if err != nil {
+			return fc, err
+		}
+		return fc, checkValidatorsHash(fc, genesisValidators(genDoc).Hash())
+	case viper.GetString(flagValHash) != "":
+		// the latest commit, signed by the trusted validator set
+		hash, err := hex.DecodeString(viper.GetString(flagValHash))
+		if err != nil {
+			return fc, err
+		}
+		fc, err = source.LatestCommit()
+		if err != nil {
+			return fc, err
+		}
+		return fc, checkValidatorsHash(fc, hash)
+	default:
+		return fc, fmt.Errorf("Must provide one of --%s, --%s or --%s", flagGenesis, flagCommit, flagValHash)
+	}
+}
+
+// the validator set of the genesis
+func genesisValidators(genDoc *tmtypes.GenesisDoc) *tmtypes.ValidatorSet {
+	vals := make([]*tmtypes.Validator, len(genDoc.Validators))
+	for i, val := range genDoc.Validators {
+		vals[i] = tmtypes.NewValidator(val.PubKey, val.Power)
+	}
+	return tmtypes.NewValidatorSet(vals)
+}
+
+func checkValidatorsHash(fc lite.FullCommit, hash []byte) error {
+	if !bytes.Equal(fc.Validators.Hash(), hash) {
+		return fmt.Errorf("Validator set %X of height %d is not the trusted one %X",
+			fc.Validators.Hash(), fc.Height(), hash)
+	}
+	return nil
+}
+
 // Register REST endpoints
 func RegisterRoutes(ctx context.CoreContext, r *mux.Router) {
 	r.HandleFunc("/node_info", NodeInfoRequestHandlerFn(ctx)).Methods("GET")
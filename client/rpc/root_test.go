@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/lite"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tmlibs/cli"
+
+	"inschain-tendermint/client/context"
+)
+
+func TestInitClient(t *testing.T) {
+	home, err := ioutil.TempDir("", "lite-init")
+	require.Nil(t, err)
+	defer os.RemoveAll(home)
+	viper.Set(cli.HomeFlag, home)
+	defer viper.Reset()
+
+	chainID := "lite-test"
+	keys := lite.GenValKeys(4)
+	vals := keys.ToValidators(10, 0)
+	source := lite.NewMemStoreProvider()
+	first := keys.GenFullCommit(chainID, 1, nil, vals, []byte("app"), []byte("params"), []byte("results"), 0, len(keys))
+	require.Nil(t, source.StoreCommit(first))
+
+	genDoc := tmtypes.GenesisDoc{ChainID: chainID}
+	for _, key := range keys {
+		genDoc.Validators = append(genDoc.Validators, tmtypes.GenesisValidator{PubKey: key.PubKey(), Power: 10})
+	}
+	genesis := filepath.Join(home, "genesis.json")
+	require.Nil(t, genDoc.SaveAs(genesis))
+	otherKeys := lite.GenValKeys(4)
+
+	cases := []struct {
+		chainID string
+		flag    string
+		value   string
+		ok      bool
+	}{
+		{chainID, flagGenesis, genesis, true},
+		{"other-chain", flagGenesis, genesis, false}, // genesis of another chain
+		{chainID, flagValHash, hex.EncodeToString(vals.Hash()), true},
+		{chainID, flagValHash, hex.EncodeToString(otherKeys.ToValidators(10, 0).Hash()), false},
+		{chainID, "", "", false}, // nothing to verify the connection
+	}
+	for i, tc := range cases {
+		for _, flag := range []string{flagGenesis, flagCommit, flagValHash} {
+			viper.Set(flag, "")
+		}
+		if tc.flag != "" {
+			viper.Set(tc.flag, tc.value)
+		}
+		fc, err := trustedCommit(tc.chainID, source)
+		if !tc.ok {
+			assert.NotNil(t, err, "%d", i)
+			continue
+		}
+		require.Nil(t, err, "%d: %v", i, err)
+		assert.Equal(t, int64(1), fc.Height(), "%d", i)
+	}
+
+	// the trusted commit must be of the chain
+	assert.NotNil(t, context.InitLiteClient("other-chain", first))
+	require.Nil(t, context.InitLiteClient(chainID, first))
+	cert, err := context.NewLiteCertifier(chainID, "tcp://localhost:46657")
+	require.Nil(t, err)
+	assert.Equal(t, chainID, cert.ChainID())
+}
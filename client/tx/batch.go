@@ -0,0 +1,162 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+)
+
+// BatchTxCmd signs the msgs of a file and broadcasts them in a single tx.
+// The file holds a JSON list of msgs, as encoded by the codec, eg.
+//
+//	[
+//	  {"type": "mutual/MutualBondMsg", "value": {...}},
+//	  {"type": "mutual/MutualPolicyApprovalMsg", "value": {...}}
+//	]
+//
+// The msgs are executed in order and either all succeed or none is applied.
+func BatchTxCmd(cdc *wire.Codec, decoder sdk.AccountDecoder) *cobra.Command {
+	return &cobra.Command{
+		Use:   "batch [file]",
+		Short: "Sign the msgs of a file and broadcast them in a single tx",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			msgs, err := decodeMsgs(cdc, bz)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper().WithDecoder(decoder)
+			if ctx.GenerateOnly {
+				return ctx.PrintUnsignedTx(msgs, cdc)
+			}
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msgs, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+}
+
+// decode a JSON list of msgs and validate them
+func decodeMsgs(cdc *wire.Codec, bz []byte) ([]sdk.Msg, error) {
+	var msgs []sdk.Msg
+	err := cdc.UnmarshalJSON(bz, &msgs)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("no msg in the batch")
+	}
+	for i, msg := range msgs {
+		if err := msg.ValidateBasic(); err != nil {
+			return nil, fmt.Errorf("msg %d: %s", i, err.Error())
+		}
+	}
+	return msgs, nil
+}
+
+// REST request body of a batch of msgs
+type batchBody struct {
+	LocalAccountName string            `json:"name"`
+	Password         string            `json:"password"`
+	ChainID          string            `json:"chain_id"`
+	Sequence         int64             `json:"sequence"`
+	Gas              int64             `json:"gas"`
+	GenerateOnly     bool              `json:"generate_only"`
+	Msgs             []json.RawMessage `json:"msgs"`
+}
+
+// BatchTxRequestHandlerFn - http request handler to sign and broadcast a batch of msgs,
+// or with generate_only to return the unsigned tx to sign offline
+func BatchTxRequestHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m batchBody
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		err = json.Unmarshal(body, &m)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		msgsBytes, err := json.Marshal(m.Msgs)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		msgs, err := decodeMsgs(cdc, msgsBytes)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		// sign
+		if m.ChainID != "" {
+			ctx = ctx.WithChainID(m.ChainID)
+		}
+		if m.Gas > 0 {
+			ctx = ctx.WithGas(m.Gas)
+		}
+		ctx = ctx.WithSequence(m.Sequence)
+		if m.GenerateOnly {
+			signMsg, err := ctx.BuildSignMsg(msgs)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			output, err := wire.MarshalJSONIndent(cdc, signMsg)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			w.Write(output)
+			return
+		}
+		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, msgs, cdc)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		// send
+		res, err := ctx.BroadcastTx(txBytes)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		output, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.Write(output)
+	}
+}
@@ -2,36 +2,100 @@ package tx
 
 import (
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 
-	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/spf13/cobra"
+
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	"inschain-tendermint/wire"
 )
 
-// Tx Broadcast Body
-type BroadcastTxBody struct {
-	TxBytes string `json="tx"`
+// BroadcastTxCmd broadcasts the tx of a file, signed by every signer
+func BroadcastTxCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "broadcast [file]",
+		Short: "Broadcast the tx of a file, signed by every signer",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			txBytes, err := buildOfflineTx(cdc, bz)
+			if err != nil {
+				return err
+			}
+
+			res, err := context.NewCoreContextFromViper().BroadcastTx(txBytes)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+	cmd.Flags().String(client.FlagNode, "tcp://localhost:46657", "<host>:<port> to tendermint rpc interface for this chain")
+	return cmd
 }
 
-// BroadcastTx REST Handler
-func BroadcastTxRequestHandlerFn(ctx context.CoreContext) http.HandlerFunc {
+// decode a signed offline tx and encode the tx to broadcast
+func buildOfflineTx(cdc *wire.Codec, bz []byte) ([]byte, error) {
+	offline, err := decodeOfflineTx(cdc, bz)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := offline.StdTx()
+	if err != nil {
+		return nil, err
+	}
+	return cdc.MarshalBinary(tx)
+}
+
+// REST request body to broadcast a signed tx
+type broadcastBody struct {
+	Tx json.RawMessage `json:"tx"`
+}
+
+// BroadcastTxRequestHandlerFn - http request handler to broadcast a tx signed by every signer
+func BroadcastTxRequestHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var m BroadcastTxBody
+		var m broadcastBody
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		err = json.Unmarshal(body, &m)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		txBytes, err := buildOfflineTx(cdc, m.Tx)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
 
-		decoder := json.NewDecoder(r.Body)
-		err := decoder.Decode(&m)
+		res, err := ctx.BroadcastTx(txBytes)
 		if err != nil {
-			w.WriteHeader(400)
+			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(err.Error()))
 			return
 		}
 
-		res, err := ctx.BroadcastTx([]byte(m.TxBytes))
+		output, err := json.MarshalIndent(res, "", "  ")
 		if err != nil {
-			w.WriteHeader(500)
+			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(err.Error()))
 			return
 		}
 
-		w.Write([]byte(string(res.Height)))
+		w.Write(output)
 	}
 }
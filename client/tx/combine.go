@@ -0,0 +1,80 @@
+package tx
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+)
+
+// CombineTxCmd combines the signatures of several copies of a tx,
+// signed in parallel by different signers or by different keys of a multisig
+func CombineTxCmd(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "combine [file]...",
+		Short: "Combine the signatures of several signed copies of a tx",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txs := make([]OfflineTx, len(args))
+			for i, file := range args {
+				bz, err := ioutil.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				txs[i], err = decodeOfflineTx(cdc, bz)
+				if err != nil {
+					return errors.Wrap(err, file)
+				}
+			}
+			tx, err := combineOfflineTxs(txs)
+			if err != nil {
+				return err
+			}
+
+			output, err := wire.MarshalJSONIndent(cdc, tx)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+}
+
+// combine the signatures of copies of the same tx: a missing signature
+// is taken from any copy, the multisignatures are merged
+func combineOfflineTxs(txs []OfflineTx) (OfflineTx, error) {
+	tx := txs[0]
+	signBytes := tx.SignMsg().Bytes()
+	for _, other := range txs[1:] {
+		if !bytes.Equal(signBytes, other.SignMsg().Bytes()) {
+			return tx, errors.New("The txs to combine are not the same")
+		}
+		for i, sig := range other.Signatures {
+			if sig.Signature == nil {
+				continue
+			}
+			multisig, ok := sig.Signature.(auth.Multisignature)
+			current, isMultisig := tx.Signatures[i].Signature.(auth.Multisignature)
+			if !ok || !isMultisig {
+				if tx.Signatures[i].Signature == nil {
+					tx.Signatures[i] = sig
+				}
+				continue
+			}
+			if len(multisig.Bitmap) != len(current.Bitmap) {
+				return tx, errors.Errorf("Multisignatures %d of the txs are not of the same key", i)
+			}
+			for j, index := range multisig.Signers() {
+				current = current.AddSignature(index, multisig.Sigs[j])
+			}
+			tx.Signatures[i].Signature = current
+		}
+	}
+	return tx, nil
+}
@@ -13,10 +13,10 @@ import (
 	abci "github.com/tendermint/abci/types"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 
-	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 )
 
 // Get the default command for a tx query
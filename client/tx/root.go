@@ -4,8 +4,8 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
 
-	"github.com/cosmos/cosmos-sdk/client/context"
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/client/context"
+	"inschain-tendermint/wire"
 )
 
 // AddCommands adds a number of tx-query related subcommands
@@ -13,13 +13,17 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 	cmd.AddCommand(
 		SearchTxCmd(cdc),
 		QueryTxCmd(cdc),
+		SignTxCmd(cdc),
+		CombineTxCmd(cdc),
+		BroadcastTxCmd(cdc),
 	)
 }
 
 // register REST routes
 func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec) {
 	r.HandleFunc("/txs/{hash}", QueryTxRequestHandlerFn(cdc, ctx)).Methods("GET")
+	r.HandleFunc("/txs/batch", BatchTxRequestHandlerFn(cdc, ctx)).Methods("POST")
+	r.HandleFunc("/txs/sign", SignTxRequestHandlerFn(cdc)).Methods("POST")
+	r.HandleFunc("/txs/broadcast", BroadcastTxRequestHandlerFn(cdc, ctx)).Methods("POST")
 	// r.HandleFunc("/txs", SearchTxRequestHandler(cdc)).Methods("GET")
-	// r.HandleFunc("/txs/sign", SignTxRequstHandler).Methods("POST")
-	// r.HandleFunc("/txs/broadcast", BroadcastTxRequestHandler).Methods("POST")
 }
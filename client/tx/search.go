@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -11,9 +12,9 @@ import (
 
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 
-	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/context"
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	"inschain-tendermint/wire"
 )
 
 const (
@@ -28,8 +29,9 @@ func SearchTxCmd(cdc *wire.Codec) *cobra.Command {
 		Short: "Search for all transactions that match the given tags",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			tags := viper.GetStringSlice(flagTags)
+			any := viper.GetBool(flagAny)
 
-			output, err := searchTx(context.NewCoreContextFromViper(), cdc, tags)
+			output, err := searchTx(context.NewCoreContextFromViper(), cdc, tags, any)
 			if err != nil {
 				return err
 			}
@@ -42,17 +44,19 @@ func SearchTxCmd(cdc *wire.Codec) *cobra.Command {
 
 	// TODO: change this to false once proofs built in
 	cmd.Flags().Bool(client.FlagTrustNode, true, "Don't verify proofs for responses")
-	cmd.Flags().StringSlice(flagTags, nil, "Tags that must match (may provide multiple)")
+	cmd.Flags().StringSlice(flagTags, nil, "Tags that must match as key=value, eg. policy=<address> (may provide multiple)")
 	cmd.Flags().Bool(flagAny, false, "Return transactions that match ANY tag, rather than ALL")
 	return cmd
 }
 
-func searchTx(ctx context.CoreContext, cdc *wire.Codec, tags []string) ([]byte, error) {
+func searchTx(ctx context.CoreContext, cdc *wire.Codec, tags []string, any bool) ([]byte, error) {
 	if len(tags) == 0 {
 		return nil, errors.New("Must declare at least one tag to search")
 	}
-	// XXX: implement ANY
-	query := strings.Join(tags, " AND ")
+	conditions, err := buildConditions(tags)
+	if err != nil {
+		return nil, err
+	}
 
 	// get the node
 	node, err := ctx.GetNode()
@@ -61,9 +65,35 @@ func searchTx(ctx context.CoreContext, cdc *wire.Codec, tags []string) ([]byte,
 	}
 
 	prove := !viper.GetBool(client.FlagTrustNode)
-	res, err := node.TxSearch(query, prove)
-	if err != nil {
-		return nil, err
+	var res []*ctypes.ResultTx
+	if !any {
+		res, err = node.TxSearch(strings.Join(conditions, " AND "), prove)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// the query language has no OR, search each tag on its own and
+		// merge the transactions matching several of them
+		seen := make(map[string]bool)
+		for _, condition := range conditions {
+			txs, err := node.TxSearch(condition, prove)
+			if err != nil {
+				return nil, err
+			}
+			for _, tx := range txs {
+				if seen[string(tx.Hash)] {
+					continue
+				}
+				seen[string(tx.Hash)] = true
+				res = append(res, tx)
+			}
+		}
+		sort.Slice(res, func(i, j int) bool {
+			if res[i].Height != res[j].Height {
+				return res[i].Height < res[j].Height
+			}
+			return res[i].Index < res[j].Index
+		})
 	}
 
 	info, err := formatTxResults(cdc, res)
@@ -78,6 +108,35 @@ func searchTx(ctx context.CoreContext, cdc *wire.Codec, tags []string) ([]byte,
 	return output, nil
 }
 
+// buildConditions turns key=value tags into Tendermint query conditions,
+// quoting the values which are not already quoted. The query language has
+// no escaping, so keys and values which would break out of the condition
+// are rejected.
+func buildConditions(tags []string) ([]string, error) {
+	conditions := make([]string, len(tags))
+	for i, tag := range tags {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("tag %q must be of the form key=value", tag)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+			value = value[1 : len(value)-1]
+		}
+		if key == "" || value == "" {
+			return nil, fmt.Errorf("tag %q must be of the form key=value", tag)
+		}
+		if strings.ContainsAny(key, " \t\n\r\\()\"'=><") {
+			return nil, fmt.Errorf("tag %q has an invalid key", tag)
+		}
+		if strings.ContainsAny(value, "\"'") {
+			return nil, fmt.Errorf("tag %q has a quote in its value", tag)
+		}
+		conditions[i] = fmt.Sprintf("%s='%s'", key, value)
+	}
+	return conditions, nil
+}
+
 func formatTxResults(cdc *wire.Codec, res []*ctypes.ResultTx) ([]txInfo, error) {
 	var err error
 	out := make([]txInfo, len(res))
@@ -96,15 +155,21 @@ func formatTxResults(cdc *wire.Codec, res []*ctypes.ResultTx) ([]txInfo, error)
 // Search Tx REST Handler
 func SearchTxRequestHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		tag := r.FormValue("tag")
-		if tag == "" {
+		err := r.ParseForm()
+		if err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		tags := r.Form["tag"]
+		if len(tags) == 0 {
 			w.WriteHeader(400)
 			w.Write([]byte("You need to provide a tag to search for."))
 			return
 		}
+		any := r.Form.Get(flagAny) == "true"
 
-		tags := []string{tag}
-		output, err := searchTx(ctx, cdc, tags)
+		output, err := searchTx(ctx, cdc, tags, any)
 		if err != nil {
 			w.WriteHeader(500)
 			w.Write([]byte(err.Error()))
@@ -2,47 +2,187 @@ package tx
 
 import (
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 
-	keybase "github.com/cosmos/cosmos-sdk/client/keys"
-	keys "github.com/tendermint/go-crypto/keys"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	"inschain-tendermint/client/keys"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 )
 
-// REST request body
-// TODO does this need to be exposed?
-type SignTxBody struct {
-	Name     string `json="name"`
-	Password string `json="password"`
-	TxBytes  string `json="tx"`
+// OfflineTx is a tx signed offline: the msg to sign, as printed with
+// --generate-only, and the signatures collected so far in the order
+// of the signers of the msgs. A missing signature is left empty.
+type OfflineTx struct {
+	ChainID    string             `json:"chain_id"`
+	Sequences  []int64            `json:"sequences"`
+	Fee        sdk.StdFee         `json:"fee"`
+	FeePayer   sdk.Address        `json:"fee_payer,omitempty"`
+	Msgs       []sdk.Msg          `json:"msgs"`
+	Signatures []sdk.StdSignature `json:"signatures"`
 }
 
-// sign transaction REST Handler
-func SignTxRequstHandler(w http.ResponseWriter, r *http.Request) {
-	var kb keys.Keybase
-	var m SignTxBody
+// SignMsg returns the msg signed by every signer
+func (tx OfflineTx) SignMsg() sdk.StdSignMsg {
+	return sdk.StdSignMsg{
+		ChainID:   tx.ChainID,
+		Sequences: tx.Sequences,
+		Fee:       tx.Fee,
+		FeePayer:  tx.FeePayer,
+		Msgs:      tx.Msgs,
+	}
+}
 
-	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(&m)
-	if err != nil {
-		w.WriteHeader(400)
-		w.Write([]byte(err.Error()))
-		return
+// StdTx returns the tx to broadcast, once signed by every signer
+func (tx OfflineTx) StdTx() (sdk.StdTx, error) {
+	signBytes := tx.SignMsg().Bytes()
+	for i, sig := range tx.Signatures {
+		if sig.PubKey == nil || sig.Signature == nil {
+			return sdk.StdTx{}, errors.Errorf("Missing signature %d of the tx", i)
+		}
+		if !sig.PubKey.VerifyBytes(signBytes, sig.Signature) {
+			return sdk.StdTx{}, errors.Errorf("Invalid signature %d of the tx", i)
+		}
 	}
+	return sdk.NewStdTx(tx.Msgs, tx.Fee, tx.Signatures).WithFeePayer(tx.FeePayer), nil
+}
 
-	kb, err = keybase.GetKeyBase()
+// decode an offline tx and validate it, with an empty signature
+// for every signer when none is present
+func decodeOfflineTx(cdc *wire.Codec, bz []byte) (tx OfflineTx, err error) {
+	err = cdc.UnmarshalJSON(bz, &tx)
 	if err != nil {
-		w.WriteHeader(500)
-		w.Write([]byte(err.Error()))
-		return
+		return tx, err
+	}
+	if tx.ChainID == "" {
+		return tx, errors.New("Chain ID of the tx not specified")
+	}
+	if len(tx.Msgs) == 0 {
+		return tx, errors.New("No msg in the tx")
+	}
+	for i, msg := range tx.Msgs {
+		if err := msg.ValidateBasic(); err != nil {
+			return tx, fmt.Errorf("msg %d: %s", i, err.Error())
+		}
 	}
 
-	//TODO check if account exists
-	sig, _, err := kb.Sign(m.Name, m.Password, []byte(m.TxBytes))
-	if err != nil {
-		w.WriteHeader(403)
-		w.Write([]byte(err.Error()))
-		return
+	signers := sdk.MsgsSigners(tx.Msgs)
+	if len(tx.Sequences) != len(signers) {
+		return tx, errors.Errorf("Expected %d sequences, got %d", len(signers), len(tx.Sequences))
+	}
+	if len(tx.Signatures) == 0 {
+		tx.Signatures = make([]sdk.StdSignature, len(signers))
+	}
+	if len(tx.Signatures) != len(signers) {
+		return tx, errors.Errorf("Expected %d signatures, got %d", len(signers), len(tx.Signatures))
+	}
+	return tx, nil
+}
+
+const flagMultisig = "multisig"
+
+// SignTxCmd adds the signature of a key to the tx of a file, generated
+// with --generate-only or already signed by other signers, and prints
+// the signed tx to pass to the next signer or to broadcast.
+// With --multisig, the key signs as one of the keys of a multisig signer.
+func SignTxCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign [file]",
+		Short: "Sign the tx of a file with the key of one of its signers",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			tx, err := decodeOfflineTx(cdc, bz)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			passphrase, err := ctx.GetPassphraseFromStdin(ctx.FromAddressName)
+			if err != nil {
+				return err
+			}
+			if name := viper.GetString(flagMultisig); name != "" {
+				info, err := keys.GetMultisig(name)
+				if err != nil {
+					return err
+				}
+				tx.Signatures, err = context.SignMultisigStdSignMsg(ctx.FromAddressName, passphrase, info.PubKey, tx.SignMsg(), tx.Signatures)
+			} else {
+				tx.Signatures, err = context.SignStdSignMsg(ctx.FromAddressName, passphrase, tx.SignMsg(), tx.Signatures)
+			}
+			if err != nil {
+				return err
+			}
+
+			output, err := wire.MarshalJSONIndent(cdc, tx)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
 	}
+	cmd.Flags().String(client.FlagName, "", "Name of private key with which to sign")
+	cmd.Flags().String(flagMultisig, "", "Name of the multisig key to sign for, the private key is one of its keys")
+	return cmd
+}
 
-	w.Write(sig.Bytes())
+// REST request body to sign a tx
+type signBody struct {
+	Name     string          `json:"name"`
+	Password string          `json:"password"`
+	Tx       json.RawMessage `json:"tx"`
+}
+
+// SignTxRequestHandlerFn - http request handler to add the signature
+// of a key to a tx, returns the signed tx
+func SignTxRequestHandlerFn(cdc *wire.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m signBody
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		err = json.Unmarshal(body, &m)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		tx, err := decodeOfflineTx(cdc, m.Tx)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		tx.Signatures, err = context.SignStdSignMsg(m.Name, m.Password, tx.SignMsg(), tx.Signatures)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		output, err := wire.MarshalJSONIndent(cdc, tx)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.Write(output)
+	}
 }
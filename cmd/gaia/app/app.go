@@ -10,14 +10,19 @@ import (
 	"github.com/tendermint/tmlibs/log"
 
 	//bam "github.com/cosmos/cosmos-sdk/baseapp"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/bank"
-	"github.com/cosmos/cosmos-sdk/x/ibc"
-	"github.com/cosmos/cosmos-sdk/x/stake"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/ibc"
+	"inschain-tendermint/x/stake"
 	// mutual package
 	"inschain-tendermint/x/mutual"
+	"inschain-tendermint/x/feegrant"
+	"inschain-tendermint/x/slashing"
+	"inschain-tendermint/x/gov"
+	"inschain-tendermint/x/scheduler"
+	"inschain-tendermint/x/compliance"
 	// custom listeners
 	"inschain-tendermint/x/listener"
 	bam "inschain-tendermint/baseapp"
@@ -39,18 +44,34 @@ type GaiaApp struct {
 	cdc *wire.Codec
 
 	// keys to access the substores
-	keyMain    *sdk.KVStoreKey
-	keyAccount *sdk.KVStoreKey
-	keyIBC     *sdk.KVStoreKey
-	keyStake   *sdk.KVStoreKey
+	keyMain       *sdk.KVStoreKey
+	keyAccount    *sdk.KVStoreKey
+	keyIBC        *sdk.KVStoreKey
+	keyStake      *sdk.KVStoreKey
+	keyFeeGrant   *sdk.KVStoreKey
+	keySlashing   *sdk.KVStoreKey
+	keyGov        *sdk.KVStoreKey
+	keyToken      *sdk.KVStoreKey
+	keySupply     *sdk.KVStoreKey
+	keyHTLC       *sdk.KVStoreKey
+	keyScheduler  *sdk.KVStoreKey
+	keyCompliance *sdk.KVStoreKey
 	//keyMutual  *sdk.KVStoreKey
 
 	// Manage getting and setting accounts
 	accountMapper 	sdk.AccountMapper
 	coinKeeper    	bank.Keeper
+	supplyKeeper	bank.SupplyKeeper
+	tokenKeeper	bank.TokenKeeper
+	htlcKeeper	bank.HTLCKeeper
 	ibcMapper     	ibc.Mapper
 	stakeKeeper   	stake.Keeper
 	mutualKeeper	mutual.Keeper
+	feeGrantKeeper	feegrant.Keeper
+	slashingKeeper	slashing.Keeper
+	govKeeper	gov.Keeper
+	schedulerKeeper	scheduler.Keeper
+	complianceKeeper	compliance.Keeper
 }
 
 func NewGaiaApp(logger log.Logger, db dbm.DB) *GaiaApp {
@@ -58,12 +79,20 @@ func NewGaiaApp(logger log.Logger, db dbm.DB) *GaiaApp {
 
 	// create your application object
 	var app = &GaiaApp{
-		BaseApp:    bam.NewBaseApp(appName, cdc, logger, db),
-		cdc:        cdc,
-		keyMain:    sdk.NewKVStoreKey("main"),
-		keyAccount: sdk.NewKVStoreKey("acc"),
-		keyIBC:     sdk.NewKVStoreKey("ibc"),
-		keyStake:   sdk.NewKVStoreKey("stake"),
+		BaseApp:       bam.NewBaseApp(appName, cdc, logger, db),
+		cdc:           cdc,
+		keyMain:       sdk.NewKVStoreKey("main"),
+		keyAccount:    sdk.NewKVStoreKey("acc"),
+		keyIBC:        sdk.NewKVStoreKey("ibc"),
+		keyStake:      sdk.NewKVStoreKey("stake"),
+		keyFeeGrant:   sdk.NewKVStoreKey("feegrant"),
+		keySlashing:   sdk.NewKVStoreKey("slashing"),
+		keyGov:        sdk.NewKVStoreKey("gov"),
+		keyToken:      sdk.NewKVStoreKey("token"),
+		keySupply:     sdk.NewKVStoreKey("supply"),
+		keyHTLC:       sdk.NewKVStoreKey("htlc"),
+		keyScheduler:  sdk.NewKVStoreKey("scheduler"),
+		keyCompliance: sdk.NewKVStoreKey("compliance"),
 		//keyMutual:  sdk.NewKVStoreKey("mutual"),
 	}
 
@@ -74,31 +103,65 @@ func NewGaiaApp(logger log.Logger, db dbm.DB) *GaiaApp {
 		&auth.BaseAccount{}, // prototype
 	)
 
-	// add handlers
-	app.coinKeeper = bank.NewKeeper(app.accountMapper)
+	// add handlers, the transfers are checked against the compliance restrictions
+	app.complianceKeeper = compliance.NewKeeper(app.cdc, app.keyCompliance, app.RegisterCodespace(compliance.DefaultCodespace))
+	app.coinKeeper = bank.NewKeeper(app.accountMapper).WithTransferRestrictions(app.complianceKeeper)
+	app.supplyKeeper = bank.NewSupplyKeeper(app.cdc, app.keySupply, app.coinKeeper, app.RegisterCodespace(bank.DefaultCodespace)).WithTransferRestrictions(app.complianceKeeper)
+	app.tokenKeeper = bank.NewTokenKeeper(app.cdc, app.keyToken, app.supplyKeeper, app.RegisterCodespace(bank.DefaultCodespace))
+	app.htlcKeeper = bank.NewHTLCKeeper(app.cdc, app.keyHTLC, app.supplyKeeper, app.RegisterCodespace(bank.DefaultCodespace))
 	app.ibcMapper = ibc.NewMapper(app.cdc, app.keyIBC, app.RegisterCodespace(ibc.DefaultCodespace))
-	app.stakeKeeper = stake.NewKeeper(app.cdc, app.keyStake, app.coinKeeper, app.RegisterCodespace(stake.DefaultCodespace))
-	app.mutualKeeper = mutual.NewKeeper(app.cdc, app.keyStake, app.coinKeeper, app.RegisterCodespace(mutual.DefaultCodespace))
+	app.stakeKeeper = stake.NewKeeper(app.cdc, app.keyStake, app.supplyKeeper, app.RegisterCodespace(stake.DefaultCodespace))
+	app.mutualKeeper = mutual.NewKeeper(app.cdc, app.keyStake, app.supplyKeeper, app.RegisterCodespace(mutual.DefaultCodespace)).WithTransferRestrictions(app.complianceKeeper)
+	app.feeGrantKeeper = feegrant.NewKeeper(app.cdc, app.keyFeeGrant, app.RegisterCodespace(feegrant.DefaultCodespace))
+	app.slashingKeeper = slashing.NewKeeper(app.cdc, app.keySlashing, stake.NewViewSlashKeeper(app.stakeKeeper), app.RegisterCodespace(slashing.DefaultCodespace))
+	app.schedulerKeeper = scheduler.NewKeeper(app.cdc, app.keyScheduler, app.coinKeeper, app.RegisterCodespace(scheduler.DefaultCodespace))
+	app.govKeeper = gov.NewKeeper(app.cdc, app.keyGov, app.supplyKeeper, stake.NewViewSlashKeeper(app.stakeKeeper), app.RegisterCodespace(gov.DefaultCodespace)).
+		AddParamChangeHandler("stake", app.stakeKeeper.SetParam).
+		AddParamChangeHandler("slashing", app.slashingKeeper.SetParam).
+		AddParamChangeHandler("scheduler", app.schedulerKeeper.SetParam).
+		AddParamChangeHandler("compliance", app.complianceKeeper.SetParam)
 
 	// register message routes
 	app.Router().
 		AddRoute("bank", bank.NewHandler(app.coinKeeper)).
-		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.coinKeeper)).
+		AddRoute("token", bank.NewTokenHandler(app.tokenKeeper)).
+		AddRoute("htlc", bank.NewHTLCHandler(app.htlcKeeper)).
+		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.supplyKeeper)).
 		AddRoute("stake", stake.NewHandler(app.stakeKeeper)).
-		AddRoute("mutual", mutual.NewHandler(app.mutualKeeper))
+		AddRoute("mutual", mutual.NewHandler(app.mutualKeeper)).
+		AddRoute("feegrant", feegrant.NewHandler(app.feeGrantKeeper)).
+		AddRoute("slashing", slashing.NewHandler(app.slashingKeeper)).
+		AddRoute("gov", gov.NewHandler(app.govKeeper)).
+		AddRoute("scheduler", scheduler.NewHandler(app.schedulerKeeper)).
+		AddRoute("compliance", compliance.NewHandler(app.complianceKeeper))
+	app.QueryRouter().
+		AddRoute("token", bank.NewTokenQuerier(app.tokenKeeper)).
+		AddRoute("supply", bank.NewSupplyQuerier(app.supplyKeeper)).
+		AddRoute("htlc", bank.NewHTLCQuerier(app.htlcKeeper)).
+		AddRoute("stake", stake.NewQuerier(app.stakeKeeper)).
+		AddRoute("mutual", mutual.NewQuerier(app.mutualKeeper)).
+		AddRoute("feegrant", feegrant.NewQuerier(app.feeGrantKeeper)).
+		AddRoute("slashing", slashing.NewQuerier(app.slashingKeeper)).
+		AddRoute("gov", gov.NewQuerier(app.govKeeper)).
+		AddRoute("scheduler", scheduler.NewQuerier(app.schedulerKeeper)).
+		AddRoute("compliance", compliance.NewQuerier(app.complianceKeeper))
 
 	// initialize BaseApp
 	app.SetInitChainer(app.initChainer)
-	app.SetEndBlocker(stake.NewEndBlocker(app.stakeKeeper))
-	app.MountStoresIAVL(app.keyMain, app.keyAccount, app.keyIBC, app.keyStake)
-	app.SetAnteHandler(auth.NewAnteHandler(app.accountMapper, stake.FeeHandler))
+	app.SetBeginBlocker(slashing.NewBeginBlocker(app.slashingKeeper))
+	app.SetEndBlocker(app.endBlocker)
+	app.MountStoresIAVL(app.keyMain, app.keyAccount, app.keyIBC, app.keyStake, app.keyFeeGrant, app.keySlashing, app.keyGov, app.keyToken, app.keySupply, app.keyHTLC, app.keyScheduler, app.keyCompliance)
+	app.SetAnteHandler(auth.NewFeeGrantAnteHandler(app.accountMapper, app.feeGrantKeeper, stake.NewFeeHandler(app.stakeKeeper)))
 	err := app.LoadLatestVersion(app.keyMain)
 	if err != nil {
 		cmn.Exit(err.Error())
 	}
 
 	//Register event listener
-	listener.RegisterListeners()
+	err = listener.RegisterListeners(app.EventBus())
+	if err != nil {
+		cmn.Exit(err.Error())
+	}
 
 	return app
 }
@@ -112,10 +175,24 @@ func MakeCodec() *wire.Codec {
 	auth.RegisterWire(cdc)
 	sdk.RegisterWire(cdc)
 	mutual.RegisterWire(cdc)
+	feegrant.RegisterWire(cdc)
+	slashing.RegisterWire(cdc)
+	gov.RegisterWire(cdc)
+	scheduler.RegisterWire(cdc)
+	compliance.RegisterWire(cdc)
 	wire.RegisterCrypto(cdc)
 	return cdc
 }
 
+// application updates every end block, the escrows expire, the standing
+// orders are paid and the proposals are tallied before the validator set changes
+func (app *GaiaApp) endBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+	bank.HTLCEndBlocker(ctx, app.htlcKeeper)
+	scheduler.EndBlocker(ctx, app.schedulerKeeper)
+	gov.EndBlocker(ctx, app.govKeeper)
+	return stake.NewEndBlocker(app.stakeKeeper)(ctx, req)
+}
+
 // custom logic for gaia initialization
 func (app *GaiaApp) initChainer(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
 	stateJSON := req.AppStateBytes
@@ -129,13 +206,51 @@ func (app *GaiaApp) initChainer(ctx sdk.Context, req abci.RequestInitChain) abci
 
 	// load the accounts
 	for _, gacc := range genesisState.Accounts {
-		acc := gacc.ToAccount()
+		acc := gacc.ToAccountI()
 		app.accountMapper.SetAccount(ctx, acc)
 	}
 
+	// load the supply, computed from the genesis if it predates it, then the
+	// tokens, the denoms without a token are native tokens
+	supplyData := genesisState.SupplyData
+	if len(supplyData.Supply) == 0 {
+		supplyData = genesisSupply(genesisState)
+	}
+	bank.InitSupplyGenesis(ctx, app.supplyKeeper, supplyData)
+	bank.InitTokenGenesis(ctx, app.tokenKeeper, genesisState.TokenData)
+	app.tokenKeeper.RegisterNativeTokens(ctx, app.supplyKeeper.GetTotalSupply(ctx))
+	bank.InitHTLCGenesis(ctx, app.htlcKeeper, genesisState.HTLCData)
+
 	// load the initial stake information
 	stake.InitGenesis(ctx, app.stakeKeeper, genesisState.StakeData)
 
+	// load the slashing params, the defaults if the genesis predates them
+	slashingData := genesisState.SlashingData
+	if slashingData.Params.SignedBlocksWindow == 0 {
+		slashingData = slashing.DefaultGenesisState()
+	}
+	slashing.InitGenesis(ctx, app.slashingKeeper, slashingData)
+
+	// load the governance params, the defaults if the genesis predates them
+	govData := genesisState.GovData
+	if govData.Params.VotingPeriod == 0 {
+		govData = gov.DefaultGenesisState()
+	}
+	gov.InitGenesis(ctx, app.govKeeper, govData)
+
+	// load the standing orders, the default params if the genesis predates them
+	schedulerData := genesisState.SchedulerData
+	if schedulerData.Params.MaxOrdersPerBlock == 0 {
+		schedulerData.Params = scheduler.DefaultParams()
+	}
+	if schedulerData.Params.MaxOrdersPerSender == 0 {
+		schedulerData.Params.MaxOrdersPerSender = scheduler.DefaultParams().MaxOrdersPerSender
+	}
+	scheduler.InitGenesis(ctx, app.schedulerKeeper, schedulerData)
+
+	// load the frozen addresses and the allow-lists
+	compliance.InitGenesis(ctx, app.complianceKeeper, genesisState.ComplianceData)
+
 	return abci.ResponseInitChain{}
 }
 
@@ -153,8 +268,15 @@ func (app *GaiaApp) ExportAppStateJSON() (appState json.RawMessage, err error) {
 	app.accountMapper.IterateAccounts(ctx, appendAccount)
 
 	genState := GenesisState{
-		Accounts:  accounts,
-		StakeData: stake.WriteGenesis(ctx, app.stakeKeeper),
+		Accounts:       accounts,
+		StakeData:      stake.WriteGenesis(ctx, app.stakeKeeper),
+		SlashingData:   slashing.WriteGenesis(ctx, app.slashingKeeper),
+		GovData:        gov.WriteGenesis(ctx, app.govKeeper),
+		TokenData:      bank.WriteTokenGenesis(ctx, app.tokenKeeper),
+		SupplyData:     bank.WriteSupplyGenesis(ctx, app.supplyKeeper),
+		HTLCData:       bank.WriteHTLCGenesis(ctx, app.htlcKeeper),
+		SchedulerData:  scheduler.WriteGenesis(ctx, app.schedulerKeeper),
+		ComplianceData: compliance.WriteGenesis(ctx, app.complianceKeeper),
 	}
 	return wire.MarshalJSONIndent(app.cdc, genState)
 }
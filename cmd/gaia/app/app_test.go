@@ -9,11 +9,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/bank"
-	"github.com/cosmos/cosmos-sdk/x/ibc"
-	"github.com/cosmos/cosmos-sdk/x/stake"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/ibc"
+	"inschain-tendermint/x/stake"
 
 	abci "github.com/tendermint/abci/types"
 	crypto "github.com/tendermint/go-crypto"
@@ -39,7 +39,7 @@ var (
 	manyCoins = sdk.Coins{{"foocoin", 1}, {"barcoin", 1}}
 	fee       = sdk.StdFee{
 		sdk.Coins{{"foocoin", 0}},
-		0,
+		100000,
 	}
 
 	sendMsg1 = bank.MsgSend{
@@ -321,6 +321,48 @@ func TestMsgSendDependent(t *testing.T) {
 	CheckBalance(t, gapp, addr1, "42foocoin")
 }
 
+func TestMultiMsgs(t *testing.T) {
+	gapp := newGaiaApp()
+
+	genCoins, err := sdk.ParseCoins("42foocoin")
+	require.Nil(t, err)
+
+	acc1 := &auth.BaseAccount{
+		Address: addr1,
+		Coins:   genCoins,
+	}
+	acc2 := &auth.BaseAccount{
+		Address: addr2,
+		Coins:   genCoins,
+	}
+
+	err = setGenesis(gapp, acc1, acc2)
+	assert.Nil(t, err)
+
+	// the second msg fails, so the first one is reverted
+	overspend := bank.MsgSend{
+		Inputs:  []bank.Input{bank.NewInput(addr2, sdk.Coins{{"foocoin", 100}})},
+		Outputs: []bank.Output{bank.NewOutput(addr1, sdk.Coins{{"foocoin", 100}})},
+	}
+	gapp.BeginBlock(abci.RequestBeginBlock{})
+	res := gapp.Deliver(genTxMsgs([]sdk.Msg{sendMsg1, overspend}, []int64{0, 0}, priv1, priv2))
+	require.NotEqual(t, sdk.ABCICodeOK, res.Code, res.Log)
+	CheckBalance(t, gapp, addr1, "42foocoin")
+	CheckBalance(t, gapp, addr2, "42foocoin")
+
+	// each signer signs once, even if it signs many msgs
+	res = gapp.Deliver(genTxMsgs([]sdk.Msg{sendMsg1, sendMsg4, sendMsg2}, []int64{1, 1}, priv1, priv2))
+	require.Equal(t, sdk.ABCICodeOK, res.Code, res.Log)
+	CheckBalance(t, gapp, addr1, "32foocoin")
+	CheckBalance(t, gapp, addr2, "47foocoin")
+	CheckBalance(t, gapp, addr3, "5foocoin")
+
+	logs, err := sdk.ParseMsgLogs(res.Log)
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(logs))
+	gapp.EndBlock(abci.RequestEndBlock{})
+}
+
 func TestIBCMsgs(t *testing.T) {
 	gapp := newGaiaApp()
 
@@ -364,6 +406,9 @@ func TestIBCMsgs(t *testing.T) {
 	SignCheckDeliver(t, gapp, receiveMsg, []int64{2}, true, priv1)
 	CheckBalance(t, gapp, addr1, "10foocoin")
 	SignCheckDeliver(t, gapp, receiveMsg, []int64{3}, false, priv1)
+
+	ctxDeliver := gapp.BaseApp.NewContext(false, abci.Header{})
+	assert.Nil(t, bank.SupplyInvariant(ctxDeliver, gapp.accountMapper, gapp.supplyKeeper))
 }
 
 func TestStakeMsgs(t *testing.T) {
@@ -396,8 +441,9 @@ func TestStakeMsgs(t *testing.T) {
 	// Declare Candidacy
 
 	description := stake.NewDescription("foo_moniker", "", "", "")
+	commission := stake.NewCommission(sdk.NewRat(1, 10), sdk.NewRat(2, 10), sdk.NewRat(1, 100))
 	declareCandidacyMsg := stake.NewMsgDeclareCandidacy(
-		addr1, priv1.PubKey(), bondCoin, description,
+		addr1, priv1.PubKey(), bondCoin, description, commission,
 	)
 	SignCheckDeliver(t, gapp, declareCandidacyMsg, []int64{0}, true, priv1)
 
@@ -407,12 +453,13 @@ func TestStakeMsgs(t *testing.T) {
 	candidate, found := gapp.stakeKeeper.GetCandidate(ctxDeliver, addr1)
 	require.True(t, found)
 	require.Equal(t, candidate.Address, addr1)
+	require.True(t, candidate.Commission.Rate.Equal(sdk.NewRat(1, 10)))
 
 	// Edit Candidacy
 
 	description = stake.NewDescription("bar_moniker", "", "", "")
 	editCandidacyMsg := stake.NewMsgEditCandidacy(
-		addr1, description,
+		addr1, description, nil,
 	)
 	SignDeliver(t, gapp, editCandidacyMsg, []int64{1}, true, priv1)
 
@@ -443,9 +490,25 @@ func TestStakeMsgs(t *testing.T) {
 
 	ctxDeliver = gapp.BaseApp.NewContext(false, abci.Header{})
 	res2 = gapp.accountMapper.GetAccount(ctxDeliver, addr2)
-	require.Equal(t, genCoins, res2.GetCoins())
+	require.Equal(t, genCoins.Minus(sdk.Coins{bondCoin}), res2.GetCoins())
 	_, found = gapp.stakeKeeper.GetDelegatorBond(ctxDeliver, addr2, addr1)
 	require.False(t, found)
+	_, found = gapp.stakeKeeper.GetUnbondingDelegation(ctxDeliver, addr2, addr1)
+	require.True(t, found)
+
+	// the unbonded coins are returned at the end of the first block after the unbonding period.
+	// commit the previous blocks, so that the next one is delivered with its own header
+	unbondingTime := gapp.stakeKeeper.GetParams(ctxDeliver).UnbondingTime
+	gapp.Commit()
+	gapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Time: unbondingTime}})
+	gapp.EndBlock(abci.RequestEndBlock{})
+
+	ctxDeliver = gapp.BaseApp.NewContext(false, abci.Header{})
+	res2 = gapp.accountMapper.GetAccount(ctxDeliver, addr2)
+	require.Equal(t, genCoins, res2.GetCoins())
+	_, found = gapp.stakeKeeper.GetUnbondingDelegation(ctxDeliver, addr2, addr1)
+	require.False(t, found)
+	require.Nil(t, bank.SupplyInvariant(ctxDeliver, gapp.accountMapper, gapp.supplyKeeper))
 }
 
 //____________________________________________________________________________________
@@ -457,16 +520,20 @@ func CheckBalance(t *testing.T, gapp *GaiaApp, addr sdk.Address, balExpected str
 }
 
 func genTx(msg sdk.Msg, seq []int64, priv ...crypto.PrivKeyEd25519) sdk.StdTx {
+	return genTxMsgs([]sdk.Msg{msg}, seq, priv...)
+}
+
+func genTxMsgs(msgs []sdk.Msg, seq []int64, priv ...crypto.PrivKeyEd25519) sdk.StdTx {
 	sigs := make([]sdk.StdSignature, len(priv))
 	for i, p := range priv {
 		sigs[i] = sdk.StdSignature{
 			PubKey:    p.PubKey(),
-			Signature: p.Sign(sdk.StdSignBytes(chainID, seq, fee, msg)),
+			Signature: p.Sign(sdk.StdSignBytes(chainID, seq, fee, msgs)),
 			Sequence:  seq[i],
 		}
 	}
 
-	return sdk.NewStdTx(msg, fee, sigs)
+	return sdk.NewStdTx(msgs, fee, sigs)
 
 }
 
@@ -9,23 +9,42 @@ import (
 	crypto "github.com/tendermint/go-crypto"
 	tmtypes "github.com/tendermint/tendermint/types"
 
-	"github.com/cosmos/cosmos-sdk/server"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/stake"
+	"inschain-tendermint/server"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/stake"
+
+	"inschain-tendermint/x/compliance"
+	"inschain-tendermint/x/gov"
+	"inschain-tendermint/x/scheduler"
+	"inschain-tendermint/x/slashing"
 )
 
 // State to Unmarshal
 type GenesisState struct {
-	Accounts  []GenesisAccount   `json:"accounts"`
-	StakeData stake.GenesisState `json:"stake"`
+	Accounts       []GenesisAccount        `json:"accounts"`
+	StakeData      stake.GenesisState      `json:"stake"`
+	SlashingData   slashing.GenesisState   `json:"slashing"`
+	GovData        gov.GenesisState        `json:"gov"`
+	TokenData      bank.TokenGenesisState  `json:"token"`
+	SupplyData     bank.SupplyGenesisState `json:"supply"`
+	HTLCData       bank.HTLCGenesisState   `json:"htlc"`
+	SchedulerData  scheduler.GenesisState  `json:"scheduler"`
+	ComplianceData compliance.GenesisState `json:"compliance"`
 }
 
-// GenesisAccount doesn't need pubkey or sequence
+// GenesisAccount doesn't need pubkey or sequence.
+// With an end time, the original vesting coins out of its coins vest
+// linearly from the start to the end time, or at the end time if the
+// start time is zero.
 type GenesisAccount struct {
-	Address sdk.Address `json:"address"`
-	Coins   sdk.Coins   `json:"coins"`
+	Address         sdk.Address `json:"address"`
+	Coins           sdk.Coins   `json:"coins"`
+	OriginalVesting sdk.Coins   `json:"original_vesting,omitempty"`
+	StartTime       int64       `json:"start_time,omitempty"`
+	EndTime         int64       `json:"end_time,omitempty"`
 }
 
 func NewGenesisAccount(acc *auth.BaseAccount) GenesisAccount {
@@ -36,10 +55,18 @@ func NewGenesisAccount(acc *auth.BaseAccount) GenesisAccount {
 }
 
 func NewGenesisAccountI(acc sdk.Account) GenesisAccount {
-	return GenesisAccount{
+	gacc := GenesisAccount{
 		Address: acc.GetAddress(),
 		Coins:   acc.GetCoins(),
 	}
+	if vacc, ok := acc.(auth.VestingAccount); ok {
+		gacc.OriginalVesting = vacc.GetOriginalVesting()
+		gacc.EndTime = vacc.GetEndTime()
+		if cva, ok := acc.(*auth.ContinuousVestingAccount); ok {
+			gacc.StartTime = cva.StartTime
+		}
+	}
+	return gacc
 }
 
 // convert GenesisAccount to auth.BaseAccount
@@ -50,6 +77,19 @@ func (ga *GenesisAccount) ToAccount() (acc *auth.BaseAccount) {
 	}
 }
 
+// convert GenesisAccount to a vesting account if it has an end time,
+// to an auth.BaseAccount otherwise
+func (ga *GenesisAccount) ToAccountI() sdk.Account {
+	acc := ga.ToAccount()
+	if ga.EndTime == 0 {
+		return acc
+	}
+	if ga.StartTime == 0 {
+		return auth.NewDelayedVestingAccount(*acc, ga.OriginalVesting, ga.EndTime)
+	}
+	return auth.NewContinuousVestingAccount(*acc, ga.OriginalVesting, ga.StartTime, ga.EndTime)
+}
+
 var (
 	flagName       = "name"
 	flagClientHome = "home-client"
@@ -154,7 +194,6 @@ func GaiaAppGenState(cdc *wire.Codec, appGenTxs []json.RawMessage) (appState jso
 		}
 		acc := NewGenesisAccount(&accAuth)
 		genaccs[i] = acc
-		stakeData.Pool.TotalSupply += freeFermionsAcc // increase the supply
 
 		// add the validator
 		if len(genTx.Name) > 0 {
@@ -164,7 +203,6 @@ func GaiaAppGenState(cdc *wire.Codec, appGenTxs []json.RawMessage) (appState jso
 			stakeData.Candidates = append(stakeData.Candidates, candidate)
 
 			// pool logic
-			stakeData.Pool.TotalSupply += freeFermionVal
 			stakeData.Pool.BondedPool += freeFermionVal
 			stakeData.Pool.BondedShares = sdk.NewRat(stakeData.Pool.BondedPool)
 		}
@@ -172,9 +210,34 @@ func GaiaAppGenState(cdc *wire.Codec, appGenTxs []json.RawMessage) (appState jso
 
 	// create the final app state
 	genesisState := GenesisState{
-		Accounts:  genaccs,
-		StakeData: stakeData,
+		Accounts:       genaccs,
+		StakeData:      stakeData,
+		SlashingData:   slashing.DefaultGenesisState(),
+		GovData:        gov.DefaultGenesisState(),
+		SchedulerData:  scheduler.DefaultGenesisState(),
+		ComplianceData: compliance.DefaultGenesisState(),
 	}
+	genesisState.SupplyData = genesisSupply(genesisState)
 	appState, err = wire.MarshalJSONIndent(cdc, genesisState)
 	return
 }
+
+// the supply of the coins of the genesis accounts and of the coins held by
+// gov, htlc and stake, for a genesis without one
+func genesisSupply(genesisState GenesisState) bank.SupplyGenesisState {
+	var accountCoins sdk.Coins
+	for _, gacc := range genesisState.Accounts {
+		accountCoins = accountCoins.Plus(gacc.Coins.Sort())
+	}
+	var escrows []bank.Escrow
+	if escrow := gov.GenesisEscrow(genesisState.GovData); !escrow.IsZero() {
+		escrows = append(escrows, bank.Escrow{gov.MsgType, escrow})
+	}
+	if escrow := bank.GenesisHTLCEscrow(genesisState.HTLCData); !escrow.IsZero() {
+		escrows = append(escrows, bank.Escrow{bank.HTLCMsgType, escrow})
+	}
+	if escrow := stake.GenesisEscrow(genesisState.StakeData); !escrow.IsZero() {
+		escrows = append(escrows, bank.Escrow{stake.MsgType, escrow})
+	}
+	return bank.NewSupplyGenesisState(accountCoins, escrows)
+}
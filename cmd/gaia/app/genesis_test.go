@@ -3,10 +3,10 @@ package app
 import (
 	"testing"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/stretchr/testify/assert"
 	crypto "github.com/tendermint/go-crypto"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/x/auth"
 )
 
 func TestToAccount(t *testing.T) {
@@ -17,6 +17,22 @@ func TestToAccount(t *testing.T) {
 	assert.Equal(t, authAcc, *genAcc.ToAccount())
 }
 
+func TestToVestingAccount(t *testing.T) {
+	priv := crypto.GenPrivKeyEd25519()
+	addr := sdk.Address(priv.PubKey().Address())
+	authAcc := auth.NewBaseAccountWithAddress(addr)
+	authAcc.Coins = sdk.Coins{{"steak", 100}}
+
+	vacc := auth.NewContinuousVestingAccount(authAcc, sdk.Coins{{"steak", 50}}, 100, 200)
+	genAcc := NewGenesisAccountI(vacc)
+	assert.Equal(t, int64(100), genAcc.StartTime)
+	assert.Equal(t, vacc, genAcc.ToAccountI())
+
+	dacc := auth.NewDelayedVestingAccount(authAcc, sdk.Coins{{"steak", 50}}, 200)
+	genAcc = NewGenesisAccountI(dacc)
+	assert.Equal(t, dacc, genAcc.ToAccountI())
+}
+
 func TestGaiaAppGenTx(t *testing.T) {
 	cdc := MakeCodec()
 	_ = cdc
@@ -9,13 +9,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/cosmos/cosmos-sdk/client/keys"
-	"github.com/cosmos/cosmos-sdk/cmd/gaia/app"
-	"github.com/cosmos/cosmos-sdk/server"
-	"github.com/cosmos/cosmos-sdk/tests"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/stake"
+	"inschain-tendermint/client/keys"
+	"inschain-tendermint/cmd/gaia/app"
+	"inschain-tendermint/server"
+	"inschain-tendermint/tests"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/stake"
 )
 
 func TestGaiaCLISend(t *testing.T) {
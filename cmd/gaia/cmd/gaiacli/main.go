@@ -5,20 +5,25 @@ import (
 
 	"github.com/tendermint/tmlibs/cli"
 
-	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/keys"
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/keys"
 	//	comment out default lcd , to import updated lcd bellow
 	//	"github.com/cosmos/cosmos-sdk/client/lcd"
-	"github.com/cosmos/cosmos-sdk/client/rpc"
-	"github.com/cosmos/cosmos-sdk/client/tx"
-	"github.com/cosmos/cosmos-sdk/version"
-	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
-	bankcmd "github.com/cosmos/cosmos-sdk/x/bank/client/cli"
-	ibccmd "github.com/cosmos/cosmos-sdk/x/ibc/client/cli"
-	stakecmd "github.com/cosmos/cosmos-sdk/x/stake/client/cli"
+	"inschain-tendermint/client/rpc"
+	"inschain-tendermint/client/tx"
+	"inschain-tendermint/version"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+	bankcmd "inschain-tendermint/x/bank/client/cli"
+	ibccmd "inschain-tendermint/x/ibc/client/cli"
+	stakecmd "inschain-tendermint/x/stake/client/cli"
 
 	//	mutual packages 
 	mutualcmd "inschain-tendermint/x/mutual/client/cli"
+	feegrantcmd "inschain-tendermint/x/feegrant/client/cli"
+	govcmd "inschain-tendermint/x/gov/client/cli"
+	slashingcmd "inschain-tendermint/x/slashing/client/cli"
+	schedulercmd "inschain-tendermint/x/scheduler/client/cli"
+	compliancecmd "inschain-tendermint/x/compliance/client/cli"
 	"inschain-tendermint/client/lcd"
 	// updated app
 	"inschain-tendermint/cmd/gaia/app"
@@ -51,18 +56,49 @@ func main() {
 	// add mutual commands
 	mutualcmd.AddCommands(rootCmd, cdc)
 	rootCmd.AddCommand(client.LineBreak)
+	// add fee grant commands
+	feegrantcmd.AddCommands(rootCmd, cdc)
+	rootCmd.AddCommand(client.LineBreak)
+	// add slashing commands
+	slashingcmd.AddCommands(rootCmd, cdc)
+	rootCmd.AddCommand(client.LineBreak)
+	// add governance commands
+	govcmd.AddCommands(rootCmd, cdc)
+	rootCmd.AddCommand(client.LineBreak)
+	// add scheduler commands
+	schedulercmd.AddCommands(rootCmd, cdc)
+	rootCmd.AddCommand(client.LineBreak)
+	// add compliance commands
+	compliancecmd.AddCommands(rootCmd, cdc)
+	rootCmd.AddCommand(client.LineBreak)
+	// add token commands
+	bankcmd.AddTokenCommands(rootCmd, cdc)
+	bankcmd.AddHTLCCommands(rootCmd, cdc)
+	rootCmd.AddCommand(client.LineBreak)
 
 	// add query/post commands (custom to binary)
 	rootCmd.AddCommand(
 		client.GetCommands(
 			authcmd.GetAccountCmd("acc", cdc, authcmd.GetAccountDecoder(cdc)),
+			bankcmd.GetSupplyCmd("supply", cdc),
+			bankcmd.GetTotalSupplyCmd("supply", cdc),
+			bankcmd.GetEscrowsCmd("supply", cdc),
 			stakecmd.GetCmdQueryCandidate("stake", cdc),
-			//stakecmd.GetCmdQueryCandidates("stake", cdc),
 			stakecmd.GetCmdQueryDelegatorBond("stake", cdc),
+			stakecmd.GetCmdQueryDelegatorRewards("stake", cdc),
+			stakecmd.GetCmdQueryUnbondingDelegations("stake", cdc),
+			stakecmd.GetCmdQueryRedelegations("stake", cdc),
 			//stakecmd.GetCmdQueryDelegatorBonds("stake", cdc),
 		)...)
+	rootCmd.AddCommand(
+		client.GetCommands(
+			client.PageCommands(
+				stakecmd.GetCmdQueryCandidates("stake", cdc),
+			)...,
+		)...)
 	rootCmd.AddCommand(
 		client.PostCommands(
+			tx.BatchTxCmd(cdc, authcmd.GetAccountDecoder(cdc)),
 			bankcmd.SendTxCmd(cdc),
 			ibccmd.IBCTransferCmd(cdc),
 			ibccmd.IBCRelayCmd(cdc),
@@ -70,6 +106,8 @@ func main() {
 			stakecmd.GetCmdEditCandidacy(cdc),
 			stakecmd.GetCmdDelegate(cdc),
 			stakecmd.GetCmdUnbond(cdc),
+			stakecmd.GetCmdRedelegate(cdc),
+			stakecmd.GetCmdWithdraw(cdc),
 		)...)
 
 	// add proxy, version and key info
@@ -14,7 +14,7 @@ import (
 	// "github.com/cosmos/cosmos-sdk/cmd/gaia/app"
 	// import updated app
 	"inschain-tendermint/cmd/gaia/app"
-	"github.com/cosmos/cosmos-sdk/server"
+	"inschain-tendermint/server"
 )
 
 func main() {
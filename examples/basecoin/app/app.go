@@ -8,15 +8,15 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	bam "github.com/cosmos/cosmos-sdk/baseapp"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/bank"
-	"github.com/cosmos/cosmos-sdk/x/ibc"
-	"github.com/cosmos/cosmos-sdk/x/stake"
-
-	"github.com/cosmos/cosmos-sdk/examples/basecoin/types"
+	bam "inschain-tendermint/baseapp"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/ibc"
+	"inschain-tendermint/x/stake"
+
+	"inschain-tendermint/examples/basecoin/types"
 )
 
 const (
@@ -33,10 +33,12 @@ type BasecoinApp struct {
 	keyAccount *sdk.KVStoreKey
 	keyIBC     *sdk.KVStoreKey
 	keyStake   *sdk.KVStoreKey
+	keySupply  *sdk.KVStoreKey
 
 	// Manage getting and setting accounts
 	accountMapper sdk.AccountMapper
 	coinKeeper    bank.Keeper
+	supplyKeeper  bank.SupplyKeeper
 	ibcMapper     ibc.Mapper
 	stakeKeeper   stake.Keeper
 }
@@ -54,6 +56,7 @@ func NewBasecoinApp(logger log.Logger, db dbm.DB) *BasecoinApp {
 		keyAccount: sdk.NewKVStoreKey("acc"),
 		keyIBC:     sdk.NewKVStoreKey("ibc"),
 		keyStake:   sdk.NewKVStoreKey("stake"),
+		keySupply:  sdk.NewKVStoreKey("supply"),
 	}
 
 	// Define the accountMapper.
@@ -65,18 +68,19 @@ func NewBasecoinApp(logger log.Logger, db dbm.DB) *BasecoinApp {
 
 	// add accountMapper/handlers
 	app.coinKeeper = bank.NewKeeper(app.accountMapper)
+	app.supplyKeeper = bank.NewSupplyKeeper(app.cdc, app.keySupply, app.coinKeeper, app.RegisterCodespace(bank.DefaultCodespace))
 	app.ibcMapper = ibc.NewMapper(app.cdc, app.keyIBC, app.RegisterCodespace(ibc.DefaultCodespace))
-	app.stakeKeeper = stake.NewKeeper(app.cdc, app.keyStake, app.coinKeeper, app.RegisterCodespace(stake.DefaultCodespace))
+	app.stakeKeeper = stake.NewKeeper(app.cdc, app.keyStake, app.supplyKeeper, app.RegisterCodespace(stake.DefaultCodespace))
 
 	// register message routes
 	app.Router().
 		AddRoute("bank", bank.NewHandler(app.coinKeeper)).
-		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.coinKeeper)).
+		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.supplyKeeper)).
 		AddRoute("stake", stake.NewHandler(app.stakeKeeper))
 
 	// Initialize BaseApp.
 	app.SetInitChainer(app.initChainer)
-	app.MountStoresIAVL(app.keyMain, app.keyAccount, app.keyIBC, app.keyStake)
+	app.MountStoresIAVL(app.keyMain, app.keyAccount, app.keyIBC, app.keyStake, app.keySupply)
 	app.SetAnteHandler(auth.NewAnteHandler(app.accountMapper, auth.BurnFeeHandler))
 	err := app.LoadLatestVersion(app.keyMain)
 	if err != nil {
@@ -111,6 +115,7 @@ func (app *BasecoinApp) initChainer(ctx sdk.Context, req abci.RequestInitChain)
 		// return sdk.ErrGenesisParse("").TraceCause(err, "")
 	}
 
+	var accountCoins sdk.Coins
 	for _, gacc := range genesisState.Accounts {
 		acc, err := gacc.ToAppAccount()
 		if err != nil {
@@ -118,7 +123,11 @@ func (app *BasecoinApp) initChainer(ctx sdk.Context, req abci.RequestInitChain)
 			//	return sdk.ErrGenesisParse("").TraceCause(err, "")
 		}
 		app.accountMapper.SetAccount(ctx, acc)
+		accountCoins = accountCoins.Plus(acc.GetCoins())
 	}
+
+	// the supply is the coins of the genesis accounts
+	bank.InitSupplyGenesis(ctx, app.supplyKeeper, bank.NewSupplyGenesisState(accountCoins, nil))
 	return abci.ResponseInitChain{}
 }
 
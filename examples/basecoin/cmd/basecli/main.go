@@ -7,20 +7,20 @@ import (
 
 	"github.com/tendermint/tmlibs/cli"
 
-	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/keys"
-	"github.com/cosmos/cosmos-sdk/client/lcd"
-	"github.com/cosmos/cosmos-sdk/client/rpc"
-	"github.com/cosmos/cosmos-sdk/client/tx"
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/keys"
+	"inschain-tendermint/client/lcd"
+	"inschain-tendermint/client/rpc"
+	"inschain-tendermint/client/tx"
 
-	"github.com/cosmos/cosmos-sdk/version"
-	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
-	bankcmd "github.com/cosmos/cosmos-sdk/x/bank/client/cli"
-	ibccmd "github.com/cosmos/cosmos-sdk/x/ibc/client/cli"
-	stakecmd "github.com/cosmos/cosmos-sdk/x/stake/client/cli"
+	"inschain-tendermint/version"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+	bankcmd "inschain-tendermint/x/bank/client/cli"
+	ibccmd "inschain-tendermint/x/ibc/client/cli"
+	stakecmd "inschain-tendermint/x/stake/client/cli"
 
-	"github.com/cosmos/cosmos-sdk/examples/basecoin/app"
-	"github.com/cosmos/cosmos-sdk/examples/basecoin/types"
+	"inschain-tendermint/examples/basecoin/app"
+	"inschain-tendermint/examples/basecoin/types"
 )
 
 // rootCmd is the entry point for this binary
@@ -11,8 +11,8 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	"github.com/cosmos/cosmos-sdk/examples/basecoin/app"
-	"github.com/cosmos/cosmos-sdk/server"
+	"inschain-tendermint/examples/basecoin/app"
+	"inschain-tendermint/server"
 )
 
 func main() {
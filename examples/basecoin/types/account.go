@@ -1,9 +1,9 @@
 package types
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
 )
 
 var _ sdk.Account = (*AppAccount)(nil)
@@ -8,18 +8,18 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	bam "github.com/cosmos/cosmos-sdk/baseapp"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/bank"
-	"github.com/cosmos/cosmos-sdk/x/ibc"
-
-	"github.com/cosmos/cosmos-sdk/examples/democoin/types"
-	"github.com/cosmos/cosmos-sdk/examples/democoin/x/cool"
-	"github.com/cosmos/cosmos-sdk/examples/democoin/x/pow"
-	"github.com/cosmos/cosmos-sdk/examples/democoin/x/simplestake"
-	"github.com/cosmos/cosmos-sdk/examples/democoin/x/sketchy"
+	bam "inschain-tendermint/baseapp"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/ibc"
+
+	"inschain-tendermint/examples/democoin/types"
+	"inschain-tendermint/examples/democoin/x/cool"
+	"inschain-tendermint/examples/democoin/x/pow"
+	"inschain-tendermint/examples/democoin/x/simplestake"
+	"inschain-tendermint/examples/democoin/x/sketchy"
 )
 
 const (
@@ -37,13 +37,15 @@ type DemocoinApp struct {
 	capKeyPowStore     *sdk.KVStoreKey
 	capKeyIBCStore     *sdk.KVStoreKey
 	capKeyStakingStore *sdk.KVStoreKey
+	capKeySupplyStore  *sdk.KVStoreKey
 
 	// keepers
-	coinKeeper  bank.Keeper
-	coolKeeper  cool.Keeper
-	powKeeper   pow.Keeper
-	ibcMapper   ibc.Mapper
-	stakeKeeper simplestake.Keeper
+	coinKeeper   bank.Keeper
+	supplyKeeper bank.SupplyKeeper
+	coolKeeper   cool.Keeper
+	powKeeper    pow.Keeper
+	ibcMapper    ibc.Mapper
+	stakeKeeper  simplestake.Keeper
 
 	// Manage getting and setting accounts
 	accountMapper sdk.AccountMapper
@@ -63,6 +65,7 @@ func NewDemocoinApp(logger log.Logger, db dbm.DB) *DemocoinApp {
 		capKeyPowStore:     sdk.NewKVStoreKey("pow"),
 		capKeyIBCStore:     sdk.NewKVStoreKey("ibc"),
 		capKeyStakingStore: sdk.NewKVStoreKey("stake"),
+		capKeySupplyStore:  sdk.NewKVStoreKey("supply"),
 	}
 
 	// Define the accountMapper.
@@ -74,6 +77,7 @@ func NewDemocoinApp(logger log.Logger, db dbm.DB) *DemocoinApp {
 
 	// Add handlers.
 	app.coinKeeper = bank.NewKeeper(app.accountMapper)
+	app.supplyKeeper = bank.NewSupplyKeeper(app.cdc, app.capKeySupplyStore, app.coinKeeper, app.RegisterCodespace(bank.DefaultCodespace))
 	app.coolKeeper = cool.NewKeeper(app.capKeyMainStore, app.coinKeeper, app.RegisterCodespace(cool.DefaultCodespace))
 	app.powKeeper = pow.NewKeeper(app.capKeyPowStore, pow.NewConfig("pow", int64(1)), app.coinKeeper, app.RegisterCodespace(pow.DefaultCodespace))
 	app.ibcMapper = ibc.NewMapper(app.cdc, app.capKeyIBCStore, app.RegisterCodespace(ibc.DefaultCodespace))
@@ -83,12 +87,12 @@ func NewDemocoinApp(logger log.Logger, db dbm.DB) *DemocoinApp {
 		AddRoute("cool", cool.NewHandler(app.coolKeeper)).
 		AddRoute("pow", app.powKeeper.Handler).
 		AddRoute("sketchy", sketchy.NewHandler()).
-		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.coinKeeper)).
+		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.supplyKeeper)).
 		AddRoute("simplestake", simplestake.NewHandler(app.stakeKeeper))
 
 	// Initialize BaseApp.
 	app.SetInitChainer(app.initChainerFn(app.coolKeeper, app.powKeeper))
-	app.MountStoresIAVL(app.capKeyMainStore, app.capKeyAccountStore, app.capKeyPowStore, app.capKeyIBCStore, app.capKeyStakingStore)
+	app.MountStoresIAVL(app.capKeyMainStore, app.capKeyAccountStore, app.capKeyPowStore, app.capKeyIBCStore, app.capKeyStakingStore, app.capKeySupplyStore)
 	app.SetAnteHandler(auth.NewAnteHandler(app.accountMapper, auth.BurnFeeHandler))
 	err := app.LoadLatestVersion(app.capKeyMainStore)
 	if err != nil {
@@ -126,6 +130,7 @@ func (app *DemocoinApp) initChainerFn(coolKeeper cool.Keeper, powKeeper pow.Keep
 			// return sdk.ErrGenesisParse("").TraceCause(err, "")
 		}
 
+		var accountCoins sdk.Coins
 		for _, gacc := range genesisState.Accounts {
 			acc, err := gacc.ToAppAccount()
 			if err != nil {
@@ -133,7 +138,9 @@ func (app *DemocoinApp) initChainerFn(coolKeeper cool.Keeper, powKeeper pow.Keep
 				//	return sdk.ErrGenesisParse("").TraceCause(err, "")
 			}
 			app.accountMapper.SetAccount(ctx, acc)
+			accountCoins = accountCoins.Plus(acc.GetCoins())
 		}
+		bank.InitSupplyGenesis(ctx, app.supplyKeeper, bank.NewSupplyGenesisState(accountCoins, nil))
 
 		// Application specific genesis handling
 		err = cool.InitGenesis(ctx, app.coolKeeper, genesisState.CoolGenesis)
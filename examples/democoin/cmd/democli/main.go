@@ -7,22 +7,22 @@ import (
 
 	"github.com/tendermint/tmlibs/cli"
 
-	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/keys"
-	"github.com/cosmos/cosmos-sdk/client/lcd"
-	"github.com/cosmos/cosmos-sdk/client/rpc"
-	"github.com/cosmos/cosmos-sdk/client/tx"
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/keys"
+	"inschain-tendermint/client/lcd"
+	"inschain-tendermint/client/rpc"
+	"inschain-tendermint/client/tx"
 
-	"github.com/cosmos/cosmos-sdk/version"
-	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
-	bankcmd "github.com/cosmos/cosmos-sdk/x/bank/client/cli"
-	ibccmd "github.com/cosmos/cosmos-sdk/x/ibc/client/cli"
+	"inschain-tendermint/version"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+	bankcmd "inschain-tendermint/x/bank/client/cli"
+	ibccmd "inschain-tendermint/x/ibc/client/cli"
 
-	"github.com/cosmos/cosmos-sdk/examples/democoin/app"
-	"github.com/cosmos/cosmos-sdk/examples/democoin/types"
-	coolcmd "github.com/cosmos/cosmos-sdk/examples/democoin/x/cool/client/cli"
-	powcmd "github.com/cosmos/cosmos-sdk/examples/democoin/x/pow/client/cli"
-	simplestakingcmd "github.com/cosmos/cosmos-sdk/examples/democoin/x/simplestake/client/cli"
+	"inschain-tendermint/examples/democoin/app"
+	"inschain-tendermint/examples/democoin/types"
+	coolcmd "inschain-tendermint/examples/democoin/x/cool/client/cli"
+	powcmd "inschain-tendermint/examples/democoin/x/pow/client/cli"
+	simplestakingcmd "inschain-tendermint/examples/democoin/x/simplestake/client/cli"
 )
 
 // rootCmd is the entry point for this binary
@@ -11,9 +11,9 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	"github.com/cosmos/cosmos-sdk/examples/democoin/app"
-	"github.com/cosmos/cosmos-sdk/server"
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/examples/democoin/app"
+	"inschain-tendermint/server"
+	"inschain-tendermint/wire"
 )
 
 // init parameters
@@ -3,7 +3,7 @@ package cool
 import (
 	"fmt"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // Cool errors reserve 400 ~ 499.
@@ -8,11 +8,11 @@ import (
 	abci "github.com/tendermint/abci/types"
 	dbm "github.com/tendermint/tmlibs/db"
 
-	"github.com/cosmos/cosmos-sdk/store"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	auth "github.com/cosmos/cosmos-sdk/x/auth"
-	bank "github.com/cosmos/cosmos-sdk/x/bank"
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	auth "inschain-tendermint/x/auth"
+	bank "inschain-tendermint/x/bank"
 )
 
 func setupMultiStore() (sdk.MultiStore, *sdk.KVStoreKey) {
@@ -1,7 +1,7 @@
 package pow
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // TODO remove, seems hacky
@@ -8,10 +8,10 @@ import (
 	abci "github.com/tendermint/abci/types"
 	"github.com/tendermint/tmlibs/log"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	wire "github.com/cosmos/cosmos-sdk/wire"
-	auth "github.com/cosmos/cosmos-sdk/x/auth"
-	bank "github.com/cosmos/cosmos-sdk/x/bank"
+	sdk "inschain-tendermint/types"
+	wire "inschain-tendermint/wire"
+	auth "inschain-tendermint/x/auth"
+	bank "inschain-tendermint/x/bank"
 )
 
 func TestPowHandler(t *testing.T) {
@@ -5,8 +5,8 @@ import (
 	"math"
 	"strconv"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	crypto "github.com/tendermint/go-crypto"
+	sdk "inschain-tendermint/types"
 )
 
 // generate the mine message
@@ -10,7 +10,7 @@ import (
 
 	crypto "github.com/tendermint/go-crypto"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // MsgMine - mine some coins with PoW
@@ -9,12 +9,12 @@ import (
 
 	crypto "github.com/tendermint/go-crypto"
 
-	"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	authcmd "inschain-tendermint/x/auth/client/cli"
 
-	"github.com/cosmos/cosmos-sdk/examples/democoin/x/simplestake"
+	"inschain-tendermint/examples/democoin/x/simplestake"
 )
 
 const (
@@ -87,7 +87,10 @@ func UnbondTxCmd(cdc *wire.Codec) *cobra.Command {
 
 func sendMsg(cdc *wire.Codec, msg sdk.Msg) error {
 	ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
-	res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+	if ctx.GenerateOnly {
+		return ctx.PrintUnsignedTx([]sdk.Msg{msg}, cdc)
+	}
+	res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
 	if err != nil {
 		return err
 	}
@@ -1,7 +1,7 @@
 package simplestake
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // simple stake errors reserve 300 ~ 399.
@@ -3,7 +3,7 @@ package simplestake
 import (
 	abci "github.com/tendermint/abci/types"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // NewHandler returns a handler for "simplestake" type messages.
@@ -13,8 +13,8 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	bam "github.com/cosmos/cosmos-sdk/baseapp"
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	bam "inschain-tendermint/baseapp"
+	sdk "inschain-tendermint/types"
 )
 
 func main() {
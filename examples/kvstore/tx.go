@@ -3,7 +3,7 @@ package main
 import (
 	"bytes"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // An sdk.Tx which is its own sdk.Msg.
@@ -17,8 +17,8 @@ func (tx kvstoreTx) Type() string {
 	return "kvstore"
 }
 
-func (tx kvstoreTx) GetMsg() sdk.Msg {
-	return tx
+func (tx kvstoreTx) GetMsgs() []sdk.Msg {
+	return []sdk.Msg{tx}
 }
 
 func (tx kvstoreTx) GetSignBytes() []byte {
@@ -11,13 +11,18 @@ import (
 
 	//bam "github.com/cosmos/cosmos-sdk/baseapp"
 	bam "inschain-tendermint/baseapp"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/bank"
-	"github.com/cosmos/cosmos-sdk/x/ibc"
-	"github.com/cosmos/cosmos-sdk/x/stake"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/ibc"
+	"inschain-tendermint/x/stake"
+	"inschain-tendermint/x/compliance"
+	"inschain-tendermint/x/feegrant"
+	"inschain-tendermint/x/gov"
 	"inschain-tendermint/x/mutual"
+	"inschain-tendermint/x/scheduler"
+	"inschain-tendermint/x/slashing"
 
 	"inschain-tendermint/examples/mutual/types"
 )
@@ -32,18 +37,34 @@ type MutualApp struct {
 	cdc *wire.Codec
 
 	// keys to access the substores
-	capKeyMainStore    *sdk.KVStoreKey
-	capKeyAccountStore *sdk.KVStoreKey
-	capKeyIBCStore     *sdk.KVStoreKey
-	capKeyStakingStore *sdk.KVStoreKey
-	capKeyMutualStore  *sdk.KVStoreKey
+	capKeyMainStore       *sdk.KVStoreKey
+	capKeyAccountStore    *sdk.KVStoreKey
+	capKeyIBCStore        *sdk.KVStoreKey
+	capKeyStakingStore    *sdk.KVStoreKey
+	capKeyMutualStore     *sdk.KVStoreKey
+	capKeyFeeGrantStore   *sdk.KVStoreKey
+	capKeySlashingStore   *sdk.KVStoreKey
+	capKeyGovStore        *sdk.KVStoreKey
+	capKeyTokenStore      *sdk.KVStoreKey
+	capKeySupplyStore     *sdk.KVStoreKey
+	capKeyHTLCStore       *sdk.KVStoreKey
+	capKeySchedulerStore  *sdk.KVStoreKey
+	capKeyComplianceStore *sdk.KVStoreKey
 
 	// keepers
 	accountMapper 	sdk.AccountMapper
 	coinKeeper    	bank.Keeper
+	supplyKeeper	bank.SupplyKeeper
+	tokenKeeper	bank.TokenKeeper
+	htlcKeeper	bank.HTLCKeeper
 	ibcMapper     	ibc.Mapper
 	stakeKeeper   	stake.Keeper
 	mutualKeeper	mutual.Keeper
+	feeGrantKeeper	feegrant.Keeper
+	slashingKeeper	slashing.Keeper
+	govKeeper	gov.Keeper
+	schedulerKeeper	scheduler.Keeper
+	complianceKeeper	compliance.Keeper
 }
 
 func NewMutualApp(logger log.Logger, db dbm.DB) *MutualApp {
@@ -51,12 +72,20 @@ func NewMutualApp(logger log.Logger, db dbm.DB) *MutualApp {
 	var cdc = MakeCodec()
 	// create your application object
 	var app = &MutualApp{
-		BaseApp:            bam.NewBaseApp(appName, cdc, logger, db),
-		cdc:                cdc,
-		capKeyMainStore:    sdk.NewKVStoreKey("main"),
-		capKeyAccountStore: sdk.NewKVStoreKey("acc"),
-		capKeyIBCStore:     sdk.NewKVStoreKey("ibc"),
-		capKeyStakingStore: sdk.NewKVStoreKey("stake"),
+		BaseApp:               bam.NewBaseApp(appName, cdc, logger, db),
+		cdc:                   cdc,
+		capKeyMainStore:       sdk.NewKVStoreKey("main"),
+		capKeyAccountStore:    sdk.NewKVStoreKey("acc"),
+		capKeyIBCStore:        sdk.NewKVStoreKey("ibc"),
+		capKeyStakingStore:    sdk.NewKVStoreKey("stake"),
+		capKeyFeeGrantStore:   sdk.NewKVStoreKey("feegrant"),
+		capKeySlashingStore:   sdk.NewKVStoreKey("slashing"),
+		capKeyGovStore:        sdk.NewKVStoreKey("gov"),
+		capKeyTokenStore:      sdk.NewKVStoreKey("token"),
+		capKeySupplyStore:     sdk.NewKVStoreKey("supply"),
+		capKeyHTLCStore:       sdk.NewKVStoreKey("htlc"),
+		capKeySchedulerStore:  sdk.NewKVStoreKey("scheduler"),
+		capKeyComplianceStore: sdk.NewKVStoreKey("compliance"),
 		//capKeyMutualStore:  sdk.NewKVStoreKey("mutual"),
 	}
 
@@ -67,21 +96,54 @@ func NewMutualApp(logger log.Logger, db dbm.DB) *MutualApp {
 		&types.AppAccount{}, // prototype
 	)
 
-	// add handlers
-	app.coinKeeper = bank.NewKeeper(app.accountMapper)
+	// add handlers, the transfers are checked against the compliance restrictions
+	app.complianceKeeper = compliance.NewKeeper(app.cdc, app.capKeyComplianceStore, app.RegisterCodespace(compliance.DefaultCodespace))
+	app.coinKeeper = bank.NewKeeper(app.accountMapper).WithTransferRestrictions(app.complianceKeeper)
+	app.supplyKeeper = bank.NewSupplyKeeper(app.cdc, app.capKeySupplyStore, app.coinKeeper, app.RegisterCodespace(bank.DefaultCodespace)).WithTransferRestrictions(app.complianceKeeper)
+	app.tokenKeeper = bank.NewTokenKeeper(app.cdc, app.capKeyTokenStore, app.supplyKeeper, app.RegisterCodespace(bank.DefaultCodespace))
+	app.htlcKeeper = bank.NewHTLCKeeper(app.cdc, app.capKeyHTLCStore, app.supplyKeeper, app.RegisterCodespace(bank.DefaultCodespace))
 	app.ibcMapper = ibc.NewMapper(app.cdc, app.capKeyIBCStore, app.RegisterCodespace(ibc.DefaultCodespace))
-	app.stakeKeeper = stake.NewKeeper(app.cdc, app.capKeyStakingStore, app.coinKeeper, app.RegisterCodespace(stake.DefaultCodespace))
-	app.mutualKeeper = mutual.NewKeeper(app.cdc, app.capKeyStakingStore, app.coinKeeper, app.RegisterCodespace(mutual.DefaultCodespace))
+	app.stakeKeeper = stake.NewKeeper(app.cdc, app.capKeyStakingStore, app.supplyKeeper, app.RegisterCodespace(stake.DefaultCodespace))
+	app.mutualKeeper = mutual.NewKeeper(app.cdc, app.capKeyStakingStore, app.supplyKeeper, app.RegisterCodespace(mutual.DefaultCodespace)).WithTransferRestrictions(app.complianceKeeper)
+	app.feeGrantKeeper = feegrant.NewKeeper(app.cdc, app.capKeyFeeGrantStore, app.RegisterCodespace(feegrant.DefaultCodespace))
+	app.slashingKeeper = slashing.NewKeeper(app.cdc, app.capKeySlashingStore, stake.NewViewSlashKeeper(app.stakeKeeper), app.RegisterCodespace(slashing.DefaultCodespace))
+	app.schedulerKeeper = scheduler.NewKeeper(app.cdc, app.capKeySchedulerStore, app.coinKeeper, app.RegisterCodespace(scheduler.DefaultCodespace))
+	app.govKeeper = gov.NewKeeper(app.cdc, app.capKeyGovStore, app.supplyKeeper, stake.NewViewSlashKeeper(app.stakeKeeper), app.RegisterCodespace(gov.DefaultCodespace)).
+		AddParamChangeHandler("stake", app.stakeKeeper.SetParam).
+		AddParamChangeHandler("slashing", app.slashingKeeper.SetParam).
+		AddParamChangeHandler("scheduler", app.schedulerKeeper.SetParam).
+		AddParamChangeHandler("compliance", app.complianceKeeper.SetParam).
+		AddParamChangeHandler("mutual", app.mutualKeeper.SetParam)
 	app.Router().
 		AddRoute("bank", bank.NewHandler(app.coinKeeper)).
-		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.coinKeeper)).
+		AddRoute("token", bank.NewTokenHandler(app.tokenKeeper)).
+		AddRoute("htlc", bank.NewHTLCHandler(app.htlcKeeper)).
+		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.supplyKeeper)).
 		AddRoute("stake", stake.NewHandler(app.stakeKeeper)).
-		AddRoute("mutual", mutual.NewHandler(app.mutualKeeper))
+		AddRoute("mutual", mutual.NewHandler(app.mutualKeeper)).
+		AddRoute("feegrant", feegrant.NewHandler(app.feeGrantKeeper)).
+		AddRoute("slashing", slashing.NewHandler(app.slashingKeeper)).
+		AddRoute("gov", gov.NewHandler(app.govKeeper)).
+		AddRoute("scheduler", scheduler.NewHandler(app.schedulerKeeper)).
+		AddRoute("compliance", compliance.NewHandler(app.complianceKeeper))
+	app.QueryRouter().
+		AddRoute("token", bank.NewTokenQuerier(app.tokenKeeper)).
+		AddRoute("supply", bank.NewSupplyQuerier(app.supplyKeeper)).
+		AddRoute("htlc", bank.NewHTLCQuerier(app.htlcKeeper)).
+		AddRoute("stake", stake.NewQuerier(app.stakeKeeper)).
+		AddRoute("mutual", mutual.NewQuerier(app.mutualKeeper)).
+		AddRoute("feegrant", feegrant.NewQuerier(app.feeGrantKeeper)).
+		AddRoute("slashing", slashing.NewQuerier(app.slashingKeeper)).
+		AddRoute("gov", gov.NewQuerier(app.govKeeper)).
+		AddRoute("scheduler", scheduler.NewQuerier(app.schedulerKeeper)).
+		AddRoute("compliance", compliance.NewQuerier(app.complianceKeeper))
 
 	// initialize BaseApp
 	app.SetInitChainer(app.initChainer)
-	app.MountStoresIAVL(app.capKeyMainStore, app.capKeyAccountStore, app.capKeyIBCStore, app.capKeyStakingStore)
-	app.SetAnteHandler(auth.NewAnteHandler(app.accountMapper, auth.BurnFeeHandler))
+	app.SetBeginBlocker(slashing.NewBeginBlocker(app.slashingKeeper))
+	app.SetEndBlocker(app.endBlocker)
+	app.MountStoresIAVL(app.capKeyMainStore, app.capKeyAccountStore, app.capKeyIBCStore, app.capKeyStakingStore, app.capKeyFeeGrantStore, app.capKeySlashingStore, app.capKeyGovStore, app.capKeyTokenStore, app.capKeySupplyStore, app.capKeyHTLCStore, app.capKeySchedulerStore, app.capKeyComplianceStore)
+	app.SetAnteHandler(auth.NewFeeGrantAnteHandler(app.accountMapper, app.feeGrantKeeper, stake.NewFeeHandler(app.stakeKeeper)))
 	err := app.LoadLatestVersion(app.capKeyMainStore)
 	if err != nil {
 		cmn.Exit(err.Error())
@@ -90,15 +152,27 @@ func NewMutualApp(logger log.Logger, db dbm.DB) *MutualApp {
 
 }
 
+// Codec returns the codec of the app
+func (app *MutualApp) Codec() *wire.Codec {
+	return app.cdc
+}
+
 // Custom tx codec
 func MakeCodec() *wire.Codec {
 	var cdc = wire.NewCodec()
 	wire.RegisterCrypto(cdc) // Register crypto.
 	sdk.RegisterWire(cdc)    // Register Msgs
+	auth.RegisterMultisig(cdc)
+	auth.RegisterVestingAccounts(cdc)
 	bank.RegisterWire(cdc)
 	stake.RegisterWire(cdc)
 	ibc.RegisterWire(cdc)
 	mutual.RegisterWire(cdc)
+	feegrant.RegisterWire(cdc)
+	slashing.RegisterWire(cdc)
+	gov.RegisterWire(cdc)
+	scheduler.RegisterWire(cdc)
+	compliance.RegisterWire(cdc)
 
 	// register custom AppAccount
 	cdc.RegisterInterface((*sdk.Account)(nil), nil)
@@ -106,6 +180,15 @@ func MakeCodec() *wire.Codec {
 	return cdc
 }
 
+// Escrows expire, standing orders are paid and proposals are tallied before
+// the validator set changes
+func (app *MutualApp) endBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+	bank.HTLCEndBlocker(ctx, app.htlcKeeper)
+	scheduler.EndBlocker(ctx, app.schedulerKeeper)
+	gov.EndBlocker(ctx, app.govKeeper)
+	return stake.NewEndBlocker(app.stakeKeeper)(ctx, req)
+}
+
 // Custom logic for mutual initialization
 func (app *MutualApp) initChainer(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
 	stateJSON := req.AppStateBytes
@@ -117,14 +200,74 @@ func (app *MutualApp) initChainer(ctx sdk.Context, req abci.RequestInitChain) ab
 		// return sdk.ErrGenesisParse("").TraceCause(err, "")
 	}
 
+	// load the accounts
+	var accountCoins sdk.Coins
 	for _, gacc := range genesisState.Accounts {
-		acc, err := gacc.ToAppAccount()
+		acc, err := gacc.ToAccount()
 		if err != nil {
 			panic(err) // TODO https://github.com/cosmos/cosmos-sdk/issues/468
 			//	return sdk.ErrGenesisParse("").TraceCause(err, "")
 		}
 		app.accountMapper.SetAccount(ctx, acc)
+		accountCoins = accountCoins.Plus(acc.GetCoins())
+	}
+
+	// load the initial stake information, the fees go to the validators
+	stakeData := genesisState.StakeData
+	if stakeData.Params.BondDenom == "" {
+		stakeData = stake.GetDefaultGenesisState()
+	}
+	stake.InitGenesis(ctx, app.stakeKeeper, stakeData)
+
+	// load the supply, from the coins of the accounts, gov, htlc and stake if
+	// the genesis predates it, then the tokens, the denoms without a token are
+	// native tokens
+	supplyData := genesisState.SupplyData
+	if len(supplyData.Supply) == 0 {
+		var escrows []bank.Escrow
+		if escrow := gov.GenesisEscrow(genesisState.GovData); !escrow.IsZero() {
+			escrows = append(escrows, bank.Escrow{gov.MsgType, escrow})
+		}
+		if escrow := bank.GenesisHTLCEscrow(genesisState.HTLCData); !escrow.IsZero() {
+			escrows = append(escrows, bank.Escrow{bank.HTLCMsgType, escrow})
+		}
+		if escrow := stake.GenesisEscrow(stakeData); !escrow.IsZero() {
+			escrows = append(escrows, bank.Escrow{stake.MsgType, escrow})
+		}
+		supplyData = bank.NewSupplyGenesisState(accountCoins, escrows)
 	}
+	bank.InitSupplyGenesis(ctx, app.supplyKeeper, supplyData)
+	bank.InitTokenGenesis(ctx, app.tokenKeeper, genesisState.TokenData)
+	app.tokenKeeper.RegisterNativeTokens(ctx, app.supplyKeeper.GetTotalSupply(ctx))
+	bank.InitHTLCGenesis(ctx, app.htlcKeeper, genesisState.HTLCData)
+
+	// load the slashing params
+	slashingData := genesisState.SlashingData
+	if slashingData.Params.SignedBlocksWindow == 0 {
+		slashingData = slashing.DefaultGenesisState()
+	}
+	slashing.InitGenesis(ctx, app.slashingKeeper, slashingData)
+
+	// load the governance params
+	govData := genesisState.GovData
+	if govData.Params.VotingPeriod == 0 {
+		govData = gov.DefaultGenesisState()
+	}
+	gov.InitGenesis(ctx, app.govKeeper, govData)
+
+	// load the standing orders and the scheduler params
+	schedulerData := genesisState.SchedulerData
+	if schedulerData.Params.MaxOrdersPerBlock == 0 {
+		schedulerData.Params = scheduler.DefaultParams()
+	}
+	if schedulerData.Params.MaxOrdersPerSender == 0 {
+		schedulerData.Params.MaxOrdersPerSender = scheduler.DefaultParams().MaxOrdersPerSender
+	}
+	scheduler.InitGenesis(ctx, app.schedulerKeeper, schedulerData)
+
+	// load the frozen addresses and the allow-lists
+	compliance.InitGenesis(ctx, app.complianceKeeper, genesisState.ComplianceData)
+
 	return abci.ResponseInitChain{}
 }
 
@@ -135,17 +278,21 @@ func (app *MutualApp) ExportAppStateJSON() (appState json.RawMessage, err error)
 	// iterate to get the accounts
 	accounts := []*types.GenesisAccount{}
 	appendAccount := func(acc sdk.Account) (stop bool) {
-		account := &types.GenesisAccount{
-			Address: acc.GetAddress(),
-			Coins:   acc.GetCoins(),
-		}
-		accounts = append(accounts, account)
+		accounts = append(accounts, types.NewGenesisAccountI(acc))
 		return false
 	}
 	app.accountMapper.IterateAccounts(ctx, appendAccount)
 
 	genState := types.GenesisState{
-		Accounts: accounts,
+		Accounts:       accounts,
+		StakeData:      stake.WriteGenesis(ctx, app.stakeKeeper),
+		SlashingData:   slashing.WriteGenesis(ctx, app.slashingKeeper),
+		GovData:        gov.WriteGenesis(ctx, app.govKeeper),
+		TokenData:      bank.WriteTokenGenesis(ctx, app.tokenKeeper),
+		SupplyData:     bank.WriteSupplyGenesis(ctx, app.supplyKeeper),
+		HTLCData:       bank.WriteHTLCGenesis(ctx, app.htlcKeeper),
+		SchedulerData:  scheduler.WriteGenesis(ctx, app.schedulerKeeper),
+		ComplianceData: compliance.WriteGenesis(ctx, app.complianceKeeper),
 	}
 	return wire.MarshalJSONIndent(app.cdc, genState)
 }
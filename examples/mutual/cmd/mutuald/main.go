@@ -3,17 +3,30 @@ package main
 import (
 	"encoding/json"
 	"os"
-	//"path/filepath"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	abci "github.com/tendermint/abci/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	"github.com/tendermint/tmlibs/cli"
+	cmn "github.com/tendermint/tmlibs/common"
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
 	"inschain-tendermint/examples/mutual/app"
-	"github.com/cosmos/cosmos-sdk/server"
+	"inschain-tendermint/x/indexer"
+	"inschain-tendermint/x/webhook"
+	"inschain-tendermint/server"
+)
+
+const (
+	flagIndexer      = "indexer"
+	flagIndexerLaddr = "indexer.laddr"
+	flagIndexerNode  = "indexer.node"
+	flagWebhooks     = "webhooks"
+	flagWebhooksNode = "webhooks.node"
 )
 
 // rootCmd is the entry point for this binary
@@ -85,6 +98,15 @@ func main() {
 		server.ConstructAppCreator(newApp, "mutual"),
 		server.ConstructAppExporter(exportAppState, "mutual"))
 
+	// embedded off-chain indexer
+	rootCmd.PersistentFlags().Bool(flagIndexer, false, "Run the mutual state indexer in-process")
+	rootCmd.PersistentFlags().String(flagIndexerLaddr, "tcp://localhost:1318", "Address for the indexer query API to listen on")
+	rootCmd.PersistentFlags().String(flagIndexerNode, "tcp://localhost:46657", "RPC address of the node, used to catch up the index")
+
+	// outbound webhooks
+	rootCmd.PersistentFlags().String(flagWebhooks, "", "Path of the webhook subscriptions file, empty to disable")
+	rootCmd.PersistentFlags().String(flagWebhooksNode, "tcp://localhost:46657", "RPC address of the node, used to replay the missed events")
+
 	// prepare and add flags
 	rootDir := os.ExpandEnv("$HOME/.mutuald")
 	executor := cli.PrepareBaseCmd(rootCmd, "MU", rootDir)
@@ -92,7 +114,38 @@ func main() {
 }
 
 func newApp(logger log.Logger, db dbm.DB) abci.Application {
-	return app.NewMutualApp(logger, db)
+	mapp := app.NewMutualApp(logger, db)
+	dataDir := filepath.Join(viper.GetString(cli.HomeFlag), "data")
+	if viper.GetBool(flagIndexer) {
+		idxDB, err := indexer.OpenDB(dataDir)
+		if err != nil {
+			cmn.Exit(err.Error())
+		}
+		_, err = indexer.Embed(mapp.BaseApp, mapp.Codec(), idxDB,
+			viper.GetString(flagIndexerNode), viper.GetString(flagIndexerLaddr),
+			logger.With("module", "indexer"))
+		if err != nil {
+			cmn.Exit(err.Error())
+		}
+	}
+	if path := viper.GetString(flagWebhooks); path != "" {
+		config, err := webhook.LoadConfig(path)
+		if err != nil {
+			cmn.Exit(err.Error())
+		}
+		outboxDB, err := webhook.OpenDB(dataDir)
+		if err != nil {
+			cmn.Exit(err.Error())
+		}
+		dispatcher := webhook.NewDispatcher(config, outboxDB, mapp.Codec(), logger.With("module", "webhook"))
+		node := rpcclient.NewHTTP(viper.GetString(flagWebhooksNode), "/websocket")
+		err = dispatcher.Subscribe(mapp.EventBus(), node)
+		if err != nil {
+			cmn.Exit(err.Error())
+		}
+		go dispatcher.Run(nil)
+	}
+	return mapp
 }
 
 func exportAppState(logger log.Logger, db dbm.DB) (json.RawMessage, error) {
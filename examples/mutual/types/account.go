@@ -1,9 +1,16 @@
 package types
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/stake"
+
+	"inschain-tendermint/x/compliance"
+	"inschain-tendermint/x/gov"
+	"inschain-tendermint/x/scheduler"
+	"inschain-tendermint/x/slashing"
 )
 
 var _ sdk.Account = (*AppAccount)(nil)
@@ -28,7 +35,8 @@ func GetAccountDecoder(cdc *wire.Codec) sdk.AccountDecoder {
 		if len(accBytes) == 0 {
 			return nil, sdk.ErrTxDecode("accBytes are empty")
 		}
-		acct := new(AppAccount)
+		// the account is an AppAccount or a vesting account
+		var acct sdk.Account
 		err = cdc.UnmarshalBinaryBare(accBytes, &acct)
 		if err != nil {
 			panic(err)
@@ -41,14 +49,28 @@ func GetAccountDecoder(cdc *wire.Codec) sdk.AccountDecoder {
 
 // State to Unmarshal
 type GenesisState struct {
-	Accounts []*GenesisAccount `json:"accounts"`
+	Accounts       []*GenesisAccount       `json:"accounts"`
+	StakeData      stake.GenesisState      `json:"stake"`
+	SlashingData   slashing.GenesisState   `json:"slashing"`
+	GovData        gov.GenesisState        `json:"gov"`
+	TokenData      bank.TokenGenesisState  `json:"token"`
+	SupplyData     bank.SupplyGenesisState `json:"supply"`
+	HTLCData       bank.HTLCGenesisState   `json:"htlc"`
+	SchedulerData  scheduler.GenesisState  `json:"scheduler"`
+	ComplianceData compliance.GenesisState `json:"compliance"`
 }
 
-// GenesisAccount doesn't need pubkey or sequence
+// GenesisAccount doesn't need pubkey or sequence.
+// With an end time, the original vesting coins out of its coins vest
+// linearly from the start to the end time, or at the end time if the
+// start time is zero.
 type GenesisAccount struct {
-	Name    string      `json:"name"`
-	Address sdk.Address `json:"address"`
-	Coins   sdk.Coins   `json:"coins"`
+	Name            string      `json:"name"`
+	Address         sdk.Address `json:"address"`
+	Coins           sdk.Coins   `json:"coins"`
+	OriginalVesting sdk.Coins   `json:"original_vesting,omitempty"`
+	StartTime       int64       `json:"start_time,omitempty"`
+	EndTime         int64       `json:"end_time,omitempty"`
 }
 
 func NewGenesisAccount(aa *AppAccount) *GenesisAccount {
@@ -59,6 +81,26 @@ func NewGenesisAccount(aa *AppAccount) *GenesisAccount {
 	}
 }
 
+// NewGenesisAccountI returns the GenesisAccount of any account,
+// with the vesting of a vesting account
+func NewGenesisAccountI(acc sdk.Account) *GenesisAccount {
+	if aa, ok := acc.(*AppAccount); ok {
+		return NewGenesisAccount(aa)
+	}
+	ga := &GenesisAccount{
+		Address: acc.GetAddress(),
+		Coins:   acc.GetCoins().Sort(),
+	}
+	if vacc, ok := acc.(auth.VestingAccount); ok {
+		ga.OriginalVesting = vacc.GetOriginalVesting()
+		ga.EndTime = vacc.GetEndTime()
+		if cva, ok := acc.(*auth.ContinuousVestingAccount); ok {
+			ga.StartTime = cva.StartTime
+		}
+	}
+	return ga
+}
+
 // convert GenesisAccount to AppAccount
 func (ga *GenesisAccount) ToAppAccount() (acc *AppAccount, err error) {
 	baseAcc := auth.BaseAccount{
@@ -70,3 +112,19 @@ func (ga *GenesisAccount) ToAppAccount() (acc *AppAccount, err error) {
 		Name:        ga.Name,
 	}, nil
 }
+
+// convert GenesisAccount to a vesting account if it has an end time,
+// to an AppAccount otherwise. A vesting account has no name.
+func (ga *GenesisAccount) ToAccount() (acc sdk.Account, err error) {
+	if ga.EndTime == 0 {
+		return ga.ToAppAccount()
+	}
+	baseAcc := auth.BaseAccount{
+		Address: ga.Address,
+		Coins:   ga.Coins.Sort(),
+	}
+	if ga.StartTime == 0 {
+		return auth.NewDelayedVestingAccount(baseAcc, ga.OriginalVesting, ga.EndTime), nil
+	}
+	return auth.NewContinuousVestingAccount(baseAcc, ga.OriginalVesting, ga.StartTime, ga.EndTime), nil
+}
@@ -11,9 +11,9 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	bam "github.com/cosmos/cosmos-sdk/baseapp"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
+	bam "inschain-tendermint/baseapp"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 )
 
 // NewApp creates a simple mock kvstore app for testing. It should work
@@ -3,7 +3,7 @@ package mock
 import (
 	dbm "github.com/tendermint/tmlibs/db"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 type multiStore struct {
@@ -107,6 +107,11 @@ func (kv kvStore) ReverseSubspaceIterator(prefix []byte) sdk.Iterator {
 	panic("not implemented")
 }
 
+// the mock store does not meter gas
+func (kv kvStore) Gas(meter sdk.GasMeter, config sdk.GasConfig) sdk.KVStore {
+	return kv
+}
+
 func NewCommitMultiStore(db dbm.DB) sdk.CommitMultiStore {
 	return multiStore{kv: make(map[sdk.StoreKey]kvStore)}
 }
@@ -7,7 +7,7 @@ import (
 
 	dbm "github.com/tendermint/tmlibs/db"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 func TestStore(t *testing.T) {
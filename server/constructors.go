@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"path/filepath"
 
+	"github.com/spf13/viper"
 	abci "github.com/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/types"
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
+
+	sdk "inschain-tendermint/types"
 )
 
 // AppCreator lets us lazily initialize app, using home dir
@@ -25,10 +29,31 @@ func ConstructAppCreator(appFn func(log.Logger, dbm.DB) abci.Application, name s
 			return nil, err
 		}
 		app := appFn(logger, db)
+		setMaxBlockGas(app, rootDir)
+		err = setMinGasPrices(app, viper.GetString(flagMinGasPrices))
+		if err != nil {
+			return nil, err
+		}
 		return app, nil
 	}
 }
 
+// apps metering gas are limited by the max gas of a block,
+// from the consensus params of the genesis
+func setMaxBlockGas(app abci.Application, rootDir string) {
+	gasApp, ok := app.(interface {
+		SetMaxBlockGas(int64)
+	})
+	if !ok {
+		return
+	}
+	genDoc, err := tmtypes.GenesisDocFromFile(filepath.Join(rootDir, "config", "genesis.json"))
+	if err != nil || genDoc.ConsensusParams == nil {
+		return
+	}
+	gasApp.SetMaxBlockGas(genDoc.ConsensusParams.BlockSize.MaxGas)
+}
+
 // ConstructAppExporter returns an application export function
 func ConstructAppExporter(appFn func(log.Logger, dbm.DB) (json.RawMessage, error), name string) AppExporter {
 	return func(rootDir string, logger log.Logger) (json.RawMessage, error) {
@@ -40,3 +65,20 @@ func ConstructAppExporter(appFn func(log.Logger, dbm.DB) (json.RawMessage, error
 		return appFn(logger, db)
 	}
 }
+
+// apps checking fees only accept txs paying the minimum gas prices
+// of the node in their mempool
+func setMinGasPrices(app abci.Application, pricesStr string) error {
+	feeApp, ok := app.(interface {
+		SetMinGasPrices(sdk.GasPrices)
+	})
+	if !ok {
+		return nil
+	}
+	prices, err := sdk.ParseGasPrices(pricesStr)
+	if err != nil {
+		return err
+	}
+	feeApp.SetMinGasPrices(prices)
+	return nil
+}
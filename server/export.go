@@ -7,7 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/wire"
 )
 
 // ExportCmd dumps app state to JSON
@@ -24,9 +24,9 @@ import (
 	cmn "github.com/tendermint/tmlibs/common"
 	dbm "github.com/tendermint/tmlibs/db"
 
-	clkeys "github.com/cosmos/cosmos-sdk/client/keys"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
+	clkeys "inschain-tendermint/client/keys"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 )
 
 // genesis piece structure for creating combined genesis
@@ -156,8 +156,6 @@ func InitCmd(ctx *Context, cdc *wire.Codec, appInit AppInit) *cobra.Command {
 					return err
 				}
 				config.P2P.PersistentPeers = persistentPeers
-				configFilePath := filepath.Join(viper.GetString(tmcli.HomeFlag), "config", "config.toml")
-				cfg.WriteConfigFile(configFilePath, config)
 			} else {
 				appGenTx, am, validator, err := appInit.AppGenTx(cdc, pubKey)
 				appMessage = am
@@ -168,6 +166,11 @@ func InitCmd(ctx *Context, cdc *wire.Codec, appInit AppInit) *cobra.Command {
 				appGenTxs = []json.RawMessage{appGenTx}
 			}
 
+			// index every tag, tx search and the indexers query by msg tags
+			config.TxIndex.IndexAllTags = true
+			configFilePath := filepath.Join(viper.GetString(tmcli.HomeFlag), "config", "config.toml")
+			cfg.WriteConfigFile(configFilePath, config)
+
 			appState, err := appInit.AppGenState(cdc, appGenTxs)
 			if err != nil {
 				return err
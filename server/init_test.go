@@ -1,15 +1,18 @@
 package server
 
 import (
+	"path/filepath"
 	"testing"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tendermint/tmlibs/cli"
 	"github.com/tendermint/tmlibs/log"
 
-	"github.com/cosmos/cosmos-sdk/mock"
-	"github.com/cosmos/cosmos-sdk/wire"
 	tcmd "github.com/tendermint/tendermint/cmd/tendermint/commands"
+	"inschain-tendermint/mock"
+	"inschain-tendermint/wire"
 )
 
 // TODO update
@@ -28,6 +31,12 @@ func TestInitCmd(t *testing.T) {
 	cmd := InitCmd(ctx, cdc, appInit)
 	err = cmd.RunE(nil, nil)
 	require.NoError(t, err)
+
+	// the generated config indexes every tag
+	written := viper.New()
+	written.SetConfigFile(filepath.Join(viper.GetString(cli.HomeFlag), "config", "config.toml"))
+	require.NoError(t, written.ReadInConfig())
+	require.True(t, written.GetBool("tx_index.index_all_tags"))
 }
 
 func TestGenTxCmd(t *testing.T) {
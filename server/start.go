@@ -17,6 +17,7 @@ import (
 const (
 	flagWithTendermint = "with-tendermint"
 	flagAddress        = "address"
+	flagMinGasPrices   = "min-gas-prices"
 )
 
 // StartCmd runs the service passed in, either
@@ -38,6 +39,7 @@ func StartCmd(ctx *Context, appCreator AppCreator) *cobra.Command {
 	// basic flags for abci app
 	cmd.Flags().Bool(flagWithTendermint, true, "run abci app embedded in-process with tendermint")
 	cmd.Flags().String(flagAddress, "tcp://0.0.0.0:46658", "Listen address")
+	cmd.Flags().String(flagMinGasPrices, "", "Minimum gas prices to accept txs in the mempool (ex. 0.025steak,1photino)")
 
 	// AddNodeFlags adds support for all tendermint-specific command line options
 	tcmd.AddNodeFlags(cmd)
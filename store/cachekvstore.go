@@ -5,8 +5,8 @@ import (
 	"sort"
 	"sync"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	cmn "github.com/tendermint/tmlibs/common"
+	sdk "inschain-tendermint/types"
 )
 
 // If value is nil but deleted is false, it means the parent doesn't have the
@@ -144,6 +144,11 @@ func (ci *cacheKVStore) ReverseSubspaceIterator(prefix []byte) Iterator {
 	return ci.iterator(prefix, sdk.PrefixEndBytes(prefix), false)
 }
 
+// Implements KVStore.
+func (ci *cacheKVStore) Gas(meter sdk.GasMeter, config sdk.GasConfig) KVStore {
+	return NewGasKVStore(meter, config, ci)
+}
+
 func (ci *cacheKVStore) iterator(start, end []byte, ascending bool) Iterator {
 	var parent, cache Iterator
 	if ascending {
@@ -1,7 +1,7 @@
 package store
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 //----------------------------------------
@@ -1,8 +1,8 @@
 package store
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	dbm "github.com/tendermint/tmlibs/db"
+	sdk "inschain-tendermint/types"
 )
 
 type dbStoreAdapter struct {
@@ -27,5 +27,10 @@ func (dsa dbStoreAdapter) ReverseSubspaceIterator(prefix []byte) Iterator {
 	return dsa.ReverseIterator(prefix, sdk.PrefixEndBytes(prefix))
 }
 
+// Implements KVStore.
+func (dsa dbStoreAdapter) Gas(meter sdk.GasMeter, config sdk.GasConfig) KVStore {
+	return NewGasKVStore(meter, config, dsa)
+}
+
 // dbm.DB implements KVStore so we can CacheKVStore it.
 var _ KVStore = dbStoreAdapter{dbm.DB(nil)}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	sdk "inschain-tendermint/types"
+)
+
+// gasKVStore applies gas tracking to an underlying KVStore
+type gasKVStore struct {
+	gasMeter  sdk.GasMeter
+	gasConfig sdk.GasConfig
+	parent    KVStore
+}
+
+var _ KVStore = &gasKVStore{}
+
+// nolint
+func NewGasKVStore(gasMeter sdk.GasMeter, gasConfig sdk.GasConfig, parent KVStore) *gasKVStore {
+	return &gasKVStore{
+		gasMeter:  gasMeter,
+		gasConfig: gasConfig,
+		parent:    parent,
+	}
+}
+
+// Implements Store.
+func (gi *gasKVStore) GetStoreType() StoreType {
+	return gi.parent.GetStoreType()
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Get(key []byte) (value []byte) {
+	gi.gasMeter.ConsumeGas(gi.gasConfig.ReadCostFlat, "ReadFlat")
+	value = gi.parent.Get(key)
+	// TODO overflow-safe math?
+	gi.gasMeter.ConsumeGas(gi.gasConfig.ReadCostPerByte*sdk.Gas(len(value)), "ReadPerByte")
+	return value
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Set(key []byte, value []byte) {
+	gi.gasMeter.ConsumeGas(gi.gasConfig.WriteCostFlat, "WriteFlat")
+	// TODO overflow-safe math?
+	gi.gasMeter.ConsumeGas(gi.gasConfig.WriteCostPerByte*sdk.Gas(len(key)+len(value)), "WritePerByte")
+	gi.parent.Set(key, value)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Has(key []byte) bool {
+	gi.gasMeter.ConsumeGas(gi.gasConfig.HasCost, "Has")
+	return gi.parent.Has(key)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Delete(key []byte) {
+	gi.gasMeter.ConsumeGas(gi.gasConfig.DeleteCost, "Delete")
+	gi.parent.Delete(key)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Iterator(start, end []byte) Iterator {
+	return gi.iterator(start, end, true)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) ReverseIterator(start, end []byte) Iterator {
+	return gi.iterator(start, end, false)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) SubspaceIterator(prefix []byte) Iterator {
+	return gi.iterator(prefix, sdk.PrefixEndBytes(prefix), true)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) ReverseSubspaceIterator(prefix []byte) Iterator {
+	return gi.iterator(prefix, sdk.PrefixEndBytes(prefix), false)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Gas(meter sdk.GasMeter, config sdk.GasConfig) KVStore {
+	return NewGasKVStore(meter, config, gi)
+}
+
+// Implements CacheWrapper.
+func (gi *gasKVStore) CacheWrap() CacheWrap {
+	panic("you cannot CacheWrap a GasKVStore")
+}
+
+func (gi *gasKVStore) iterator(start, end []byte, ascending bool) Iterator {
+	var parent Iterator
+	if ascending {
+		parent = gi.parent.Iterator(start, end)
+	} else {
+		parent = gi.parent.ReverseIterator(start, end)
+	}
+	gi.gasMeter.ConsumeGas(gi.gasConfig.ReadCostFlat, "IterStart")
+	return newGasIterator(gi.gasMeter, gi.gasConfig, parent)
+}
+
+// gasIterator charges every step and the size of every value read
+type gasIterator struct {
+	gasMeter  sdk.GasMeter
+	gasConfig sdk.GasConfig
+	parent    Iterator
+}
+
+func newGasIterator(gasMeter sdk.GasMeter, gasConfig sdk.GasConfig, parent Iterator) Iterator {
+	return &gasIterator{
+		gasMeter:  gasMeter,
+		gasConfig: gasConfig,
+		parent:    parent,
+	}
+}
+
+// Implements Iterator.
+func (g *gasIterator) Domain() (start []byte, end []byte) {
+	return g.parent.Domain()
+}
+
+// Implements Iterator.
+func (g *gasIterator) Valid() bool {
+	return g.parent.Valid()
+}
+
+// Implements Iterator.
+func (g *gasIterator) Next() {
+	g.gasMeter.ConsumeGas(g.gasConfig.IterNextCostFlat, "IterNext")
+	g.parent.Next()
+}
+
+// Implements Iterator.
+func (g *gasIterator) Key() (key []byte) {
+	return g.parent.Key()
+}
+
+// Implements Iterator.
+func (g *gasIterator) Value() (value []byte) {
+	value = g.parent.Value()
+	g.gasMeter.ConsumeGas(g.gasConfig.ValueCostPerByte*sdk.Gas(len(value)), "ValuePerByte")
+	return value
+}
+
+// Implements Iterator.
+func (g *gasIterator) Close() {
+	g.parent.Close()
+}
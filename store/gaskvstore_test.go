@@ -0,0 +1,67 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tmlibs/db"
+
+	sdk "inschain-tendermint/types"
+)
+
+func newGasKVStore(limit sdk.Gas) (sdk.GasMeter, KVStore) {
+	meter := sdk.NewGasMeter(limit)
+	mem := dbStoreAdapter{dbm.NewMemDB()}
+	return meter, NewGasKVStore(meter, sdk.KVGasConfig(), mem)
+}
+
+func TestGasKVStoreBasic(t *testing.T) {
+	meter, st := newGasKVStore(1000)
+
+	require.Empty(t, st.Get(keyFmt(1)), "Expected `key1` to be empty")
+	st.Set(keyFmt(1), valFmt(1))
+	require.Equal(t, valFmt(1), st.Get(keyFmt(1)))
+	require.True(t, st.Has(keyFmt(1)))
+	st.Delete(keyFmt(1))
+	require.Empty(t, st.Get(keyFmt(1)), "Expected `key1` to be empty")
+
+	// read, write (11 bytes of key and 13 of value), read (13 bytes), has, delete, read
+	config := sdk.KVGasConfig()
+	expected := config.ReadCostFlat +
+		config.WriteCostFlat + 24*config.WriteCostPerByte +
+		config.ReadCostFlat + 13*config.ReadCostPerByte +
+		config.HasCost +
+		config.DeleteCost +
+		config.ReadCostFlat
+	require.Equal(t, expected, meter.GasConsumed())
+}
+
+func TestGasKVStoreIterator(t *testing.T) {
+	meter, st := newGasKVStore(1000)
+	st.Set(keyFmt(1), valFmt(1))
+	st.Set(keyFmt(2), valFmt(2))
+	consumed := meter.GasConsumed()
+
+	iter := st.Iterator(nil, nil)
+	var n int
+	for ; iter.Valid(); iter.Next() {
+		n++
+	}
+	iter.Close()
+	require.Equal(t, 2, n)
+	require.True(t, meter.GasConsumed() > consumed)
+}
+
+func TestGasKVStoreOutOfGas(t *testing.T) {
+	meter, st := newGasKVStore(20)
+	require.Panics(t, func() { st.Set(keyFmt(1), valFmt(1)) })
+	require.Equal(t, meter.Limit(), meter.GasConsumed())
+
+	// the panic carries the descriptor of the operation
+	defer func() {
+		r := recover()
+		_, ok := r.(sdk.ErrorOutOfGas)
+		require.True(t, ok, "expected ErrorOutOfGas, got %v", r)
+	}()
+	st.Get(keyFmt(1))
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 
@@ -9,7 +10,7 @@ import (
 	cmn "github.com/tendermint/tmlibs/common"
 	dbm "github.com/tendermint/tmlibs/db"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 	//sdk "inschain-tendermint/types"
 )
 
@@ -136,6 +137,11 @@ func (st *iavlStore) ReverseSubspaceIterator(prefix []byte) Iterator {
 	return st.ReverseIterator(prefix, sdk.PrefixEndBytes(prefix))
 }
 
+// Implements KVStore.
+func (st *iavlStore) Gas(meter sdk.GasMeter, config sdk.GasConfig) KVStore {
+	return NewGasKVStore(meter, config, st)
+}
+
 // Query implements ABCI interface, allows queries
 //
 // by default we will return from (latest height -1),
@@ -178,15 +184,17 @@ func (st *iavlStore) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
 			_, res.Value = tree.GetVersioned(key, height)
 		}
 	case "/subspace":
-		subspace := req.Data
-		res.Key = subspace
-		var KVs []KVPair
-		iterator := st.SubspaceIterator(subspace)
-		for ; iterator.Valid(); iterator.Next() {
-			KVs = append(KVs, KVPair{iterator.Key(), iterator.Value()})
+		var query sdk.SubspaceQuery
+		err := cdc.UnmarshalBinary(req.Data, &query)
+		if err != nil {
+			return sdk.ErrTxDecode(err.Error()).QueryResult()
+		}
+		res.Key = query.Subspace
+		result, sdkErr := querySubspace(st, query)
+		if sdkErr != nil {
+			return sdkErr.QueryResult()
 		}
-		iterator.Close()
-		res.Value = cdc.MustMarshalBinary(KVs)
+		res.Value = cdc.MustMarshalBinary(result)
 	default:
 		msg := fmt.Sprintf("Unexpected Query path: %v", req.Path)
 		return sdk.ErrUnknownRequest(msg).QueryResult()
@@ -194,6 +202,63 @@ func (st *iavlStore) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
 	return
 }
 
+// get a page of the pairs of a subspace, bounded by the limits of the query
+func querySubspace(st KVStore, query sdk.SubspaceQuery) (result sdk.SubspaceResult, err sdk.Error) {
+	subspace, start := query.Subspace, query.Start
+	if len(subspace) == 0 {
+		return result, sdk.ErrUnknownRequest("Subspace cannot be zero length")
+	}
+	if len(start) > 0 && !bytes.HasPrefix(start, subspace) {
+		return result, sdk.ErrUnknownRequest("Start key is not in the subspace")
+	}
+
+	var iterator Iterator
+	switch {
+	case query.Reverse && len(start) > 0:
+		// the end of the reverse iterator is exclusive
+		iterator = st.ReverseIterator(subspace, append(cp(start), 0x00))
+	case query.Reverse:
+		iterator = st.ReverseSubspaceIterator(subspace)
+	case len(start) > 0:
+		iterator = st.Iterator(start, sdk.PrefixEndBytes(subspace))
+	default:
+		iterator = st.SubspaceIterator(subspace)
+	}
+	defer iterator.Close()
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = sdk.DefaultSubspaceLimit
+	}
+	if limit > sdk.MaxSubspaceLimit {
+		limit = sdk.MaxSubspaceLimit
+	}
+	if query.CountOnly {
+		// counts are paged too, a subspace is counted by summing its pages
+		for ; iterator.Valid(); iterator.Next() {
+			if result.Count == int64(limit) {
+				result.Next = iterator.Key()
+				break
+			}
+			result.Count++
+		}
+		return result, nil
+	}
+	for ; iterator.Valid(); iterator.Next() {
+		if len(result.KVs) == limit {
+			result.Next = iterator.Key()
+			break
+		}
+		kv := KVPair{Key: iterator.Key()}
+		if !query.KeysOnly {
+			kv.Value = iterator.Value()
+		}
+		result.KVs = append(result.KVs, kv)
+	}
+	result.Count = int64(len(result.KVs))
+	return result, nil
+}
+
 //----------------------------------------
 
 // Implements Iterator.
@@ -4,13 +4,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	abci "github.com/tendermint/abci/types"
 	"github.com/tendermint/iavl"
 	cmn "github.com/tendermint/tmlibs/common"
 	dbm "github.com/tendermint/tmlibs/db"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 var (
@@ -317,3 +318,65 @@ func TestIAVLStoreQuery(t *testing.T) {
 	assert.Equal(t, uint32(sdk.CodeOK), qres.Code)
 	assert.Equal(t, v, qres.Value)
 }
+
+func TestIAVLStoreSubspaceQuery(t *testing.T) {
+	db := dbm.NewMemDB()
+	tree := iavl.NewVersionedTree(db, cacheSize)
+	iavlStore := newIAVLStore(tree, numHistory)
+
+	for _, k := range []string{"k1", "k2", "k3", "k4", "other"} {
+		iavlStore.Set([]byte(k), []byte("v"+k))
+	}
+	iavlStore.Commit()
+
+	query := func(q sdk.SubspaceQuery) (result sdk.SubspaceResult) {
+		qres := iavlStore.Query(abci.RequestQuery{Path: "/subspace", Data: cdc.MustMarshalBinary(q)})
+		require.Equal(t, uint32(sdk.CodeOK), qres.Code, qres.Log)
+		cdc.MustUnmarshalBinary(qres.Value, &result)
+		return
+	}
+	keys := func(result sdk.SubspaceResult) (res []string) {
+		for _, kv := range result.KVs {
+			res = append(res, string(kv.Key))
+		}
+		return
+	}
+
+	// page through the subspace with the cursor
+	result := query(sdk.SubspaceQuery{Subspace: []byte("k"), Limit: 3})
+	assert.Equal(t, []string{"k1", "k2", "k3"}, keys(result))
+	assert.Equal(t, []byte("vk1"), []byte(result.KVs[0].Value))
+	assert.Equal(t, []byte("k4"), result.Next)
+	result = query(sdk.SubspaceQuery{Subspace: []byte("k"), Start: result.Next, Limit: 3})
+	assert.Equal(t, []string{"k4"}, keys(result))
+	assert.Empty(t, result.Next)
+
+	// in descending order
+	result = query(sdk.SubspaceQuery{Subspace: []byte("k"), Limit: 2, Reverse: true})
+	assert.Equal(t, []string{"k4", "k3"}, keys(result))
+	result = query(sdk.SubspaceQuery{Subspace: []byte("k"), Start: result.Next, Limit: 2, Reverse: true})
+	assert.Equal(t, []string{"k2", "k1"}, keys(result))
+	assert.Empty(t, result.Next)
+
+	// keys only, and count only
+	result = query(sdk.SubspaceQuery{Subspace: []byte("k"), KeysOnly: true})
+	assert.Equal(t, []string{"k1", "k2", "k3", "k4"}, keys(result))
+	assert.Empty(t, result.KVs[0].Value)
+	result = query(sdk.SubspaceQuery{Subspace: []byte("k"), Start: []byte("k2"), CountOnly: true})
+	assert.Empty(t, result.KVs)
+	assert.Equal(t, int64(3), result.Count)
+	assert.Empty(t, result.Next)
+	result = query(sdk.SubspaceQuery{Subspace: []byte("k"), Limit: 3, CountOnly: true})
+	assert.Equal(t, int64(3), result.Count)
+	assert.Equal(t, []byte("k4"), result.Next)
+	result = query(sdk.SubspaceQuery{Subspace: []byte("k"), Start: result.Next, Limit: 3, CountOnly: true})
+	assert.Equal(t, int64(1), result.Count)
+	assert.Empty(t, result.Next)
+
+	// the start key must be in the subspace
+	qres := iavlStore.Query(abci.RequestQuery{
+		Path: "/subspace",
+		Data: cdc.MustMarshalBinary(sdk.SubspaceQuery{Subspace: []byte("k"), Start: []byte("other")}),
+	})
+	assert.NotEqual(t, uint32(sdk.CodeOK), qres.Code)
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tendermint/iavl"
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// MultiStoreProof proves a query of a substore against the app hash.
+// KeyProof is the IAVL proof of the key up to the root of the substore,
+// and StoreInfos are the commit ids of every substore at the version,
+// hashing to the app hash committed in the header of the next block.
+type MultiStoreProof struct {
+	StoreName  string
+	StoreInfos []storeInfo
+	KeyProof   []byte
+}
+
+// add the proof of the substore root to the IAVL proof of a query
+func newMultiStoreProof(db dbm.DB, storeName string, version int64, keyProof []byte) (MultiStoreProof, error) {
+	cInfo, err := getCommitInfo(db, version)
+	if err != nil {
+		return MultiStoreProof{}, err
+	}
+	return MultiStoreProof{
+		StoreName:  storeName,
+		StoreInfos: cInfo.StoreInfos,
+		KeyProof:   keyProof,
+	}, nil
+}
+
+// VerifyMultiStoreProof verifies the value of the key in the substore
+// against the app hash. A nil value verifies the absence of the key.
+func VerifyMultiStoreProof(proofBytes []byte, storeName string, key, value, appHash []byte) error {
+	var proof MultiStoreProof
+	err := cdc.UnmarshalBinary(proofBytes, &proof)
+	if err != nil {
+		return fmt.Errorf("Failed to decode the multistore proof: %v", err)
+	}
+	if proof.StoreName != storeName {
+		return fmt.Errorf("Proof is for store %s, not %s", proof.StoreName, storeName)
+	}
+
+	// the commit ids of the substores must hash to the app hash
+	cInfo := commitInfo{StoreInfos: proof.StoreInfos}
+	if !bytes.Equal(cInfo.Hash(), appHash) {
+		return fmt.Errorf("Substores hash %X, the app hash is %X", cInfo.Hash(), appHash)
+	}
+	var root []byte
+	for _, si := range proof.StoreInfos {
+		if si.Name == storeName {
+			root = si.Core.CommitID.Hash
+		}
+	}
+	if root == nil {
+		return fmt.Errorf("No store %s in the proof", storeName)
+	}
+
+	// and the key proof to the root of the substore
+	keyProof, err := iavl.ReadKeyProof(proof.KeyProof)
+	if err != nil {
+		return fmt.Errorf("Failed to decode the key proof: %v", err)
+	}
+	return keyProof.Verify(key, value, root)
+}
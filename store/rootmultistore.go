@@ -10,7 +10,7 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/merkle"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 const (
@@ -201,7 +201,7 @@ func (rs *rootMultiStore) getStoreByName(name string) Store {
 // Query calls substore.Query with the same `req` where `req.Path` is
 // modified to remove the substore prefix.
 // Ie. `req.Path` here is `/<substore>/<path>`, and trimmed to `/<path>` for the substore.
-// TODO: add proof for `multistore -> substore`.
+// The proof of the substore is wrapped into a MultiStoreProof up to the app hash.
 func (rs *rootMultiStore) Query(req abci.RequestQuery) abci.ResponseQuery {
 	// Query just routes this to a substore.
 	path := req.Path
@@ -224,6 +224,15 @@ func (rs *rootMultiStore) Query(req abci.RequestQuery) abci.ResponseQuery {
 	// trim the path and make the query
 	req.Path = subpath
 	res := queryable.Query(req)
+	if !req.Prove || len(res.Proof) == 0 {
+		return res
+	}
+
+	proof, perr := newMultiStoreProof(rs.db, storeName, res.Height, res.Proof)
+	if perr != nil {
+		return sdk.ErrInternal(perr.Error()).QueryResult()
+	}
+	res.Proof = cdc.MustMarshalBinary(proof)
 	return res
 }
 
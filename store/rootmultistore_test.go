@@ -8,7 +8,7 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/merkle"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 const useDebugDB = false
@@ -200,3 +200,43 @@ func hashStores(stores map[StoreKey]CommitStore) []byte {
 	}
 	return merkle.SimpleHashFromMap(m)
 }
+
+func TestMultiStoreQueryProof(t *testing.T) {
+	db := dbm.NewMemDB()
+	multi := newMultiStoreWithMounts(db)
+	err := multi.LoadLatestVersion()
+	assert.Nil(t, err)
+
+	k, v := []byte("wind"), []byte("blows")
+	k2 := []byte("water")
+	store1 := multi.getStoreByName("store1").(KVStore)
+	store1.Set(k, v)
+	cid := multi.Commit()
+
+	// the value is proven against the hash of the multistore
+	query := abci.RequestQuery{Path: "/store1/key", Data: k, Height: cid.Version, Prove: true}
+	qres := multi.Query(query)
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeOK), sdk.ABCICodeType(qres.Code))
+	assert.Equal(t, v, qres.Value)
+	assert.Nil(t, VerifyMultiStoreProof(qres.Proof, "store1", k, v, cid.Hash))
+
+	// but not another value, store or hash
+	assert.NotNil(t, VerifyMultiStoreProof(qres.Proof, "store1", k, []byte("is cold"), cid.Hash))
+	assert.NotNil(t, VerifyMultiStoreProof(qres.Proof, "store2", k, v, cid.Hash))
+	assert.NotNil(t, VerifyMultiStoreProof(qres.Proof, "store1", k, v, []byte("garbage")))
+
+	// the absence of a key is proven too
+	query.Data = k2
+	qres = multi.Query(query)
+	assert.Nil(t, qres.Value)
+	assert.Nil(t, VerifyMultiStoreProof(qres.Proof, "store1", k2, nil, cid.Hash))
+	assert.NotNil(t, VerifyMultiStoreProof(qres.Proof, "store1", k2, v, cid.Hash))
+
+	// proofs of an old version hold against its hash
+	store1.Set(k, []byte("is cold"))
+	multi.Commit()
+	query.Data = k
+	qres = multi.Query(query)
+	assert.Equal(t, v, qres.Value)
+	assert.Nil(t, VerifyMultiStoreProof(qres.Proof, "store1", k, v, cid.Hash))
+}
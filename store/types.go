@@ -1,8 +1,8 @@
 package store
 
 import (
-	"github.com/cosmos/cosmos-sdk/types"
 	cmn "github.com/tendermint/tmlibs/common"
+	"inschain-tendermint/types"
 )
 
 // Import cosmos-sdk/types/store.go for convenience.
@@ -1,7 +1,7 @@
 package store
 
 import (
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/wire"
 )
 
 var cdc = wire.NewCodec()
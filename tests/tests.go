@@ -13,7 +13,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/cosmos/cosmos-sdk/server"
+	"inschain-tendermint/server"
 	"github.com/stretchr/testify/require"
 )
 
@@ -43,6 +43,8 @@ func NewContext(ms MultiStore, header abci.Header, isCheckTx bool, txBytes []byt
 	c = c.WithIsCheckTx(isCheckTx)
 	c = c.WithTxBytes(txBytes)
 	c = c.WithLogger(logger)
+	c = c.WithGasMeter(NewInfiniteGasMeter())
+	c = c.WithMinGasPrices(nil)
 	return c
 }
 
@@ -67,10 +69,13 @@ func (c Context) Value(key interface{}) interface{} {
 }
 
 // KVStore fetches a KVStore from the MultiStore.
+// Its operations consume gas from the GasMeter of the context.
 func (c Context) KVStore(key StoreKey) KVStore {
-	return c.multiStore().GetKVStore(key)
+	return c.multiStore().GetKVStore(key).Gas(c.GasMeter(), cachedKVGasConfig)
 }
 
+var cachedKVGasConfig = KVGasConfig()
+
 //----------------------------------------
 // With* (setting a value)
 
@@ -127,6 +132,8 @@ const (
 	contextKeyIsCheckTx
 	contextKeyTxBytes
 	contextKeyLogger
+	contextKeyGasMeter
+	contextKeyMinGasPrices
 )
 
 // NOTE: Do not expose MultiStore.
@@ -155,6 +162,12 @@ func (c Context) TxBytes() []byte {
 func (c Context) Logger() log.Logger {
 	return c.Value(contextKeyLogger).(log.Logger)
 }
+func (c Context) GasMeter() GasMeter {
+	return c.Value(contextKeyGasMeter).(GasMeter)
+}
+func (c Context) MinGasPrices() GasPrices {
+	return c.Value(contextKeyMinGasPrices).(GasPrices)
+}
 func (c Context) WithMultiStore(ms MultiStore) Context {
 	return c.withValue(contextKeyMultiStore, ms)
 }
@@ -177,6 +190,12 @@ func (c Context) WithTxBytes(txBytes []byte) Context {
 func (c Context) WithLogger(logger log.Logger) Context {
 	return c.withValue(contextKeyLogger, logger)
 }
+func (c Context) WithGasMeter(meter GasMeter) Context {
+	return c.withValue(contextKeyGasMeter, meter)
+}
+func (c Context) WithMinGasPrices(prices GasPrices) Context {
+	return c.withValue(contextKeyMinGasPrices, prices)
+}
 
 // Cache the multistore and return a new cached context. The cached context is
 // written to the context when writeCache is called.
@@ -9,9 +9,9 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	"github.com/cosmos/cosmos-sdk/store"
-	"github.com/cosmos/cosmos-sdk/types"
 	abci "github.com/tendermint/abci/types"
+	"inschain-tendermint/store"
+	"inschain-tendermint/types"
 )
 
 type MockLogger struct {
@@ -52,6 +52,9 @@ const (
 	CodeUnknownAddress    CodeType = 9
 	CodeInsufficientCoins CodeType = 10
 	CodeInvalidCoins      CodeType = 11
+	CodeOutOfGas          CodeType = 12
+	CodeBlockGasLimit     CodeType = 13
+	CodeInsufficientFee   CodeType = 14
 
 	// CodespaceRoot is a codespace for error codes in this file only.
 	// Notice that 0 is an "unset" codespace, which can be overridden with
@@ -88,6 +91,12 @@ func CodeToDefaultMsg(code CodeType) string {
 		return "Insufficient coins"
 	case CodeInvalidCoins:
 		return "Invalid coins"
+	case CodeOutOfGas:
+		return "Out of gas"
+	case CodeBlockGasLimit:
+		return "Block gas limit exceeded"
+	case CodeInsufficientFee:
+		return "Insufficient fee"
 	default:
 		return fmt.Sprintf("Unknown code %d", code)
 	}
@@ -131,6 +140,15 @@ func ErrInsufficientCoins(msg string) Error {
 func ErrInvalidCoins(msg string) Error {
 	return newErrorWithRootCodespace(CodeInvalidCoins, msg)
 }
+func ErrOutOfGas(msg string) Error {
+	return newErrorWithRootCodespace(CodeOutOfGas, msg)
+}
+func ErrBlockGasLimit(msg string) Error {
+	return newErrorWithRootCodespace(CodeBlockGasLimit, msg)
+}
+func ErrInsufficientFee(msg string) Error {
+	return newErrorWithRootCodespace(CodeInsufficientFee, msg)
+}
 
 //----------------------------------------
 // Error & sdkError
@@ -0,0 +1,100 @@
+package types
+
+import (
+	"math"
+)
+
+// Gas measures the work done by a transaction
+type Gas = int64
+
+// ErrorOutOfGas is the panic value of a GasMeter running out of gas.
+// It is recovered by the BaseApp, which reverts the transaction.
+type ErrorOutOfGas struct {
+	Descriptor string
+}
+
+// GasMeter tracks the gas consumed by a transaction
+type GasMeter interface {
+	GasConsumed() Gas
+	Limit() Gas
+
+	// ConsumeGas panics with ErrorOutOfGas if the limit is exceeded
+	ConsumeGas(amount Gas, descriptor string)
+}
+
+type basicGasMeter struct {
+	limit    Gas
+	consumed Gas
+}
+
+// NewGasMeter returns a GasMeter with the given limit
+func NewGasMeter(limit Gas) GasMeter {
+	return &basicGasMeter{
+		limit: limit,
+	}
+}
+
+func (g *basicGasMeter) GasConsumed() Gas {
+	return g.consumed
+}
+
+func (g *basicGasMeter) Limit() Gas {
+	return g.limit
+}
+
+func (g *basicGasMeter) ConsumeGas(amount Gas, descriptor string) {
+	if amount > g.limit-g.consumed {
+		g.consumed = g.limit
+		panic(ErrorOutOfGas{descriptor})
+	}
+	g.consumed += amount
+}
+
+type infiniteGasMeter struct {
+	consumed Gas
+}
+
+// NewInfiniteGasMeter returns a GasMeter which only counts the gas,
+// for contexts outside of transactions (genesis, begin/end block, queries)
+func NewInfiniteGasMeter() GasMeter {
+	return &infiniteGasMeter{}
+}
+
+func (g *infiniteGasMeter) GasConsumed() Gas {
+	return g.consumed
+}
+
+func (g *infiniteGasMeter) Limit() Gas {
+	return math.MaxInt64
+}
+
+func (g *infiniteGasMeter) ConsumeGas(amount Gas, descriptor string) {
+	g.consumed += amount
+}
+
+// GasConfig defines the gas cost of the KVStore operations
+type GasConfig struct {
+	HasCost          Gas
+	DeleteCost       Gas
+	ReadCostFlat     Gas
+	ReadCostPerByte  Gas
+	WriteCostFlat    Gas
+	WriteCostPerByte Gas
+	IterNextCostFlat Gas
+	ValueCostPerByte Gas
+}
+
+// KVGasConfig returns the default gas costs of the KVStore operations.
+// Writes cost more than reads, as they grow the state of every node.
+func KVGasConfig() GasConfig {
+	return GasConfig{
+		HasCost:          10,
+		DeleteCost:       10,
+		ReadCostFlat:     10,
+		ReadCostPerByte:  1,
+		WriteCostFlat:    10,
+		WriteCostPerByte: 10,
+		IterNextCostFlat: 5,
+		ValueCostPerByte: 1,
+	}
+}
@@ -0,0 +1,104 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GasPrice is the price of a unit of gas in a denomination
+type GasPrice struct {
+	Denom  string `json:"denom"`
+	Amount Rat    `json:"amount"`
+}
+
+func (price GasPrice) String() string {
+	amount := strings.TrimRight(price.Amount.Rat.FloatString(18), "0")
+	return strings.TrimSuffix(amount, ".") + price.Denom
+}
+
+// GasPrices is a set of gas prices, sorted by denomination.
+// A fee satisfies the prices if it pays for the gas in any one of them.
+type GasPrices []GasPrice
+
+func (prices GasPrices) String() string {
+	strs := make([]string, len(prices))
+	for i, price := range prices {
+		strs[i] = price.String()
+	}
+	return strings.Join(strs, ",")
+}
+
+// IsZero returns true if no gas price is set
+func (prices GasPrices) IsZero() bool {
+	for _, price := range prices {
+		if !price.Amount.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// Fees returns the fees paying for the gas in every denomination,
+// rounded up to the next coin
+func (prices GasPrices) Fees(gas Gas) Coins {
+	fees := make(Coins, 0, len(prices))
+	gasRat := new(big.Rat).SetInt64(gas)
+	for _, price := range prices {
+		total := new(big.Rat).Mul(&price.Amount.Rat, gasRat)
+		amount, rem := new(big.Int).QuoRem(total.Num(), total.Denom(), new(big.Int))
+		if rem.Sign() > 0 {
+			amount.Add(amount, big.NewInt(1))
+		}
+		fees = append(fees, Coin{price.Denom, amount.Int64()})
+	}
+	return fees
+}
+
+// IsPaidBy returns true if the fee pays for the gas
+// in any of the denominations of the prices
+func (prices GasPrices) IsPaidBy(fee Coins, gas Gas) bool {
+	if prices.IsZero() {
+		return true
+	}
+	for _, required := range prices.Fees(gas) {
+		if fee.AmountOf(required.Denom) >= required.Amount {
+			return true
+		}
+	}
+	return false
+}
+
+var reGasPrice = regexp.MustCompile(fmt.Sprintf(`^([[:digit:]]+(?:\.[[:digit:]]+)?)%s(%s)$`, reSpc, reDnm))
+
+// ParseGasPrices parses a list of gas prices separated by commas,
+// eg. "0.025steak,1photino". Returned prices are sorted.
+func ParseGasPrices(pricesStr string) (prices GasPrices, err error) {
+	pricesStr = strings.TrimSpace(pricesStr)
+	if len(pricesStr) == 0 {
+		return nil, nil
+	}
+
+	for _, priceStr := range strings.Split(pricesStr, ",") {
+		matches := reGasPrice.FindStringSubmatch(strings.TrimSpace(priceStr))
+		if matches == nil {
+			return nil, fmt.Errorf("Invalid gas price expression: %s", priceStr)
+		}
+		amount, err := NewRatFromDecimal(matches[1])
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, GasPrice{matches[2], amount})
+	}
+
+	// Sort prices for determinism, and reject duplicates.
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Denom < prices[j].Denom })
+	for i := 1; i < len(prices); i++ {
+		if prices[i].Denom == prices[i-1].Denom {
+			return nil, fmt.Errorf("Duplicate gas price denomination: %s", prices[i].Denom)
+		}
+	}
+	return prices, nil
+}
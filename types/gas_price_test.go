@@ -0,0 +1,53 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGasPrices(t *testing.T) {
+	cases := []struct {
+		input string
+		valid bool
+		str   string
+	}{
+		{"", true, ""},
+		{"1steak", true, "1steak"},
+		{"0.025steak", true, "0.025steak"},
+		{"0.025steak, 1photon", true, "1photon,0.025steak"},
+		{"0.025 steak", true, "0.025steak"},
+		{"-1steak", false, ""},
+		{"1.steak", false, ""},
+		{"0.025steak,1steak", false, ""},
+		{"steak", false, ""},
+	}
+
+	for _, tc := range cases {
+		prices, err := ParseGasPrices(tc.input)
+		if !tc.valid {
+			assert.NotNil(t, err, "%s should fail", tc.input)
+			continue
+		}
+		require.Nil(t, err, "%s: %v", tc.input, err)
+		assert.Equal(t, tc.str, prices.String())
+	}
+}
+
+func TestGasPricesFees(t *testing.T) {
+	prices, err := ParseGasPrices("0.025steak,1photon")
+	require.Nil(t, err)
+
+	// fees are rounded up
+	assert.Equal(t, Coins{{"photon", 10}, {"steak", 1}}, prices.Fees(10))
+	assert.Equal(t, Coins{{"photon", 200}, {"steak", 5}}, prices.Fees(200))
+
+	// the fee may pay in any denomination
+	assert.True(t, prices.IsPaidBy(Coins{{"steak", 5}}, 200))
+	assert.True(t, prices.IsPaidBy(Coins{{"photon", 200}}, 200))
+	assert.False(t, prices.IsPaidBy(Coins{{"photon", 199}, {"steak", 4}}, 200))
+
+	// no prices, no fee required
+	assert.True(t, GasPrices(nil).IsPaidBy(nil, 200))
+}
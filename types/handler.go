@@ -1,5 +1,7 @@
 package types
 
+import abci "github.com/tendermint/abci/types"
+
 // core function variable which application runs for transactions
 type Handler func(ctx Context, msg Msg) Result
 
@@ -8,3 +10,6 @@ type FeeHandler func(ctx Context, tx Tx, fee Coins)
 
 // If newCtx.IsZero(), ctx is used instead.
 type AnteHandler func(ctx Context, tx Tx) (newCtx Context, result Result, abort bool)
+
+// core function variable which application runs to answer custom queries
+type Querier func(ctx Context, path []string, req abci.RequestQuery) (res []byte, err Error)
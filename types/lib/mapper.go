@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"strconv"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	wire "github.com/cosmos/cosmos-sdk/wire"
+	sdk "inschain-tendermint/types"
+	wire "inschain-tendermint/wire"
 )
 
 // Mapper defines a primitive mapper type
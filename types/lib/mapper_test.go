@@ -10,9 +10,9 @@ import (
 
 	abci "github.com/tendermint/abci/types"
 
-	"github.com/cosmos/cosmos-sdk/store"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	wire "github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	wire "inschain-tendermint/wire"
 )
 
 type S struct {
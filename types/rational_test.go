@@ -4,9 +4,9 @@ import (
 	"math/big"
 	"testing"
 
-	wire "github.com/cosmos/cosmos-sdk/wire"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	wire "inschain-tendermint/wire"
 )
 
 func TestNew(t *testing.T) {
@@ -1,8 +1,9 @@
 package types
 
 import (
+	"encoding/json"
+
 	abci "github.com/tendermint/abci/types"
-	cmn "github.com/tendermint/tmlibs/common"
 )
 
 // Result is the union of ResponseDeliverTx and ResponseCheckTx.
@@ -15,6 +16,7 @@ type Result struct {
 	Data []byte
 
 	// Log is just debug information. NOTE: nondeterministic.
+	// For a delivered tx, it is the JSON encoding of the MsgLogs.
 	Log string
 
 	// GasWanted is the maximum units of work we allow this tx to perform.
@@ -31,10 +33,42 @@ type Result struct {
 	ValidatorUpdates []abci.Validator
 
 	// Tags are used for transaction indexing and pubsub.
-	Tags []cmn.KVPair
+	Tags Tags
 }
 
 // TODO: In the future, more codes may be OK.
 func (res Result) IsOK() bool {
 	return res.Code.IsOK()
 }
+
+// MsgLog is the log of one of the Msgs of a transaction
+type MsgLog struct {
+	MsgIndex int    `json:"msg_index"`
+	Success  bool   `json:"success"`
+	Log      string `json:"log"`
+
+	// Number of tags and bytes of data the Msg added to the result of
+	// the transaction, which attribute them to it when the block is replayed
+	TagCount   int `json:"tag_count,omitempty"`
+	DataLength int `json:"data_length,omitempty"`
+}
+
+// MsgLogs are the logs of the Msgs run by a transaction, in order.
+// If the transaction failed, the last one is the failing Msg.
+type MsgLogs []MsgLog
+
+// String returns the JSON encoding of the logs
+func (logs MsgLogs) String() string {
+	bz, err := json.Marshal(logs)
+	if err != nil {
+		panic(err)
+	}
+	return string(bz)
+}
+
+// ParseMsgLogs decodes the log of a transaction result
+func ParseMsgLogs(log string) (MsgLogs, error) {
+	var logs MsgLogs
+	err := json.Unmarshal([]byte(log), &logs)
+	return logs, err
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	abci "github.com/tendermint/abci/types"
+	cmn "github.com/tendermint/tmlibs/common"
 	dbm "github.com/tendermint/tmlibs/db"
 )
 
@@ -119,6 +120,9 @@ type KVStore interface {
 	// CONTRACT: No writes may happen within a domain while an iterator exists over it.
 	ReverseSubspaceIterator(prefix []byte) Iterator
 
+	// Gas wraps the store so that every operation consumes gas from the meter.
+	Gas(GasMeter, GasConfig) KVStore
+
 	// TODO Not yet implemented.
 	// CreateSubKVStore(key *storeKey) (KVStore, error)
 
@@ -256,3 +260,33 @@ func PrefixEndBytes(prefix []byte) []byte {
 	}
 	return end
 }
+
+//----------------------------------------
+// Subspace queries
+
+// Bounds of the number of pairs returned by a subspace query
+const (
+	DefaultSubspaceLimit = 100
+	MaxSubspaceLimit     = 1000
+)
+
+// SubspaceQuery is the request of a /subspace query of a store: a page of
+// the pairs with the prefix, from the start key included, in ascending
+// order or descending order if Reverse. Without a start key the page starts
+// at the first pair of the subspace in the order.
+type SubspaceQuery struct {
+	Subspace  []byte `json:"subspace"`
+	Start     []byte `json:"start"`
+	Limit     int    `json:"limit"`
+	Reverse   bool   `json:"reverse"`
+	KeysOnly  bool   `json:"keys_only"`  // omit the values
+	CountOnly bool   `json:"count_only"` // only count the pairs of the page
+}
+
+// SubspaceResult is the response of a /subspace query.
+// Next is the start of the next page, empty on the last page.
+type SubspaceResult struct {
+	KVs   []cmn.KVPair `json:"kvs"`
+	Next  []byte       `json:"next"`
+	Count int64        `json:"count"`
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	cmn "github.com/tendermint/tmlibs/common"
+)
+
+// Tag is a key value pair indexed by Tendermint for transaction search
+type Tag = cmn.KVPair
+
+// Tags is the list of tags attached to a Result
+type Tags cmn.KVPairs
+
+// EmptyTags returns a new empty list of tags
+func EmptyTags() Tags {
+	return Tags{}
+}
+
+// MakeTag creates a single tag
+func MakeTag(key string, value []byte) Tag {
+	return Tag{Key: []byte(key), Value: value}
+}
+
+// AppendTag appends a single tag
+func (t Tags) AppendTag(key string, value []byte) Tags {
+	return append(t, MakeTag(key, value))
+}
+
+// AppendTags appends a list of tags
+func (t Tags) AppendTags(tags Tags) Tags {
+	return append(t, tags...)
+}
+
+// NewTags builds tags from alternating string keys and []byte values,
+// eg. NewTags("action", []byte("send"), "sender", addr.Bytes())
+func NewTags(keyvals ...interface{}) Tags {
+	if len(keyvals)%2 != 0 {
+		panic("odd number of tag arguments")
+	}
+	tags := make(Tags, 0, len(keyvals)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			panic("tag key must be a string")
+		}
+		value, ok := keyvals[i+1].([]byte)
+		if !ok {
+			panic("tag value must be []byte")
+		}
+		tags = tags.AppendTag(key, value)
+	}
+	return tags
+}
@@ -0,0 +1,23 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	tags := NewTags("action", []byte("send"), "sender", []byte("ABCD"))
+	assert.Equal(t, 2, len(tags))
+	assert.Equal(t, []byte("action"), tags[0].Key)
+	assert.Equal(t, []byte("ABCD"), tags[1].Value)
+
+	tags = tags.AppendTag("recipient", []byte("EF01"))
+	tags = tags.AppendTags(EmptyTags().AppendTag("amount", []byte("10getx")))
+	assert.Equal(t, 4, len(tags))
+	assert.Equal(t, []byte("amount"), tags[3].Key)
+
+	assert.Panics(t, func() { NewTags("action") })
+	assert.Panics(t, func() { NewTags(1, []byte("x")) })
+	assert.Panics(t, func() { NewTags("action", "send") })
+}
@@ -29,10 +29,10 @@ type Msg interface {
 // Transactions objects must fulfill the Tx
 type Tx interface {
 
-	// Gets the Msg.
-	GetMsg() Msg
+	// Gets the Msgs, executed atomically in order.
+	GetMsgs() []Msg
 
-	// Signatures returns the signature of signers who signed the Msg.
+	// Signatures returns the signature of signers who signed the Msgs.
 	// CONTRACT: Length returned is same as length of
 	// pubkeys returned from MsgKeySigners, and the order
 	// matches.
@@ -44,31 +44,68 @@ type Tx interface {
 
 var _ Tx = (*StdTx)(nil)
 
-// StdTx is a standard way to wrap a Msg with Fee and Signatures.
-// NOTE: the first signature is the FeePayer (Signatures must not be nil).
+// StdTx is a standard way to wrap Msgs with Fee and Signatures.
+// NOTE: the first signature is the FeePayer (Signatures must not be nil),
+// unless the FeePayer is set: it then pays the fee out of the fee allowance
+// it granted to the first signer.
 type StdTx struct {
-	Msg        `json:"msg"`
+	Msgs       []Msg          `json:"msgs"`
 	Fee        StdFee         `json:"fee"`
 	Signatures []StdSignature `json:"signatures"`
+	FeePayer   Address        `json:"fee_payer"`
 }
 
-func NewStdTx(msg Msg, fee StdFee, sigs []StdSignature) StdTx {
+func NewStdTx(msgs []Msg, fee StdFee, sigs []StdSignature) StdTx {
 	return StdTx{
-		Msg:        msg,
+		Msgs:       msgs,
 		Fee:        fee,
 		Signatures: sigs,
 	}
 }
 
 //nolint
-func (tx StdTx) GetMsg() Msg                   { return tx.Msg }
+func (tx StdTx) GetMsgs() []Msg                { return tx.Msgs }
 func (tx StdTx) GetSignatures() []StdSignature { return tx.Signatures }
 
+// GetSigners returns the addresses that must sign the transaction:
+// the union of the signers of its Msgs, in order of first appearance.
+// CONTRACT: the Signatures of the tx match this order.
+func (tx StdTx) GetSigners() []Address {
+	return MsgsSigners(tx.Msgs)
+}
+
+// MsgsSigners returns the signers of the msgs without duplicates,
+// in order of first appearance.
+func MsgsSigners(msgs []Msg) []Address {
+	seen := make(map[string]bool)
+	var signers []Address
+	for _, msg := range msgs {
+		for _, addr := range msg.GetSigners() {
+			if seen[string(addr)] {
+				continue
+			}
+			seen[string(addr)] = true
+			signers = append(signers, addr)
+		}
+	}
+	return signers
+}
+
+// WithFeePayer returns the tx with the fees paid by the fee payer
+func (tx StdTx) WithFeePayer(feePayer Address) StdTx {
+	tx.FeePayer = feePayer
+	return tx
+}
+
 // FeePayer returns the address responsible for paying the fees
-// for the transactions. It's the first address returned by msg.GetSigners().
-// If GetSigners() is empty, this panics.
+// for the transactions. It's the fee payer of a StdTx if set,
+// or else the first signer of the first Msg.
+// If there is no signer, this panics.
 func FeePayer(tx Tx) Address {
-	return tx.GetMsg().GetSigners()[0]
+	if stdTx, ok := tx.(StdTx); ok && len(stdTx.FeePayer) > 0 {
+		return stdTx.FeePayer
+	}
+	return tx.GetMsgs()[0].GetSigners()[0]
 }
 
 //__________________________________________________________
@@ -107,26 +144,39 @@ func (fee StdFee) Bytes() []byte {
 //__________________________________________________________
 
 // StdSignDoc is replay-prevention structure.
-// It includes the result of msg.GetSignBytes(),
+// It includes the result of msg.GetSignBytes() for every Msg,
 // as well as the ChainID (prevent cross chain replay)
 // and the Sequence numbers for each signature (prevent
 // inchain replay and enforce tx ordering per account).
 type StdSignDoc struct {
-	ChainID   string  `json:"chain_id"`
-	Sequences []int64 `json:"sequences"`
-	FeeBytes  []byte  `json:"fee_bytes"`
-	MsgBytes  []byte  `json:"msg_bytes"`
-	AltBytes  []byte  `json:"alt_bytes"`
+	ChainID   string   `json:"chain_id"`
+	Sequences []int64  `json:"sequences"`
+	FeeBytes  []byte   `json:"fee_bytes"`
+	MsgsBytes [][]byte `json:"msgs_bytes"`
+	AltBytes  []byte   `json:"alt_bytes"`
+	FeePayer  Address  `json:"fee_payer,omitempty"`
 }
 
 // StdSignBytes returns the bytes to sign for a transaction.
 // TODO: change the API to just take a chainID and StdTx ?
-func StdSignBytes(chainID string, sequences []int64, fee StdFee, msg Msg) []byte {
+func StdSignBytes(chainID string, sequences []int64, fee StdFee, msgs []Msg) []byte {
+	return StdSignBytesWithFeePayer(chainID, sequences, fee, nil, msgs)
+}
+
+// StdSignBytesWithFeePayer returns the bytes to sign for a transaction
+// of which the fees are paid by the fee payer. Without a fee payer,
+// they are the same as StdSignBytes.
+func StdSignBytesWithFeePayer(chainID string, sequences []int64, fee StdFee, feePayer Address, msgs []Msg) []byte {
+	msgsBytes := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		msgsBytes[i] = msg.GetSignBytes()
+	}
 	bz, err := json.Marshal(StdSignDoc{
 		ChainID:   chainID,
 		Sequences: sequences,
 		FeeBytes:  fee.Bytes(),
-		MsgBytes:  msg.GetSignBytes(),
+		MsgsBytes: msgsBytes,
+		FeePayer:  feePayer,
 	})
 	if err != nil {
 		panic(err)
@@ -135,19 +185,20 @@ func StdSignBytes(chainID string, sequences []int64, fee StdFee, msg Msg) []byte
 }
 
 // StdSignMsg is a convenience structure for passing along
-// a Msg with the other requirements for a StdSignDoc before
-// it is signed. For use in the CLI.
+// the Msgs with the other requirements for a StdSignDoc before
+// they are signed. For use in the CLI.
 type StdSignMsg struct {
-	ChainID   string
-	Sequences []int64
-	Fee       StdFee
-	Msg       Msg
+	ChainID   string  `json:"chain_id"`
+	Sequences []int64 `json:"sequences"`
+	Fee       StdFee  `json:"fee"`
+	FeePayer  Address `json:"fee_payer,omitempty"`
+	Msgs      []Msg   `json:"msgs"`
 	// XXX: Alt
 }
 
 // get message bytes
 func (msg StdSignMsg) Bytes() []byte {
-	return StdSignBytes(msg.ChainID, msg.Sequences, msg.Fee, msg.Msg)
+	return StdSignBytesWithFeePayer(msg.ChainID, msg.Sequences, msg.Fee, msg.FeePayer, msg.Msgs)
 }
 
 //__________________________________________________________
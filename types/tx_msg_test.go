@@ -21,10 +21,42 @@ func TestStdTx(t *testing.T) {
 	fee := newStdFee()
 	sigs := []StdSignature{}
 
-	tx := NewStdTx(msg, fee, sigs)
-	assert.Equal(t, msg, tx.GetMsg())
+	tx := NewStdTx([]Msg{msg}, fee, sigs)
+	assert.Equal(t, []Msg{msg}, tx.GetMsgs())
 	assert.Equal(t, sigs, tx.GetSignatures())
 
 	feePayer := FeePayer(tx)
 	assert.Equal(t, addr, feePayer)
+
+	// a fee payer pays instead of the first signer,
+	// and is covered by the signatures
+	payer := crypto.GenPrivKeyEd25519().PubKey().Address()
+	assert.Equal(t, payer, FeePayer(tx.WithFeePayer(payer)))
+	assert.Equal(t,
+		StdSignBytes("chain", nil, fee, tx.Msgs),
+		StdSignBytesWithFeePayer("chain", nil, fee, nil, tx.Msgs))
+	assert.NotEqual(t,
+		StdSignBytes("chain", nil, fee, tx.Msgs),
+		StdSignBytesWithFeePayer("chain", nil, fee, payer, tx.Msgs))
+}
+
+func TestStdTxSigners(t *testing.T) {
+	addr1 := crypto.GenPrivKeyEd25519().PubKey().Address()
+	addr2 := crypto.GenPrivKeyEd25519().PubKey().Address()
+	addr3 := crypto.GenPrivKeyEd25519().PubKey().Address()
+	msgs := []Msg{
+		NewTestMsg(addr2, addr1),
+		NewTestMsg(addr1, addr3),
+		NewTestMsg(addr3),
+	}
+
+	tx := NewStdTx(msgs, newStdFee(), nil)
+	assert.Equal(t, []Address{addr2, addr1, addr3}, tx.GetSigners())
+	assert.Equal(t, addr2, FeePayer(tx))
+
+	// the sign bytes cover every msg, in order
+	reversed := []Msg{msgs[2], msgs[1], msgs[0]}
+	assert.NotEqual(t,
+		StdSignBytes("chain", nil, newStdFee(), msgs),
+		StdSignBytes("chain", nil, newStdFee(), reversed))
 }
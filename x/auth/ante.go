@@ -4,14 +4,30 @@ import (
 	"bytes"
 	"fmt"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/spf13/viper"
+	sdk "inschain-tendermint/types"
 )
 
+// FeeGrantKeeper checks the fee allowances granters gave to grantees.
+// UseGrantedFees checks the allowance of the granter to the grantee covers
+// the fee of the msgs, and deducts the fee from the allowance.
+type FeeGrantKeeper interface {
+	UseGrantedFees(ctx sdk.Context, granter, grantee sdk.Address, fee sdk.Coins, msgs []sdk.Msg) sdk.Error
+}
+
 // NewAnteHandler returns an AnteHandler that checks
 // and increments sequence numbers, checks signatures,
-// and deducts fees from the first signer.
+// and deducts fees from the first signer, or from
+// the fee payer of the tx if it is a signer.
+// The gas of the tx is metered up to the gas of the fee.
 func NewAnteHandler(accountMapper sdk.AccountMapper, feeHandler sdk.FeeHandler) sdk.AnteHandler {
+	return NewFeeGrantAnteHandler(accountMapper, nil, feeHandler)
+}
+
+// NewFeeGrantAnteHandler returns an AnteHandler like NewAnteHandler,
+// which also deducts fees from a fee payer which is not a signer of
+// the tx, out of the fee allowance it granted to the first signer.
+func NewFeeGrantAnteHandler(accountMapper sdk.AccountMapper, feeGrantKeeper FeeGrantKeeper, feeHandler sdk.FeeHandler) sdk.AnteHandler {
 	return func(
 		ctx sdk.Context, tx sdk.Tx,
 	) (_ sdk.Context, _ sdk.Result, abort bool) {
@@ -24,17 +40,30 @@ func NewAnteHandler(accountMapper sdk.AccountMapper, feeHandler sdk.FeeHandler)
 				true
 		}
 
-		// TODO: can tx just implement message?
-		msg := tx.GetMsg()
-
 		// TODO: will this always be a stdtx? should that be used in the function signature?
 		stdTx, ok := tx.(sdk.StdTx)
 		if !ok {
 			return ctx, sdk.ErrInternal("tx must be sdk.StdTx").Result(), true
 		}
 
+		// Meter the gas of the tx, from here on.
+		ctx = ctx.WithGasMeter(sdk.NewGasMeter(stdTx.Fee.Gas))
+
+		// Check the fee pays the minimum gas prices of this node.
+		// It is only done in CheckTx: the prices are not a consensus rule.
+		if ctx.IsCheckTx() {
+			prices := ctx.MinGasPrices()
+			if !prices.IsPaidBy(stdTx.Fee.Amount, stdTx.Fee.Gas) {
+				return ctx,
+					sdk.ErrInsufficientFee(fmt.Sprintf("Insufficient fee. Got %v, required one of %v",
+						stdTx.Fee.Amount, prices.Fees(stdTx.Fee.Gas))).Result(),
+					true
+			}
+		}
+
 		// Assert that number of signatures is correct.
-		var signerAddrs = msg.GetSigners()
+		// Every signer of any of the msgs signs once.
+		var signerAddrs = stdTx.GetSigners()
 		if len(sigs) != len(signerAddrs) {
 			return ctx,
 				sdk.ErrUnauthorized("wrong number of signers").Result(),
@@ -53,7 +82,9 @@ func NewAnteHandler(accountMapper sdk.AccountMapper, feeHandler sdk.FeeHandler)
 		if chainID == "" {
 			chainID = viper.GetString("chain-id")
 		}
-		signBytes := sdk.StdSignBytes(ctx.ChainID(), sequences, fee, msg)
+		signBytes := sdk.StdSignBytesWithFeePayer(ctx.ChainID(), sequences, fee, stdTx.FeePayer, stdTx.GetMsgs())
+		feePayer := sdk.FeePayer(stdTx)
+		feePaid := fee.Amount.IsZero()
 
 		// Check sig and nonce and collect signer accounts.
 		var signerAccs = make([]sdk.Account, len(signerAddrs))
@@ -69,16 +100,14 @@ func NewAnteHandler(accountMapper sdk.AccountMapper, feeHandler sdk.FeeHandler)
 				return ctx, res, true
 			}
 
-			// first sig pays the fees
-			if i == 0 {
-				// TODO: min fee
-				if !fee.Amount.IsZero() {
-					signerAcc, res = deductFees(signerAcc, fee)
-					feeHandler(ctx, tx, fee.Amount)
-					if !res.IsOK() {
-						return ctx, res, true
-					}
+			// the fee payer pays the fees, the first sig by default
+			if !feePaid && bytes.Equal(signerAddr, feePayer) {
+				signerAcc, res = deductFees(ctx, signerAcc, fee)
+				if !res.IsOK() {
+					return ctx, res, true
 				}
+				feeHandler(ctx, tx, fee.Amount)
+				feePaid = true
 			}
 
 			// Save the account.
@@ -86,12 +115,25 @@ func NewAnteHandler(accountMapper sdk.AccountMapper, feeHandler sdk.FeeHandler)
 			signerAccs[i] = signerAcc
 		}
 
+		// a fee payer which did not sign pays out of its allowance
+		// to the first signer
+		if !feePaid {
+			if feeGrantKeeper == nil {
+				return ctx, sdk.ErrUnauthorized("fee payer is not a signer of the tx").Result(), true
+			}
+			res := payGrantedFees(ctx, accountMapper, feeGrantKeeper, feePayer, signerAddrs[0], fee, stdTx.GetMsgs())
+			if !res.IsOK() {
+				return ctx, res, true
+			}
+			feeHandler(ctx, tx, fee.Amount)
+		}
+
 		// cache the signer accounts in the context
 		ctx = WithSigners(ctx, signerAccs)
 
 		// TODO: tx tags (?)
 
-		return ctx, sdk.Result{}, false // continue...
+		return ctx, sdk.Result{GasWanted: fee.Gas}, false // continue...
 	}
 }
 
@@ -134,7 +176,18 @@ func processSig(
 		}
 	}
 
-	// Check sig.
+	// Check sig. The sig of a multisig account is a multisignature,
+	// its bitmap must reach the threshold of the account's pubkey.
+	if multisig, ok := pubKey.(MultisigThresholdPubKey); ok {
+		msig, ok := sig.Signature.(Multisignature)
+		if !ok {
+			return nil, sdk.ErrUnauthorized("multisig account requires a multisignature").Result()
+		}
+		if len(msig.Signers()) < multisig.Threshold {
+			return nil, sdk.ErrUnauthorized(
+				fmt.Sprintf("Multisignature has %d signatures, threshold is %d", len(msig.Signers()), multisig.Threshold)).Result()
+		}
+	}
 	if !pubKey.VerifyBytes(signBytes, sig.Signature) {
 		return nil, sdk.ErrUnauthorized("signature verification failed").Result()
 	}
@@ -145,19 +198,40 @@ func processSig(
 // Deduct the fee from the account.
 // We could use the CoinKeeper (in addition to the AccountMapper,
 // because the CoinKeeper doesn't give us accounts), but it seems easier to do this.
-func deductFees(acc sdk.Account, fee sdk.StdFee) (sdk.Account, sdk.Result) {
+// The fee is paid with the spendable coins, the locked coins of a vesting account can't pay it.
+func deductFees(ctx sdk.Context, acc sdk.Account, fee sdk.StdFee) (sdk.Account, sdk.Result) {
 	coins := acc.GetCoins()
 	feeAmount := fee.Amount
 
-	newCoins := coins.Minus(feeAmount)
-	if !newCoins.IsNotNegative() {
-		errMsg := fmt.Sprintf("%s < %s", coins, feeAmount)
+	spendable := SpendableCoins(acc, ctx.BlockHeader().Time)
+	if !spendable.IsGTE(feeAmount) {
+		errMsg := fmt.Sprintf("%s < %s", spendable, feeAmount)
 		return nil, sdk.ErrInsufficientFunds(errMsg).Result()
 	}
-	acc.SetCoins(newCoins)
+	acc.SetCoins(coins.Minus(feeAmount))
 	return acc, sdk.Result{}
 }
 
+// Deduct the fee from the fee payer, out of the fee allowance it granted to the grantee.
+func payGrantedFees(ctx sdk.Context, am sdk.AccountMapper, fgk FeeGrantKeeper,
+	feePayer, grantee sdk.Address, fee sdk.StdFee, msgs []sdk.Msg) sdk.Result {
+
+	payerAcc := am.GetAccount(ctx, feePayer)
+	if payerAcc == nil {
+		return sdk.ErrUnknownAddress(feePayer.String()).Result()
+	}
+	payerAcc, res := deductFees(ctx, payerAcc, fee)
+	if !res.IsOK() {
+		return res
+	}
+	err := fgk.UseGrantedFees(ctx, feePayer, grantee, fee.Amount, msgs)
+	if err != nil {
+		return err.Result()
+	}
+	am.SetAccount(ctx, payerAcc)
+	return sdk.Result{}
+}
+
 // BurnFeeHandler burns all fees (decreasing total supply)
 func BurnFeeHandler(ctx sdk.Context, tx sdk.Tx, fee sdk.Coins) {
 }
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,8 +10,8 @@ import (
 	crypto "github.com/tendermint/go-crypto"
 	"github.com/tendermint/tmlibs/log"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	wire "github.com/cosmos/cosmos-sdk/wire"
+	sdk "inschain-tendermint/types"
+	wire "inschain-tendermint/wire"
 )
 
 func newTestMsg(addrs ...sdk.Address) *sdk.TestMsg {
@@ -18,7 +19,7 @@ func newTestMsg(addrs ...sdk.Address) *sdk.TestMsg {
 }
 
 func newStdFee() sdk.StdFee {
-	return sdk.NewStdFee(100,
+	return sdk.NewStdFee(100000,
 		sdk.Coin{"atom", 150},
 	)
 }
@@ -53,16 +54,20 @@ func checkInvalidTx(t *testing.T, anteHandler sdk.AnteHandler, ctx sdk.Context,
 }
 
 func newTestTx(ctx sdk.Context, msg sdk.Msg, privs []crypto.PrivKey, seqs []int64, fee sdk.StdFee) sdk.Tx {
-	signBytes := sdk.StdSignBytes(ctx.ChainID(), seqs, fee, msg)
-	return newTestTxWithSignBytes(msg, privs, seqs, fee, signBytes)
+	return newTestTxMsgs(ctx, []sdk.Msg{msg}, privs, seqs, fee)
 }
 
-func newTestTxWithSignBytes(msg sdk.Msg, privs []crypto.PrivKey, seqs []int64, fee sdk.StdFee, signBytes []byte) sdk.Tx {
+func newTestTxMsgs(ctx sdk.Context, msgs []sdk.Msg, privs []crypto.PrivKey, seqs []int64, fee sdk.StdFee) sdk.Tx {
+	signBytes := sdk.StdSignBytes(ctx.ChainID(), seqs, fee, msgs)
+	return newTestTxWithSignBytes(msgs, privs, seqs, fee, signBytes)
+}
+
+func newTestTxWithSignBytes(msgs []sdk.Msg, privs []crypto.PrivKey, seqs []int64, fee sdk.StdFee, signBytes []byte) sdk.Tx {
 	sigs := make([]sdk.StdSignature, len(privs))
 	for i, priv := range privs {
 		sigs[i] = sdk.StdSignature{PubKey: priv.PubKey(), Signature: priv.Sign(signBytes), Sequence: seqs[i]}
 	}
-	tx := sdk.NewStdTx(msg, fee, sigs)
+	tx := sdk.NewStdTx(msgs, fee, sigs)
 	return tx
 }
 
@@ -173,6 +178,60 @@ func TestAnteHandlerSequences(t *testing.T) {
 	checkValidTx(t, anteHandler, ctx, tx)
 }
 
+// Test that a tx with many msgs is signed once by each of their signers.
+func TestAnteHandlerMultiMsg(t *testing.T) {
+	// setup
+	ms, capKey := setupMultiStore()
+	cdc := wire.NewCodec()
+	RegisterBaseAccount(cdc)
+	mapper := NewAccountMapper(cdc, capKey, &BaseAccount{})
+	anteHandler := NewAnteHandler(mapper, BurnFeeHandler)
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid"}, false, nil, log.NewNopLogger())
+
+	// keys and addresses
+	priv1, addr1 := privAndAddr()
+	priv2, addr2 := privAndAddr()
+	priv3, addr3 := privAndAddr()
+
+	// set the accounts
+	for _, addr := range []sdk.Address{addr1, addr2, addr3} {
+		acc := mapper.NewAccountWithAddress(ctx, addr)
+		acc.SetCoins(newCoins())
+		mapper.SetAccount(ctx, acc)
+	}
+
+	var tx sdk.Tx
+	msgs := []sdk.Msg{newTestMsg(addr1, addr2), newTestMsg(addr3, addr1)}
+	fee := newStdFee()
+
+	// the rejected txs run on a cache of the state which is discarded, as
+	// in the baseapp, so they don't bump the sequences of the first signers
+
+	// a signature per msg signer is rejected
+	privs, seqs := []crypto.PrivKey{priv1, priv2, priv3, priv1}, []int64{0, 0, 0, 0}
+	tx = newTestTxMsgs(ctx, msgs, privs, seqs, fee)
+	cacheCtx, _ := ctx.CacheContext()
+	checkInvalidTx(t, anteHandler, cacheCtx, tx, sdk.CodeUnauthorized)
+
+	// signatures in the wrong order don't match the pubkeys of the signers
+	privs, seqs = []crypto.PrivKey{priv1, priv3, priv2}, []int64{0, 0, 0}
+	tx = newTestTxMsgs(ctx, msgs, privs, seqs, fee)
+	cacheCtx, _ = ctx.CacheContext()
+	checkInvalidTx(t, anteHandler, cacheCtx, tx, sdk.CodeInvalidPubKey)
+
+	// one signature per distinct signer passes, the first one pays the fee
+	privs, seqs = []crypto.PrivKey{priv1, priv2, priv3}, []int64{0, 0, 0}
+	tx = newTestTxMsgs(ctx, msgs, privs, seqs, fee)
+	checkValidTx(t, anteHandler, ctx, tx)
+	assert.Equal(t, newCoins().Minus(fee.Amount), mapper.GetAccount(ctx, addr1).GetCoins())
+	assert.Equal(t, newCoins(), mapper.GetAccount(ctx, addr3).GetCoins())
+
+	// the signature covers all the msgs
+	tx = newTestTxWithSignBytes(msgs, privs, []int64{1, 1, 1}, fee,
+		sdk.StdSignBytes(ctx.ChainID(), []int64{1, 1, 1}, fee, msgs[:1]))
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+}
+
 // Test logic around fee deduction.
 func TestAnteHandlerFees(t *testing.T) {
 	// setup
@@ -194,7 +253,7 @@ func TestAnteHandlerFees(t *testing.T) {
 	var tx sdk.Tx
 	msg := newTestMsg(addr1)
 	privs, seqs := []crypto.PrivKey{priv1}, []int64{0}
-	fee := sdk.NewStdFee(100,
+	fee := sdk.NewStdFee(100000,
 		sdk.Coin{"atom", 150},
 	)
 
@@ -211,6 +270,117 @@ func TestAnteHandlerFees(t *testing.T) {
 	checkValidTx(t, anteHandler, ctx, tx)
 }
 
+// fee grant keeper of a single allowance for testing
+type testFeeGrantKeeper struct {
+	granter, grantee sdk.Address
+	allowance        sdk.Coins
+}
+
+func (k *testFeeGrantKeeper) UseGrantedFees(ctx sdk.Context, granter, grantee sdk.Address, fee sdk.Coins, msgs []sdk.Msg) sdk.Error {
+	if !bytes.Equal(granter, k.granter) || !bytes.Equal(grantee, k.grantee) || !k.allowance.IsGTE(fee) {
+		return sdk.ErrUnauthorized("no fee allowance")
+	}
+	k.allowance = k.allowance.Minus(fee)
+	return nil
+}
+
+// Test the fees are paid by the fee payer of the tx.
+func TestAnteHandlerFeePayer(t *testing.T) {
+	// setup
+	ms, capKey := setupMultiStore()
+	cdc := wire.NewCodec()
+	RegisterBaseAccount(cdc)
+	mapper := NewAccountMapper(cdc, capKey, &BaseAccount{})
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid"}, false, nil, log.NewNopLogger())
+
+	// keys and addresses
+	priv1, addr1 := privAndAddr()
+	priv2, addr2 := privAndAddr()
+	_, addr3 := privAndAddr()
+
+	// set the accounts, the first signer can't pay the fees
+	acc1 := mapper.NewAccountWithAddress(ctx, addr1)
+	mapper.SetAccount(ctx, acc1)
+	acc2 := mapper.NewAccountWithAddress(ctx, addr2)
+	acc2.SetCoins(newCoins())
+	mapper.SetAccount(ctx, acc2)
+	acc3 := mapper.NewAccountWithAddress(ctx, addr3)
+	acc3.SetCoins(newCoins())
+	mapper.SetAccount(ctx, acc3)
+
+	fee := newStdFee()
+	newFeePayerTx := func(msg sdk.Msg, privs []crypto.PrivKey, seqs []int64, feePayer sdk.Address) sdk.Tx {
+		msgs := []sdk.Msg{msg}
+		signBytes := sdk.StdSignBytesWithFeePayer(ctx.ChainID(), seqs, fee, feePayer, msgs)
+		tx := newTestTxWithSignBytes(msgs, privs, seqs, fee, signBytes)
+		return tx.(sdk.StdTx).WithFeePayer(feePayer)
+	}
+
+	// a fee payer which signed pays the fees
+	anteHandler := NewAnteHandler(mapper, BurnFeeHandler)
+	tx := newFeePayerTx(newTestMsg(addr1, addr2), []crypto.PrivKey{priv1, priv2}, []int64{0, 0}, addr2)
+	checkValidTx(t, anteHandler, ctx, tx)
+	assert.Equal(t, newCoins().Minus(fee.Amount), mapper.GetAccount(ctx, addr2).GetCoins())
+
+	// the fee payer is covered by the signatures
+	tx = newFeePayerTx(newTestMsg(addr1, addr2), []crypto.PrivKey{priv1, priv2}, []int64{1, 1}, addr2)
+	checkInvalidTx(t, anteHandler, ctx, tx.(sdk.StdTx).WithFeePayer(addr3), sdk.CodeUnauthorized)
+
+	// a fee payer which did not sign needs a fee grant keeper
+	tx = newFeePayerTx(newTestMsg(addr1), []crypto.PrivKey{priv1}, []int64{1}, addr3)
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+
+	// and an allowance to the first signer
+	// (the sequence was incremented, as the failed tx state is not reverted here)
+	keeper := &testFeeGrantKeeper{addr3, addr1, fee.Amount}
+	anteHandler = NewFeeGrantAnteHandler(mapper, keeper, BurnFeeHandler)
+	tx = newFeePayerTx(newTestMsg(addr1), []crypto.PrivKey{priv1}, []int64{2}, addr3)
+	checkValidTx(t, anteHandler, ctx, tx)
+	assert.Equal(t, newCoins().Minus(fee.Amount), mapper.GetAccount(ctx, addr3).GetCoins())
+	assert.True(t, keeper.allowance.IsZero())
+
+	// the allowance is used up
+	tx = newFeePayerTx(newTestMsg(addr1), []crypto.PrivKey{priv1}, []int64{3}, addr3)
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+}
+
+// Test the minimum gas prices are only enforced in CheckTx.
+func TestAnteHandlerMinGasPrices(t *testing.T) {
+	// setup
+	ms, capKey := setupMultiStore()
+	cdc := wire.NewCodec()
+	RegisterBaseAccount(cdc)
+	mapper := NewAccountMapper(cdc, capKey, &BaseAccount{})
+	anteHandler := NewAnteHandler(mapper, BurnFeeHandler)
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid"}, false, nil, log.NewNopLogger())
+	prices, err := sdk.ParseGasPrices("0.0015atom,1photon")
+	require.Nil(t, err)
+	checkCtx := ctx.WithIsCheckTx(true).WithMinGasPrices(prices)
+
+	// keys and addresses
+	priv1, addr1 := privAndAddr()
+
+	// set the accounts
+	acc1 := mapper.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(newCoins())
+	mapper.SetAccount(ctx, acc1)
+
+	// msg and signatures
+	var tx sdk.Tx
+	msg := newTestMsg(addr1)
+	privs := []crypto.PrivKey{priv1}
+
+	// 100000 gas at 0.0015atom requires 150atom
+	tx = newTestTx(ctx, msg, privs, []int64{0}, sdk.NewStdFee(100000, sdk.Coin{"atom", 149}))
+	checkInvalidTx(t, anteHandler, checkCtx, tx, sdk.CodeInsufficientFee)
+
+	// the prices are not checked when delivering
+	checkValidTx(t, anteHandler, ctx, tx)
+
+	tx = newTestTx(ctx, msg, privs, []int64{1}, sdk.NewStdFee(100000, sdk.Coin{"atom", 150}))
+	checkValidTx(t, anteHandler, checkCtx, tx)
+}
+
 func TestAnteHandlerBadSignBytes(t *testing.T) {
 	// setup
 	ms, capKey := setupMultiStore()
@@ -267,8 +437,8 @@ func TestAnteHandlerBadSignBytes(t *testing.T) {
 	privs, seqs = []crypto.PrivKey{priv1}, []int64{1}
 	for _, cs := range cases {
 		tx := newTestTxWithSignBytes(
-			msg, privs, seqs, fee,
-			sdk.StdSignBytes(cs.chainID, cs.seqs, cs.fee, cs.msg),
+			[]sdk.Msg{msg}, privs, seqs, fee,
+			sdk.StdSignBytes(cs.chainID, cs.seqs, cs.fee, []sdk.Msg{cs.msg}),
 		)
 		checkInvalidTx(t, anteHandler, ctx, tx, cs.code)
 	}
@@ -336,3 +506,59 @@ func TestAnteHandlerSetPubKey(t *testing.T) {
 	acc2 = mapper.GetAccount(ctx, addr2)
 	assert.Nil(t, acc2.GetPubKey())
 }
+
+// Test a multisig account signs with a multisignature reaching its threshold.
+func TestAnteHandlerMultisig(t *testing.T) {
+	// setup
+	ms, capKey := setupMultiStore()
+	cdc := wire.NewCodec()
+	RegisterBaseAccount(cdc)
+	mapper := NewAccountMapper(cdc, capKey, &BaseAccount{})
+	anteHandler := NewAnteHandler(mapper, BurnFeeHandler)
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid"}, false, nil, log.NewNopLogger())
+
+	// a 2 of 3 multisig account
+	priv1, _ := privAndAddr()
+	priv2, _ := privAndAddr()
+	priv3, _ := privAndAddr()
+	pubkey := NewMultisigThresholdPubKey(2, []crypto.PubKey{priv1.PubKey(), priv2.PubKey(), priv3.PubKey()})
+	addr := pubkey.Address()
+	acc := mapper.NewAccountWithAddress(ctx, addr)
+	acc.SetCoins(newCoins())
+	mapper.SetAccount(ctx, acc)
+
+	msg := newTestMsg(addr)
+	fee := newStdFee()
+	multisigTx := func(seq int64, privs map[int]crypto.PrivKey) sdk.Tx {
+		signBytes := sdk.StdSignBytes(ctx.ChainID(), []int64{seq}, fee, []sdk.Msg{msg})
+		multisig := NewMultisignature(3)
+		for i, priv := range privs {
+			multisig = multisig.AddSignature(i, priv.Sign(signBytes))
+		}
+		sigs := []sdk.StdSignature{{PubKey: pubkey, Signature: multisig, Sequence: seq}}
+		return sdk.NewStdTx([]sdk.Msg{msg}, fee, sigs)
+	}
+
+	// a single signature doesn't reach the threshold
+	tx := multisigTx(0, map[int]crypto.PrivKey{0: priv1})
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+
+	// a signature of another key in the bitmap fails
+	tx = multisigTx(0, map[int]crypto.PrivKey{0: priv1, 1: priv3})
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+
+	// two signatures pass, and set the multisig pubkey
+	tx = multisigTx(0, map[int]crypto.PrivKey{0: priv1, 2: priv3})
+	checkValidTx(t, anteHandler, ctx, tx)
+	require.Equal(t, pubkey, mapper.GetAccount(ctx, addr).GetPubKey())
+
+	// a plain signature of a key fails
+	signBytes := sdk.StdSignBytes(ctx.ChainID(), []int64{1}, fee, []sdk.Msg{msg})
+	sigs := []sdk.StdSignature{{PubKey: priv1.PubKey(), Signature: priv1.Sign(signBytes), Sequence: 1}}
+	tx = sdk.NewStdTx([]sdk.Msg{msg}, fee, sigs)
+	checkInvalidTx(t, anteHandler, ctx, tx, sdk.CodeUnauthorized)
+
+	// all the signatures pass
+	tx = multisigTx(1, map[int]crypto.PrivKey{0: priv1, 1: priv2, 2: priv3})
+	checkValidTx(t, anteHandler, ctx, tx)
+}
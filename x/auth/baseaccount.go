@@ -5,8 +5,8 @@ import (
 
 	"github.com/tendermint/go-crypto"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 )
 
 //-----------------------------------------------------------
@@ -87,5 +87,7 @@ func (acc *BaseAccount) SetSequence(seq int64) error {
 func RegisterBaseAccount(cdc *wire.Codec) {
 	cdc.RegisterInterface((*sdk.Account)(nil), nil)
 	cdc.RegisterConcrete(&BaseAccount{}, "cosmos-sdk/BaseAccount", nil)
+	RegisterVestingAccounts(cdc)
 	wire.RegisterCrypto(cdc)
+	RegisterMultisig(cdc)
 }
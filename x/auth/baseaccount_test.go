@@ -7,8 +7,8 @@ import (
 
 	crypto "github.com/tendermint/go-crypto"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	wire "github.com/cosmos/cosmos-sdk/wire"
+	sdk "inschain-tendermint/types"
+	wire "inschain-tendermint/wire"
 )
 
 func keyPubAddr() (crypto.PrivKey, crypto.PubKey, sdk.Address) {
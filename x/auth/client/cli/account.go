@@ -6,9 +6,9 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 )
 
 // GetAccountCmd for the auth.BaseAccount type
@@ -9,9 +9,9 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	"github.com/cosmos/cosmos-sdk/store"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	wire "github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	wire "inschain-tendermint/wire"
 )
 
 func setupMultiStore() (sdk.MultiStore, *sdk.KVStoreKey) {
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"bytes"
+
+	crypto "github.com/tendermint/go-crypto"
+	"golang.org/x/crypto/ripemd160"
+
+	"inschain-tendermint/wire"
+)
+
+// MultisigThresholdPubKey is an M-of-N threshold public key: a multisignature
+// is valid when at least Threshold of the PubKeys signed. The address of the key
+// is derived from the threshold and the PubKeys, in order.
+type MultisigThresholdPubKey struct {
+	Threshold int             `json:"threshold"`
+	PubKeys   []crypto.PubKey `json:"pubkeys"`
+}
+
+var _ crypto.PubKey = MultisigThresholdPubKey{}
+
+// NewMultisigThresholdPubKey returns the threshold key of the pubkeys,
+// it panics if the threshold is not between 1 and the number of pubkeys
+func NewMultisigThresholdPubKey(threshold int, pubkeys []crypto.PubKey) MultisigThresholdPubKey {
+	if threshold <= 0 || threshold > len(pubkeys) {
+		panic("threshold must be between 1 and the number of pubkeys")
+	}
+	return MultisigThresholdPubKey{threshold, pubkeys}
+}
+
+// Implements crypto.PubKey.
+func (pk MultisigThresholdPubKey) Address() crypto.Address {
+	hasher := ripemd160.New()
+	hasher.Write(pk.Bytes()) // does not error
+	return crypto.Address(hasher.Sum(nil))
+}
+
+// Implements crypto.PubKey.
+func (pk MultisigThresholdPubKey) Bytes() []byte {
+	bz, err := multisigCdc.MarshalBinaryBare(pk)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// Implements crypto.PubKey.
+func (pk MultisigThresholdPubKey) Equals(other crypto.PubKey) bool {
+	return bytes.Equal(pk.Bytes(), other.Bytes())
+}
+
+// VerifyBytes checks the multisignature has a bit set for at least Threshold
+// of the pubkeys, and a valid signature of the msg for every bit set.
+// Implements crypto.PubKey.
+func (pk MultisigThresholdPubKey) VerifyBytes(msg []byte, sig crypto.Signature) bool {
+	multisig, ok := sig.(Multisignature)
+	if !ok {
+		return false
+	}
+	if len(multisig.Bitmap) != bitmapSize(len(pk.PubKeys)) {
+		return false
+	}
+	signed := multisig.Signers()
+	if len(signed) < pk.Threshold || len(signed) != len(multisig.Sigs) {
+		return false
+	}
+	for i, index := range signed {
+		if index >= len(pk.PubKeys) || !pk.PubKeys[index].VerifyBytes(msg, multisig.Sigs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Multisignature holds the signatures of a MultisigThresholdPubKey:
+// bit i of the Bitmap is set when the pubkey i signed, and Sigs holds
+// the signatures of the bits set, in order.
+type Multisignature struct {
+	Bitmap []byte             `json:"bitmap"`
+	Sigs   []crypto.Signature `json:"sigs"`
+}
+
+var _ crypto.Signature = Multisignature{}
+
+// NewMultisignature returns an empty multisignature of n pubkeys
+func NewMultisignature(n int) Multisignature {
+	return Multisignature{Bitmap: make([]byte, bitmapSize(n))}
+}
+
+// Signers returns the indexes of the pubkeys which signed, in order
+func (multisig Multisignature) Signers() []int {
+	var indexes []int
+	for i := 0; i < len(multisig.Bitmap)*8; i++ {
+		if multisig.Bitmap[i/8]&(1<<uint(i%8)) != 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// AddSignature returns the multisignature with the signature of the pubkey
+// at the index, it replaces the previous signature of the pubkey if any
+func (multisig Multisignature) AddSignature(index int, sig crypto.Signature) Multisignature {
+	// the position of the signature is the number of bits set before the index
+	pos := 0
+	for _, i := range multisig.Signers() {
+		if i < index {
+			pos++
+		}
+	}
+
+	bitmap := make([]byte, len(multisig.Bitmap))
+	copy(bitmap, multisig.Bitmap)
+	sigs := make([]crypto.Signature, 0, len(multisig.Sigs)+1)
+	sigs = append(sigs, multisig.Sigs[:pos]...)
+	sigs = append(sigs, sig)
+	if bitmap[index/8]&(1<<uint(index%8)) != 0 {
+		pos++
+	}
+	sigs = append(sigs, multisig.Sigs[pos:]...)
+	bitmap[index/8] |= 1 << uint(index%8)
+	return Multisignature{bitmap, sigs}
+}
+
+// Implements crypto.Signature.
+func (multisig Multisignature) Bytes() []byte {
+	bz, err := multisigCdc.MarshalBinaryBare(multisig)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// Implements crypto.Signature.
+func (multisig Multisignature) IsZero() bool {
+	return len(multisig.Sigs) == 0
+}
+
+// Implements crypto.Signature.
+func (multisig Multisignature) Equals(other crypto.Signature) bool {
+	return bytes.Equal(multisig.Bytes(), other.Bytes())
+}
+
+// number of bytes of the bitmap of n pubkeys
+func bitmapSize(n int) int {
+	return (n + 7) / 8
+}
+
+//----------------------------------------
+// Wire
+
+var multisigCdc = wire.NewCodec()
+
+func init() {
+	wire.RegisterCrypto(multisigCdc)
+	RegisterMultisig(multisigCdc)
+}
+
+// RegisterMultisig registers the multisig pubkey and signature
+// as implementations of the go-crypto interfaces
+func RegisterMultisig(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MultisigThresholdPubKey{}, "auth/MultisigThresholdPubKey", nil)
+	cdc.RegisterConcrete(Multisignature{}, "auth/Multisignature", nil)
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	crypto "github.com/tendermint/go-crypto"
+
+	"inschain-tendermint/wire"
+)
+
+func TestMultisigThresholdPubKey(t *testing.T) {
+	msg := []byte("hello")
+	privs := make([]crypto.PrivKey, 10)
+	pubkeys := make([]crypto.PubKey, 10)
+	for i := range privs {
+		privs[i] = crypto.GenPrivKeyEd25519()
+		pubkeys[i] = privs[i].PubKey()
+	}
+	pubkey := NewMultisigThresholdPubKey(3, pubkeys)
+
+	// the address depends on the threshold and the keys
+	assert.NotEqual(t, pubkey.Address(), NewMultisigThresholdPubKey(2, pubkeys).Address())
+	assert.NotEqual(t, pubkey.Address(), NewMultisigThresholdPubKey(3, pubkeys[1:]).Address())
+	assert.Panics(t, func() { NewMultisigThresholdPubKey(11, pubkeys) })
+	assert.Panics(t, func() { NewMultisigThresholdPubKey(0, pubkeys) })
+
+	// the signatures are added in the order of the bitmap, on two bytes
+	multisig := NewMultisignature(10)
+	multisig = multisig.AddSignature(9, privs[9].Sign(msg))
+	multisig = multisig.AddSignature(2, privs[2].Sign(msg))
+	assert.False(t, pubkey.VerifyBytes(msg, multisig))
+	multisig = multisig.AddSignature(4, privs[4].Sign(msg))
+	assert.Equal(t, []int{2, 4, 9}, multisig.Signers())
+	assert.True(t, pubkey.VerifyBytes(msg, multisig))
+	assert.False(t, pubkey.VerifyBytes([]byte("other"), multisig))
+
+	// replacing a signature keeps the bitmap
+	multisig = multisig.AddSignature(4, privs[5].Sign(msg))
+	assert.Equal(t, []int{2, 4, 9}, multisig.Signers())
+	assert.False(t, pubkey.VerifyBytes(msg, multisig))
+	multisig = multisig.AddSignature(4, privs[4].Sign(msg))
+	assert.True(t, pubkey.VerifyBytes(msg, multisig))
+
+	// a plain signature is not a multisignature
+	assert.False(t, pubkey.VerifyBytes(msg, privs[0].Sign(msg)))
+
+	// the key and the multisignature go through the codec
+	cdc := wire.NewCodec()
+	wire.RegisterCrypto(cdc)
+	RegisterMultisig(cdc)
+	var pk crypto.PubKey
+	bz, err := cdc.MarshalJSON(crypto.PubKey(pubkey))
+	require.Nil(t, err)
+	require.Nil(t, cdc.UnmarshalJSON(bz, &pk))
+	assert.True(t, pubkey.Equals(pk))
+	var sig crypto.Signature
+	bz, err = cdc.MarshalBinary(crypto.Signature(multisig))
+	require.Nil(t, err)
+	require.Nil(t, cdc.UnmarshalBinary(bz, &sig))
+	assert.True(t, pk.VerifyBytes(msg, sig))
+}
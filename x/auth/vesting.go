@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"math/big"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+)
+
+//-----------------------------------------------------------
+// VestingAccount
+
+// VestingAccount is an account of which some coins vest over time.
+// The coins still vesting are locked: they can't be spent,
+// though they can be delegated, eg. bonded into a policy.
+// The block times are unix times in seconds.
+type VestingAccount interface {
+	sdk.Account
+
+	// the coins vested or still vesting at the block time,
+	// out of the original vesting coins
+	GetVestedCoins(blockTime int64) sdk.Coins
+	GetVestingCoins(blockTime int64) sdk.Coins
+
+	// the coins which can be spent at the block time
+	SpendableCoins(blockTime int64) sdk.Coins
+
+	// track the coins delegated out of the account, and undelegated
+	// back to it. The coins of the account are not changed.
+	TrackDelegation(blockTime int64, amount sdk.Coins)
+	TrackUndelegation(amount sdk.Coins)
+
+	GetOriginalVesting() sdk.Coins
+	GetDelegatedFree() sdk.Coins
+	GetDelegatedVesting() sdk.Coins
+	GetEndTime() int64
+}
+
+// BaseVestingAccount - common fields of the vesting accounts.
+// OriginalVesting are the coins vesting when the account was created,
+// DelegatedFree and DelegatedVesting are the vested and vesting coins
+// delegated out of the account.
+type BaseVestingAccount struct {
+	BaseAccount
+	OriginalVesting  sdk.Coins `json:"original_vesting"`
+	DelegatedFree    sdk.Coins `json:"delegated_free"`
+	DelegatedVesting sdk.Coins `json:"delegated_vesting"`
+	EndTime          int64     `json:"end_time"`
+}
+
+// Implements VestingAccount.
+func (bva BaseVestingAccount) GetOriginalVesting() sdk.Coins {
+	return bva.OriginalVesting
+}
+
+// Implements VestingAccount.
+func (bva BaseVestingAccount) GetDelegatedFree() sdk.Coins {
+	return bva.DelegatedFree
+}
+
+// Implements VestingAccount.
+func (bva BaseVestingAccount) GetDelegatedVesting() sdk.Coins {
+	return bva.DelegatedVesting
+}
+
+// Implements VestingAccount.
+func (bva BaseVestingAccount) GetEndTime() int64 {
+	return bva.EndTime
+}
+
+// the coins which can be spent while vestingCoins are still vesting:
+// min(coins + delegated vesting - vesting, coins) for every denom.
+// The delegated vesting coins count as locked coins already out of the account.
+func (bva BaseVestingAccount) spendableCoins(vestingCoins sdk.Coins) sdk.Coins {
+	var spendable sdk.Coins
+	for _, coin := range bva.Coins {
+		locked := vestingCoins.AmountOf(coin.Denom) - bva.DelegatedVesting.AmountOf(coin.Denom)
+		if locked < 0 {
+			locked = 0
+		}
+		if amount := coin.Amount - locked; amount > 0 {
+			spendable = append(spendable, sdk.Coin{coin.Denom, amount})
+		}
+	}
+	return spendable
+}
+
+// delegate the amount out of the account: the coins still vesting and not
+// delegated yet are delegated first, then the free coins
+func (bva *BaseVestingAccount) trackDelegation(vestingCoins, amount sdk.Coins) {
+	for _, coin := range amount {
+		vesting := vestingCoins.AmountOf(coin.Denom) - bva.DelegatedVesting.AmountOf(coin.Denom)
+		if vesting < 0 {
+			vesting = 0
+		}
+		if vesting > coin.Amount {
+			vesting = coin.Amount
+		}
+		free := coin.Amount - vesting
+		if vesting > 0 {
+			bva.DelegatedVesting = bva.DelegatedVesting.Plus(sdk.Coins{{coin.Denom, vesting}})
+		}
+		if free > 0 {
+			bva.DelegatedFree = bva.DelegatedFree.Plus(sdk.Coins{{coin.Denom, free}})
+		}
+	}
+}
+
+// Undelegate the amount back to the account: the free coins are undelegated
+// first, as the vesting coins may have vested since their delegation.
+// The amount may be less than delegated, if the delegation was slashed.
+// Implements VestingAccount.
+func (bva *BaseVestingAccount) TrackUndelegation(amount sdk.Coins) {
+	for _, coin := range amount {
+		free := bva.DelegatedFree.AmountOf(coin.Denom)
+		if free > coin.Amount {
+			free = coin.Amount
+		}
+		vesting := coin.Amount - free
+		if delegated := bva.DelegatedVesting.AmountOf(coin.Denom); vesting > delegated {
+			vesting = delegated
+		}
+		if free > 0 {
+			bva.DelegatedFree = bva.DelegatedFree.Minus(sdk.Coins{{coin.Denom, free}})
+		}
+		if vesting > 0 {
+			bva.DelegatedVesting = bva.DelegatedVesting.Minus(sdk.Coins{{coin.Denom, vesting}})
+		}
+	}
+}
+
+//-----------------------------------------------------------
+// ContinuousVestingAccount
+
+var _ VestingAccount = (*ContinuousVestingAccount)(nil)
+
+// ContinuousVestingAccount - the coins vest linearly from StartTime to EndTime
+type ContinuousVestingAccount struct {
+	BaseVestingAccount
+	StartTime int64 `json:"start_time"`
+}
+
+// NewContinuousVestingAccount returns the account vesting the original vesting
+// coins linearly from the start to the end time, its coins are left unchanged
+func NewContinuousVestingAccount(acc BaseAccount, originalVesting sdk.Coins, startTime, endTime int64) *ContinuousVestingAccount {
+	return &ContinuousVestingAccount{
+		BaseVestingAccount: BaseVestingAccount{
+			BaseAccount:     acc,
+			OriginalVesting: originalVesting.Sort(),
+			EndTime:         endTime,
+		},
+		StartTime: startTime,
+	}
+}
+
+// Implements VestingAccount.
+func (cva ContinuousVestingAccount) GetVestedCoins(blockTime int64) sdk.Coins {
+	if blockTime <= cva.StartTime {
+		return nil
+	}
+	if blockTime >= cva.EndTime {
+		return cva.OriginalVesting
+	}
+	var vested sdk.Coins
+	for _, coin := range cva.OriginalVesting {
+		// amount * elapsed / duration, without overflow
+		amount := new(big.Int).Mul(big.NewInt(coin.Amount), big.NewInt(blockTime-cva.StartTime))
+		amount.Div(amount, big.NewInt(cva.EndTime-cva.StartTime))
+		if amount := amount.Int64(); amount > 0 {
+			vested = append(vested, sdk.Coin{coin.Denom, amount})
+		}
+	}
+	return vested
+}
+
+// Implements VestingAccount.
+func (cva ContinuousVestingAccount) GetVestingCoins(blockTime int64) sdk.Coins {
+	return cva.OriginalVesting.Minus(cva.GetVestedCoins(blockTime))
+}
+
+// Implements VestingAccount.
+func (cva ContinuousVestingAccount) SpendableCoins(blockTime int64) sdk.Coins {
+	return cva.spendableCoins(cva.GetVestingCoins(blockTime))
+}
+
+// Implements VestingAccount.
+func (cva *ContinuousVestingAccount) TrackDelegation(blockTime int64, amount sdk.Coins) {
+	cva.trackDelegation(cva.GetVestingCoins(blockTime), amount)
+}
+
+//-----------------------------------------------------------
+// DelayedVestingAccount
+
+var _ VestingAccount = (*DelayedVestingAccount)(nil)
+
+// DelayedVestingAccount - the coins vest all at once at EndTime, the cliff
+type DelayedVestingAccount struct {
+	BaseVestingAccount
+}
+
+// NewDelayedVestingAccount returns the account vesting the original vesting
+// coins at the end time, its coins are left unchanged
+func NewDelayedVestingAccount(acc BaseAccount, originalVesting sdk.Coins, endTime int64) *DelayedVestingAccount {
+	return &DelayedVestingAccount{
+		BaseVestingAccount: BaseVestingAccount{
+			BaseAccount:     acc,
+			OriginalVesting: originalVesting.Sort(),
+			EndTime:         endTime,
+		},
+	}
+}
+
+// Implements VestingAccount.
+func (dva DelayedVestingAccount) GetVestedCoins(blockTime int64) sdk.Coins {
+	if blockTime >= dva.EndTime {
+		return dva.OriginalVesting
+	}
+	return nil
+}
+
+// Implements VestingAccount.
+func (dva DelayedVestingAccount) GetVestingCoins(blockTime int64) sdk.Coins {
+	return dva.OriginalVesting.Minus(dva.GetVestedCoins(blockTime))
+}
+
+// Implements VestingAccount.
+func (dva DelayedVestingAccount) SpendableCoins(blockTime int64) sdk.Coins {
+	return dva.spendableCoins(dva.GetVestingCoins(blockTime))
+}
+
+// Implements VestingAccount.
+func (dva *DelayedVestingAccount) TrackDelegation(blockTime int64, amount sdk.Coins) {
+	dva.trackDelegation(dva.GetVestingCoins(blockTime), amount)
+}
+
+//-----------------------------------------------------------
+
+// SpendableCoins returns the coins of the account which can be spent
+// at the block time, all of its coins unless it is a vesting account
+func SpendableCoins(acc sdk.Account, blockTime int64) sdk.Coins {
+	if vacc, ok := acc.(VestingAccount); ok {
+		return vacc.SpendableCoins(blockTime)
+	}
+	return acc.GetCoins()
+}
+
+//----------------------------------------
+// Wire
+
+// RegisterVestingAccounts registers the vesting accounts
+// as implementations of sdk.Account
+func RegisterVestingAccounts(cdc *wire.Codec) {
+	cdc.RegisterConcrete(&ContinuousVestingAccount{}, "auth/ContinuousVestingAccount", nil)
+	cdc.RegisterConcrete(&DelayedVestingAccount{}, "auth/DelayedVestingAccount", nil)
+}
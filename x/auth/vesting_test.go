@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdk "inschain-tendermint/types"
+	wire "inschain-tendermint/wire"
+)
+
+func TestContinuousVestingAccount(t *testing.T) {
+	_, addr := privAndAddr()
+	base := NewBaseAccountWithAddress(addr)
+	base.Coins = sdk.Coins{{"fee", 100}, {"steak", 100}}
+	acc := NewContinuousVestingAccount(base, sdk.Coins{{"steak", 100}}, 1000, 2000)
+
+	// nothing vested before the start, the other coins are spendable
+	assert.Nil(t, acc.GetVestedCoins(1000))
+	assert.Equal(t, sdk.Coins{{"steak", 100}}, acc.GetVestingCoins(500))
+	assert.Equal(t, sdk.Coins{{"fee", 100}}, acc.SpendableCoins(500))
+
+	// linear vesting, all vested at the end
+	assert.Equal(t, sdk.Coins{{"steak", 25}}, acc.GetVestedCoins(1250))
+	assert.Equal(t, sdk.Coins{{"fee", 100}, {"steak", 25}}, acc.SpendableCoins(1250))
+	assert.Equal(t, sdk.Coins{{"steak", 100}}, acc.GetVestedCoins(3000))
+	assert.Equal(t, sdk.Coins{{"fee", 100}, {"steak", 100}}, acc.SpendableCoins(3000))
+
+	// delegate the vesting coins first, the spendable coins are unchanged
+	acc.TrackDelegation(1500, sdk.Coins{{"steak", 60}})
+	acc.SetCoins(sdk.Coins{{"fee", 100}, {"steak", 40}})
+	assert.Equal(t, sdk.Coins{{"steak", 50}}, acc.GetDelegatedVesting())
+	assert.Equal(t, sdk.Coins{{"steak", 10}}, acc.GetDelegatedFree())
+	assert.Equal(t, sdk.Coins{{"fee", 100}, {"steak", 40}}, acc.SpendableCoins(1500))
+
+	// undelegate the free coins first
+	acc.TrackUndelegation(sdk.Coins{{"steak", 30}})
+	acc.SetCoins(sdk.Coins{{"fee", 100}, {"steak", 70}})
+	assert.Nil(t, acc.GetDelegatedFree())
+	assert.Equal(t, sdk.Coins{{"steak", 30}}, acc.GetDelegatedVesting())
+	assert.Equal(t, sdk.Coins{{"fee", 100}, {"steak", 50}}, acc.SpendableCoins(1500))
+}
+
+func TestDelayedVestingAccount(t *testing.T) {
+	_, addr := privAndAddr()
+	base := NewBaseAccountWithAddress(addr)
+	base.Coins = sdk.Coins{{"steak", 100}}
+	acc := NewDelayedVestingAccount(base, sdk.Coins{{"steak", 100}}, 2000)
+
+	assert.Nil(t, acc.GetVestedCoins(1999))
+	assert.Nil(t, acc.SpendableCoins(1999))
+	assert.Equal(t, sdk.Coins{{"steak", 100}}, acc.SpendableCoins(2000))
+
+	// a delegation of all the coins, slashed in half before the cliff
+	acc.TrackDelegation(1000, sdk.Coins{{"steak", 100}})
+	acc.SetCoins(nil)
+	acc.TrackUndelegation(sdk.Coins{{"steak", 50}})
+	acc.SetCoins(sdk.Coins{{"steak", 50}})
+	assert.Equal(t, sdk.Coins{{"steak", 50}}, acc.GetDelegatedVesting())
+	assert.Nil(t, acc.SpendableCoins(1999))
+	assert.Equal(t, sdk.Coins{{"steak", 50}}, acc.SpendableCoins(2000))
+
+	// the vesting accounts go through the codec as accounts
+	cdc := wire.NewCodec()
+	RegisterBaseAccount(cdc)
+	bz, err := cdc.MarshalBinaryBare(sdk.Account(acc))
+	require.Nil(t, err)
+	var decoded sdk.Account
+	require.Nil(t, cdc.UnmarshalBinaryBare(bz, &decoded))
+	assert.Equal(t, acc, decoded)
+	assert.Equal(t, acc.SpendableCoins(1999), SpendableCoins(decoded, 1999))
+}
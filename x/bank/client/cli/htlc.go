@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"encoding/hex"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+	"inschain-tendermint/x/bank"
+)
+
+const (
+	flagHashLock     = "hashlock"
+	flagExpiryHeight = "expiry-height"
+	flagHTLCID       = "htlc-id"
+	flagPreimage     = "preimage"
+	flagAddress      = "address"
+)
+
+// AddHTLCCommands adds the hashed timelock escrow subcommands, under a htlc command
+func AddHTLCCommands(cmd *cobra.Command, cdc *wire.Codec) {
+	htlcCmd := &cobra.Command{
+		Use:   "htlc",
+		Short: "Hashed timelock escrow subcommands",
+	}
+	htlcCmd.AddCommand(
+		client.PostCommands(
+			CreateHTLCCmd(cdc),
+			ClaimHTLCCmd(cdc),
+			RefundHTLCCmd(cdc),
+		)...)
+	htlcCmd.AddCommand(
+		client.GetCommands(
+			GetHTLCCmd("htlc", cdc),
+			GetHTLCsCmd("htlc", cdc),
+		)...)
+	cmd.AddCommand(htlcCmd)
+}
+
+// CreateHTLCCmd locks coins of the key toward a recipient under a hashlock
+func CreateHTLCCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Lock coins toward a recipient until the preimage of the hashlock is revealed",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			sender, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			recipient, err := sdk.GetAddress(viper.GetString(flagTo))
+			if err != nil {
+				return err
+			}
+			amount, err := sdk.ParseCoins(viper.GetString(flagAmount))
+			if err != nil {
+				return err
+			}
+			hashLock, err := hex.DecodeString(viper.GetString(flagHashLock))
+			if err != nil {
+				return err
+			}
+			msg := bank.NewMsgCreateHTLC(sender, recipient, amount, hashLock, viper.GetInt64(flagExpiryHeight))
+			return ctx.SendMsg(msg, cdc)
+		},
+	}
+	cmd.Flags().String(flagTo, "", "Address of the recipient")
+	cmd.Flags().String(flagAmount, "", "Amount of coins to lock")
+	cmd.Flags().String(flagHashLock, "", "SHA-256 hash of the preimage, in hex")
+	cmd.Flags().Int64(flagExpiryHeight, 0, "Height at the end of which the escrow expires")
+	return cmd
+}
+
+// ClaimHTLCCmd claims an escrow of the key with the preimage of its hashlock
+func ClaimHTLCCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claim",
+		Short: "Claim the coins of an escrow by revealing the preimage of its hashlock",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			recipient, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			preimage, err := hex.DecodeString(viper.GetString(flagPreimage))
+			if err != nil {
+				return err
+			}
+			msg := bank.NewMsgClaimHTLC(recipient, viper.GetInt64(flagHTLCID), preimage)
+			return ctx.SendMsg(msg, cdc)
+		},
+	}
+	cmd.Flags().Int64(flagHTLCID, 0, "id of the escrow")
+	cmd.Flags().String(flagPreimage, "", "preimage of the hashlock, in hex")
+	return cmd
+}
+
+// RefundHTLCCmd takes back the coins of an expired escrow of the key
+func RefundHTLCCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "refund",
+		Short: "Take back the coins of an expired escrow",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			sender, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			return ctx.SendMsg(bank.NewMsgRefundHTLC(sender, viper.GetInt64(flagHTLCID)), cdc)
+		},
+	}
+	cmd.Flags().Int64(flagHTLCID, 0, "id of the escrow")
+	return cmd
+}
+
+// GetHTLCCmd queries an escrow
+func GetHTLCCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "htlc",
+		Short: "Query a hashed timelock escrow",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := bank.QueryHTLCParams{HTLCID: viper.GetInt64(flagHTLCID)}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, bank.QueryHTLC, params, new(bank.HTLC), cdc)
+		},
+	}
+	cmd.Flags().Int64(flagHTLCID, 0, "id of the escrow")
+	return cmd
+}
+
+// GetHTLCsCmd queries the escrows, only those of an address if given
+func GetHTLCsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "htlcs",
+		Short: "Query the hashed timelock escrows, of which the address is the sender or the recipient if given",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var params bank.QueryHTLCsParams
+			if addr := viper.GetString(flagAddress); addr != "" {
+				address, err := sdk.GetAddress(addr)
+				if err != nil {
+					return err
+				}
+				params.Address = address
+			}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, bank.QueryHTLCs, params, &[]bank.HTLC{}, cdc)
+		},
+	}
+	cmd.Flags().String(flagAddress, "", "Address of the sender or the recipient")
+	return cmd
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/bank"
+)
+
+// GetSupplyCmd queries the supply of a denom
+func GetSupplyCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "supply",
+		Short: "Query the supply of a denom",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := bank.QuerySupplyParams{Denom: viper.GetString(flagDenom)}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, bank.QuerySupply, params, new(sdk.Coin), cdc)
+		},
+	}
+	cmd.Flags().String(flagDenom, "", "denom of the coins")
+	return cmd
+}
+
+// GetTotalSupplyCmd queries the supply of every denom
+func GetTotalSupplyCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "total-supply",
+		Short: "Query the supply of every denom",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, bank.QueryTotalSupply, struct{}{}, &sdk.Coins{}, cdc)
+		},
+	}
+}
+
+// GetEscrowsCmd queries the coins held in escrow by the modules
+func GetEscrowsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "escrows",
+		Short: "Query the coins held in escrow by the modules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, bank.QueryEscrows, struct{}{}, &[]bank.Escrow{}, cdc)
+		},
+	}
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+	"inschain-tendermint/x/bank"
+)
+
+const (
+	flagDenom     = "denom"
+	flagName      = "name"
+	flagDecimals  = "decimals"
+	flagMaxSupply = "max-supply"
+	flagNewIssuer = "new-issuer"
+)
+
+// AddTokenCommands adds the token subcommands, under a token command
+func AddTokenCommands(cmd *cobra.Command, cdc *wire.Codec) {
+	tokenCmd := &cobra.Command{
+		Use:   "token",
+		Short: "Token issuance subcommands",
+	}
+	tokenCmd.AddCommand(
+		client.PostCommands(
+			RegisterTokenCmd(cdc),
+			IssueCmd(cdc),
+			BurnCmd(cdc),
+			TransferIssuerCmd(cdc),
+			RenounceIssuerCmd(cdc),
+		)...)
+	tokenCmd.AddCommand(
+		client.GetCommands(
+			GetTokenCmd("token", cdc),
+			GetTokensCmd("token", cdc),
+		)...)
+	cmd.AddCommand(tokenCmd)
+}
+
+// RegisterTokenCmd registers a new denom, issued by the key
+func RegisterTokenCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register",
+		Short: "Register a new denom with the key as its issuer",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			issuer, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			decimals := viper.GetInt(flagDecimals)
+			if decimals < 0 || decimals > 18 {
+				return fmt.Errorf("decimals must be between 0 and 18")
+			}
+			token := bank.NewToken(viper.GetString(flagDenom), viper.GetString(flagName),
+				uint8(decimals), viper.GetInt64(flagMaxSupply), issuer)
+			return ctx.SendMsg(bank.NewMsgRegisterToken(token), cdc)
+		},
+	}
+	cmd.Flags().String(flagDenom, "", "denom of the token (ex. bond)")
+	cmd.Flags().String(flagName, "", "name of the token")
+	cmd.Flags().Int(flagDecimals, 0, "decimals of the token, at most 18")
+	cmd.Flags().Int64(flagMaxSupply, 0, "maximum supply of the token")
+	return cmd
+}
+
+// IssueCmd mints coins of the tokens issued by the key to an address
+func IssueCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "issue",
+		Short: "Mint coins of tokens issued by the key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			banker, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			to, err := sdk.GetAddress(viper.GetString(flagTo))
+			if err != nil {
+				return err
+			}
+			coins, err := sdk.ParseCoins(viper.GetString(flagAmount))
+			if err != nil {
+				return err
+			}
+			msg := bank.NewMsgIssue(banker, []bank.Output{bank.NewOutput(to, coins)})
+			return ctx.SendMsg(msg, cdc)
+		},
+	}
+	cmd.Flags().String(flagTo, "", "Address to mint the coins to")
+	cmd.Flags().String(flagAmount, "", "Amount of coins to mint")
+	return cmd
+}
+
+// BurnCmd destroys coins of the key
+func BurnCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "burn",
+		Short: "Burn coins of the key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			owner, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			coins, err := sdk.ParseCoins(viper.GetString(flagAmount))
+			if err != nil {
+				return err
+			}
+			return ctx.SendMsg(bank.NewMsgBurn(owner, coins), cdc)
+		},
+	}
+	cmd.Flags().String(flagAmount, "", "Amount of coins to burn")
+	return cmd
+}
+
+// TransferIssuerCmd gives the issuer rights of a token of the key to another address
+func TransferIssuerCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-issuer",
+		Short: "Transfer the issuer rights of a token to another address",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			issuer, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			newIssuer, err := sdk.GetAddress(viper.GetString(flagNewIssuer))
+			if err != nil {
+				return err
+			}
+			msg := bank.NewMsgTransferIssuer(issuer, viper.GetString(flagDenom), newIssuer)
+			return ctx.SendMsg(msg, cdc)
+		},
+	}
+	cmd.Flags().String(flagDenom, "", "denom of the token")
+	cmd.Flags().String(flagNewIssuer, "", "Address of the new issuer")
+	return cmd
+}
+
+// RenounceIssuerCmd gives up the issuer rights of a token of the key
+func RenounceIssuerCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "renounce-issuer",
+		Short: "Renounce the issuer rights of a token, its supply can't grow anymore",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			issuer, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			return ctx.SendMsg(bank.NewMsgRenounceIssuer(issuer, viper.GetString(flagDenom)), cdc)
+		},
+	}
+	cmd.Flags().String(flagDenom, "", "denom of the token")
+	return cmd
+}
+
+// GetTokenCmd queries the token of a denom
+func GetTokenCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Query the token of a denom",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := bank.QueryTokenParams{Denom: viper.GetString(flagDenom)}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, bank.QueryToken, params, new(bank.Token), cdc)
+		},
+	}
+	cmd.Flags().String(flagDenom, "", "denom of the token")
+	return cmd
+}
+
+// GetTokensCmd queries all the registered tokens
+func GetTokensCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "tokens",
+		Short: "Query all the registered tokens",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, bank.QueryTokens, struct{}{}, &[]bank.Token{}, cdc)
+		},
+	}
+}
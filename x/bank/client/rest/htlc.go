@@ -0,0 +1,211 @@
+package rest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tendermint/go-crypto/keys"
+	cmn "github.com/tendermint/tmlibs/common"
+
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/bank"
+)
+
+// registerHTLCRoutes - define the routes of the hashed timelock escrows
+func registerHTLCRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, kb keys.Keybase) {
+	r.HandleFunc("/htlcs", CreateHTLCHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/htlcs/{htlcID}/claim", ClaimHTLCHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/htlcs/{htlcID}/refund", RefundHTLCHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/htlcs", HTLCsHandlerFn("htlc", cdc, ctx)).Methods("GET")
+	r.HandleFunc("/htlcs/{htlcID}", HTLCHandlerFn("htlc", cdc, ctx)).Methods("GET")
+}
+
+// the fields every escrow tx request carries to sign it
+type baseReq struct {
+	LocalAccountName string `json:"name"`
+	Password         string `json:"password"`
+	ChainID          string `json:"chain_id"`
+	Sequence         int64  `json:"sequence"`
+	GenerateOnly     bool   `json:"generate_only"`
+}
+
+type createHTLCBody struct {
+	baseReq
+	Recipient    sdk.Address  `json:"recipient"`
+	Amount       sdk.Coins    `json:"amount"`
+	HashLock     cmn.HexBytes `json:"hash_lock"`
+	ExpiryHeight int64        `json:"expiry_height"`
+}
+
+type claimHTLCBody struct {
+	baseReq
+	Preimage cmn.HexBytes `json:"preimage"`
+}
+
+// CreateHTLCHandlerFn - http request handler to lock coins toward a recipient
+func CreateHTLCHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m createHTLCBody
+		if !readBody(w, r, &m) {
+			return
+		}
+		info, err := kb.Get(m.LocalAccountName)
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		sender := sdk.Address(info.PubKey.Address())
+		msg := bank.NewMsgCreateHTLC(sender, m.Recipient, m.Amount, m.HashLock, m.ExpiryHeight)
+		signAndBroadcast(w, cdc, ctx, m.baseReq, msg)
+	}
+}
+
+// ClaimHTLCHandlerFn - http request handler to claim an escrow with the preimage
+func ClaimHTLCHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		htlcID, ok := readHTLCID(w, r)
+		if !ok {
+			return
+		}
+		var m claimHTLCBody
+		if !readBody(w, r, &m) {
+			return
+		}
+		info, err := kb.Get(m.LocalAccountName)
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		msg := bank.NewMsgClaimHTLC(sdk.Address(info.PubKey.Address()), htlcID, m.Preimage)
+		signAndBroadcast(w, cdc, ctx, m.baseReq, msg)
+	}
+}
+
+// RefundHTLCHandlerFn - http request handler to take back an expired escrow
+func RefundHTLCHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		htlcID, ok := readHTLCID(w, r)
+		if !ok {
+			return
+		}
+		var m baseReq
+		if !readBody(w, r, &m) {
+			return
+		}
+		info, err := kb.Get(m.LocalAccountName)
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		msg := bank.NewMsgRefundHTLC(sdk.Address(info.PubKey.Address()), htlcID)
+		signAndBroadcast(w, cdc, ctx, m, msg)
+	}
+}
+
+// HTLCsHandlerFn - http request handler to query the escrows, only those of
+// which the address query parameter is the sender or the recipient if any
+func HTLCsHandlerFn(queryRoute string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var params bank.QueryHTLCsParams
+		if address := r.URL.Query().Get("address"); address != "" {
+			bz, err := hex.DecodeString(address)
+			if err != nil {
+				writeErr(w, http.StatusBadRequest, err)
+				return
+			}
+			params.Address = bz
+		}
+		query(w, cdc, ctx, queryRoute, bank.QueryHTLCs, params)
+	}
+}
+
+// HTLCHandlerFn - http request handler to query an escrow
+func HTLCHandlerFn(queryRoute string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		htlcID, ok := readHTLCID(w, r)
+		if !ok {
+			return
+		}
+		query(w, cdc, ctx, queryRoute, bank.QueryHTLC, bank.QueryHTLCParams{HTLCID: htlcID})
+	}
+}
+
+//_______________________________________________________________________
+
+func readHTLCID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	htlcID, err := strconv.ParseInt(mux.Vars(r)["htlcID"], 10, 64)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return 0, false
+	}
+	return htlcID, true
+}
+
+func readBody(w http.ResponseWriter, r *http.Request, m interface{}) bool {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return false
+	}
+	err = json.Unmarshal(body, m)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+// return the unsigned tx to sign offline, or sign and broadcast it
+func signAndBroadcast(w http.ResponseWriter, cdc *wire.Codec, ctx context.CoreContext, req baseReq, msg sdk.Msg) {
+	if err := msg.ValidateBasic(); err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx = ctx.WithSequence(req.Sequence)
+	if req.GenerateOnly {
+		signMsg, err := ctx.BuildSignMsg([]sdk.Msg{msg})
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err)
+			return
+		}
+		output, err := wire.MarshalJSONIndent(cdc, signMsg)
+		if err != nil {
+			writeErr(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Write(output)
+		return
+	}
+
+	txBytes, err := ctx.SignAndBuild(req.LocalAccountName, req.Password, []sdk.Msg{msg}, cdc)
+	if err != nil {
+		writeErr(w, http.StatusUnauthorized, err)
+		return
+	}
+	res, err := ctx.BroadcastTx(txBytes)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	output, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(output)
+}
+
+func writeErr(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	w.Write([]byte(err.Error()))
+}
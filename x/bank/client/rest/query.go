@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"inschain-tendermint/client/context"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/bank"
+)
+
+// SupplyHandlerFn - http request handler to query the supply of a denom
+func SupplyHandlerFn(queryRoute string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := bank.QuerySupplyParams{Denom: mux.Vars(r)["denom"]}
+		query(w, cdc, ctx, queryRoute, bank.QuerySupply, params)
+	}
+}
+
+// QueryHandlerFn - http request handler to query a route without parameters
+func QueryHandlerFn(queryRoute, route string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query(w, cdc, ctx, queryRoute, route, struct{}{})
+	}
+}
+
+func query(w http.ResponseWriter, cdc *wire.Codec, ctx context.CoreContext, queryRoute, route string, params interface{}) {
+	data, err := cdc.MarshalJSON(params)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	res, err := ctx.QueryCustom(queryRoute, route, data)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf("Couldn't query %s. Error: %s", route, err.Error())))
+		return
+	}
+	w.Write(res)
+}
@@ -9,15 +9,20 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/tendermint/go-crypto/keys"
 
-	"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/bank/client"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/bank/client"
 )
 
 // RegisterRoutes - Central function to define routes that get registered by the main application
 func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, kb keys.Keybase) {
 	r.HandleFunc("/accounts/{address}/send", SendRequestHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/supply", QueryHandlerFn("supply", bank.QueryTotalSupply, cdc, ctx)).Methods("GET")
+	r.HandleFunc("/supply/{denom}", SupplyHandlerFn("supply", cdc, ctx)).Methods("GET")
+	r.HandleFunc("/escrows", QueryHandlerFn("supply", bank.QueryEscrows, cdc, ctx)).Methods("GET")
+	registerHTLCRoutes(ctx, r, cdc, kb)
 }
 
 type sendBody struct {
@@ -28,6 +33,7 @@ type sendBody struct {
 	Password         string    `json:"password"`
 	ChainID          string    `json:"chain_id"`
 	Sequence         int64     `json:"sequence"`
+	GenerateOnly     bool      `json:"generate_only"`
 }
 
 // SendRequestHandlerFn - http request handler to send coins to a address
@@ -74,9 +80,27 @@ func SendRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreCont
 			return
 		}
 
-		// sign
+		// return the unsigned tx to sign offline
 		ctx = ctx.WithSequence(m.Sequence)
-		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, msg, cdc)
+		if m.GenerateOnly {
+			signMsg, err := ctx.BuildSignMsg([]sdk.Msg{msg})
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			output, err := wire.MarshalJSONIndent(cdc, signMsg)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			w.Write(output)
+			return
+		}
+
+		// sign
+		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, []sdk.Msg{msg}, cdc)
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(err.Error()))
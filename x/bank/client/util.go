@@ -1,8 +1,8 @@
 package client
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	bank "github.com/cosmos/cosmos-sdk/x/bank"
+	sdk "inschain-tendermint/types"
+	bank "inschain-tendermint/x/bank"
 )
 
 // build the sendTx msg
@@ -2,15 +2,24 @@
 package bank
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	"fmt"
+
+	sdk "inschain-tendermint/types"
 )
 
 // Bank errors reserve 100 ~ 199.
 const (
 	DefaultCodespace sdk.CodespaceType = 2
 
-	CodeInvalidInput  sdk.CodeType = 101
-	CodeInvalidOutput sdk.CodeType = 102
+	CodeInvalidInput   sdk.CodeType = 101
+	CodeInvalidOutput  sdk.CodeType = 102
+	CodeInvalidVesting sdk.CodeType = 103
+	CodeInvalidToken   sdk.CodeType = 104
+	CodeUnknownToken   sdk.CodeType = 105
+	CodeNotIssuer      sdk.CodeType = 106
+	CodeMaxSupply      sdk.CodeType = 107
+	CodeInvalidHTLC    sdk.CodeType = 108
+	CodeUnknownHTLC    sdk.CodeType = 109
 )
 
 // NOTE: Don't stringer this, we'll put better messages in later.
@@ -20,6 +29,20 @@ func codeToDefaultMsg(code sdk.CodeType) string {
 		return "Invalid input coins"
 	case CodeInvalidOutput:
 		return "Invalid output coins"
+	case CodeInvalidVesting:
+		return "Invalid vesting"
+	case CodeInvalidToken:
+		return "Invalid token"
+	case CodeUnknownToken:
+		return "Unknown token"
+	case CodeNotIssuer:
+		return "Not the issuer of the token"
+	case CodeMaxSupply:
+		return "Max supply exceeded"
+	case CodeInvalidHTLC:
+		return "Invalid hashed timelock escrow"
+	case CodeUnknownHTLC:
+		return "Unknown hashed timelock escrow"
 	default:
 		return sdk.CodeToDefaultMsg(code)
 	}
@@ -44,6 +67,38 @@ func ErrNoOutputs(codespace sdk.CodespaceType) sdk.Error {
 	return newError(codespace, CodeInvalidOutput, "")
 }
 
+func ErrInvalidVesting(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidVesting, msg)
+}
+
+func ErrInvalidToken(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidToken, msg)
+}
+
+func ErrTokenExists(codespace sdk.CodespaceType, denom string) sdk.Error {
+	return newError(codespace, CodeInvalidToken, fmt.Sprintf("token %s is already registered", denom))
+}
+
+func ErrUnknownToken(codespace sdk.CodespaceType, denom string) sdk.Error {
+	return newError(codespace, CodeUnknownToken, fmt.Sprintf("token %s is not registered", denom))
+}
+
+func ErrNotIssuer(codespace sdk.CodespaceType, denom string) sdk.Error {
+	return newError(codespace, CodeNotIssuer, fmt.Sprintf("not the issuer of token %s", denom))
+}
+
+func ErrMaxSupplyExceeded(codespace sdk.CodespaceType, denom string, maxSupply int64) sdk.Error {
+	return newError(codespace, CodeMaxSupply, fmt.Sprintf("the supply of token %s cannot exceed %d", denom, maxSupply))
+}
+
+func ErrInvalidHTLC(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidHTLC, msg)
+}
+
+func ErrUnknownHTLC(codespace sdk.CodespaceType, id int64) sdk.Error {
+	return newError(codespace, CodeUnknownHTLC, fmt.Sprintf("unknown escrow %d", id))
+}
+
 //----------------------------------------
 
 func msgOrDefaultMsg(msg string, code sdk.CodeType) string {
@@ -2,8 +2,9 @@ package bank
 
 import (
 	"reflect"
+	"strconv"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // NewHandler returns a handler for "bank" type messages.
@@ -12,8 +13,6 @@ func NewHandler(k Keeper) sdk.Handler {
 		switch msg := msg.(type) {
 		case MsgSend:
 			return handleMsgSend(ctx, k, msg)
-		case MsgIssue:
-			return handleMsgIssue(ctx, k, msg)
 		default:
 			errMsg := "Unrecognized bank Msg type: " + reflect.TypeOf(msg).Name()
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -30,11 +29,145 @@ func handleMsgSend(ctx sdk.Context, k Keeper, msg MsgSend) sdk.Result {
 		return err.Result()
 	}
 
-	// TODO: add some tags so we can search it!
-	return sdk.Result{} // TODO
+	return sdk.Result{
+		Tags: sendTags(msg.Inputs, msg.Outputs),
+	}
+}
+
+// NewTokenHandler returns a handler for "token" type messages.
+func NewTokenHandler(k TokenKeeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgRegisterToken:
+			return handleMsgRegisterToken(ctx, k, msg)
+		case MsgIssue:
+			return handleMsgIssue(ctx, k, msg)
+		case MsgBurn:
+			return handleMsgBurn(ctx, k, msg)
+		case MsgTransferIssuer:
+			return handleMsgTransferIssuer(ctx, k, msg)
+		case MsgRenounceIssuer:
+			return handleMsgRenounceIssuer(ctx, k, msg)
+		default:
+			errMsg := "Unrecognized token Msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+// Handle MsgRegisterToken.
+func handleMsgRegisterToken(ctx sdk.Context, k TokenKeeper, msg MsgRegisterToken) sdk.Result {
+	err := k.RegisterToken(ctx, msg.Token())
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: tokenTags(ActionRegisterToken, msg.Issuer, msg.Denom),
+	}
 }
 
 // Handle MsgIssue.
-func handleMsgIssue(ctx sdk.Context, k Keeper, msg MsgIssue) sdk.Result {
-	panic("not implemented yet")
+func handleMsgIssue(ctx sdk.Context, k TokenKeeper, msg MsgIssue) sdk.Result {
+	err := k.Issue(ctx, msg.Banker, msg.Outputs)
+	if err != nil {
+		return err.Result()
+	}
+	tags := sdk.NewTags(TagAction, ActionIssue, TagIssuer, []byte(msg.Banker.String()))
+	for _, out := range msg.Outputs {
+		tags = tags.AppendTag(TagRecipient, []byte(out.Address.String()))
+	}
+	return sdk.Result{
+		Tags: tags,
+	}
+}
+
+// Handle MsgBurn.
+func handleMsgBurn(ctx sdk.Context, k TokenKeeper, msg MsgBurn) sdk.Result {
+	err := k.Burn(ctx, msg.Owner, msg.Coins)
+	if err != nil {
+		return err.Result()
+	}
+	tags := sdk.NewTags(TagAction, ActionBurn, TagSender, []byte(msg.Owner.String()))
+	for _, coin := range msg.Coins {
+		tags = tags.AppendTag(TagDenom, []byte(coin.Denom))
+	}
+	return sdk.Result{
+		Tags: tags,
+	}
+}
+
+// Handle MsgTransferIssuer.
+func handleMsgTransferIssuer(ctx sdk.Context, k TokenKeeper, msg MsgTransferIssuer) sdk.Result {
+	err := k.TransferIssuer(ctx, msg.Issuer, msg.Denom, msg.NewIssuer)
+	if err != nil {
+		return err.Result()
+	}
+	tags := tokenTags(ActionTransferIssuer, msg.Issuer, msg.Denom).
+		AppendTag(TagIssuer, []byte(msg.NewIssuer.String()))
+	return sdk.Result{
+		Tags: tags,
+	}
+}
+
+// Handle MsgRenounceIssuer.
+func handleMsgRenounceIssuer(ctx sdk.Context, k TokenKeeper, msg MsgRenounceIssuer) sdk.Result {
+	err := k.RenounceIssuer(ctx, msg.Issuer, msg.Denom)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: tokenTags(ActionRenounceIssuer, msg.Issuer, msg.Denom),
+	}
+}
+
+// NewHTLCHandler returns a handler for "htlc" type messages.
+func NewHTLCHandler(k HTLCKeeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgCreateHTLC:
+			return handleMsgCreateHTLC(ctx, k, msg)
+		case MsgClaimHTLC:
+			return handleMsgClaimHTLC(ctx, k, msg)
+		case MsgRefundHTLC:
+			return handleMsgRefundHTLC(ctx, k, msg)
+		default:
+			errMsg := "Unrecognized htlc Msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+// Handle MsgCreateHTLC.
+func handleMsgCreateHTLC(ctx sdk.Context, k HTLCKeeper, msg MsgCreateHTLC) sdk.Result {
+	id, err := k.CreateHTLC(ctx, msg.Sender, msg.Recipient, msg.Amount, msg.HashLock, msg.ExpiryHeight)
+	if err != nil {
+		return err.Result()
+	}
+	htlc, _ := k.GetHTLC(ctx, id)
+	return sdk.Result{
+		Data: []byte(strconv.FormatInt(id, 10)),
+		Tags: htlcTags(ActionCreateHTLC, htlc),
+	}
+}
+
+// Handle MsgClaimHTLC.
+func handleMsgClaimHTLC(ctx sdk.Context, k HTLCKeeper, msg MsgClaimHTLC) sdk.Result {
+	htlc, err := k.ClaimHTLC(ctx, msg.Recipient, msg.HTLCID, msg.Preimage)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: htlcTags(ActionClaimHTLC, htlc),
+	}
+}
+
+// Handle MsgRefundHTLC.
+func handleMsgRefundHTLC(ctx sdk.Context, k HTLCKeeper, msg MsgRefundHTLC) sdk.Result {
+	htlc, err := k.RefundHTLC(ctx, msg.Sender, msg.HTLCID)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: htlcTags(ActionRefundHTLC, htlc),
+	}
 }
@@ -0,0 +1,299 @@
+package bank
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	cmn "github.com/tendermint/tmlibs/common"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+)
+
+// A hashed timelock escrow (HTLC) locks coins of a sender toward a recipient
+// until the expiry height. The recipient claims them by revealing the
+// preimage of the SHA-256 hashlock, as long as the escrow hasn't expired.
+// The escrows expire at the end of their expiry height, then only the sender
+// can take the coins back. The locked coins are held by the SupplyKeeper
+// under the htlc holder.
+
+var (
+	// Keys for store prefixes
+	HTLCKeyPrefix = []byte{0x00} // prefix for each key to an escrow, by id
+	HTLCQueueKey  = []byte{0x01} // prefix for the open escrows, by expiry height
+	NextHTLCIDKey = []byte{0x02} // key for the id of the next escrow
+)
+
+const (
+	HashLockLength  = sha256.Size // length of a hashlock
+	MaxPreimageSize = 256         // max length of a preimage
+)
+
+func htlcIDBytes(id int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(id))
+	return bz
+}
+
+// get the key for the escrow
+func GetHTLCKey(id int64) []byte {
+	return append(HTLCKeyPrefix, htlcIDBytes(id)...)
+}
+
+// get the key of the escrow in the queue, ordered by its expiry height
+func getHTLCQueueKey(expiryHeight int64, id int64) []byte {
+	heightBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(heightBytes, uint64(expiryHeight))
+	key := append(append([]byte{}, HTLCQueueKey...), heightBytes...)
+	return append(key, htlcIDBytes(id)...)
+}
+
+// HTLC - coins locked toward the recipient under a hashlock until the expiry height
+type HTLC struct {
+	ID           int64        `json:"id"`
+	Sender       sdk.Address  `json:"sender"`
+	Recipient    sdk.Address  `json:"recipient"`
+	Amount       sdk.Coins    `json:"amount"`
+	HashLock     cmn.HexBytes `json:"hash_lock"` // SHA-256 of the preimage
+	ExpiryHeight int64        `json:"expiry_height"`
+	Expired      bool         `json:"expired"` // set at the end of the expiry height
+}
+
+// HashPreimage returns the hashlock of the preimage
+func HashPreimage(preimage []byte) []byte {
+	hash := sha256.Sum256(preimage)
+	return hash[:]
+}
+
+// HTLCGenesisState - the open and expired escrows
+type HTLCGenesisState struct {
+	StartingHTLCID int64  `json:"starting_htlc_id"`
+	HTLCs          []HTLC `json:"htlcs"`
+}
+
+//______________________________________________________________________________________________
+
+// HTLCKeeper manages the hashed timelock escrows
+type HTLCKeeper struct {
+	key          sdk.StoreKey
+	cdc          *wire.Codec
+	supplyKeeper SupplyKeeper
+	codespace    sdk.CodespaceType
+}
+
+// NewHTLCKeeper returns a new HTLCKeeper
+func NewHTLCKeeper(cdc *wire.Codec, key sdk.StoreKey, sk SupplyKeeper, codespace sdk.CodespaceType) HTLCKeeper {
+	return HTLCKeeper{
+		key:          key,
+		cdc:          cdc,
+		supplyKeeper: sk,
+		codespace:    codespace,
+	}
+}
+
+// GetHTLC returns the escrow with the id
+func (k HTLCKeeper) GetHTLC(ctx sdk.Context, id int64) (htlc HTLC, found bool) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetHTLCKey(id))
+	if bz == nil {
+		return htlc, false
+	}
+	err := k.cdc.UnmarshalJSON(bz, &htlc)
+	if err != nil {
+		panic(err)
+	}
+	return htlc, true
+}
+
+// GetHTLCs returns the escrows by id, only those of which the addr is the
+// sender or the recipient if not empty
+func (k HTLCKeeper) GetHTLCs(ctx sdk.Context, addr sdk.Address) []HTLC {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(HTLCKeyPrefix)
+	defer iterator.Close()
+
+	htlcs := []HTLC{}
+	for ; iterator.Valid(); iterator.Next() {
+		var htlc HTLC
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &htlc)
+		if err != nil {
+			panic(err)
+		}
+		if len(addr) != 0 && !bytes.Equal(htlc.Sender, addr) && !bytes.Equal(htlc.Recipient, addr) {
+			continue
+		}
+		htlcs = append(htlcs, htlc)
+	}
+	return htlcs
+}
+
+func (k HTLCKeeper) setHTLC(ctx sdk.Context, htlc HTLC) {
+	store := ctx.KVStore(k.key)
+	bz, err := k.cdc.MarshalJSON(htlc)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(GetHTLCKey(htlc.ID), bz)
+}
+
+// the ids start at 1
+func (k HTLCKeeper) getNextHTLCID(ctx sdk.Context) (id int64) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(NextHTLCIDKey)
+	if bz == nil {
+		return 1
+	}
+	err := k.cdc.UnmarshalJSON(bz, &id)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func (k HTLCKeeper) setNextHTLCID(ctx sdk.Context, id int64) {
+	store := ctx.KVStore(k.key)
+	bz, err := k.cdc.MarshalJSON(id)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(NextHTLCIDKey, bz)
+}
+
+// CreateHTLC locks the amount of the sender toward the recipient, returning
+// the id of the escrow
+func (k HTLCKeeper) CreateHTLC(ctx sdk.Context, sender, recipient sdk.Address, amount sdk.Coins,
+	hashLock []byte, expiryHeight int64) (int64, sdk.Error) {
+
+	if len(hashLock) != HashLockLength {
+		return 0, ErrInvalidHTLC(k.codespace, fmt.Sprintf("the hashlock must be %d bytes long", HashLockLength))
+	}
+	if expiryHeight <= ctx.BlockHeight() {
+		return 0, ErrInvalidHTLC(k.codespace, fmt.Sprintf("the expiry height must be after the current height %d", ctx.BlockHeight()))
+	}
+	_, err := k.supplyKeeper.EscrowCoins(ctx, HTLCMsgType, sender, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	htlc := HTLC{
+		ID:           k.getNextHTLCID(ctx),
+		Sender:       sender,
+		Recipient:    recipient,
+		Amount:       amount,
+		HashLock:     hashLock,
+		ExpiryHeight: expiryHeight,
+	}
+	k.setHTLC(ctx, htlc)
+	k.setNextHTLCID(ctx, htlc.ID+1)
+	ctx.KVStore(k.key).Set(getHTLCQueueKey(htlc.ExpiryHeight, htlc.ID), htlcIDBytes(htlc.ID))
+	return htlc.ID, nil
+}
+
+// ClaimHTLC pays the escrow to its recipient, if the preimage matches the
+// hashlock before the escrow expired
+func (k HTLCKeeper) ClaimHTLC(ctx sdk.Context, recipient sdk.Address, id int64, preimage []byte) (HTLC, sdk.Error) {
+	htlc, found := k.GetHTLC(ctx, id)
+	if !found {
+		return htlc, ErrUnknownHTLC(k.codespace, id)
+	}
+	if !bytes.Equal(htlc.Recipient, recipient) {
+		return htlc, ErrInvalidHTLC(k.codespace, fmt.Sprintf("%s is not the recipient of the escrow %d", recipient, id))
+	}
+	if htlc.Expired {
+		return htlc, ErrInvalidHTLC(k.codespace, fmt.Sprintf("the escrow %d expired at height %d", id, htlc.ExpiryHeight))
+	}
+	if !bytes.Equal(HashPreimage(preimage), htlc.HashLock) {
+		return htlc, ErrInvalidHTLC(k.codespace, "the preimage doesn't match the hashlock")
+	}
+	_, err := k.supplyKeeper.ReleaseCoins(ctx, HTLCMsgType, htlc.Recipient, htlc.Amount)
+	if err != nil {
+		return htlc, err
+	}
+	store := ctx.KVStore(k.key)
+	store.Delete(getHTLCQueueKey(htlc.ExpiryHeight, htlc.ID))
+	store.Delete(GetHTLCKey(htlc.ID))
+	return htlc, nil
+}
+
+// RefundHTLC pays an expired escrow back to its sender
+func (k HTLCKeeper) RefundHTLC(ctx sdk.Context, sender sdk.Address, id int64) (HTLC, sdk.Error) {
+	htlc, found := k.GetHTLC(ctx, id)
+	if !found {
+		return htlc, ErrUnknownHTLC(k.codespace, id)
+	}
+	if !bytes.Equal(htlc.Sender, sender) {
+		return htlc, ErrInvalidHTLC(k.codespace, fmt.Sprintf("%s is not the sender of the escrow %d", sender, id))
+	}
+	if !htlc.Expired {
+		return htlc, ErrInvalidHTLC(k.codespace, fmt.Sprintf("the escrow %d expires at height %d", id, htlc.ExpiryHeight))
+	}
+	_, err := k.supplyKeeper.RefundCoins(ctx, HTLCMsgType, htlc.Sender, htlc.Amount)
+	if err != nil {
+		return htlc, err
+	}
+	ctx.KVStore(k.key).Delete(GetHTLCKey(htlc.ID))
+	return htlc, nil
+}
+
+// HTLCEndBlocker expires the escrows whose expiry height is over, their
+// coins can only be refunded to their sender from now on
+func HTLCEndBlocker(ctx sdk.Context, k HTLCKeeper) {
+	logger := ctx.Logger().With("module", "x/bank")
+	store := ctx.KVStore(k.key)
+
+	iterator := store.Iterator(HTLCQueueKey, getHTLCQueueKey(ctx.BlockHeight()+1, 0))
+	var keys [][]byte
+	var ids []int64
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+		ids = append(ids, int64(binary.BigEndian.Uint64(iterator.Value())))
+	}
+	iterator.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+
+	for _, id := range ids {
+		htlc, found := k.GetHTLC(ctx, id)
+		if !found {
+			continue
+		}
+		htlc.Expired = true
+		k.setHTLC(ctx, htlc)
+		logger.Info("Escrow expired", "htlc", id)
+	}
+}
+
+//______________________________________________________________________________________________
+
+// GenesisHTLCEscrow - the coins of the genesis escrows, held by htlc
+func GenesisHTLCEscrow(data HTLCGenesisState) (escrow sdk.Coins) {
+	for _, htlc := range data.HTLCs {
+		escrow = escrow.Plus(htlc.Amount)
+	}
+	return escrow
+}
+
+// InitHTLCGenesis - store the genesis escrows, queueing those still open
+func InitHTLCGenesis(ctx sdk.Context, k HTLCKeeper, data HTLCGenesisState) {
+	if data.StartingHTLCID > 0 {
+		k.setNextHTLCID(ctx, data.StartingHTLCID)
+	}
+	store := ctx.KVStore(k.key)
+	for _, htlc := range data.HTLCs {
+		k.setHTLC(ctx, htlc)
+		if !htlc.Expired {
+			store.Set(getHTLCQueueKey(htlc.ExpiryHeight, htlc.ID), htlcIDBytes(htlc.ID))
+		}
+	}
+}
+
+// WriteHTLCGenesis - output the escrows
+func WriteHTLCGenesis(ctx sdk.Context, k HTLCKeeper) HTLCGenesisState {
+	return HTLCGenesisState{
+		StartingHTLCID: k.getNextHTLCID(ctx),
+		HTLCs:          k.GetHTLCs(ctx, nil),
+	}
+}
@@ -0,0 +1,118 @@
+package bank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/tendermint/tmlibs/log"
+
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	wire "inschain-tendermint/wire"
+
+	"inschain-tendermint/x/auth"
+)
+
+func createHTLCTestInput(t *testing.T) (sdk.Context, sdk.AccountMapper, SupplyKeeper, HTLCKeeper) {
+	db := dbm.NewMemDB()
+	authKey := sdk.NewKVStoreKey("authkey")
+	supplyKey := sdk.NewKVStoreKey("supply")
+	htlcKey := sdk.NewKVStoreKey("htlc")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(authKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(supplyKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(htlcKey, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{Height: 10}, false, nil, log.NewNopLogger())
+	accountMapper := auth.NewAccountMapper(cdc, authKey, &auth.BaseAccount{})
+	supplyKeeper := NewSupplyKeeper(cdc, supplyKey, NewKeeper(accountMapper), DefaultCodespace)
+	return ctx, accountMapper, supplyKeeper, NewHTLCKeeper(cdc, htlcKey, supplyKeeper, DefaultCodespace)
+}
+
+func TestHTLC(t *testing.T) {
+	ctx, am, supplyKeeper, keeper := createHTLCTestInput(t)
+	handler := NewHTLCHandler(keeper)
+	sender := sdk.Address([]byte("sender"))
+	recipient := sdk.Address([]byte("recipient"))
+	preimage := []byte("secret")
+	hashLock := HashPreimage(preimage)
+	_, err := supplyKeeper.Mint(ctx, sender, sdk.Coins{{"foo", 100}})
+	require.Nil(t, err)
+
+	// the coins must be there and the expiry in the future
+	res := handler(ctx, NewMsgCreateHTLC(sender, recipient, sdk.Coins{{"foo", 101}}, hashLock, 20))
+	assert.False(t, res.IsOK())
+	res = handler(ctx, NewMsgCreateHTLC(sender, recipient, sdk.Coins{{"foo", 30}}, hashLock, 10))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidHTLC), res.Code)
+
+	// lock two escrows, the coins leave the account of the sender
+	res = handler(ctx, NewMsgCreateHTLC(sender, recipient, sdk.Coins{{"foo", 30}}, hashLock, 20))
+	require.True(t, res.IsOK(), "%v", res)
+	assert.Equal(t, []byte("1"), res.Data)
+	res = handler(ctx, NewMsgCreateHTLC(sender, recipient, sdk.Coins{{"foo", 20}}, hashLock, 15))
+	require.True(t, res.IsOK(), "%v", res)
+	assert.True(t, supplyKeeper.GetCoins(ctx, sender).IsEqual(sdk.Coins{{"foo", 50}}))
+	assert.True(t, supplyKeeper.GetEscrow(ctx, HTLCMsgType).IsEqual(sdk.Coins{{"foo", 50}}))
+	assert.Equal(t, 2, len(keeper.GetHTLCs(ctx, recipient)))
+	assert.Nil(t, SupplyInvariant(ctx, am, supplyKeeper))
+
+	// only the recipient claims, with the right preimage
+	res = handler(ctx, NewMsgClaimHTLC(sender, 1, preimage))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidHTLC), res.Code)
+	res = handler(ctx, NewMsgClaimHTLC(recipient, 1, []byte("guess")))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidHTLC), res.Code)
+	res = handler(ctx, NewMsgRefundHTLC(sender, 1))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidHTLC), res.Code)
+	res = handler(ctx, NewMsgClaimHTLC(recipient, 1, preimage))
+	require.True(t, res.IsOK(), "%v", res)
+	assert.True(t, supplyKeeper.GetCoins(ctx, recipient).IsEqual(sdk.Coins{{"foo", 30}}))
+	res = handler(ctx, NewMsgClaimHTLC(recipient, 1, preimage))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeUnknownHTLC), res.Code)
+
+	// the second escrow is still open at its expiry height, and expires after it
+	ctx = ctx.WithBlockHeight(15)
+	HTLCEndBlocker(ctx, keeper)
+	htlc, found := keeper.GetHTLC(ctx, 2)
+	require.True(t, found)
+	assert.True(t, htlc.Expired)
+	res = handler(ctx, NewMsgClaimHTLC(recipient, 2, preimage))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidHTLC), res.Code)
+
+	// the genesis keeps the expired escrow and the next id
+	genesis := WriteHTLCGenesis(ctx, keeper)
+	assert.Equal(t, int64(3), genesis.StartingHTLCID)
+	assert.Equal(t, []HTLC{htlc}, genesis.HTLCs)
+
+	// then only the sender is refunded
+	res = handler(ctx, NewMsgRefundHTLC(recipient, 2))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidHTLC), res.Code)
+	res = handler(ctx, NewMsgRefundHTLC(sender, 2))
+	require.True(t, res.IsOK(), "%v", res)
+	assert.True(t, supplyKeeper.GetCoins(ctx, sender).IsEqual(sdk.Coins{{"foo", 70}}))
+	assert.True(t, supplyKeeper.GetEscrow(ctx, HTLCMsgType).IsZero())
+	assert.Equal(t, 0, len(keeper.GetHTLCs(ctx, nil)))
+	assert.Nil(t, SupplyInvariant(ctx, am, supplyKeeper))
+}
+
+func TestMsgCreateHTLCValidation(t *testing.T) {
+	sender := sdk.Address([]byte("sender"))
+	recipient := sdk.Address([]byte("recipient"))
+	hashLock := HashPreimage([]byte("secret"))
+	coins := sdk.Coins{{"foo", 10}}
+	assert.Nil(t, NewMsgCreateHTLC(sender, recipient, coins, hashLock, 10).ValidateBasic())
+	assert.NotNil(t, NewMsgCreateHTLC(nil, recipient, coins, hashLock, 10).ValidateBasic())
+	assert.NotNil(t, NewMsgCreateHTLC(sender, nil, coins, hashLock, 10).ValidateBasic())
+	assert.NotNil(t, NewMsgCreateHTLC(sender, recipient, sdk.Coins{{"foo", 0}}, hashLock, 10).ValidateBasic())
+	assert.NotNil(t, NewMsgCreateHTLC(sender, recipient, coins, []byte("secret"), 10).ValidateBasic())
+	assert.NotNil(t, NewMsgCreateHTLC(sender, recipient, coins, hashLock, 0).ValidateBasic())
+	assert.NotNil(t, NewMsgClaimHTLC(recipient, 1, nil).ValidateBasic())
+	assert.NotNil(t, NewMsgClaimHTLC(recipient, 1, make([]byte, MaxPreimageSize+1)).ValidateBasic())
+}
@@ -3,12 +3,20 @@ package bank
 import (
 	"fmt"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/x/auth"
 )
 
+// TransferRestrictions decides whether coins may move between accounts, eg.
+// to freeze accounts or to restrict who may hold a denom
+type TransferRestrictions interface {
+	CheckTransfer(ctx sdk.Context, inputs []Input, outputs []Output) sdk.Error
+}
+
 // Keeper manages transfers between accounts
 type Keeper struct {
 	am sdk.AccountMapper
+	tr TransferRestrictions
 }
 
 // NewKeeper returns a new Keeper
@@ -16,6 +24,13 @@ func NewKeeper(am sdk.AccountMapper) Keeper {
 	return Keeper{am: am}
 }
 
+// WithTransferRestrictions returns the keeper checking the transfers between
+// accounts against the restrictions
+func (keeper Keeper) WithTransferRestrictions(tr TransferRestrictions) Keeper {
+	keeper.tr = tr
+	return keeper
+}
+
 // GetCoins returns the coins at the addr.
 func (keeper Keeper) GetCoins(ctx sdk.Context, addr sdk.Address) sdk.Coins {
 	return getCoins(ctx, keeper.am, addr)
@@ -41,14 +56,29 @@ func (keeper Keeper) AddCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coins)
 	return addCoins(ctx, keeper.am, addr, amt)
 }
 
+// DelegateCoins subtracts amt from the coins at the addr, locked coins included.
+func (keeper Keeper) DelegateCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	return delegateCoins(ctx, keeper.am, addr, amt)
+}
+
+// UndelegateCoins adds amt undelegated back to the coins at the addr.
+func (keeper Keeper) UndelegateCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	return undelegateCoins(ctx, keeper.am, addr, amt)
+}
+
+// AddVestingCoins adds amt to the coins at the addr, vesting from the start to the end time.
+func (keeper Keeper) AddVestingCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coins, startTime, endTime int64) (sdk.Coins, sdk.Error) {
+	return addVestingCoins(ctx, keeper.am, addr, amt, startTime, endTime)
+}
+
 // SendCoins moves coins from one account to another
 func (keeper Keeper) SendCoins(ctx sdk.Context, fromAddr sdk.Address, toAddr sdk.Address, amt sdk.Coins) sdk.Error {
-	return sendCoins(ctx, keeper.am, fromAddr, toAddr, amt)
+	return sendCoins(ctx, keeper.am, keeper.tr, fromAddr, toAddr, amt)
 }
 
 // InputOutputCoins handles a list of inputs and outputs
 func (keeper Keeper) InputOutputCoins(ctx sdk.Context, inputs []Input, outputs []Output) sdk.Error {
-	return inputOutputCoins(ctx, keeper.am, inputs, outputs)
+	return inputOutputCoins(ctx, keeper.am, keeper.tr, inputs, outputs)
 }
 
 //______________________________________________________________________________________________
@@ -56,6 +86,7 @@ func (keeper Keeper) InputOutputCoins(ctx sdk.Context, inputs []Input, outputs [
 // SendKeeper only allows transfers between accounts, without the possibility of creating coins
 type SendKeeper struct {
 	am sdk.AccountMapper
+	tr TransferRestrictions
 }
 
 // NewSendKeeper returns a new Keeper
@@ -63,6 +94,13 @@ func NewSendKeeper(am sdk.AccountMapper) SendKeeper {
 	return SendKeeper{am: am}
 }
 
+// WithTransferRestrictions returns the keeper checking the transfers between
+// accounts against the restrictions
+func (keeper SendKeeper) WithTransferRestrictions(tr TransferRestrictions) SendKeeper {
+	keeper.tr = tr
+	return keeper
+}
+
 // GetCoins returns the coins at the addr.
 func (keeper SendKeeper) GetCoins(ctx sdk.Context, addr sdk.Address) sdk.Coins {
 	return getCoins(ctx, keeper.am, addr)
@@ -75,12 +113,12 @@ func (keeper SendKeeper) HasCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coi
 
 // SendCoins moves coins from one account to another
 func (keeper SendKeeper) SendCoins(ctx sdk.Context, fromAddr sdk.Address, toAddr sdk.Address, amt sdk.Coins) sdk.Error {
-	return sendCoins(ctx, keeper.am, fromAddr, toAddr, amt)
+	return sendCoins(ctx, keeper.am, keeper.tr, fromAddr, toAddr, amt)
 }
 
 // InputOutputCoins handles a list of inputs and outputs
 func (keeper SendKeeper) InputOutputCoins(ctx sdk.Context, inputs []Input, outputs []Output) sdk.Error {
-	return inputOutputCoins(ctx, keeper.am, inputs, outputs)
+	return inputOutputCoins(ctx, keeper.am, keeper.tr, inputs, outputs)
 }
 
 //______________________________________________________________________________________________
@@ -131,14 +169,86 @@ func hasCoins(ctx sdk.Context, am sdk.AccountMapper, addr sdk.Address, amt sdk.C
 }
 
 // SubtractCoins subtracts amt from the coins at the addr.
+// Only the spendable coins can be subtracted, not the locked coins of a vesting account.
 func subtractCoins(ctx sdk.Context, am sdk.AccountMapper, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
-	oldCoins := getCoins(ctx, am, addr)
+	oldCoins, spendable := sdk.Coins{}, sdk.Coins{}
+	acc := am.GetAccount(ctx, addr)
+	if acc != nil {
+		oldCoins = acc.GetCoins()
+		spendable = auth.SpendableCoins(acc, ctx.BlockHeader().Time)
+	}
+	if !spendable.Minus(amt).IsNotNegative() {
+		return amt, sdk.ErrInsufficientCoins(fmt.Sprintf("%s < %s", spendable, amt))
+	}
+	newCoins := oldCoins.Minus(amt)
+	err := setCoins(ctx, am, addr, newCoins)
+	return newCoins, err
+}
+
+// DelegateCoins subtracts amt from the coins at the addr, to delegate them.
+// The locked coins of a vesting account can be delegated, the account tracks them.
+func delegateCoins(ctx sdk.Context, am sdk.AccountMapper, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	acc := am.GetAccount(ctx, addr)
+	if acc == nil {
+		return amt, sdk.ErrInsufficientCoins(fmt.Sprintf("%s < %s", sdk.Coins{}, amt))
+	}
+	oldCoins := acc.GetCoins()
 	newCoins := oldCoins.Minus(amt)
 	if !newCoins.IsNotNegative() {
 		return amt, sdk.ErrInsufficientCoins(fmt.Sprintf("%s < %s", oldCoins, amt))
 	}
-	err := setCoins(ctx, am, addr, newCoins)
-	return newCoins, err
+	if vacc, ok := acc.(auth.VestingAccount); ok {
+		vacc.TrackDelegation(ctx.BlockHeader().Time, amt)
+	}
+	acc.SetCoins(newCoins)
+	am.SetAccount(ctx, acc)
+	return newCoins, nil
+}
+
+// UndelegateCoins adds amt undelegated back to the coins at the addr.
+func undelegateCoins(ctx sdk.Context, am sdk.AccountMapper, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	acc := am.GetAccount(ctx, addr)
+	if acc == nil {
+		return addCoins(ctx, am, addr, amt)
+	}
+	if vacc, ok := acc.(auth.VestingAccount); ok {
+		vacc.TrackUndelegation(amt)
+	}
+	newCoins := acc.GetCoins().Plus(amt)
+	acc.SetCoins(newCoins)
+	am.SetAccount(ctx, acc)
+	return newCoins, nil
+}
+
+// AddVestingCoins adds amt to the coins at the addr, vesting linearly from the start
+// to the end time, or at the end time with a zero start time. The account becomes
+// a vesting account, an account which is vesting already can't vest other coins.
+func addVestingCoins(ctx sdk.Context, am sdk.AccountMapper, addr sdk.Address, amt sdk.Coins, startTime, endTime int64) (sdk.Coins, sdk.Error) {
+	if endTime <= startTime {
+		return amt, ErrInvalidVesting(DefaultCodespace, "Vesting must end after its start")
+	}
+	acc := am.GetAccount(ctx, addr)
+	if acc == nil {
+		acc = am.NewAccountWithAddress(ctx, addr)
+	}
+	if _, ok := acc.(auth.VestingAccount); ok {
+		return amt, ErrInvalidVesting(DefaultCodespace, fmt.Sprintf("Account %s is vesting already", addr))
+	}
+
+	// the base of the vesting account, from the fields of the account
+	base := auth.NewBaseAccountWithAddress(addr)
+	base.Coins = acc.GetCoins().Plus(amt)
+	base.PubKey = acc.GetPubKey()
+	base.Sequence = acc.GetSequence()
+
+	var vacc auth.VestingAccount
+	if startTime == 0 {
+		vacc = auth.NewDelayedVestingAccount(base, amt, endTime)
+	} else {
+		vacc = auth.NewContinuousVestingAccount(base, amt, startTime, endTime)
+	}
+	am.SetAccount(ctx, vacc)
+	return vacc.GetCoins(), nil
 }
 
 // AddCoins adds amt to the coins at the addr.
@@ -152,9 +262,16 @@ func addCoins(ctx sdk.Context, am sdk.AccountMapper, addr sdk.Address, amt sdk.C
 	return newCoins, err
 }
 
-// SendCoins moves coins from one account to another
+// SendCoins moves coins from one account to another, if the restrictions allow it
 // NOTE: Make sure to revert state changes from tx on error
-func sendCoins(ctx sdk.Context, am sdk.AccountMapper, fromAddr sdk.Address, toAddr sdk.Address, amt sdk.Coins) sdk.Error {
+func sendCoins(ctx sdk.Context, am sdk.AccountMapper, tr TransferRestrictions, fromAddr sdk.Address, toAddr sdk.Address, amt sdk.Coins) sdk.Error {
+	if tr != nil {
+		err := tr.CheckTransfer(ctx, []Input{NewInput(fromAddr, amt)}, []Output{NewOutput(toAddr, amt)})
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err := subtractCoins(ctx, am, fromAddr, amt)
 	if err != nil {
 		return err
@@ -168,9 +285,16 @@ func sendCoins(ctx sdk.Context, am sdk.AccountMapper, fromAddr sdk.Address, toAd
 	return nil
 }
 
-// InputOutputCoins handles a list of inputs and outputs
+// InputOutputCoins handles a list of inputs and outputs, if the restrictions allow them
 // NOTE: Make sure to revert state changes from tx on error
-func inputOutputCoins(ctx sdk.Context, am sdk.AccountMapper, inputs []Input, outputs []Output) sdk.Error {
+func inputOutputCoins(ctx sdk.Context, am sdk.AccountMapper, tr TransferRestrictions, inputs []Input, outputs []Output) sdk.Error {
+	if tr != nil {
+		err := tr.CheckTransfer(ctx, inputs, outputs)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, in := range inputs {
 		_, err := subtractCoins(ctx, am, in.Address, in.Coins)
 		if err != nil {
@@ -1,6 +1,7 @@
 package bank
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,11 +10,11 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	"github.com/cosmos/cosmos-sdk/store"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	wire "github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	wire "inschain-tendermint/wire"
 
-	"github.com/cosmos/cosmos-sdk/x/auth"
+	"inschain-tendermint/x/auth"
 )
 
 func setupMultiStore() (sdk.MultiStore, *sdk.KVStoreKey) {
@@ -207,3 +208,99 @@ func TestViewKeeper(t *testing.T) {
 	assert.False(t, viewKeeper.HasCoins(ctx, addr, sdk.Coins{{"foocoin", 15}}))
 	assert.False(t, viewKeeper.HasCoins(ctx, addr, sdk.Coins{{"barcoin", 5}}))
 }
+
+func TestVestingKeeper(t *testing.T) {
+	ms, authKey := setupMultiStore()
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{Time: 1000}, false, nil, log.NewNopLogger())
+	accountMapper := auth.NewAccountMapper(cdc, authKey, &auth.BaseAccount{})
+	coinKeeper := NewKeeper(accountMapper)
+
+	addr := sdk.Address([]byte("addr1"))
+	addr2 := sdk.Address([]byte("addr2"))
+	coinKeeper.SetCoins(ctx, addr, sdk.Coins{{"foocoin", 10}})
+
+	// the vesting coins are added to the account, which can't vest other coins
+	_, err := coinKeeper.AddVestingCoins(ctx, addr, sdk.Coins{{"foocoin", 100}}, 0, 2000)
+	assert.Nil(t, err)
+	assert.True(t, coinKeeper.GetCoins(ctx, addr).IsEqual(sdk.Coins{{"foocoin", 110}}))
+	_, err = coinKeeper.AddVestingCoins(ctx, addr, sdk.Coins{{"foocoin", 100}}, 0, 2000)
+	assert.NotNil(t, err)
+	_, err = coinKeeper.AddVestingCoins(ctx, addr2, sdk.Coins{{"foocoin", 100}}, 2000, 2000)
+	assert.NotNil(t, err)
+
+	// only the vested coins can be sent
+	err = coinKeeper.SendCoins(ctx, addr, addr2, sdk.Coins{{"foocoin", 11}})
+	assert.NotNil(t, err)
+	err = coinKeeper.SendCoins(ctx, addr, addr2, sdk.Coins{{"foocoin", 5}})
+	assert.Nil(t, err)
+
+	// the locked coins can be delegated and undelegated
+	_, err = coinKeeper.DelegateCoins(ctx, addr, sdk.Coins{{"foocoin", 100}})
+	assert.Nil(t, err)
+	assert.True(t, coinKeeper.GetCoins(ctx, addr).IsEqual(sdk.Coins{{"foocoin", 5}}))
+	err = coinKeeper.SendCoins(ctx, addr, addr2, sdk.Coins{{"foocoin", 5}})
+	assert.Nil(t, err)
+	_, err = coinKeeper.UndelegateCoins(ctx, addr, sdk.Coins{{"foocoin", 100}})
+	assert.Nil(t, err)
+	err = coinKeeper.SendCoins(ctx, addr, addr2, sdk.Coins{{"foocoin", 1}})
+	assert.NotNil(t, err)
+
+	// all the coins can be sent after the cliff
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 2000})
+	err = coinKeeper.SendCoins(ctx, addr, addr2, sdk.Coins{{"foocoin", 100}})
+	assert.Nil(t, err)
+}
+
+// blocks the transfers from or to an address
+type blockAddress sdk.Address
+
+func (b blockAddress) CheckTransfer(ctx sdk.Context, inputs []Input, outputs []Output) sdk.Error {
+	for _, in := range inputs {
+		if bytes.Equal(in.Address, b) {
+			return sdk.ErrUnauthorized("blocked")
+		}
+	}
+	for _, out := range outputs {
+		if bytes.Equal(out.Address, b) {
+			return sdk.ErrUnauthorized("blocked")
+		}
+	}
+	return nil
+}
+
+func TestTransferRestrictions(t *testing.T) {
+	ms, authKey := setupMultiStore()
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, nil, log.NewNopLogger())
+	accountMapper := auth.NewAccountMapper(cdc, authKey, &auth.BaseAccount{})
+	addr := sdk.Address([]byte("addr1"))
+	addr2 := sdk.Address([]byte("addr2"))
+	blocked := sdk.Address([]byte("blocked"))
+	coinKeeper := NewKeeper(accountMapper).WithTransferRestrictions(blockAddress(blocked))
+	sendKeeper := NewSendKeeper(accountMapper).WithTransferRestrictions(blockAddress(blocked))
+
+	coinKeeper.SetCoins(ctx, addr, sdk.Coins{{"foocoin", 10}})
+	coinKeeper.SetCoins(ctx, blocked, sdk.Coins{{"foocoin", 10}})
+
+	// the blocked address neither sends nor receives
+	assert.NotNil(t, coinKeeper.SendCoins(ctx, addr, blocked, sdk.Coins{{"foocoin", 5}}))
+	assert.NotNil(t, sendKeeper.SendCoins(ctx, blocked, addr, sdk.Coins{{"foocoin", 5}}))
+	inputs := []Input{NewInput(addr, sdk.Coins{{"foocoin", 2}}), NewInput(blocked, sdk.Coins{{"foocoin", 2}})}
+	outputs := []Output{NewOutput(addr2, sdk.Coins{{"foocoin", 4}})}
+	assert.NotNil(t, coinKeeper.InputOutputCoins(ctx, inputs, outputs))
+	assert.True(t, coinKeeper.GetCoins(ctx, addr).IsEqual(sdk.Coins{{"foocoin", 10}}))
+	assert.True(t, coinKeeper.GetCoins(ctx, blocked).IsEqual(sdk.Coins{{"foocoin", 10}}))
+
+	// the other transfers go through, and coins are still minted to it
+	assert.Nil(t, coinKeeper.SendCoins(ctx, addr, addr2, sdk.Coins{{"foocoin", 5}}))
+	assert.True(t, coinKeeper.GetCoins(ctx, addr2).IsEqual(sdk.Coins{{"foocoin", 5}}))
+	_, err := coinKeeper.AddCoins(ctx, blocked, sdk.Coins{{"foocoin", 5}})
+	assert.Nil(t, err)
+}
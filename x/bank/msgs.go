@@ -2,10 +2,19 @@ package bank
 
 import (
 	"encoding/json"
+	"fmt"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	cmn "github.com/tendermint/tmlibs/common"
+
+	sdk "inschain-tendermint/types"
 )
 
+// name to identify the token msgs, routed to the token handler
+const TokenMsgType = "token"
+
+// name to identify the hashed timelock escrow msgs, routed to the htlc handler
+const HTLCMsgType = "htlc"
+
 // MsgSend - high level transaction of the coin module
 type MsgSend struct {
 	Inputs  []Input  `json:"inputs"`
@@ -74,23 +83,25 @@ func (msg MsgSend) GetSigners() []sdk.Address {
 //----------------------------------------
 // MsgIssue
 
-// MsgIssue - high level transaction of the coin module
+// MsgIssue - the banker mints coins of the tokens it issues to the outputs
 type MsgIssue struct {
 	Banker  sdk.Address `json:"banker"`
 	Outputs []Output    `json:"outputs"`
 }
 
-// NewMsgIssue - construct arbitrary multi-in, multi-out send msg.
+// NewMsgIssue - construct the msg minting coins to the outputs
 func NewMsgIssue(banker sdk.Address, out []Output) MsgIssue {
 	return MsgIssue{Banker: banker, Outputs: out}
 }
 
 // Implements Msg.
-func (msg MsgIssue) Type() string { return "bank" } // TODO: "bank/issue"
+func (msg MsgIssue) Type() string { return TokenMsgType }
 
 // Implements Msg.
 func (msg MsgIssue) ValidateBasic() sdk.Error {
-	// XXX
+	if len(msg.Banker) == 0 {
+		return sdk.ErrInvalidAddress(msg.Banker.String())
+	}
 	if len(msg.Outputs) == 0 {
 		return ErrNoOutputs(DefaultCodespace).Trace("")
 	}
@@ -116,6 +127,348 @@ func (msg MsgIssue) GetSigners() []sdk.Address {
 	return []sdk.Address{msg.Banker}
 }
 
+//----------------------------------------
+// MsgRegisterToken
+
+// MsgRegisterToken - register a new denom, the signer becomes its issuer
+type MsgRegisterToken struct {
+	Issuer    sdk.Address `json:"issuer"`
+	Denom     string      `json:"denom"`
+	Name      string      `json:"name"`
+	Decimals  uint8       `json:"decimals"`
+	MaxSupply int64       `json:"max_supply"`
+}
+
+var _ sdk.Msg = MsgRegisterToken{}
+
+// NewMsgRegisterToken - construct the msg registering the token
+func NewMsgRegisterToken(token Token) MsgRegisterToken {
+	return MsgRegisterToken{
+		Issuer:    token.Issuer,
+		Denom:     token.Denom,
+		Name:      token.Name,
+		Decimals:  token.Decimals,
+		MaxSupply: token.MaxSupply,
+	}
+}
+
+// Token returns the token registered by the msg
+func (msg MsgRegisterToken) Token() Token {
+	return NewToken(msg.Denom, msg.Name, msg.Decimals, msg.MaxSupply, msg.Issuer)
+}
+
+// Implements Msg.
+func (msg MsgRegisterToken) Type() string { return TokenMsgType }
+
+// Implements Msg.
+func (msg MsgRegisterToken) ValidateBasic() sdk.Error {
+	if len(msg.Issuer) == 0 {
+		return sdk.ErrInvalidAddress(msg.Issuer.String())
+	}
+	return msg.Token().ValidateBasic(DefaultCodespace)
+}
+
+// Implements Msg.
+func (msg MsgRegisterToken) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgRegisterToken) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Issuer}
+}
+
+//----------------------------------------
+// MsgBurn
+
+// MsgBurn - the owner destroys some of its coins
+type MsgBurn struct {
+	Owner sdk.Address `json:"owner"`
+	Coins sdk.Coins   `json:"coins"`
+}
+
+var _ sdk.Msg = MsgBurn{}
+
+// NewMsgBurn - construct the msg burning the coins
+func NewMsgBurn(owner sdk.Address, coins sdk.Coins) MsgBurn {
+	return MsgBurn{Owner: owner, Coins: coins}
+}
+
+// Implements Msg.
+func (msg MsgBurn) Type() string { return TokenMsgType }
+
+// Implements Msg.
+func (msg MsgBurn) ValidateBasic() sdk.Error {
+	if len(msg.Owner) == 0 {
+		return sdk.ErrInvalidAddress(msg.Owner.String())
+	}
+	if !msg.Coins.IsValid() || !msg.Coins.IsPositive() {
+		return sdk.ErrInvalidCoins(msg.Coins.String())
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgBurn) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgBurn) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Owner}
+}
+
+//----------------------------------------
+// MsgTransferIssuer
+
+// MsgTransferIssuer - the issuer of a token gives its rights to a new issuer
+type MsgTransferIssuer struct {
+	Issuer    sdk.Address `json:"issuer"`
+	Denom     string      `json:"denom"`
+	NewIssuer sdk.Address `json:"new_issuer"`
+}
+
+var _ sdk.Msg = MsgTransferIssuer{}
+
+// NewMsgTransferIssuer - construct the msg transferring the issuer rights
+func NewMsgTransferIssuer(issuer sdk.Address, denom string, newIssuer sdk.Address) MsgTransferIssuer {
+	return MsgTransferIssuer{Issuer: issuer, Denom: denom, NewIssuer: newIssuer}
+}
+
+// Implements Msg.
+func (msg MsgTransferIssuer) Type() string { return TokenMsgType }
+
+// Implements Msg.
+func (msg MsgTransferIssuer) ValidateBasic() sdk.Error {
+	if len(msg.Issuer) == 0 {
+		return sdk.ErrInvalidAddress(msg.Issuer.String())
+	}
+	if len(msg.NewIssuer) == 0 {
+		return sdk.ErrInvalidAddress(msg.NewIssuer.String())
+	}
+	if len(msg.Denom) == 0 {
+		return ErrInvalidToken(DefaultCodespace, "denom cannot be empty")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgTransferIssuer) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgTransferIssuer) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Issuer}
+}
+
+//----------------------------------------
+// MsgRenounceIssuer
+
+// MsgRenounceIssuer - the issuer of a token gives up its rights for good,
+// the supply of the token can't grow anymore
+type MsgRenounceIssuer struct {
+	Issuer sdk.Address `json:"issuer"`
+	Denom  string      `json:"denom"`
+}
+
+var _ sdk.Msg = MsgRenounceIssuer{}
+
+// NewMsgRenounceIssuer - construct the msg renouncing the issuer rights
+func NewMsgRenounceIssuer(issuer sdk.Address, denom string) MsgRenounceIssuer {
+	return MsgRenounceIssuer{Issuer: issuer, Denom: denom}
+}
+
+// Implements Msg.
+func (msg MsgRenounceIssuer) Type() string { return TokenMsgType }
+
+// Implements Msg.
+func (msg MsgRenounceIssuer) ValidateBasic() sdk.Error {
+	if len(msg.Issuer) == 0 {
+		return sdk.ErrInvalidAddress(msg.Issuer.String())
+	}
+	if len(msg.Denom) == 0 {
+		return ErrInvalidToken(DefaultCodespace, "denom cannot be empty")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgRenounceIssuer) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgRenounceIssuer) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Issuer}
+}
+
+//----------------------------------------
+// MsgCreateHTLC
+
+// MsgCreateHTLC - lock coins of the sender toward the recipient under a
+// SHA-256 hashlock until the expiry height
+type MsgCreateHTLC struct {
+	Sender       sdk.Address  `json:"sender"`
+	Recipient    sdk.Address  `json:"recipient"`
+	Amount       sdk.Coins    `json:"amount"`
+	HashLock     cmn.HexBytes `json:"hash_lock"`
+	ExpiryHeight int64        `json:"expiry_height"`
+}
+
+var _ sdk.Msg = MsgCreateHTLC{}
+
+// NewMsgCreateHTLC - construct the msg locking the amount
+func NewMsgCreateHTLC(sender, recipient sdk.Address, amount sdk.Coins, hashLock []byte, expiryHeight int64) MsgCreateHTLC {
+	return MsgCreateHTLC{
+		Sender:       sender,
+		Recipient:    recipient,
+		Amount:       amount,
+		HashLock:     hashLock,
+		ExpiryHeight: expiryHeight,
+	}
+}
+
+// Implements Msg.
+func (msg MsgCreateHTLC) Type() string { return HTLCMsgType }
+
+// Implements Msg.
+func (msg MsgCreateHTLC) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+	if len(msg.Recipient) == 0 {
+		return sdk.ErrInvalidAddress(msg.Recipient.String())
+	}
+	if !msg.Amount.IsValid() || !msg.Amount.IsPositive() {
+		return sdk.ErrInvalidCoins(msg.Amount.String())
+	}
+	if len(msg.HashLock) != HashLockLength {
+		return ErrInvalidHTLC(DefaultCodespace, fmt.Sprintf("the hashlock must be %d bytes long", HashLockLength))
+	}
+	if msg.ExpiryHeight <= 0 {
+		return ErrInvalidHTLC(DefaultCodespace, "the expiry height must be positive")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgCreateHTLC) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgCreateHTLC) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Sender}
+}
+
+//----------------------------------------
+// MsgClaimHTLC
+
+// MsgClaimHTLC - the recipient of an escrow claims its coins with the
+// preimage of its hashlock
+type MsgClaimHTLC struct {
+	Recipient sdk.Address  `json:"recipient"`
+	HTLCID    int64        `json:"htlc_id"`
+	Preimage  cmn.HexBytes `json:"preimage"`
+}
+
+var _ sdk.Msg = MsgClaimHTLC{}
+
+// NewMsgClaimHTLC - construct the msg claiming the escrow
+func NewMsgClaimHTLC(recipient sdk.Address, htlcID int64, preimage []byte) MsgClaimHTLC {
+	return MsgClaimHTLC{Recipient: recipient, HTLCID: htlcID, Preimage: preimage}
+}
+
+// Implements Msg.
+func (msg MsgClaimHTLC) Type() string { return HTLCMsgType }
+
+// Implements Msg.
+func (msg MsgClaimHTLC) ValidateBasic() sdk.Error {
+	if len(msg.Recipient) == 0 {
+		return sdk.ErrInvalidAddress(msg.Recipient.String())
+	}
+	if len(msg.Preimage) == 0 || len(msg.Preimage) > MaxPreimageSize {
+		return ErrInvalidHTLC(DefaultCodespace, fmt.Sprintf("the preimage must be 1 ~ %d bytes long", MaxPreimageSize))
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgClaimHTLC) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgClaimHTLC) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Recipient}
+}
+
+//----------------------------------------
+// MsgRefundHTLC
+
+// MsgRefundHTLC - the sender of an expired escrow takes its coins back
+type MsgRefundHTLC struct {
+	Sender sdk.Address `json:"sender"`
+	HTLCID int64       `json:"htlc_id"`
+}
+
+var _ sdk.Msg = MsgRefundHTLC{}
+
+// NewMsgRefundHTLC - construct the msg refunding the escrow
+func NewMsgRefundHTLC(sender sdk.Address, htlcID int64) MsgRefundHTLC {
+	return MsgRefundHTLC{Sender: sender, HTLCID: htlcID}
+}
+
+// Implements Msg.
+func (msg MsgRefundHTLC) Type() string { return HTLCMsgType }
+
+// Implements Msg.
+func (msg MsgRefundHTLC) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgRefundHTLC) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgRefundHTLC) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Sender}
+}
+
 //----------------------------------------
 // Input
 
@@ -6,7 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 func TestNewMsgSend(t *testing.T) {}
@@ -240,7 +240,7 @@ func TestMsgIssueType(t *testing.T) {
 	}
 
 	// TODO some failures for bad result
-	assert.Equal(t, msg.Type(), "bank")
+	assert.Equal(t, msg.Type(), "token")
 }
 
 func TestMsgIssueValidation(t *testing.T) {
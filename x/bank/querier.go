@@ -0,0 +1,170 @@
+package bank
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+// query endpoints supported by the token querier, at /custom/token/<route>
+const (
+	QueryToken  = "token"
+	QueryTokens = "tokens"
+)
+
+// query endpoints supported by the supply querier, at /custom/supply/<route>
+const (
+	QuerySupply      = "supply"
+	QueryTotalSupply = "total"
+	QueryEscrows     = "escrows"
+)
+
+// query endpoints supported by the htlc querier, at /custom/htlc/<route>
+const (
+	QueryHTLC  = "htlc"
+	QueryHTLCs = "htlcs"
+)
+
+// parameters of the token query
+type QueryTokenParams struct {
+	Denom string `json:"denom"`
+}
+
+// parameters of the supply query
+type QuerySupplyParams struct {
+	Denom string `json:"denom"`
+}
+
+// parameters of the htlc query
+type QueryHTLCParams struct {
+	HTLCID int64 `json:"htlc_id"`
+}
+
+// parameters of the htlcs query, only the escrows of which the address is
+// the sender or the recipient if not empty
+type QueryHTLCsParams struct {
+	Address sdk.Address `json:"address"`
+}
+
+// NewTokenQuerier returns the querier of the registered tokens
+func NewTokenQuerier(k TokenKeeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("No token query endpoint specified")
+		}
+		switch path[0] {
+		case QueryToken:
+			return queryToken(ctx, req, k)
+		case QueryTokens:
+			return k.marshalQueryResult(k.GetTokens(ctx))
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Unknown token query endpoint %s", path[0]))
+		}
+	}
+}
+
+func queryToken(ctx sdk.Context, req abci.RequestQuery, k TokenKeeper) ([]byte, sdk.Error) {
+	var params QueryTokenParams
+	err := k.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Incorrectly formatted query data: %s", err.Error()))
+	}
+	token, found := k.GetToken(ctx, params.Denom)
+	if !found {
+		return nil, ErrUnknownToken(k.codespace, params.Denom)
+	}
+	return k.marshalQueryResult(token)
+}
+
+func (k TokenKeeper) marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	bz, err := k.cdc.MarshalJSON(res)
+	if err != nil {
+		panic(err)
+	}
+	return bz, nil
+}
+
+// NewSupplyQuerier returns the querier of the supply and the escrows
+func NewSupplyQuerier(k SupplyKeeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("No supply query endpoint specified")
+		}
+		switch path[0] {
+		case QuerySupply:
+			return querySupply(ctx, req, k)
+		case QueryTotalSupply:
+			return k.marshalQueryResult(k.GetTotalSupply(ctx))
+		case QueryEscrows:
+			return k.marshalQueryResult(k.GetEscrows(ctx))
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Unknown supply query endpoint %s", path[0]))
+		}
+	}
+}
+
+func querySupply(ctx sdk.Context, req abci.RequestQuery, k SupplyKeeper) ([]byte, sdk.Error) {
+	var params QuerySupplyParams
+	err := k.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Incorrectly formatted query data: %s", err.Error()))
+	}
+	return k.marshalQueryResult(sdk.Coin{params.Denom, k.GetSupply(ctx, params.Denom)})
+}
+
+func (k SupplyKeeper) marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	bz, err := k.cdc.MarshalJSON(res)
+	if err != nil {
+		panic(err)
+	}
+	return bz, nil
+}
+
+// NewHTLCQuerier returns the querier of the hashed timelock escrows
+func NewHTLCQuerier(k HTLCKeeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("No htlc query endpoint specified")
+		}
+		switch path[0] {
+		case QueryHTLC:
+			return queryHTLC(ctx, req, k)
+		case QueryHTLCs:
+			return queryHTLCs(ctx, req, k)
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Unknown htlc query endpoint %s", path[0]))
+		}
+	}
+}
+
+func queryHTLC(ctx sdk.Context, req abci.RequestQuery, k HTLCKeeper) ([]byte, sdk.Error) {
+	var params QueryHTLCParams
+	err := k.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Incorrectly formatted query data: %s", err.Error()))
+	}
+	htlc, found := k.GetHTLC(ctx, params.HTLCID)
+	if !found {
+		return nil, ErrUnknownHTLC(k.codespace, params.HTLCID)
+	}
+	return k.marshalQueryResult(htlc)
+}
+
+func queryHTLCs(ctx sdk.Context, req abci.RequestQuery, k HTLCKeeper) ([]byte, sdk.Error) {
+	var params QueryHTLCsParams
+	err := k.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Incorrectly formatted query data: %s", err.Error()))
+	}
+	return k.marshalQueryResult(k.GetHTLCs(ctx, params.Address))
+}
+
+func (k HTLCKeeper) marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	bz, err := k.cdc.MarshalJSON(res)
+	if err != nil {
+		panic(err)
+	}
+	return bz, nil
+}
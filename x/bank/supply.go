@@ -0,0 +1,390 @@
+package bank
+
+import (
+	"fmt"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+)
+
+// The supply of a denom is the amount of its coins in the accounts and in the
+// escrows of the modules, like the bonded tokens of the stake pool. The
+// SupplyKeeper is the only way the modules create and destroy coins, or move
+// them out of the accounts, so it records the supply of every denom and the
+// coins every module holds. The coins minted, burned, escrowed or released
+// are checked against the transfer restrictions like any transfer, except the
+// refunds of escrowed coins to their owner.
+
+var (
+	// Keys for store prefixes
+	SupplyKeyPrefix = []byte{0x00} // prefix for each key to the supply of a denom
+	EscrowKeyPrefix = []byte{0x01} // prefix for each key to the coins held by a module
+)
+
+// get the key for the supply of the denom
+func GetSupplyKey(denom string) []byte {
+	return append(SupplyKeyPrefix, []byte(denom)...)
+}
+
+// get the key for the escrow of the holder
+func GetEscrowKey(holder string) []byte {
+	return append(EscrowKeyPrefix, []byte(holder)...)
+}
+
+// Escrow - the coins held by a module, out of the accounts
+type Escrow struct {
+	Holder string    `json:"holder"`
+	Coins  sdk.Coins `json:"coins"`
+}
+
+// SupplyGenesisState - the supply of every denom and the escrows holding part of it
+type SupplyGenesisState struct {
+	Supply  sdk.Coins `json:"supply"`
+	Escrows []Escrow  `json:"escrows"`
+}
+
+//______________________________________________________________________________________________
+
+// SupplyKeeper mints and burns coins and moves them in and out of the
+// escrows of the modules, recording the supply of every denom
+type SupplyKeeper struct {
+	key        sdk.StoreKey
+	cdc        *wire.Codec
+	coinKeeper Keeper
+	tr         TransferRestrictions
+	codespace  sdk.CodespaceType
+}
+
+// NewSupplyKeeper returns a new SupplyKeeper
+func NewSupplyKeeper(cdc *wire.Codec, key sdk.StoreKey, ck Keeper, codespace sdk.CodespaceType) SupplyKeeper {
+	return SupplyKeeper{
+		key:        key,
+		cdc:        cdc,
+		coinKeeper: ck,
+		codespace:  codespace,
+	}
+}
+
+// WithTransferRestrictions returns the keeper checking the coins moving in
+// and out of the accounts against the restrictions
+func (k SupplyKeeper) WithTransferRestrictions(tr TransferRestrictions) SupplyKeeper {
+	k.tr = tr
+	return k
+}
+
+// check the coins leaving the account of the addr against the restrictions
+func (k SupplyKeeper) checkInput(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) sdk.Error {
+	if k.tr == nil {
+		return nil
+	}
+	return k.tr.CheckTransfer(ctx, []Input{NewInput(addr, amt)}, nil)
+}
+
+// check the coins coming into the account of the addr against the restrictions
+func (k SupplyKeeper) checkOutput(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) sdk.Error {
+	if k.tr == nil {
+		return nil
+	}
+	return k.tr.CheckTransfer(ctx, nil, []Output{NewOutput(addr, amt)})
+}
+
+// GetCoins returns the coins at the addr.
+func (k SupplyKeeper) GetCoins(ctx sdk.Context, addr sdk.Address) sdk.Coins {
+	return k.coinKeeper.GetCoins(ctx, addr)
+}
+
+// HasCoins returns whether or not an account has at least amt coins.
+func (k SupplyKeeper) HasCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) bool {
+	return k.coinKeeper.HasCoins(ctx, addr, amt)
+}
+
+// SendCoins moves coins from one account to another, the supply is unchanged
+func (k SupplyKeeper) SendCoins(ctx sdk.Context, fromAddr sdk.Address, toAddr sdk.Address, amt sdk.Coins) sdk.Error {
+	return k.coinKeeper.SendCoins(ctx, fromAddr, toAddr, amt)
+}
+
+// GetSupply returns the supply of the denom
+func (k SupplyKeeper) GetSupply(ctx sdk.Context, denom string) int64 {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetSupplyKey(denom))
+	if bz == nil {
+		return 0
+	}
+	var supply int64
+	err := k.cdc.UnmarshalJSON(bz, &supply)
+	if err != nil {
+		panic(err)
+	}
+	return supply
+}
+
+// GetTotalSupply returns the supply of every denom
+func (k SupplyKeeper) GetTotalSupply(ctx sdk.Context) (supply sdk.Coins) {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(SupplyKeyPrefix)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var amount int64
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &amount)
+		if err != nil {
+			panic(err)
+		}
+		denom := string(iterator.Key()[len(SupplyKeyPrefix):])
+		supply = append(supply, sdk.Coin{denom, amount})
+	}
+	return supply
+}
+
+// the supply of a denom is removed once all its coins are burned
+func (k SupplyKeeper) setSupply(ctx sdk.Context, denom string, supply int64) {
+	store := ctx.KVStore(k.key)
+	if supply == 0 {
+		store.Delete(GetSupplyKey(denom))
+		return
+	}
+	bz, err := k.cdc.MarshalJSON(supply)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(GetSupplyKey(denom), bz)
+}
+
+// add the coins to the supply, negative coins are removed from it
+func (k SupplyKeeper) updateSupply(ctx sdk.Context, coins sdk.Coins) {
+	for _, coin := range coins {
+		supply := k.GetSupply(ctx, coin.Denom) + coin.Amount
+		if supply < 0 {
+			panic(fmt.Sprintf("negative supply of %s", coin.Denom))
+		}
+		k.setSupply(ctx, coin.Denom, supply)
+	}
+}
+
+// GetEscrow returns the coins held by the holder
+func (k SupplyKeeper) GetEscrow(ctx sdk.Context, holder string) sdk.Coins {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetEscrowKey(holder))
+	if bz == nil {
+		return nil
+	}
+	var coins sdk.Coins
+	err := k.cdc.UnmarshalJSON(bz, &coins)
+	if err != nil {
+		panic(err)
+	}
+	return coins
+}
+
+// GetEscrows returns the coins held by every holder
+func (k SupplyKeeper) GetEscrows(ctx sdk.Context) []Escrow {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(EscrowKeyPrefix)
+	defer iterator.Close()
+
+	escrows := []Escrow{}
+	for ; iterator.Valid(); iterator.Next() {
+		var coins sdk.Coins
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &coins)
+		if err != nil {
+			panic(err)
+		}
+		holder := string(iterator.Key()[len(EscrowKeyPrefix):])
+		escrows = append(escrows, Escrow{holder, coins})
+	}
+	return escrows
+}
+
+func (k SupplyKeeper) setEscrow(ctx sdk.Context, holder string, coins sdk.Coins) {
+	store := ctx.KVStore(k.key)
+	if coins.IsZero() {
+		store.Delete(GetEscrowKey(holder))
+		return
+	}
+	bz, err := k.cdc.MarshalJSON(coins)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(GetEscrowKey(holder), bz)
+}
+
+// add the coins to the escrow of the holder, negative coins are removed from it
+func (k SupplyKeeper) updateEscrow(ctx sdk.Context, holder string, coins sdk.Coins) sdk.Error {
+	escrow := k.GetEscrow(ctx, holder).Plus(coins)
+	if !escrow.IsNotNegative() {
+		return sdk.ErrInsufficientCoins(fmt.Sprintf("%s holds %v in escrow", holder, k.GetEscrow(ctx, holder)))
+	}
+	k.setEscrow(ctx, holder, escrow)
+	return nil
+}
+
+// Mint creates the coins in the account of the addr
+func (k SupplyKeeper) Mint(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	if err := k.checkOutput(ctx, addr, amt); err != nil {
+		return nil, err
+	}
+	coins, err := k.coinKeeper.AddCoins(ctx, addr, amt)
+	if err != nil {
+		return nil, err
+	}
+	k.updateSupply(ctx, amt)
+	return coins, nil
+}
+
+// Burn destroys the coins of the account of the addr
+func (k SupplyKeeper) Burn(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	if err := k.checkInput(ctx, addr, amt); err != nil {
+		return nil, err
+	}
+	coins, err := k.coinKeeper.SubtractCoins(ctx, addr, amt)
+	if err != nil {
+		return nil, err
+	}
+	k.updateSupply(ctx, amt.Negative())
+	return coins, nil
+}
+
+// MintEscrow creates the coins in the escrow of the holder
+func (k SupplyKeeper) MintEscrow(ctx sdk.Context, holder string, amt sdk.Coins) sdk.Error {
+	err := k.updateEscrow(ctx, holder, amt)
+	if err != nil {
+		return err
+	}
+	k.updateSupply(ctx, amt)
+	return nil
+}
+
+// BurnEscrow destroys coins held by the holder
+func (k SupplyKeeper) BurnEscrow(ctx sdk.Context, holder string, amt sdk.Coins) sdk.Error {
+	err := k.updateEscrow(ctx, holder, amt.Negative())
+	if err != nil {
+		return err
+	}
+	k.updateSupply(ctx, amt.Negative())
+	return nil
+}
+
+// AddEscrow records coins already taken out of the accounts as held by the
+// holder, like the fees deducted by the ante handler
+func (k SupplyKeeper) AddEscrow(ctx sdk.Context, holder string, amt sdk.Coins) sdk.Error {
+	return k.updateEscrow(ctx, holder, amt)
+}
+
+// EscrowCoins moves coins of the account of the addr to the escrow of the holder
+func (k SupplyKeeper) EscrowCoins(ctx sdk.Context, holder string, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	if err := k.checkInput(ctx, addr, amt); err != nil {
+		return nil, err
+	}
+	coins, err := k.coinKeeper.SubtractCoins(ctx, addr, amt)
+	if err != nil {
+		return nil, err
+	}
+	return coins, k.updateEscrow(ctx, holder, amt)
+}
+
+// DelegateCoins moves coins of the account of the addr to the escrow of the
+// holder, locked coins included
+func (k SupplyKeeper) DelegateCoins(ctx sdk.Context, holder string, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	if err := k.checkInput(ctx, addr, amt); err != nil {
+		return nil, err
+	}
+	coins, err := k.coinKeeper.DelegateCoins(ctx, addr, amt)
+	if err != nil {
+		return nil, err
+	}
+	return coins, k.updateEscrow(ctx, holder, amt)
+}
+
+// ReleaseCoins moves coins held by the holder to the account of the addr
+func (k SupplyKeeper) ReleaseCoins(ctx sdk.Context, holder string, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	if err := k.checkOutput(ctx, addr, amt); err != nil {
+		return nil, err
+	}
+	return k.RefundCoins(ctx, holder, addr, amt)
+}
+
+// RefundCoins moves coins held by the holder back to the account of the addr
+// which escrowed them. The restrictions are not checked, the owner gets its
+// own coins back even if frozen.
+func (k SupplyKeeper) RefundCoins(ctx sdk.Context, holder string, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	err := k.updateEscrow(ctx, holder, amt.Negative())
+	if err != nil {
+		return nil, err
+	}
+	return k.coinKeeper.AddCoins(ctx, addr, amt)
+}
+
+// UndelegateCoins moves coins held by the holder back to the account of the
+// addr which delegated them, unchecked like RefundCoins
+func (k SupplyKeeper) UndelegateCoins(ctx sdk.Context, holder string, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	err := k.updateEscrow(ctx, holder, amt.Negative())
+	if err != nil {
+		return nil, err
+	}
+	return k.coinKeeper.UndelegateCoins(ctx, addr, amt)
+}
+
+// ReleaseVestingCoins moves coins held by the holder to the account of the
+// addr, vesting from the start to the end time
+func (k SupplyKeeper) ReleaseVestingCoins(ctx sdk.Context, holder string, addr sdk.Address, amt sdk.Coins, startTime, endTime int64) (sdk.Coins, sdk.Error) {
+	if err := k.checkOutput(ctx, addr, amt); err != nil {
+		return nil, err
+	}
+	err := k.updateEscrow(ctx, holder, amt.Negative())
+	if err != nil {
+		return nil, err
+	}
+	return k.coinKeeper.AddVestingCoins(ctx, addr, amt, startTime, endTime)
+}
+
+//______________________________________________________________________________________________
+
+// SupplyInvariant checks that the coins of the accounts and of the escrows
+// add up to the supply of every denom
+func SupplyInvariant(ctx sdk.Context, am sdk.AccountMapper, k SupplyKeeper) error {
+	var total sdk.Coins
+	am.IterateAccounts(ctx, func(acc sdk.Account) bool {
+		total = total.Plus(acc.GetCoins())
+		return false
+	})
+	for _, escrow := range k.GetEscrows(ctx) {
+		total = total.Plus(escrow.Coins)
+	}
+	supply := k.GetTotalSupply(ctx)
+	for _, coin := range total.Plus(supply) {
+		if total.AmountOf(coin.Denom) != supply.AmountOf(coin.Denom) {
+			return fmt.Errorf("the accounts and escrows hold %v, the supply is %v", total, supply)
+		}
+	}
+	return nil
+}
+
+//______________________________________________________________________________________________
+
+// NewSupplyGenesisState - the supply of the coins of the genesis accounts, and
+// of the escrows
+func NewSupplyGenesisState(accountCoins sdk.Coins, escrows []Escrow) SupplyGenesisState {
+	supply := accountCoins
+	for _, escrow := range escrows {
+		supply = supply.Plus(escrow.Coins)
+	}
+	return SupplyGenesisState{Supply: supply, Escrows: escrows}
+}
+
+// InitSupplyGenesis - store the genesis supply and escrows
+func InitSupplyGenesis(ctx sdk.Context, k SupplyKeeper, data SupplyGenesisState) {
+	for _, coin := range data.Supply {
+		k.setSupply(ctx, coin.Denom, coin.Amount)
+	}
+	for _, escrow := range data.Escrows {
+		k.setEscrow(ctx, escrow.Holder, escrow.Coins)
+	}
+}
+
+// WriteSupplyGenesis - output the supply and the escrows
+func WriteSupplyGenesis(ctx sdk.Context, k SupplyKeeper) SupplyGenesisState {
+	return SupplyGenesisState{
+		Supply:  k.GetTotalSupply(ctx),
+		Escrows: k.GetEscrows(ctx),
+	}
+}
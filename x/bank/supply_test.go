@@ -0,0 +1,82 @@
+package bank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+func TestSupplyKeeper(t *testing.T) {
+	ctx, am, keeper, _ := createTokenTestInput(t)
+	addr := sdk.Address([]byte("addr1"))
+	addr2 := sdk.Address([]byte("addr2"))
+
+	// minting and burning change the supply
+	_, err := keeper.Mint(ctx, addr, sdk.Coins{{"bar", 5}, {"foo", 10}})
+	require.Nil(t, err)
+	_, err = keeper.Burn(ctx, addr, sdk.Coins{{"foo", 11}})
+	assert.NotNil(t, err)
+	_, err = keeper.Burn(ctx, addr, sdk.Coins{{"bar", 5}, {"foo", 4}})
+	require.Nil(t, err)
+	assert.Equal(t, int64(6), keeper.GetSupply(ctx, "foo"))
+	assert.True(t, keeper.GetTotalSupply(ctx).IsEqual(sdk.Coins{{"foo", 6}}))
+	assert.Nil(t, SupplyInvariant(ctx, am, keeper))
+
+	// moving coins in and out of escrows doesn't
+	_, err = keeper.EscrowCoins(ctx, "stake", addr, sdk.Coins{{"foo", 4}})
+	require.Nil(t, err)
+	_, err = keeper.ReleaseCoins(ctx, "stake", addr2, sdk.Coins{{"foo", 5}})
+	assert.NotNil(t, err)
+	_, err = keeper.ReleaseCoins(ctx, "stake", addr2, sdk.Coins{{"foo", 1}})
+	require.Nil(t, err)
+	assert.True(t, keeper.GetEscrow(ctx, "stake").IsEqual(sdk.Coins{{"foo", 3}}))
+	assert.Equal(t, int64(6), keeper.GetSupply(ctx, "foo"))
+	assert.Nil(t, SupplyInvariant(ctx, am, keeper))
+
+	// but minting and burning in escrow does
+	require.Nil(t, keeper.MintEscrow(ctx, "stake", sdk.Coins{{"foo", 7}}))
+	require.Nil(t, keeper.BurnEscrow(ctx, "stake", sdk.Coins{{"foo", 2}}))
+	assert.NotNil(t, keeper.BurnEscrow(ctx, "gov", sdk.Coins{{"foo", 1}}))
+	assert.Equal(t, int64(11), keeper.GetSupply(ctx, "foo"))
+	assert.Nil(t, SupplyInvariant(ctx, am, keeper))
+
+	// coins leaving the accounts without the keeper break the invariant
+	_, err = keeper.coinKeeper.SubtractCoins(ctx, addr2, sdk.Coins{{"foo", 1}})
+	require.Nil(t, err)
+	assert.NotNil(t, SupplyInvariant(ctx, am, keeper))
+	require.Nil(t, keeper.AddEscrow(ctx, "stake", sdk.Coins{{"foo", 1}}))
+	assert.Nil(t, SupplyInvariant(ctx, am, keeper))
+
+	// the genesis restores the supply and the escrows
+	genesis := WriteSupplyGenesis(ctx, keeper)
+	ctx2, _, keeper2, _ := createTokenTestInput(t)
+	InitSupplyGenesis(ctx2, keeper2, genesis)
+	assert.Equal(t, genesis, WriteSupplyGenesis(ctx2, keeper2))
+	assert.Equal(t, genesis, NewSupplyGenesisState(sdk.Coins{{"foo", 2}}, genesis.Escrows))
+}
+
+func TestSupplyQuerier(t *testing.T) {
+	ctx, _, keeper, _ := createTokenTestInput(t)
+	_, err := keeper.Mint(ctx, sdk.Address([]byte("addr1")), sdk.Coins{{"foo", 10}})
+	require.Nil(t, err)
+	querier := NewSupplyQuerier(keeper)
+
+	data, jerr := keeper.cdc.MarshalJSON(QuerySupplyParams{"foo"})
+	require.Nil(t, jerr)
+	bz, err := querier(ctx, []string{QuerySupply}, abci.RequestQuery{Data: data})
+	require.Nil(t, err)
+	var supply sdk.Coin
+	require.Nil(t, keeper.cdc.UnmarshalJSON(bz, &supply))
+	assert.Equal(t, sdk.Coin{"foo", 10}, supply)
+
+	bz, err = querier(ctx, []string{QueryTotalSupply}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var total sdk.Coins
+	require.Nil(t, keeper.cdc.UnmarshalJSON(bz, &total))
+	assert.True(t, total.IsEqual(sdk.Coins{{"foo", 10}}))
+}
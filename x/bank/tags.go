@@ -0,0 +1,62 @@
+package bank
+
+import (
+	"strconv"
+
+	sdk "inschain-tendermint/types"
+)
+
+// Tags attached to the results of bank messages, indexed by Tendermint.
+// Addresses are tagged in upper case hex.
+const (
+	TagAction    = "action"
+	TagSender    = "sender"
+	TagRecipient = "recipient"
+	TagIssuer    = "issuer"
+	TagDenom     = "denom"
+	TagHTLCID    = "htlc-id"
+)
+
+// Values of the action tag
+var (
+	ActionSend           = []byte("send")
+	ActionRegisterToken  = []byte("register-token")
+	ActionIssue          = []byte("issue")
+	ActionBurn           = []byte("burn")
+	ActionTransferIssuer = []byte("transfer-issuer")
+	ActionRenounceIssuer = []byte("renounce-issuer")
+	ActionCreateHTLC     = []byte("create-htlc")
+	ActionClaimHTLC      = []byte("claim-htlc")
+	ActionRefundHTLC     = []byte("refund-htlc")
+)
+
+// tags of a multi-in, multi-out transfer
+func sendTags(inputs []Input, outputs []Output) sdk.Tags {
+	tags := sdk.NewTags(TagAction, ActionSend)
+	for _, in := range inputs {
+		tags = tags.AppendTag(TagSender, []byte(in.Address.String()))
+	}
+	for _, out := range outputs {
+		tags = tags.AppendTag(TagRecipient, []byte(out.Address.String()))
+	}
+	return tags
+}
+
+// tags of a token msg of the issuer
+func tokenTags(action []byte, issuer sdk.Address, denom string) sdk.Tags {
+	return sdk.NewTags(
+		TagAction, action,
+		TagIssuer, []byte(issuer.String()),
+		TagDenom, []byte(denom),
+	)
+}
+
+// tags of a htlc msg, with the sender and the recipient of the escrow
+func htlcTags(action []byte, htlc HTLC) sdk.Tags {
+	return sdk.NewTags(
+		TagAction, action,
+		TagHTLCID, []byte(strconv.FormatInt(htlc.ID, 10)),
+		TagSender, []byte(htlc.Sender.String()),
+		TagRecipient, []byte(htlc.Recipient.String()),
+	)
+}
@@ -0,0 +1,247 @@
+package bank
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+)
+
+// Tokens are denoms registered with their metadata. The issuer of a token
+// mints it with MsgIssue, up to its max supply, and can transfer or renounce
+// its rights. The denoms of the genesis supply are native tokens without
+// issuer, so they can never be registered again or minted with MsgIssue.
+// The supply of the tokens is recorded by the SupplyKeeper.
+
+var (
+	// Keys for store prefixes
+	TokenKeyPrefix = []byte{0x00} // prefix for each key to a token, by denom
+
+	// Denominations can be 3 ~ 16 characters long, as parsed by sdk.ParseCoin
+	reDenom = regexp.MustCompile(`^[[:alpha:]][[:alnum:]]{2,15}$`)
+)
+
+// get the key for the token of the denom
+func GetTokenKey(denom string) []byte {
+	return append(TokenKeyPrefix, []byte(denom)...)
+}
+
+// Token - a registered denom and its metadata
+type Token struct {
+	Denom     string      `json:"denom"`
+	Name      string      `json:"name"`
+	Decimals  uint8       `json:"decimals"`
+	MaxSupply int64       `json:"max_supply"`
+	Issuer    sdk.Address `json:"issuer"` // nil for a native token or once renounced
+}
+
+// NewToken - a token to register
+func NewToken(denom, name string, decimals uint8, maxSupply int64, issuer sdk.Address) Token {
+	return Token{
+		Denom:     denom,
+		Name:      name,
+		Decimals:  decimals,
+		MaxSupply: maxSupply,
+		Issuer:    issuer,
+	}
+}
+
+// ValidateBasic - validate the metadata of a token to register
+func (token Token) ValidateBasic(codespace sdk.CodespaceType) sdk.Error {
+	if !reDenom.MatchString(token.Denom) {
+		return ErrInvalidToken(codespace, fmt.Sprintf("invalid denom %s, it must be 3 ~ 16 alphanumeric characters starting with a letter", token.Denom))
+	}
+	if len(token.Name) == 0 {
+		return ErrInvalidToken(codespace, "token name cannot be empty")
+	}
+	if token.Decimals > 18 {
+		return ErrInvalidToken(codespace, "decimals cannot be more than 18")
+	}
+	if token.MaxSupply <= 0 {
+		return ErrInvalidToken(codespace, "max supply must be positive")
+	}
+	return nil
+}
+
+// TokenGenesisState - the registered tokens
+type TokenGenesisState struct {
+	Tokens []Token `json:"tokens"`
+}
+
+//______________________________________________________________________________________________
+
+// TokenKeeper manages the registered tokens and mints and burns their coins
+type TokenKeeper struct {
+	key          sdk.StoreKey
+	cdc          *wire.Codec
+	supplyKeeper SupplyKeeper
+	codespace    sdk.CodespaceType
+}
+
+// NewTokenKeeper returns a new TokenKeeper
+func NewTokenKeeper(cdc *wire.Codec, key sdk.StoreKey, sk SupplyKeeper, codespace sdk.CodespaceType) TokenKeeper {
+	return TokenKeeper{
+		key:          key,
+		cdc:          cdc,
+		supplyKeeper: sk,
+		codespace:    codespace,
+	}
+}
+
+// GetToken returns the token of the denom, if registered
+func (k TokenKeeper) GetToken(ctx sdk.Context, denom string) (token Token, found bool) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetTokenKey(denom))
+	if bz == nil {
+		return token, false
+	}
+	err := k.cdc.UnmarshalJSON(bz, &token)
+	if err != nil {
+		panic(err)
+	}
+	return token, true
+}
+
+// GetTokens returns all the tokens, by denom
+func (k TokenKeeper) GetTokens(ctx sdk.Context) []Token {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(TokenKeyPrefix)
+	defer iterator.Close()
+
+	tokens := []Token{}
+	for ; iterator.Valid(); iterator.Next() {
+		var token Token
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &token)
+		if err != nil {
+			panic(err)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+func (k TokenKeeper) setToken(ctx sdk.Context, token Token) {
+	store := ctx.KVStore(k.key)
+	bz, err := k.cdc.MarshalJSON(token)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(GetTokenKey(token.Denom), bz)
+}
+
+// RegisterToken registers a new denom with the issuer of the token
+func (k TokenKeeper) RegisterToken(ctx sdk.Context, token Token) sdk.Error {
+	if err := token.ValidateBasic(k.codespace); err != nil {
+		return err
+	}
+	if _, found := k.GetToken(ctx, token.Denom); found {
+		return ErrTokenExists(k.codespace, token.Denom)
+	}
+	if k.supplyKeeper.GetSupply(ctx, token.Denom) != 0 {
+		return ErrInvalidToken(k.codespace, fmt.Sprintf("denom %s already has a supply", token.Denom))
+	}
+	k.setToken(ctx, token)
+	return nil
+}
+
+// RegisterNativeTokens registers the unknown denoms of the coins as native
+// tokens without issuer
+func (k TokenKeeper) RegisterNativeTokens(ctx sdk.Context, coins sdk.Coins) {
+	for _, coin := range coins {
+		if _, found := k.GetToken(ctx, coin.Denom); !found {
+			k.setToken(ctx, Token{Denom: coin.Denom, Name: coin.Denom})
+		}
+	}
+}
+
+// Issue mints the coins of the outputs, the banker must be the issuer of
+// every denom and the supplies stay within their max
+func (k TokenKeeper) Issue(ctx sdk.Context, banker sdk.Address, outputs []Output) sdk.Error {
+	var total sdk.Coins
+	for _, out := range outputs {
+		total = total.Plus(out.Coins)
+	}
+	for _, coin := range total {
+		token, err := k.issuedToken(ctx, banker, coin.Denom)
+		if err != nil {
+			return err
+		}
+		if coin.Amount > token.MaxSupply-k.supplyKeeper.GetSupply(ctx, coin.Denom) {
+			return ErrMaxSupplyExceeded(k.codespace, token.Denom, token.MaxSupply)
+		}
+	}
+	for _, out := range outputs {
+		_, err := k.supplyKeeper.Mint(ctx, out.Address, out.Coins)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Burn destroys coins of the owner, decreasing the supply of their tokens.
+// Only the tokens registered with MsgRegisterToken can be burned, the native
+// tokens, without max supply, keep their genesis supply.
+func (k TokenKeeper) Burn(ctx sdk.Context, owner sdk.Address, coins sdk.Coins) sdk.Error {
+	for _, coin := range coins {
+		token, found := k.GetToken(ctx, coin.Denom)
+		if !found {
+			return ErrUnknownToken(k.codespace, coin.Denom)
+		}
+		if token.MaxSupply == 0 {
+			return ErrInvalidToken(k.codespace, fmt.Sprintf("native token %s cannot be burned", coin.Denom))
+		}
+	}
+	_, err := k.supplyKeeper.Burn(ctx, owner, coins)
+	return err
+}
+
+// TransferIssuer gives the issuer rights of the token to the new issuer
+func (k TokenKeeper) TransferIssuer(ctx sdk.Context, issuer sdk.Address, denom string, newIssuer sdk.Address) sdk.Error {
+	token, err := k.issuedToken(ctx, issuer, denom)
+	if err != nil {
+		return err
+	}
+	token.Issuer = newIssuer
+	k.setToken(ctx, token)
+	return nil
+}
+
+// RenounceIssuer removes the issuer of the token, its supply can't grow anymore
+func (k TokenKeeper) RenounceIssuer(ctx sdk.Context, issuer sdk.Address, denom string) sdk.Error {
+	token, err := k.issuedToken(ctx, issuer, denom)
+	if err != nil {
+		return err
+	}
+	token.Issuer = nil
+	k.setToken(ctx, token)
+	return nil
+}
+
+// get the token of the denom, checking the issuer
+func (k TokenKeeper) issuedToken(ctx sdk.Context, issuer sdk.Address, denom string) (token Token, err sdk.Error) {
+	token, found := k.GetToken(ctx, denom)
+	if !found {
+		return token, ErrUnknownToken(k.codespace, denom)
+	}
+	if len(token.Issuer) == 0 || !bytes.Equal(token.Issuer, issuer) {
+		return token, ErrNotIssuer(k.codespace, denom)
+	}
+	return token, nil
+}
+
+//______________________________________________________________________________________________
+
+// InitTokenGenesis - store the genesis tokens
+func InitTokenGenesis(ctx sdk.Context, k TokenKeeper, data TokenGenesisState) {
+	for _, token := range data.Tokens {
+		k.setToken(ctx, token)
+	}
+}
+
+// WriteTokenGenesis - output the tokens
+func WriteTokenGenesis(ctx sdk.Context, k TokenKeeper) TokenGenesisState {
+	return TokenGenesisState{Tokens: k.GetTokens(ctx)}
+}
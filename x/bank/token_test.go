@@ -0,0 +1,122 @@
+package bank
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/tendermint/tmlibs/log"
+
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	wire "inschain-tendermint/wire"
+
+	"inschain-tendermint/x/auth"
+)
+
+func createTokenTestInput(t *testing.T) (sdk.Context, sdk.AccountMapper, SupplyKeeper, TokenKeeper) {
+	db := dbm.NewMemDB()
+	authKey := sdk.NewKVStoreKey("authkey")
+	supplyKey := sdk.NewKVStoreKey("supply")
+	tokenKey := sdk.NewKVStoreKey("token")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(authKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(supplyKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(tokenKey, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, nil, log.NewNopLogger())
+	accountMapper := auth.NewAccountMapper(cdc, authKey, &auth.BaseAccount{})
+	supplyKeeper := NewSupplyKeeper(cdc, supplyKey, NewKeeper(accountMapper), DefaultCodespace)
+	return ctx, accountMapper, supplyKeeper, NewTokenKeeper(cdc, tokenKey, supplyKeeper, DefaultCodespace)
+}
+
+func TestTokenIssue(t *testing.T) {
+	ctx, am, supplyKeeper, keeper := createTokenTestInput(t)
+	handler := NewTokenHandler(keeper)
+	issuer := sdk.Address([]byte("issuer"))
+	addr := sdk.Address([]byte("addr1"))
+
+	// the denoms of the genesis are native tokens without issuer
+	keeper.RegisterNativeTokens(ctx, sdk.Coins{{"steak", 100}})
+	keeper.RegisterNativeTokens(ctx, sdk.Coins{{"steak", 50}})
+	token, found := keeper.GetToken(ctx, "steak")
+	require.True(t, found)
+	assert.Empty(t, token.Issuer)
+	res := handler(ctx, NewMsgRegisterToken(NewToken("steak", "Steak", 0, 1000, issuer)))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidToken), res.Code)
+	res = handler(ctx, NewMsgIssue(issuer, []Output{NewOutput(addr, sdk.Coins{{"steak", 10}})}))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeNotIssuer), res.Code)
+	_, err := supplyKeeper.Mint(ctx, issuer, sdk.Coins{{"steak", 10}})
+	require.Nil(t, err)
+	res = handler(ctx, NewMsgBurn(issuer, sdk.Coins{{"steak", 10}}))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidToken), res.Code)
+	assert.Equal(t, int64(10), supplyKeeper.GetSupply(ctx, "steak"))
+
+	// register a token and mint it up to the max supply
+	res = handler(ctx, NewMsgRegisterToken(NewToken("bond", "Policy bond", 2, 100, issuer)))
+	require.True(t, res.IsOK(), "%v", res)
+	res = handler(ctx, NewMsgIssue(addr, []Output{NewOutput(addr, sdk.Coins{{"bond", 10}})}))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeNotIssuer), res.Code)
+	res = handler(ctx, NewMsgIssue(issuer, []Output{
+		NewOutput(addr, sdk.Coins{{"bond", 60}}),
+		NewOutput(issuer, sdk.Coins{{"bond", 40}}),
+	}))
+	require.True(t, res.IsOK(), "%v", res)
+	assert.True(t, supplyKeeper.GetCoins(ctx, addr).IsEqual(sdk.Coins{{"bond", 60}}))
+	res = handler(ctx, NewMsgIssue(issuer, []Output{NewOutput(addr, sdk.Coins{{"bond", 1}})}))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeMaxSupply), res.Code)
+
+	// burning makes room under the cap
+	res = handler(ctx, NewMsgBurn(addr, sdk.Coins{{"bond", 70}}))
+	assert.False(t, res.IsOK())
+	res = handler(ctx, NewMsgBurn(addr, sdk.Coins{{"bond", 20}}))
+	require.True(t, res.IsOK(), "%v", res)
+	assert.Equal(t, int64(80), supplyKeeper.GetSupply(ctx, "bond"))
+	assert.True(t, supplyKeeper.GetCoins(ctx, addr).IsEqual(sdk.Coins{{"bond", 40}}))
+
+	// the rights move to the new issuer, then are renounced
+	res = handler(ctx, NewMsgTransferIssuer(issuer, "bond", addr))
+	require.True(t, res.IsOK(), "%v", res)
+	res = handler(ctx, NewMsgIssue(issuer, []Output{NewOutput(addr, sdk.Coins{{"bond", 1}})}))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeNotIssuer), res.Code)
+	res = handler(ctx, NewMsgIssue(addr, []Output{NewOutput(addr, sdk.Coins{{"bond", 20}})}))
+	require.True(t, res.IsOK(), "%v", res)
+	res = handler(ctx, NewMsgRenounceIssuer(addr, "bond"))
+	require.True(t, res.IsOK(), "%v", res)
+	res = handler(ctx, NewMsgIssue(addr, []Output{NewOutput(addr, sdk.Coins{{"bond", 1}})}))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeNotIssuer), res.Code)
+	token, _ = keeper.GetToken(ctx, "bond")
+	assert.Equal(t, int64(100), supplyKeeper.GetSupply(ctx, "bond"))
+	assert.Empty(t, token.Issuer)
+	assert.Nil(t, SupplyInvariant(ctx, am, supplyKeeper))
+
+	// the supply can't overflow past the max
+	res = handler(ctx, NewMsgRegisterToken(NewToken("big", "Big", 0, math.MaxInt64, issuer)))
+	require.True(t, res.IsOK(), "%v", res)
+	res = handler(ctx, NewMsgIssue(issuer, []Output{NewOutput(issuer, sdk.Coins{{"big", math.MaxInt64}})}))
+	require.True(t, res.IsOK(), "%v", res)
+	res = handler(ctx, NewMsgIssue(issuer, []Output{NewOutput(issuer, sdk.Coins{{"big", 1}})}))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeMaxSupply), res.Code)
+	assert.Equal(t, int64(math.MaxInt64), supplyKeeper.GetSupply(ctx, "big"))
+
+	assert.Equal(t, 3, len(WriteTokenGenesis(ctx, keeper).Tokens))
+}
+
+func TestMsgRegisterTokenValidation(t *testing.T) {
+	issuer := sdk.Address([]byte("issuer"))
+	assert.Nil(t, NewMsgRegisterToken(NewToken("bond", "Policy bond", 2, 100, issuer)).ValidateBasic())
+	assert.NotNil(t, NewMsgRegisterToken(NewToken("bond", "Policy bond", 2, 100, nil)).ValidateBasic())
+	assert.NotNil(t, NewMsgRegisterToken(NewToken("1bond", "Policy bond", 2, 100, issuer)).ValidateBasic())
+	assert.NotNil(t, NewMsgRegisterToken(NewToken("bd", "Policy bond", 2, 100, issuer)).ValidateBasic())
+	assert.NotNil(t, NewMsgRegisterToken(NewToken("bond", "", 2, 100, issuer)).ValidateBasic())
+	assert.NotNil(t, NewMsgRegisterToken(NewToken("bond", "Policy bond", 19, 100, issuer)).ValidateBasic())
+	assert.NotNil(t, NewMsgRegisterToken(NewToken("bond", "Policy bond", 2, 0, issuer)).ValidateBasic())
+}
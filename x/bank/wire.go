@@ -1,11 +1,18 @@
 package bank
 
 import (
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/wire"
 )
 
 // Register concrete types on wire codec
 func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(MsgSend{}, "cosmos-sdk/Send", nil)
 	cdc.RegisterConcrete(MsgIssue{}, "cosmos-sdk/Issue", nil)
+	cdc.RegisterConcrete(MsgRegisterToken{}, "cosmos-sdk/RegisterToken", nil)
+	cdc.RegisterConcrete(MsgBurn{}, "cosmos-sdk/Burn", nil)
+	cdc.RegisterConcrete(MsgTransferIssuer{}, "cosmos-sdk/TransferIssuer", nil)
+	cdc.RegisterConcrete(MsgRenounceIssuer{}, "cosmos-sdk/RenounceIssuer", nil)
+	cdc.RegisterConcrete(MsgCreateHTLC{}, "cosmos-sdk/CreateHTLC", nil)
+	cdc.RegisterConcrete(MsgClaimHTLC{}, "cosmos-sdk/ClaimHTLC", nil)
+	cdc.RegisterConcrete(MsgRefundHTLC{}, "cosmos-sdk/RefundHTLC", nil)
 }
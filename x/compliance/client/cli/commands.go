@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+
+	"inschain-tendermint/x/compliance"
+)
+
+const (
+	flagAddress   = "address"
+	flagAddresses = "addresses"
+	flagDenom     = "denom"
+)
+
+// AddCommands adds the compliance subcommands
+func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
+	cmd.AddCommand(
+		client.PostCommands(
+			FreezeCmd(cdc),
+			UnfreezeCmd(cdc),
+			SetAllowListCmd(cdc),
+			RemoveAllowListCmd(cdc),
+		)...)
+	cmd.AddCommand(
+		client.GetCommands(
+			GetRestrictionsCmd("compliance", cdc),
+		)...)
+}
+
+// FreezeCmd freezes the coins of an address, the key being the admin
+func FreezeCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "freeze",
+		Short: "Freeze the coins of an address",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			admin, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			addr, err := sdk.GetAddress(viper.GetString(flagAddress))
+			if err != nil {
+				return err
+			}
+			return ctx.SendMsg(compliance.NewMsgFreeze(admin, addr), cdc)
+		},
+	}
+	cmd.Flags().String(flagAddress, "", "Address to freeze")
+	return cmd
+}
+
+// UnfreezeCmd unfreezes the coins of an address, the key being the admin
+func UnfreezeCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unfreeze",
+		Short: "Unfreeze the coins of an address",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			admin, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			addr, err := sdk.GetAddress(viper.GetString(flagAddress))
+			if err != nil {
+				return err
+			}
+			return ctx.SendMsg(compliance.NewMsgUnfreeze(admin, addr), cdc)
+		},
+	}
+	cmd.Flags().String(flagAddress, "", "Address to unfreeze")
+	return cmd
+}
+
+// SetAllowListCmd sets the addresses allowed to transfer a denom, the key
+// being the admin
+func SetAllowListCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-allow-list",
+		Short: "Allow only the addresses to send and receive a denom",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			admin, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			var addrs []sdk.Address
+			for _, s := range strings.Split(viper.GetString(flagAddresses), ",") {
+				if s = strings.TrimSpace(s); s == "" {
+					continue
+				}
+				addr, err := sdk.GetAddress(s)
+				if err != nil {
+					return err
+				}
+				addrs = append(addrs, addr)
+			}
+			return ctx.SendMsg(compliance.NewMsgSetAllowList(admin, viper.GetString(flagDenom), addrs), cdc)
+		},
+	}
+	cmd.Flags().String(flagDenom, "", "Denom of the allow-list")
+	cmd.Flags().String(flagAddresses, "", "Comma separated addresses allowed to transfer the denom")
+	return cmd
+}
+
+// RemoveAllowListCmd lets anyone transfer a denom again, the key being the admin
+func RemoveAllowListCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-allow-list",
+		Short: "Remove the allow-list of a denom",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			admin, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			return ctx.SendMsg(compliance.NewMsgRemoveAllowList(admin, viper.GetString(flagDenom)), cdc)
+		},
+	}
+	cmd.Flags().String(flagDenom, "", "Denom of the allow-list")
+	return cmd
+}
+
+// GetRestrictionsCmd queries the admin, the frozen addresses and the allow-lists
+func GetRestrictionsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "restrictions",
+		Short: "Query the compliance admin, the frozen addresses and the allow-lists",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper()
+			bz, err := ctx.QueryCustom(queryRoute, compliance.QueryRestrictions, nil)
+			if err != nil {
+				return err
+			}
+
+			var restrictions compliance.Restrictions
+			err = cdc.UnmarshalJSON(bz, &restrictions)
+			if err != nil {
+				return err
+			}
+			output, err := wire.MarshalJSONIndent(cdc, restrictions)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+}
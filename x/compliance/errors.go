@@ -0,0 +1,54 @@
+package compliance
+
+import (
+	sdk "inschain-tendermint/types"
+)
+
+// Compliance errors reserve 1000 ~ 1099.
+const (
+	DefaultCodespace sdk.CodespaceType = 11
+
+	CodeTransferBlocked     sdk.CodeType = 1001
+	CodeUnauthorized        sdk.CodeType = 1002
+	CodeInvalidRestrictions sdk.CodeType = 1003
+	CodeUnknownRequest      sdk.CodeType = sdk.CodeUnknownRequest
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeTransferBlocked:
+		return "Transfer blocked by the compliance restrictions"
+	case CodeUnauthorized:
+		return "Not the compliance admin"
+	case CodeInvalidRestrictions:
+		return "Invalid compliance restrictions"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// nolint
+func ErrTransferBlocked(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeTransferBlocked, msg)
+}
+func ErrUnauthorized(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeUnauthorized, msg)
+}
+func ErrInvalidRestrictions(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidRestrictions, msg)
+}
+
+// -------------------------
+// Helpers
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	msg = msgOrDefaultMsg(msg, code)
+	return sdk.NewError(codespace, code, msg)
+}
+
+func msgOrDefaultMsg(msg string, code sdk.CodeType) string {
+	if msg != "" {
+		return msg
+	}
+	return codeToDefaultMsg(code)
+}
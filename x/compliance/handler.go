@@ -0,0 +1,99 @@
+package compliance
+
+import (
+	"reflect"
+
+	sdk "inschain-tendermint/types"
+)
+
+// NewHandler returns a handler for "compliance" type messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgFreeze:
+			return handleMsgFreeze(ctx, k, msg)
+		case MsgUnfreeze:
+			return handleMsgUnfreeze(ctx, k, msg)
+		case MsgSetAllowList:
+			return handleMsgSetAllowList(ctx, k, msg)
+		case MsgRemoveAllowList:
+			return handleMsgRemoveAllowList(ctx, k, msg)
+		default:
+			errMsg := "Unrecognized compliance Msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgFreeze(ctx sdk.Context, k Keeper, msg MsgFreeze) sdk.Result {
+	err := k.Freeze(ctx, msg.Admin, msg.Address)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: sdk.NewTags(
+			TagAction, ActionFreeze,
+			TagAdmin, []byte(msg.Admin.String()),
+			TagAddress, []byte(msg.Address.String()),
+		),
+	}
+}
+
+func handleMsgUnfreeze(ctx sdk.Context, k Keeper, msg MsgUnfreeze) sdk.Result {
+	err := k.Unfreeze(ctx, msg.Admin, msg.Address)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: sdk.NewTags(
+			TagAction, ActionUnfreeze,
+			TagAdmin, []byte(msg.Admin.String()),
+			TagAddress, []byte(msg.Address.String()),
+		),
+	}
+}
+
+func handleMsgSetAllowList(ctx sdk.Context, k Keeper, msg MsgSetAllowList) sdk.Result {
+	err := k.SetAllowList(ctx, msg.Admin, msg.AllowList)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: sdk.NewTags(
+			TagAction, ActionSetAllowList,
+			TagAdmin, []byte(msg.Admin.String()),
+			TagDenom, []byte(msg.AllowList.Denom),
+		),
+	}
+}
+
+func handleMsgRemoveAllowList(ctx sdk.Context, k Keeper, msg MsgRemoveAllowList) sdk.Result {
+	err := k.RemoveAllowList(ctx, msg.Admin, msg.Denom)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: sdk.NewTags(
+			TagAction, ActionRemoveAllowList,
+			TagAdmin, []byte(msg.Admin.String()),
+			TagDenom, []byte(msg.Denom),
+		),
+	}
+}
+
+//_____________________________________________________________________
+
+// InitGenesis - store the genesis restrictions
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
+	if err := data.Restrictions.ValidateBasic(k.codespace); err != nil {
+		panic(err)
+	}
+	k.setRestrictions(ctx, data.Restrictions)
+}
+
+// WriteGenesis - output the restrictions
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	return GenesisState{
+		Restrictions: k.GetRestrictions(ctx),
+	}
+}
@@ -0,0 +1,325 @@
+package compliance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/bank"
+)
+
+var (
+	// Keys for store prefixes
+	AdminKey           = []byte{0x00} // key for the compliance admin
+	FrozenKeyPrefix    = []byte{0x01} // prefix for each key to a frozen address
+	AllowListKeyPrefix = []byte{0x02} // prefix for each key to the allow-list of a denom
+)
+
+// get the key marking the address as frozen
+func GetFrozenKey(addr sdk.Address) []byte {
+	return append(FrozenKeyPrefix, addr...)
+}
+
+// get the key for the allow-list of the denom
+func GetAllowListKey(denom string) []byte {
+	return append(AllowListKeyPrefix, []byte(denom)...)
+}
+
+// Keeper manages the restrictions on the transfers, enforced by the bank,
+// supply and mutual keepers through CheckTransfer
+type Keeper struct {
+	key       sdk.StoreKey
+	cdc       *wire.Codec
+	codespace sdk.CodespaceType
+}
+
+var _ bank.TransferRestrictions = Keeper{}
+
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		key:       key,
+		cdc:       cdc,
+		codespace: codespace,
+	}
+}
+
+// GetAdmin returns the compliance admin, empty if there is none
+func (k Keeper) GetAdmin(ctx sdk.Context) (admin sdk.Address) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(AdminKey)
+	if bz == nil {
+		return nil
+	}
+	k.mustUnmarshal(bz, &admin)
+	return admin
+}
+
+func (k Keeper) setAdmin(ctx sdk.Context, admin sdk.Address) {
+	store := ctx.KVStore(k.key)
+	if len(admin) == 0 {
+		store.Delete(AdminKey)
+		return
+	}
+	store.Set(AdminKey, k.mustMarshal(admin))
+}
+
+// IsFrozen returns whether the coins of the address are frozen
+func (k Keeper) IsFrozen(ctx sdk.Context, addr sdk.Address) bool {
+	store := ctx.KVStore(k.key)
+	return store.Has(GetFrozenKey(addr))
+}
+
+func (k Keeper) setFrozen(ctx sdk.Context, addr sdk.Address, frozen bool) {
+	store := ctx.KVStore(k.key)
+	if !frozen {
+		store.Delete(GetFrozenKey(addr))
+		return
+	}
+	store.Set(GetFrozenKey(addr), k.mustMarshal(addr))
+}
+
+// GetAllowList returns the allow-list of the denom, not found if anyone may
+// hold the denom
+func (k Keeper) GetAllowList(ctx sdk.Context, denom string) (list AllowList, found bool) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetAllowListKey(denom))
+	if bz == nil {
+		return list, false
+	}
+	k.mustUnmarshal(bz, &list)
+	return list, true
+}
+
+func (k Keeper) setAllowList(ctx sdk.Context, list AllowList) {
+	store := ctx.KVStore(k.key)
+	store.Set(GetAllowListKey(list.Denom), k.mustMarshal(list))
+}
+
+func (k Keeper) deleteAllowList(ctx sdk.Context, denom string) {
+	store := ctx.KVStore(k.key)
+	store.Delete(GetAllowListKey(denom))
+}
+
+// GetRestrictions returns the admin, the frozen addresses and the allow-lists
+func (k Keeper) GetRestrictions(ctx sdk.Context) Restrictions {
+	store := ctx.KVStore(k.key)
+	restrictions := Restrictions{
+		Admin:           k.GetAdmin(ctx),
+		FrozenAddresses: []sdk.Address{},
+		AllowLists:      []AllowList{},
+	}
+
+	iterator := store.SubspaceIterator(FrozenKeyPrefix)
+	for ; iterator.Valid(); iterator.Next() {
+		var addr sdk.Address
+		k.mustUnmarshal(iterator.Value(), &addr)
+		restrictions.FrozenAddresses = append(restrictions.FrozenAddresses, addr)
+	}
+	iterator.Close()
+
+	iterator = store.SubspaceIterator(AllowListKeyPrefix)
+	for ; iterator.Valid(); iterator.Next() {
+		var list AllowList
+		k.mustUnmarshal(iterator.Value(), &list)
+		restrictions.AllowLists = append(restrictions.AllowLists, list)
+	}
+	iterator.Close()
+
+	return restrictions
+}
+
+// replace all the restrictions
+func (k Keeper) setRestrictions(ctx sdk.Context, restrictions Restrictions) {
+	store := ctx.KVStore(k.key)
+	for _, prefix := range [][]byte{FrozenKeyPrefix, AllowListKeyPrefix} {
+		iterator := store.SubspaceIterator(prefix)
+		var keys [][]byte
+		for ; iterator.Valid(); iterator.Next() {
+			keys = append(keys, iterator.Key())
+		}
+		iterator.Close()
+		for _, key := range keys {
+			store.Delete(key)
+		}
+	}
+
+	k.setAdmin(ctx, restrictions.Admin)
+	for _, addr := range restrictions.FrozenAddresses {
+		k.setFrozen(ctx, addr, true)
+	}
+	for _, list := range restrictions.AllowLists {
+		k.setAllowList(ctx, list)
+	}
+}
+
+// SetParam replaces a whole field of the restrictions from its JSON name and
+// value, eg. "frozen_addresses", so that governance can change them
+func (k Keeper) SetParam(ctx sdk.Context, key, value string) sdk.Error {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(k.mustMarshal(k.GetRestrictions(ctx)), &fields)
+	if err != nil {
+		panic(err)
+	}
+	if _, ok := fields[key]; !ok {
+		return ErrInvalidRestrictions(k.codespace, fmt.Sprintf("unknown compliance param %s", key))
+	}
+	fields[key] = json.RawMessage(value)
+	bz, err := json.Marshal(fields)
+	if err != nil {
+		return ErrInvalidRestrictions(k.codespace, fmt.Sprintf("invalid value for compliance param %s", key))
+	}
+	var restrictions Restrictions
+	err = k.cdc.UnmarshalJSON(bz, &restrictions)
+	if err != nil {
+		return ErrInvalidRestrictions(k.codespace, fmt.Sprintf("invalid value for compliance param %s: %v", key, err))
+	}
+	if err := restrictions.ValidateBasic(k.codespace); err != nil {
+		return err
+	}
+	k.setRestrictions(ctx, restrictions)
+	return nil
+}
+
+//_______________________________________________________________________
+
+// only the admin changes the restrictions through msgs
+func (k Keeper) authorize(ctx sdk.Context, sender sdk.Address) sdk.Error {
+	admin := k.GetAdmin(ctx)
+	if len(admin) == 0 {
+		return ErrUnauthorized(k.codespace, "there is no compliance admin, the restrictions are changed through governance")
+	}
+	if !bytes.Equal(admin, sender) {
+		return ErrUnauthorized(k.codespace, fmt.Sprintf("%s is not the compliance admin", sender))
+	}
+	return nil
+}
+
+// Freeze freezes the coins of the address, on behalf of the admin
+func (k Keeper) Freeze(ctx sdk.Context, admin sdk.Address, addr sdk.Address) sdk.Error {
+	if err := k.authorize(ctx, admin); err != nil {
+		return err
+	}
+	k.setFrozen(ctx, addr, true)
+	return nil
+}
+
+// Unfreeze unfreezes the coins of the address, on behalf of the admin
+func (k Keeper) Unfreeze(ctx sdk.Context, admin sdk.Address, addr sdk.Address) sdk.Error {
+	if err := k.authorize(ctx, admin); err != nil {
+		return err
+	}
+	k.setFrozen(ctx, addr, false)
+	return nil
+}
+
+// SetAllowList sets or replaces the allow-list of its denom, on behalf of
+// the admin
+func (k Keeper) SetAllowList(ctx sdk.Context, admin sdk.Address, list AllowList) sdk.Error {
+	if err := k.authorize(ctx, admin); err != nil {
+		return err
+	}
+	if err := list.ValidateBasic(k.codespace); err != nil {
+		return err
+	}
+	k.setAllowList(ctx, list)
+	return nil
+}
+
+// RemoveAllowList lets anyone hold the denom again, on behalf of the admin
+func (k Keeper) RemoveAllowList(ctx sdk.Context, admin sdk.Address, denom string) sdk.Error {
+	if err := k.authorize(ctx, admin); err != nil {
+		return err
+	}
+	if _, found := k.GetAllowList(ctx, denom); !found {
+		return ErrInvalidRestrictions(k.codespace, fmt.Sprintf("no allow-list for %s", denom))
+	}
+	k.deleteAllowList(ctx, denom)
+	return nil
+}
+
+//_______________________________________________________________________
+
+// CheckTransfer blocks the transfer if any of its addresses is frozen, or is
+// not allowed to send or receive one of its denoms. Implements
+// bank.TransferRestrictions.
+func (k Keeper) CheckTransfer(ctx sdk.Context, inputs []bank.Input, outputs []bank.Output) sdk.Error {
+	for _, in := range inputs {
+		if err := k.checkAddress(ctx, in.Address, in.Coins); err != nil {
+			return err
+		}
+	}
+	for _, out := range outputs {
+		if err := k.checkAddress(ctx, out.Address, out.Coins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k Keeper) checkAddress(ctx sdk.Context, addr sdk.Address, coins sdk.Coins) sdk.Error {
+	if k.IsFrozen(ctx, addr) {
+		return k.blocked(ctx, addr, "", fmt.Sprintf("the coins of %s are frozen", addr))
+	}
+	for _, coin := range coins {
+		list, found := k.GetAllowList(ctx, coin.Denom)
+		if found && !list.Allows(addr) {
+			return k.blocked(ctx, addr, coin.Denom, fmt.Sprintf("%s is not allowed to transfer %s", addr, coin.Denom))
+		}
+	}
+	return nil
+}
+
+// log the blocked attempt, and tag the error so that it shows in the result
+// of the failed tx
+func (k Keeper) blocked(ctx sdk.Context, addr sdk.Address, denom string, msg string) sdk.Error {
+	logger := ctx.Logger().With("module", "x/compliance")
+	logger.Info("Transfer blocked", "address", addr, "denom", denom)
+
+	tags := sdk.NewTags(
+		TagAction, ActionTransferBlocked,
+		TagAddress, []byte(addr.String()),
+	)
+	if denom != "" {
+		tags = tags.AppendTag(TagDenom, []byte(denom))
+	}
+	return blockedError{ErrTransferBlocked(k.codespace, msg), tags}
+}
+
+// sdkError lets blockedError embed an sdk.Error, whose Error method would
+// otherwise be shadowed by the embedded field of the same name
+type sdkError interface {
+	sdk.Error
+}
+
+// blockedError is the error of a blocked transfer, with the tags of the
+// attempt in its result
+type blockedError struct {
+	sdkError
+	tags sdk.Tags
+}
+
+// Implements sdk.Error.
+func (err blockedError) Result() sdk.Result {
+	res := err.sdkError.Result()
+	res.Tags = res.Tags.AppendTags(err.tags)
+	return res
+}
+
+//_______________________________________________________________________
+
+func (k Keeper) mustMarshal(o interface{}) []byte {
+	bz, err := k.cdc.MarshalJSON(o)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+func (k Keeper) mustUnmarshal(bz []byte, ptr interface{}) {
+	err := k.cdc.UnmarshalJSON(bz, ptr)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,214 @@
+package compliance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/tendermint/tmlibs/log"
+
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+)
+
+var (
+	admin  = sdk.Address([]byte("admin"))
+	member = sdk.Address([]byte("member"))
+	other  = sdk.Address([]byte("other"))
+)
+
+func createTestInput(t *testing.T) (sdk.Context, bank.Keeper, Keeper) {
+	db := dbm.NewMemDB()
+	authKey := sdk.NewKVStoreKey("acc")
+	key := sdk.NewKVStoreKey("compliance")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(authKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	err := ms.LoadLatestVersion()
+	require.Nil(t, err)
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "foochainid", Height: 10}, false, nil, log.NewNopLogger())
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+	RegisterWire(cdc)
+
+	keeper := NewKeeper(cdc, key, DefaultCodespace)
+	ck := bank.NewKeeper(auth.NewAccountMapper(cdc, authKey, &auth.BaseAccount{})).WithTransferRestrictions(keeper)
+	InitGenesis(ctx, keeper, GenesisState{Restrictions{Admin: admin}})
+	return ctx, ck, keeper
+}
+
+func TestFreeze(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	handler := NewHandler(keeper)
+	_, err := ck.AddCoins(ctx, member, sdk.Coins{{"foo", 10}})
+	require.Nil(t, err)
+
+	// only the admin freezes
+	res := handler(ctx, NewMsgFreeze(member, member))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeUnauthorized), res.Code)
+	res = handler(ctx, NewMsgFreeze(admin, member))
+	require.True(t, res.IsOK(), "%v", res)
+	assert.True(t, keeper.IsFrozen(ctx, member))
+
+	// the frozen coins neither leave nor come in, the attempt is tagged
+	err = ck.SendCoins(ctx, member, other, sdk.Coins{{"foo", 5}})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTransferBlocked, err.Code())
+	res = err.Result()
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeTransferBlocked), res.Code)
+	assert.Equal(t, sdk.NewTags(TagAction, ActionTransferBlocked, TagAddress, []byte(member.String())), res.Tags)
+	_, err = ck.AddCoins(ctx, other, sdk.Coins{{"foo", 5}})
+	require.Nil(t, err)
+	assert.NotNil(t, ck.SendCoins(ctx, other, member, sdk.Coins{{"foo", 5}}))
+	assert.True(t, ck.GetCoins(ctx, member).IsEqual(sdk.Coins{{"foo", 10}}))
+
+	res = handler(ctx, NewMsgUnfreeze(admin, member))
+	require.True(t, res.IsOK(), "%v", res)
+	assert.Nil(t, ck.SendCoins(ctx, member, other, sdk.Coins{{"foo", 5}}))
+}
+
+func TestAllowList(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	handler := NewHandler(keeper)
+	_, err := ck.AddCoins(ctx, member, sdk.Coins{{"foo", 10}, {"policy", 10}})
+	require.Nil(t, err)
+
+	res := handler(ctx, NewMsgSetAllowList(admin, "policy", []sdk.Address{member, admin}))
+	require.True(t, res.IsOK(), "%v", res)
+
+	// the policy tokens move only between the allowed addresses
+	assert.Nil(t, ck.SendCoins(ctx, member, admin, sdk.Coins{{"policy", 2}}))
+	err = ck.SendCoins(ctx, member, other, sdk.Coins{{"policy", 2}})
+	require.NotNil(t, err)
+	assert.Equal(t, sdk.NewTags(TagAction, ActionTransferBlocked, TagAddress, []byte(other.String()),
+		TagDenom, []byte("policy")), err.Result().Tags)
+	inputs := []bank.Input{bank.NewInput(member, sdk.Coins{{"foo", 2}, {"policy", 2}})}
+	outputs := []bank.Output{bank.NewOutput(admin, sdk.Coins{{"policy", 2}}), bank.NewOutput(other, sdk.Coins{{"foo", 2}})}
+	assert.Nil(t, ck.InputOutputCoins(ctx, inputs, outputs))
+	outputs = []bank.Output{bank.NewOutput(other, sdk.Coins{{"foo", 2}, {"policy", 2}})}
+	assert.NotNil(t, ck.InputOutputCoins(ctx, inputs, outputs))
+	assert.True(t, ck.GetCoins(ctx, member).IsEqual(sdk.Coins{{"foo", 8}, {"policy", 6}}))
+
+	// anyone transfers the denom once the allow-list is removed
+	res = handler(ctx, NewMsgRemoveAllowList(admin, "policy"))
+	require.True(t, res.IsOK(), "%v", res)
+	res = handler(ctx, NewMsgRemoveAllowList(admin, "policy"))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidRestrictions), res.Code)
+	assert.Nil(t, ck.SendCoins(ctx, member, other, sdk.Coins{{"policy", 2}}))
+}
+
+func TestGovernanceRestrictions(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	_, err := ck.AddCoins(ctx, member, sdk.Coins{{"foo", 10}})
+	require.Nil(t, err)
+
+	require.Nil(t, keeper.SetParam(ctx, "frozen_addresses", `["`+member.String()+`"]`))
+	assert.True(t, keeper.IsFrozen(ctx, member))
+	assert.NotNil(t, ck.SendCoins(ctx, member, other, sdk.Coins{{"foo", 5}}))
+	assert.NotNil(t, keeper.SetParam(ctx, "allow_lists", `[{"denom":"","addresses":[]}]`))
+	assert.NotNil(t, keeper.SetParam(ctx, "unknown", "1"))
+
+	// without an admin, the restrictions are only changed through governance
+	require.Nil(t, keeper.SetParam(ctx, "admin", `""`))
+	assert.NotNil(t, keeper.Unfreeze(ctx, admin, member))
+	require.Nil(t, keeper.SetParam(ctx, "frozen_addresses", `[]`))
+	assert.False(t, keeper.IsFrozen(ctx, member))
+	assert.Nil(t, ck.SendCoins(ctx, member, other, sdk.Coins{{"foo", 5}}))
+}
+
+func TestGenesis(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	require.Nil(t, keeper.Freeze(ctx, admin, member))
+	require.Nil(t, keeper.SetAllowList(ctx, admin, AllowList{"policy", []sdk.Address{member}}))
+	genesis := WriteGenesis(ctx, keeper)
+	assert.Equal(t, Restrictions{
+		Admin:           admin,
+		FrozenAddresses: []sdk.Address{member},
+		AllowLists:      []AllowList{{"policy", []sdk.Address{member}}},
+	}, genesis.Restrictions)
+
+	ctx2, _, keeper2 := createTestInput(t)
+	InitGenesis(ctx2, keeper2, genesis)
+	assert.Equal(t, genesis, WriteGenesis(ctx2, keeper2))
+}
+
+func createSupplyTestInput(t *testing.T) (sdk.Context, bank.SupplyKeeper, bank.HTLCKeeper, bank.TokenKeeper, Keeper) {
+	db := dbm.NewMemDB()
+	authKey := sdk.NewKVStoreKey("acc")
+	supplyKey := sdk.NewKVStoreKey("supply")
+	htlcKey := sdk.NewKVStoreKey("htlc")
+	tokenKey := sdk.NewKVStoreKey("token")
+	key := sdk.NewKVStoreKey("compliance")
+	ms := store.NewCommitMultiStore(db)
+	for _, k := range []sdk.StoreKey{authKey, supplyKey, htlcKey, tokenKey, key} {
+		ms.MountStoreWithDB(k, sdk.StoreTypeIAVL, db)
+	}
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "foochainid", Height: 10}, false, nil, log.NewNopLogger())
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+	RegisterWire(cdc)
+
+	keeper := NewKeeper(cdc, key, DefaultCodespace)
+	ck := bank.NewKeeper(auth.NewAccountMapper(cdc, authKey, &auth.BaseAccount{})).WithTransferRestrictions(keeper)
+	sk := bank.NewSupplyKeeper(cdc, supplyKey, ck, bank.DefaultCodespace).WithTransferRestrictions(keeper)
+	htlcKeeper := bank.NewHTLCKeeper(cdc, htlcKey, sk, bank.DefaultCodespace)
+	tokenKeeper := bank.NewTokenKeeper(cdc, tokenKey, sk, bank.DefaultCodespace)
+	InitGenesis(ctx, keeper, GenesisState{Restrictions{Admin: admin}})
+	return ctx, sk, htlcKeeper, tokenKeeper, keeper
+}
+
+func TestFreezeHTLC(t *testing.T) {
+	ctx, sk, htlcKeeper, _, keeper := createSupplyTestInput(t)
+	preimage := []byte("secret")
+	hashLock := bank.HashPreimage(preimage)
+	for _, addr := range []sdk.Address{member, other} {
+		_, err := sk.Mint(ctx, addr, sdk.Coins{{"foo", 10}})
+		require.Nil(t, err)
+	}
+
+	// escrows locked before the member is frozen
+	_, err := htlcKeeper.CreateHTLC(ctx, member, other, sdk.Coins{{"foo", 5}}, hashLock, 20)
+	require.Nil(t, err)
+	_, err = htlcKeeper.CreateHTLC(ctx, other, member, sdk.Coins{{"foo", 5}}, hashLock, 20)
+	require.Nil(t, err)
+	require.Nil(t, keeper.Freeze(ctx, admin, member))
+
+	// the frozen coins can't be escrowed, nor the escrowed coins claimed
+	_, err = htlcKeeper.CreateHTLC(ctx, member, other, sdk.Coins{{"foo", 5}}, hashLock, 20)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTransferBlocked, err.Code())
+	_, err = htlcKeeper.ClaimHTLC(ctx, member, 2, preimage)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTransferBlocked, err.Code())
+	assert.True(t, sk.GetCoins(ctx, member).IsEqual(sdk.Coins{{"foo", 5}}))
+
+	// the sender still gets its own coins back once the escrow expired
+	ctx = ctx.WithBlockHeight(20)
+	bank.HTLCEndBlocker(ctx, htlcKeeper)
+	_, err = htlcKeeper.RefundHTLC(ctx, member, 1)
+	require.Nil(t, err)
+	assert.True(t, sk.GetCoins(ctx, member).IsEqual(sdk.Coins{{"foo", 10}}))
+}
+
+func TestAllowListIssue(t *testing.T) {
+	ctx, sk, _, tokenKeeper, keeper := createSupplyTestInput(t)
+	require.Nil(t, tokenKeeper.RegisterToken(ctx, bank.NewToken("policy", "Policy", 0, 100, admin)))
+	require.Nil(t, keeper.SetAllowList(ctx, admin, AllowList{"policy", []sdk.Address{admin, member}}))
+
+	// the token is only issued to the allowed addresses
+	err := tokenKeeper.Issue(ctx, admin, []bank.Output{bank.NewOutput(other, sdk.Coins{{"policy", 10}})})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTransferBlocked, err.Code())
+	assert.Equal(t, int64(0), sk.GetSupply(ctx, "policy"))
+	assert.Nil(t, tokenKeeper.Issue(ctx, admin, []bank.Output{bank.NewOutput(member, sdk.Coins{{"policy", 10}})}))
+	assert.Equal(t, int64(10), sk.GetSupply(ctx, "policy"))
+}
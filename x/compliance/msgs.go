@@ -0,0 +1,188 @@
+package compliance
+
+import (
+	"encoding/json"
+
+	sdk "inschain-tendermint/types"
+)
+
+// name to identify the compliance msgs
+const MsgType = "compliance"
+
+//----------------------------------------
+// MsgFreeze
+
+// MsgFreeze - the admin freezes the coins of the address
+type MsgFreeze struct {
+	Admin   sdk.Address `json:"admin"`
+	Address sdk.Address `json:"address"`
+}
+
+var _ sdk.Msg = MsgFreeze{}
+
+// NewMsgFreeze - construct the msg freezing the coins of the address
+func NewMsgFreeze(admin, addr sdk.Address) MsgFreeze {
+	return MsgFreeze{Admin: admin, Address: addr}
+}
+
+// Implements Msg.
+func (msg MsgFreeze) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgFreeze) ValidateBasic() sdk.Error {
+	return validateAddresses(msg.Admin, msg.Address)
+}
+
+// Implements Msg.
+func (msg MsgFreeze) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgFreeze) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Admin}
+}
+
+//----------------------------------------
+// MsgUnfreeze
+
+// MsgUnfreeze - the admin unfreezes the coins of the address
+type MsgUnfreeze struct {
+	Admin   sdk.Address `json:"admin"`
+	Address sdk.Address `json:"address"`
+}
+
+var _ sdk.Msg = MsgUnfreeze{}
+
+// NewMsgUnfreeze - construct the msg unfreezing the coins of the address
+func NewMsgUnfreeze(admin, addr sdk.Address) MsgUnfreeze {
+	return MsgUnfreeze{Admin: admin, Address: addr}
+}
+
+// Implements Msg.
+func (msg MsgUnfreeze) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgUnfreeze) ValidateBasic() sdk.Error {
+	return validateAddresses(msg.Admin, msg.Address)
+}
+
+// Implements Msg.
+func (msg MsgUnfreeze) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgUnfreeze) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Admin}
+}
+
+//----------------------------------------
+// MsgSetAllowList
+
+// MsgSetAllowList - the admin sets or replaces the allow-list of a denom,
+// only the addresses on it may then send and receive the denom
+type MsgSetAllowList struct {
+	Admin     sdk.Address `json:"admin"`
+	AllowList AllowList   `json:"allow_list"`
+}
+
+var _ sdk.Msg = MsgSetAllowList{}
+
+// NewMsgSetAllowList - construct the msg setting the allow-list of the denom
+func NewMsgSetAllowList(admin sdk.Address, denom string, addrs []sdk.Address) MsgSetAllowList {
+	return MsgSetAllowList{
+		Admin:     admin,
+		AllowList: AllowList{Denom: denom, Addresses: addrs},
+	}
+}
+
+// Implements Msg.
+func (msg MsgSetAllowList) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgSetAllowList) ValidateBasic() sdk.Error {
+	if len(msg.Admin) == 0 {
+		return sdk.ErrInvalidAddress(msg.Admin.String())
+	}
+	return msg.AllowList.ValidateBasic(DefaultCodespace)
+}
+
+// Implements Msg.
+func (msg MsgSetAllowList) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgSetAllowList) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Admin}
+}
+
+//----------------------------------------
+// MsgRemoveAllowList
+
+// MsgRemoveAllowList - the admin removes the allow-list of a denom, anyone
+// may then send and receive the denom
+type MsgRemoveAllowList struct {
+	Admin sdk.Address `json:"admin"`
+	Denom string      `json:"denom"`
+}
+
+var _ sdk.Msg = MsgRemoveAllowList{}
+
+// NewMsgRemoveAllowList - construct the msg removing the allow-list of the denom
+func NewMsgRemoveAllowList(admin sdk.Address, denom string) MsgRemoveAllowList {
+	return MsgRemoveAllowList{Admin: admin, Denom: denom}
+}
+
+// Implements Msg.
+func (msg MsgRemoveAllowList) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgRemoveAllowList) ValidateBasic() sdk.Error {
+	if len(msg.Admin) == 0 {
+		return sdk.ErrInvalidAddress(msg.Admin.String())
+	}
+	if msg.Denom == "" {
+		return ErrInvalidRestrictions(DefaultCodespace, "the denom must not be empty")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgRemoveAllowList) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgRemoveAllowList) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Admin}
+}
+
+//----------------------------------------
+
+func validateAddresses(admin, addr sdk.Address) sdk.Error {
+	if len(admin) == 0 {
+		return sdk.ErrInvalidAddress(admin.String())
+	}
+	if len(addr) == 0 {
+		return sdk.ErrInvalidAddress(addr.String())
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package compliance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sdk "inschain-tendermint/types"
+)
+
+func TestMsgValidation(t *testing.T) {
+	var emptyAddr sdk.Address
+
+	cases := []struct {
+		valid bool
+		msg   sdk.Msg
+	}{
+		{true, NewMsgFreeze(admin, member)},
+		{false, NewMsgFreeze(emptyAddr, member)},
+		{false, NewMsgFreeze(admin, emptyAddr)},
+		{true, NewMsgUnfreeze(admin, member)},
+		{false, NewMsgUnfreeze(admin, emptyAddr)},
+		{true, NewMsgSetAllowList(admin, "policy", []sdk.Address{member})},
+		{true, NewMsgSetAllowList(admin, "policy", nil)},
+		{false, NewMsgSetAllowList(emptyAddr, "policy", []sdk.Address{member})},
+		{false, NewMsgSetAllowList(admin, "", []sdk.Address{member})},
+		{false, NewMsgSetAllowList(admin, "policy", []sdk.Address{emptyAddr})},
+		{true, NewMsgRemoveAllowList(admin, "policy")},
+		{false, NewMsgRemoveAllowList(emptyAddr, "policy")},
+		{false, NewMsgRemoveAllowList(admin, "")},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.ValidateBasic()
+		if tc.valid {
+			assert.Nil(t, err, "%d: %+v", i, err)
+			assert.Equal(t, []sdk.Address{admin}, tc.msg.GetSigners(), "%d", i)
+		} else {
+			assert.NotNil(t, err, "%d", i)
+		}
+		assert.Equal(t, MsgType, tc.msg.Type())
+	}
+}
+
+func TestRestrictionsValidation(t *testing.T) {
+	var emptyAddr sdk.Address
+
+	assert.Nil(t, Restrictions{}.ValidateBasic(DefaultCodespace))
+	assert.Nil(t, Restrictions{FrozenAddresses: []sdk.Address{member},
+		AllowLists: []AllowList{{"foo", nil}, {"bar", []sdk.Address{member}}}}.ValidateBasic(DefaultCodespace))
+	assert.NotNil(t, Restrictions{FrozenAddresses: []sdk.Address{emptyAddr}}.ValidateBasic(DefaultCodespace))
+	assert.NotNil(t, Restrictions{AllowLists: []AllowList{{"foo", nil}, {"foo", nil}}}.ValidateBasic(DefaultCodespace))
+}
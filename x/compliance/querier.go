@@ -0,0 +1,33 @@
+package compliance
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+// query endpoints supported by the compliance querier, at /custom/compliance/<route>
+const (
+	QueryRestrictions = "restrictions"
+)
+
+// NewQuerier returns the querier of the compliance module
+func NewQuerier(k Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("No compliance query endpoint specified")
+		}
+		switch path[0] {
+		case QueryRestrictions:
+			return k.marshalQueryResult(k.GetRestrictions(ctx))
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Unknown compliance query endpoint %s", path[0]))
+		}
+	}
+}
+
+func (k Keeper) marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	return k.mustMarshal(res), nil
+}
@@ -0,0 +1,19 @@
+package compliance
+
+// Tags attached to the results of compliance messages and of the blocked
+// transfers, indexed by Tendermint. Addresses are tagged in upper case hex.
+const (
+	TagAction  = "action"
+	TagAdmin   = "admin"
+	TagAddress = "address"
+	TagDenom   = "denom"
+)
+
+// Values of the action tag
+var (
+	ActionFreeze          = []byte("freeze")
+	ActionUnfreeze        = []byte("unfreeze")
+	ActionSetAllowList    = []byte("set-allow-list")
+	ActionRemoveAllowList = []byte("remove-allow-list")
+	ActionTransferBlocked = []byte("transfer-blocked")
+)
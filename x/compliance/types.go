@@ -0,0 +1,83 @@
+package compliance
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "inschain-tendermint/types"
+)
+
+// GenesisState - all compliance state that must be provided at genesis
+type GenesisState struct {
+	Restrictions Restrictions `json:"restrictions"`
+}
+
+// DefaultGenesisState - no admin and no restrictions, they can only be set
+// through governance
+func DefaultGenesisState() GenesisState {
+	return GenesisState{}
+}
+
+// Restrictions on the transfers of coins. The coins of a frozen address can
+// neither be sent nor received. The coins of a denom with an allow-list can
+// only move between the addresses on it. The admin changes the restrictions
+// with the compliance msgs; without an admin, only governance can.
+type Restrictions struct {
+	Admin           sdk.Address   `json:"admin"`
+	FrozenAddresses []sdk.Address `json:"frozen_addresses"`
+	AllowLists      []AllowList   `json:"allow_lists"`
+}
+
+// AllowList - the addresses allowed to send and receive the coins of the denom
+type AllowList struct {
+	Denom     string        `json:"denom"`
+	Addresses []sdk.Address `json:"addresses"`
+}
+
+// ValidateBasic checks the allow-list is well formed
+func (l AllowList) ValidateBasic(codespace sdk.CodespaceType) sdk.Error {
+	if l.Denom == "" {
+		return ErrInvalidRestrictions(codespace, "the denom of an allow-list must not be empty")
+	}
+	for _, addr := range l.Addresses {
+		if len(addr) == 0 {
+			return sdk.ErrInvalidAddress(addr.String())
+		}
+	}
+	return nil
+}
+
+// Allows returns whether the addr is on the allow-list
+func (l AllowList) Allows(addr sdk.Address) bool {
+	return containsAddress(l.Addresses, addr)
+}
+
+// ValidateBasic checks the restrictions are well formed, with a single
+// allow-list per denom
+func (r Restrictions) ValidateBasic(codespace sdk.CodespaceType) sdk.Error {
+	for _, addr := range r.FrozenAddresses {
+		if len(addr) == 0 {
+			return sdk.ErrInvalidAddress(addr.String())
+		}
+	}
+	denoms := make(map[string]bool)
+	for _, list := range r.AllowLists {
+		if err := list.ValidateBasic(codespace); err != nil {
+			return err
+		}
+		if denoms[list.Denom] {
+			return ErrInvalidRestrictions(codespace, fmt.Sprintf("duplicate allow-list for %s", list.Denom))
+		}
+		denoms[list.Denom] = true
+	}
+	return nil
+}
+
+func containsAddress(addrs []sdk.Address, addr sdk.Address) bool {
+	for _, a := range addrs {
+		if bytes.Equal(a, addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package compliance
+
+import (
+	"inschain-tendermint/wire"
+)
+
+// Register concrete types on wire codec
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgFreeze{}, "compliance/Freeze", nil)
+	cdc.RegisterConcrete(MsgUnfreeze{}, "compliance/Unfreeze", nil)
+	cdc.RegisterConcrete(MsgSetAllowList{}, "compliance/SetAllowList", nil)
+	cdc.RegisterConcrete(MsgRemoveAllowList{}, "compliance/RemoveAllowList", nil)
+}
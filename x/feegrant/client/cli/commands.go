@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+
+	"inschain-tendermint/x/feegrant"
+)
+
+const (
+	flagGranter         = "granter"
+	flagGrantee         = "grantee"
+	flagSpendLimit      = "spend-limit"
+	flagExpiration      = "expiration"
+	flagAllowedMsgTypes = "allowed-msg-types"
+)
+
+// AddCommands adds the fee grant subcommands
+func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
+	cmd.AddCommand(
+		client.PostCommands(
+			GrantFeeAllowanceCmd(cdc),
+			RevokeFeeAllowanceCmd(cdc),
+		)...)
+	cmd.AddCommand(
+		client.GetCommands(
+			GetFeeAllowanceCmd("feegrant", cdc),
+			GetFeeAllowancesCmd("feegrant", cdc),
+		)...)
+}
+
+// GrantFeeAllowanceCmd authorizes the grantee to pay fees out of the coins
+// of the key, with --fee-payer set to the key address
+func GrantFeeAllowanceCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grant-fees",
+		Short: "Allow the grantee to pay fees out of the coins of the key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			granter, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			grantee, err := sdk.GetAddress(viper.GetString(flagGrantee))
+			if err != nil {
+				return err
+			}
+			spendLimit, err := sdk.ParseCoins(viper.GetString(flagSpendLimit))
+			if err != nil {
+				return err
+			}
+			var msgTypes []string
+			if types := viper.GetString(flagAllowedMsgTypes); types != "" {
+				msgTypes = strings.Split(types, ",")
+			}
+
+			msg := feegrant.NewMsgGrantFeeAllowance(feegrant.FeeAllowance{
+				Granter:         granter,
+				Grantee:         grantee,
+				SpendLimit:      spendLimit,
+				Expiration:      viper.GetInt64(flagExpiration),
+				AllowedMsgTypes: msgTypes,
+			})
+			return ctx.SendMsg(msg, cdc)
+		},
+	}
+	cmd.Flags().String(flagGrantee, "", "Address of the grantee")
+	cmd.Flags().String(flagSpendLimit, "", "Fees the grantee may pay in total")
+	cmd.Flags().Int64(flagExpiration, 0, "Unix time the allowance expires at, 0 never expires")
+	cmd.Flags().String(flagAllowedMsgTypes, "", "Comma separated msg types the fees may be paid for, eg. mutual,bank (default any)")
+	return cmd
+}
+
+// RevokeFeeAllowanceCmd revokes the allowance of the key to the grantee
+func RevokeFeeAllowanceCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke-fees",
+		Short: "Revoke the fee allowance of the key to the grantee",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			granter, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			grantee, err := sdk.GetAddress(viper.GetString(flagGrantee))
+			if err != nil {
+				return err
+			}
+			return ctx.SendMsg(feegrant.NewMsgRevokeFeeAllowance(granter, grantee), cdc)
+		},
+	}
+	cmd.Flags().String(flagGrantee, "", "Address of the grantee")
+	return cmd
+}
+
+// GetFeeAllowanceCmd queries the allowance of a granter to a grantee
+func GetFeeAllowanceCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fee-allowance",
+		Short: "Query the fee allowance of a granter to a grantee",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			granter, err := sdk.GetAddress(viper.GetString(flagGranter))
+			if err != nil {
+				return err
+			}
+			grantee, err := sdk.GetAddress(viper.GetString(flagGrantee))
+			if err != nil {
+				return err
+			}
+			params := feegrant.QueryAllowanceParams{Granter: granter, Grantee: grantee}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, feegrant.QueryAllowance, params, new(feegrant.FeeAllowance), cdc)
+		},
+	}
+	cmd.Flags().String(flagGranter, "", "Address of the granter")
+	cmd.Flags().String(flagGrantee, "", "Address of the grantee")
+	return cmd
+}
+
+// GetFeeAllowancesCmd queries the allowances to a grantee
+func GetFeeAllowancesCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fee-allowances",
+		Short: "Query the fee allowances to a grantee",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grantee, err := sdk.GetAddress(viper.GetString(flagGrantee))
+			if err != nil {
+				return err
+			}
+			params := feegrant.QueryAllowancesParams{Grantee: grantee}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, feegrant.QueryAllowances, params, new([]feegrant.FeeAllowance), cdc)
+		},
+	}
+	cmd.Flags().String(flagGrantee, "", "Address of the grantee")
+	return cmd
+}
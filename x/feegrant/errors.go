@@ -0,0 +1,66 @@
+package feegrant
+
+import (
+	sdk "inschain-tendermint/types"
+)
+
+// Fee grant errors reserve 600 ~ 699.
+const (
+	DefaultCodespace sdk.CodespaceType = 7
+
+	CodeNoAllowance        sdk.CodeType = 600
+	CodeAllowanceExpired   sdk.CodeType = 601
+	CodeMsgTypeNotAllowed  sdk.CodeType = 602
+	CodeSpendLimitExceeded sdk.CodeType = 603
+	CodeInvalidAllowance   sdk.CodeType = 604
+	CodeUnknownRequest     sdk.CodeType = sdk.CodeUnknownRequest
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeNoAllowance:
+		return "No fee allowance from the granter to the grantee"
+	case CodeAllowanceExpired:
+		return "Fee allowance expired"
+	case CodeMsgTypeNotAllowed:
+		return "Fee allowance does not allow the msg type"
+	case CodeSpendLimitExceeded:
+		return "Fee exceeds the spend limit of the allowance"
+	case CodeInvalidAllowance:
+		return "Invalid fee allowance"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// nolint
+func ErrNoAllowance(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeNoAllowance, "")
+}
+func ErrAllowanceExpired(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeAllowanceExpired, "")
+}
+func ErrMsgTypeNotAllowed(codespace sdk.CodespaceType, msgType string) sdk.Error {
+	return newError(codespace, CodeMsgTypeNotAllowed, "Fee allowance does not allow msgs of type "+msgType)
+}
+func ErrSpendLimitExceeded(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeSpendLimitExceeded, msg)
+}
+func ErrInvalidAllowance(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidAllowance, msg)
+}
+
+// -------------------------
+// Helpers
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	msg = msgOrDefaultMsg(msg, code)
+	return sdk.NewError(codespace, code, msg)
+}
+
+func msgOrDefaultMsg(msg string, code sdk.CodeType) string {
+	if msg != "" {
+		return msg
+	}
+	return codeToDefaultMsg(code)
+}
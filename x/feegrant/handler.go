@@ -0,0 +1,50 @@
+package feegrant
+
+import (
+	"reflect"
+
+	sdk "inschain-tendermint/types"
+)
+
+// NewHandler returns a handler for "feegrant" type messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgGrantFeeAllowance:
+			return handleMsgGrantFeeAllowance(ctx, k, msg)
+		case MsgRevokeFeeAllowance:
+			return handleMsgRevokeFeeAllowance(ctx, k, msg)
+		default:
+			errMsg := "Unrecognized feegrant Msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgGrantFeeAllowance(ctx sdk.Context, k Keeper, msg MsgGrantFeeAllowance) sdk.Result {
+	err := k.GrantFeeAllowance(ctx, msg.Allowance())
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: sdk.NewTags(
+			TagAction, ActionGrantFeeAllowance,
+			TagGranter, []byte(msg.Granter.String()),
+			TagGrantee, []byte(msg.Grantee.String()),
+		),
+	}
+}
+
+func handleMsgRevokeFeeAllowance(ctx sdk.Context, k Keeper, msg MsgRevokeFeeAllowance) sdk.Result {
+	err := k.RevokeFeeAllowance(ctx, msg.Granter, msg.Grantee)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: sdk.NewTags(
+			TagAction, ActionRevokeFeeAllowance,
+			TagGranter, []byte(msg.Granter.String()),
+			TagGrantee, []byte(msg.Grantee.String()),
+		),
+	}
+}
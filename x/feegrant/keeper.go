@@ -0,0 +1,135 @@
+package feegrant
+
+import (
+	"fmt"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+)
+
+var (
+	// Keys for store prefixes
+	FeeAllowanceKeyPrefix = []byte{0x00} // prefix for the fee allowances, by grantee
+)
+
+// get the key for the allowance of the granter to the grantee
+func GetFeeAllowanceKey(grantee, granter sdk.Address) []byte {
+	return append(GetFeeAllowancesKey(grantee), granter.Bytes()...)
+}
+
+// get the key prefix for the allowances to the grantee. The grantee is
+// length prefixed, so that it doesn't prefix the keys of longer addresses.
+func GetFeeAllowancesKey(grantee sdk.Address) []byte {
+	key := append(FeeAllowanceKeyPrefix, byte(len(grantee)))
+	return append(key, grantee.Bytes()...)
+}
+
+var _ auth.FeeGrantKeeper = Keeper{}
+
+// Keeper manages the fee allowances
+type Keeper struct {
+	key       sdk.StoreKey
+	cdc       *wire.Codec
+	codespace sdk.CodespaceType
+}
+
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		key:       key,
+		cdc:       cdc,
+		codespace: codespace,
+	}
+}
+
+// GetFeeAllowance returns the allowance of the granter to the grantee, if any
+func (k Keeper) GetFeeAllowance(ctx sdk.Context, granter, grantee sdk.Address) (allowance FeeAllowance, found bool) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetFeeAllowanceKey(grantee, granter))
+	if bz == nil {
+		return allowance, false
+	}
+	err := k.cdc.UnmarshalJSON(bz, &allowance)
+	if err != nil {
+		panic(err)
+	}
+	return allowance, true
+}
+
+// GetFeeAllowances returns the allowances to the grantee
+func (k Keeper) GetFeeAllowances(ctx sdk.Context, grantee sdk.Address) []FeeAllowance {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(GetFeeAllowancesKey(grantee))
+	defer iterator.Close()
+
+	allowances := []FeeAllowance{}
+	for ; iterator.Valid(); iterator.Next() {
+		var allowance FeeAllowance
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &allowance)
+		if err != nil {
+			panic(err)
+		}
+		allowances = append(allowances, allowance)
+	}
+	return allowances
+}
+
+func (k Keeper) setFeeAllowance(ctx sdk.Context, allowance FeeAllowance) {
+	store := ctx.KVStore(k.key)
+	bz, err := k.cdc.MarshalJSON(allowance)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(GetFeeAllowanceKey(allowance.Grantee, allowance.Granter), bz)
+}
+
+func (k Keeper) deleteFeeAllowance(ctx sdk.Context, granter, grantee sdk.Address) {
+	store := ctx.KVStore(k.key)
+	store.Delete(GetFeeAllowanceKey(grantee, granter))
+}
+
+// GrantFeeAllowance sets the allowance, it replaces the previous allowance
+// of the granter to the grantee if any
+func (k Keeper) GrantFeeAllowance(ctx sdk.Context, allowance FeeAllowance) sdk.Error {
+	if allowance.IsExpired(ctx.BlockHeader().Time) {
+		return ErrAllowanceExpired(k.codespace)
+	}
+	k.setFeeAllowance(ctx, allowance)
+	return nil
+}
+
+// RevokeFeeAllowance deletes the allowance of the granter to the grantee
+func (k Keeper) RevokeFeeAllowance(ctx sdk.Context, granter, grantee sdk.Address) sdk.Error {
+	if _, found := k.GetFeeAllowance(ctx, granter, grantee); !found {
+		return ErrNoAllowance(k.codespace)
+	}
+	k.deleteFeeAllowance(ctx, granter, grantee)
+	return nil
+}
+
+// UseGrantedFees deducts the fee of the msgs from the allowance of the granter
+// to the grantee, the allowance is deleted once its spend limit is used up.
+// Implements auth.FeeGrantKeeper.
+func (k Keeper) UseGrantedFees(ctx sdk.Context, granter, grantee sdk.Address, fee sdk.Coins, msgs []sdk.Msg) sdk.Error {
+	allowance, found := k.GetFeeAllowance(ctx, granter, grantee)
+	if !found {
+		return ErrNoAllowance(k.codespace)
+	}
+	if allowance.IsExpired(ctx.BlockHeader().Time) {
+		return ErrAllowanceExpired(k.codespace)
+	}
+	if msg := allowance.disallowedMsg(msgs); msg != nil {
+		return ErrMsgTypeNotAllowed(k.codespace, msg.Type())
+	}
+	if !allowance.SpendLimit.IsGTE(fee) {
+		return ErrSpendLimitExceeded(k.codespace, fmt.Sprintf("%s < %s", allowance.SpendLimit, fee))
+	}
+
+	allowance.SpendLimit = allowance.SpendLimit.Minus(fee)
+	if allowance.SpendLimit.IsZero() {
+		k.deleteFeeAllowance(ctx, granter, grantee)
+		return nil
+	}
+	k.setFeeAllowance(ctx, allowance)
+	return nil
+}
@@ -0,0 +1,104 @@
+package feegrant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/tendermint/tmlibs/log"
+
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+)
+
+var (
+	granter  = sdk.Address([]byte("granter"))
+	grantee  = sdk.Address([]byte("grantee"))
+	grantee2 = sdk.Address([]byte("grantee2"))
+)
+
+func createTestInput(t *testing.T) (sdk.Context, Keeper) {
+	db := dbm.NewMemDB()
+	key := sdk.NewKVStoreKey("feegrant")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	err := ms.LoadLatestVersion()
+	require.Nil(t, err)
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "foochainid", Time: 1000}, false, nil, log.NewNopLogger())
+	cdc := wire.NewCodec()
+	RegisterWire(cdc)
+	return ctx, NewKeeper(cdc, key, DefaultCodespace)
+}
+
+func TestGrantRevokeFeeAllowance(t *testing.T) {
+	ctx, keeper := createTestInput(t)
+
+	_, found := keeper.GetFeeAllowance(ctx, granter, grantee)
+	assert.False(t, found)
+	assert.NotNil(t, keeper.RevokeFeeAllowance(ctx, granter, grantee))
+
+	// an expired allowance can't be granted
+	allowance := FeeAllowance{granter, grantee, sdk.Coins{{"getx", 100}}, 1000, nil}
+	assert.NotNil(t, keeper.GrantFeeAllowance(ctx, allowance))
+
+	allowance.Expiration = 2000
+	assert.Nil(t, keeper.GrantFeeAllowance(ctx, allowance))
+	saved, found := keeper.GetFeeAllowance(ctx, granter, grantee)
+	assert.True(t, found)
+	assert.Equal(t, allowance, saved)
+
+	allowance2 := FeeAllowance{granter, grantee2, sdk.Coins{{"getx", 10}}, 0, nil}
+	assert.Nil(t, keeper.GrantFeeAllowance(ctx, allowance2))
+	assert.Equal(t, []FeeAllowance{allowance}, keeper.GetFeeAllowances(ctx, grantee))
+	assert.Equal(t, []FeeAllowance{allowance2}, keeper.GetFeeAllowances(ctx, grantee2))
+
+	assert.Nil(t, keeper.RevokeFeeAllowance(ctx, granter, grantee))
+	_, found = keeper.GetFeeAllowance(ctx, granter, grantee)
+	assert.False(t, found)
+	assert.Equal(t, []FeeAllowance{}, keeper.GetFeeAllowances(ctx, grantee))
+}
+
+func TestUseGrantedFees(t *testing.T) {
+	ctx, keeper := createTestInput(t)
+	msgs := []sdk.Msg{NewMsgRevokeFeeAllowance(grantee, grantee2)}
+	fee := sdk.Coins{{"getx", 40}}
+
+	// no allowance
+	err := keeper.UseGrantedFees(ctx, granter, grantee, fee, msgs)
+	assert.Equal(t, CodeNoAllowance, err.Code())
+
+	// the msg type is not allowed
+	allowance := FeeAllowance{granter, grantee, sdk.Coins{{"getx", 100}}, 2000, []string{"mutual"}}
+	assert.Nil(t, keeper.GrantFeeAllowance(ctx, allowance))
+	err = keeper.UseGrantedFees(ctx, granter, grantee, fee, msgs)
+	assert.Equal(t, CodeMsgTypeNotAllowed, err.Code())
+
+	// the fees are deducted from the spend limit
+	allowance.AllowedMsgTypes = []string{"mutual", MsgType}
+	assert.Nil(t, keeper.GrantFeeAllowance(ctx, allowance))
+	assert.Nil(t, keeper.UseGrantedFees(ctx, granter, grantee, fee, msgs))
+	assert.Nil(t, keeper.UseGrantedFees(ctx, granter, grantee, fee, msgs))
+	saved, _ := keeper.GetFeeAllowance(ctx, granter, grantee)
+	assert.Equal(t, sdk.Coins{{"getx", 20}}, saved.SpendLimit)
+
+	// up to the spend limit
+	err = keeper.UseGrantedFees(ctx, granter, grantee, fee, msgs)
+	assert.Equal(t, CodeSpendLimitExceeded, err.Code())
+	err = keeper.UseGrantedFees(ctx, granter, grantee, sdk.Coins{{"steak", 1}}, msgs)
+	assert.Equal(t, CodeSpendLimitExceeded, err.Code())
+
+	// until the expiration
+	expired := ctx.WithBlockHeader(abci.Header{ChainID: "foochainid", Time: 2000})
+	err = keeper.UseGrantedFees(expired, granter, grantee, sdk.Coins{{"getx", 10}}, msgs)
+	assert.Equal(t, CodeAllowanceExpired, err.Code())
+
+	// the used up allowance is deleted
+	assert.Nil(t, keeper.UseGrantedFees(ctx, granter, grantee, sdk.Coins{{"getx", 20}}, msgs))
+	_, found := keeper.GetFeeAllowance(ctx, granter, grantee)
+	assert.False(t, found)
+}
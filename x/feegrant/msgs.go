@@ -0,0 +1,113 @@
+package feegrant
+
+import (
+	"encoding/json"
+
+	sdk "inschain-tendermint/types"
+)
+
+// name to identify the fee grant msgs
+const MsgType = "feegrant"
+
+//----------------------------------------
+// MsgGrantFeeAllowance
+
+// MsgGrantFeeAllowance - the granter authorizes the grantee to pay fees
+// out of the granter's coins, see FeeAllowance
+type MsgGrantFeeAllowance struct {
+	Granter         sdk.Address `json:"granter"`
+	Grantee         sdk.Address `json:"grantee"`
+	SpendLimit      sdk.Coins   `json:"spend_limit"`
+	Expiration      int64       `json:"expiration"`
+	AllowedMsgTypes []string    `json:"allowed_msg_types"`
+}
+
+var _ sdk.Msg = MsgGrantFeeAllowance{}
+
+// NewMsgGrantFeeAllowance - construct the msg granting the allowance
+func NewMsgGrantFeeAllowance(allowance FeeAllowance) MsgGrantFeeAllowance {
+	return MsgGrantFeeAllowance{
+		Granter:         allowance.Granter,
+		Grantee:         allowance.Grantee,
+		SpendLimit:      allowance.SpendLimit,
+		Expiration:      allowance.Expiration,
+		AllowedMsgTypes: allowance.AllowedMsgTypes,
+	}
+}
+
+// Allowance returns the allowance granted by the msg
+func (msg MsgGrantFeeAllowance) Allowance() FeeAllowance {
+	return FeeAllowance{
+		Granter:         msg.Granter,
+		Grantee:         msg.Grantee,
+		SpendLimit:      msg.SpendLimit,
+		Expiration:      msg.Expiration,
+		AllowedMsgTypes: msg.AllowedMsgTypes,
+	}
+}
+
+// Implements Msg.
+func (msg MsgGrantFeeAllowance) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgGrantFeeAllowance) ValidateBasic() sdk.Error {
+	return msg.Allowance().ValidateBasic()
+}
+
+// Implements Msg.
+func (msg MsgGrantFeeAllowance) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgGrantFeeAllowance) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Granter}
+}
+
+//----------------------------------------
+// MsgRevokeFeeAllowance
+
+// MsgRevokeFeeAllowance - the granter revokes its allowance to the grantee
+type MsgRevokeFeeAllowance struct {
+	Granter sdk.Address `json:"granter"`
+	Grantee sdk.Address `json:"grantee"`
+}
+
+var _ sdk.Msg = MsgRevokeFeeAllowance{}
+
+// NewMsgRevokeFeeAllowance - construct the msg revoking the allowance
+func NewMsgRevokeFeeAllowance(granter, grantee sdk.Address) MsgRevokeFeeAllowance {
+	return MsgRevokeFeeAllowance{Granter: granter, Grantee: grantee}
+}
+
+// Implements Msg.
+func (msg MsgRevokeFeeAllowance) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgRevokeFeeAllowance) ValidateBasic() sdk.Error {
+	if len(msg.Granter) == 0 {
+		return sdk.ErrInvalidAddress(msg.Granter.String())
+	}
+	if len(msg.Grantee) == 0 {
+		return sdk.ErrInvalidAddress(msg.Grantee.String())
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgRevokeFeeAllowance) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgRevokeFeeAllowance) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Granter}
+}
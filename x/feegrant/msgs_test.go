@@ -0,0 +1,49 @@
+package feegrant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sdk "inschain-tendermint/types"
+)
+
+func TestMsgGrantFeeAllowanceValidation(t *testing.T) {
+	var emptyAddr sdk.Address
+	limit := sdk.Coins{{"getx", 100}}
+
+	cases := []struct {
+		valid bool
+		msg   MsgGrantFeeAllowance
+	}{
+		{true, MsgGrantFeeAllowance{granter, grantee, limit, 0, nil}},
+		{true, MsgGrantFeeAllowance{granter, grantee, limit, 1000, []string{"mutual"}}},
+		{false, MsgGrantFeeAllowance{emptyAddr, grantee, limit, 0, nil}},
+		{false, MsgGrantFeeAllowance{granter, emptyAddr, limit, 0, nil}},
+		{false, MsgGrantFeeAllowance{granter, granter, limit, 0, nil}},
+		{false, MsgGrantFeeAllowance{granter, grantee, nil, 0, nil}},
+		{false, MsgGrantFeeAllowance{granter, grantee, sdk.Coins{{"getx", -1}}, 0, nil}},
+		{false, MsgGrantFeeAllowance{granter, grantee, limit, -1, nil}},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.ValidateBasic()
+		if tc.valid {
+			assert.Nil(t, err, "%d: %+v", i, err)
+		} else {
+			assert.NotNil(t, err, "%d", i)
+		}
+	}
+
+	msg := NewMsgGrantFeeAllowance(FeeAllowance{granter, grantee, limit, 0, nil})
+	assert.Equal(t, MsgType, msg.Type())
+	assert.Equal(t, []sdk.Address{granter}, msg.GetSigners())
+}
+
+func TestMsgRevokeFeeAllowanceValidation(t *testing.T) {
+	var emptyAddr sdk.Address
+	assert.Nil(t, NewMsgRevokeFeeAllowance(granter, grantee).ValidateBasic())
+	assert.NotNil(t, NewMsgRevokeFeeAllowance(emptyAddr, grantee).ValidateBasic())
+	assert.NotNil(t, NewMsgRevokeFeeAllowance(granter, emptyAddr).ValidateBasic())
+	assert.Equal(t, []sdk.Address{granter}, NewMsgRevokeFeeAllowance(granter, grantee).GetSigners())
+}
@@ -0,0 +1,79 @@
+package feegrant
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+// query endpoints supported by the fee grant querier, at /custom/feegrant/<route>
+const (
+	QueryAllowance  = "allowance"
+	QueryAllowances = "allowances"
+)
+
+// parameters of the allowance query
+type QueryAllowanceParams struct {
+	Granter sdk.Address `json:"granter"`
+	Grantee sdk.Address `json:"grantee"`
+}
+
+// parameters of the query of the allowances to a grantee
+type QueryAllowancesParams struct {
+	Grantee sdk.Address `json:"grantee"`
+}
+
+// NewQuerier returns the querier of the fee grant module
+func NewQuerier(k Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("No feegrant query endpoint specified")
+		}
+		switch path[0] {
+		case QueryAllowance:
+			return queryAllowance(ctx, req, k)
+		case QueryAllowances:
+			return queryAllowances(ctx, req, k)
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Unknown feegrant query endpoint %s", path[0]))
+		}
+	}
+}
+
+func queryAllowance(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryAllowanceParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	allowance, found := k.GetFeeAllowance(ctx, params.Granter, params.Grantee)
+	if !found {
+		return nil, ErrNoAllowance(k.codespace)
+	}
+	return k.marshalQueryResult(allowance)
+}
+
+func queryAllowances(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryAllowancesParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	return k.marshalQueryResult(k.GetFeeAllowances(ctx, params.Grantee))
+}
+
+func (k Keeper) unmarshalQueryParams(req abci.RequestQuery, params interface{}) sdk.Error {
+	err := k.cdc.UnmarshalJSON(req.Data, params)
+	if err != nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("Incorrectly formatted query data: %s", err.Error()))
+	}
+	return nil
+}
+
+func (k Keeper) marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	bz, err := k.cdc.MarshalJSON(res)
+	if err != nil {
+		panic(err)
+	}
+	return bz, nil
+}
@@ -0,0 +1,15 @@
+package feegrant
+
+// Tags attached to the results of fee grant messages, indexed by Tendermint.
+// Addresses are tagged in upper case hex.
+const (
+	TagAction  = "action"
+	TagGranter = "granter"
+	TagGrantee = "grantee"
+)
+
+// Values of the action tag
+var (
+	ActionGrantFeeAllowance  = []byte("grant-fee-allowance")
+	ActionRevokeFeeAllowance = []byte("revoke-fee-allowance")
+)
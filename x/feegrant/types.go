@@ -0,0 +1,62 @@
+package feegrant
+
+import (
+	sdk "inschain-tendermint/types"
+)
+
+// FeeAllowance authorizes the Grantee to pay the fees of its txs out of the
+// coins of the Granter, set as the fee payer of the txs. The fees are deducted
+// from the SpendLimit, until the Expiration block time if any (in unix seconds).
+// With AllowedMsgTypes, the txs may only hold msgs of these types.
+type FeeAllowance struct {
+	Granter         sdk.Address `json:"granter"`
+	Grantee         sdk.Address `json:"grantee"`
+	SpendLimit      sdk.Coins   `json:"spend_limit"`
+	Expiration      int64       `json:"expiration"`
+	AllowedMsgTypes []string    `json:"allowed_msg_types"`
+}
+
+// ValidateBasic checks the allowance is well formed
+func (a FeeAllowance) ValidateBasic() sdk.Error {
+	if len(a.Granter) == 0 {
+		return sdk.ErrInvalidAddress(a.Granter.String())
+	}
+	if len(a.Grantee) == 0 {
+		return sdk.ErrInvalidAddress(a.Grantee.String())
+	}
+	if a.Granter.String() == a.Grantee.String() {
+		return ErrInvalidAllowance(DefaultCodespace, "Cannot grant a fee allowance to self")
+	}
+	if !a.SpendLimit.IsValid() || !a.SpendLimit.IsPositive() {
+		return sdk.ErrInvalidCoins(a.SpendLimit.String())
+	}
+	if a.Expiration < 0 {
+		return ErrInvalidAllowance(DefaultCodespace, "Negative expiration")
+	}
+	return nil
+}
+
+// IsExpired returns whether the allowance expired at the block time
+func (a FeeAllowance) IsExpired(blockTime int64) bool {
+	return a.Expiration != 0 && blockTime >= a.Expiration
+}
+
+// the first msg of a type the allowance doesn't allow, if any
+func (a FeeAllowance) disallowedMsg(msgs []sdk.Msg) sdk.Msg {
+	if len(a.AllowedMsgTypes) == 0 {
+		return nil
+	}
+	for _, msg := range msgs {
+		allowed := false
+		for _, msgType := range a.AllowedMsgTypes {
+			if msg.Type() == msgType {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return msg
+		}
+	}
+	return nil
+}
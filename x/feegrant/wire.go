@@ -0,0 +1,11 @@
+package feegrant
+
+import (
+	"inschain-tendermint/wire"
+)
+
+// Register concrete types on wire codec
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgGrantFeeAllowance{}, "feegrant/GrantFeeAllowance", nil)
+	cdc.RegisterConcrete(MsgRevokeFeeAllowance{}, "feegrant/RevokeFeeAllowance", nil)
+}
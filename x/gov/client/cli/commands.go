@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+
+	"inschain-tendermint/x/gov"
+)
+
+const (
+	flagTitle         = "title"
+	flagDescription   = "description"
+	flagProposalType  = "type"
+	flagDeposit       = "deposit"
+	flagParamChange   = "param"
+	flagUpgradeName   = "upgrade-name"
+	flagUpgradeHeight = "upgrade-height"
+	flagUpgradeInfo   = "upgrade-info"
+	flagProposalID    = "proposal-id"
+	flagOption        = "option"
+	flagStatus        = "status"
+)
+
+// AddCommands adds the governance subcommands, under a gov command as
+// other modules have proposals too
+func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
+	govCmd := &cobra.Command{
+		Use:   "gov",
+		Short: "Governance subcommands",
+	}
+	govCmd.AddCommand(
+		client.PostCommands(
+			SubmitProposalCmd(cdc),
+			DepositCmd(cdc),
+			VoteCmd(cdc),
+		)...)
+	govCmd.AddCommand(
+		client.GetCommands(
+			GetProposalCmd("gov", cdc),
+			GetProposalsCmd("gov", cdc),
+			GetDepositsCmd("gov", cdc),
+			GetVotesCmd("gov", cdc),
+			GetTallyCmd("gov", cdc),
+			GetParamsCmd("gov", cdc),
+			GetUpgradeCmd("gov", cdc),
+		)...)
+	cmd.AddCommand(govCmd)
+}
+
+// SubmitProposalCmd submits a proposal from the key, with its first deposit
+func SubmitProposalCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "submit-proposal",
+		Short: "Submit a text, parameter-change or software-upgrade proposal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			proposer, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			deposit, err := sdk.ParseCoins(viper.GetString(flagDeposit))
+			if err != nil {
+				return err
+			}
+			proposalType, err := gov.ProposalTypeFromString(viper.GetString(flagProposalType))
+			if err != nil {
+				return err
+			}
+
+			title, description := viper.GetString(flagTitle), viper.GetString(flagDescription)
+			var msg gov.MsgSubmitProposal
+			switch proposalType {
+			case gov.ProposalTypeParameterChange:
+				changes, err := parseParamChanges(viper.GetStringSlice(flagParamChange))
+				if err != nil {
+					return err
+				}
+				msg = gov.NewMsgSubmitParamChangeProposal(title, description, changes, proposer, deposit)
+			case gov.ProposalTypeSoftwareUpgrade:
+				upgrade := gov.SoftwareUpgrade{
+					Name:   viper.GetString(flagUpgradeName),
+					Height: viper.GetInt64(flagUpgradeHeight),
+					Info:   viper.GetString(flagUpgradeInfo),
+				}
+				msg = gov.NewMsgSubmitSoftwareUpgradeProposal(title, description, upgrade, proposer, deposit)
+			default:
+				msg = gov.NewMsgSubmitProposal(title, description, proposer, deposit)
+			}
+			return ctx.SendMsg(msg, cdc)
+		},
+	}
+	cmd.Flags().String(flagTitle, "", "title of the proposal")
+	cmd.Flags().String(flagDescription, "", "description of the proposal")
+	cmd.Flags().String(flagProposalType, "text", "type of the proposal: text, parameter-change or software-upgrade")
+	cmd.Flags().String(flagDeposit, "", "initial deposit on the proposal (ex. 10steak)")
+	cmd.Flags().StringSlice(flagParamChange, nil, "param to change, as <module>/<key>=<JSON value> (ex. stake/max_validators=120)")
+	cmd.Flags().String(flagUpgradeName, "", "name of the software to upgrade to")
+	cmd.Flags().Int64(flagUpgradeHeight, 0, "height at which to upgrade the software")
+	cmd.Flags().String(flagUpgradeInfo, "", "where to get the software to upgrade to")
+	return cmd
+}
+
+// parse the <module>/<key>=<value> param changes
+func parseParamChanges(strs []string) (changes []gov.ParamChange, err error) {
+	for _, str := range strs {
+		kv := strings.SplitN(str, "=", 2)
+		path := strings.SplitN(kv[0], "/", 2)
+		if len(kv) != 2 || len(path) != 2 {
+			return nil, fmt.Errorf("param change %s must be formatted as <module>/<key>=<value>", str)
+		}
+		changes = append(changes, gov.ParamChange{Module: path[0], Key: path[1], Value: kv[1]})
+	}
+	return changes, nil
+}
+
+// DepositCmd adds coins of the key to the deposit of a proposal
+func DepositCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deposit",
+		Short: "Deposit coins on a proposal in its deposit period",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			depositor, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			amount, err := sdk.ParseCoins(viper.GetString(flagDeposit))
+			if err != nil {
+				return err
+			}
+			msg := gov.NewMsgDeposit(viper.GetInt64(flagProposalID), depositor, amount)
+			return ctx.SendMsg(msg, cdc)
+		},
+	}
+	cmd.Flags().Int64(flagProposalID, 0, "id of the proposal")
+	cmd.Flags().String(flagDeposit, "", "coins to deposit (ex. 10steak)")
+	return cmd
+}
+
+// VoteCmd votes on a proposal with the key
+func VoteCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vote",
+		Short: "Vote on a proposal in its voting period",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			voter, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			option, err := gov.VoteOptionFromString(viper.GetString(flagOption))
+			if err != nil {
+				return err
+			}
+			msg := gov.NewMsgVote(viper.GetInt64(flagProposalID), voter, option)
+			return ctx.SendMsg(msg, cdc)
+		},
+	}
+	cmd.Flags().Int64(flagProposalID, 0, "id of the proposal")
+	cmd.Flags().String(flagOption, "", "vote option: yes, no, no-with-veto or abstain")
+	return cmd
+}
+
+// GetProposalCmd queries a proposal
+func GetProposalCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proposal",
+		Short: "Query a proposal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := gov.QueryProposalParams{ProposalID: viper.GetInt64(flagProposalID)}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, gov.QueryProposal, params, new(gov.Proposal), cdc)
+		},
+	}
+	cmd.Flags().Int64(flagProposalID, 0, "id of the proposal")
+	return cmd
+}
+
+// GetProposalsCmd queries the proposals, optionally only those with a status
+func GetProposalsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proposals",
+		Short: "Query the proposals",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var params gov.QueryProposalsParams
+			switch status := viper.GetString(flagStatus); status {
+			case "":
+			case gov.StatusDepositPeriod.String():
+				params.Status = gov.StatusDepositPeriod
+			case gov.StatusVotingPeriod.String():
+				params.Status = gov.StatusVotingPeriod
+			case gov.StatusPassed.String():
+				params.Status = gov.StatusPassed
+			case gov.StatusRejected.String():
+				params.Status = gov.StatusRejected
+			default:
+				return fmt.Errorf("'%s' is not a valid proposal status", status)
+			}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, gov.QueryProposals, params, new([]gov.Proposal), cdc)
+		},
+	}
+	cmd.Flags().String(flagStatus, "", "only the proposals with the status: deposit-period, voting-period, passed or rejected")
+	return cmd
+}
+
+// GetDepositsCmd queries the deposits on a proposal
+func GetDepositsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deposits",
+		Short: "Query the deposits on a proposal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := gov.QueryProposalParams{ProposalID: viper.GetInt64(flagProposalID)}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, gov.QueryDeposits, params, new([]gov.Deposit), cdc)
+		},
+	}
+	cmd.Flags().Int64(flagProposalID, 0, "id of the proposal")
+	return cmd
+}
+
+// GetVotesCmd queries the votes on a proposal
+func GetVotesCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "votes",
+		Short: "Query the votes on a proposal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := gov.QueryProposalParams{ProposalID: viper.GetInt64(flagProposalID)}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, gov.QueryVotes, params, new([]gov.Vote), cdc)
+		},
+	}
+	cmd.Flags().Int64(flagProposalID, 0, "id of the proposal")
+	return cmd
+}
+
+// GetTallyCmd queries the tally of a proposal
+func GetTallyCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tally",
+		Short: "Query the current tally of a proposal being voted on, or the final one",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := gov.QueryProposalParams{ProposalID: viper.GetInt64(flagProposalID)}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, gov.QueryTally, params, new(gov.TallyResult), cdc)
+		},
+	}
+	cmd.Flags().Int64(flagProposalID, 0, "id of the proposal")
+	return cmd
+}
+
+// GetParamsCmd queries the governance params
+func GetParamsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gov-params",
+		Short: "Query the governance params",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, gov.QueryParams, struct{}{}, new(gov.Params), cdc)
+		},
+	}
+	return cmd
+}
+
+// GetUpgradeCmd queries the last software upgrade passed
+func GetUpgradeCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Query the last software upgrade passed by governance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, gov.QueryUpgrade, struct{}{}, new(gov.SoftwareUpgrade), cdc)
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,265 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tendermint/go-crypto/keys"
+
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+
+	"inschain-tendermint/x/gov"
+)
+
+// RegisterRoutes - Central function to define routes that get registered by the main application
+func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, kb keys.Keybase) {
+	r.HandleFunc("/gov/proposals", SubmitProposalHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/gov/proposals/{proposalID}/deposits", DepositHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/gov/proposals/{proposalID}/votes", VoteHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/gov/proposals", ProposalsHandlerFn("gov", cdc, ctx)).Methods("GET")
+	r.HandleFunc("/gov/proposals/{proposalID}", ProposalQueryHandlerFn("gov", gov.QueryProposal, cdc, ctx)).Methods("GET")
+	r.HandleFunc("/gov/proposals/{proposalID}/deposits", ProposalQueryHandlerFn("gov", gov.QueryDeposits, cdc, ctx)).Methods("GET")
+	r.HandleFunc("/gov/proposals/{proposalID}/votes", ProposalQueryHandlerFn("gov", gov.QueryVotes, cdc, ctx)).Methods("GET")
+	r.HandleFunc("/gov/proposals/{proposalID}/tally", ProposalQueryHandlerFn("gov", gov.QueryTally, cdc, ctx)).Methods("GET")
+	r.HandleFunc("/gov/params", QueryHandlerFn("gov", gov.QueryParams, cdc, ctx)).Methods("GET")
+	r.HandleFunc("/gov/upgrade", QueryHandlerFn("gov", gov.QueryUpgrade, cdc, ctx)).Methods("GET")
+}
+
+// the fields every governance tx request carries to sign it
+type baseReq struct {
+	LocalAccountName string `json:"name"`
+	Password         string `json:"password"`
+	ChainID          string `json:"chain_id"`
+	Sequence         int64  `json:"sequence"`
+	GenerateOnly     bool   `json:"generate_only"`
+}
+
+type submitProposalBody struct {
+	baseReq
+	Title          string              `json:"title"`
+	Description    string              `json:"description"`
+	ProposalType   string              `json:"proposal_type"`
+	ParamChanges   []gov.ParamChange   `json:"param_changes"`
+	Upgrade        gov.SoftwareUpgrade `json:"upgrade"`
+	InitialDeposit sdk.Coins           `json:"initial_deposit"`
+}
+
+type depositBody struct {
+	baseReq
+	Amount sdk.Coins `json:"amount"`
+}
+
+type voteBody struct {
+	baseReq
+	Option string `json:"option"`
+}
+
+// SubmitProposalHandlerFn - http request handler to submit a proposal
+func SubmitProposalHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m submitProposalBody
+		if !readBody(w, r, &m) {
+			return
+		}
+		info, err := kb.Get(m.LocalAccountName)
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, err)
+			return
+		}
+		proposalType, err := gov.ProposalTypeFromString(m.ProposalType)
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err)
+			return
+		}
+
+		proposer := sdk.Address(info.PubKey.Address())
+		var msg gov.MsgSubmitProposal
+		switch proposalType {
+		case gov.ProposalTypeParameterChange:
+			msg = gov.NewMsgSubmitParamChangeProposal(m.Title, m.Description, m.ParamChanges, proposer, m.InitialDeposit)
+		case gov.ProposalTypeSoftwareUpgrade:
+			msg = gov.NewMsgSubmitSoftwareUpgradeProposal(m.Title, m.Description, m.Upgrade, proposer, m.InitialDeposit)
+		default:
+			msg = gov.NewMsgSubmitProposal(m.Title, m.Description, proposer, m.InitialDeposit)
+		}
+		signAndBroadcast(w, cdc, ctx, m.baseReq, msg)
+	}
+}
+
+// DepositHandlerFn - http request handler to deposit on a proposal
+func DepositHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		proposalID, ok := readProposalID(w, r)
+		if !ok {
+			return
+		}
+		var m depositBody
+		if !readBody(w, r, &m) {
+			return
+		}
+		info, err := kb.Get(m.LocalAccountName)
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		msg := gov.NewMsgDeposit(proposalID, sdk.Address(info.PubKey.Address()), m.Amount)
+		signAndBroadcast(w, cdc, ctx, m.baseReq, msg)
+	}
+}
+
+// VoteHandlerFn - http request handler to vote on a proposal
+func VoteHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		proposalID, ok := readProposalID(w, r)
+		if !ok {
+			return
+		}
+		var m voteBody
+		if !readBody(w, r, &m) {
+			return
+		}
+		info, err := kb.Get(m.LocalAccountName)
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, err)
+			return
+		}
+		option, err := gov.VoteOptionFromString(m.Option)
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err)
+			return
+		}
+
+		msg := gov.NewMsgVote(proposalID, sdk.Address(info.PubKey.Address()), option)
+		signAndBroadcast(w, cdc, ctx, m.baseReq, msg)
+	}
+}
+
+// ProposalsHandlerFn - http request handler to query the proposals, only
+// those with the status of the status query parameter if any
+func ProposalsHandlerFn(queryRoute string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var params gov.QueryProposalsParams
+		if status := r.URL.Query().Get("status"); status != "" {
+			for _, s := range []gov.ProposalStatus{gov.StatusDepositPeriod, gov.StatusVotingPeriod, gov.StatusPassed, gov.StatusRejected} {
+				if s.String() == status {
+					params.Status = s
+				}
+			}
+			if params.Status == 0 {
+				writeErr(w, http.StatusBadRequest, fmt.Errorf("'%s' is not a valid proposal status", status))
+				return
+			}
+		}
+		query(w, cdc, ctx, queryRoute, gov.QueryProposals, params)
+	}
+}
+
+// ProposalQueryHandlerFn - http request handler to query the route about a single proposal
+func ProposalQueryHandlerFn(queryRoute, route string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		proposalID, ok := readProposalID(w, r)
+		if !ok {
+			return
+		}
+		query(w, cdc, ctx, queryRoute, route, gov.QueryProposalParams{ProposalID: proposalID})
+	}
+}
+
+// QueryHandlerFn - http request handler to query a route without params
+func QueryHandlerFn(queryRoute, route string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query(w, cdc, ctx, queryRoute, route, struct{}{})
+	}
+}
+
+//_______________________________________________________________________
+
+func query(w http.ResponseWriter, cdc *wire.Codec, ctx context.CoreContext, queryRoute, route string, params interface{}) {
+	data, err := cdc.MarshalJSON(params)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	res, err := ctx.QueryCustom(queryRoute, route, data)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, fmt.Errorf("Couldn't query %s. Error: %s", route, err.Error()))
+		return
+	}
+	w.Write(res)
+}
+
+func readProposalID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	proposalID, err := strconv.ParseInt(mux.Vars(r)["proposalID"], 10, 64)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return 0, false
+	}
+	return proposalID, true
+}
+
+func readBody(w http.ResponseWriter, r *http.Request, m interface{}) bool {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return false
+	}
+	err = json.Unmarshal(body, m)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+// return the unsigned tx to sign offline, or sign and broadcast it
+func signAndBroadcast(w http.ResponseWriter, cdc *wire.Codec, ctx context.CoreContext, req baseReq, msg sdk.Msg) {
+	if err := msg.ValidateBasic(); err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx = ctx.WithSequence(req.Sequence)
+	if req.GenerateOnly {
+		signMsg, err := ctx.BuildSignMsg([]sdk.Msg{msg})
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err)
+			return
+		}
+		output, err := wire.MarshalJSONIndent(cdc, signMsg)
+		if err != nil {
+			writeErr(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Write(output)
+		return
+	}
+
+	txBytes, err := ctx.SignAndBuild(req.LocalAccountName, req.Password, []sdk.Msg{msg}, cdc)
+	if err != nil {
+		writeErr(w, http.StatusUnauthorized, err)
+		return
+	}
+	res, err := ctx.BroadcastTx(txBytes)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	output, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(output)
+}
+
+func writeErr(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	w.Write([]byte(err.Error()))
+}
@@ -0,0 +1,110 @@
+package gov
+
+import (
+	"fmt"
+
+	sdk "inschain-tendermint/types"
+)
+
+// Governance errors reserve 800 ~ 899.
+const (
+	DefaultCodespace sdk.CodespaceType = 9
+
+	CodeUnknownProposal         sdk.CodeType = 801
+	CodeInactiveProposal        sdk.CodeType = 802
+	CodeAlreadyActiveProposal   sdk.CodeType = 803
+	CodeAlreadyFinishedProposal sdk.CodeType = 804
+	CodeInvalidTitle            sdk.CodeType = 805
+	CodeInvalidDescription      sdk.CodeType = 806
+	CodeInvalidProposalType     sdk.CodeType = 807
+	CodeInvalidVote             sdk.CodeType = 808
+	CodeInvalidDeposit          sdk.CodeType = 809
+	CodeInvalidParamChange      sdk.CodeType = 810
+	CodeInvalidUpgrade          sdk.CodeType = 811
+	CodeInvalidParams           sdk.CodeType = 812
+	CodeUnknownRequest          sdk.CodeType = sdk.CodeUnknownRequest
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeUnknownProposal:
+		return "Unknown proposal"
+	case CodeInactiveProposal:
+		return "Proposal is not in its voting period"
+	case CodeAlreadyActiveProposal:
+		return "Proposal is already in its voting period"
+	case CodeAlreadyFinishedProposal:
+		return "Proposal voting is already finished"
+	case CodeInvalidTitle:
+		return "Invalid proposal title"
+	case CodeInvalidDescription:
+		return "Invalid proposal description"
+	case CodeInvalidProposalType:
+		return "Invalid proposal type"
+	case CodeInvalidVote:
+		return "Invalid vote option"
+	case CodeInvalidDeposit:
+		return "Invalid deposit"
+	case CodeInvalidParamChange:
+		return "Invalid parameter change"
+	case CodeInvalidUpgrade:
+		return "Invalid software upgrade"
+	case CodeInvalidParams:
+		return "Invalid governance params"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// nolint
+func ErrUnknownProposal(codespace sdk.CodespaceType, proposalID int64) sdk.Error {
+	return newError(codespace, CodeUnknownProposal, fmt.Sprintf("Unknown proposal %d", proposalID))
+}
+func ErrInactiveProposal(codespace sdk.CodespaceType, proposalID int64) sdk.Error {
+	return newError(codespace, CodeInactiveProposal, fmt.Sprintf("Proposal %d is not in its voting period", proposalID))
+}
+func ErrAlreadyActiveProposal(codespace sdk.CodespaceType, proposalID int64) sdk.Error {
+	return newError(codespace, CodeAlreadyActiveProposal, fmt.Sprintf("Proposal %d is already in its voting period", proposalID))
+}
+func ErrAlreadyFinishedProposal(codespace sdk.CodespaceType, proposalID int64) sdk.Error {
+	return newError(codespace, CodeAlreadyFinishedProposal, fmt.Sprintf("Proposal %d voting is already finished", proposalID))
+}
+func ErrInvalidTitle(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidTitle, "Proposal title cannot be blank")
+}
+func ErrInvalidDescription(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidDescription, "Proposal description cannot be blank")
+}
+func ErrInvalidProposalType(codespace sdk.CodespaceType, proposalType string) sdk.Error {
+	return newError(codespace, CodeInvalidProposalType, fmt.Sprintf("Proposal type '%s' is not valid", proposalType))
+}
+func ErrInvalidVote(codespace sdk.CodespaceType, option string) sdk.Error {
+	return newError(codespace, CodeInvalidVote, fmt.Sprintf("'%s' is not a valid voting option", option))
+}
+func ErrInvalidDeposit(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidDeposit, msg)
+}
+func ErrInvalidParamChange(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidParamChange, msg)
+}
+func ErrInvalidUpgrade(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidUpgrade, msg)
+}
+func ErrInvalidParams(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidParams, msg)
+}
+
+// -------------------------
+// Helpers
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	msg = msgOrDefaultMsg(msg, code)
+	return sdk.NewError(codespace, code, msg)
+}
+
+func msgOrDefaultMsg(msg string, code sdk.CodeType) string {
+	if msg != "" {
+		return msg
+	}
+	return codeToDefaultMsg(code)
+}
@@ -0,0 +1,146 @@
+package gov
+
+import (
+	"reflect"
+	"strconv"
+
+	sdk "inschain-tendermint/types"
+)
+
+// NewHandler returns a handler for "gov" type messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgSubmitProposal:
+			return handleMsgSubmitProposal(ctx, k, msg)
+		case MsgDeposit:
+			return handleMsgDeposit(ctx, k, msg)
+		case MsgVote:
+			return handleMsgVote(ctx, k, msg)
+		default:
+			errMsg := "Unrecognized gov Msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgSubmitProposal(ctx sdk.Context, k Keeper, msg MsgSubmitProposal) sdk.Result {
+	proposalID, err := k.SubmitProposal(ctx, Proposal{
+		Title:        msg.Title,
+		Description:  msg.Description,
+		ProposalType: msg.ProposalType,
+		ParamChanges: msg.ParamChanges,
+		Upgrade:      msg.Upgrade,
+		Proposer:     msg.Proposer,
+	})
+	if err != nil {
+		return err.Result()
+	}
+	proposalIDStr := []byte(strconv.FormatInt(proposalID, 10))
+	tags := sdk.NewTags(
+		TagAction, ActionSubmitProposal,
+		TagProposalID, proposalIDStr,
+		TagProposer, []byte(msg.Proposer.String()),
+	)
+
+	if !msg.InitialDeposit.IsZero() {
+		activated, err := k.AddDeposit(ctx, proposalID, msg.Proposer, msg.InitialDeposit)
+		if err != nil {
+			return err.Result()
+		}
+		if activated {
+			tags = tags.AppendTag(TagActivated, proposalIDStr)
+		}
+	}
+	return sdk.Result{
+		Data: proposalIDStr,
+		Tags: tags,
+	}
+}
+
+func handleMsgDeposit(ctx sdk.Context, k Keeper, msg MsgDeposit) sdk.Result {
+	activated, err := k.AddDeposit(ctx, msg.ProposalID, msg.Depositor, msg.Amount)
+	if err != nil {
+		return err.Result()
+	}
+	proposalIDStr := []byte(strconv.FormatInt(msg.ProposalID, 10))
+	tags := sdk.NewTags(
+		TagAction, ActionDeposit,
+		TagProposalID, proposalIDStr,
+		TagDepositor, []byte(msg.Depositor.String()),
+	)
+	if activated {
+		tags = tags.AppendTag(TagActivated, proposalIDStr)
+	}
+	return sdk.Result{
+		Tags: tags,
+	}
+}
+
+func handleMsgVote(ctx sdk.Context, k Keeper, msg MsgVote) sdk.Result {
+	err := k.AddVote(ctx, msg.ProposalID, msg.Voter, msg.Option)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: sdk.NewTags(
+			TagAction, ActionVote,
+			TagProposalID, []byte(strconv.FormatInt(msg.ProposalID, 10)),
+			TagVoter, []byte(msg.Voter.String()),
+		),
+	}
+}
+
+//_____________________________________________________________________
+
+// InitGenesis - store genesis parameters
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
+	if err := data.Params.ValidateBasic(k.codespace); err != nil {
+		panic(err)
+	}
+	k.setParams(ctx, data.Params)
+	k.setNextProposalID(ctx, data.StartingProposalID)
+
+	store := ctx.KVStore(k.key)
+	for _, proposal := range data.Proposals {
+		k.setProposal(ctx, proposal)
+		switch proposal.Status {
+		case StatusDepositPeriod:
+			store.Set(getQueueKey(DepositQueueKey, proposal.DepositEndTime, proposal.ProposalID), proposalIDBytes(proposal.ProposalID))
+		case StatusVotingPeriod:
+			store.Set(getQueueKey(VotingQueueKey, proposal.VotingEndTime, proposal.ProposalID), proposalIDBytes(proposal.ProposalID))
+		}
+	}
+	for _, deposit := range data.Deposits {
+		store.Set(GetDepositKey(deposit.ProposalID, deposit.Depositor), k.mustMarshal(deposit))
+	}
+	for _, vote := range data.Votes {
+		store.Set(GetVoteKey(vote.ProposalID, vote.Voter), k.mustMarshal(vote))
+	}
+}
+
+// WriteGenesis - output genesis parameters, proposals, deposits and votes
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	proposals := k.GetProposals(ctx)
+	var deposits []Deposit
+	var votes []Vote
+	for _, proposal := range proposals {
+		deposits = append(deposits, k.GetDeposits(ctx, proposal.ProposalID)...)
+		votes = append(votes, k.GetVotes(ctx, proposal.ProposalID)...)
+	}
+	return GenesisState{
+		StartingProposalID: k.getNextProposalID(ctx),
+		Params:             k.GetParams(ctx),
+		Proposals:          proposals,
+		Deposits:           deposits,
+		Votes:              votes,
+	}
+}
+
+// GenesisEscrow - the coins of the deposits of the genesis, held by gov
+func GenesisEscrow(data GenesisState) (escrow sdk.Coins) {
+	for _, deposit := range data.Deposits {
+		escrow = escrow.Plus(deposit.Amount)
+	}
+	return escrow
+}
@@ -0,0 +1,409 @@
+package gov
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/stake"
+)
+
+var (
+	// Keys for store prefixes
+	ParamsKey         = []byte{0x00} // key for the governance params
+	NextProposalIDKey = []byte{0x01} // key for the id of the next proposal
+	ProposalKey       = []byte{0x02} // prefix for the proposals, by id
+	DepositKey        = []byte{0x03} // prefix for the deposits, by proposal id and depositor
+	VoteKey           = []byte{0x04} // prefix for the votes, by proposal id and voter
+	DepositQueueKey   = []byte{0x05} // prefix for the proposals in their deposit period, by deposit end time
+	VotingQueueKey    = []byte{0x06} // prefix for the proposals in their voting period, by voting end time
+	UpgradeKey        = []byte{0x07} // key for the last software upgrade passed
+)
+
+func proposalIDBytes(proposalID int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(proposalID))
+	return bz
+}
+
+// get the key for the proposal
+func GetProposalKey(proposalID int64) []byte {
+	return append(ProposalKey, proposalIDBytes(proposalID)...)
+}
+
+// get the key prefix for the deposits on the proposal
+func GetDepositsKey(proposalID int64) []byte {
+	return append(DepositKey, proposalIDBytes(proposalID)...)
+}
+
+// get the key for the deposit of the depositor on the proposal
+func GetDepositKey(proposalID int64, depositor sdk.Address) []byte {
+	return append(GetDepositsKey(proposalID), depositor.Bytes()...)
+}
+
+// get the key prefix for the votes on the proposal
+func GetVotesKey(proposalID int64) []byte {
+	return append(VoteKey, proposalIDBytes(proposalID)...)
+}
+
+// get the key for the vote of the voter on the proposal
+func GetVoteKey(proposalID int64, voter sdk.Address) []byte {
+	return append(GetVotesKey(proposalID), voter.Bytes()...)
+}
+
+// get the key of the proposal in the queue, ordered by the unix time the
+// period of the proposal ends at
+func getQueueKey(queueKey []byte, endTime int64, proposalID int64) []byte {
+	timeBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(timeBytes, uint64(endTime))
+	key := append(append([]byte{}, queueKey...), timeBytes...)
+	return append(key, proposalIDBytes(proposalID)...)
+}
+
+// ParamChangeHandler sets a param of a module from its JSON name and value,
+// when a parameter-change proposal passes
+type ParamChangeHandler func(ctx sdk.Context, key, value string) sdk.Error
+
+// Keeper manages the proposals, their deposits and their votes
+type Keeper struct {
+	key                 sdk.StoreKey
+	cdc                 *wire.Codec
+	supplyKeeper        bank.SupplyKeeper
+	stakeKeeper         stake.ViewSlashKeeper
+	paramChangeHandlers map[string]ParamChangeHandler
+	codespace           sdk.CodespaceType
+}
+
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, supplyKeeper bank.SupplyKeeper, sk stake.ViewSlashKeeper, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		key:                 key,
+		cdc:                 cdc,
+		supplyKeeper:        supplyKeeper,
+		stakeKeeper:         sk,
+		paramChangeHandlers: make(map[string]ParamChangeHandler),
+		codespace:           codespace,
+	}
+}
+
+// AddParamChangeHandler lets parameter-change proposals set the params of the module
+func (k Keeper) AddParamChangeHandler(module string, h ParamChangeHandler) Keeper {
+	if _, ok := k.paramChangeHandlers[module]; ok {
+		panic(fmt.Sprintf("param change handler for %s has already been added", module))
+	}
+	k.paramChangeHandlers[module] = h
+	return k
+}
+
+// GetParams returns the governance params
+func (k Keeper) GetParams(ctx sdk.Context) (params Params) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(ParamsKey)
+	if bz == nil {
+		panic("governance params have not been initialized")
+	}
+	k.mustUnmarshal(bz, &params)
+	return params
+}
+
+func (k Keeper) setParams(ctx sdk.Context, params Params) {
+	store := ctx.KVStore(k.key)
+	store.Set(ParamsKey, k.mustMarshal(params))
+}
+
+func (k Keeper) getNextProposalID(ctx sdk.Context) (proposalID int64) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(NextProposalIDKey)
+	if bz == nil {
+		panic("the next proposal id has not been initialized")
+	}
+	k.mustUnmarshal(bz, &proposalID)
+	return proposalID
+}
+
+func (k Keeper) setNextProposalID(ctx sdk.Context, proposalID int64) {
+	store := ctx.KVStore(k.key)
+	store.Set(NextProposalIDKey, k.mustMarshal(proposalID))
+}
+
+//_______________________________________________________________________
+
+// GetProposal returns the proposal with the id
+func (k Keeper) GetProposal(ctx sdk.Context, proposalID int64) (proposal Proposal, found bool) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetProposalKey(proposalID))
+	if bz == nil {
+		return proposal, false
+	}
+	k.mustUnmarshal(bz, &proposal)
+	return proposal, true
+}
+
+// GetProposals returns all the proposals, oldest first
+func (k Keeper) GetProposals(ctx sdk.Context) []Proposal {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(ProposalKey)
+	defer iterator.Close()
+
+	proposals := []Proposal{}
+	for ; iterator.Valid(); iterator.Next() {
+		var proposal Proposal
+		k.mustUnmarshal(iterator.Value(), &proposal)
+		proposals = append(proposals, proposal)
+	}
+	return proposals
+}
+
+func (k Keeper) setProposal(ctx sdk.Context, proposal Proposal) {
+	store := ctx.KVStore(k.key)
+	store.Set(GetProposalKey(proposal.ProposalID), k.mustMarshal(proposal))
+}
+
+// SubmitProposal stores a new proposal in its deposit period, returning its id
+func (k Keeper) SubmitProposal(ctx sdk.Context, proposal Proposal) (int64, sdk.Error) {
+	switch proposal.ProposalType {
+	case ProposalTypeText:
+	case ProposalTypeParameterChange:
+		// try the changes on a throwaway store, so that proposals which
+		// could never be applied are refused right away
+		cacheCtx, _ := ctx.CacheContext()
+		err := k.applyParamChanges(cacheCtx, proposal.ParamChanges)
+		if err != nil {
+			return 0, err
+		}
+	case ProposalTypeSoftwareUpgrade:
+		if proposal.Upgrade.Height <= ctx.BlockHeight() {
+			return 0, ErrInvalidUpgrade(k.codespace, "the upgrade height has already passed")
+		}
+	default:
+		return 0, ErrInvalidProposalType(k.codespace, proposal.ProposalType.String())
+	}
+
+	now := ctx.BlockHeader().Time
+	proposal.ProposalID = k.getNextProposalID(ctx)
+	proposal.Status = StatusDepositPeriod
+	proposal.TotalDeposit = sdk.Coins{}
+	proposal.TallyResult = EmptyTallyResult()
+	proposal.SubmitTime = now
+	proposal.DepositEndTime = now + k.GetParams(ctx).MaxDepositPeriod
+	k.setProposal(ctx, proposal)
+	k.setNextProposalID(ctx, proposal.ProposalID+1)
+
+	store := ctx.KVStore(k.key)
+	store.Set(getQueueKey(DepositQueueKey, proposal.DepositEndTime, proposal.ProposalID), proposalIDBytes(proposal.ProposalID))
+	return proposal.ProposalID, nil
+}
+
+// start the voting period of the proposal
+func (k Keeper) activateVotingPeriod(ctx sdk.Context, proposal Proposal) Proposal {
+	store := ctx.KVStore(k.key)
+	store.Delete(getQueueKey(DepositQueueKey, proposal.DepositEndTime, proposal.ProposalID))
+
+	now := ctx.BlockHeader().Time
+	proposal.Status = StatusVotingPeriod
+	proposal.VotingStartTime = now
+	proposal.VotingEndTime = now + k.GetParams(ctx).VotingPeriod
+	store.Set(getQueueKey(VotingQueueKey, proposal.VotingEndTime, proposal.ProposalID), proposalIDBytes(proposal.ProposalID))
+	return proposal
+}
+
+//_______________________________________________________________________
+
+// GetDeposit returns the deposit of the depositor on the proposal
+func (k Keeper) GetDeposit(ctx sdk.Context, proposalID int64, depositor sdk.Address) (deposit Deposit, found bool) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetDepositKey(proposalID, depositor))
+	if bz == nil {
+		return deposit, false
+	}
+	k.mustUnmarshal(bz, &deposit)
+	return deposit, true
+}
+
+// GetDeposits returns the deposits on the proposal
+func (k Keeper) GetDeposits(ctx sdk.Context, proposalID int64) []Deposit {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(GetDepositsKey(proposalID))
+	defer iterator.Close()
+
+	deposits := []Deposit{}
+	for ; iterator.Valid(); iterator.Next() {
+		var deposit Deposit
+		k.mustUnmarshal(iterator.Value(), &deposit)
+		deposits = append(deposits, deposit)
+	}
+	return deposits
+}
+
+// AddDeposit takes the coins of the depositor for the proposal, which enters
+// its voting period once the deposits reach the min deposit
+func (k Keeper) AddDeposit(ctx sdk.Context, proposalID int64, depositor sdk.Address,
+	amount sdk.Coins) (activated bool, err sdk.Error) {
+
+	proposal, found := k.GetProposal(ctx, proposalID)
+	if !found {
+		return false, ErrUnknownProposal(k.codespace, proposalID)
+	}
+	switch proposal.Status {
+	case StatusDepositPeriod:
+	case StatusVotingPeriod:
+		return false, ErrAlreadyActiveProposal(k.codespace, proposalID)
+	default:
+		return false, ErrAlreadyFinishedProposal(k.codespace, proposalID)
+	}
+
+	_, err = k.supplyKeeper.EscrowCoins(ctx, MsgType, depositor, amount)
+	if err != nil {
+		return false, err
+	}
+
+	deposit, found := k.GetDeposit(ctx, proposalID, depositor)
+	if !found {
+		deposit = Deposit{ProposalID: proposalID, Depositor: depositor}
+	}
+	deposit.Amount = deposit.Amount.Plus(amount)
+	store := ctx.KVStore(k.key)
+	store.Set(GetDepositKey(proposalID, depositor), k.mustMarshal(deposit))
+
+	proposal.TotalDeposit = proposal.TotalDeposit.Plus(amount)
+	if proposal.TotalDeposit.IsGTE(k.GetParams(ctx).MinDeposit) {
+		proposal = k.activateVotingPeriod(ctx, proposal)
+		activated = true
+	}
+	k.setProposal(ctx, proposal)
+	return activated, nil
+}
+
+// return the deposits on the proposal to their depositors
+func (k Keeper) refundDeposits(ctx sdk.Context, proposalID int64) {
+	for _, deposit := range k.GetDeposits(ctx, proposalID) {
+		_, err := k.supplyKeeper.RefundCoins(ctx, MsgType, deposit.Depositor, deposit.Amount)
+		if err != nil {
+			panic(err)
+		}
+	}
+	k.deleteDeposits(ctx, proposalID)
+}
+
+// burn the deposits on the proposal
+func (k Keeper) burnDeposits(ctx sdk.Context, proposalID int64) {
+	for _, deposit := range k.GetDeposits(ctx, proposalID) {
+		err := k.supplyKeeper.BurnEscrow(ctx, MsgType, deposit.Amount)
+		if err != nil {
+			panic(err)
+		}
+	}
+	k.deleteDeposits(ctx, proposalID)
+}
+
+// remove the deposits on the proposal
+func (k Keeper) deleteDeposits(ctx sdk.Context, proposalID int64) {
+	store := ctx.KVStore(k.key)
+	for _, deposit := range k.GetDeposits(ctx, proposalID) {
+		store.Delete(GetDepositKey(proposalID, deposit.Depositor))
+	}
+}
+
+//_______________________________________________________________________
+
+// GetVote returns the vote of the voter on the proposal
+func (k Keeper) GetVote(ctx sdk.Context, proposalID int64, voter sdk.Address) (vote Vote, found bool) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetVoteKey(proposalID, voter))
+	if bz == nil {
+		return vote, false
+	}
+	k.mustUnmarshal(bz, &vote)
+	return vote, true
+}
+
+// GetVotes returns the votes on the proposal
+func (k Keeper) GetVotes(ctx sdk.Context, proposalID int64) []Vote {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(GetVotesKey(proposalID))
+	defer iterator.Close()
+
+	votes := []Vote{}
+	for ; iterator.Valid(); iterator.Next() {
+		var vote Vote
+		k.mustUnmarshal(iterator.Value(), &vote)
+		votes = append(votes, vote)
+	}
+	return votes
+}
+
+// AddVote records the vote of the voter on a proposal in its voting period,
+// replacing the previous vote of the voter if any
+func (k Keeper) AddVote(ctx sdk.Context, proposalID int64, voter sdk.Address, option VoteOption) sdk.Error {
+	proposal, found := k.GetProposal(ctx, proposalID)
+	if !found {
+		return ErrUnknownProposal(k.codespace, proposalID)
+	}
+	if proposal.Status != StatusVotingPeriod {
+		return ErrInactiveProposal(k.codespace, proposalID)
+	}
+	if !validVoteOption(option) {
+		return ErrInvalidVote(k.codespace, option.String())
+	}
+
+	vote := Vote{
+		ProposalID: proposalID,
+		Voter:      voter,
+		Option:     option,
+	}
+	store := ctx.KVStore(k.key)
+	store.Set(GetVoteKey(proposalID, voter), k.mustMarshal(vote))
+	return nil
+}
+
+//_______________________________________________________________________
+
+// GetUpgrade returns the last software upgrade passed by governance
+func (k Keeper) GetUpgrade(ctx sdk.Context) (upgrade SoftwareUpgrade, found bool) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(UpgradeKey)
+	if bz == nil {
+		return upgrade, false
+	}
+	k.mustUnmarshal(bz, &upgrade)
+	return upgrade, true
+}
+
+func (k Keeper) setUpgrade(ctx sdk.Context, upgrade SoftwareUpgrade) {
+	store := ctx.KVStore(k.key)
+	store.Set(UpgradeKey, k.mustMarshal(upgrade))
+}
+
+// apply the changes of a parameter-change proposal, through the param
+// change handlers of their modules
+func (k Keeper) applyParamChanges(ctx sdk.Context, changes []ParamChange) sdk.Error {
+	for _, change := range changes {
+		h, ok := k.paramChangeHandlers[change.Module]
+		if !ok {
+			return ErrInvalidParamChange(k.codespace, fmt.Sprintf("the params of module %s cannot be changed", change.Module))
+		}
+		err := h(ctx, change.Key, change.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//_______________________________________________________________________
+
+func (k Keeper) mustMarshal(o interface{}) []byte {
+	bz, err := k.cdc.MarshalJSON(o)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+func (k Keeper) mustUnmarshal(bz []byte, ptr interface{}) {
+	err := k.cdc.UnmarshalJSON(bz, ptr)
+	if err != nil {
+		panic(err)
+	}
+}
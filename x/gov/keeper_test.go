@@ -0,0 +1,291 @@
+package gov
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+	crypto "github.com/tendermint/go-crypto"
+	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/tendermint/tmlibs/log"
+
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/stake"
+)
+
+var (
+	pks = []crypto.PubKey{
+		crypto.GenPrivKeyEd25519().PubKey(),
+		crypto.GenPrivKeyEd25519().PubKey(),
+		crypto.GenPrivKeyEd25519().PubKey(),
+	}
+	val1      = sdk.Address(pks[0].Address())
+	val2      = sdk.Address(pks[1].Address())
+	delegator = sdk.Address(pks[2].Address())
+)
+
+func testParams() Params {
+	return Params{
+		MinDeposit:       sdk.Coins{{"steak", 10}},
+		MaxDepositPeriod: 100,
+		VotingPeriod:     100,
+		Quorum:           sdk.NewRat(1, 3),
+		Threshold:        sdk.NewRat(1, 2),
+		Veto:             sdk.NewRat(1, 3),
+	}
+}
+
+// create a governance keeper with two validators of 70 and 30 steak, 30 of
+// the 70 being delegated by the delegator. Every account keeps 100 steak.
+func createTestInput(t *testing.T) (sdk.Context, bank.Keeper, stake.Keeper, Keeper) {
+	db := dbm.NewMemDB()
+	keyAcc := sdk.NewKVStoreKey("acc")
+	keyStake := sdk.NewKVStoreKey("stake")
+	keyGov := sdk.NewKVStoreKey("gov")
+	keySupply := sdk.NewKVStoreKey("supply")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keySupply, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyStake, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyGov, sdk.StoreTypeIAVL, db)
+	err := ms.LoadLatestVersion()
+	require.Nil(t, err)
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "foochainid", Time: 1000}, false, nil, log.NewNopLogger())
+	cdc := wire.NewCodec()
+	auth.RegisterWire(cdc)
+	RegisterWire(cdc)
+	wire.RegisterCrypto(cdc)
+
+	accountMapper := auth.NewAccountMapper(cdc, keyAcc, &auth.BaseAccount{})
+	ck := bank.NewKeeper(accountMapper)
+	supplyKeeper := bank.NewSupplyKeeper(cdc, keySupply, ck, bank.DefaultCodespace)
+	sk := stake.NewKeeper(cdc, keyStake, supplyKeeper, stake.DefaultCodespace)
+	stakeGenesis := stake.GetDefaultGenesisState()
+	stake.InitGenesis(ctx, sk, stakeGenesis)
+	keeper := NewKeeper(cdc, keyGov, supplyKeeper, stake.NewViewSlashKeeper(sk), DefaultCodespace).
+		AddParamChangeHandler("stake", sk.SetParam)
+	InitGenesis(ctx, keeper, GenesisState{StartingProposalID: 1, Params: testParams()})
+
+	for _, addr := range []sdk.Address{val1, val2, delegator} {
+		_, sdkErr := supplyKeeper.Mint(ctx, addr, sdk.Coins{{"steak", 200}})
+		require.Nil(t, sdkErr)
+	}
+	stakeHandler := stake.NewHandler(sk)
+	zeroCommission := stake.NewCommission(sdk.ZeroRat(), sdk.ZeroRat(), sdk.ZeroRat())
+	msgs := []sdk.Msg{
+		stake.NewMsgDeclareCandidacy(val1, pks[0], sdk.Coin{"steak", 40}, stake.NewDescription("val1", "", "", ""), zeroCommission),
+		stake.NewMsgDeclareCandidacy(val2, pks[1], sdk.Coin{"steak", 30}, stake.NewDescription("val2", "", "", ""), zeroCommission),
+		stake.NewMsgDelegate(delegator, val1, sdk.Coin{"steak", 30}),
+	}
+	for _, msg := range msgs {
+		res := stakeHandler(ctx, msg)
+		require.True(t, res.IsOK(), "%v", res)
+	}
+	// level the balances, which makes the deposits easy to follow
+	_, sdkErr := supplyKeeper.Burn(ctx, val1, sdk.Coins{{"steak", 60}})
+	require.Nil(t, sdkErr)
+	_, sdkErr = supplyKeeper.Burn(ctx, val2, sdk.Coins{{"steak", 70}})
+	require.Nil(t, sdkErr)
+	_, sdkErr = supplyKeeper.Burn(ctx, delegator, sdk.Coins{{"steak", 70}})
+	require.Nil(t, sdkErr)
+	return ctx, ck, sk, keeper
+}
+
+func endBlock(ctx sdk.Context, keeper Keeper, time int64) sdk.Context {
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time})
+	EndBlocker(ctx, keeper)
+	return ctx
+}
+
+// submit a text proposal from the delegator, with the min deposit
+func submitVotingProposal(t *testing.T, ctx sdk.Context, keeper Keeper) int64 {
+	msg := NewMsgSubmitProposal("title", "description", delegator, sdk.Coins{{"steak", 10}})
+	res := NewHandler(keeper)(ctx, msg)
+	require.True(t, res.IsOK(), "%v", res)
+	proposal := keeper.GetProposals(ctx)[len(keeper.GetProposals(ctx))-1]
+	require.Equal(t, StatusVotingPeriod, proposal.Status)
+	return proposal.ProposalID
+}
+
+func vote(t *testing.T, ctx sdk.Context, keeper Keeper, proposalID int64, voter sdk.Address, option VoteOption) {
+	res := NewHandler(keeper)(ctx, NewMsgVote(proposalID, voter, option))
+	require.True(t, res.IsOK(), "%v", res)
+}
+
+func TestDepositPeriod(t *testing.T) {
+	ctx, ck, _, keeper := createTestInput(t)
+	handler := NewHandler(keeper)
+
+	res := handler(ctx, NewMsgSubmitProposal("title", "description", val1, sdk.Coins{{"steak", 4}}))
+	require.True(t, res.IsOK(), "%v", res)
+	assert.Equal(t, "1", string(res.Data))
+	proposal, found := keeper.GetProposal(ctx, 1)
+	require.True(t, found)
+	assert.Equal(t, StatusDepositPeriod, proposal.Status)
+	assert.Equal(t, int64(1100), proposal.DepositEndTime)
+
+	// no votes before the voting period
+	res = handler(ctx, NewMsgVote(1, val1, OptionYes))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInactiveProposal), res.Code)
+
+	// reaching the min deposit starts the voting period
+	res = handler(ctx, NewMsgDeposit(1, val2, sdk.Coins{{"steak", 6}}))
+	require.True(t, res.IsOK(), "%v", res)
+	proposal, _ = keeper.GetProposal(ctx, 1)
+	assert.Equal(t, StatusVotingPeriod, proposal.Status)
+	assert.Equal(t, sdk.Coins{{"steak", 10}}, proposal.TotalDeposit)
+	assert.Equal(t, int64(1100), proposal.VotingEndTime)
+	assert.Equal(t, 2, len(keeper.GetDeposits(ctx, 1)))
+	assert.Equal(t, int64(94), ck.GetCoins(ctx, val2).AmountOf("steak"))
+
+	res = handler(ctx, NewMsgDeposit(1, val2, sdk.Coins{{"steak", 6}}))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeAlreadyActiveProposal), res.Code)
+	res = handler(ctx, NewMsgDeposit(2, val2, sdk.Coins{{"steak", 6}}))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeUnknownProposal), res.Code)
+}
+
+func TestDepositPeriodExpires(t *testing.T) {
+	ctx, ck, _, keeper := createTestInput(t)
+
+	res := NewHandler(keeper)(ctx, NewMsgSubmitProposal("title", "description", val1, sdk.Coins{{"steak", 4}}))
+	require.True(t, res.IsOK(), "%v", res)
+
+	ctx = endBlock(ctx, keeper, 1099)
+	proposal, _ := keeper.GetProposal(ctx, 1)
+	assert.Equal(t, StatusDepositPeriod, proposal.Status)
+
+	// the deposits of a proposal which never got to a vote are burned
+	ctx = endBlock(ctx, keeper, 1100)
+	proposal, _ = keeper.GetProposal(ctx, 1)
+	assert.Equal(t, StatusRejected, proposal.Status)
+	assert.Equal(t, 0, len(keeper.GetDeposits(ctx, 1)))
+	assert.Equal(t, int64(96), ck.GetCoins(ctx, val1).AmountOf("steak"))
+
+	res = NewHandler(keeper)(ctx, NewMsgDeposit(1, val2, sdk.Coins{{"steak", 6}}))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeAlreadyFinishedProposal), res.Code)
+}
+
+func TestTallyPasses(t *testing.T) {
+	ctx, ck, _, keeper := createTestInput(t)
+	proposalID := submitVotingProposal(t, ctx, keeper)
+	vote(t, ctx, keeper, proposalID, val1, OptionYes)
+	vote(t, ctx, keeper, proposalID, val2, OptionNo)
+
+	// the delegator inherits the yes of its validator
+	ctx = endBlock(ctx, keeper, 1100)
+	proposal, _ := keeper.GetProposal(ctx, proposalID)
+	assert.Equal(t, StatusPassed, proposal.Status)
+	assert.True(t, proposal.TallyResult.Yes.Equal(sdk.NewRat(70)))
+	assert.True(t, proposal.TallyResult.No.Equal(sdk.NewRat(30)))
+	assert.Equal(t, int64(100), ck.GetCoins(ctx, delegator).AmountOf("steak"))
+}
+
+func TestTallyDelegatorOverride(t *testing.T) {
+	ctx, _, _, keeper := createTestInput(t)
+	proposalID := submitVotingProposal(t, ctx, keeper)
+	vote(t, ctx, keeper, proposalID, val1, OptionYes)
+	vote(t, ctx, keeper, proposalID, val2, OptionNo)
+	vote(t, ctx, keeper, proposalID, delegator, OptionNo)
+
+	ctx = endBlock(ctx, keeper, 1100)
+	proposal, _ := keeper.GetProposal(ctx, proposalID)
+	assert.Equal(t, StatusRejected, proposal.Status)
+	assert.True(t, proposal.TallyResult.Yes.Equal(sdk.NewRat(40)))
+	assert.True(t, proposal.TallyResult.No.Equal(sdk.NewRat(60)))
+}
+
+func TestTallyVeto(t *testing.T) {
+	ctx, ck, _, keeper := createTestInput(t)
+	proposalID := submitVotingProposal(t, ctx, keeper)
+	vote(t, ctx, keeper, proposalID, val1, OptionYes)
+	vote(t, ctx, keeper, proposalID, val2, OptionNoWithVeto)
+	vote(t, ctx, keeper, proposalID, delegator, OptionNoWithVeto)
+
+	// a vetoed proposal burns its deposits
+	ctx = endBlock(ctx, keeper, 1100)
+	proposal, _ := keeper.GetProposal(ctx, proposalID)
+	assert.Equal(t, StatusRejected, proposal.Status)
+	assert.True(t, proposal.TallyResult.NoWithVeto.Equal(sdk.NewRat(60)))
+	assert.Equal(t, int64(90), ck.GetCoins(ctx, delegator).AmountOf("steak"))
+}
+
+func TestTallyQuorum(t *testing.T) {
+	ctx, ck, _, keeper := createTestInput(t)
+	proposalID := submitVotingProposal(t, ctx, keeper)
+	vote(t, ctx, keeper, proposalID, val2, OptionYes)
+
+	// 30 of the 100 bonded power is short of the quorum
+	ctx = endBlock(ctx, keeper, 1100)
+	proposal, _ := keeper.GetProposal(ctx, proposalID)
+	assert.Equal(t, StatusRejected, proposal.Status)
+	assert.Equal(t, int64(100), ck.GetCoins(ctx, delegator).AmountOf("steak"))
+}
+
+func TestParamChangeProposal(t *testing.T) {
+	ctx, _, sk, keeper := createTestInput(t)
+	handler := NewHandler(keeper)
+
+	// changes which could never be applied are refused at submission
+	for _, change := range []ParamChange{
+		{"stake", "bond_denom", `"atom"`},
+		{"stake", "unknown", `1`},
+		{"bank", "max_validators", `5`},
+	} {
+		msg := NewMsgSubmitParamChangeProposal("title", "description", []ParamChange{change}, val1, sdk.Coins{{"steak", 10}})
+		res := handler(ctx, msg)
+		assert.False(t, res.IsOK(), "%v", change)
+	}
+
+	changes := []ParamChange{{"stake", "max_validators", `5`}}
+	msg := NewMsgSubmitParamChangeProposal("title", "description", changes, val1, sdk.Coins{{"steak", 10}})
+	res := handler(ctx, msg)
+	require.True(t, res.IsOK(), "%v", res)
+	vote(t, ctx, keeper, 1, val1, OptionYes)
+	assert.Equal(t, stake.GetDefaultGenesisState().Params.MaxValidators, sk.GetParams(ctx).MaxValidators)
+
+	ctx = endBlock(ctx, keeper, 1100)
+	proposal, _ := keeper.GetProposal(ctx, 1)
+	assert.Equal(t, StatusPassed, proposal.Status)
+	assert.Equal(t, uint16(5), sk.GetParams(ctx).MaxValidators)
+}
+
+func TestSoftwareUpgradeProposal(t *testing.T) {
+	ctx, _, _, keeper := createTestInput(t)
+	ctx = ctx.WithBlockHeight(10)
+
+	upgrade := SoftwareUpgrade{Name: "v2", Height: 5}
+	msg := NewMsgSubmitSoftwareUpgradeProposal("title", "description", upgrade, val1, sdk.Coins{{"steak", 10}})
+	res := NewHandler(keeper)(ctx, msg)
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidUpgrade), res.Code)
+
+	msg.Upgrade.Height = 20
+	res = NewHandler(keeper)(ctx, msg)
+	require.True(t, res.IsOK(), "%v", res)
+	vote(t, ctx, keeper, 1, val1, OptionYes)
+	_, found := keeper.GetUpgrade(ctx)
+	assert.False(t, found)
+
+	ctx = endBlock(ctx, keeper, 1100)
+	got, found := keeper.GetUpgrade(ctx)
+	require.True(t, found)
+	assert.Equal(t, msg.Upgrade, got)
+}
+
+func TestParamsValidateBasic(t *testing.T) {
+	assert.Nil(t, DefaultParams().ValidateBasic(DefaultCodespace))
+
+	params := testParams()
+	params.VotingPeriod = 0
+	assert.NotNil(t, params.ValidateBasic(DefaultCodespace))
+
+	params = testParams()
+	params.Quorum = sdk.NewRat(3, 2)
+	assert.NotNil(t, params.ValidateBasic(DefaultCodespace))
+}
@@ -0,0 +1,206 @@
+package gov
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	sdk "inschain-tendermint/types"
+)
+
+// name to identify the governance msgs
+const MsgType = "gov"
+
+var _, _, _ sdk.Msg = MsgSubmitProposal{}, MsgDeposit{}, MsgVote{}
+
+//_______________________________________________________________________
+
+// MsgSubmitProposal - submit a proposal, with a first deposit on it
+type MsgSubmitProposal struct {
+	Title          string          `json:"title"`
+	Description    string          `json:"description"`
+	ProposalType   ProposalKind    `json:"proposal_type"`
+	ParamChanges   []ParamChange   `json:"param_changes,omitempty"`
+	Upgrade        SoftwareUpgrade `json:"upgrade"`
+	Proposer       sdk.Address     `json:"proposer"`
+	InitialDeposit sdk.Coins       `json:"initial_deposit"`
+}
+
+// NewMsgSubmitProposal - construct the msg submitting a text proposal
+func NewMsgSubmitProposal(title, description string, proposer sdk.Address, initialDeposit sdk.Coins) MsgSubmitProposal {
+	return MsgSubmitProposal{
+		Title:          title,
+		Description:    description,
+		ProposalType:   ProposalTypeText,
+		Proposer:       proposer,
+		InitialDeposit: initialDeposit,
+	}
+}
+
+// NewMsgSubmitParamChangeProposal - construct the msg submitting a parameter-change proposal
+func NewMsgSubmitParamChangeProposal(title, description string, changes []ParamChange,
+	proposer sdk.Address, initialDeposit sdk.Coins) MsgSubmitProposal {
+	msg := NewMsgSubmitProposal(title, description, proposer, initialDeposit)
+	msg.ProposalType = ProposalTypeParameterChange
+	msg.ParamChanges = changes
+	return msg
+}
+
+// NewMsgSubmitSoftwareUpgradeProposal - construct the msg submitting a software-upgrade proposal
+func NewMsgSubmitSoftwareUpgradeProposal(title, description string, upgrade SoftwareUpgrade,
+	proposer sdk.Address, initialDeposit sdk.Coins) MsgSubmitProposal {
+	msg := NewMsgSubmitProposal(title, description, proposer, initialDeposit)
+	msg.ProposalType = ProposalTypeSoftwareUpgrade
+	msg.Upgrade = upgrade
+	return msg
+}
+
+// Implements Msg.
+func (msg MsgSubmitProposal) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgSubmitProposal) ValidateBasic() sdk.Error {
+	if len(strings.TrimSpace(msg.Title)) == 0 {
+		return ErrInvalidTitle(DefaultCodespace)
+	}
+	if len(strings.TrimSpace(msg.Description)) == 0 {
+		return ErrInvalidDescription(DefaultCodespace)
+	}
+	if len(msg.Proposer) == 0 {
+		return sdk.ErrInvalidAddress("proposer address is missing")
+	}
+	if !msg.InitialDeposit.IsValid() {
+		return ErrInvalidDeposit(DefaultCodespace, fmt.Sprintf("initial deposit %v is not valid", msg.InitialDeposit))
+	}
+	switch msg.ProposalType {
+	case ProposalTypeText:
+	case ProposalTypeParameterChange:
+		if len(msg.ParamChanges) == 0 {
+			return ErrInvalidParamChange(DefaultCodespace, "a parameter-change proposal must change some params")
+		}
+		for _, change := range msg.ParamChanges {
+			if len(change.Module) == 0 || len(change.Key) == 0 || len(change.Value) == 0 {
+				return ErrInvalidParamChange(DefaultCodespace, "the module, key and value of a param change are required")
+			}
+		}
+	case ProposalTypeSoftwareUpgrade:
+		if len(strings.TrimSpace(msg.Upgrade.Name)) == 0 || msg.Upgrade.Height <= 0 {
+			return ErrInvalidUpgrade(DefaultCodespace, "a software upgrade needs a name and a positive height")
+		}
+	default:
+		return ErrInvalidProposalType(DefaultCodespace, msg.ProposalType.String())
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgSubmitProposal) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgSubmitProposal) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Proposer}
+}
+
+//_______________________________________________________________________
+
+// MsgDeposit - add coins to the deposit of a proposal in its deposit period
+type MsgDeposit struct {
+	ProposalID int64       `json:"proposal_id"`
+	Depositor  sdk.Address `json:"depositor"`
+	Amount     sdk.Coins   `json:"amount"`
+}
+
+// NewMsgDeposit - construct the msg depositing on the proposal
+func NewMsgDeposit(proposalID int64, depositor sdk.Address, amount sdk.Coins) MsgDeposit {
+	return MsgDeposit{
+		ProposalID: proposalID,
+		Depositor:  depositor,
+		Amount:     amount,
+	}
+}
+
+// Implements Msg.
+func (msg MsgDeposit) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgDeposit) ValidateBasic() sdk.Error {
+	if len(msg.Depositor) == 0 {
+		return sdk.ErrInvalidAddress("depositor address is missing")
+	}
+	if !msg.Amount.IsValid() || !msg.Amount.IsPositive() {
+		return ErrInvalidDeposit(DefaultCodespace, fmt.Sprintf("deposit %v must be positive", msg.Amount))
+	}
+	if msg.ProposalID <= 0 {
+		return ErrUnknownProposal(DefaultCodespace, msg.ProposalID)
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgDeposit) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgDeposit) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Depositor}
+}
+
+//_______________________________________________________________________
+
+// MsgVote - vote on a proposal in its voting period
+type MsgVote struct {
+	ProposalID int64       `json:"proposal_id"`
+	Voter      sdk.Address `json:"voter"`
+	Option     VoteOption  `json:"option"`
+}
+
+// NewMsgVote - construct the msg voting on the proposal
+func NewMsgVote(proposalID int64, voter sdk.Address, option VoteOption) MsgVote {
+	return MsgVote{
+		ProposalID: proposalID,
+		Voter:      voter,
+		Option:     option,
+	}
+}
+
+// Implements Msg.
+func (msg MsgVote) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgVote) ValidateBasic() sdk.Error {
+	if len(msg.Voter) == 0 {
+		return sdk.ErrInvalidAddress("voter address is missing")
+	}
+	if !validVoteOption(msg.Option) {
+		return ErrInvalidVote(DefaultCodespace, msg.Option.String())
+	}
+	if msg.ProposalID <= 0 {
+		return ErrUnknownProposal(DefaultCodespace, msg.ProposalID)
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgVote) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgVote) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Voter}
+}
@@ -0,0 +1,133 @@
+package gov
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+// query endpoints supported by the governance querier, at /custom/gov/<route>
+const (
+	QueryParams    = "params"
+	QueryProposal  = "proposal"
+	QueryProposals = "proposals"
+	QueryDeposits  = "deposits"
+	QueryVotes     = "votes"
+	QueryTally     = "tally"
+	QueryUpgrade   = "upgrade"
+)
+
+// parameters of the queries about a single proposal
+type QueryProposalParams struct {
+	ProposalID int64 `json:"proposal_id"`
+}
+
+// parameters of the proposals query, a zero status returns all the proposals
+type QueryProposalsParams struct {
+	Status ProposalStatus `json:"status"`
+}
+
+// NewQuerier returns the querier of the governance module
+func NewQuerier(k Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("No gov query endpoint specified")
+		}
+		switch path[0] {
+		case QueryParams:
+			return k.marshalQueryResult(k.GetParams(ctx))
+		case QueryProposal:
+			return queryProposal(ctx, req, k)
+		case QueryProposals:
+			return queryProposals(ctx, req, k)
+		case QueryDeposits:
+			return queryDeposits(ctx, req, k)
+		case QueryVotes:
+			return queryVotes(ctx, req, k)
+		case QueryTally:
+			return queryTally(ctx, req, k)
+		case QueryUpgrade:
+			upgrade, _ := k.GetUpgrade(ctx)
+			return k.marshalQueryResult(upgrade)
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Unknown gov query endpoint %s", path[0]))
+		}
+	}
+}
+
+func queryProposal(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	proposal, err := k.queriedProposal(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return k.marshalQueryResult(proposal)
+}
+
+func queryProposals(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryProposalsParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	proposals := []Proposal{}
+	for _, proposal := range k.GetProposals(ctx) {
+		if params.Status == 0 || proposal.Status == params.Status {
+			proposals = append(proposals, proposal)
+		}
+	}
+	return k.marshalQueryResult(proposals)
+}
+
+func queryDeposits(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	proposal, err := k.queriedProposal(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return k.marshalQueryResult(k.GetDeposits(ctx, proposal.ProposalID))
+}
+
+func queryVotes(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	proposal, err := k.queriedProposal(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return k.marshalQueryResult(k.GetVotes(ctx, proposal.ProposalID))
+}
+
+// the current tally of a proposal in its voting period, the final one otherwise
+func queryTally(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	proposal, err := k.queriedProposal(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Status != StatusVotingPeriod {
+		return k.marshalQueryResult(proposal.TallyResult)
+	}
+	_, _, result := k.tally(ctx, proposal)
+	return k.marshalQueryResult(result)
+}
+
+func (k Keeper) queriedProposal(ctx sdk.Context, req abci.RequestQuery) (proposal Proposal, err sdk.Error) {
+	var params QueryProposalParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return proposal, err
+	}
+	proposal, found := k.GetProposal(ctx, params.ProposalID)
+	if !found {
+		return proposal, ErrUnknownProposal(k.codespace, params.ProposalID)
+	}
+	return proposal, nil
+}
+
+func (k Keeper) unmarshalQueryParams(req abci.RequestQuery, params interface{}) sdk.Error {
+	err := k.cdc.UnmarshalJSON(req.Data, params)
+	if err != nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("Incorrectly formatted query data: %s", err.Error()))
+	}
+	return nil
+}
+
+func (k Keeper) marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	return k.mustMarshal(res), nil
+}
@@ -0,0 +1,19 @@
+package gov
+
+// Tags attached to the results of governance messages, indexed by Tendermint.
+// Addresses are tagged in upper case hex, proposal ids in decimal.
+const (
+	TagAction     = "action"
+	TagProposalID = "proposal-id"
+	TagProposer   = "proposer"
+	TagDepositor  = "depositor"
+	TagVoter      = "voter"
+	TagActivated  = "voting-period-start"
+)
+
+// Values of the action tag
+var (
+	ActionSubmitProposal = []byte("submit-proposal")
+	ActionDeposit        = []byte("deposit")
+	ActionVote           = []byte("vote")
+)
@@ -0,0 +1,100 @@
+package gov
+
+import (
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/x/stake"
+)
+
+// the voting power of a bonded validator, and the vote it casts for the
+// delegators who did not vote themselves
+type validatorGovInfo struct {
+	Power           sdk.Rat    // voting power of the validator
+	DelegatorShares sdk.Rat    // shares issued to the delegators of the validator
+	Minus           sdk.Rat    // shares of the delegators who voted themselves
+	Vote            VoteOption // zero if the validator did not vote
+}
+
+// tally the votes on the proposal. Every bonded validator votes with its
+// power, except for the share of its delegators who voted themselves:
+// their vote overrides the one of the validator for their bonds.
+func (k Keeper) tally(ctx sdk.Context, proposal Proposal) (passes, vetoed bool, result TallyResult) {
+	results := map[VoteOption]sdk.Rat{
+		OptionYes:        sdk.ZeroRat(),
+		OptionAbstain:    sdk.ZeroRat(),
+		OptionNo:         sdk.ZeroRat(),
+		OptionNoWithVeto: sdk.ZeroRat(),
+	}
+
+	totalPower := sdk.ZeroRat()
+	validators := make(map[string]*validatorGovInfo)
+	for _, validator := range k.stakeKeeper.GetBondedValidators(ctx) {
+		candidate, found := k.stakeKeeper.GetCandidate(ctx, validator.Address)
+		if !found {
+			continue
+		}
+		validators[string(validator.Address)] = &validatorGovInfo{
+			Power:           validator.Power,
+			DelegatorShares: candidate.Liabilities,
+			Minus:           sdk.ZeroRat(),
+		}
+		totalPower = totalPower.Add(validator.Power)
+	}
+
+	votes := k.GetVotes(ctx, proposal.ProposalID)
+	for _, vote := range votes {
+		if val, ok := validators[string(vote.Voter)]; ok {
+			val.Vote = vote.Option
+		}
+	}
+
+	// the delegators vote with the power of their bonds to bonded validators
+	totalVoted := sdk.ZeroRat()
+	for _, vote := range votes {
+		option := vote.Option
+		k.stakeKeeper.IterateDelegatorBonds(ctx, vote.Voter, func(bond stake.DelegatorBond) bool {
+			val, ok := validators[string(bond.CandidateAddr)]
+			if !ok || val.DelegatorShares.IsZero() {
+				return false
+			}
+			val.Minus = val.Minus.Add(bond.Shares)
+			power := bond.Shares.Mul(val.Power).Quo(val.DelegatorShares)
+			results[option] = results[option].Add(power)
+			totalVoted = totalVoted.Add(power)
+			return false
+		})
+	}
+
+	// the validators vote with the power left to them
+	for _, val := range validators {
+		if val.Vote == 0 || val.DelegatorShares.IsZero() {
+			continue
+		}
+		power := val.DelegatorShares.Sub(val.Minus).Mul(val.Power).Quo(val.DelegatorShares)
+		results[val.Vote] = results[val.Vote].Add(power)
+		totalVoted = totalVoted.Add(power)
+	}
+
+	result = TallyResult{
+		Yes:        results[OptionYes],
+		Abstain:    results[OptionAbstain],
+		No:         results[OptionNo],
+		NoWithVeto: results[OptionNoWithVeto],
+	}
+
+	params := k.GetParams(ctx)
+
+	// not enough of the bonded power voted
+	if totalPower.IsZero() || totalVoted.IsZero() || totalVoted.Quo(totalPower).LT(params.Quorum) {
+		return false, false, result
+	}
+	// too many vetoed the proposal, the deposits are burned
+	if result.NoWithVeto.Quo(totalVoted).GT(params.Veto) {
+		return false, true, result
+	}
+	// everyone abstained
+	nonAbstaining := totalVoted.Sub(result.Abstain)
+	if nonAbstaining.IsZero() {
+		return false, false, result
+	}
+	return result.Yes.Quo(nonAbstaining).GT(params.Threshold), false, result
+}
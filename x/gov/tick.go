@@ -0,0 +1,98 @@
+package gov
+
+import (
+	"encoding/binary"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+// NewEndBlocker generates sdk.EndBlocker
+// Ends the deposit and voting periods of the proposals which expired
+func NewEndBlocker(k Keeper) sdk.EndBlocker {
+	return func(ctx sdk.Context, req abci.RequestEndBlock) (res abci.ResponseEndBlock) {
+		EndBlocker(ctx, k)
+		return
+	}
+}
+
+// EndBlocker rejects the proposals which did not reach the min deposit in
+// time, and tallies the proposals whose voting period is over
+func EndBlocker(ctx sdk.Context, k Keeper) {
+	logger := ctx.Logger().With("module", "x/gov")
+	store := ctx.KVStore(k.key)
+	now := ctx.BlockHeader().Time
+
+	// the deposits of proposals which never got to a vote are burned
+	for _, proposalID := range dequeueExpired(store, DepositQueueKey, now) {
+		proposal, found := k.GetProposal(ctx, proposalID)
+		if !found {
+			continue
+		}
+		k.burnDeposits(ctx, proposalID)
+		proposal.Status = StatusRejected
+		k.setProposal(ctx, proposal)
+		logger.Info("Proposal did not reach the min deposit", "proposal", proposalID)
+	}
+
+	for _, proposalID := range dequeueExpired(store, VotingQueueKey, now) {
+		proposal, found := k.GetProposal(ctx, proposalID)
+		if !found {
+			continue
+		}
+		passes, vetoed, result := k.tally(ctx, proposal)
+		proposal.TallyResult = result
+		if vetoed {
+			k.burnDeposits(ctx, proposalID)
+		} else {
+			k.refundDeposits(ctx, proposalID)
+		}
+		if !passes {
+			proposal.Status = StatusRejected
+			k.setProposal(ctx, proposal)
+			logger.Info("Proposal rejected", "proposal", proposalID, "vetoed", vetoed)
+			continue
+		}
+
+		proposal.Status = StatusPassed
+		k.setProposal(ctx, proposal)
+		logger.Info("Proposal passed", "proposal", proposalID)
+		k.executeProposal(ctx, proposal)
+	}
+}
+
+// carry out a passed proposal
+func (k Keeper) executeProposal(ctx sdk.Context, proposal Proposal) {
+	switch proposal.ProposalType {
+	case ProposalTypeParameterChange:
+		// the changes are applied all together or not at all, the params
+		// may have changed since they were checked at submission
+		cacheCtx, writeCache := ctx.CacheContext()
+		err := k.applyParamChanges(cacheCtx, proposal.ParamChanges)
+		if err != nil {
+			ctx.Logger().With("module", "x/gov").Error("Param changes of the proposal failed",
+				"proposal", proposal.ProposalID, "err", err.Error())
+			return
+		}
+		writeCache()
+	case ProposalTypeSoftwareUpgrade:
+		// the validators switch to the new software at the upgrade height
+		k.setUpgrade(ctx, proposal.Upgrade)
+	}
+}
+
+// remove the queue entries whose period ended by the time, returning their proposal ids
+func dequeueExpired(store sdk.KVStore, queueKey []byte, now int64) (proposalIDs []int64) {
+	iterator := store.Iterator(queueKey, getQueueKey(queueKey, now+1, 0))
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+		proposalIDs = append(proposalIDs, int64(binary.BigEndian.Uint64(iterator.Value())))
+	}
+	iterator.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+	return proposalIDs
+}
@@ -0,0 +1,246 @@
+package gov
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "inschain-tendermint/types"
+)
+
+// GenesisState - all governance state that must be provided at genesis
+type GenesisState struct {
+	StartingProposalID int64      `json:"starting_proposal_id"`
+	Params             Params     `json:"params"`
+	Proposals          []Proposal `json:"proposals"`
+	Deposits           []Deposit  `json:"deposits"`
+	Votes              []Vote     `json:"votes"`
+}
+
+// Params defines how proposals are funded, voted on and tallied
+type Params struct {
+	MinDeposit       sdk.Coins `json:"min_deposit"`        // deposit a proposal needs to enter its voting period
+	MaxDepositPeriod int64     `json:"max_deposit_period"` // seconds a proposal has to reach the min deposit
+	VotingPeriod     int64     `json:"voting_period"`      // seconds a proposal is voted on
+	Quorum           sdk.Rat   `json:"quorum"`             // fraction of the bonded power which must vote
+	Threshold        sdk.Rat   `json:"threshold"`          // fraction of the non-abstaining power voting yes to pass
+	Veto             sdk.Rat   `json:"veto"`               // fraction of the voting power vetoing to reject and burn the deposits
+}
+
+// DefaultParams - the params used when the genesis does not set them
+func DefaultParams() Params {
+	return Params{
+		MinDeposit:       sdk.Coins{{"steak", 10}},
+		MaxDepositPeriod: 60 * 60 * 24 * 14,
+		VotingPeriod:     60 * 60 * 24 * 14,
+		Quorum:           sdk.NewRat(1, 3),
+		Threshold:        sdk.NewRat(1, 2),
+		Veto:             sdk.NewRat(1, 3),
+	}
+}
+
+// DefaultGenesisState - the governance genesis with the default params
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		StartingProposalID: 1,
+		Params:             DefaultParams(),
+	}
+}
+
+// ValidateBasic checks the params are consistent
+func (p Params) ValidateBasic(codespace sdk.CodespaceType) sdk.Error {
+	if !p.MinDeposit.IsValid() {
+		return ErrInvalidParams(codespace, fmt.Sprintf("min deposit %v is not valid", p.MinDeposit))
+	}
+	if p.MaxDepositPeriod <= 0 || p.VotingPeriod <= 0 {
+		return ErrInvalidParams(codespace, "deposit and voting periods must be positive")
+	}
+	for _, fraction := range []sdk.Rat{p.Quorum, p.Threshold, p.Veto} {
+		if fraction.LT(sdk.ZeroRat()) || fraction.GT(sdk.OneRat()) {
+			return ErrInvalidParams(codespace, fmt.Sprintf("fraction %v must be between 0 and 1", fraction))
+		}
+	}
+	return nil
+}
+
+//_______________________________________________________________________
+
+// ProposalKind - the type of a proposal, which decides what happens when it passes
+type ProposalKind byte
+
+const (
+	// nolint
+	ProposalTypeText            ProposalKind = 0x01
+	ProposalTypeParameterChange ProposalKind = 0x02
+	ProposalTypeSoftwareUpgrade ProposalKind = 0x03
+)
+
+// ProposalTypeFromString - parse the name of a proposal type
+func ProposalTypeFromString(str string) (ProposalKind, error) {
+	switch strings.ToLower(str) {
+	case "text":
+		return ProposalTypeText, nil
+	case "parameter-change":
+		return ProposalTypeParameterChange, nil
+	case "software-upgrade":
+		return ProposalTypeSoftwareUpgrade, nil
+	default:
+		return ProposalKind(0x00), fmt.Errorf("'%s' is not a valid proposal type", str)
+	}
+}
+
+func (kind ProposalKind) String() string {
+	switch kind {
+	case ProposalTypeText:
+		return "text"
+	case ProposalTypeParameterChange:
+		return "parameter-change"
+	case ProposalTypeSoftwareUpgrade:
+		return "software-upgrade"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(kind))
+	}
+}
+
+// ProposalStatus - the stage a proposal is at
+type ProposalStatus byte
+
+const (
+	// nolint
+	StatusDepositPeriod ProposalStatus = 0x01
+	StatusVotingPeriod  ProposalStatus = 0x02
+	StatusPassed        ProposalStatus = 0x03
+	StatusRejected      ProposalStatus = 0x04
+)
+
+func (status ProposalStatus) String() string {
+	switch status {
+	case StatusDepositPeriod:
+		return "deposit-period"
+	case StatusVotingPeriod:
+		return "voting-period"
+	case StatusPassed:
+		return "passed"
+	case StatusRejected:
+		return "rejected"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(status))
+	}
+}
+
+// VoteOption - the choice of a vote
+type VoteOption byte
+
+const (
+	// nolint
+	OptionYes        VoteOption = 0x01
+	OptionAbstain    VoteOption = 0x02
+	OptionNo         VoteOption = 0x03
+	OptionNoWithVeto VoteOption = 0x04
+)
+
+// VoteOptionFromString - parse the name of a vote option
+func VoteOptionFromString(str string) (VoteOption, error) {
+	switch strings.ToLower(str) {
+	case "yes":
+		return OptionYes, nil
+	case "abstain":
+		return OptionAbstain, nil
+	case "no":
+		return OptionNo, nil
+	case "no-with-veto", "veto":
+		return OptionNoWithVeto, nil
+	default:
+		return VoteOption(0x00), fmt.Errorf("'%s' is not a valid vote option", str)
+	}
+}
+
+func (option VoteOption) String() string {
+	switch option {
+	case OptionYes:
+		return "yes"
+	case OptionAbstain:
+		return "abstain"
+	case OptionNo:
+		return "no"
+	case OptionNoWithVeto:
+		return "no-with-veto"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(option))
+	}
+}
+
+func validVoteOption(option VoteOption) bool {
+	return option >= OptionYes && option <= OptionNoWithVeto
+}
+
+//_______________________________________________________________________
+
+// ParamChange - a single parameter of a module to set when the proposal
+// passes, the key is the JSON name of the param and the value its JSON
+type ParamChange struct {
+	Module string `json:"module"`
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+}
+
+// SoftwareUpgrade - the software version the validators agree to switch
+// to at the height, once the proposal passed
+type SoftwareUpgrade struct {
+	Name   string `json:"name"`
+	Height int64  `json:"height"`
+	Info   string `json:"info"`
+}
+
+// Proposal - a proposal submitted to governance, funded by deposits and
+// voted on by the bonded stake
+type Proposal struct {
+	ProposalID   int64           `json:"proposal_id"`
+	Title        string          `json:"title"`
+	Description  string          `json:"description"`
+	ProposalType ProposalKind    `json:"proposal_type"`
+	ParamChanges []ParamChange   `json:"param_changes,omitempty"` // for parameter-change proposals
+	Upgrade      SoftwareUpgrade `json:"upgrade"`                 // for software-upgrade proposals
+	Proposer     sdk.Address     `json:"proposer"`
+
+	Status       ProposalStatus `json:"status"`
+	TotalDeposit sdk.Coins      `json:"total_deposit"`
+	TallyResult  TallyResult    `json:"tally_result"` // set once the voting period ended
+
+	SubmitTime      int64 `json:"submit_time"`       // unix time the proposal was submitted at
+	DepositEndTime  int64 `json:"deposit_end_time"`  // unix time the deposit period ends at
+	VotingStartTime int64 `json:"voting_start_time"` // unix time the voting period started at
+	VotingEndTime   int64 `json:"voting_end_time"`   // unix time the voting period ends at
+}
+
+// Deposit - the coins deposited by an account on a proposal
+type Deposit struct {
+	ProposalID int64       `json:"proposal_id"`
+	Depositor  sdk.Address `json:"depositor"`
+	Amount     sdk.Coins   `json:"amount"`
+}
+
+// Vote - the vote of an account on a proposal, the votes of delegators
+// override the votes of their validators
+type Vote struct {
+	ProposalID int64       `json:"proposal_id"`
+	Voter      sdk.Address `json:"voter"`
+	Option     VoteOption  `json:"option"`
+}
+
+// TallyResult - the voting power cast for each option
+type TallyResult struct {
+	Yes        sdk.Rat `json:"yes"`
+	Abstain    sdk.Rat `json:"abstain"`
+	No         sdk.Rat `json:"no"`
+	NoWithVeto sdk.Rat `json:"no_with_veto"`
+}
+
+// EmptyTallyResult - the result of a proposal nobody voted on
+func EmptyTallyResult() TallyResult {
+	return TallyResult{
+		Yes:        sdk.ZeroRat(),
+		Abstain:    sdk.ZeroRat(),
+		No:         sdk.ZeroRat(),
+		NoWithVeto: sdk.ZeroRat(),
+	}
+}
@@ -0,0 +1,12 @@
+package gov
+
+import (
+	"inschain-tendermint/wire"
+)
+
+// Register concrete types on wire codec
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgSubmitProposal{}, "gov/SubmitProposal", nil)
+	cdc.RegisterConcrete(MsgDeposit{}, "gov/Deposit", nil)
+	cdc.RegisterConcrete(MsgVote{}, "gov/Vote", nil)
+}
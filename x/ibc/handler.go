@@ -3,17 +3,17 @@ package ibc
 import (
 	"reflect"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/x/bank"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/x/bank"
 )
 
-func NewHandler(ibcm Mapper, ck bank.Keeper) sdk.Handler {
+func NewHandler(ibcm Mapper, sk bank.SupplyKeeper) sdk.Handler {
 	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
 		switch msg := msg.(type) {
 		case IBCTransferMsg:
-			return handleIBCTransferMsg(ctx, ibcm, ck, msg)
+			return handleIBCTransferMsg(ctx, ibcm, sk, msg)
 		case IBCReceiveMsg:
-			return handleIBCReceiveMsg(ctx, ibcm, ck, msg)
+			return handleIBCReceiveMsg(ctx, ibcm, sk, msg)
 		default:
 			errMsg := "Unrecognized IBC Msg type: " + reflect.TypeOf(msg).Name()
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -21,11 +21,11 @@ func NewHandler(ibcm Mapper, ck bank.Keeper) sdk.Handler {
 	}
 }
 
-// IBCTransferMsg deducts coins from the account and creates an egress IBC packet.
-func handleIBCTransferMsg(ctx sdk.Context, ibcm Mapper, ck bank.Keeper, msg IBCTransferMsg) sdk.Result {
+// IBCTransferMsg burns coins from the account and creates an egress IBC packet.
+func handleIBCTransferMsg(ctx sdk.Context, ibcm Mapper, sk bank.SupplyKeeper, msg IBCTransferMsg) sdk.Result {
 	packet := msg.IBCPacket
 
-	_, err := ck.SubtractCoins(ctx, packet.SrcAddr, packet.Coins)
+	_, err := sk.Burn(ctx, packet.SrcAddr, packet.Coins)
 	if err != nil {
 		return err.Result()
 	}
@@ -38,8 +38,8 @@ func handleIBCTransferMsg(ctx sdk.Context, ibcm Mapper, ck bank.Keeper, msg IBCT
 	return sdk.Result{}
 }
 
-// IBCReceiveMsg adds coins to the destination address and creates an ingress IBC packet.
-func handleIBCReceiveMsg(ctx sdk.Context, ibcm Mapper, ck bank.Keeper, msg IBCReceiveMsg) sdk.Result {
+// IBCReceiveMsg mints coins to the destination address and creates an ingress IBC packet.
+func handleIBCReceiveMsg(ctx sdk.Context, ibcm Mapper, sk bank.SupplyKeeper, msg IBCReceiveMsg) sdk.Result {
 	packet := msg.IBCPacket
 
 	seq := ibcm.GetIngressSequence(ctx, packet.SrcChain)
@@ -47,7 +47,7 @@ func handleIBCReceiveMsg(ctx sdk.Context, ibcm Mapper, ck bank.Keeper, msg IBCRe
 		return ErrInvalidSequence(ibcm.codespace).Result()
 	}
 
-	_, err := ck.AddCoins(ctx, packet.DestAddr, packet.Coins)
+	_, err := sk.Mint(ctx, packet.DestAddr, packet.Coins)
 	if err != nil {
 		return err.Result()
 	}
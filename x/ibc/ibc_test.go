@@ -10,19 +10,21 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	"github.com/cosmos/cosmos-sdk/store"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/bank"
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
 )
 
 // AccountMapper(/Keeper) and IBCMapper should use different StoreKey later
 
-func defaultContext(key sdk.StoreKey) sdk.Context {
+func defaultContext(keys ...sdk.StoreKey) sdk.Context {
 	db := dbm.NewMemDB()
 	cms := store.NewCommitMultiStore(db)
-	cms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	for _, key := range keys {
+		cms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	}
 	cms.LoadLatestVersion()
 	ctx := sdk.NewContext(cms, abci.Header{}, false, nil, log.NewNopLogger())
 	return ctx
@@ -59,10 +61,12 @@ func TestIBC(t *testing.T) {
 	cdc := makeCodec()
 
 	key := sdk.NewKVStoreKey("ibc")
-	ctx := defaultContext(key)
+	keySupply := sdk.NewKVStoreKey("supply")
+	ctx := defaultContext(key, keySupply)
 
 	am := auth.NewAccountMapper(cdc, key, &auth.BaseAccount{})
 	ck := bank.NewKeeper(am)
+	sk := bank.NewSupplyKeeper(cdc, keySupply, ck, bank.DefaultCodespace)
 
 	src := newAddress()
 	dest := newAddress()
@@ -70,12 +74,12 @@ func TestIBC(t *testing.T) {
 	zero := sdk.Coins(nil)
 	mycoins := sdk.Coins{sdk.Coin{"mycoin", 10}}
 
-	coins, err := ck.AddCoins(ctx, src, mycoins)
+	coins, err := sk.Mint(ctx, src, mycoins)
 	assert.Nil(t, err)
 	assert.Equal(t, mycoins, coins)
 
 	ibcm := NewMapper(cdc, key, DefaultCodespace)
-	h := NewHandler(ibcm, ck)
+	h := NewHandler(ibcm, sk)
 	packet := IBCPacket{
 		SrcAddr:   src,
 		DestAddr:  dest,
@@ -1,9 +1,9 @@
 package ibc
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 
-	wire "github.com/cosmos/cosmos-sdk/wire"
+	wire "inschain-tendermint/wire"
 )
 
 // ------------------------------
@@ -1,7 +1,7 @@
 package ibc
 
 import (
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/wire"
 )
 
 // Register concrete types on wire codec
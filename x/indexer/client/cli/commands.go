@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	cmn "github.com/tendermint/tmlibs/common"
+	"github.com/tendermint/tmlibs/log"
+
+	"inschain-tendermint/client"
+	"inschain-tendermint/wire"
+
+	"inschain-tendermint/x/indexer"
+)
+
+const (
+	flagDBDir        = "db-dir"
+	flagListenAddr   = "laddr"
+	flagRebuild      = "rebuild"
+	flagPollInterval = "poll-interval"
+)
+
+// ServeCmd runs the indexer as a separate process following a node
+func ServeCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "indexer",
+		Short: "Index the mutual state of a node and serve it over REST",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "indexer")
+
+			db, err := indexer.OpenDB(viper.GetString(flagDBDir))
+			if err != nil {
+				return err
+			}
+			idx := indexer.NewIndexer(db)
+			if viper.GetBool(flagRebuild) {
+				logger.Info("Rebuilding the index from genesis")
+				idx.Reset()
+			}
+
+			listener, err := indexer.Serve(idx, viper.GetString(flagListenAddr), logger)
+			if err != nil {
+				return err
+			}
+
+			node := rpcclient.NewHTTP(viper.GetString(client.FlagNode), "/websocket")
+			replayer := indexer.NewReplayer(idx, node, cdc, logger)
+			go func() {
+				err := indexer.Follow(replayer, viper.GetDuration(flagPollInterval), nil)
+				if err != nil {
+					cmn.Exit(err.Error())
+				}
+			}()
+
+			// Wait forever and cleanup
+			cmn.TrapSignal(func() {
+				err := listener.Close()
+				logger.Error("Error closing listener", "err", err)
+			})
+			return nil
+		},
+	}
+	cmd.Flags().StringP(client.FlagNode, "n", "tcp://localhost:46657", "Node to index")
+	cmd.Flags().String(flagDBDir, os.ExpandEnv("$HOME/.mutualcli/indexer"), "Directory of the index database")
+	cmd.Flags().String(flagListenAddr, "tcp://localhost:1318", "Address for the query API to listen on")
+	cmd.Flags().Bool(flagRebuild, false, "Delete the index and replay the chain from genesis")
+	cmd.Flags().Duration(flagPollInterval, time.Second, "Interval between polls for new blocks")
+	return cmd
+}
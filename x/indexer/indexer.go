@@ -0,0 +1,386 @@
+package indexer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	sdk "inschain-tendermint/types"
+
+	bam "inschain-tendermint/baseapp"
+	"inschain-tendermint/x/mutual"
+)
+
+var (
+	cursorKey    = []byte("meta/cursor")
+	syncedKey    = []byte("meta/synced")
+	policyPrefix = []byte("policy/")
+	memberPrefix = []byte("member/")
+	claimPrefix  = []byte("claim/")
+	payoutPrefix = []byte("payout/")
+)
+
+// nolint
+func policyKey(policy sdk.Address) []byte { return append(copyBytes(policyPrefix), policy.String()...) }
+func membersKey(policy sdk.Address) []byte {
+	return append(append(copyBytes(memberPrefix), policy.String()...), '/')
+}
+func memberKey(policy, member sdk.Address) []byte {
+	return append(membersKey(policy), member.String()...)
+}
+func claimKey(id string) []byte  { return append(copyBytes(claimPrefix), id...) }
+func payoutKey(id string) []byte { return append(copyBytes(payoutPrefix), id...) }
+
+// records created by a transaction are identified by policy and position in the chain
+func recordID(policy sdk.Address, height int64, txIndex int) string {
+	return fmt.Sprintf("%s/%020d/%06d", policy.String(), height, txIndex)
+}
+
+func copyBytes(bz []byte) []byte {
+	cp := make([]byte, len(bz))
+	copy(cp, bz)
+	return cp
+}
+
+// Indexer materializes the mutual state of committed blocks into a database.
+// Transactions are applied in chain order and the position of the last applied
+// transaction is stored with its changes, so replaying already indexed blocks
+// after a restart is a no-op.
+type Indexer struct {
+	mtx sync.RWMutex
+	db  dbm.DB
+}
+
+// NewIndexer returns an Indexer backed by the given database
+func NewIndexer(db dbm.DB) *Indexer {
+	return &Indexer{db: db}
+}
+
+// Cursor returns the position of the last applied message
+func (idx *Indexer) Cursor() Cursor {
+	idx.mtx.RLock()
+	defer idx.mtx.RUnlock()
+	return idx.cursor()
+}
+
+func (idx *Indexer) cursor() Cursor {
+	var cursor Cursor
+	idx.get(cursorKey, &cursor)
+	return cursor
+}
+
+// SyncedHeight returns the height of the last block replayed in full
+func (idx *Indexer) SyncedHeight() int64 {
+	idx.mtx.RLock()
+	defer idx.mtx.RUnlock()
+	var height int64
+	idx.get(syncedKey, &height)
+	return height
+}
+
+// MarkSynced records that every transaction up to the height has been applied
+func (idx *Indexer) MarkSynced(height int64) {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+	var synced int64
+	idx.get(syncedKey, &synced)
+	if height <= synced {
+		return
+	}
+	batch := idx.db.NewBatch()
+	idx.set(batch, syncedKey, height)
+	batch.Write()
+}
+
+// Reset deletes all indexed data, so the index can be rebuilt from genesis
+func (idx *Indexer) Reset() {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	batch := idx.db.NewBatch()
+	iter := idx.db.Iterator(nil, nil)
+	for ; iter.Valid(); iter.Next() {
+		batch.Delete(iter.Key())
+	}
+	iter.Close()
+	batch.Write()
+}
+
+// Apply indexes a committed message. Messages at or before the cursor are ignored.
+func (idx *Indexer) Apply(ev bam.TxEvent) error {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	if !idx.cursor().After(ev.Height, ev.TxIndex, ev.MsgIndex) {
+		return nil
+	}
+
+	batch := idx.db.NewBatch()
+	if ev.Result.IsOK() {
+		err := idx.applyMsg(batch, ev)
+		if err != nil {
+			return err
+		}
+	}
+	idx.set(batch, cursorKey, Cursor{Height: ev.Height, TxIndex: ev.TxIndex, MsgIndex: ev.MsgIndex})
+	batch.Write()
+	return nil
+}
+
+func (idx *Indexer) applyMsg(batch dbm.Batch, ev bam.TxEvent) error {
+	switch msg := ev.Msg.(type) {
+	case mutual.MutualNewPolicyMsg:
+		return idx.applyNewPolicy(batch, ev, msg)
+	case mutual.MutualBondMsg:
+		return idx.applyBond(batch, ev, msg)
+	case mutual.MutualUnbondMsg:
+		return idx.applyUnbond(batch, ev, msg)
+	case mutual.MutualProposalMsg:
+		return idx.applyProposal(batch, ev, msg)
+	case mutual.MutualPolicyApprovalMsg:
+		return idx.applyApproval(batch, ev, msg)
+	case mutual.MutualCollectCliamMsg:
+		return idx.applyCollect(batch, ev, msg)
+	case mutual.MutualPolicyLockMsg:
+		return idx.applyLock(batch, ev, msg)
+	default:
+		// not part of the materialized state
+		return nil
+	}
+}
+
+func (idx *Indexer) applyNewPolicy(batch dbm.Batch, ev bam.TxEvent, msg mutual.MutualNewPolicyMsg) error {
+	if _, ok := idx.getPolicy(msg.Address); ok {
+		return nil
+	}
+	idx.set(batch, policyKey(msg.Address), Policy{
+		Address:       msg.Address,
+		Locked:        true,
+		CreatedHeight: ev.Height,
+		UpdatedHeight: ev.Height,
+	})
+	return nil
+}
+
+func (idx *Indexer) applyBond(batch dbm.Batch, ev bam.TxEvent, msg mutual.MutualBondMsg) error {
+	policy, ok := idx.getPolicy(msg.PolicyAddress)
+	if !ok {
+		return fmt.Errorf("bond to unknown policy %v at height %d", msg.PolicyAddress, ev.Height)
+	}
+	member, ok := idx.getMember(msg.PolicyAddress, msg.Address)
+	if !ok {
+		member = Member{
+			Policy:       msg.PolicyAddress,
+			Address:      msg.Address,
+			JoinedHeight: ev.Height,
+		}
+		policy.Members++
+	}
+	member.Amount += msg.Stake.Amount
+	member.UpdatedHeight = ev.Height
+	policy.TotalAmount += msg.Stake.Amount
+	policy.UpdatedHeight = ev.Height
+
+	idx.set(batch, memberKey(msg.PolicyAddress, msg.Address), member)
+	idx.set(batch, policyKey(msg.PolicyAddress), policy)
+	return nil
+}
+
+func (idx *Indexer) applyUnbond(batch dbm.Batch, ev bam.TxEvent, msg mutual.MutualUnbondMsg) error {
+	policy, ok := idx.getPolicy(msg.PolicyAddress)
+	if !ok {
+		return fmt.Errorf("unbond from unknown policy %v at height %d", msg.PolicyAddress, ev.Height)
+	}
+	member, ok := idx.getMember(msg.PolicyAddress, msg.Address)
+	if !ok {
+		return fmt.Errorf("unbond of unknown member %v at height %d", msg.Address, ev.Height)
+	}
+	policy.Members--
+	policy.TotalAmount -= member.Amount
+	policy.UpdatedHeight = ev.Height
+
+	batch.Delete(memberKey(msg.PolicyAddress, msg.Address))
+	idx.set(batch, policyKey(msg.PolicyAddress), policy)
+	return nil
+}
+
+func (idx *Indexer) applyProposal(batch dbm.Batch, ev bam.TxEvent, msg mutual.MutualProposalMsg) error {
+	policy, ok := idx.getPolicy(msg.PolicyAddress)
+	if !ok {
+		return fmt.Errorf("claim on unknown policy %v at height %d", msg.PolicyAddress, ev.Height)
+	}
+	claim := Claim{
+		ID:            recordID(msg.PolicyAddress, ev.Height, ev.TxIndex),
+		Policy:        msg.PolicyAddress,
+		Claimant:      msg.Address,
+		Amount:        msg.Amount.Amount,
+		Status:        ClaimProposed,
+		FiledHeight:   ev.Height,
+		UpdatedHeight: ev.Height,
+	}
+	policy.OpenClaim = claim.ID
+	policy.UpdatedHeight = ev.Height
+
+	idx.set(batch, claimKey(claim.ID), claim)
+	idx.set(batch, policyKey(msg.PolicyAddress), policy)
+	return nil
+}
+
+func (idx *Indexer) applyApproval(batch dbm.Batch, ev bam.TxEvent, msg mutual.MutualPolicyApprovalMsg) error {
+	claim, err := idx.openClaim(msg.PolicyAddress, ev.Height)
+	if err != nil {
+		return err
+	}
+	claim.Status = ClaimApproved
+	if !msg.Approval {
+		// a rejected claim is closed, see mutual.Keeper.ApproveClaim
+		claim.Status = ClaimRejected
+		policy, _ := idx.getPolicy(msg.PolicyAddress)
+		policy.OpenClaim = ""
+		policy.UpdatedHeight = ev.Height
+		idx.set(batch, policyKey(msg.PolicyAddress), policy)
+	}
+	claim.UpdatedHeight = ev.Height
+	idx.set(batch, claimKey(claim.ID), claim)
+	return nil
+}
+
+func (idx *Indexer) applyCollect(batch dbm.Batch, ev bam.TxEvent, msg mutual.MutualCollectCliamMsg) error {
+	policy, ok := idx.getPolicy(msg.PolicyAddress)
+	if !ok {
+		return fmt.Errorf("collection on unknown policy %v at height %d", msg.PolicyAddress, ev.Height)
+	}
+	claim, err := idx.openClaim(msg.PolicyAddress, ev.Height)
+	if err != nil {
+		return err
+	}
+
+	// the keeper reports the deductions it made in the tags
+	totalTag, ok := ev.Tag(mutual.TagAmount)
+	if !ok {
+		return fmt.Errorf("collection without %s tag at height %d", mutual.TagAmount, ev.Height)
+	}
+	total, err := sdk.ParseCoin(string(totalTag))
+	if err != nil {
+		return fmt.Errorf("invalid %s tag at height %d: %v", mutual.TagAmount, ev.Height, err)
+	}
+	perMemberTag, ok := ev.Tag(mutual.TagPerMember)
+	if !ok {
+		return fmt.Errorf("collection without %s tag at height %d", mutual.TagPerMember, ev.Height)
+	}
+	perMember, err := sdk.ParseCoin(string(perMemberTag))
+	if err != nil {
+		return fmt.Errorf("invalid %s tag at height %d: %v", mutual.TagPerMember, ev.Height, err)
+	}
+	chargedTag, ok := ev.Tag(mutual.TagMembersCharged)
+	if !ok {
+		return fmt.Errorf("collection without %s tag at height %d", mutual.TagMembersCharged, ev.Height)
+	}
+	charged, err := strconv.Atoi(string(chargedTag))
+	if err != nil {
+		return fmt.Errorf("invalid %s tag at height %d: %v", mutual.TagMembersCharged, ev.Height, err)
+	}
+
+	// the keeper charges the other members in the order of their addresses,
+	// which is the order of the member keys
+	i := 0
+	iter := idx.db.Iterator(membersKey(msg.PolicyAddress), sdk.PrefixEndBytes(membersKey(msg.PolicyAddress)))
+	for ; iter.Valid() && i < charged; iter.Next() {
+		var member Member
+		mustUnmarshal(iter.Value(), &member)
+		if member.Address.String() == claim.Claimant.String() {
+			continue
+		}
+		member.Amount -= perMember.Amount
+		member.UpdatedHeight = ev.Height
+		idx.set(batch, copyBytes(iter.Key()), member)
+		i++
+	}
+	iter.Close()
+	if i < charged {
+		return fmt.Errorf("collection at height %d charged %d members but %d are indexed", ev.Height, charged, i)
+	}
+
+	payout := Payout{
+		ID:        recordID(msg.PolicyAddress, ev.Height, ev.TxIndex),
+		ClaimID:   claim.ID,
+		Policy:    msg.PolicyAddress,
+		Claimant:  claim.Claimant,
+		Amount:    total.Amount,
+		PerMember: perMember.Amount,
+		Members:   charged,
+		Timestamp: msg.Timestamp,
+		Height:    ev.Height,
+	}
+	claim.Status = ClaimCollected
+	claim.UpdatedHeight = ev.Height
+	policy.TotalAmount -= total.Amount
+	policy.OpenClaim = ""
+	policy.UpdatedHeight = ev.Height
+
+	idx.set(batch, payoutKey(payout.ID), payout)
+	idx.set(batch, claimKey(claim.ID), claim)
+	idx.set(batch, policyKey(msg.PolicyAddress), policy)
+	return nil
+}
+
+func (idx *Indexer) applyLock(batch dbm.Batch, ev bam.TxEvent, msg mutual.MutualPolicyLockMsg) error {
+	policy, ok := idx.getPolicy(msg.PolicyAddress)
+	if !ok {
+		return fmt.Errorf("lock of unknown policy %v at height %d", msg.PolicyAddress, ev.Height)
+	}
+	policy.Locked = msg.Lock
+	policy.UpdatedHeight = ev.Height
+	idx.set(batch, policyKey(msg.PolicyAddress), policy)
+	return nil
+}
+
+//______________________________________________________________________________
+
+func (idx *Indexer) getPolicy(addr sdk.Address) (policy Policy, ok bool) {
+	ok = idx.get(policyKey(addr), &policy)
+	return
+}
+
+func (idx *Indexer) getMember(policyAddr, addr sdk.Address) (member Member, ok bool) {
+	ok = idx.get(memberKey(policyAddr, addr), &member)
+	return
+}
+
+func (idx *Indexer) openClaim(policyAddr sdk.Address, height int64) (claim Claim, err error) {
+	policy, ok := idx.getPolicy(policyAddr)
+	if !ok || policy.OpenClaim == "" {
+		return claim, fmt.Errorf("no open claim on policy %v at height %d", policyAddr, height)
+	}
+	if !idx.get(claimKey(policy.OpenClaim), &claim) {
+		return claim, fmt.Errorf("missing claim %s", policy.OpenClaim)
+	}
+	return claim, nil
+}
+
+func (idx *Indexer) get(key []byte, ptr interface{}) bool {
+	bz := idx.db.Get(key)
+	if bz == nil {
+		return false
+	}
+	mustUnmarshal(bz, ptr)
+	return true
+}
+
+func (idx *Indexer) set(batch dbm.Batch, key []byte, o interface{}) {
+	bz, err := json.Marshal(o)
+	if err != nil {
+		panic(err)
+	}
+	batch.Set(key, bz)
+}
+
+func mustUnmarshal(bz []byte, ptr interface{}) {
+	err := json.Unmarshal(bz, ptr)
+	if err != nil {
+		panic(err)
+	}
+}
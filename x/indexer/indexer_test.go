@@ -0,0 +1,213 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	sdk "inschain-tendermint/types"
+
+	bam "inschain-tendermint/baseapp"
+	"inschain-tendermint/x/mutual"
+)
+
+var (
+	policyAddr = sdk.Address([]byte("policy______________"))
+	members    = []sdk.Address{
+		sdk.Address([]byte("member0_____________")),
+		sdk.Address([]byte("member1_____________")),
+		sdk.Address([]byte("member2_____________")),
+	}
+)
+
+func getx(amount int64) sdk.Coin {
+	return sdk.Coin{"getx", amount}
+}
+
+// events of a policy with three members and a collected claim
+func testEvents() []bam.TxEvent {
+	msgs := []sdk.Msg{
+		mutual.NewMutualNewPolicyMsg(policyAddr),
+		mutual.NewMutualBondMsg(policyAddr, members[0], getx(10)),
+		mutual.NewMutualBondMsg(policyAddr, members[1], getx(10)),
+		mutual.NewMutualBondMsg(policyAddr, members[2], getx(10)),
+		mutual.NewMutualProposalMsg(policyAddr, members[0], getx(6)),
+		mutual.NewMutualPolicyApprovalMsg(policyAddr, members[0], true),
+		mutual.NewMutualCollectCliamMsg(policyAddr, members[0], nil, "2018-05-27"),
+	}
+	events := make([]bam.TxEvent, len(msgs))
+	for i, msg := range msgs {
+		events[i] = bam.TxEvent{
+			Height:  int64(i/2 + 1),
+			TxIndex: i % 2,
+			MsgType: bam.MsgTypeName(msg),
+			Module:  msg.Type(),
+			Msg:     msg,
+		}
+	}
+	collect := &events[len(events)-1].Result
+	collect.Tags = sdk.NewTags(
+		mutual.TagAmount, []byte("6getx"),
+		mutual.TagPerMember, []byte("3getx"),
+		mutual.TagMembersCharged, []byte("2"),
+	)
+	return events
+}
+
+func TestApply(t *testing.T) {
+	idx := NewIndexer(dbm.NewMemDB())
+	for _, ev := range testEvents() {
+		require.Nil(t, idx.Apply(ev))
+	}
+
+	policy, ok := idx.Policy(policyAddr)
+	require.True(t, ok)
+	assert.Equal(t, int32(3), policy.Members)
+	assert.Equal(t, int64(24), policy.TotalAmount)
+	assert.Equal(t, "", policy.OpenClaim)
+
+	page, err := idx.Members(policyAddr, ListOptions{SortBy: "amount", Desc: true})
+	require.Nil(t, err)
+	assert.Equal(t, 3, page.Total)
+	list := page.Items.([]Member)
+	assert.Equal(t, members[0].String(), list[0].Address.String())
+	assert.Equal(t, int64(10), list[0].Amount)
+	assert.Equal(t, int64(7), list[1].Amount)
+
+	page, err = idx.Claims(ClaimFilter{Policy: policyAddr, Status: ClaimCollected}, ListOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, 1, page.Total)
+
+	page, err = idx.Payouts(PayoutFilter{Claimant: members[0]}, ListOptions{})
+	require.Nil(t, err)
+	require.Equal(t, 1, page.Total)
+	payout := page.Items.([]Payout)[0]
+	assert.Equal(t, int64(6), payout.Amount)
+	assert.Equal(t, int64(3), payout.PerMember)
+	assert.Equal(t, 2, payout.Members)
+}
+
+func TestApplyIdempotent(t *testing.T) {
+	idx := NewIndexer(dbm.NewMemDB())
+	events := testEvents()
+
+	// a restart replays the already indexed transactions
+	for _, ev := range events[:4] {
+		require.Nil(t, idx.Apply(ev))
+	}
+	for _, ev := range events {
+		require.Nil(t, idx.Apply(ev))
+	}
+	policy, _ := idx.Policy(policyAddr)
+	assert.Equal(t, int32(3), policy.Members)
+	assert.Equal(t, int64(24), policy.TotalAmount)
+	assert.Equal(t, Cursor{4, 0, 0}, idx.Cursor())
+
+	// failed transactions only move the cursor
+	ev := bam.TxEvent{Height: 5, Msg: mutual.NewMutualBondMsg(policyAddr, members[1], getx(5))}
+	ev.Result.Code = sdk.ABCICodeType(1)
+	require.Nil(t, idx.Apply(ev))
+	policy, _ = idx.Policy(policyAddr)
+	assert.Equal(t, int64(24), policy.TotalAmount)
+	assert.Equal(t, Cursor{5, 0, 0}, idx.Cursor())
+
+	// the following msgs of a tx are applied
+	ev = bam.TxEvent{Height: 5, MsgIndex: 1, Msg: mutual.NewMutualBondMsg(policyAddr, members[1], getx(5))}
+	require.Nil(t, idx.Apply(ev))
+	policy, _ = idx.Policy(policyAddr)
+	assert.Equal(t, int64(29), policy.TotalAmount)
+	assert.Equal(t, Cursor{5, 0, 1}, idx.Cursor())
+
+	idx.MarkSynced(5)
+	assert.Equal(t, int64(5), idx.SyncedHeight())
+	idx.Reset()
+	assert.Equal(t, int64(0), idx.SyncedHeight())
+	_, ok := idx.Policy(policyAddr)
+	assert.False(t, ok)
+}
+
+func TestApplyRejectedClaim(t *testing.T) {
+	idx := NewIndexer(dbm.NewMemDB())
+	events := testEvents()
+	events[5].Msg = mutual.NewMutualPolicyApprovalMsg(policyAddr, members[0], false)
+	for _, ev := range events[:6] {
+		require.Nil(t, idx.Apply(ev))
+	}
+
+	policy, _ := idx.Policy(policyAddr)
+	assert.Equal(t, "", policy.OpenClaim)
+	page, err := idx.Claims(ClaimFilter{Policy: policyAddr, Status: ClaimRejected}, ListOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, 1, page.Total)
+
+	// the rejected claim can't be collected
+	assert.NotNil(t, idx.Apply(events[6]))
+}
+
+func TestApplyCollectMissingTags(t *testing.T) {
+	idx := NewIndexer(dbm.NewMemDB())
+	events := testEvents()
+	events[6].Result.Tags = nil
+	for _, ev := range events[:6] {
+		require.Nil(t, idx.Apply(ev))
+	}
+	assert.NotNil(t, idx.Apply(events[6]))
+	policy, _ := idx.Policy(policyAddr)
+	assert.Equal(t, int64(30), policy.TotalAmount)
+}
+
+func TestSplitResult(t *testing.T) {
+	bond := sdk.NewTags("action", []byte("bond"))
+	collect := sdk.NewTags("action", []byte("collect-claim"), mutual.TagAmount, []byte("6getx"))
+	logs := sdk.MsgLogs{
+		{MsgIndex: 0, Success: true, Log: "bonded", TagCount: 1, DataLength: 2},
+		{MsgIndex: 1, Success: true},
+		{MsgIndex: 2, Success: true, TagCount: 2, DataLength: 1},
+	}
+	result := sdk.Result{
+		Data: []byte("106"),
+		Log:  logs.String(),
+		Tags: bond.AppendTags(collect),
+	}
+
+	// the data and tags are attributed to the msgs which added them
+	results := splitResult(result, 3)
+	assert.Equal(t, sdk.Result{Data: []byte("10"), Log: "bonded", Tags: bond}, results[0])
+	assert.Equal(t, sdk.Result{}, results[1])
+	assert.Equal(t, sdk.Result{Data: []byte("6"), Tags: collect}, results[2])
+
+	// without counts, the tags of several msgs can't be attributed
+	result.Log = ""
+	results = splitResult(result, 3)
+	for _, res := range results {
+		assert.Nil(t, res.Tags)
+	}
+	assert.Equal(t, []sdk.Result{result}, splitResult(result, 1))
+
+	// the msgs of a failed tx share its result
+	result.Code = sdk.ABCICodeType(1)
+	assert.Equal(t, []sdk.Result{result, result}, splitResult(result, 2))
+}
+
+func TestListOptions(t *testing.T) {
+	idx := NewIndexer(dbm.NewMemDB())
+	for _, ev := range testEvents()[:4] {
+		require.Nil(t, idx.Apply(ev))
+	}
+
+	page, err := idx.Members(policyAddr, ListOptions{Offset: 1, Limit: 1})
+	require.Nil(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, 1, len(page.Items.([]Member)))
+	assert.Equal(t, members[1].String(), page.Items.([]Member)[0].Address.String())
+
+	page, err = idx.Members(policyAddr, ListOptions{Offset: 10})
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(page.Items.([]Member)))
+
+	_, err = idx.Policies(ListOptions{SortBy: "unknown"})
+	assert.NotNil(t, err)
+}
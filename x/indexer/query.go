@@ -0,0 +1,225 @@
+package indexer
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "inschain-tendermint/types"
+)
+
+// default and maximum page sizes
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// ListOptions control sorting and pagination of listings
+type ListOptions struct {
+	SortBy string
+	Desc   bool
+	Offset int
+	Limit  int
+}
+
+// normalize the page bounds
+func (opts ListOptions) bounds(total int) (start, end, limit int) {
+	limit = opts.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	start = opts.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end = start + limit
+	if end > total {
+		end = total
+	}
+	return
+}
+
+// Page is a page of a listing
+type Page struct {
+	Total  int         `json:"total"`
+	Offset int         `json:"offset"`
+	Limit  int         `json:"limit"`
+	Items  interface{} `json:"items"`
+}
+
+// ClaimFilter selects claims, empty fields match everything
+type ClaimFilter struct {
+	Policy   sdk.Address
+	Claimant sdk.Address
+	Status   string
+}
+
+// PayoutFilter selects payouts, empty fields match everything
+type PayoutFilter struct {
+	Policy   sdk.Address
+	Claimant sdk.Address
+}
+
+// orders on a listing, keyed by sort field
+type lessFuncs map[string]func(i, j int) bool
+
+func sortItems(n int, swap func(i, j int), less lessFuncs, opts ListOptions, defaultSort string) error {
+	sortBy := opts.SortBy
+	if sortBy == "" {
+		sortBy = defaultSort
+	}
+	lessFn, ok := less[sortBy]
+	if !ok {
+		return fmt.Errorf("cannot sort by %s", sortBy)
+	}
+	if opts.Desc {
+		lessFn = func(i, j int) bool { return less[sortBy](j, i) }
+	}
+	sort.Stable(sortable{n, swap, lessFn})
+	return nil
+}
+
+type sortable struct {
+	n    int
+	swap func(i, j int)
+	less func(i, j int) bool
+}
+
+func (s sortable) Len() int           { return s.n }
+func (s sortable) Swap(i, j int)      { s.swap(i, j) }
+func (s sortable) Less(i, j int) bool { return s.less(i, j) }
+
+func sameAddr(filter, addr sdk.Address) bool {
+	return len(filter) == 0 || filter.String() == addr.String()
+}
+
+//______________________________________________________________________________
+
+// Policy returns an indexed policy
+func (idx *Indexer) Policy(addr sdk.Address) (Policy, bool) {
+	idx.mtx.RLock()
+	defer idx.mtx.RUnlock()
+	return idx.getPolicy(addr)
+}
+
+// Policies lists the indexed policies.
+// Sort by address (default), members, total_amount or created_height.
+func (idx *Indexer) Policies(opts ListOptions) (Page, error) {
+	idx.mtx.RLock()
+	policies := []Policy{}
+	idx.iterate(policyPrefix, func(bz []byte) {
+		var policy Policy
+		mustUnmarshal(bz, &policy)
+		policies = append(policies, policy)
+	})
+	idx.mtx.RUnlock()
+
+	err := sortItems(len(policies), func(i, j int) { policies[i], policies[j] = policies[j], policies[i] }, lessFuncs{
+		"address":        func(i, j int) bool { return policies[i].Address.String() < policies[j].Address.String() },
+		"members":        func(i, j int) bool { return policies[i].Members < policies[j].Members },
+		"total_amount":   func(i, j int) bool { return policies[i].TotalAmount < policies[j].TotalAmount },
+		"created_height": func(i, j int) bool { return policies[i].CreatedHeight < policies[j].CreatedHeight },
+	}, opts, "address")
+	if err != nil {
+		return Page{}, err
+	}
+	start, end, limit := opts.bounds(len(policies))
+	return Page{len(policies), start, limit, policies[start:end]}, nil
+}
+
+// Members lists the members of a policy.
+// Sort by address (default), amount or joined_height.
+func (idx *Indexer) Members(policy sdk.Address, opts ListOptions) (Page, error) {
+	idx.mtx.RLock()
+	members := []Member{}
+	idx.iterate(membersKey(policy), func(bz []byte) {
+		var member Member
+		mustUnmarshal(bz, &member)
+		members = append(members, member)
+	})
+	idx.mtx.RUnlock()
+
+	err := sortItems(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] }, lessFuncs{
+		"address":       func(i, j int) bool { return members[i].Address.String() < members[j].Address.String() },
+		"amount":        func(i, j int) bool { return members[i].Amount < members[j].Amount },
+		"joined_height": func(i, j int) bool { return members[i].JoinedHeight < members[j].JoinedHeight },
+	}, opts, "address")
+	if err != nil {
+		return Page{}, err
+	}
+	start, end, limit := opts.bounds(len(members))
+	return Page{len(members), start, limit, members[start:end]}, nil
+}
+
+// Claims lists the claims matching the filter.
+// Sort by filed_height (default) or amount.
+func (idx *Indexer) Claims(filter ClaimFilter, opts ListOptions) (Page, error) {
+	prefix := claimPrefix
+	if len(filter.Policy) > 0 {
+		prefix = append(claimKey(filter.Policy.String()), '/')
+	}
+	idx.mtx.RLock()
+	claims := []Claim{}
+	idx.iterate(prefix, func(bz []byte) {
+		var claim Claim
+		mustUnmarshal(bz, &claim)
+		if sameAddr(filter.Claimant, claim.Claimant) &&
+			(filter.Status == "" || filter.Status == claim.Status) {
+			claims = append(claims, claim)
+		}
+	})
+	idx.mtx.RUnlock()
+
+	err := sortItems(len(claims), func(i, j int) { claims[i], claims[j] = claims[j], claims[i] }, lessFuncs{
+		"filed_height": func(i, j int) bool { return claims[i].FiledHeight < claims[j].FiledHeight },
+		"amount":       func(i, j int) bool { return claims[i].Amount < claims[j].Amount },
+	}, opts, "filed_height")
+	if err != nil {
+		return Page{}, err
+	}
+	start, end, limit := opts.bounds(len(claims))
+	return Page{len(claims), start, limit, claims[start:end]}, nil
+}
+
+// Payouts lists the payouts matching the filter.
+// Sort by height (default) or amount.
+func (idx *Indexer) Payouts(filter PayoutFilter, opts ListOptions) (Page, error) {
+	prefix := payoutPrefix
+	if len(filter.Policy) > 0 {
+		prefix = append(payoutKey(filter.Policy.String()), '/')
+	}
+	idx.mtx.RLock()
+	payouts := []Payout{}
+	idx.iterate(prefix, func(bz []byte) {
+		var payout Payout
+		mustUnmarshal(bz, &payout)
+		if sameAddr(filter.Claimant, payout.Claimant) {
+			payouts = append(payouts, payout)
+		}
+	})
+	idx.mtx.RUnlock()
+
+	err := sortItems(len(payouts), func(i, j int) { payouts[i], payouts[j] = payouts[j], payouts[i] }, lessFuncs{
+		"height": func(i, j int) bool { return payouts[i].Height < payouts[j].Height },
+		"amount": func(i, j int) bool { return payouts[i].Amount < payouts[j].Amount },
+	}, opts, "height")
+	if err != nil {
+		return Page{}, err
+	}
+	start, end, limit := opts.bounds(len(payouts))
+	return Page{len(payouts), start, limit, payouts[start:end]}, nil
+}
+
+// iterate over the values under a prefix
+func (idx *Indexer) iterate(prefix []byte, process func(bz []byte)) {
+	iter := idx.db.Iterator(prefix, sdk.PrefixEndBytes(prefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		process(iter.Value())
+	}
+}
@@ -0,0 +1,168 @@
+package indexer
+
+import (
+	"fmt"
+
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/tmlibs/log"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+
+	bam "inschain-tendermint/baseapp"
+)
+
+// Replayer feeds the committed blocks of a node to an Indexer.
+// It is used to rebuild the index from genesis and to catch up after a restart.
+type Replayer struct {
+	idx    *Indexer
+	node   rpcclient.Client
+	cdc    *wire.Codec
+	logger log.Logger
+}
+
+// NewReplayer returns a Replayer reading blocks from the node
+func NewReplayer(idx *Indexer, node rpcclient.Client, cdc *wire.Codec, logger log.Logger) Replayer {
+	return Replayer{
+		idx:    idx,
+		node:   node,
+		cdc:    cdc,
+		logger: logger,
+	}
+}
+
+// LatestHeight returns the height of the latest block of the node
+func (r Replayer) LatestHeight() (int64, error) {
+	status, err := r.node.Status()
+	if err != nil {
+		return 0, err
+	}
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// SyncTo applies the blocks following the last fully indexed one up to the height.
+// Transactions already applied from live events are skipped by the indexer.
+func (r Replayer) SyncTo(height int64) error {
+	for h := r.idx.SyncedHeight() + 1; h <= height; h++ {
+		err := r.ApplyBlock(h)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyBlock applies the transactions of a committed block
+func (r Replayer) ApplyBlock(height int64) error {
+	events, err := BlockEvents(r.node, r.cdc, height)
+	if err != nil {
+		return err
+	}
+	for _, ev := range events {
+		err = r.idx.Apply(ev)
+		if err != nil {
+			return err
+		}
+	}
+	r.idx.MarkSynced(height)
+	r.logger.Debug("Indexed block", "height", height, "events", len(events))
+	return nil
+}
+
+// BlockEvents reads the events of a committed block from the node, with the
+// same results as the events of the bus: the data and tags of a successful
+// transaction are split between its messages according to its logs.
+func BlockEvents(node rpcclient.Client, cdc *wire.Codec, height int64) ([]bam.TxEvent, error) {
+	block, err := node.Block(&height)
+	if err != nil {
+		return nil, err
+	}
+	results, err := node.BlockResults(&height)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := block.Block.Data.Txs
+	if len(txs) != len(results.Results.DeliverTx) {
+		return nil, fmt.Errorf("block %d has %d txs but %d results", height, len(txs), len(results.Results.DeliverTx))
+	}
+	var events []bam.TxEvent
+	for i, txBytes := range txs {
+		var tx sdk.StdTx
+		err := cdc.UnmarshalBinary(txBytes, &tx)
+		if err != nil {
+			// undecodable txs were rejected by the app
+			continue
+		}
+		res := results.Results.DeliverTx[i]
+		result := sdk.Result{
+			Code: sdk.ABCICodeType(res.Code),
+			Data: res.Data,
+			Log:  res.Log,
+			Tags: res.Tags,
+		}
+		msgs := tx.GetMsgs()
+		msgResults := splitResult(result, len(msgs))
+		for j, msg := range msgs {
+			events = append(events, bam.TxEvent{
+				Height:   height,
+				TxIndex:  i,
+				MsgIndex: j,
+				MsgType:  bam.MsgTypeName(msg),
+				Module:   msg.Type(),
+				Msg:      msg,
+				Result:   msgResults[j],
+			})
+		}
+	}
+	return events, nil
+}
+
+// splitResult returns the result of each msg of a transaction. Every msg of
+// a failed transaction shares its result. The data and tags of a successful
+// one are attributed with the counts of the msg logs; when its log doesn't
+// carry them, they go to a single msg, and several msgs are left without.
+func splitResult(result sdk.Result, numMsgs int) []sdk.Result {
+	results := make([]sdk.Result, numMsgs)
+	if !result.IsOK() {
+		for i := range results {
+			results[i] = result
+		}
+		return results
+	}
+
+	logs, err := sdk.ParseMsgLogs(result.Log)
+	if err != nil || len(logs) != numMsgs || !countsMatch(logs, result) {
+		if numMsgs == 1 {
+			results[0] = result
+			return results
+		}
+		for i := range results {
+			results[i] = sdk.Result{Code: result.Code}
+		}
+		return results
+	}
+	tags, data := result.Tags, result.Data
+	for i, log := range logs {
+		results[i] = sdk.Result{Code: result.Code, Log: log.Log}
+		if log.TagCount > 0 {
+			results[i].Tags = tags[:log.TagCount]
+			tags = tags[log.TagCount:]
+		}
+		if log.DataLength > 0 {
+			results[i].Data = data[:log.DataLength]
+			data = data[log.DataLength:]
+		}
+	}
+	return results
+}
+
+// countsMatch checks that the logs account for all the tags and data
+func countsMatch(logs sdk.MsgLogs, result sdk.Result) bool {
+	tags, data := 0, 0
+	for _, log := range logs {
+		tags += log.TagCount
+		data += log.DataLength
+	}
+	return tags == len(result.Tags) && data == len(result.Data)
+}
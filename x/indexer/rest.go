@@ -0,0 +1,194 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	sdk "inschain-tendermint/types"
+)
+
+// RegisterRoutes registers the query API of the indexer
+func RegisterRoutes(r *mux.Router, idx *Indexer) {
+	r.HandleFunc("/indexer/status", StatusHandlerFn(idx)).Methods("GET")
+	r.HandleFunc("/indexer/policies", PoliciesHandlerFn(idx)).Methods("GET")
+	r.HandleFunc("/indexer/policies/{policy}", PolicyHandlerFn(idx)).Methods("GET")
+	r.HandleFunc("/indexer/policies/{policy}/members", MembersHandlerFn(idx)).Methods("GET")
+	r.HandleFunc("/indexer/claims", ClaimsHandlerFn(idx)).Methods("GET")
+	r.HandleFunc("/indexer/payouts", PayoutsHandlerFn(idx)).Methods("GET")
+}
+
+// StatusHandlerFn - http request handler to query the last indexed position
+func StatusHandlerFn(idx *Indexer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, idx.Cursor())
+	}
+}
+
+// PoliciesHandlerFn - http request handler to list policies
+func PoliciesHandlerFn(idx *Indexer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		opts, err := parseListOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		page, err := idx.Policies(opts)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, page)
+	}
+}
+
+// PolicyHandlerFn - http request handler to query a policy
+func PolicyHandlerFn(idx *Indexer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policyAddr, err := parseAddress(mux.Vars(r)["policy"])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		policy, ok := idx.Policy(policyAddr)
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, policy)
+	}
+}
+
+// MembersHandlerFn - http request handler to list the members of a policy
+func MembersHandlerFn(idx *Indexer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policyAddr, err := parseAddress(mux.Vars(r)["policy"])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		opts, err := parseListOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		page, err := idx.Members(policyAddr, opts)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, page)
+	}
+}
+
+// ClaimsHandlerFn - http request handler to list claims,
+// filtered by the policy, claimant and status query parameters
+func ClaimsHandlerFn(idx *Indexer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filter ClaimFilter
+		var err error
+		filter.Policy, err = parseOptionalAddress(r.FormValue("policy"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		filter.Claimant, err = parseOptionalAddress(r.FormValue("claimant"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		filter.Status = r.FormValue("status")
+		opts, err := parseListOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		page, err := idx.Claims(filter, opts)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, page)
+	}
+}
+
+// PayoutsHandlerFn - http request handler to list payouts,
+// filtered by the policy and claimant query parameters
+func PayoutsHandlerFn(idx *Indexer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var filter PayoutFilter
+		var err error
+		filter.Policy, err = parseOptionalAddress(r.FormValue("policy"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		filter.Claimant, err = parseOptionalAddress(r.FormValue("claimant"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		opts, err := parseListOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		page, err := idx.Payouts(filter, opts)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, page)
+	}
+}
+
+//______________________________________________________________________________
+
+// read the sort, order, offset and limit query parameters
+func parseListOptions(r *http.Request) (opts ListOptions, err error) {
+	opts.SortBy = r.FormValue("sort")
+	opts.Desc = r.FormValue("order") == "desc"
+	if offset := r.FormValue("offset"); offset != "" {
+		opts.Offset, err = strconv.Atoi(offset)
+		if err != nil {
+			return
+		}
+	}
+	if limit := r.FormValue("limit"); limit != "" {
+		opts.Limit, err = strconv.Atoi(limit)
+	}
+	return
+}
+
+func parseAddress(s string) (sdk.Address, error) {
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.Address(bz), nil
+}
+
+func parseOptionalAddress(s string) (sdk.Address, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return parseAddress(s)
+}
+
+func writeJSON(w http.ResponseWriter, o interface{}) {
+	output, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(output)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	w.Write([]byte(err.Error()))
+}
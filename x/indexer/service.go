@@ -0,0 +1,147 @@
+package indexer
+
+import (
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	tmserver "github.com/tendermint/tendermint/rpc/lib/server"
+	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/tendermint/tmlibs/log"
+
+	"inschain-tendermint/wire"
+
+	bam "inschain-tendermint/baseapp"
+)
+
+const (
+	// name of the indexer database
+	DBName = "indexer"
+
+	// name of the embedded indexer on the event bus
+	SubscriberName = "indexer"
+
+	// delay between two attempts to reach the node
+	retryInterval = time.Second
+)
+
+// OpenDB opens the indexer database in the directory
+func OpenDB(dir string) (dbm.DB, error) {
+	return dbm.NewGoLevelDB(DBName, dir)
+}
+
+// Embed runs the indexer inside the application process. Committed events
+// are taken from the event bus of the app; the blocks committed while the
+// indexer was not running are first replayed from the local node, and the
+// live events are skipped until then. The query API is served on listenAddr.
+func Embed(app *bam.BaseApp, cdc *wire.Codec, db dbm.DB, nodeURI, listenAddr string, logger log.Logger) (*Indexer, error) {
+	idx := NewIndexer(db)
+	f := &follower{
+		idx:      idx,
+		replayer: NewReplayer(idx, rpcclient.NewHTTP(nodeURI, "/websocket"), cdc, logger),
+		logger:   logger,
+	}
+
+	// the indexer must see every event, a full buffer holds the app back
+	err := app.EventBus().SubscribeBlocking(SubscriberName, bam.EventFilter{}, 0, f.handle)
+	if err != nil {
+		return nil, err
+	}
+
+	// the node is started after the app, catch up in the background
+	go f.catchUp()
+
+	_, err = Serve(idx, listenAddr, logger)
+	if err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// follower applies the live events of the app to the indexer. The blocks
+// it can't account for, before the first live event and after a failed one,
+// are replayed from the node.
+type follower struct {
+	idx      *Indexer
+	replayer Replayer
+	logger   log.Logger
+
+	// set once the blocks committed before the start are indexed
+	ready int32
+
+	// whether every event since the last replay has been applied.
+	// only accessed from the subscriber goroutine.
+	following bool
+}
+
+// replay the blocks of the node until the latest one
+func (f *follower) catchUp() {
+	for {
+		height, err := f.replayer.LatestHeight()
+		if err == nil {
+			err = f.replayer.SyncTo(height)
+		}
+		if err == nil {
+			break
+		}
+		f.logger.Info("Waiting for node to catch up the index", "err", err)
+		time.Sleep(retryInterval)
+	}
+	atomic.StoreInt32(&f.ready, 1)
+}
+
+func (f *follower) handle(ev bam.TxEvent) error {
+	if atomic.LoadInt32(&f.ready) == 0 {
+		// the block is replayed from the node once caught up
+		return nil
+	}
+	if !f.following {
+		// replay the blocks committed since the last applied event, up to
+		// the block of the event: the node saves the block and its results
+		// before the app commits it
+		err := f.replayer.SyncTo(ev.Height)
+		if err != nil {
+			return fmt.Errorf("couldn't replay the index up to height %d: %v", ev.Height, err)
+		}
+		f.following = true
+	}
+
+	err := f.idx.Apply(ev)
+	if err != nil {
+		// the block is replayed from the node with the next event
+		f.following = false
+		return err
+	}
+	// no event is dropped, the previous blocks are complete
+	f.idx.MarkSynced(ev.Height - 1)
+	return nil
+}
+
+// Follow keeps the indexer in sync with a node from a separate process,
+// polling for new blocks until quit is closed. A nil quit runs forever.
+func Follow(replayer Replayer, interval time.Duration, quit <-chan struct{}) error {
+	for {
+		height, err := replayer.LatestHeight()
+		if err != nil {
+			replayer.logger.Error("Couldn't get the node height", "err", err)
+		} else if err := replayer.SyncTo(height); err != nil {
+			return err
+		}
+
+		select {
+		case <-quit:
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Serve starts the REST query API of the indexer
+func Serve(idx *Indexer, listenAddr string, logger log.Logger) (net.Listener, error) {
+	r := mux.NewRouter()
+	RegisterRoutes(r, idx)
+	return tmserver.StartHTTPServer(listenAddr, r, logger)
+}
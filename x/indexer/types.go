@@ -0,0 +1,75 @@
+package indexer
+
+import (
+	sdk "inschain-tendermint/types"
+)
+
+// claim status values
+const (
+	ClaimProposed  = "proposed"
+	ClaimApproved  = "approved"
+	ClaimRejected  = "rejected"
+	ClaimCollected = "collected"
+)
+
+// Policy is the indexed state of a mutual policy
+type Policy struct {
+	Address       sdk.Address `json:"address"`
+	Members       int32       `json:"members"`
+	TotalAmount   int64       `json:"total_amount"`
+	Locked        bool        `json:"locked"`
+	OpenClaim     string      `json:"open_claim,omitempty"` // id of the claim being processed
+	CreatedHeight int64       `json:"created_height"`
+	UpdatedHeight int64       `json:"updated_height"`
+}
+
+// Member is the indexed bond of a member in a policy
+type Member struct {
+	Policy        sdk.Address `json:"policy"`
+	Address       sdk.Address `json:"address"`
+	Amount        int64       `json:"amount"`
+	JoinedHeight  int64       `json:"joined_height"`
+	UpdatedHeight int64       `json:"updated_height"`
+}
+
+// Claim is a claim filed against a policy
+type Claim struct {
+	ID            string      `json:"id"`
+	Policy        sdk.Address `json:"policy"`
+	Claimant      sdk.Address `json:"claimant"`
+	Amount        int64       `json:"amount"`
+	Status        string      `json:"status"`
+	FiledHeight   int64       `json:"filed_height"`
+	UpdatedHeight int64       `json:"updated_height"`
+}
+
+// Payout is the collection of an approved claim
+type Payout struct {
+	ID        string      `json:"id"`
+	ClaimID   string      `json:"claim_id"`
+	Policy    sdk.Address `json:"policy"`
+	Claimant  sdk.Address `json:"claimant"`
+	Amount    int64       `json:"amount"`     // total paid to the claimant
+	PerMember int64       `json:"per_member"` // amount deducted from each other member
+	Members   int         `json:"members"`    // number of members charged
+	Timestamp string      `json:"timestamp"`
+	Height    int64       `json:"height"`
+}
+
+// Cursor is the position of the last applied message
+type Cursor struct {
+	Height   int64 `json:"height"`
+	TxIndex  int   `json:"tx_index"`
+	MsgIndex int   `json:"msg_index"`
+}
+
+// After returns whether the position is strictly after the cursor
+func (c Cursor) After(height int64, txIndex, msgIndex int) bool {
+	if height != c.Height {
+		return height > c.Height
+	}
+	if txIndex != c.TxIndex {
+		return txIndex > c.TxIndex
+	}
+	return msgIndex > c.MsgIndex
+}
@@ -13,11 +13,11 @@ import (
 
 	//crypto "github.com/tendermint/go-crypto"
 
-	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	authcmd "inschain-tendermint/x/auth/client/cli"
 	//keyc "github.com/tendermint/go-crypto/keys"
 	//keys "github.com/cosmos/cosmos-sdk/client/keys"
 	
@@ -32,6 +32,8 @@ const (
 	flagApproval = "approval"
 	flagUnlocked = "unlocked"
 	flagFileName = "file"
+	flagVestingStart = "vesting-start"
+	flagVestingEnd = "vesting-end"
 )
 
 // AddCommands adds mutual subcommands
@@ -49,11 +51,13 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 		)...)
 	cmd.AddCommand(
 		client.GetCommands(
-			GetPolicyInfoCmd("stake", cdc),
-			GetBondInfoCmd("stake", cdc),
-			GetPolicyParticipantsCmd("stake", cdc),
-			GetClaimTxsCmd("stake", cdc),
-			GetParticipantClaimTxCmd("stake", cdc),
+			GetPolicyInfoCmd("mutual", cdc),
+			GetBondInfoCmd("mutual", cdc),
+			GetPolicyParticipantsCmd("mutual", cdc),
+			GetClaimsCmd("mutual", cdc),
+			GetClaimTxsCmd("mutual", cdc),
+			GetParticipantClaimTxCmd("mutual", cdc),
+			GetParamsCmd("mutual", cdc),
 		)...)
 }
 
@@ -145,6 +149,8 @@ func AirdropCmd(cdc *wire.Codec) *cobra.Command {
 		RunE:  cmdr.airdropCmd,
 	}
 	cmd.Flags().String(flagFileName, "", "File with path")
+	cmd.Flags().Int64(flagVestingStart, 0, "Unix time the airdropped coins start vesting linearly, omit to vest them at once at the end")
+	cmd.Flags().Int64(flagVestingEnd, 0, "Unix time the airdropped coins are vested, omit to credit them unlocked")
 	return cmd
 }
 
@@ -411,7 +417,11 @@ func (co commander) airdropCmd(cmd *cobra.Command, args []string) error {
 	fmt.Println(count)
 	fmt.Println(totalCoin)
 
-	msg := mutual.NewMutualAirdropMsg(from, targets, totalCoin)
+	msg := mutual.NewMutualVestingAirdropMsg(from, targets, totalCoin,
+		viper.GetInt64(flagVestingStart), viper.GetInt64(flagVestingEnd))
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
 
 	//fmt.Println(msg)
 
@@ -420,11 +430,5 @@ func (co commander) airdropCmd(cmd *cobra.Command, args []string) error {
 
 func (co commander) sendMsg(msg sdk.Msg) error {
 	ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(co.cdc))
-	res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, co.cdc)
-	if err != nil {
-		return err
-	}
-
-	fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
-	return nil
+	return ctx.SendMsg(msg, co.cdc)
 }
@@ -2,7 +2,6 @@ package commands
 
 import (
 	//"encoding/hex"
-	"fmt"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -10,15 +9,20 @@ import (
 	//crypto "github.com/tendermint/go-crypto"
 
 	//"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 	"inschain-tendermint/client/context"
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/wire"
 	
 	"inschain-tendermint/x/mutual"
 )
 
-// get the command to query a candidate
-func GetPolicyInfoCmd(storeName string, cdc *wire.Codec) *cobra.Command {
+const (
+	flagStart = "start"
+	flagLimit = "limit"
+)
+
+// get the command to query a policy
+func GetPolicyInfoCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "policyInfo",
 		Short: "Query a policy",
@@ -29,39 +33,17 @@ func GetPolicyInfoCmd(storeName string, cdc *wire.Codec) *cobra.Command {
 				return err
 			}
 
-			key := mutual.GetPolicyKey(addr)
-
-			ctx := context.NewCoreContextFromViper()
-
-			res, err := ctx.Query(key, storeName)
-			if err != nil {
-				return err
-			}
-
-			// parse out the policy
-			policy := new(mutual.PolicyInfo)
-			err = cdc.UnmarshalJSON(res, policy)
-			if err != nil {
-				return err
-			}
-			output, err := wire.MarshalJSONIndent(cdc, policy)
-			if err != nil {
-				return err
-			}
-			fmt.Println(string(output))
-			return nil
-
-			// TODO output with proofs / machine parseable etc.
+			params := mutual.QueryPolicyParams{PolicyAddr: addr}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, mutual.QueryPolicy, params, new(mutual.PolicyInfo), cdc)
 		},
 	}
 
 	cmd.Flags().String(flagPolicy, "", "Policy address")
-	//cmd.Flags().AddFlagSet(fsCandidate)
 	return cmd
 }
 
 // get the command to query a member bond
-func GetBondInfoCmd(storeName string, cdc *wire.Codec) *cobra.Command {
+func GetBondInfoCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "policy-bondinfo",
 		Short: "Query a member bond",
@@ -77,45 +59,21 @@ func GetBondInfoCmd(storeName string, cdc *wire.Codec) *cobra.Command {
 				return err
 			}
 
-			key := mutual.GetPolicyMemberKey(addr, memberaddr)
-
-			ctx := context.NewCoreContextFromViper()
-
-			res, err := ctx.Query(key, storeName)
-			if err != nil {
-				return err
-			}
-
-			// parse out the bond
-			bond := new(mutual.BondInfo)
-			err = cdc.UnmarshalJSON(res, bond)
-			if err != nil {
-				return err
-			}
-			output, err := wire.MarshalJSONIndent(cdc, bond)
-			if err != nil {
-				return err
-			}
-			fmt.Println(string(output))
-			return nil
-
-			// TODO output with proofs / machine parseable etc.
+			params := mutual.QueryMemberParams{PolicyAddr: addr, MemberAddr: memberaddr}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, mutual.QueryMember, params, new(mutual.BondInfo), cdc)
 		},
 	}
 	
 	cmd.Flags().String(flagPolicy, "", "Policy address")
 	cmd.Flags().String(flagMember, "", "Member address")
-
-//	cmd.Flags().AddFlagSet(fsCandidate)
-//	cmd.Flags().AddFlagSet(fsDelegator)
 	return cmd
 }
 
-// get the command to query all participants for a policy
-func GetPolicyParticipantsCmd(storeName string, cdc *wire.Codec) *cobra.Command {
+// get the command to query a page of the participants of a policy
+func GetPolicyParticipantsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "participants",
-		Short: "Query all participants for a givien policy",
+		Short: "Query the participants of a given policy, page by page",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			addr, err := sdk.GetAddress(viper.GetString(flagPolicy))
@@ -123,32 +81,42 @@ func GetPolicyParticipantsCmd(storeName string, cdc *wire.Codec) *cobra.Command
 				return err
 			}
 
-			key := mutual.GetPolicyParticipantsKey(addr)
-			ctx := context.NewCoreContextFromViper()
-			resKVs, err := ctx.QuerySubspace(cdc, key, storeName)
-			if err != nil {
-				return err
-			}
-
-			// parse out the participants
-			var participants []mutual.BondInfo
-			for _, kv := range resKVs {
-				var participant mutual.BondInfo
-				err = cdc.UnmarshalJSON(kv.Value, &participant)
+			var start sdk.Address
+			if viper.GetString(flagStart) != "" {
+				start, err = sdk.GetAddress(viper.GetString(flagStart))
 				if err != nil {
 					return err
 				}
-				participants = append(participants, participant)
 			}
 
-			output, err := wire.MarshalJSONIndent(cdc, participants)
+			params := mutual.QueryMembersParams{
+				PolicyAddr: addr,
+				Start:      start,
+				Limit:      viper.GetInt(flagLimit),
+			}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, mutual.QueryMembers, params, new(mutual.MembersPage), cdc)
+		},
+	}
+	cmd.Flags().String(flagPolicy, "", "Policy address")
+	cmd.Flags().String(flagStart, "", "Participant address to start the page from, the next of the previous page")
+	cmd.Flags().Int(flagLimit, 100, "Maximum number of participants to return")
+	return cmd
+}
+
+// get the command to query the open and collected claims of a policy
+func GetClaimsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claims",
+		Short: "Query the open and collected claims of a policy",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			addr, err := sdk.GetAddress(viper.GetString(flagPolicy))
 			if err != nil {
 				return err
 			}
-			fmt.Println(string(output))
-			return nil
 
-			// TODO output with proofs / machine parseable etc.
+			params := mutual.QueryPolicyParams{PolicyAddr: addr}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, mutual.QueryClaims, params, &[]mutual.Claim{}, cdc)
 		},
 	}
 	cmd.Flags().String(flagPolicy, "", "Policy address")
@@ -156,7 +124,7 @@ func GetPolicyParticipantsCmd(storeName string, cdc *wire.Codec) *cobra.Command
 }
 
 // get the command to query all transaction for a claim
-func GetClaimTxsCmd(storeName string, cdc *wire.Codec) *cobra.Command {
+func GetClaimTxsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "claimTxs",
 		Short: "Query all transaction for a claim",
@@ -172,32 +140,8 @@ func GetClaimTxsCmd(storeName string, cdc *wire.Codec) *cobra.Command {
 				return err
 			}
 
-			key := mutual.GetClaimTxsKey(addr, claimAddr)
-			ctx := context.NewCoreContextFromViper()
-			resKVs, err := ctx.QuerySubspace(cdc, key, storeName)
-			if err != nil {
-				return err
-			}
-
-			// parse out the transaction
-			var transaction []mutual.ClaimTransaction
-			for _, kv := range resKVs {
-				var tx mutual.ClaimTransaction
-				err = cdc.UnmarshalJSON(kv.Value, &tx)
-				if err != nil {
-					return err
-				}
-				transaction = append(transaction, tx)
-			}
-
-			output, err := wire.MarshalJSONIndent(cdc, transaction)
-			if err != nil {
-				return err
-			}
-			fmt.Println(string(output))
-			return nil
-
-			// TODO output with proofs / machine parseable etc.
+			params := mutual.QueryClaimTxsParams{PolicyAddr: addr, ClaimAddr: claimAddr}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, mutual.QueryClaimTxs, params, &[]mutual.ClaimTransaction{}, cdc)
 		},
 	}
 	cmd.Flags().String(flagPolicy, "", "Policy address")
@@ -207,7 +151,7 @@ func GetClaimTxsCmd(storeName string, cdc *wire.Codec) *cobra.Command {
 }
 
 // get the command to query participant transaction for a claim
-func GetParticipantClaimTxCmd(storeName string, cdc *wire.Codec) *cobra.Command {
+func GetParticipantClaimTxCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "claimTx",
 		Short: "Query transaction for a claim",
@@ -229,31 +173,8 @@ func GetParticipantClaimTxCmd(storeName string, cdc *wire.Codec) *cobra.Command
 				return err
 			}
 
-			key := mutual.GetClaimTxKey(addr, claimAddr, participantAddr)
-			resKVs, err := ctx.QuerySubspace(cdc, key, storeName)
-			if err != nil {
-				return err
-			}
-
-			// parse out the transaction
-			var transaction []mutual.ClaimTransaction
-			for _, kv := range resKVs {
-				var tx mutual.ClaimTransaction
-				err = cdc.UnmarshalJSON(kv.Value, &tx)
-				if err != nil {
-					return err
-				}
-				transaction = append(transaction, tx)
-			}
-
-			output, err := wire.MarshalJSONIndent(cdc, transaction)
-			if err != nil {
-				return err
-			}
-			fmt.Println(string(output))
-			return nil
-
-			// TODO output with proofs / machine parseable etc.
+			params := mutual.QueryClaimTxsParams{PolicyAddr: addr, ClaimAddr: claimAddr, MemberAddr: participantAddr}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, mutual.QueryClaimTxs, params, &[]mutual.ClaimTransaction{}, cdc)
 		},
 	}
 	cmd.Flags().String(flagPolicy, "", "Policy address")
@@ -262,6 +183,17 @@ func GetParticipantClaimTxCmd(storeName string, cdc *wire.Codec) *cobra.Command
 	return cmd
 }
 
+// get the command to query the parameters of the mutual module
+func GetParamsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "params",
+		Short: "Query the parameters of the mutual module",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, mutual.QueryParams, struct{}{}, new(mutual.Params), cdc)
+		},
+	}
+}
+
 //// get the command to query all the candidates bonded to a delegator
 //func GetCmdQueryDelegatorBonds(storeName string, cdc *wire.Codec) *cobra.Command {
 //cmd := &cobra.Command{
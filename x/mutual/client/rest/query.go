@@ -4,130 +4,148 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/tendermint/go-crypto/keys"
 
-	"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 	"inschain-tendermint/x/mutual"
 )
 
-// RegisterRoutes - Central function to define routes that get registered by the main application
-//func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, kb keys.Keybase) {
-//	r.HandleFunc("/mutual/{policy}", PolicyStatusHandlerFn("mutual", cdc, kb, ctx)).Methods("GET")
-//}
+// query the mutual querier with the params and write its JSON result
+func queryMutual(w http.ResponseWriter, ctx context.CoreContext, cdc *wire.Codec, queryRoute, route string, params interface{}) {
+	data, err := cdc.MarshalJSON(params)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
 
-// PolicyStatusHandlerFn - http request handler to query policy status
-func PolicyStatusHandlerFn(storeName string, cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// read parameters
-		vars := mux.Vars(r)
-		policy := vars["policy"]
-		//validator := vars["validator"]
-		bz, err := hex.DecodeString(policy)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
-			return
-		}
-		policyAddr := sdk.Address(bz)
-/*
-		bz, err = hex.DecodeString(validator)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
-			return
-		}
-		candidateAddr := sdk.Address(bz)
-*/
-		key := mutual.GetPolicyKey(policyAddr)
-		res, err := ctx.Query(key, storeName)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf("Couldn't query policy. Error: %s", err.Error())))
-			return
-		}
+	res, err := ctx.QueryCustom(queryRoute, route, data)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf("Couldn't query %s. Error: %s", route, err.Error())))
+		return
+	}
 
-		// the query will return empty if there is no data for this policy
-		if len(res) == 0 {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
+	w.Write(res)
+}
 
-		var policyInfo mutual.PolicyInfo
-		err = cdc.UnmarshalJSON(res, &policyInfo)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf("Couldn't decode policy. Error: %s", err.Error())))
-			return
-		}
+// read a hex encoded address from the path variables
+func addressVar(w http.ResponseWriter, r *http.Request, name string) (sdk.Address, bool) {
+	bz, err := hex.DecodeString(mux.Vars(r)[name])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return nil, false
+	}
+	return sdk.Address(bz), true
+}
 
-		output, err := cdc.MarshalJSON(policyInfo)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
+// PolicyStatusHandlerFn - http request handler to query policy status
+func PolicyStatusHandlerFn(queryRoute string, cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policyAddr, ok := addressVar(w, r, "policy")
+		if !ok {
 			return
 		}
-
-		w.Write(output)
+		queryMutual(w, ctx, cdc, queryRoute, mutual.QueryPolicy, mutual.QueryPolicyParams{PolicyAddr: policyAddr})
 	}
 }
 
 // PolicyBondStatusHandlerFn - http request handler to query policy bond status
-func PolicyBondStatusHandlerFn(storeName string, cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+func PolicyBondStatusHandlerFn(queryRoute string, cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// read parameters
-		vars := mux.Vars(r)
-		policy := vars["policy"]
-		participant := vars["participant"]
-
-		bz, err := hex.DecodeString(policy)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+		policyAddr, ok := addressVar(w, r, "policy")
+		if !ok {
 			return
 		}
-		policyAddr := sdk.Address(bz)
-
-		bz, err = hex.DecodeString(participant)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+		participantAddr, ok := addressVar(w, r, "participant")
+		if !ok {
 			return
 		}
-		participantAddr := sdk.Address(bz)
+		params := mutual.QueryMemberParams{PolicyAddr: policyAddr, MemberAddr: participantAddr}
+		queryMutual(w, ctx, cdc, queryRoute, mutual.QueryMember, params)
+	}
+}
 
-		key := mutual.GetPolicyMemberKey(policyAddr, participantAddr)
-		res, err := ctx.Query(key, storeName)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf("Couldn't query policy bond. Error: %s", err.Error())))
+// PolicyMembersHandlerFn - http request handler to query a page of the members of a policy,
+// from the optional start member and up to the optional limit
+func PolicyMembersHandlerFn(queryRoute string, cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policyAddr, ok := addressVar(w, r, "policy")
+		if !ok {
 			return
 		}
+		params := mutual.QueryMembersParams{PolicyAddr: policyAddr}
+
+		query := r.URL.Query()
+		if start := query.Get("start"); start != "" {
+			bz, err := hex.DecodeString(start)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			params.Start = sdk.Address(bz)
+		}
+		if limit := query.Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			params.Limit = n
+		}
+		queryMutual(w, ctx, cdc, queryRoute, mutual.QueryMembers, params)
+	}
+}
 
-		// the query will return empty if there is no data for this policy
-		if len(res) == 0 {
-			w.WriteHeader(http.StatusNoContent)
+// PolicyClaimsHandlerFn - http request handler to query the open and collected claims of a policy
+func PolicyClaimsHandlerFn(queryRoute string, cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policyAddr, ok := addressVar(w, r, "policy")
+		if !ok {
 			return
 		}
+		queryMutual(w, ctx, cdc, queryRoute, mutual.QueryClaims, mutual.QueryPolicyParams{PolicyAddr: policyAddr})
+	}
+}
 
-		var bondInfo mutual.BondInfo
-		err = cdc.UnmarshalJSON(res, &bondInfo)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf("Couldn't decode policy bond. Error: %s", err.Error())))
+// ClaimTxsHandlerFn - http request handler to query the transactions of a claim,
+// of every member or of the optional member
+func ClaimTxsHandlerFn(queryRoute string, cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policyAddr, ok := addressVar(w, r, "policy")
+		if !ok {
 			return
 		}
-
-		output, err := cdc.MarshalJSON(bondInfo)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
+		claimAddr, ok := addressVar(w, r, "claim")
+		if !ok {
 			return
 		}
+		params := mutual.QueryClaimTxsParams{PolicyAddr: policyAddr, ClaimAddr: claimAddr}
+
+		if member := r.URL.Query().Get("member"); member != "" {
+			bz, err := hex.DecodeString(member)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			params.MemberAddr = sdk.Address(bz)
+		}
+		queryMutual(w, ctx, cdc, queryRoute, mutual.QueryClaimTxs, params)
+	}
+}
 
-		w.Write(output)
+// ParamsHandlerFn - http request handler to query the parameters of the mutual module
+func ParamsHandlerFn(queryRoute string, cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queryMutual(w, ctx, cdc, queryRoute, mutual.QueryParams, struct{}{})
 	}
 }
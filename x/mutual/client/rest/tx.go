@@ -8,9 +8,9 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/tendermint/go-crypto/keys"
 
-	"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 
 	"inschain-tendermint/x/mutual"
 )
@@ -21,8 +21,12 @@ func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, kb
 	r.HandleFunc("/mutual/{policy}/{participant}/join", JoinPolicyRequestHandlerFn(cdc, kb, ctx)).Methods("POST")
 	r.HandleFunc("/mutual/{policy}/{participant}/propose", ProposalRequestHandlerFn(cdc, kb, ctx)).Methods("POST")
 	r.HandleFunc("/mutual/{policy}/{participant}/quit", QuitPolicyRequestHandlerFn(cdc, kb, ctx)).Methods("POST")
-	r.HandleFunc("/mutual/{policy}", PolicyStatusHandlerFn("stake", cdc, kb, ctx)).Methods("GET")
-	r.HandleFunc("/mutual/{policy}/{participant}", PolicyBondStatusHandlerFn("stake", cdc, kb, ctx)).Methods("GET")
+	r.HandleFunc("/mutual/params", ParamsHandlerFn("mutual", cdc, kb, ctx)).Methods("GET")
+	r.HandleFunc("/mutual/{policy}", PolicyStatusHandlerFn("mutual", cdc, kb, ctx)).Methods("GET")
+	r.HandleFunc("/mutual/{policy}/members", PolicyMembersHandlerFn("mutual", cdc, kb, ctx)).Methods("GET")
+	r.HandleFunc("/mutual/{policy}/claims", PolicyClaimsHandlerFn("mutual", cdc, kb, ctx)).Methods("GET")
+	r.HandleFunc("/mutual/{policy}/claims/{claim}/txs", ClaimTxsHandlerFn("mutual", cdc, kb, ctx)).Methods("GET")
+	r.HandleFunc("/mutual/{policy}/{participant}", PolicyBondStatusHandlerFn("mutual", cdc, kb, ctx)).Methods("GET")
 }
 
 type newPolicyBody struct {
@@ -69,7 +73,7 @@ func NewPolicyRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.Cor
 
 		// sign
 		ctx = ctx.WithSequence(m.Sequence)
-		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, msg, cdc)
+		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, []sdk.Msg{msg}, cdc)
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(err.Error()))
@@ -138,7 +142,7 @@ func JoinPolicyRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.Co
 
 		// sign
 		ctx = ctx.WithSequence(m.Sequence)
-		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, msg, cdc)
+		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, []sdk.Msg{msg}, cdc)
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(err.Error()))
@@ -207,7 +211,7 @@ func ProposalRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.Core
 
 		// sign
 		ctx = ctx.WithSequence(m.Sequence)
-		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, msg, cdc)
+		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, []sdk.Msg{msg}, cdc)
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(err.Error()))
@@ -276,7 +280,7 @@ func QuitPolicyRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.Co
 
 		// sign
 		ctx = ctx.WithSequence(m.Sequence)
-		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, msg, cdc)
+		txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, []sdk.Msg{msg}, cdc)
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(err.Error()))
@@ -1,7 +1,7 @@
 package mutual
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 const (
@@ -18,6 +18,9 @@ const (
 	CodePolicyLocked		sdk.CodeType = 508
 	CodeNullAddress			sdk.CodeType = 509
 	CodeInvalidPaticipant	sdk.CodeType = 510
+	CodeInvalidVesting		sdk.CodeType = 511
+	CodeInvalidAirdrop		sdk.CodeType = 512
+	CodeInvalidParams		sdk.CodeType = 513
 )
 
 func ErrIncorrectStakingToken(codespace sdk.CodespaceType) sdk.Error {
@@ -64,6 +67,18 @@ func ErrInvalidPaticipant(codespace sdk.CodespaceType) sdk.Error {
 	return newError(codespace, CodeInvalidPaticipant, "")
 }
 
+func ErrInvalidVesting(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidVesting, "vesting must end after its start")
+}
+
+func ErrInvalidAirdrop(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidAirdrop, "the airdrop targets must add up to the amount")
+}
+
+func ErrInvalidParams(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidParams, msg)
+}
+
 // -----------------------------
 // Helpers
 
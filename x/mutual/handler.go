@@ -3,7 +3,7 @@ package mutual
 import (
 	"strconv"
 //	abci "github.com/tendermint/abci/types"
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // NewHandler returns a handler for "mutual" type messages.
@@ -41,6 +41,10 @@ func handleNewPolicyMsg(ctx sdk.Context, k Keeper, msg MutualNewPolicyMsg) sdk.R
 	return sdk.Result{
 		Code:	sdk.ABCICodeOK,
 		Data:   []byte(strconv.FormatInt(power, 10)),
+		Tags:	sdk.NewTags(
+			TagAction, ActionNewPolicy,
+			TagPolicy, addrTag(msg.Address),
+		),
 	}
 }
 
@@ -53,6 +57,13 @@ func handleProposalMsg(ctx sdk.Context, k Keeper, msg MutualProposalMsg) sdk.Res
 	return sdk.Result{
 		Code:	sdk.ABCICodeOK,
 		Data:   []byte(strconv.FormatInt(power, 10)),
+		Tags:	sdk.NewTags(
+			TagAction, ActionClaim,
+			TagPolicy, addrTag(msg.PolicyAddress),
+			TagMember, addrTag(msg.Address),
+			TagClaim, addrTag(msg.Address),
+			TagAmount, amountTag(msg.Amount),
+		),
 	}
 }
 
@@ -65,6 +76,10 @@ func handleMutualPolicyLockMsg(ctx sdk.Context, k Keeper, msg MutualPolicyLockMs
 	return sdk.Result{
 		Code:	sdk.ABCICodeOK,
 		Data:   []byte(strconv.FormatBool(power)),
+		Tags:	sdk.NewTags(
+			TagAction, ActionPolicyLock,
+			TagPolicy, addrTag(msg.PolicyAddress),
+		),
 	}
 }
 
@@ -77,18 +92,36 @@ func handlePolicyApprovalMsg(ctx sdk.Context, k Keeper, msg MutualPolicyApproval
 	return sdk.Result{
 		Code:	sdk.ABCICodeOK,
 		Data:   []byte(strconv.FormatInt(power, 10)),
+		Tags:	sdk.NewTags(
+			TagAction, ActionApproveClaim,
+			TagPolicy, addrTag(msg.PolicyAddress),
+			TagMember, addrTag(msg.Address),
+			TagClaim, addrTag(msg.Address),
+		),
 	}
 }
 
 func handleMutualCollectCliamMsg(ctx sdk.Context, k Keeper, msg MutualCollectCliamMsg) sdk.Result {
-	_, power, err := k.CollectClaim(ctx, msg.PolicyAddress, msg.ClaimAddress, msg.BeginAddress, msg.Timestamp)
+	collection, err := k.collectClaim(ctx, msg.PolicyAddress, msg.ClaimAddress, msg.BeginAddress, msg.Timestamp)
 	if err != nil {
 		return err.Result()
 	}
 
+	// NOTE: the participants charged for the claim are not tagged one by one,
+	// a policy may have tens of thousands of members
+	denom := k.GetParams(ctx).StakingToken
 	return sdk.Result{
 		Code:	sdk.ABCICodeOK,
-		Data:   []byte(strconv.FormatInt(power, 10)),
+		Data:   []byte(strconv.FormatInt(collection.Total, 10)),
+		Tags:	sdk.NewTags(
+			TagAction, ActionCollectClaim,
+			TagPolicy, addrTag(msg.PolicyAddress),
+			TagMember, addrTag(msg.ClaimAddress),
+			TagClaim, addrTag(msg.ClaimAddress),
+			TagAmount, amountTag(sdk.Coin{denom, collection.Total}),
+			TagPerMember, amountTag(sdk.Coin{denom, collection.PerMember}),
+			TagMembersCharged, []byte(strconv.Itoa(collection.Members)),
+		),
 	}
 }
 
@@ -101,11 +134,17 @@ func handleBondMsg(ctx sdk.Context, k Keeper, msg MutualBondMsg) sdk.Result {
 	return sdk.Result{
 		Code:	sdk.ABCICodeOK,
 		Data: 	[]byte(strconv.FormatInt(power, 10)),
+		Tags:	sdk.NewTags(
+			TagAction, ActionBond,
+			TagPolicy, addrTag(msg.PolicyAddress),
+			TagMember, addrTag(msg.Address),
+			TagAmount, amountTag(msg.Stake),
+		),
 	}
 }
 
 func handleMutualUnbondMsg(ctx sdk.Context, k Keeper, msg MutualUnbondMsg) sdk.Result {
-	addr, _, err := k.Unbond(ctx, msg.PolicyAddress, msg.Address)
+	addr, amount, err := k.Unbond(ctx, msg.PolicyAddress, msg.Address)
 	if err != nil {
 		return err.Result()
 	}
@@ -120,16 +159,32 @@ func handleMutualUnbondMsg(ctx sdk.Context, k Keeper, msg MutualUnbondMsg) sdk.R
 		Code:       sdk.ABCICodeOK,
 		Data:		[]byte(addr),
 //		ValidatorUpdates: abci.Validators{valSet},
+		Tags:	sdk.NewTags(
+			TagAction, ActionUnbond,
+			TagPolicy, addrTag(msg.PolicyAddress),
+			TagMember, addrTag(msg.Address),
+			TagAmount, amountTag(sdk.Coin{k.GetParams(ctx).StakingToken, amount}),
+		),
 	}
 }
 
 func handleMutualAirdropMsg(ctx sdk.Context, k Keeper, msg MutualAirdropMsg) sdk.Result {
-	_, total, err := k.Airdrop(ctx, msg.SourceAddr, msg.Targets, msg.Amount)
+	_, total, err := k.Airdrop(ctx, msg.SourceAddr, msg.Targets, msg.Amount, msg.VestingStart, msg.VestingEnd)
 	if err != nil {
 		return err.Result()
 	}
+
+	tags := sdk.NewTags(
+		TagAction, ActionAirdrop,
+		TagSender, addrTag(msg.SourceAddr),
+		TagAmount, amountTag(msg.Amount),
+	)
+	for _, target := range msg.Targets {
+		tags = tags.AppendTag(TagRecipient, addrTag(target.Address))
+	}
 	return sdk.Result{
 		Code:       sdk.ABCICodeOK,
 		Data:		[]byte(strconv.FormatInt(total, 10)),
+		Tags:		tags,
 	}
 }
@@ -0,0 +1,43 @@
+package mutual
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdk "inschain-tendermint/types"
+)
+
+// find the values of a tag in a result
+func tagValues(res sdk.Result, key string) []string {
+	values := []string{}
+	for _, tag := range res.Tags {
+		if string(tag.Key) == key {
+			values = append(values, string(tag.Value))
+		}
+	}
+	return values
+}
+
+func TestHandlerTags(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 100)
+	handler := NewHandler(keeper)
+
+	res := handler(ctx, NewMutualNewPolicyMsg(addrs[0]))
+	require.True(t, res.IsOK())
+	assert.Equal(t, []string{string(ActionNewPolicy)}, tagValues(res, TagAction))
+	assert.Equal(t, []string{addrs[0].String()}, tagValues(res, TagPolicy))
+
+	res = handler(ctx, NewMutualBondMsg(addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 10}))
+	require.True(t, res.IsOK())
+	assert.Equal(t, []string{string(ActionBond)}, tagValues(res, TagAction))
+	assert.Equal(t, []string{addrs[0].String()}, tagValues(res, TagPolicy))
+	assert.Equal(t, []string{addrs[1].String()}, tagValues(res, TagMember))
+	assert.Equal(t, []string{"10getx"}, tagValues(res, TagAmount))
+
+	// failed messages carry no tags
+	res = handler(ctx, NewMutualBondMsg(addrs[5], addrs[1], sdk.Coin{defaultStakingToken, 10}))
+	assert.False(t, res.IsOK())
+	assert.Equal(t, 0, len(res.Tags))
+}
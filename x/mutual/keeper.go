@@ -1,16 +1,18 @@
 package mutual
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
 //	"time"
 //	crypto "github.com/tendermint/go-crypto"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/bank"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/bank"
 )
 
-const stakingToken = "getx" //"ins2Token"
+const defaultStakingToken = "getx" //"ins2Token"
 
 const moduleName = "mutual"
 
@@ -19,10 +21,12 @@ var (
 	PolicyKeyPrefix             = []byte{0x00} // prefix for policy key 
 	MemberKeyPrefix				= []byte{0x01} // prefix for member key
 	ClaimTxKeyPrefix            = []byte{0x02} // prefix for claim transaction  key
+	ParamsKey                   = []byte{0x20} // key for the mutual params, clear of the prefixes of the stake store
 )
 
 type Keeper struct {
-	ck bank.Keeper
+	ck bank.SupplyKeeper
+	tr bank.TransferRestrictions
 
 	key sdk.StoreKey
 	cdc *wire.Codec
@@ -59,15 +63,91 @@ func GetClaimTxKey(policyAddr sdk.Address, claimAddr sdk.Address, memberAddr sdk
 	return append(append(append(ClaimTxKeyPrefix, policyAddr.Bytes()...), claimAddr.Bytes()...), memberAddr.Bytes()...)
 }
 
-func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, coinKeeper bank.Keeper, codespace sdk.CodespaceType) Keeper {
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, supplyKeeper bank.SupplyKeeper, codespace sdk.CodespaceType) Keeper {
 	return Keeper{
 		key: key,
 		cdc: cdc,
-		ck:  coinKeeper,
+		ck:  supplyKeeper,
 		codespace: codespace,
 	}
 }
 
+// WithTransferRestrictions returns the keeper checking the bonds and unbonds
+// against the restrictions, as transfers between the member and the policy
+func (k Keeper) WithTransferRestrictions(tr bank.TransferRestrictions) Keeper {
+	k.tr = tr
+	return k
+}
+
+// GetParams returns the mutual params, the defaults until governance changes them
+func (k Keeper) GetParams(ctx sdk.Context) (params Params) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(ParamsKey)
+	if bz == nil {
+		return DefaultParams()
+	}
+	err := k.cdc.UnmarshalJSON(bz, &params)
+	if err != nil {
+		panic(err)
+	}
+	return params
+}
+
+func (k Keeper) setParams(ctx sdk.Context, params Params) {
+	store := ctx.KVStore(k.key)
+	bz, err := k.cdc.MarshalJSON(params)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(ParamsKey, bz)
+}
+
+// SetParam sets a single mutual param from its JSON name and value. The
+// staking token can't change while members have bonds in it.
+func (k Keeper) SetParam(ctx sdk.Context, key, value string) sdk.Error {
+	current := k.GetParams(ctx)
+	bz, err := k.cdc.MarshalJSON(current)
+	if err != nil {
+		panic(err)
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(bz, &fields)
+	if err != nil {
+		panic(err)
+	}
+	if _, ok := fields[key]; !ok {
+		return ErrInvalidParams(k.codespace, fmt.Sprintf("unknown mutual param %s", key))
+	}
+	fields[key] = json.RawMessage(value)
+	bz, err = json.Marshal(fields)
+	if err != nil {
+		return ErrInvalidParams(k.codespace, fmt.Sprintf("invalid value for mutual param %s", key))
+	}
+	var params Params
+	err = k.cdc.UnmarshalJSON(bz, &params)
+	if err != nil {
+		return ErrInvalidParams(k.codespace, fmt.Sprintf("invalid value for mutual param %s: %v", key, err))
+	}
+	if err := params.ValidateBasic(k.codespace); err != nil {
+		return err
+	}
+	if params.StakingToken != current.StakingToken &&
+		k.ck.GetEscrow(ctx, moduleName).AmountOf(current.StakingToken) != 0 {
+		return ErrInvalidParams(k.codespace, fmt.Sprintf("members still have bonds in %s", current.StakingToken))
+	}
+	k.setParams(ctx, params)
+	return nil
+}
+
+// check the stake may move from the sender to the recipient
+func (k Keeper) checkTransfer(ctx sdk.Context, from sdk.Address, to sdk.Address, stake sdk.Coin) sdk.Error {
+	if k.tr == nil {
+		return nil
+	}
+	coins := sdk.Coins{stake}
+	return k.tr.CheckTransfer(ctx, []bank.Input{bank.NewInput(from, coins)}, []bank.Output{bank.NewOutput(to, coins)})
+}
+
 // -----------------------
 // policy functions
 
@@ -147,21 +227,34 @@ func (k Keeper) ApproveClaim(ctx sdk.Context, policyAddr sdk.Address, claimAddr
 		return false, 0, ErrInvalidClaim(k.codespace)
 	}
 	
+	amount := pi.ClaimAmount
 	pi.ClaimApproved = approval
+	if !approval {
+		// a rejected claim is closed, the member may file a new one
+		pi.ClaimAddr = nil
+		pi.ClaimAmount = 0
+	}
 	k.setPolicyInfo(ctx, policyAddr, pi)
-	return pi.ClaimApproved, pi.ClaimAmount, nil
+	return approval, amount, nil
 }
 
 func (k Keeper) CollectClaim(ctx sdk.Context, policyAddr sdk.Address, claimAddr sdk.Address, beginWith sdk.Address, timestamp string) (bool, int64, sdk.Error) {
+	collection, err := k.collectClaim(ctx, policyAddr, claimAddr, beginWith, timestamp)
+	return err == nil, collection.Total, err
+}
+
+// collectClaim pays an approved claim, and reports the deductions made from
+// the bonds of the other members
+func (k Keeper) collectClaim(ctx sdk.Context, policyAddr sdk.Address, claimAddr sdk.Address, beginWith sdk.Address, timestamp string) (ClaimCollection, sdk.Error) {
 	pi := k.getPolicyInfo(ctx, policyAddr)
 	if pi.PolicyAddr == nil {
-		return false, 0, ErrNullPolicy(k.codespace)
+		return ClaimCollection{}, ErrNullPolicy(k.codespace)
 	}
 	if pi.ClaimAddr == nil {
-		return false, 0, ErrNullClaim(k.codespace)
+		return ClaimCollection{}, ErrNullClaim(k.codespace)
 	}
 	if pi.ClaimAddr.String() != claimAddr.String() || pi.ClaimApproved == false {
-		return false, 0, ErrInvalidClaim(k.codespace)
+		return ClaimCollection{}, ErrInvalidClaim(k.codespace)
 	}
 	maxRetrieve := 50000
 	if beginWith != nil {	// for test only , is not using begin address for now
@@ -194,12 +287,14 @@ func (k Keeper) CollectClaim(ctx sdk.Context, policyAddr sdk.Address, claimAddr
 	}
 	//iterator.Close()
 	
+	charged := 0
 	for j := 0 ; j < i; j++ {
 		// deduct participant amount
 		if bonds[j].MemberAddr.String() == claimAddr.String() {
 			continue
 		}
 		bonds[j].Amount -= toDeliver
+		charged++
 		bz, err := k.cdc.MarshalJSON(bonds[j])
 			if err != nil {
 			panic(err)
@@ -225,12 +320,17 @@ func (k Keeper) CollectClaim(ctx sdk.Context, policyAddr sdk.Address, claimAddr
 
 	}
 	totalDeliverAmt := toDeliver * int64(i-1)
-	totalCoins := sdk.Coin{stakingToken, totalDeliverAmt}
+	totalCoins := sdk.Coin{k.GetParams(ctx).StakingToken, totalDeliverAmt}
+	collection := ClaimCollection{
+		PerMember: toDeliver,
+		Members:   charged,
+		Total:     totalDeliverAmt,
+	}
 
-	// add amount to claim address
-	_, err := k.ck.AddCoins(ctx, claimAddr, []sdk.Coin{totalCoins})
+	// pay the claim address from the bonds held by the policies
+	_, err := k.ck.ReleaseCoins(ctx, moduleName, claimAddr, []sdk.Coin{totalCoins})
 	if err != nil {
-		return false, totalDeliverAmt, err
+		return collection, err
 	}
 	
 	pi.TotalAmount -= totalDeliverAmt
@@ -239,7 +339,7 @@ func (k Keeper) CollectClaim(ctx sdk.Context, policyAddr sdk.Address, claimAddr
 	pi.ClaimApproved = false
 	k.setPolicyInfo(ctx, policyAddr, pi)
 	
-	return true, totalDeliverAmt, nil
+	return collection, nil
 }
 
 
@@ -287,15 +387,20 @@ func (k Keeper) deleteBondInfo(ctx sdk.Context, policyAddr sdk.Address, addr sdk
 }
 
 func (k Keeper) Bond(ctx sdk.Context, policyAddr sdk.Address, addr sdk.Address, stake sdk.Coin) (int64, sdk.Error) {
-	if stake.Denom != stakingToken {
+	if stake.Denom != k.GetParams(ctx).StakingToken {
 		return 0, ErrIncorrectStakingToken(k.codespace)
 	}
 	pi := k.getPolicyInfo(ctx, policyAddr)
 	if pi.PolicyAddr == nil {
 		return 0, ErrNullPolicy(k.codespace)
 	}
+	err := k.checkTransfer(ctx, addr, policyAddr, stake)
+	if err != nil {
+		return 0, err
+	}
 
-	_, err := k.ck.SubtractCoins(ctx, addr, []sdk.Coin{stake})
+	// the locked coins of a vesting account can be bonded
+	_, err = k.ck.DelegateCoins(ctx, moduleName, addr, []sdk.Coin{stake})
 	if err != nil {
 		return 0, err
 	}
@@ -330,6 +435,11 @@ func (k Keeper) Unbond(ctx sdk.Context, policyAddr sdk.Address, addr sdk.Address
 	if pi.Lock == true || (pi.ClaimAddr != nil && len(pi.ClaimAddr) > 1) {
 		return sdk.Address{}, 0, ErrPolicyLocked(k.codespace)
 	}
+	returnedBond := sdk.Coin{k.GetParams(ctx).StakingToken, bi.Amount}
+	err := k.checkTransfer(ctx, policyAddr, addr, returnedBond)
+	if err != nil {
+		return sdk.Address{}, 0, err
+	}
 
 	k.deleteBondInfo(ctx, policyAddr, addr)
 	
@@ -338,9 +448,7 @@ func (k Keeper) Unbond(ctx sdk.Context, policyAddr sdk.Address, addr sdk.Address
 	
 	k.setPolicyInfo(ctx, policyAddr, pi)
 
-	returnedBond := sdk.Coin{stakingToken, bi.Amount}
-
-	_, err := k.ck.AddCoins(ctx, addr, []sdk.Coin{returnedBond})
+	_, err = k.ck.UndelegateCoins(ctx, moduleName, addr, []sdk.Coin{returnedBond})
 	if err != nil {
 		return bi.MemberAddr, bi.Amount, err
 	}
@@ -348,22 +456,33 @@ func (k Keeper) Unbond(ctx sdk.Context, policyAddr sdk.Address, addr sdk.Address
 	return bi.MemberAddr, bi.Amount, nil
 }
 
-// Airdrop coins to target addresses
-func (k Keeper) Airdrop(ctx sdk.Context, sourceAddr sdk.Address, targets []ADTarget, amount sdk.Coin) (sdk.Address, int64, sdk.Error) {
+// Airdrop coins to target addresses, with a vesting end time the coins vest
+// from the vesting start time, or at the end time if there is no start time
+func (k Keeper) Airdrop(ctx sdk.Context, sourceAddr sdk.Address, targets []ADTarget, amount sdk.Coin, vestingStart, vestingEnd int64) (sdk.Address, int64, sdk.Error) {
+
+	// the targets share the amount, no coins are created or destroyed
+	var total sdk.Coins
+	for _, target := range targets {
+		total = total.Plus(sdk.Coins{target.Amount})
+	}
+	if !total.IsEqual(sdk.Coins{amount}) {
+		return sourceAddr, 0, ErrInvalidAirdrop(k.codespace)
+	}
 
-//	totalAmt := sdk.Coin {
-//		Denom:	amount.Denom,
-//		Amount: amount.Amount * int64(len(targetAddrs)),
-//	}
 	// deduct from source address
-	_, err := k.ck.SubtractCoins(ctx, sourceAddr, []sdk.Coin{amount})
+	_, err := k.ck.EscrowCoins(ctx, moduleName, sourceAddr, []sdk.Coin{amount})
 	if err != nil {
 		return sourceAddr, 0, err
 	}
 
 	// add coins to each target address
 	for _, target := range targets {
-		_, err := k.ck.AddCoins(ctx, target.Address, []sdk.Coin{target.Amount})
+		var err sdk.Error
+		if vestingEnd == 0 {
+			_, err = k.ck.ReleaseCoins(ctx, moduleName, target.Address, []sdk.Coin{target.Amount})
+		} else {
+			_, err = k.ck.ReleaseVestingCoins(ctx, moduleName, target.Address, []sdk.Coin{target.Amount}, vestingStart, vestingEnd)
+		}
 		if err != nil {
 			return sourceAddr, 0, err
 		}
@@ -375,7 +494,7 @@ func (k Keeper) Airdrop(ctx sdk.Context, sourceAddr sdk.Address, targets []ADTar
 // FOR TESTING PURPOSES -------------------------------------------------
 
 func (k Keeper) bondWithoutCoins(ctx sdk.Context, policyAddr sdk.Address, addr sdk.Address, stake sdk.Coin) (int64, sdk.Error) {
-	if stake.Denom != stakingToken {
+	if stake.Denom != k.GetParams(ctx).StakingToken {
 		return 0, ErrIncorrectStakingToken(k.codespace)
 	}
 
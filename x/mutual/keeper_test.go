@@ -1,7 +1,7 @@
 package mutual
 
 import (
-//	"bytes"
+	"bytes"
 //	"encoding/hex"
 	"fmt"
 
@@ -15,11 +15,11 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	"github.com/cosmos/cosmos-sdk/store"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/bank"
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
 )
 
 // dummy addresses used for testing
@@ -75,6 +75,68 @@ func TestKeeperGetSet(t *testing.T) {
 }
 
 
+func TestRejectClaim(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 100)
+
+	_, err := keeper.NewPolicy(ctx, addrs[0])
+	assert.Nil(t, err)
+	_, err = keeper.Bond(ctx, addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 10})
+	assert.Nil(t, err)
+	_, err = keeper.Bond(ctx, addrs[0], addrs[2], sdk.Coin{defaultStakingToken, 10})
+	assert.Nil(t, err)
+	_, err = keeper.Claim(ctx, addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 6})
+	assert.Nil(t, err)
+
+	// the rejected claim is closed and can't be collected
+	approved, amt, err := keeper.ApproveClaim(ctx, addrs[0], addrs[1], false)
+	assert.Nil(t, err)
+	assert.Equal(t, false, approved)
+	assert.Equal(t, int64(6), amt)
+	pi := keeper.getPolicyInfo(ctx, addrs[0])
+	assert.Equal(t, 0, len(pi.ClaimAddr))
+	_, _, err = keeper.CollectClaim(ctx, addrs[0], addrs[1], nil, "2018-05-27")
+	assert.NotNil(t, err)
+
+	// a new claim can be filed
+	amt, err = keeper.Claim(ctx, addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 4})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), amt)
+}
+
+func TestSetParam(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 100)
+	assert.Equal(t, DefaultParams(), keeper.GetParams(ctx))
+
+	_, err := keeper.NewPolicy(ctx, addrs[0])
+	assert.Nil(t, err)
+	_, err = keeper.Bond(ctx, addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 10})
+	assert.Nil(t, err)
+
+	cases := []struct {
+		key, value string
+		ok         bool
+	}{
+		{"unknown", `"ins"`, false},
+		{"staking_token", `""`, false},
+		{"staking_token", `5`, false},
+		{"staking_token", `"ins"`, false}, // a member is bonded in getx
+	}
+	for i, tc := range cases {
+		err := keeper.SetParam(ctx, tc.key, tc.value)
+		assert.Equal(t, tc.ok, err == nil, "%d: %v", i, err)
+	}
+
+	// once the bonds are returned the staking token can change
+	_, err = keeper.PolicyLock(ctx, addrs[0], false)
+	assert.Nil(t, err)
+	_, _, err = keeper.Unbond(ctx, addrs[0], addrs[1])
+	assert.Nil(t, err)
+	assert.Nil(t, keeper.SetParam(ctx, "staking_token", `"ins"`))
+	assert.Equal(t, "ins", keeper.GetParams(ctx).StakingToken)
+	_, err = keeper.Bond(ctx, addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 10})
+	assert.NotNil(t, err)
+}
+
 func TestBonding(t *testing.T) {
 	ctx, _, keeper := createTestInput(t, false, 100)
 
@@ -84,19 +146,19 @@ func TestBonding(t *testing.T) {
 	fmt.Printf("Police Info: %v\n", amt)
 
 	// create three participants, each bond 10 tokens
-	amt, err = keeper.Bond(ctx, addrs[0], addrs[1], sdk.Coin{stakingToken, 10})
+	amt, err = keeper.Bond(ctx, addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 10})
 	assert.Nil(t, err)
 	assert.Equal(t, int64(10), amt)
-	amt, err = keeper.Bond(ctx, addrs[0], addrs[2], sdk.Coin{stakingToken, 10})
+	amt, err = keeper.Bond(ctx, addrs[0], addrs[2], sdk.Coin{defaultStakingToken, 10})
 	assert.Nil(t, err)
 	assert.Equal(t, int64(10), amt)
-	amt, err = keeper.Bond(ctx, addrs[0], addrs[3], sdk.Coin{stakingToken, 10})
+	amt, err = keeper.Bond(ctx, addrs[0], addrs[3], sdk.Coin{defaultStakingToken, 10})
 	assert.Nil(t, err)
 	assert.Equal(t, int64(10), amt)
 	fmt.Printf("Participants created\n")
 	
 	// participant 1 : make a proposal for 6 tokens
-	amt, err = keeper.Claim(ctx, addrs[0], addrs[1], sdk.Coin{stakingToken, 6})
+	amt, err = keeper.Claim(ctx, addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 6})
 	assert.Nil(t, err)
 	assert.Equal(t, int64(6), amt)
 	fmt.Printf("Proposal made\n")
@@ -126,6 +188,92 @@ func TestBonding(t *testing.T) {
 
 }
 
+func TestVestingAirdrop(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 100)
+	ctx = ctx.WithBlockHeader(abci.Header{ChainID: "foochainid", Time: 1000})
+
+	_, err := keeper.NewPolicy(ctx, addrs[0])
+	assert.Nil(t, err)
+
+	// airdrop 50 tokens to participant 1, locked until the vesting end
+	targets := []ADTarget{{addrs[1], sdk.Coin{defaultStakingToken, 50}}}
+	_, _, err = keeper.Airdrop(ctx, addrs[2], targets, sdk.Coin{defaultStakingToken, 60}, 0, 2000)
+	assert.NotNil(t, err)
+	_, amt, err := keeper.Airdrop(ctx, addrs[2], targets, sdk.Coin{defaultStakingToken, 50}, 0, 2000)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(50), amt)
+	assert.Equal(t, int64(50), keeper.ck.GetCoins(ctx, addrs[2]).AmountOf(defaultStakingToken))
+
+	// the airdropped tokens can't be sent
+	_, err = keeper.ck.Burn(ctx, addrs[1], sdk.Coins{{defaultStakingToken, 101}})
+	assert.NotNil(t, err)
+
+	// but they can be bonded into the policy
+	amt, err = keeper.Bond(ctx, addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 150})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(150), amt)
+
+	// unbonded, they are still locked
+	keeper.PolicyLock(ctx, addrs[0], false)
+	_, amt, err = keeper.Unbond(ctx, addrs[0], addrs[1])
+	assert.Nil(t, err)
+	assert.Equal(t, int64(150), amt)
+	_, err = keeper.ck.Burn(ctx, addrs[1], sdk.Coins{{defaultStakingToken, 101}})
+	assert.NotNil(t, err)
+
+	// until the vesting end
+	ctx = ctx.WithBlockHeader(abci.Header{ChainID: "foochainid", Time: 2000})
+	_, err = keeper.ck.Burn(ctx, addrs[1], sdk.Coins{{defaultStakingToken, 150}})
+	assert.Nil(t, err)
+
+	// the policy doesn't hold any coins anymore
+	assert.True(t, keeper.ck.GetEscrow(ctx, moduleName).IsZero())
+}
+
+// blockAddress blocks the transfers from and to the address
+type blockAddress sdk.Address
+
+func (b blockAddress) CheckTransfer(ctx sdk.Context, inputs []bank.Input, outputs []bank.Output) sdk.Error {
+	for _, in := range inputs {
+		if bytes.Equal(in.Address, b) {
+			return sdk.ErrUnauthorized("blocked")
+		}
+	}
+	for _, out := range outputs {
+		if bytes.Equal(out.Address, b) {
+			return sdk.ErrUnauthorized("blocked")
+		}
+	}
+	return nil
+}
+
+func TestBondingRestrictions(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 100)
+	keeper = keeper.WithTransferRestrictions(blockAddress(addrs[2]))
+
+	_, err := keeper.NewPolicy(ctx, addrs[0])
+	assert.Nil(t, err)
+
+	// the blocked member can't bond
+	_, err = keeper.Bond(ctx, addrs[0], addrs[2], sdk.Coin{defaultStakingToken, 10})
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(100), keeper.ck.GetCoins(ctx, addrs[2]).AmountOf(defaultStakingToken))
+
+	// nor unbond, the bond is kept
+	amt, err := keeper.Bond(ctx, addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 10})
+	require.Nil(t, err)
+	assert.Equal(t, int64(10), amt)
+	keeper.PolicyLock(ctx, addrs[0], false)
+	_, _, err = keeper.WithTransferRestrictions(blockAddress(addrs[1])).Unbond(ctx, addrs[0], addrs[1])
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(10), keeper.getBondInfo(ctx, addrs[0], addrs[1]).Amount)
+
+	_, amt, err = keeper.Unbond(ctx, addrs[0], addrs[1])
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), amt)
+	assert.Equal(t, int64(100), keeper.ck.GetCoins(ctx, addrs[1]).AmountOf(defaultStakingToken))
+}
+
 // register codec for testing
 func makeTestCodec() *wire.Codec {
 	var cdc = wire.NewCodec()
@@ -144,6 +292,7 @@ func makeTestCodec() *wire.Codec {
 	// Register AppAccount
 	cdc.RegisterInterface((*sdk.Account)(nil), nil)
 	cdc.RegisterConcrete(&auth.BaseAccount{}, "test/mutual/Account", nil)
+	auth.RegisterVestingAccounts(cdc)
 	wire.RegisterCrypto(cdc)
 
 	return cdc
@@ -154,9 +303,11 @@ func createTestInput(t *testing.T, isCheckTx bool, initCoins int64) (sdk.Context
 	db := dbm.NewMemDB()
 	keyStake := sdk.NewKVStoreKey("mutual")
 	keyMain := keyStake //sdk.NewKVStoreKey("main") //TODO fix multistore
+	keySupply := sdk.NewKVStoreKey("supply")
 
 	ms := store.NewCommitMultiStore(db)
 	ms.MountStoreWithDB(keyStake, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keySupply, sdk.StoreTypeIAVL, db)
 	err := ms.LoadLatestVersion()
 	require.Nil(t, err)
 
@@ -167,15 +318,15 @@ func createTestInput(t *testing.T, isCheckTx bool, initCoins int64) (sdk.Context
 		keyMain,             // target store
 		&auth.BaseAccount{}, // prototype
 	)
-	ck := bank.NewKeeper(accountMapper)
+	ck := bank.NewSupplyKeeper(cdc, keySupply, bank.NewKeeper(accountMapper), bank.DefaultCodespace)
 	keeper := NewKeeper(cdc, keyStake, ck, DefaultCodespace)
 	//keeper.setPool(ctx, initialPool())
 	//keeper.setParams(ctx, defaultParams())
 
 	// fill all the addresses with some coins
 	for _, addr := range addrs {
-		ck.AddCoins(ctx, addr, sdk.Coins{
-			{stakingToken, initCoins},
+		ck.Mint(ctx, addr, sdk.Coins{
+			{defaultStakingToken, initCoins},
 		})
 	}
 
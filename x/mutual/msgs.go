@@ -5,7 +5,7 @@ import (
 
 //	crypto "github.com/tendermint/go-crypto"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // Mutual policy messages only for test
@@ -330,10 +330,14 @@ type ADTarget struct {
 	Amount		sdk.Coin	`json:"ad_amount"`
 }
 
+// with a vesting end time, the airdropped coins vest linearly from the vesting
+// start time to the end time, or at the end time if there is no start time
 type MutualAirdropMsg struct {
 	SourceAddr 	sdk.Address `json:"source_address"`
 	Amount		sdk.Coin	`json:"amount"`
 	Targets 	[]ADTarget 	`json:"targets"`
+	VestingStart	int64	`json:"vesting_start"`
+	VestingEnd	int64	`json:"vesting_end"`
 }
 
 func NewMutualAirdropMsg(sourceAddr sdk.Address, targets []ADTarget, amount sdk.Coin) MutualAirdropMsg {
@@ -344,11 +348,21 @@ func NewMutualAirdropMsg(sourceAddr sdk.Address, targets []ADTarget, amount sdk.
 	}
 }
 
+func NewMutualVestingAirdropMsg(sourceAddr sdk.Address, targets []ADTarget, amount sdk.Coin, vestingStart, vestingEnd int64) MutualAirdropMsg {
+	msg := NewMutualAirdropMsg(sourceAddr, targets, amount)
+	msg.VestingStart = vestingStart
+	msg.VestingEnd = vestingEnd
+	return msg
+}
+
 func (msg MutualAirdropMsg) Type() string {
 	return moduleName
 }
 
 func (msg MutualAirdropMsg) ValidateBasic() sdk.Error {
+	if msg.VestingStart < 0 || (msg.VestingEnd != 0 && msg.VestingEnd <= msg.VestingStart) {
+		return ErrInvalidVesting(DefaultCodespace)
+	}
 	return nil
 }
 
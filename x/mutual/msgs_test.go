@@ -7,7 +7,7 @@ import (
 
 //	crypto "github.com/tendermint/go-crypto"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // test ValidateBasic for MutualNewPolicyMsg
@@ -0,0 +1,247 @@
+package mutual
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+// query endpoints supported by the mutual querier, at /custom/mutual/<route>
+const (
+	QueryPolicy   = "policy"
+	QueryMember   = "member"
+	QueryMembers  = "members"
+	QueryClaims   = "claims"
+	QueryClaimTxs = "claimTxs"
+	QueryParams   = "params"
+)
+
+const (
+	defaultMembersLimit = 100
+	maxMembersLimit     = 1000
+)
+
+// parameters of the policy and claims queries
+type QueryPolicyParams struct {
+	PolicyAddr sdk.Address `json:"policy"`
+}
+
+// parameters of the member query
+type QueryMemberParams struct {
+	PolicyAddr sdk.Address `json:"policy"`
+	MemberAddr sdk.Address `json:"member"`
+}
+
+// parameters of the members query, the page starts at the Start member
+// included, or at the first member of the policy
+type QueryMembersParams struct {
+	PolicyAddr sdk.Address `json:"policy"`
+	Start      sdk.Address `json:"start"`
+	Limit      int         `json:"limit"`
+}
+
+// a page of members, Next is the start of the next page if any
+type MembersPage struct {
+	Members []BondInfo  `json:"members"`
+	Next    sdk.Address `json:"next"`
+}
+
+// parameters of the claim transactions query,
+// the member is optional to get the transactions of every member
+type QueryClaimTxsParams struct {
+	PolicyAddr sdk.Address `json:"policy"`
+	ClaimAddr  sdk.Address `json:"claim"`
+	MemberAddr sdk.Address `json:"member"`
+}
+
+// a claim of a policy, either open or collected at the timestamp
+type Claim struct {
+	PolicyAddr sdk.Address `json:"policy"`
+	ClaimAddr  sdk.Address `json:"claim"`
+	Amount     int64       `json:"amount"`
+	Approved   bool        `json:"approved"`
+	Collected  bool        `json:"collected"`
+	Timestamp  string      `json:"timestamp"`
+}
+
+// NewQuerier returns the querier of the mutual module
+func NewQuerier(k Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("No mutual query endpoint specified")
+		}
+		switch path[0] {
+		case QueryPolicy:
+			return queryPolicy(ctx, req, k)
+		case QueryMember:
+			return queryMember(ctx, req, k)
+		case QueryMembers:
+			return queryMembers(ctx, req, k)
+		case QueryClaims:
+			return queryClaims(ctx, req, k)
+		case QueryClaimTxs:
+			return queryClaimTxs(ctx, req, k)
+		case QueryParams:
+			return k.marshalQueryResult(k.GetParams(ctx))
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Unknown mutual query endpoint %s", path[0]))
+		}
+	}
+}
+
+func queryPolicy(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryPolicyParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	pi := k.getPolicyInfo(ctx, params.PolicyAddr)
+	if pi.PolicyAddr == nil {
+		return nil, ErrNullPolicy(k.codespace)
+	}
+	return k.marshalQueryResult(pi)
+}
+
+func queryMember(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryMemberParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	bi := k.getBondInfo(ctx, params.PolicyAddr, params.MemberAddr)
+	if bi.PolicyAddr == nil {
+		return nil, ErrInvalidPaticipant(k.codespace)
+	}
+	return k.marshalQueryResult(bi)
+}
+
+func queryMembers(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryMembersParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultMembersLimit
+	}
+	if limit > maxMembersLimit {
+		limit = maxMembersLimit
+	}
+
+	prefix := GetPolicyMembersKey(params.PolicyAddr)
+	start := prefix
+	if len(params.Start) > 0 {
+		start = GetPolicyMemberKey(params.PolicyAddr, params.Start)
+	}
+	store := ctx.KVStore(k.key)
+	iterator := store.Iterator(start, sdk.PrefixEndBytes(prefix))
+	defer iterator.Close()
+
+	page := MembersPage{Members: []BondInfo{}}
+	for ; iterator.Valid(); iterator.Next() {
+		var bond BondInfo
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &bond)
+		if err != nil {
+			panic(err)
+		}
+		if len(page.Members) == limit {
+			page.Next = bond.MemberAddr
+			break
+		}
+		page.Members = append(page.Members, bond)
+	}
+	return k.marshalQueryResult(page)
+}
+
+// the open claim of the policy, followed by the collected claims
+func queryClaims(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryPolicyParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	pi := k.getPolicyInfo(ctx, params.PolicyAddr)
+	if pi.PolicyAddr == nil {
+		return nil, ErrNullPolicy(k.codespace)
+	}
+
+	claims := []Claim{}
+	if len(pi.ClaimAddr) > 0 {
+		claims = append(claims, Claim{
+			PolicyAddr: pi.PolicyAddr,
+			ClaimAddr:  pi.ClaimAddr,
+			Amount:     pi.ClaimAmount,
+			Approved:   pi.ClaimApproved,
+		})
+	}
+
+	// a collection creates a transaction per member with the same timestamp
+	collected := make(map[string]int)
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(append(ClaimTxKeyPrefix, params.PolicyAddr.Bytes()...))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var tx ClaimTransaction
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &tx)
+		if err != nil {
+			panic(err)
+		}
+		id := tx.ClaimAddr.String() + "/" + tx.Timestamp
+		i, ok := collected[id]
+		if !ok {
+			i = len(claims)
+			collected[id] = i
+			claims = append(claims, Claim{
+				PolicyAddr: tx.Policy,
+				ClaimAddr:  tx.ClaimAddr,
+				Approved:   true,
+				Collected:  true,
+				Timestamp:  tx.Timestamp,
+			})
+		}
+		claims[i].Amount += tx.Amount
+	}
+	return k.marshalQueryResult(claims)
+}
+
+func queryClaimTxs(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryClaimTxsParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	prefix := GetClaimTxsKey(params.PolicyAddr, params.ClaimAddr)
+	if len(params.MemberAddr) > 0 {
+		prefix = GetClaimTxKey(params.PolicyAddr, params.ClaimAddr, params.MemberAddr)
+	}
+
+	txs := []ClaimTransaction{}
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(prefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var tx ClaimTransaction
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &tx)
+		if err != nil {
+			panic(err)
+		}
+		txs = append(txs, tx)
+	}
+	return k.marshalQueryResult(txs)
+}
+
+//_______________________________________________________________________
+
+func (k Keeper) unmarshalQueryParams(req abci.RequestQuery, params interface{}) sdk.Error {
+	err := k.cdc.UnmarshalJSON(req.Data, params)
+	if err != nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("Incorrectly formatted query data: %s", err.Error()))
+	}
+	return nil
+}
+
+func (k Keeper) marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	bz, err := k.cdc.MarshalJSON(res)
+	if err != nil {
+		panic(err)
+	}
+	return bz, nil
+}
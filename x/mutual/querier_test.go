@@ -0,0 +1,81 @@
+package mutual
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+func query(t *testing.T, ctx sdk.Context, keeper Keeper, route string, params interface{}, res interface{}) sdk.Error {
+	data, err := keeper.cdc.MarshalJSON(params)
+	require.Nil(t, err)
+	bz, sdkErr := NewQuerier(keeper)(ctx, []string{route}, abci.RequestQuery{Data: data})
+	if sdkErr != nil {
+		return sdkErr
+	}
+	require.Nil(t, keeper.cdc.UnmarshalJSON(bz, res))
+	return nil
+}
+
+func TestQuerier(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 100)
+
+	// a policy with three members, and a claim collected
+	keeper.NewPolicy(ctx, addrs[0])
+	for _, addr := range addrs[1:4] {
+		_, err := keeper.Bond(ctx, addrs[0], addr, sdk.Coin{defaultStakingToken, 10})
+		require.Nil(t, err)
+	}
+	keeper.Claim(ctx, addrs[0], addrs[1], sdk.Coin{defaultStakingToken, 6})
+	keeper.ApproveClaim(ctx, addrs[0], addrs[1], true)
+	_, _, err := keeper.CollectClaim(ctx, addrs[0], addrs[1], nil, "2018-05-27")
+	require.Nil(t, err)
+
+	var policy PolicyInfo
+	require.Nil(t, query(t, ctx, keeper, QueryPolicy, QueryPolicyParams{addrs[0]}, &policy))
+	assert.Equal(t, int32(3), policy.Count)
+	assert.NotNil(t, query(t, ctx, keeper, QueryPolicy, QueryPolicyParams{addrs[9]}, &policy))
+
+	var member BondInfo
+	require.Nil(t, query(t, ctx, keeper, QueryMember, QueryMemberParams{addrs[0], addrs[2]}, &member))
+	assert.Equal(t, int64(7), member.Amount)
+	assert.NotNil(t, query(t, ctx, keeper, QueryMember, QueryMemberParams{addrs[0], addrs[9]}, &member))
+
+	// the members are paginated
+	var page MembersPage
+	require.Nil(t, query(t, ctx, keeper, QueryMembers, QueryMembersParams{addrs[0], nil, 2}, &page))
+	require.Equal(t, 2, len(page.Members))
+	assert.Equal(t, addrs[1], page.Members[0].MemberAddr)
+	assert.Equal(t, addrs[3], page.Next)
+	require.Nil(t, query(t, ctx, keeper, QueryMembers, QueryMembersParams{addrs[0], page.Next, 2}, &page))
+	require.Equal(t, 1, len(page.Members))
+	assert.Equal(t, addrs[3], page.Members[0].MemberAddr)
+	assert.Empty(t, page.Next)
+
+	// the collected claim, and a new open claim
+	keeper.Claim(ctx, addrs[0], addrs[2], sdk.Coin{defaultStakingToken, 4})
+	var claims []Claim
+	require.Nil(t, query(t, ctx, keeper, QueryClaims, QueryPolicyParams{addrs[0]}, &claims))
+	require.Equal(t, 2, len(claims))
+	assert.Equal(t, Claim{addrs[0], addrs[2], 4, false, false, ""}, claims[0])
+	assert.Equal(t, Claim{addrs[0], addrs[1], 6, true, true, "2018-05-27"}, claims[1])
+
+	var txs []ClaimTransaction
+	require.Nil(t, query(t, ctx, keeper, QueryClaimTxs, QueryClaimTxsParams{addrs[0], addrs[1], nil}, &txs))
+	assert.Equal(t, 2, len(txs))
+	require.Nil(t, query(t, ctx, keeper, QueryClaimTxs, QueryClaimTxsParams{addrs[0], addrs[1], addrs[3]}, &txs))
+	require.Equal(t, 1, len(txs))
+	assert.Equal(t, addrs[3], txs[0].Participant)
+
+	var params Params
+	require.Nil(t, query(t, ctx, keeper, QueryParams, QueryPolicyParams{}, &params))
+	assert.Equal(t, defaultStakingToken, params.StakingToken)
+
+	_, sdkErr := NewQuerier(keeper)(ctx, []string{"unknown"}, abci.RequestQuery{})
+	assert.NotNil(t, sdkErr)
+}
@@ -0,0 +1,41 @@
+package mutual
+
+import (
+	sdk "inschain-tendermint/types"
+)
+
+// Tags attached to the results of mutual messages, indexed by Tendermint.
+// Addresses are tagged in upper case hex, amounts as coin strings (eg. "100getx").
+const (
+	TagAction    = "action"
+	TagPolicy    = "policy"
+	TagMember    = "member"
+	TagClaim     = "claim"
+	TagAmount    = "amount"
+	TagSender    = "sender"
+	TagRecipient = "recipient"
+
+	// amount deducted from each member charged for a claim, and their number
+	TagPerMember      = "per-member"
+	TagMembersCharged = "members-charged"
+)
+
+// Values of the action tag
+var (
+	ActionNewPolicy    = []byte("new-policy")
+	ActionClaim        = []byte("claim")
+	ActionApproveClaim = []byte("approve-claim")
+	ActionCollectClaim = []byte("collect-claim")
+	ActionBond         = []byte("bond")
+	ActionUnbond       = []byte("unbond")
+	ActionPolicyLock   = []byte("policy-lock")
+	ActionAirdrop      = []byte("airdrop")
+)
+
+func addrTag(addr sdk.Address) []byte {
+	return []byte(addr.String())
+}
+
+func amountTag(amount sdk.Coin) []byte {
+	return []byte(amount.String())
+}
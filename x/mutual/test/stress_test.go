@@ -38,11 +38,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	keys "github.com/cosmos/cosmos-sdk/client/keys"
-	tests "github.com/cosmos/cosmos-sdk/tests"
+	keys "inschain-tendermint/client/keys"
+	tests "inschain-tendermint/tests"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 /*
@@ -2,9 +2,29 @@ package mutual
 
 import (
 //	crypto "github.com/tendermint/go-crypto"
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
+// Params of the mutual module, changed by governance
+type Params struct {
+	StakingToken string `json:"staking_token"` // denom of the bonds of the members
+}
+
+// DefaultParams - the params until governance changes them
+func DefaultParams() Params {
+	return Params{
+		StakingToken: defaultStakingToken,
+	}
+}
+
+// ValidateBasic checks the params are consistent
+func (p Params) ValidateBasic(codespace sdk.CodespaceType) sdk.Error {
+	if p.StakingToken == "" {
+		return ErrInvalidParams(codespace, "staking token must be set")
+	}
+	return nil
+}
+
 // a simple policy class for test only
 type PolicyInfo struct {
 	PolicyAddr		sdk.Address
@@ -42,6 +62,14 @@ func (bi BondInfo) isEmpty() bool {
 }
 */
 
+// ClaimCollection reports the payment of a claim: the amount deducted from
+// the bond of each member charged, and the total paid to the claimant
+type ClaimCollection struct {
+	PerMember int64 `json:"per_member"`
+	Members   int   `json:"members"`
+	Total     int64 `json:"total"`
+}
+
 // claim colletion transaction
 type ClaimTransaction struct {
 	Policy 		sdk.Address
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+
+	"inschain-tendermint/x/scheduler"
+)
+
+const (
+	flagTo          = "to"
+	flagAmount      = "amount"
+	flagInterval    = "interval"
+	flagRepetitions = "repetitions"
+	flagEndHeight   = "end-height"
+	flagStartHeight = "start-height"
+	flagOrderID     = "order-id"
+	flagAddress     = "address"
+)
+
+// AddCommands adds the scheduler subcommands
+func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
+	cmd.AddCommand(
+		client.PostCommands(
+			CreateOrderCmd(cdc),
+			CancelOrderCmd(cdc),
+		)...)
+	cmd.AddCommand(
+		client.GetCommands(
+			GetOrderCmd("scheduler", cdc),
+			GetOrdersCmd("scheduler", cdc),
+		)...)
+}
+
+// CreateOrderCmd registers a standing order paying out of the coins of the key
+func CreateOrderCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-order",
+		Short: "Pay an amount of the key to a recipient every interval of blocks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			sender, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			recipient, err := sdk.GetAddress(viper.GetString(flagTo))
+			if err != nil {
+				return err
+			}
+			amount, err := sdk.ParseCoins(viper.GetString(flagAmount))
+			if err != nil {
+				return err
+			}
+			msg := scheduler.NewMsgCreateOrder(sender, recipient, amount, viper.GetInt64(flagInterval),
+				viper.GetInt64(flagRepetitions), viper.GetInt64(flagEndHeight), viper.GetInt64(flagStartHeight))
+			return ctx.SendMsg(msg, cdc)
+		},
+	}
+	cmd.Flags().String(flagTo, "", "Address of the recipient")
+	cmd.Flags().String(flagAmount, "", "Amount of coins of every payment")
+	cmd.Flags().Int64(flagInterval, 0, "Blocks between two payments")
+	cmd.Flags().Int64(flagRepetitions, 0, "Number of payments, 0 pays until the end height")
+	cmd.Flags().Int64(flagEndHeight, 0, "Height of the last possible payment, 0 pays until the repetitions run out")
+	cmd.Flags().Int64(flagStartHeight, 0, "Height of the first payment (default an interval from now)")
+	return cmd
+}
+
+// CancelOrderCmd cancels a standing order of the key
+func CancelOrderCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel-order",
+		Short: "Cancel a standing order of the key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			sender, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			return ctx.SendMsg(scheduler.NewMsgCancelOrder(sender, viper.GetInt64(flagOrderID)), cdc)
+		},
+	}
+	cmd.Flags().Int64(flagOrderID, 0, "id of the standing order")
+	return cmd
+}
+
+// GetOrderCmd queries a standing order
+func GetOrderCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "order",
+		Short: "Query a standing order",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := scheduler.QueryOrderParams{OrderID: viper.GetInt64(flagOrderID)}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, scheduler.QueryOrder, params, new(scheduler.StandingOrder), cdc)
+		},
+	}
+	cmd.Flags().Int64(flagOrderID, 0, "id of the standing order")
+	return cmd
+}
+
+// GetOrdersCmd queries the standing orders, only those of an address if given
+func GetOrdersCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "orders",
+		Short: "Query the standing orders, of which the address is the sender or the recipient if given",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var params scheduler.QueryOrdersParams
+			if addr := viper.GetString(flagAddress); addr != "" {
+				address, err := sdk.GetAddress(addr)
+				if err != nil {
+					return err
+				}
+				params.Address = address
+			}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, scheduler.QueryOrders, params, new([]scheduler.StandingOrder), cdc)
+		},
+	}
+	cmd.Flags().String(flagAddress, "", "Address of the sender or the recipient")
+	return cmd
+}
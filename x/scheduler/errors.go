@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"fmt"
+
+	sdk "inschain-tendermint/types"
+)
+
+// Scheduler errors reserve 900 ~ 999.
+const (
+	DefaultCodespace sdk.CodespaceType = 10
+
+	CodeUnknownOrder   sdk.CodeType = 901
+	CodeInvalidOrder   sdk.CodeType = 902
+	CodeInvalidParams  sdk.CodeType = 903
+	CodeTooManyOrders  sdk.CodeType = 904
+	CodeUnknownRequest sdk.CodeType = sdk.CodeUnknownRequest
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeUnknownOrder:
+		return "Unknown standing order"
+	case CodeInvalidOrder:
+		return "Invalid standing order"
+	case CodeInvalidParams:
+		return "Invalid scheduler params"
+	case CodeTooManyOrders:
+		return "Too many standing orders"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// nolint
+func ErrUnknownOrder(codespace sdk.CodespaceType, orderID int64) sdk.Error {
+	return newError(codespace, CodeUnknownOrder, fmt.Sprintf("Unknown standing order %d", orderID))
+}
+func ErrInvalidOrder(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidOrder, msg)
+}
+func ErrInvalidParams(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidParams, msg)
+}
+func ErrTooManyOrders(codespace sdk.CodespaceType, sender sdk.Address, max int64) sdk.Error {
+	return newError(codespace, CodeTooManyOrders, fmt.Sprintf("%s cannot have more than %d standing orders", sender, max))
+}
+
+// -------------------------
+// Helpers
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	msg = msgOrDefaultMsg(msg, code)
+	return sdk.NewError(codespace, code, msg)
+}
+
+func msgOrDefaultMsg(msg string, code sdk.CodeType) string {
+	if msg != "" {
+		return msg
+	}
+	return codeToDefaultMsg(code)
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"reflect"
+	"strconv"
+
+	sdk "inschain-tendermint/types"
+)
+
+// NewHandler returns a handler for "scheduler" type messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgCreateOrder:
+			return handleMsgCreateOrder(ctx, k, msg)
+		case MsgCancelOrder:
+			return handleMsgCancelOrder(ctx, k, msg)
+		default:
+			errMsg := "Unrecognized scheduler Msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgCreateOrder(ctx sdk.Context, k Keeper, msg MsgCreateOrder) sdk.Result {
+	orderID, err := k.CreateOrder(ctx, msg.Order(), msg.StartHeight)
+	if err != nil {
+		return err.Result()
+	}
+	id := []byte(strconv.FormatInt(orderID, 10))
+	return sdk.Result{
+		Data: id,
+		Tags: sdk.NewTags(
+			TagAction, ActionCreateOrder,
+			TagSender, []byte(msg.Sender.String()),
+			TagRecipient, []byte(msg.Recipient.String()),
+			TagOrderID, id,
+		),
+	}
+}
+
+func handleMsgCancelOrder(ctx sdk.Context, k Keeper, msg MsgCancelOrder) sdk.Result {
+	err := k.CancelOrder(ctx, msg.Sender, msg.OrderID)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: sdk.NewTags(
+			TagAction, ActionCancelOrder,
+			TagSender, []byte(msg.Sender.String()),
+			TagOrderID, []byte(strconv.FormatInt(msg.OrderID, 10)),
+		),
+	}
+}
+
+//_____________________________________________________________________
+
+// InitGenesis - store the genesis params and standing orders
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
+	if err := data.Params.ValidateBasic(k.codespace); err != nil {
+		panic(err)
+	}
+	k.setParams(ctx, data.Params)
+	if data.StartingOrderID > 0 {
+		k.setNextOrderID(ctx, data.StartingOrderID)
+	}
+	for _, order := range data.Orders {
+		k.scheduleOrder(ctx, order)
+	}
+}
+
+// WriteGenesis - output the params and standing orders
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	return GenesisState{
+		StartingOrderID: k.getNextOrderID(ctx),
+		Params:          k.GetParams(ctx),
+		Orders:          k.GetOrders(ctx, nil),
+	}
+}
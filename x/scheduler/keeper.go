@@ -0,0 +1,253 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/bank"
+)
+
+var (
+	// Keys for store prefixes
+	ParamsKey      = []byte{0x00} // key for the scheduler params
+	OrderKeyPrefix = []byte{0x01} // prefix for each key to a standing order, by id
+	OrderQueueKey  = []byte{0x02} // prefix for the orders, by the height of their next payment
+	NextOrderIDKey = []byte{0x03} // key for the id of the next order
+	SenderOrderKey = []byte{0x04} // prefix for the orders, by sender
+)
+
+func orderIDBytes(orderID int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(orderID))
+	return bz
+}
+
+// get the key for the standing order
+func GetOrderKey(orderID int64) []byte {
+	return append(OrderKeyPrefix, orderIDBytes(orderID)...)
+}
+
+// get the index key for the standing order of the sender
+func GetSenderOrderKey(sender sdk.Address, orderID int64) []byte {
+	return append(GetSenderOrdersKey(sender), orderIDBytes(orderID)...)
+}
+
+// get the prefix for the standing orders of the sender
+func GetSenderOrdersKey(sender sdk.Address) []byte {
+	return append(SenderOrderKey, sender.Bytes()...)
+}
+
+// get the key of the order in the queue, ordered by the height of its next payment
+func getOrderQueueKey(height int64, orderID int64) []byte {
+	heightBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(heightBytes, uint64(height))
+	key := append(append([]byte{}, OrderQueueKey...), heightBytes...)
+	return append(key, orderIDBytes(orderID)...)
+}
+
+// Keeper manages the standing orders, paid through the bank keeper
+type Keeper struct {
+	key        sdk.StoreKey
+	cdc        *wire.Codec
+	coinKeeper bank.Keeper
+	codespace  sdk.CodespaceType
+}
+
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		key:        key,
+		cdc:        cdc,
+		coinKeeper: ck,
+		codespace:  codespace,
+	}
+}
+
+// GetParams returns the scheduler params
+func (k Keeper) GetParams(ctx sdk.Context) (params Params) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(ParamsKey)
+	if bz == nil {
+		panic("scheduler params have not been initialized")
+	}
+	k.mustUnmarshal(bz, &params)
+	return params
+}
+
+func (k Keeper) setParams(ctx sdk.Context, params Params) {
+	store := ctx.KVStore(k.key)
+	store.Set(ParamsKey, k.mustMarshal(params))
+}
+
+// SetParam sets a single scheduler param from its JSON name and value
+func (k Keeper) SetParam(ctx sdk.Context, key, value string) sdk.Error {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(k.mustMarshal(k.GetParams(ctx)), &fields)
+	if err != nil {
+		panic(err)
+	}
+	if _, ok := fields[key]; !ok {
+		return ErrInvalidParams(k.codespace, fmt.Sprintf("unknown scheduler param %s", key))
+	}
+	fields[key] = json.RawMessage(value)
+	bz, err := json.Marshal(fields)
+	if err != nil {
+		return ErrInvalidParams(k.codespace, fmt.Sprintf("invalid value for scheduler param %s", key))
+	}
+	var params Params
+	err = k.cdc.UnmarshalJSON(bz, &params)
+	if err != nil {
+		return ErrInvalidParams(k.codespace, fmt.Sprintf("invalid value for scheduler param %s: %v", key, err))
+	}
+	if err := params.ValidateBasic(k.codespace); err != nil {
+		return err
+	}
+	k.setParams(ctx, params)
+	return nil
+}
+
+//_______________________________________________________________________
+
+// GetOrder returns the standing order with the id
+func (k Keeper) GetOrder(ctx sdk.Context, orderID int64) (order StandingOrder, found bool) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetOrderKey(orderID))
+	if bz == nil {
+		return order, false
+	}
+	k.mustUnmarshal(bz, &order)
+	return order, true
+}
+
+// GetOrders returns the standing orders by id, only those of which the addr
+// is the sender or the recipient if not empty
+func (k Keeper) GetOrders(ctx sdk.Context, addr sdk.Address) []StandingOrder {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(OrderKeyPrefix)
+	defer iterator.Close()
+
+	orders := []StandingOrder{}
+	for ; iterator.Valid(); iterator.Next() {
+		var order StandingOrder
+		k.mustUnmarshal(iterator.Value(), &order)
+		if len(addr) != 0 && !bytes.Equal(order.Sender, addr) && !bytes.Equal(order.Recipient, addr) {
+			continue
+		}
+		orders = append(orders, order)
+	}
+	return orders
+}
+
+func (k Keeper) setOrder(ctx sdk.Context, order StandingOrder) {
+	store := ctx.KVStore(k.key)
+	store.Set(GetOrderKey(order.ID), k.mustMarshal(order))
+}
+
+// store the order, index it by sender and queue it by the height of its next
+// payment
+func (k Keeper) scheduleOrder(ctx sdk.Context, order StandingOrder) {
+	k.setOrder(ctx, order)
+	store := ctx.KVStore(k.key)
+	store.Set(GetSenderOrderKey(order.Sender, order.ID), orderIDBytes(order.ID))
+	store.Set(getOrderQueueKey(order.NextHeight, order.ID), orderIDBytes(order.ID))
+}
+
+// delete the order with its index and queue entries, if still queued
+func (k Keeper) deleteOrder(ctx sdk.Context, order StandingOrder) {
+	store := ctx.KVStore(k.key)
+	store.Delete(getOrderQueueKey(order.NextHeight, order.ID))
+	store.Delete(GetSenderOrderKey(order.Sender, order.ID))
+	store.Delete(GetOrderKey(order.ID))
+}
+
+// count the standing orders of the sender, up to max
+func (k Keeper) countSenderOrders(ctx sdk.Context, sender sdk.Address, max int64) (count int64) {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(GetSenderOrdersKey(sender))
+	defer iterator.Close()
+	for ; iterator.Valid() && count < max; iterator.Next() {
+		count++
+	}
+	return count
+}
+
+// the ids start at 1
+func (k Keeper) getNextOrderID(ctx sdk.Context) (orderID int64) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(NextOrderIDKey)
+	if bz == nil {
+		return 1
+	}
+	k.mustUnmarshal(bz, &orderID)
+	return orderID
+}
+
+func (k Keeper) setNextOrderID(ctx sdk.Context, orderID int64) {
+	store := ctx.KVStore(k.key)
+	store.Set(NextOrderIDKey, k.mustMarshal(orderID))
+}
+
+// CreateOrder registers the standing order, its first payment is made at the
+// start height, or an interval after the current height if zero. A sender has
+// at most MaxOrdersPerSender orders, so the orders of one sender can't keep
+// the others waiting. It returns the id of the order.
+func (k Keeper) CreateOrder(ctx sdk.Context, order StandingOrder, startHeight int64) (int64, sdk.Error) {
+	if err := order.ValidateBasic(); err != nil {
+		return 0, err
+	}
+	maxOrders := k.GetParams(ctx).MaxOrdersPerSender
+	if k.countSenderOrders(ctx, order.Sender, maxOrders) >= maxOrders {
+		return 0, ErrTooManyOrders(k.codespace, order.Sender, maxOrders)
+	}
+	if startHeight == 0 {
+		startHeight = ctx.BlockHeight() + order.Interval
+	}
+	if startHeight <= ctx.BlockHeight() {
+		return 0, ErrInvalidOrder(k.codespace, fmt.Sprintf("the start height must be after the current height %d", ctx.BlockHeight()))
+	}
+
+	order.ID = k.getNextOrderID(ctx)
+	order.NextHeight = startHeight
+	order.Misses = 0
+	order.LastMissHeight = 0
+	if order.pastEndHeight() {
+		return 0, ErrInvalidOrder(k.codespace, fmt.Sprintf("the end height %d comes before the first payment at %d", order.EndHeight, order.NextHeight))
+	}
+	k.scheduleOrder(ctx, order)
+	k.setNextOrderID(ctx, order.ID+1)
+	return order.ID, nil
+}
+
+// CancelOrder deletes the standing order of the sender, no payment of it is
+// made anymore
+func (k Keeper) CancelOrder(ctx sdk.Context, sender sdk.Address, orderID int64) sdk.Error {
+	order, found := k.GetOrder(ctx, orderID)
+	if !found {
+		return ErrUnknownOrder(k.codespace, orderID)
+	}
+	if !bytes.Equal(order.Sender, sender) {
+		return ErrInvalidOrder(k.codespace, fmt.Sprintf("%s is not the sender of the order %d", sender, orderID))
+	}
+	k.deleteOrder(ctx, order)
+	return nil
+}
+
+//_______________________________________________________________________
+
+func (k Keeper) mustMarshal(o interface{}) []byte {
+	bz, err := k.cdc.MarshalJSON(o)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+func (k Keeper) mustUnmarshal(bz []byte, ptr interface{}) {
+	err := k.cdc.UnmarshalJSON(bz, ptr)
+	if err != nil {
+		panic(err)
+	}
+}
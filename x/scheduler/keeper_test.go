@@ -0,0 +1,225 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/tendermint/tmlibs/log"
+
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+)
+
+var (
+	sender    = sdk.Address([]byte("sender"))
+	recipient = sdk.Address([]byte("recipient"))
+)
+
+func createTestInput(t *testing.T) (sdk.Context, bank.Keeper, Keeper) {
+	db := dbm.NewMemDB()
+	authKey := sdk.NewKVStoreKey("acc")
+	key := sdk.NewKVStoreKey("scheduler")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(authKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	err := ms.LoadLatestVersion()
+	require.Nil(t, err)
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "foochainid", Height: 10}, false, nil, log.NewNopLogger())
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+	RegisterWire(cdc)
+
+	ck := bank.NewKeeper(auth.NewAccountMapper(cdc, authKey, &auth.BaseAccount{}))
+	keeper := NewKeeper(cdc, key, ck, DefaultCodespace)
+	InitGenesis(ctx, keeper, DefaultGenesisState())
+	return ctx, ck, keeper
+}
+
+func TestCreateCancelOrder(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	handler := NewHandler(keeper)
+
+	// the first payment must come after the current height and before the end height
+	res := handler(ctx, NewMsgCreateOrder(sender, recipient, sdk.Coins{{"foo", 10}}, 5, 0, 20, 10))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidOrder), res.Code)
+	res = handler(ctx, NewMsgCreateOrder(sender, recipient, sdk.Coins{{"foo", 10}}, 5, 0, 12, 0))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidOrder), res.Code)
+
+	res = handler(ctx, NewMsgCreateOrder(sender, recipient, sdk.Coins{{"foo", 10}}, 5, 3, 0, 0))
+	require.True(t, res.IsOK(), "%v", res)
+	assert.Equal(t, []byte("1"), res.Data)
+	order, found := keeper.GetOrder(ctx, 1)
+	require.True(t, found)
+	assert.Equal(t, int64(15), order.NextHeight)
+	assert.Equal(t, []StandingOrder{order}, keeper.GetOrders(ctx, recipient))
+	assert.Equal(t, 0, len(keeper.GetOrders(ctx, sdk.Address([]byte("other")))))
+
+	// only the sender cancels
+	res = handler(ctx, NewMsgCancelOrder(recipient, 1))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidOrder), res.Code)
+	res = handler(ctx, NewMsgCancelOrder(sender, 1))
+	require.True(t, res.IsOK(), "%v", res)
+	_, found = keeper.GetOrder(ctx, 1)
+	assert.False(t, found)
+	res = handler(ctx, NewMsgCancelOrder(sender, 1))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeUnknownOrder), res.Code)
+
+	// the cancelled order is not paid
+	EndBlocker(ctx.WithBlockHeight(15), keeper)
+	assert.Equal(t, 0, len(keeper.GetOrders(ctx, nil)))
+}
+
+func TestExecuteOrders(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	_, err := ck.AddCoins(ctx, sender, sdk.Coins{{"foo", 100}})
+	require.Nil(t, err)
+
+	// three payments from height 12, and payments until height 20
+	_, err = keeper.CreateOrder(ctx, StandingOrder{Sender: sender, Recipient: recipient,
+		Amount: sdk.Coins{{"foo", 10}}, Interval: 2, Repetitions: 3}, 12)
+	require.Nil(t, err)
+	_, err = keeper.CreateOrder(ctx, StandingOrder{Sender: sender, Recipient: recipient,
+		Amount: sdk.Coins{{"foo", 1}}, Interval: 5, EndHeight: 20}, 0)
+	require.Nil(t, err)
+
+	for height := int64(11); height <= 30; height++ {
+		EndBlocker(ctx.WithBlockHeight(height), keeper)
+	}
+	// paid at 12, 14, 16 then at 15 and 20
+	assert.True(t, ck.GetCoins(ctx, recipient).IsEqual(sdk.Coins{{"foo", 32}}))
+	assert.True(t, ck.GetCoins(ctx, sender).IsEqual(sdk.Coins{{"foo", 68}}))
+	assert.Equal(t, 0, len(keeper.GetOrders(ctx, nil)))
+}
+
+func TestMissedPayments(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	_, err := ck.AddCoins(ctx, sender, sdk.Coins{{"foo", 15}})
+	require.Nil(t, err)
+	_, err = keeper.CreateOrder(ctx, StandingOrder{Sender: sender, Recipient: recipient,
+		Amount: sdk.Coins{{"foo", 10}}, Interval: 1, Repetitions: 10}, 11)
+	require.Nil(t, err)
+
+	// the second payment is missed, the coins of the sender untouched
+	EndBlocker(ctx.WithBlockHeight(11), keeper)
+	EndBlocker(ctx.WithBlockHeight(12), keeper)
+	order, found := keeper.GetOrder(ctx, 1)
+	require.True(t, found)
+	assert.Equal(t, int64(1), order.Misses)
+	assert.Equal(t, int64(12), order.LastMissHeight)
+	assert.Equal(t, int64(9), order.Repetitions)
+	assert.True(t, ck.GetCoins(ctx, sender).IsEqual(sdk.Coins{{"foo", 5}}))
+
+	// a payment resets the misses
+	_, err = ck.AddCoins(ctx, sender, sdk.Coins{{"foo", 5}})
+	require.Nil(t, err)
+	EndBlocker(ctx.WithBlockHeight(13), keeper)
+	order, _ = keeper.GetOrder(ctx, 1)
+	assert.Equal(t, int64(0), order.Misses)
+	assert.Equal(t, int64(8), order.Repetitions)
+
+	// the order is cancelled after max misses in a row
+	maxMisses := keeper.GetParams(ctx).MaxMisses
+	for height := int64(14); height < 14+maxMisses-1; height++ {
+		EndBlocker(ctx.WithBlockHeight(height), keeper)
+	}
+	_, found = keeper.GetOrder(ctx, 1)
+	require.True(t, found)
+	EndBlocker(ctx.WithBlockHeight(14+maxMisses-1), keeper)
+	_, found = keeper.GetOrder(ctx, 1)
+	assert.False(t, found)
+	assert.True(t, ck.GetCoins(ctx, recipient).IsEqual(sdk.Coins{{"foo", 20}}))
+}
+
+func TestMaxOrdersPerBlock(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	_, err := ck.AddCoins(ctx, sender, sdk.Coins{{"foo", 100}})
+	require.Nil(t, err)
+	require.Nil(t, keeper.SetParam(ctx, "max_orders_per_block", "2"))
+	assert.NotNil(t, keeper.SetParam(ctx, "max_orders_per_block", "0"))
+	assert.NotNil(t, keeper.SetParam(ctx, "unknown", "1"))
+
+	for i := 0; i < 3; i++ {
+		_, err = keeper.CreateOrder(ctx, StandingOrder{Sender: sender, Recipient: recipient,
+			Amount: sdk.Coins{{"foo", 1}}, Interval: 10, Repetitions: 1}, 11)
+		require.Nil(t, err)
+	}
+
+	// the third order waits for the next block
+	EndBlocker(ctx.WithBlockHeight(11), keeper)
+	assert.True(t, ck.GetCoins(ctx, recipient).IsEqual(sdk.Coins{{"foo", 2}}))
+	_, found := keeper.GetOrder(ctx, 3)
+	assert.True(t, found)
+	EndBlocker(ctx.WithBlockHeight(12), keeper)
+	assert.True(t, ck.GetCoins(ctx, recipient).IsEqual(sdk.Coins{{"foo", 3}}))
+	assert.Equal(t, 0, len(keeper.GetOrders(ctx, nil)))
+}
+
+func TestMaxOrdersPerSender(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	spammer := sdk.Address([]byte("spammer"))
+	for _, addr := range []sdk.Address{sender, spammer} {
+		_, err := ck.AddCoins(ctx, addr, sdk.Coins{{"foo", 100}})
+		require.Nil(t, err)
+	}
+	require.Nil(t, keeper.SetParam(ctx, "max_orders_per_block", "2"))
+	require.Nil(t, keeper.SetParam(ctx, "max_orders_per_sender", "2"))
+	assert.NotNil(t, keeper.SetParam(ctx, "max_orders_per_sender", "0"))
+
+	// an order paid at 12, then the spammer tries to fill the blocks with
+	// orders paid every block from 11
+	_, err := keeper.CreateOrder(ctx, StandingOrder{Sender: sender, Recipient: recipient,
+		Amount: sdk.Coins{{"foo", 1}}, Interval: 10, Repetitions: 1}, 12)
+	require.Nil(t, err)
+	spam := StandingOrder{Sender: spammer, Recipient: spammer, Amount: sdk.Coins{{"foo", 1}}, Interval: 1, Repetitions: 50}
+	for i := 0; i < 5; i++ {
+		_, err = keeper.CreateOrder(ctx, spam, 11)
+		if i < 2 {
+			require.Nil(t, err)
+			continue
+		}
+		require.NotNil(t, err)
+		assert.Equal(t, CodeTooManyOrders, err.Code())
+	}
+	assert.Equal(t, 3, len(keeper.GetOrders(ctx, nil)))
+
+	// the existing order is paid on time, not after the late spam
+	EndBlocker(ctx.WithBlockHeight(11), keeper)
+	EndBlocker(ctx.WithBlockHeight(12), keeper)
+	assert.True(t, ck.GetCoins(ctx, recipient).IsEqual(sdk.Coins{{"foo", 1}}))
+	_, found := keeper.GetOrder(ctx, 1)
+	assert.False(t, found)
+	assert.Equal(t, int64(0), keeper.countSenderOrders(ctx, sender, 10))
+
+	// a cancelled order frees a slot of the sender
+	require.Nil(t, keeper.CancelOrder(ctx, spammer, 2))
+	_, err = keeper.CreateOrder(ctx, spam, 13)
+	assert.Nil(t, err)
+}
+
+func TestGenesis(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	_, err := keeper.CreateOrder(ctx, StandingOrder{Sender: sender, Recipient: recipient,
+		Amount: sdk.Coins{{"foo", 1}}, Interval: 2, Repetitions: 2}, 0)
+	require.Nil(t, err)
+	genesis := WriteGenesis(ctx, keeper)
+	assert.Equal(t, int64(2), genesis.StartingOrderID)
+	assert.Equal(t, 1, len(genesis.Orders))
+
+	// the orders are queued again from the genesis
+	ctx2, ck2, keeper2 := createTestInput(t)
+	InitGenesis(ctx2, keeper2, genesis)
+	assert.Equal(t, genesis, WriteGenesis(ctx2, keeper2))
+	_, err = ck2.AddCoins(ctx2, sender, sdk.Coins{{"foo", 1}})
+	require.Nil(t, err)
+	EndBlocker(ctx2.WithBlockHeight(12), keeper2)
+	assert.True(t, ck2.GetCoins(ctx2, recipient).IsEqual(sdk.Coins{{"foo", 1}}))
+	assert.True(t, ck.GetCoins(ctx, recipient).IsZero())
+}
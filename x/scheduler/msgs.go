@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"encoding/json"
+
+	sdk "inschain-tendermint/types"
+)
+
+// name to identify the scheduler msgs
+const MsgType = "scheduler"
+
+//----------------------------------------
+// MsgCreateOrder
+
+// MsgCreateOrder - the sender registers a standing order, see StandingOrder.
+// The first payment is made at the end of the start height, or an interval
+// after the height of the msg if zero.
+type MsgCreateOrder struct {
+	Sender      sdk.Address `json:"sender"`
+	Recipient   sdk.Address `json:"recipient"`
+	Amount      sdk.Coins   `json:"amount"`
+	Interval    int64       `json:"interval"`
+	Repetitions int64       `json:"repetitions"`
+	EndHeight   int64       `json:"end_height"`
+	StartHeight int64       `json:"start_height"`
+}
+
+var _ sdk.Msg = MsgCreateOrder{}
+
+// NewMsgCreateOrder - construct the msg registering the standing order
+func NewMsgCreateOrder(sender, recipient sdk.Address, amount sdk.Coins,
+	interval, repetitions, endHeight, startHeight int64) MsgCreateOrder {
+
+	return MsgCreateOrder{
+		Sender:      sender,
+		Recipient:   recipient,
+		Amount:      amount,
+		Interval:    interval,
+		Repetitions: repetitions,
+		EndHeight:   endHeight,
+		StartHeight: startHeight,
+	}
+}
+
+// Order returns the standing order registered by the msg
+func (msg MsgCreateOrder) Order() StandingOrder {
+	return StandingOrder{
+		Sender:      msg.Sender,
+		Recipient:   msg.Recipient,
+		Amount:      msg.Amount,
+		Interval:    msg.Interval,
+		Repetitions: msg.Repetitions,
+		EndHeight:   msg.EndHeight,
+	}
+}
+
+// Implements Msg.
+func (msg MsgCreateOrder) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgCreateOrder) ValidateBasic() sdk.Error {
+	if msg.StartHeight < 0 {
+		return ErrInvalidOrder(DefaultCodespace, "the start height must not be negative")
+	}
+	return msg.Order().ValidateBasic()
+}
+
+// Implements Msg.
+func (msg MsgCreateOrder) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgCreateOrder) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Sender}
+}
+
+//----------------------------------------
+// MsgCancelOrder
+
+// MsgCancelOrder - the sender cancels its standing order
+type MsgCancelOrder struct {
+	Sender  sdk.Address `json:"sender"`
+	OrderID int64       `json:"order_id"`
+}
+
+var _ sdk.Msg = MsgCancelOrder{}
+
+// NewMsgCancelOrder - construct the msg cancelling the standing order
+func NewMsgCancelOrder(sender sdk.Address, orderID int64) MsgCancelOrder {
+	return MsgCancelOrder{Sender: sender, OrderID: orderID}
+}
+
+// Implements Msg.
+func (msg MsgCancelOrder) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgCancelOrder) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+	if msg.OrderID <= 0 {
+		return ErrUnknownOrder(DefaultCodespace, msg.OrderID)
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgCancelOrder) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgCancelOrder) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Sender}
+}
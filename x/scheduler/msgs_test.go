@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sdk "inschain-tendermint/types"
+)
+
+func TestMsgCreateOrderValidation(t *testing.T) {
+	var emptyAddr sdk.Address
+	amount := sdk.Coins{{"foo", 10}}
+
+	cases := []struct {
+		valid bool
+		msg   MsgCreateOrder
+	}{
+		{true, NewMsgCreateOrder(sender, recipient, amount, 10, 12, 0, 0)},
+		{true, NewMsgCreateOrder(sender, recipient, amount, 10, 0, 1000, 20)},
+		{true, NewMsgCreateOrder(sender, recipient, amount, 10, 12, 1000, 0)},
+		{false, NewMsgCreateOrder(emptyAddr, recipient, amount, 10, 12, 0, 0)},
+		{false, NewMsgCreateOrder(sender, emptyAddr, amount, 10, 12, 0, 0)},
+		{false, NewMsgCreateOrder(sender, recipient, nil, 10, 12, 0, 0)},
+		{false, NewMsgCreateOrder(sender, recipient, sdk.Coins{{"foo", 0}}, 10, 12, 0, 0)},
+		{false, NewMsgCreateOrder(sender, recipient, amount, 0, 12, 0, 0)},
+		{false, NewMsgCreateOrder(sender, recipient, amount, 10, 0, 0, 0)},
+		{false, NewMsgCreateOrder(sender, recipient, amount, 10, -1, 1000, 0)},
+		{false, NewMsgCreateOrder(sender, recipient, amount, 10, 12, 0, -1)},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.ValidateBasic()
+		if tc.valid {
+			assert.Nil(t, err, "%d: %+v", i, err)
+		} else {
+			assert.NotNil(t, err, "%d", i)
+		}
+	}
+
+	assert.NotNil(t, NewMsgCancelOrder(sender, 0).ValidateBasic())
+	assert.NotNil(t, NewMsgCancelOrder(emptyAddr, 1).ValidateBasic())
+	msg := NewMsgCancelOrder(sender, 1)
+	assert.Nil(t, msg.ValidateBasic())
+	assert.Equal(t, MsgType, msg.Type())
+	assert.Equal(t, []sdk.Address{sender}, msg.GetSigners())
+}
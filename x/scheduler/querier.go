@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+// query endpoints supported by the scheduler querier, at /custom/scheduler/<route>
+const (
+	QueryOrder  = "order"
+	QueryOrders = "orders"
+	QueryParams = "params"
+)
+
+// parameters of the standing order query
+type QueryOrderParams struct {
+	OrderID int64 `json:"order_id"`
+}
+
+// parameters of the query of the standing orders, only those of which the
+// address is the sender or the recipient if not empty
+type QueryOrdersParams struct {
+	Address sdk.Address `json:"address"`
+}
+
+// NewQuerier returns the querier of the scheduler module
+func NewQuerier(k Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("No scheduler query endpoint specified")
+		}
+		switch path[0] {
+		case QueryOrder:
+			return queryOrder(ctx, req, k)
+		case QueryOrders:
+			return queryOrders(ctx, req, k)
+		case QueryParams:
+			return k.marshalQueryResult(k.GetParams(ctx))
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Unknown scheduler query endpoint %s", path[0]))
+		}
+	}
+}
+
+func queryOrder(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryOrderParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	order, found := k.GetOrder(ctx, params.OrderID)
+	if !found {
+		return nil, ErrUnknownOrder(k.codespace, params.OrderID)
+	}
+	return k.marshalQueryResult(order)
+}
+
+func queryOrders(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryOrdersParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	return k.marshalQueryResult(k.GetOrders(ctx, params.Address))
+}
+
+func (k Keeper) unmarshalQueryParams(req abci.RequestQuery, params interface{}) sdk.Error {
+	err := k.cdc.UnmarshalJSON(req.Data, params)
+	if err != nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("Incorrectly formatted query data: %s", err.Error()))
+	}
+	return nil
+}
+
+func (k Keeper) marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	return k.mustMarshal(res), nil
+}
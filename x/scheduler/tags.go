@@ -0,0 +1,16 @@
+package scheduler
+
+// Tags attached to the results of scheduler messages, indexed by Tendermint.
+// Addresses are tagged in upper case hex.
+const (
+	TagAction    = "action"
+	TagSender    = "sender"
+	TagRecipient = "recipient"
+	TagOrderID   = "order-id"
+)
+
+// Values of the action tag
+var (
+	ActionCreateOrder = []byte("create-order")
+	ActionCancelOrder = []byte("cancel-order")
+)
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"encoding/binary"
+
+	sdk "inschain-tendermint/types"
+)
+
+// EndBlocker pays the standing orders which are due, at most
+// MaxOrdersPerBlock of them: the others stay queued and are paid first in
+// the next blocks. A failed payment is missed, and its order cancelled after
+// MaxMisses misses in a row.
+func EndBlocker(ctx sdk.Context, k Keeper) {
+	logger := ctx.Logger().With("module", "x/scheduler")
+	params := k.GetParams(ctx)
+
+	for _, orderID := range k.dequeueDueOrders(ctx, params.MaxOrdersPerBlock) {
+		order, found := k.GetOrder(ctx, orderID)
+		if !found {
+			continue
+		}
+
+		// the payment is made in full or not at all
+		cacheCtx, writeCache := ctx.CacheContext()
+		err := k.coinKeeper.SendCoins(cacheCtx, order.Sender, order.Recipient, order.Amount)
+		done := false
+		if err == nil {
+			writeCache()
+			order.Misses = 0
+			if order.Repetitions > 0 {
+				order.Repetitions--
+				done = order.Repetitions == 0
+			}
+			logger.Info("Standing order paid", "order", orderID, "amount", order.Amount.String())
+		} else {
+			order.Misses++
+			order.LastMissHeight = ctx.BlockHeight()
+			logger.Info("Standing order missed", "order", orderID, "misses", order.Misses, "err", err.Error())
+			if order.Misses >= params.MaxMisses {
+				k.deleteOrder(ctx, order)
+				logger.Info("Standing order cancelled", "order", orderID)
+				continue
+			}
+		}
+
+		// a late payment doesn't shift the schedule of the next ones
+		order.NextHeight += order.Interval
+		if done || order.pastEndHeight() {
+			k.deleteOrder(ctx, order)
+			logger.Info("Standing order done", "order", orderID)
+			continue
+		}
+		k.scheduleOrder(ctx, order)
+	}
+}
+
+// remove at most max queue entries of the orders due by the current height,
+// the earliest first, returning their ids
+func (k Keeper) dequeueDueOrders(ctx sdk.Context, max int64) (orderIDs []int64) {
+	store := ctx.KVStore(k.key)
+	iterator := store.Iterator(OrderQueueKey, getOrderQueueKey(ctx.BlockHeight()+1, 0))
+	var keys [][]byte
+	for ; iterator.Valid() && int64(len(keys)) < max; iterator.Next() {
+		keys = append(keys, iterator.Key())
+		orderIDs = append(orderIDs, int64(binary.BigEndian.Uint64(iterator.Value())))
+	}
+	iterator.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+	return orderIDs
+}
@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	sdk "inschain-tendermint/types"
+)
+
+// GenesisState - all scheduler state that must be provided at genesis
+type GenesisState struct {
+	StartingOrderID int64           `json:"starting_order_id"`
+	Params          Params          `json:"params"`
+	Orders          []StandingOrder `json:"orders"`
+}
+
+// Params defines how much work the scheduler does every block
+type Params struct {
+	MaxOrdersPerBlock  int64 `json:"max_orders_per_block"`  // due orders executed at most every block, the others wait for the next blocks
+	MaxMisses          int64 `json:"max_misses"`            // payments missed in a row after which an order is cancelled
+	MaxOrdersPerSender int64 `json:"max_orders_per_sender"` // standing orders a sender can have at once, so no sender fills the blocks
+}
+
+// DefaultParams - the params used when the genesis does not set them
+func DefaultParams() Params {
+	return Params{
+		MaxOrdersPerBlock:  100,
+		MaxMisses:          3,
+		MaxOrdersPerSender: 10,
+	}
+}
+
+// DefaultGenesisState - the scheduler genesis with the default params
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		StartingOrderID: 1,
+		Params:          DefaultParams(),
+	}
+}
+
+// ValidateBasic checks the params are consistent
+func (p Params) ValidateBasic(codespace sdk.CodespaceType) sdk.Error {
+	if p.MaxOrdersPerBlock <= 0 {
+		return ErrInvalidParams(codespace, "max orders per block must be positive")
+	}
+	if p.MaxMisses <= 0 {
+		return ErrInvalidParams(codespace, "max misses must be positive")
+	}
+	if p.MaxOrdersPerSender <= 0 {
+		return ErrInvalidParams(codespace, "max orders per sender must be positive")
+	}
+	return nil
+}
+
+//_______________________________________________________________________
+
+// StandingOrder pays the Amount of the Sender to the Recipient every Interval
+// blocks from the NextHeight on, at the end of the block. The order is done
+// once Repetitions payments are made, or once the next payment would come
+// after the EndHeight, whichever comes first; one of them at least must be
+// set. A payment failing, for want of coins foremost, is missed and not
+// retried: the order is cancelled after MaxMisses payments missed in a row.
+type StandingOrder struct {
+	ID             int64       `json:"id"`
+	Sender         sdk.Address `json:"sender"`
+	Recipient      sdk.Address `json:"recipient"`
+	Amount         sdk.Coins   `json:"amount"`
+	Interval       int64       `json:"interval"`         // blocks between two payments
+	Repetitions    int64       `json:"repetitions"`      // payments left to make, 0 pays until the end height
+	EndHeight      int64       `json:"end_height"`       // height of the last possible payment, 0 pays until the repetitions run out
+	NextHeight     int64       `json:"next_height"`      // height at the end of which the next payment is made
+	Misses         int64       `json:"misses"`           // payments missed in a row
+	LastMissHeight int64       `json:"last_miss_height"` // height of the last missed payment, 0 if none
+}
+
+// ValidateBasic checks the order is well formed
+func (o StandingOrder) ValidateBasic() sdk.Error {
+	if len(o.Sender) == 0 {
+		return sdk.ErrInvalidAddress(o.Sender.String())
+	}
+	if len(o.Recipient) == 0 {
+		return sdk.ErrInvalidAddress(o.Recipient.String())
+	}
+	if !o.Amount.IsValid() || !o.Amount.IsPositive() {
+		return sdk.ErrInvalidCoins(o.Amount.String())
+	}
+	if o.Interval <= 0 {
+		return ErrInvalidOrder(DefaultCodespace, "the interval must be positive")
+	}
+	if o.Repetitions < 0 || o.EndHeight < 0 {
+		return ErrInvalidOrder(DefaultCodespace, "the repetitions and the end height must not be negative")
+	}
+	if o.Repetitions == 0 && o.EndHeight == 0 {
+		return ErrInvalidOrder(DefaultCodespace, "either the repetitions or the end height must be set")
+	}
+	return nil
+}
+
+// whether the next payment of the order would come after its end height
+func (o StandingOrder) pastEndHeight() bool {
+	return o.EndHeight != 0 && o.NextHeight > o.EndHeight
+}
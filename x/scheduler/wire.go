@@ -0,0 +1,11 @@
+package scheduler
+
+import (
+	"inschain-tendermint/wire"
+)
+
+// Register concrete types on wire codec
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgCreateOrder{}, "scheduler/CreateOrder", nil)
+	cdc.RegisterConcrete(MsgCancelOrder{}, "scheduler/CancelOrder", nil)
+}
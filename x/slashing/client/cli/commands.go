@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"inschain-tendermint/client"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+
+	"inschain-tendermint/x/slashing"
+)
+
+const (
+	flagAddressCandidate = "address-candidate"
+)
+
+// AddCommands adds the slashing subcommands
+func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
+	cmd.AddCommand(
+		client.PostCommands(
+			UnjailCmd(cdc),
+		)...)
+	cmd.AddCommand(
+		client.GetCommands(
+			GetSigningInfoCmd("slashing", cdc),
+			GetParamsCmd("slashing", cdc),
+		)...)
+}
+
+// UnjailCmd lets the candidate of the key back into the validator set
+func UnjailCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unjail",
+		Short: "Unjail the candidate of the key once its jail time is over",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+			candidateAddr, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			msg := slashing.NewMsgUnjail(candidateAddr)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			if ctx.GenerateOnly {
+				return ctx.PrintUnsignedTx([]sdk.Msg{msg}, cdc)
+			}
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+	return cmd
+}
+
+// GetSigningInfoCmd queries the liveness of a candidate
+func GetSigningInfoCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signing-info",
+		Short: "Query the signed blocks and jail time of a candidate",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			candidateAddr, err := sdk.GetAddress(viper.GetString(flagAddressCandidate))
+			if err != nil {
+				return err
+			}
+			params := slashing.QuerySigningInfoParams{CandidateAddr: candidateAddr}
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, slashing.QuerySigningInfo, params, new(slashing.ValidatorSigningInfo), cdc)
+		},
+	}
+	cmd.Flags().String(flagAddressCandidate, "", "hex address of the candidate")
+	return cmd
+}
+
+// GetParamsCmd queries the slashing params
+func GetParamsCmd(queryRoute string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "slashing-params",
+		Short: "Query the slashing params",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return context.NewCoreContextFromViper().PrintQueryCustom(queryRoute, slashing.QueryParams, struct{}{}, new(slashing.Params), cdc)
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,66 @@
+package slashing
+
+import (
+	sdk "inschain-tendermint/types"
+)
+
+// Slashing errors reserve 700 ~ 799.
+const (
+	DefaultCodespace sdk.CodespaceType = 8
+
+	CodeInvalidValidator   sdk.CodeType = 701
+	CodeValidatorNotJailed sdk.CodeType = 702
+	CodeValidatorJailed    sdk.CodeType = 703
+	CodeNoSigningInfo      sdk.CodeType = 704
+	CodeInvalidParams      sdk.CodeType = 705
+	CodeUnknownRequest     sdk.CodeType = sdk.CodeUnknownRequest
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeInvalidValidator:
+		return "Candidate does not exist for that address"
+	case CodeValidatorNotJailed:
+		return "Validator is not jailed"
+	case CodeValidatorJailed:
+		return "Validator is still jailed, cannot yet be unjailed"
+	case CodeNoSigningInfo:
+		return "No signing info for the validator"
+	case CodeInvalidParams:
+		return "Invalid slashing params"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// nolint
+func ErrInvalidValidator(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidValidator, "")
+}
+func ErrValidatorNotJailed(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeValidatorNotJailed, "")
+}
+func ErrValidatorJailed(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeValidatorJailed, "")
+}
+func ErrNoSigningInfo(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeNoSigningInfo, "")
+}
+func ErrInvalidParams(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidParams, msg)
+}
+
+// -------------------------
+// Helpers
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	msg = msgOrDefaultMsg(msg, code)
+	return sdk.NewError(codespace, code, msg)
+}
+
+func msgOrDefaultMsg(msg string, code sdk.CodeType) string {
+	if msg != "" {
+		return msg
+	}
+	return codeToDefaultMsg(code)
+}
@@ -0,0 +1,50 @@
+package slashing
+
+import (
+	"reflect"
+
+	sdk "inschain-tendermint/types"
+)
+
+// NewHandler returns a handler for "slashing" type messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgUnjail:
+			return handleMsgUnjail(ctx, k, msg)
+		default:
+			errMsg := "Unrecognized slashing Msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgUnjail(ctx sdk.Context, k Keeper, msg MsgUnjail) sdk.Result {
+	err := k.Unjail(ctx, msg.CandidateAddr)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: sdk.NewTags(
+			TagAction, ActionUnjail,
+			TagCandidate, []byte(msg.CandidateAddr.String()),
+		),
+	}
+}
+
+//_____________________________________________________________________
+
+// InitGenesis - store genesis parameters
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
+	if err := data.Params.ValidateBasic(k.codespace); err != nil {
+		panic(err)
+	}
+	k.setParams(ctx, data.Params)
+}
+
+// WriteGenesis - output genesis parameters
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	return GenesisState{
+		Params: k.GetParams(ctx),
+	}
+}
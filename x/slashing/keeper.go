@@ -0,0 +1,280 @@
+package slashing
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/stake"
+)
+
+var (
+	// Keys for store prefixes
+	ParamsKey                   = []byte{0x00} // key for the slashing params
+	ValidatorSigningInfoKey     = []byte{0x01} // prefix for the signing info, by consensus pubkey address
+	ValidatorMissedBlockKey     = []byte{0x02} // prefix for the missed block bit array, by consensus pubkey address
+	ValidatorMissedBlockByteVal = []byte{0x01} // value stored for a missed block
+)
+
+// get the key for the signing info of the validator
+func GetValidatorSigningInfoKey(pubKeyAddr sdk.Address) []byte {
+	return append(ValidatorSigningInfoKey, pubKeyAddr.Bytes()...)
+}
+
+// get the key prefix for the missed blocks of the validator
+func GetValidatorMissedBlocksKey(pubKeyAddr sdk.Address) []byte {
+	return append(ValidatorMissedBlockKey, pubKeyAddr.Bytes()...)
+}
+
+// get the key for the missed block at the index of the window of the validator
+func GetValidatorMissedBlockKey(pubKeyAddr sdk.Address, index int64) []byte {
+	indexBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(indexBytes, uint64(index))
+	return append(GetValidatorMissedBlocksKey(pubKeyAddr), indexBytes...)
+}
+
+// Keeper tracks the liveness of validators and punishes misbehaving ones
+// through the stake keeper
+type Keeper struct {
+	key         sdk.StoreKey
+	cdc         *wire.Codec
+	stakeKeeper stake.ViewSlashKeeper
+	codespace   sdk.CodespaceType
+}
+
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, sk stake.ViewSlashKeeper, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		key:         key,
+		cdc:         cdc,
+		stakeKeeper: sk,
+		codespace:   codespace,
+	}
+}
+
+// GetParams returns the slashing params
+func (k Keeper) GetParams(ctx sdk.Context) (params Params) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(ParamsKey)
+	if bz == nil {
+		panic("slashing params have not been initialized")
+	}
+	err := k.cdc.UnmarshalJSON(bz, &params)
+	if err != nil {
+		panic(err)
+	}
+	return params
+}
+
+func (k Keeper) setParams(ctx sdk.Context, params Params) {
+	store := ctx.KVStore(k.key)
+	bz, err := k.cdc.MarshalJSON(params)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(ParamsKey, bz)
+}
+
+// SetParam sets a single slashing param from its JSON name and value
+func (k Keeper) SetParam(ctx sdk.Context, key, value string) sdk.Error {
+	bz, err := k.cdc.MarshalJSON(k.GetParams(ctx))
+	if err != nil {
+		panic(err)
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(bz, &fields)
+	if err != nil {
+		panic(err)
+	}
+	if _, ok := fields[key]; !ok {
+		return ErrInvalidParams(k.codespace, fmt.Sprintf("unknown slashing param %s", key))
+	}
+	fields[key] = json.RawMessage(value)
+	bz, err = json.Marshal(fields)
+	if err != nil {
+		return ErrInvalidParams(k.codespace, fmt.Sprintf("invalid value for slashing param %s", key))
+	}
+	var params Params
+	err = k.cdc.UnmarshalJSON(bz, &params)
+	if err != nil {
+		return ErrInvalidParams(k.codespace, fmt.Sprintf("invalid value for slashing param %s: %v", key, err))
+	}
+	if err := params.ValidateBasic(k.codespace); err != nil {
+		return err
+	}
+	k.setParams(ctx, params)
+	return nil
+}
+
+// GetValidatorSigningInfo returns the signing info of the validator with
+// the consensus pubkey address
+func (k Keeper) GetValidatorSigningInfo(ctx sdk.Context, pubKeyAddr sdk.Address) (info ValidatorSigningInfo, found bool) {
+	store := ctx.KVStore(k.key)
+	bz := store.Get(GetValidatorSigningInfoKey(pubKeyAddr))
+	if bz == nil {
+		return info, false
+	}
+	err := k.cdc.UnmarshalJSON(bz, &info)
+	if err != nil {
+		panic(err)
+	}
+	return info, true
+}
+
+func (k Keeper) setValidatorSigningInfo(ctx sdk.Context, pubKeyAddr sdk.Address, info ValidatorSigningInfo) {
+	store := ctx.KVStore(k.key)
+	bz, err := k.cdc.MarshalJSON(info)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(GetValidatorSigningInfoKey(pubKeyAddr), bz)
+}
+
+func (k Keeper) getValidatorMissedBlock(ctx sdk.Context, pubKeyAddr sdk.Address, index int64) bool {
+	store := ctx.KVStore(k.key)
+	return store.Get(GetValidatorMissedBlockKey(pubKeyAddr, index)) != nil
+}
+
+func (k Keeper) setValidatorMissedBlock(ctx sdk.Context, pubKeyAddr sdk.Address, index int64, missed bool) {
+	store := ctx.KVStore(k.key)
+	if missed {
+		store.Set(GetValidatorMissedBlockKey(pubKeyAddr, index), ValidatorMissedBlockByteVal)
+	} else {
+		store.Delete(GetValidatorMissedBlockKey(pubKeyAddr, index))
+	}
+}
+
+func (k Keeper) clearValidatorMissedBlocks(ctx sdk.Context, pubKeyAddr sdk.Address) {
+	store := ctx.KVStore(k.key)
+	iterator := store.SubspaceIterator(GetValidatorMissedBlocksKey(pubKeyAddr))
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// record whether the bonded validator signed the last block, and jail it
+// once it missed more blocks of the window than allowed
+func (k Keeper) handleValidatorSignature(ctx sdk.Context, validator stake.Validator, signed bool) {
+	logger := ctx.Logger().With("module", "x/slashing")
+	params := k.GetParams(ctx)
+	height := ctx.BlockHeight()
+	pubKeyAddr := validator.PubKey.Address()
+
+	info, found := k.GetValidatorSigningInfo(ctx, pubKeyAddr)
+	if !found {
+		info = ValidatorSigningInfo{StartHeight: height}
+	}
+
+	// the window is a ring buffer indexed by the blocks tracked so far
+	index := info.IndexOffset % params.SignedBlocksWindow
+	info.IndexOffset++
+	previousMissed := k.getValidatorMissedBlock(ctx, pubKeyAddr, index)
+	switch {
+	case !previousMissed && !signed:
+		k.setValidatorMissedBlock(ctx, pubKeyAddr, index, true)
+		info.MissedBlocksCounter++
+	case previousMissed && signed:
+		k.setValidatorMissedBlock(ctx, pubKeyAddr, index, false)
+		info.MissedBlocksCounter--
+	}
+	if !signed {
+		logger.Info(fmt.Sprintf("Absent validator %s at height %d, %d missed in the window",
+			pubKeyAddr, height, info.MissedBlocksCounter))
+	}
+
+	// validators are only judged once a whole window was tracked
+	maxMissed := params.SignedBlocksWindow - params.MinSignedPerWindow
+	if height >= info.StartHeight+params.SignedBlocksWindow && info.MissedBlocksCounter > maxMissed {
+		logger.Info(fmt.Sprintf("Validator %s missed %d of the last %d blocks, jailing",
+			pubKeyAddr, info.MissedBlocksCounter, params.SignedBlocksWindow))
+		err := k.stakeKeeper.Jail(ctx, validator.Address)
+		if err != nil {
+			panic(err)
+		}
+		info = k.resetSigningWindow(ctx, pubKeyAddr, info)
+		info.JailedUntil = ctx.BlockHeader().Time + params.DowntimeJailDuration
+	}
+	k.setValidatorSigningInfo(ctx, pubKeyAddr, info)
+}
+
+// slash and jail the validator whose consensus pubkey has the address
+// for signing two blocks at the same height
+func (k Keeper) handleDoubleSign(ctx sdk.Context, pubKeyAddr sdk.Address, infractionHeight int64) {
+	logger := ctx.Logger().With("module", "x/slashing")
+	params := k.GetParams(ctx)
+	age := ctx.BlockHeight() - infractionHeight
+	if age > params.MaxEvidenceAge {
+		logger.Info(fmt.Sprintf("Ignored double sign of %s at height %d, evidence too old",
+			pubKeyAddr, infractionHeight))
+		return
+	}
+	candidate, found := k.stakeKeeper.GetCandidateByPubKeyAddress(ctx, pubKeyAddr)
+	if !found {
+		// the candidate has unbonded all its tokens, nothing left to slash
+		logger.Info(fmt.Sprintf("Ignored double sign of unknown validator %s", pubKeyAddr))
+		return
+	}
+
+	logger.Info(fmt.Sprintf("Validator %s double signed at height %d, slashing %v",
+		pubKeyAddr, infractionHeight, params.SlashFractionDoubleSign))
+	err := k.stakeKeeper.Slash(ctx, candidate.Address, infractionHeight, params.SlashFractionDoubleSign)
+	if err != nil {
+		panic(err)
+	}
+	err = k.stakeKeeper.Jail(ctx, candidate.Address)
+	if err != nil {
+		panic(err)
+	}
+
+	info, _ := k.GetValidatorSigningInfo(ctx, pubKeyAddr)
+	info = k.resetSigningWindow(ctx, pubKeyAddr, info)
+	jailedUntil := ctx.BlockHeader().Time + params.DoubleSignJailDuration
+	if jailedUntil > info.JailedUntil {
+		info.JailedUntil = jailedUntil
+	}
+	k.setValidatorSigningInfo(ctx, pubKeyAddr, info)
+}
+
+// start a fresh signing window at the current height
+func (k Keeper) resetSigningWindow(ctx sdk.Context, pubKeyAddr sdk.Address, info ValidatorSigningInfo) ValidatorSigningInfo {
+	k.clearValidatorMissedBlocks(ctx, pubKeyAddr)
+	info.StartHeight = ctx.BlockHeight()
+	info.IndexOffset = 0
+	info.MissedBlocksCounter = 0
+	return info
+}
+
+// Unjail lets the owner of a jailed candidate back into the validator set
+// once its jail time is over
+func (k Keeper) Unjail(ctx sdk.Context, candidateAddr sdk.Address) sdk.Error {
+	candidate, found := k.stakeKeeper.GetCandidate(ctx, candidateAddr)
+	if !found {
+		return ErrInvalidValidator(k.codespace)
+	}
+	if !candidate.Jailed {
+		return ErrValidatorNotJailed(k.codespace)
+	}
+	pubKeyAddr := candidate.PubKey.Address()
+	info, found := k.GetValidatorSigningInfo(ctx, pubKeyAddr)
+	if !found {
+		return ErrNoSigningInfo(k.codespace)
+	}
+	if ctx.BlockHeader().Time < info.JailedUntil {
+		return ErrValidatorJailed(k.codespace)
+	}
+
+	err := k.stakeKeeper.Unjail(ctx, candidateAddr)
+	if err != nil {
+		return err
+	}
+	// the unjailed validator gets a whole window before it is judged again
+	info = k.resetSigningWindow(ctx, pubKeyAddr, info)
+	k.setValidatorSigningInfo(ctx, pubKeyAddr, info)
+	return nil
+}
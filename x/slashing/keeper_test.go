@@ -0,0 +1,167 @@
+package slashing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+	crypto "github.com/tendermint/go-crypto"
+	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/tendermint/tmlibs/log"
+
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
+	"inschain-tendermint/x/stake"
+)
+
+var (
+	pk   = crypto.GenPrivKeyEd25519().PubKey()
+	addr = sdk.Address(pk.Address())
+)
+
+func testParams() Params {
+	return Params{
+		SignedBlocksWindow:      10,
+		MinSignedPerWindow:      5,
+		DowntimeJailDuration:    60,
+		DoubleSignJailDuration:  600,
+		MaxEvidenceAge:          5,
+		SlashFractionDoubleSign: sdk.NewRat(1, 20),
+	}
+}
+
+// create a slashing keeper with a candidate bonding 100 steak
+func createTestInput(t *testing.T) (sdk.Context, stake.Keeper, Keeper) {
+	db := dbm.NewMemDB()
+	keyAcc := sdk.NewKVStoreKey("acc")
+	keyStake := sdk.NewKVStoreKey("stake")
+	keySlashing := sdk.NewKVStoreKey("slashing")
+	keySupply := sdk.NewKVStoreKey("supply")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keySupply, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyStake, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keySlashing, sdk.StoreTypeIAVL, db)
+	err := ms.LoadLatestVersion()
+	require.Nil(t, err)
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "foochainid", Time: 1000}, false, nil, log.NewNopLogger())
+	cdc := wire.NewCodec()
+	auth.RegisterWire(cdc)
+	RegisterWire(cdc)
+	wire.RegisterCrypto(cdc)
+
+	accountMapper := auth.NewAccountMapper(cdc, keyAcc, &auth.BaseAccount{})
+	supplyKeeper := bank.NewSupplyKeeper(cdc, keySupply, bank.NewKeeper(accountMapper), bank.DefaultCodespace)
+	sk := stake.NewKeeper(cdc, keyStake, supplyKeeper, stake.DefaultCodespace)
+	stakeGenesis := stake.GetDefaultGenesisState()
+	stake.InitGenesis(ctx, sk, stakeGenesis)
+	keeper := NewKeeper(cdc, keySlashing, stake.NewViewSlashKeeper(sk), DefaultCodespace)
+	InitGenesis(ctx, keeper, GenesisState{testParams()})
+
+	_, sdkErr := supplyKeeper.Mint(ctx, addr, sdk.Coins{{"steak", 100}})
+	require.Nil(t, sdkErr)
+	commission := stake.NewCommission(sdk.ZeroRat(), sdk.ZeroRat(), sdk.ZeroRat())
+	msg := stake.NewMsgDeclareCandidacy(addr, pk, sdk.Coin{"steak", 100}, stake.NewDescription("val", "", "", ""), commission)
+	res := stake.NewHandler(sk)(ctx, msg)
+	require.True(t, res.IsOK(), "%v", res)
+	return ctx, sk, keeper
+}
+
+func beginBlock(ctx sdk.Context, keeper Keeper, height int64, req abci.RequestBeginBlock) sdk.Context {
+	ctx = ctx.WithBlockHeight(height)
+	NewBeginBlocker(keeper)(ctx, req)
+	return ctx
+}
+
+func TestHandleAbsentValidator(t *testing.T) {
+	ctx, sk, keeper := createTestInput(t)
+
+	// sign the first window
+	for height := int64(1); height <= 10; height++ {
+		ctx = beginBlock(ctx, keeper, height, abci.RequestBeginBlock{})
+	}
+	info, found := keeper.GetValidatorSigningInfo(ctx, addr)
+	require.True(t, found)
+	assert.Equal(t, int64(1), info.StartHeight)
+	assert.Equal(t, int64(10), info.IndexOffset)
+	assert.Equal(t, int64(0), info.MissedBlocksCounter)
+
+	// missing half of the window is allowed
+	absent := abci.RequestBeginBlock{AbsentValidators: []int32{0}}
+	for height := int64(11); height <= 15; height++ {
+		ctx = beginBlock(ctx, keeper, height, absent)
+	}
+	info, _ = keeper.GetValidatorSigningInfo(ctx, addr)
+	assert.Equal(t, int64(5), info.MissedBlocksCounter)
+	candidate, _ := sk.GetCandidate(ctx, addr)
+	assert.False(t, candidate.Jailed)
+
+	// missing one more block jails the validator
+	ctx = beginBlock(ctx, keeper, 16, absent)
+	candidate, _ = sk.GetCandidate(ctx, addr)
+	assert.True(t, candidate.Jailed)
+	assert.Equal(t, 0, len(stake.NewViewSlashKeeper(sk).GetBondedValidators(ctx)))
+	info, _ = keeper.GetValidatorSigningInfo(ctx, addr)
+	assert.Equal(t, int64(0), info.MissedBlocksCounter)
+	assert.Equal(t, int64(1060), info.JailedUntil)
+
+	// the validator can't be unjailed before its jail time is over
+	res := NewHandler(keeper)(ctx, NewMsgUnjail(addr))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeValidatorJailed), res.Code)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1060})
+	res = NewHandler(keeper)(ctx, NewMsgUnjail(addr))
+	require.True(t, res.IsOK(), "%v", res)
+	candidate, _ = sk.GetCandidate(ctx, addr)
+	assert.False(t, candidate.Jailed)
+	assert.Equal(t, 1, len(stake.NewViewSlashKeeper(sk).GetBondedValidators(ctx)))
+
+	res = NewHandler(keeper)(ctx, NewMsgUnjail(addr))
+	assert.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeValidatorNotJailed), res.Code)
+
+	// no tokens were slashed for the downtime
+	assert.True(t, candidate.Assets.Equal(sdk.NewRat(100)))
+}
+
+func TestHandleDoubleSign(t *testing.T) {
+	ctx, sk, keeper := createTestInput(t)
+	ctx = beginBlock(ctx, keeper, 1, abci.RequestBeginBlock{})
+
+	// evidence older than the max age is ignored
+	oldEvidence := abci.RequestBeginBlock{
+		ByzantineValidators: []abci.Evidence{{PubKey: addr, Height: 1}},
+	}
+	ctx = beginBlock(ctx, keeper, 10, oldEvidence)
+	candidate, _ := sk.GetCandidate(ctx, addr)
+	assert.False(t, candidate.Jailed)
+
+	// the double signer is slashed and jailed
+	evidence := abci.RequestBeginBlock{
+		ByzantineValidators: []abci.Evidence{{PubKey: addr, Height: 9}},
+	}
+	ctx = beginBlock(ctx, keeper, 11, evidence)
+	candidate, _ = sk.GetCandidate(ctx, addr)
+	assert.True(t, candidate.Jailed)
+	assert.True(t, candidate.Assets.Equal(sdk.NewRat(95)))
+	assert.True(t, candidate.Liabilities.Equal(sdk.NewRat(100)))
+	assert.Equal(t, int64(95), sk.GetTotalSupply(ctx))
+	info, _ := keeper.GetValidatorSigningInfo(ctx, addr)
+	assert.Equal(t, int64(1600), info.JailedUntil)
+}
+
+func TestParamsValidateBasic(t *testing.T) {
+	assert.Nil(t, DefaultParams().ValidateBasic(DefaultCodespace))
+
+	params := testParams()
+	params.MinSignedPerWindow = 11
+	assert.NotNil(t, params.ValidateBasic(DefaultCodespace))
+
+	params = testParams()
+	params.SlashFractionDoubleSign = sdk.NewRat(3, 2)
+	assert.NotNil(t, params.ValidateBasic(DefaultCodespace))
+}
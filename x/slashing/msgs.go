@@ -0,0 +1,50 @@
+package slashing
+
+import (
+	"encoding/json"
+
+	sdk "inschain-tendermint/types"
+)
+
+// name to identify the slashing msgs
+const MsgType = "slashing"
+
+// MsgUnjail - the owner of a jailed candidate asks for it to be let back
+// into the validator set
+type MsgUnjail struct {
+	CandidateAddr sdk.Address `json:"address"`
+}
+
+var _ sdk.Msg = MsgUnjail{}
+
+// NewMsgUnjail - construct the msg unjailing the candidate
+func NewMsgUnjail(candidateAddr sdk.Address) MsgUnjail {
+	return MsgUnjail{
+		CandidateAddr: candidateAddr,
+	}
+}
+
+// Implements Msg.
+func (msg MsgUnjail) Type() string { return MsgType }
+
+// Implements Msg.
+func (msg MsgUnjail) ValidateBasic() sdk.Error {
+	if len(msg.CandidateAddr) == 0 {
+		return ErrInvalidValidator(DefaultCodespace)
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgUnjail) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Implements Msg.
+func (msg MsgUnjail) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.CandidateAddr}
+}
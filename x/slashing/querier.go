@@ -0,0 +1,62 @@
+package slashing
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+// query endpoints supported by the slashing querier, at /custom/slashing/<route>
+const (
+	QuerySigningInfo = "signing-info"
+	QueryParams      = "params"
+)
+
+// parameters of the signing info query
+type QuerySigningInfoParams struct {
+	CandidateAddr sdk.Address `json:"address"`
+}
+
+// NewQuerier returns the querier of the slashing module
+func NewQuerier(k Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("No slashing query endpoint specified")
+		}
+		switch path[0] {
+		case QuerySigningInfo:
+			return querySigningInfo(ctx, req, k)
+		case QueryParams:
+			return k.marshalQueryResult(k.GetParams(ctx))
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Unknown slashing query endpoint %s", path[0]))
+		}
+	}
+}
+
+func querySigningInfo(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QuerySigningInfoParams
+	err := k.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Incorrectly formatted query data: %s", err.Error()))
+	}
+	candidate, found := k.stakeKeeper.GetCandidate(ctx, params.CandidateAddr)
+	if !found {
+		return nil, ErrInvalidValidator(k.codespace)
+	}
+	info, found := k.GetValidatorSigningInfo(ctx, candidate.PubKey.Address())
+	if !found {
+		return nil, ErrNoSigningInfo(k.codespace)
+	}
+	return k.marshalQueryResult(info)
+}
+
+func (k Keeper) marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	bz, err := k.cdc.MarshalJSON(res)
+	if err != nil {
+		panic(err)
+	}
+	return bz, nil
+}
@@ -0,0 +1,13 @@
+package slashing
+
+// Tags attached to the results of slashing messages, indexed by Tendermint.
+// Addresses are tagged in upper case hex.
+const (
+	TagAction    = "action"
+	TagCandidate = "candidate"
+)
+
+// Values of the action tag
+var (
+	ActionUnjail = []byte("unjail")
+)
@@ -0,0 +1,37 @@
+package slashing
+
+import (
+	"bytes"
+	"sort"
+
+	abci "github.com/tendermint/abci/types"
+	sdk "inschain-tendermint/types"
+)
+
+// NewBeginBlocker generates sdk.BeginBlocker
+// Handles the double sign evidence and tracks the absent validators
+func NewBeginBlocker(k Keeper) sdk.BeginBlocker {
+	return func(ctx sdk.Context, req abci.RequestBeginBlock) (res abci.ResponseBeginBlock) {
+
+		// tendermint reports the address of the consensus pubkey of the
+		// double signer in the PubKey field of the evidence
+		for _, evidence := range req.ByzantineValidators {
+			k.handleDoubleSign(ctx, sdk.Address(evidence.PubKey), evidence.Height)
+		}
+
+		// the absent validators are indexes into the validator set of the
+		// last commit, which tendermint sorts by consensus pubkey address
+		absent := make(map[int32]bool, len(req.AbsentValidators))
+		for _, index := range req.AbsentValidators {
+			absent[index] = true
+		}
+		validators := k.stakeKeeper.GetBondedValidators(ctx)
+		sort.Slice(validators, func(i, j int) bool {
+			return bytes.Compare(validators[i].PubKey.Address(), validators[j].PubKey.Address()) < 0
+		})
+		for i, validator := range validators {
+			k.handleValidatorSignature(ctx, validator, !absent[int32(i)])
+		}
+		return
+	}
+}
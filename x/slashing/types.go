@@ -0,0 +1,70 @@
+package slashing
+
+import (
+	"fmt"
+
+	sdk "inschain-tendermint/types"
+)
+
+// GenesisState - all slashing state that must be provided at genesis
+type GenesisState struct {
+	Params Params `json:"params"`
+}
+
+// Params defines how validators are punished for downtime and double signing
+type Params struct {
+	SignedBlocksWindow      int64   `json:"signed_blocks_window"`       // number of blocks the liveness of a validator is tracked over
+	MinSignedPerWindow      int64   `json:"min_signed_per_window"`      // blocks a validator must sign in the window to stay bonded
+	DowntimeJailDuration    int64   `json:"downtime_jail_duration"`     // seconds a validator stays jailed for downtime
+	DoubleSignJailDuration  int64   `json:"double_sign_jail_duration"`  // seconds a validator stays jailed for double signing
+	MaxEvidenceAge          int64   `json:"max_evidence_age"`           // blocks after which double sign evidence is ignored
+	SlashFractionDoubleSign sdk.Rat `json:"slash_fraction_double_sign"` // fraction of the bonded tokens slashed for double signing
+}
+
+// DefaultParams - the params used when the genesis does not set them
+func DefaultParams() Params {
+	return Params{
+		SignedBlocksWindow:      100,
+		MinSignedPerWindow:      50,
+		DowntimeJailDuration:    60 * 10,
+		DoubleSignJailDuration:  60 * 60 * 24 * 21,
+		MaxEvidenceAge:          100000,
+		SlashFractionDoubleSign: sdk.NewRat(1, 20),
+	}
+}
+
+// DefaultGenesisState - the slashing genesis with the default params
+func DefaultGenesisState() GenesisState {
+	return GenesisState{Params: DefaultParams()}
+}
+
+// ValidateBasic checks the params are consistent
+func (p Params) ValidateBasic(codespace sdk.CodespaceType) sdk.Error {
+	if p.SignedBlocksWindow <= 0 {
+		return ErrInvalidParams(codespace, "signed blocks window must be positive")
+	}
+	if p.MinSignedPerWindow < 0 || p.MinSignedPerWindow > p.SignedBlocksWindow {
+		return ErrInvalidParams(codespace, "min signed per window must be between 0 and the window")
+	}
+	if p.DowntimeJailDuration < 0 || p.DoubleSignJailDuration < 0 || p.MaxEvidenceAge < 0 {
+		return ErrInvalidParams(codespace, "durations must not be negative")
+	}
+	if p.SlashFractionDoubleSign.LT(sdk.ZeroRat()) || p.SlashFractionDoubleSign.GT(sdk.OneRat()) {
+		return ErrInvalidParams(codespace, fmt.Sprintf("slash fraction %v must be between 0 and 1", p.SlashFractionDoubleSign))
+	}
+	return nil
+}
+
+// ValidatorSigningInfo tracks the liveness of a validator, by the address
+// of its consensus pubkey
+type ValidatorSigningInfo struct {
+	StartHeight         int64 `json:"start_height"`          // height the tracking window started at
+	IndexOffset         int64 `json:"index_offset"`          // blocks tracked since the start height
+	MissedBlocksCounter int64 `json:"missed_blocks_counter"` // blocks missed in the current window
+	JailedUntil         int64 `json:"jailed_until"`          // unix time the validator may be unjailed at
+}
+
+func (info ValidatorSigningInfo) String() string {
+	return fmt.Sprintf("Start height: %d, index offset: %d, missed blocks: %d, jailed until: %d",
+		info.StartHeight, info.IndexOffset, info.MissedBlocksCounter, info.JailedUntil)
+}
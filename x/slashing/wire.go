@@ -0,0 +1,10 @@
+package slashing
+
+import (
+	"inschain-tendermint/wire"
+)
+
+// Register concrete types on wire codec
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgUnjail{}, "slashing/Unjail", nil)
+}
@@ -6,26 +6,34 @@ import (
 
 // nolint
 const (
-	FlagAddressDelegator = "address-delegator"
-	FlagAddressCandidate = "address-candidate"
-	FlagPubKey           = "pubkey"
-	FlagAmount           = "amount"
-	FlagShares           = "shares"
+	FlagAddressDelegator    = "address-delegator"
+	FlagAddressCandidate    = "address-candidate"
+	FlagAddressCandidateSrc = "address-candidate-source"
+	FlagAddressCandidateDst = "address-candidate-dest"
+	FlagPubKey              = "pubkey"
+	FlagAmount              = "amount"
+	FlagShares              = "shares"
 
 	FlagMoniker  = "moniker"
 	FlagIdentity = "keybase-sig"
 	FlagWebsite  = "website"
 	FlagDetails  = "details"
+
+	FlagCommissionRate          = "commission-rate"
+	FlagCommissionMaxRate       = "commission-max-rate"
+	FlagCommissionMaxChangeRate = "commission-max-change-rate"
 )
 
 // common flagsets to add to various functions
 var (
-	fsPk          = flag.NewFlagSet("", flag.ContinueOnError)
-	fsAmount      = flag.NewFlagSet("", flag.ContinueOnError)
-	fsShares      = flag.NewFlagSet("", flag.ContinueOnError)
-	fsDescription = flag.NewFlagSet("", flag.ContinueOnError)
-	fsCandidate   = flag.NewFlagSet("", flag.ContinueOnError)
-	fsDelegator   = flag.NewFlagSet("", flag.ContinueOnError)
+	fsPk           = flag.NewFlagSet("", flag.ContinueOnError)
+	fsAmount       = flag.NewFlagSet("", flag.ContinueOnError)
+	fsShares       = flag.NewFlagSet("", flag.ContinueOnError)
+	fsDescription  = flag.NewFlagSet("", flag.ContinueOnError)
+	fsCommission   = flag.NewFlagSet("", flag.ContinueOnError)
+	fsCandidate    = flag.NewFlagSet("", flag.ContinueOnError)
+	fsDelegator    = flag.NewFlagSet("", flag.ContinueOnError)
+	fsRedelegation = flag.NewFlagSet("", flag.ContinueOnError)
 )
 
 func init() {
@@ -36,6 +44,11 @@ func init() {
 	fsDescription.String(FlagIdentity, "", "optional keybase signature")
 	fsDescription.String(FlagWebsite, "", "optional website")
 	fsDescription.String(FlagDetails, "", "optional details")
+	fsCommission.String(FlagCommissionRate, "0", "share of the rewards kept by the validator-candidate, in decimal (ex. 0.1)")
+	fsCommission.String(FlagCommissionMaxRate, "0", "maximum commission rate the validator-candidate can ever charge, in decimal")
+	fsCommission.String(FlagCommissionMaxChangeRate, "0", "maximum change of the commission rate per day, in decimal")
 	fsCandidate.String(FlagAddressCandidate, "", "hex address of the validator/candidate")
 	fsDelegator.String(FlagAddressDelegator, "", "hex address of the delegator")
+	fsRedelegation.String(FlagAddressCandidateSrc, "", "hex address of the validator/candidate to redelegate from")
+	fsRedelegation.String(FlagAddressCandidateDst, "", "hex address of the validator/candidate to redelegate to")
 }
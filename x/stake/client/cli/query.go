@@ -8,48 +8,66 @@ import (
 	"github.com/spf13/viper"
 
 	crypto "github.com/tendermint/go-crypto"
+	cmn "github.com/tendermint/tmlibs/common"
 
-	"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire" // XXX fix
-	"github.com/cosmos/cosmos-sdk/x/stake"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire" // XXX fix
+	"inschain-tendermint/x/stake"
 )
 
-//// create command to query for all candidates
-//func GetCmdQueryCandidates(storeName string, cdc *wire.Codec) *cobra.Command {
-//cmd := &cobra.Command{
-//Use:   "candidates",
-//Short: "Query for the set of validator-candidates pubkeys",
-//RunE: func(cmd *cobra.Command, args []string) error {
+// a page of candidates, Next is the page start of the next page
+type candidatesPage struct {
+	Candidates []stake.Candidate `json:"candidates,omitempty"`
+	Addresses  []sdk.Address     `json:"addresses,omitempty"`
+	Next       cmn.HexBytes      `json:"next,omitempty"`
+	Count      int64             `json:"count"`
+}
 
-//key := stake.CandidatesKey
+// create command to query a page of the candidates
+func GetCmdQueryCandidates(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "candidates",
+		Short: "Query for a page of the validator-candidates",
+		RunE: func(cmd *cobra.Command, args []string) error {
 
-//ctx := context.NewCoreContextFromViper()
-//res, err := ctx.Query(key, storeName)
-//if err != nil {
-//return err
-//}
+			opts, err := context.SubspaceOptionsFromViper()
+			if err != nil {
+				return err
+			}
+			ctx := context.NewCoreContextFromViper().WithSubspaceOptions(opts)
+			res, err := ctx.QuerySubspace(cdc, stake.CandidatesKey, storeName)
+			if err != nil {
+				return err
+			}
 
-//// parse out the candidates
-//candidates := new(stake.Candidates)
-//err = cdc.UnmarshalBinary(res, candidates)
-//if err != nil {
-//return err
-//}
-//output, err := wire.MarshalJSONIndent(cdc, candidates)
-//if err != nil {
-//return err
-//}
-//fmt.Println(string(output))
-//return nil
+			// parse out the candidates, or only their addresses
+			page := candidatesPage{Next: res.Next, Count: res.Count}
+			for _, kv := range res.KVs {
+				if opts.KeysOnly {
+					page.Addresses = append(page.Addresses, sdk.Address(kv.Key[len(stake.CandidatesKey):]))
+					continue
+				}
+				var candidate stake.Candidate
+				err = cdc.UnmarshalJSON(kv.Value, &candidate)
+				if err != nil {
+					return err
+				}
+				page.Candidates = append(page.Candidates, candidate)
+			}
+			output, err := wire.MarshalJSONIndent(cdc, page)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
 
-//// TODO output with proofs / machine parseable etc.
-//},
-//}
+			// TODO output with proofs / machine parseable etc.
+		},
+	}
 
-//cmd.Flags().AddFlagSet(fsDelegator)
-//return cmd
-//}
+	return cmd
+}
 
 // get the command to query a candidate
 func GetCmdQueryCandidate(storeName string, cdc *wire.Codec) *cobra.Command {
@@ -142,6 +160,65 @@ func GetCmdQueryDelegatorBond(storeName string, cdc *wire.Codec) *cobra.Command
 	return cmd
 }
 
+// get the command to query the fee rewards of a delegator bond not yet withdrawn
+func GetCmdQueryDelegatorRewards(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delegator-rewards",
+		Short: "Query the fee rewards of a delegators bond not yet withdrawn",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			addr, err := sdk.GetAddress(viper.GetString(FlagAddressCandidate))
+			if err != nil {
+				return err
+			}
+
+			delegator, err := sdk.GetAddress(viper.GetString(FlagAddressDelegator))
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+
+			res, err := ctx.Query(stake.GetDelegatorBondKey(delegator, addr, cdc), storeName)
+			if err != nil {
+				return err
+			}
+			if len(res) == 0 {
+				return fmt.Errorf("no bond of %v with candidate %v", delegator, addr)
+			}
+			bond := new(stake.DelegatorBond)
+			err = cdc.UnmarshalJSON(res, bond)
+			if err != nil {
+				return err
+			}
+
+			// the candidate has no rewards until fees are distributed to it
+			rewards := stake.ValidatorRewards{CandidateAddr: addr}
+			res, err = ctx.Query(stake.GetValidatorRewardsKey(addr), storeName)
+			if err != nil {
+				return err
+			}
+			if len(res) > 0 {
+				err = cdc.UnmarshalJSON(res, &rewards)
+				if err != nil {
+					return err
+				}
+			}
+
+			output, err := wire.MarshalJSONIndent(cdc, stake.PendingRewards(*bond, rewards))
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+
+	cmd.Flags().AddFlagSet(fsCandidate)
+	cmd.Flags().AddFlagSet(fsDelegator)
+	return cmd
+}
+
 //// get the command to query all the candidates bonded to a delegator
 //func GetCmdQueryDelegatorBonds(storeName string, cdc *wire.Codec) *cobra.Command {
 //cmd := &cobra.Command{
@@ -183,3 +260,83 @@ func GetCmdQueryDelegatorBond(storeName string, cdc *wire.Codec) *cobra.Command
 //cmd.Flags().AddFlagSet(fsDelegator)
 //return cmd
 //}
+
+// get the command to query the unbonding delegations of a delegator
+func GetCmdQueryUnbondingDelegations(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unbonding-delegations",
+		Short: "Query the delegators tokens still unbonding from their candidates",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			delegator, err := sdk.GetAddress(viper.GetString(FlagAddressDelegator))
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QuerySubspace(cdc, stake.GetUnbondingDelegationsKey(delegator), storeName)
+			if err != nil {
+				return err
+			}
+
+			ubds := []stake.UnbondingDelegation{}
+			for _, kv := range res.KVs {
+				var ubd stake.UnbondingDelegation
+				err = cdc.UnmarshalJSON(kv.Value, &ubd)
+				if err != nil {
+					return err
+				}
+				ubds = append(ubds, ubd)
+			}
+			output, err := wire.MarshalJSONIndent(cdc, ubds)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+
+	cmd.Flags().AddFlagSet(fsDelegator)
+	return cmd
+}
+
+// get the command to query the redelegations of a delegator
+func GetCmdQueryRedelegations(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "redelegations",
+		Short: "Query the delegators redelegations which are still slashable",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			delegator, err := sdk.GetAddress(viper.GetString(FlagAddressDelegator))
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QuerySubspace(cdc, stake.GetRedelegationsKey(delegator), storeName)
+			if err != nil {
+				return err
+			}
+
+			reds := []stake.Redelegation{}
+			for _, kv := range res.KVs {
+				var red stake.Redelegation
+				err = cdc.UnmarshalJSON(kv.Value, &red)
+				if err != nil {
+					return err
+				}
+				reds = append(reds, red)
+			}
+			output, err := wire.MarshalJSONIndent(cdc, reds)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+
+	cmd.Flags().AddFlagSet(fsDelegator)
+	return cmd
+}
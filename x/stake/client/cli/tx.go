@@ -9,11 +9,11 @@ import (
 
 	crypto "github.com/tendermint/go-crypto"
 
-	"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
-	"github.com/cosmos/cosmos-sdk/x/stake"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	authcmd "inschain-tendermint/x/auth/client/cli"
+	"inschain-tendermint/x/stake"
 )
 
 // create declare candidacy command
@@ -53,12 +53,19 @@ func GetCmdDeclareCandidacy(cdc *wire.Codec) *cobra.Command {
 				Website:  viper.GetString(FlagWebsite),
 				Details:  viper.GetString(FlagDetails),
 			}
-			msg := stake.NewMsgDeclareCandidacy(candidateAddr, pk, amount, description)
+			commission, err := buildCommission()
+			if err != nil {
+				return err
+			}
+			msg := stake.NewMsgDeclareCandidacy(candidateAddr, pk, amount, description, commission)
 
 			// build and sign the transaction, then broadcast to Tendermint
 			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
 
-			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if ctx.GenerateOnly {
+				return ctx.PrintUnsignedTx([]sdk.Msg{msg}, cdc)
+			}
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
 			if err != nil {
 				return err
 			}
@@ -71,10 +78,24 @@ func GetCmdDeclareCandidacy(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().AddFlagSet(fsPk)
 	cmd.Flags().AddFlagSet(fsAmount)
 	cmd.Flags().AddFlagSet(fsDescription)
+	cmd.Flags().AddFlagSet(fsCommission)
 	cmd.Flags().AddFlagSet(fsCandidate)
 	return cmd
 }
 
+// parse the commission of a new candidacy from the commission flags
+func buildCommission() (commission stake.Commission, err error) {
+	rates := make([]sdk.Rat, 3)
+	for i, flag := range []string{FlagCommissionRate, FlagCommissionMaxRate, FlagCommissionMaxChangeRate} {
+		rate, err := sdk.NewRatFromDecimal(viper.GetString(flag))
+		if err != nil {
+			return commission, fmt.Errorf("invalid --%s: %s", flag, err.Error())
+		}
+		rates[i] = rate
+	}
+	return stake.NewCommission(rates[0], rates[1], rates[2]), nil
+}
+
 // create edit candidacy command
 func GetCmdEditCandidacy(cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
@@ -92,12 +113,24 @@ func GetCmdEditCandidacy(cdc *wire.Codec) *cobra.Command {
 				Website:  viper.GetString(FlagWebsite),
 				Details:  viper.GetString(FlagDetails),
 			}
-			msg := stake.NewMsgEditCandidacy(candidateAddr, description)
+			// the commission rate is only changed when given
+			var commissionRate *sdk.Rat
+			if rateStr := viper.GetString(FlagCommissionRate); rateStr != "" {
+				rate, err := sdk.NewRatFromDecimal(rateStr)
+				if err != nil {
+					return fmt.Errorf("invalid --%s: %s", FlagCommissionRate, err.Error())
+				}
+				commissionRate = &rate
+			}
+			msg := stake.NewMsgEditCandidacy(candidateAddr, description, commissionRate)
 
 			// build and sign the transaction, then broadcast to Tendermint
 			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
 
-			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if ctx.GenerateOnly {
+				return ctx.PrintUnsignedTx([]sdk.Msg{msg}, cdc)
+			}
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
 			if err != nil {
 				return err
 			}
@@ -109,6 +142,7 @@ func GetCmdEditCandidacy(cdc *wire.Codec) *cobra.Command {
 
 	cmd.Flags().AddFlagSet(fsDescription)
 	cmd.Flags().AddFlagSet(fsCandidate)
+	cmd.Flags().String(FlagCommissionRate, "", "new commission rate of the validator-candidate, in decimal (ex. 0.1)")
 	return cmd
 }
 
@@ -134,7 +168,10 @@ func GetCmdDelegate(cdc *wire.Codec) *cobra.Command {
 			// build and sign the transaction, then broadcast to Tendermint
 			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
 
-			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if ctx.GenerateOnly {
+				return ctx.PrintUnsignedTx([]sdk.Msg{msg}, cdc)
+			}
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
 			if err != nil {
 				return err
 			}
@@ -182,7 +219,66 @@ func GetCmdUnbond(cdc *wire.Codec) *cobra.Command {
 			// build and sign the transaction, then broadcast to Tendermint
 			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
 
-			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, msg, cdc)
+			if ctx.GenerateOnly {
+				return ctx.PrintUnsignedTx([]sdk.Msg{msg}, cdc)
+			}
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+
+	cmd.Flags().AddFlagSet(fsShares)
+	cmd.Flags().AddFlagSet(fsDelegator)
+	cmd.Flags().AddFlagSet(fsCandidate)
+	return cmd
+}
+
+// create redelegate command
+func GetCmdRedelegate(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "redelegate",
+		Short: "move bonded coins from a validator/candidate to another one without unbonding them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			// check the shares before broadcasting
+			sharesStr := viper.GetString(FlagShares)
+			if sharesStr != "MAX" {
+				shares, err := sdk.NewRatFromDecimal(sharesStr)
+				if err != nil {
+					return err
+				}
+				if !shares.GT(sdk.ZeroRat()) {
+					return fmt.Errorf("shares must be positive integer or decimal (ex. 123, 1.23456789)")
+				}
+			}
+
+			delegatorAddr, err := sdk.GetAddress(viper.GetString(FlagAddressDelegator))
+			if err != nil {
+				return err
+			}
+			srcAddr, err := sdk.GetAddress(viper.GetString(FlagAddressCandidateSrc))
+			if err != nil {
+				return err
+			}
+			dstAddr, err := sdk.GetAddress(viper.GetString(FlagAddressCandidateDst))
+			if err != nil {
+				return err
+			}
+
+			msg := stake.NewMsgRedelegate(delegatorAddr, srcAddr, dstAddr, sharesStr)
+
+			// build and sign the transaction, then broadcast to Tendermint
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			if ctx.GenerateOnly {
+				return ctx.PrintUnsignedTx([]sdk.Msg{msg}, cdc)
+			}
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
 			if err != nil {
 				return err
 			}
@@ -193,6 +289,45 @@ func GetCmdUnbond(cdc *wire.Codec) *cobra.Command {
 	}
 
 	cmd.Flags().AddFlagSet(fsShares)
+	cmd.Flags().AddFlagSet(fsDelegator)
+	cmd.Flags().AddFlagSet(fsRedelegation)
+	return cmd
+}
+
+// create withdraw command
+func GetCmdWithdraw(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "withdraw",
+		Short: "withdraw the fee rewards of a bond, and the commission of an owned validator/candidate",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			delegatorAddr, err := sdk.GetAddress(viper.GetString(FlagAddressDelegator))
+			if err != nil {
+				return err
+			}
+			candidateAddr, err := sdk.GetAddress(viper.GetString(FlagAddressCandidate))
+			if err != nil {
+				return err
+			}
+
+			msg := stake.NewMsgWithdraw(delegatorAddr, candidateAddr)
+
+			// build and sign the transaction, then broadcast to Tendermint
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			if ctx.GenerateOnly {
+				return ctx.PrintUnsignedTx([]sdk.Msg{msg}, cdc)
+			}
+			res, err := ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Committed at block %d. Hash: %s\n", res.Height, res.Hash.String())
+			return nil
+		},
+	}
+
 	cmd.Flags().AddFlagSet(fsDelegator)
 	cmd.Flags().AddFlagSet(fsCandidate)
 	return cmd
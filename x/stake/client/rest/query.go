@@ -4,19 +4,31 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/tendermint/go-crypto/keys"
 
-	"github.com/cosmos/cosmos-sdk/client/context"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/stake"
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/stake"
 )
 
 // RegisterRoutes - Central function to define routes that get registered by the main application
 func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, kb keys.Keybase) {
+	r.HandleFunc("/stake/declare_candidacy", DeclareCandidacyHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/stake/edit_candidacy", EditCandidacyHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/stake/delegate", DelegateHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/stake/unbond", UnbondHandlerFn(cdc, kb, ctx)).Methods("POST")
+	r.HandleFunc("/stake/candidates", CandidatesHandlerFn("stake", cdc, ctx)).Methods("GET")
+	r.HandleFunc("/stake/candidates/{candidate}", CandidateHandlerFn("stake", cdc, ctx)).Methods("GET")
+	r.HandleFunc("/stake/pool", QueryHandlerFn("stake", stake.QueryPool, cdc, ctx)).Methods("GET")
+	r.HandleFunc("/stake/params", QueryHandlerFn("stake", stake.QueryParams, cdc, ctx)).Methods("GET")
+	r.HandleFunc("/stake/{delegator}/bonds", DelegatorBondsHandlerFn("stake", cdc, ctx)).Methods("GET")
 	r.HandleFunc("/stake/{delegator}/bonding_status/{candidate}", BondingStatusHandlerFn("stake", cdc, kb, ctx)).Methods("GET")
+	r.HandleFunc("/stake/{delegator}/unbonding_delegations", UnbondingDelegationsHandlerFn("stake", cdc, kb, ctx)).Methods("GET")
+	r.HandleFunc("/stake/{delegator}/redelegations", RedelegationsHandlerFn("stake", cdc, kb, ctx)).Methods("GET")
 }
 
 // BondingStatusHandlerFn - http request handler to query delegator bonding status
@@ -25,7 +37,7 @@ func BondingStatusHandlerFn(storeName string, cdc *wire.Codec, kb keys.Keybase,
 		// read parameters
 		vars := mux.Vars(r)
 		delegator := vars["delegator"]
-		validator := vars["validator"]
+		validator := vars["candidate"]
 
 		bz, err := hex.DecodeString(delegator)
 		if err != nil {
@@ -76,3 +88,173 @@ func BondingStatusHandlerFn(storeName string, cdc *wire.Codec, kb keys.Keybase,
 		w.Write(output)
 	}
 }
+
+// CandidatesHandlerFn - http request handler to query a page of the candidates,
+// from the hex candidate of the start query parameter and at most limit of them
+func CandidatesHandlerFn(queryRoute string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		params := stake.QueryCandidatesParams{}
+		if start := r.URL.Query().Get("start"); start != "" {
+			params.Start, err = hex.DecodeString(start)
+			if err != nil {
+				writeErr(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		if limit := r.URL.Query().Get("limit"); limit != "" {
+			params.Limit, err = strconv.Atoi(limit)
+			if err != nil {
+				writeErr(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		query(w, cdc, ctx, queryRoute, stake.QueryCandidates, params)
+	}
+}
+
+// UnbondingDelegationsHandlerFn - http request handler to query the tokens
+// of a delegator still unbonding from their candidates
+func UnbondingDelegationsHandlerFn(storeName string, cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bz, err := hex.DecodeString(mux.Vars(r)["delegator"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		delegatorAddr := sdk.Address(bz)
+
+		res, err := ctx.QuerySubspace(cdc, stake.GetUnbondingDelegationsKey(delegatorAddr), storeName)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("Couldn't query unbonding delegations. Error: %s", err.Error())))
+			return
+		}
+
+		ubds := []stake.UnbondingDelegation{}
+		for _, kv := range res.KVs {
+			var ubd stake.UnbondingDelegation
+			err = cdc.UnmarshalJSON(kv.Value, &ubd)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(fmt.Sprintf("Couldn't decode unbonding delegation. Error: %s", err.Error())))
+				return
+			}
+			ubds = append(ubds, ubd)
+		}
+
+		output, err := cdc.MarshalJSON(ubds)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.Write(output)
+	}
+}
+
+// RedelegationsHandlerFn - http request handler to query the redelegations
+// of a delegator which are still slashable
+func RedelegationsHandlerFn(storeName string, cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bz, err := hex.DecodeString(mux.Vars(r)["delegator"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		delegatorAddr := sdk.Address(bz)
+
+		res, err := ctx.QuerySubspace(cdc, stake.GetRedelegationsKey(delegatorAddr), storeName)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("Couldn't query redelegations. Error: %s", err.Error())))
+			return
+		}
+
+		reds := []stake.Redelegation{}
+		for _, kv := range res.KVs {
+			var red stake.Redelegation
+			err = cdc.UnmarshalJSON(kv.Value, &red)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(fmt.Sprintf("Couldn't decode redelegation. Error: %s", err.Error())))
+				return
+			}
+			reds = append(reds, red)
+		}
+
+		output, err := cdc.MarshalJSON(reds)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.Write(output)
+	}
+}
+
+// CandidateHandlerFn - http request handler to query a candidate
+func CandidateHandlerFn(queryRoute string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bz, err := hex.DecodeString(mux.Vars(r)["candidate"])
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err)
+			return
+		}
+		query(w, cdc, ctx, queryRoute, stake.QueryCandidate, stake.QueryCandidateParams{CandidateAddr: sdk.Address(bz)})
+	}
+}
+
+// DelegatorBondsHandlerFn - http request handler to query a page of the bonds
+// of a delegator, from the hex candidate of the start query parameter and at
+// most limit of them
+func DelegatorBondsHandlerFn(queryRoute string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bz, err := hex.DecodeString(mux.Vars(r)["delegator"])
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err)
+			return
+		}
+		params := stake.QueryDelegatorBondsParams{DelegatorAddr: sdk.Address(bz)}
+		if start := r.URL.Query().Get("start"); start != "" {
+			params.Start, err = hex.DecodeString(start)
+			if err != nil {
+				writeErr(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		if limit := r.URL.Query().Get("limit"); limit != "" {
+			params.Limit, err = strconv.Atoi(limit)
+			if err != nil {
+				writeErr(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		query(w, cdc, ctx, queryRoute, stake.QueryDelegatorBonds, params)
+	}
+}
+
+// QueryHandlerFn - http request handler to query a route without params
+func QueryHandlerFn(queryRoute, route string, cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query(w, cdc, ctx, queryRoute, route, struct{}{})
+	}
+}
+
+func query(w http.ResponseWriter, cdc *wire.Codec, ctx context.CoreContext, queryRoute, route string, params interface{}) {
+	data, err := cdc.MarshalJSON(params)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	res, err := ctx.QueryCustom(queryRoute, route, data)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, fmt.Errorf("Couldn't query %s. Error: %s", route, err.Error()))
+		return
+	}
+	w.Write(res)
+}
@@ -0,0 +1,231 @@
+package rest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	crypto "github.com/tendermint/go-crypto"
+	"github.com/tendermint/go-crypto/keys"
+
+	"inschain-tendermint/client/context"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/stake"
+)
+
+// the fields every staking tx request carries to sign it
+type baseReq struct {
+	LocalAccountName string `json:"name"`
+	Password         string `json:"password"`
+	ChainID          string `json:"chain_id"`
+	Sequence         int64  `json:"sequence"`
+	GenerateOnly     bool   `json:"generate_only"`
+}
+
+// the candidate is the address of the signing key, the pubkey is hex encoded
+// and the rates are decimals
+type declareCandidacyBody struct {
+	baseReq
+	PubKey                  string            `json:"pub_key"`
+	Amount                  sdk.Coin          `json:"amount"`
+	Description             stake.Description `json:"description"`
+	CommissionRate          string            `json:"commission_rate"`
+	CommissionMaxRate       string            `json:"commission_max_rate"`
+	CommissionMaxChangeRate string            `json:"commission_max_change_rate"`
+}
+
+// the commission rate is only changed when given
+type editCandidacyBody struct {
+	baseReq
+	Description    stake.Description `json:"description"`
+	CommissionRate string            `json:"commission_rate"`
+}
+
+type delegateBody struct {
+	baseReq
+	CandidateAddr sdk.Address `json:"candidate"`
+	Amount        sdk.Coin    `json:"amount"`
+}
+
+// the shares are a decimal, or MAX to unbond everything
+type unbondBody struct {
+	baseReq
+	CandidateAddr sdk.Address `json:"candidate"`
+	Shares        string      `json:"shares"`
+}
+
+// DeclareCandidacyHandlerFn - http request handler to declare the candidacy of the signer
+func DeclareCandidacyHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m declareCandidacyBody
+		if !readBody(w, r, &m) {
+			return
+		}
+		info, err := kb.Get(m.LocalAccountName)
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, err)
+			return
+		}
+		pkBytes, err := hex.DecodeString(m.PubKey)
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err)
+			return
+		}
+		pk, err := crypto.PubKeyFromBytes(pkBytes)
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err)
+			return
+		}
+		rates := make([]sdk.Rat, 3)
+		for i, rate := range []string{m.CommissionRate, m.CommissionMaxRate, m.CommissionMaxChangeRate} {
+			rates[i], err = sdk.NewRatFromDecimal(rate)
+			if err != nil {
+				writeErr(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		commission := stake.NewCommission(rates[0], rates[1], rates[2])
+		msg := stake.NewMsgDeclareCandidacy(sdk.Address(info.PubKey.Address()), pk, m.Amount, m.Description, commission)
+		signAndBroadcast(w, cdc, ctx, m.baseReq, msg)
+	}
+}
+
+// EditCandidacyHandlerFn - http request handler to edit the candidacy of the signer
+func EditCandidacyHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m editCandidacyBody
+		if !readBody(w, r, &m) {
+			return
+		}
+		info, err := kb.Get(m.LocalAccountName)
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, err)
+			return
+		}
+		var commissionRate *sdk.Rat
+		if m.CommissionRate != "" {
+			rate, err := sdk.NewRatFromDecimal(m.CommissionRate)
+			if err != nil {
+				writeErr(w, http.StatusBadRequest, err)
+				return
+			}
+			commissionRate = &rate
+		}
+
+		msg := stake.NewMsgEditCandidacy(sdk.Address(info.PubKey.Address()), m.Description, commissionRate)
+		signAndBroadcast(w, cdc, ctx, m.baseReq, msg)
+	}
+}
+
+// DelegateHandlerFn - http request handler to delegate coins of the signer to a candidate
+func DelegateHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m delegateBody
+		if !readBody(w, r, &m) {
+			return
+		}
+		info, err := kb.Get(m.LocalAccountName)
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		msg := stake.NewMsgDelegate(sdk.Address(info.PubKey.Address()), m.CandidateAddr, m.Amount)
+		signAndBroadcast(w, cdc, ctx, m.baseReq, msg)
+	}
+}
+
+// UnbondHandlerFn - http request handler to unbond shares of the signer from a candidate
+func UnbondHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m unbondBody
+		if !readBody(w, r, &m) {
+			return
+		}
+		info, err := kb.Get(m.LocalAccountName)
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, err)
+			return
+		}
+		if m.Shares != "MAX" {
+			shares, err := sdk.NewRatFromDecimal(m.Shares)
+			if err != nil {
+				writeErr(w, http.StatusBadRequest, err)
+				return
+			}
+			if !shares.GT(sdk.ZeroRat()) {
+				writeErr(w, http.StatusBadRequest, fmt.Errorf("shares must be positive integer or decimal (ex. 123, 1.23456789)"))
+				return
+			}
+		}
+
+		msg := stake.NewMsgUnbond(sdk.Address(info.PubKey.Address()), m.CandidateAddr, m.Shares)
+		signAndBroadcast(w, cdc, ctx, m.baseReq, msg)
+	}
+}
+
+//_______________________________________________________________________
+
+func readBody(w http.ResponseWriter, r *http.Request, m interface{}) bool {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return false
+	}
+	err = json.Unmarshal(body, m)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+// return the unsigned tx to sign offline, or sign and broadcast it
+func signAndBroadcast(w http.ResponseWriter, cdc *wire.Codec, ctx context.CoreContext, req baseReq, msg sdk.Msg) {
+	if err := msg.ValidateBasic(); err != nil {
+		writeErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx = ctx.WithSequence(req.Sequence)
+	if req.GenerateOnly {
+		signMsg, err := ctx.BuildSignMsg([]sdk.Msg{msg})
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err)
+			return
+		}
+		output, err := wire.MarshalJSONIndent(cdc, signMsg)
+		if err != nil {
+			writeErr(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Write(output)
+		return
+	}
+
+	txBytes, err := ctx.SignAndBuild(req.LocalAccountName, req.Password, []sdk.Msg{msg}, cdc)
+	if err != nil {
+		writeErr(w, http.StatusUnauthorized, err)
+		return
+	}
+	res, err := ctx.BroadcastTx(txBytes)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	output, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Write(output)
+}
+
+func writeErr(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	w.Write([]byte(err.Error()))
+}
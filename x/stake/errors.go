@@ -4,7 +4,7 @@ package stake
 import (
 	"fmt"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 type CodeType = sdk.CodeType
@@ -68,6 +68,18 @@ func ErrCommissionNegative(codespace sdk.CodespaceType) sdk.Error {
 func ErrCommissionHuge(codespace sdk.CodespaceType) sdk.Error {
 	return newError(codespace, CodeInvalidValidator, "Commission cannot be more than 100%")
 }
+func ErrCommissionGTMaxRate(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidValidator, "Commission cannot be more than the max rate")
+}
+func ErrCommissionChangeRateGTMaxRate(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidValidator, "Commission max change rate cannot be more than the max rate")
+}
+func ErrCommissionGTMaxChangeRate(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidValidator, "Commission cannot change by more than the max change rate")
+}
+func ErrCommissionUpdateTime(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidValidator, "Commission cannot be changed more than once a day")
+}
 func ErrBadValidatorAddr(codespace sdk.CodespaceType) sdk.Error {
 	return newError(codespace, CodeInvalidValidator, "Validator does not exist for that address")
 }
@@ -80,6 +92,12 @@ func ErrBadDelegatorAddr(codespace sdk.CodespaceType) sdk.Error {
 func ErrCandidateExistsAddr(codespace sdk.CodespaceType) sdk.Error {
 	return newError(codespace, CodeInvalidValidator, "Candidate already exist, cannot re-declare candidacy")
 }
+func ErrCandidatePubKeyEmpty(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidValidator, "Cannot declare candidacy without a validator pubkey")
+}
+func ErrCandidateExistsPubKey(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidValidator, "Candidate already exist for this pubkey, must use new validator pubkey")
+}
 func ErrCandidateRevoked(codespace sdk.CodespaceType) sdk.Error {
 	return newError(codespace, CodeInvalidValidator, "Candidacy for this address is currently revoked")
 }
@@ -101,6 +119,15 @@ func ErrInsufficientFunds(codespace sdk.CodespaceType) sdk.Error {
 func ErrBadShares(codespace sdk.CodespaceType) sdk.Error {
 	return newError(codespace, CodeInvalidInput, "bad shares provided as input, must be MAX or decimal")
 }
+func ErrSelfRedelegation(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidBond, "Cannot redelegate to the same candidate")
+}
+func ErrTransitiveRedelegation(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidBond, "Redelegation to this candidate has not completed, cannot redelegate from it yet")
+}
+func ErrBadParam(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidInput, msg)
+}
 func ErrBadRemoveValidator(codespace sdk.CodespaceType) sdk.Error {
 	return newError(codespace, CodeInvalidValidator, "Error removing validator")
 }
@@ -1,10 +1,300 @@
 package stake
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	"math/big"
+	"sort"
+
+	sdk "inschain-tendermint/types"
 )
 
-// Handle fee distribution to the validators and delegators
-func FeeHandler(ctx sdk.Context, tx sdk.Tx, fee sdk.Coins) {
+// Fees are collected by the ante handler into the fee pool. At the end of
+// every block the fee pool is distributed to the bonded validators, in
+// proportion to their voting power. The owner of a validator keeps the
+// commission rate of the candidate out of its rewards, the rest goes to its
+// delegators.
+//
+// Delegator rewards are accounted lazily: every candidate accumulates its
+// rewards per delegator share, and every bond remembers the accumulated
+// value of its last withdrawal. The rewards of a bond are withdrawn before
+// any change of its shares, and can be withdrawn anytime with MsgWithdraw.
+
+// NewFeeHandler returns a FeeHandler adding the fees to the fee pool
+func NewFeeHandler(k Keeper) sdk.FeeHandler {
+	return func(ctx sdk.Context, tx sdk.Tx, fee sdk.Coins) {
+		feePool := k.GetFeePool(ctx)
+		feePool.Fees = feePool.Fees.Plus(fee)
+		k.setFeePool(ctx, feePool)
+
+		// the ante handler took the fees out of the accounts
+		err := k.supplyKeeper.AddEscrow(ctx, MsgType, fee)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// distribute the fee pool to the bonded validators,
+// the coins which cannot be divided stay in the pool
+func (k Keeper) distributeFees(ctx sdk.Context) {
+	feePool := k.GetFeePool(ctx)
+	if feePool.Fees.IsZero() {
+		return
+	}
+
+	validators := k.getBondedValidators(ctx)
+	totalPower := sdk.ZeroRat()
+	for _, validator := range validators {
+		totalPower = totalPower.Add(validator.Power)
+	}
+	if totalPower.IsZero() {
+		return
+	}
+
+	fees := NewRatCoins(feePool.Fees)
+	var distributed sdk.Coins
+	for _, validator := range validators {
+		candidate, found := k.GetCandidate(ctx, validator.Address)
+		if !found {
+			continue
+		}
+		reward := fees.Mul(validator.Power.Quo(totalPower)).Floor()
+		if reward.IsZero() {
+			continue
+		}
+		distributed = distributed.Plus(reward)
+
+		// the owner keeps the commission, and everything if there are no delegator shares
+		rewards := k.GetValidatorRewards(ctx, candidate.Address)
+		commission := NewRatCoins(reward).Mul(candidate.Commission.Rate).Floor()
+		delegatorsReward := reward.Minus(commission)
+		if candidate.Liabilities.IsZero() {
+			commission = reward
+			delegatorsReward = nil
+		}
+		rewards.Commission = rewards.Commission.Plus(commission)
+		if !delegatorsReward.IsZero() {
+			perShare := NewRatCoins(delegatorsReward).Quo(candidate.Liabilities)
+			rewards.RewardsPerShare = rewards.RewardsPerShare.Plus(perShare)
+		}
+		k.setValidatorRewards(ctx, rewards)
+	}
+
+	feePool.Fees = feePool.Fees.Minus(distributed)
+	k.setFeePool(ctx, feePool)
+}
+
+// PendingRewards returns the rewards of a bond not yet withdrawn
+func PendingRewards(bond DelegatorBond, rewards ValidatorRewards) sdk.Coins {
+	return rewards.RewardsPerShare.Minus(bond.RewardsPerShare).Mul(bond.Shares).Floor()
+}
+
+// withdraw the pending rewards of a bond to the delegator,
+// the bond is returned with its updated rewards and must be saved by the caller
+func (k Keeper) withdrawBondRewards(ctx sdk.Context, bond DelegatorBond) (DelegatorBond, sdk.Coins, sdk.Error) {
+	rewards := k.GetValidatorRewards(ctx, bond.CandidateAddr)
+	amount := PendingRewards(bond, rewards)
+	bond.RewardsPerShare = rewards.RewardsPerShare
+	if amount.IsZero() {
+		return bond, nil, nil
+	}
+	_, err := k.supplyKeeper.ReleaseCoins(ctx, MsgType, bond.DelegatorAddr, amount)
+	if err != nil {
+		return bond, nil, err
+	}
+	return bond, amount, nil
+}
+
+// withdraw the commission of a candidate to its owner
+func (k Keeper) withdrawCommission(ctx sdk.Context, candidateAddr sdk.Address) (sdk.Coins, sdk.Error) {
+	rewards := k.GetValidatorRewards(ctx, candidateAddr)
+	amount := rewards.Commission
+	if amount.IsZero() {
+		return nil, nil
+	}
+	_, err := k.supplyKeeper.ReleaseCoins(ctx, MsgType, candidateAddr, amount)
+	if err != nil {
+		return nil, err
+	}
+	rewards.Commission = nil
+	k.setValidatorRewards(ctx, rewards)
+	return amount, nil
+}
+
+// get the bonded validators from the power index, without updating the
+// validator set like GetValidators does
+func (k Keeper) getBondedValidators(ctx sdk.Context) (validators []Validator) {
+	store := ctx.KVStore(k.storeKey)
+	maxValidators := k.GetParams(ctx).MaxValidators
+	iterator := store.ReverseIterator(subspace(ValidatorsKey)) // largest to smallest
+	for i := 0; iterator.Valid() && i < int(maxValidators); i++ {
+		var validator Validator
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &validator)
+		if err != nil {
+			panic(err)
+		}
+		if validator.Power.GT(sdk.ZeroRat()) {
+			validators = append(validators, validator)
+		}
+		iterator.Next()
+	}
+	iterator.Close()
+	return validators
+}
+
+//_______________________________________________________________________
+
+// load/save the fee pool
+func (k Keeper) GetFeePool(ctx sdk.Context) (feePool FeePool) {
+	store := ctx.KVStore(k.storeKey)
+	b := store.Get(FeePoolKey)
+	if b == nil {
+		return FeePool{}
+	}
+	err := k.cdc.UnmarshalJSON(b, &feePool)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+func (k Keeper) setFeePool(ctx sdk.Context, feePool FeePool) {
+	store := ctx.KVStore(k.storeKey)
+	b, err := k.cdc.MarshalJSON(feePool)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(FeePoolKey, b)
+}
+
+// load/save the rewards of a candidate
+func (k Keeper) GetValidatorRewards(ctx sdk.Context, candidateAddr sdk.Address) (rewards ValidatorRewards) {
+	store := ctx.KVStore(k.storeKey)
+	b := store.Get(GetValidatorRewardsKey(candidateAddr))
+	if b == nil {
+		return ValidatorRewards{CandidateAddr: candidateAddr}
+	}
+	err := k.cdc.UnmarshalJSON(b, &rewards)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// load all the rewards of the candidates
+func (k Keeper) getAllValidatorRewards(ctx sdk.Context) (allRewards []ValidatorRewards) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(subspace(ValidatorRewardsKey))
+	for ; iterator.Valid(); iterator.Next() {
+		var rewards ValidatorRewards
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &rewards)
+		if err != nil {
+			panic(err)
+		}
+		allRewards = append(allRewards, rewards)
+	}
+	iterator.Close()
+	return
+}
+
+func (k Keeper) setValidatorRewards(ctx sdk.Context, rewards ValidatorRewards) {
+	store := ctx.KVStore(k.storeKey)
+	b, err := k.cdc.MarshalJSON(rewards)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(GetValidatorRewardsKey(rewards.CandidateAddr), b)
+}
+
+func (k Keeper) removeValidatorRewards(ctx sdk.Context, candidateAddr sdk.Address) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(GetValidatorRewardsKey(candidateAddr))
+}
+
+//_______________________________________________________________________
+
+// RatCoin - a rational amount of coins, used to account rewards per share
+type RatCoin struct {
+	Denom  string  `json:"denom"`
+	Amount sdk.Rat `json:"amount"`
+}
+
+// RatCoins - rational amounts of coins, sorted by denomination
+type RatCoins []RatCoin
+
+// NewRatCoins converts coins to rational coins
+func NewRatCoins(coins sdk.Coins) RatCoins {
+	ratCoins := make(RatCoins, 0, len(coins))
+	for _, coin := range coins {
+		ratCoins = append(ratCoins, RatCoin{coin.Denom, sdk.NewRat(coin.Amount)})
+	}
+	return ratCoins.sort()
+}
+
+// Plus adds two sets of rational coins
+func (coins RatCoins) Plus(coinsB RatCoins) RatCoins {
+	sum := append(make(RatCoins, 0, len(coins)+len(coinsB)), coins...)
+	for _, coinB := range coinsB {
+		i := sum.index(coinB.Denom)
+		if i < 0 {
+			sum = append(sum, coinB)
+			continue
+		}
+		sum[i] = RatCoin{coinB.Denom, sum[i].Amount.Add(coinB.Amount)}
+	}
+	return sum.sort()
+}
+
+// Minus subtracts a set of rational coins from another
+func (coins RatCoins) Minus(coinsB RatCoins) RatCoins {
+	negative := make(RatCoins, len(coinsB))
+	for i, coinB := range coinsB {
+		negative[i] = RatCoin{coinB.Denom, sdk.ZeroRat().Sub(coinB.Amount)}
+	}
+	return coins.Plus(negative)
+}
+
+// Mul multiplies every amount by a rational
+func (coins RatCoins) Mul(r sdk.Rat) RatCoins {
+	res := make(RatCoins, len(coins))
+	for i, coin := range coins {
+		res[i] = RatCoin{coin.Denom, coin.Amount.Mul(r)}
+	}
+	return res
+}
+
+// Quo divides every amount by a rational
+func (coins RatCoins) Quo(r sdk.Rat) RatCoins {
+	res := make(RatCoins, len(coins))
+	for i, coin := range coins {
+		res[i] = RatCoin{coin.Denom, coin.Amount.Quo(r)}
+	}
+	return res
+}
+
+// Floor returns the whole positive coins, rounded down
+func (coins RatCoins) Floor() sdk.Coins {
+	var res sdk.Coins
+	for _, coin := range coins {
+		amount := new(big.Int).Quo(coin.Amount.Rat.Num(), coin.Amount.Rat.Denom())
+		if amount.Sign() > 0 {
+			res = append(res, sdk.Coin{coin.Denom, amount.Int64()})
+		}
+	}
+	return res
+}
+
+func (coins RatCoins) index(denom string) int {
+	for i, coin := range coins {
+		if coin.Denom == denom {
+			return i
+		}
+	}
+	return -1
+}
 
+func (coins RatCoins) sort() RatCoins {
+	sort.Slice(coins, func(i, j int) bool {
+		return coins[i].Denom < coins[j].Denom
+	})
+	return coins
 }
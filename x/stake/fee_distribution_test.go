@@ -0,0 +1,81 @@
+package stake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdk "inschain-tendermint/types"
+)
+
+func TestFeeDistribution(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 1000)
+	feeHandler := NewFeeHandler(keeper)
+
+	// two candidates with a power of 10 and 40, a quarter delegated to the
+	// second one, both with a commission of 10%
+	for i, amt := range []int64{10, 30} {
+		msg := newTestMsgDeclareCandidacy(addrs[i], pks[i], amt)
+		msg.Commission = NewCommission(sdk.NewRat(10, 100), sdk.NewRat(20, 100), sdk.NewRat(1, 100))
+		got := handleMsgDeclareCandidacy(ctx, msg, keeper)
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	got := handleMsgDelegate(ctx, newTestMsgDelegate(addrs[2], addrs[1], 10), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+
+	// the fees are collected in the pool
+	feeHandler(ctx, nil, sdk.Coins{{"steak", 60}})
+	feeHandler(ctx, nil, sdk.Coins{{"steak", 40}})
+	assert.Equal(t, sdk.Coins{{"steak", 100}}, keeper.GetFeePool(ctx).Fees)
+
+	// and distributed in proportion to the power: 20 and 80, less 10% commission
+	keeper.Tick(ctx)
+	assert.True(t, keeper.GetFeePool(ctx).Fees.IsZero())
+	rewards0 := keeper.GetValidatorRewards(ctx, addrs[0])
+	rewards1 := keeper.GetValidatorRewards(ctx, addrs[1])
+	assert.Equal(t, sdk.Coins{{"steak", 2}}, rewards0.Commission)
+	assert.Equal(t, sdk.Coins{{"steak", 8}}, rewards1.Commission)
+
+	// the delegators share the rest: 18/10 and 72/40 steak per share
+	bond, found := keeper.GetDelegatorBond(ctx, addrs[2], addrs[1])
+	require.True(t, found)
+	assert.Equal(t, sdk.Coins{{"steak", 18}}, PendingRewards(bond, rewards1))
+	bond, found = keeper.GetDelegatorBond(ctx, addrs[1], addrs[1])
+	require.True(t, found)
+	assert.Equal(t, sdk.Coins{{"steak", 54}}, PendingRewards(bond, rewards1))
+
+	// a delegator withdraws its rewards
+	got = handleMsgWithdraw(ctx, NewMsgWithdraw(addrs[2], addrs[1]), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+	assert.Equal(t, int64(1008), keeper.supplyKeeper.GetCoins(ctx, addrs[2]).AmountOf("steak"))
+	bond, _ = keeper.GetDelegatorBond(ctx, addrs[2], addrs[1])
+	assert.True(t, PendingRewards(bond, rewards1).IsZero())
+
+	// the owner withdraws its rewards and the commission
+	got = handleMsgWithdraw(ctx, NewMsgWithdraw(addrs[1], addrs[1]), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+	assert.Equal(t, int64(1032), keeper.supplyKeeper.GetCoins(ctx, addrs[1]).AmountOf("steak"))
+	assert.True(t, keeper.GetValidatorRewards(ctx, addrs[1]).Commission.IsZero())
+
+	// the rewards are withdrawn before the shares of a bond change
+	feeHandler(ctx, nil, sdk.Coins{{"steak", 100}})
+	keeper.Tick(ctx)
+	got = handleMsgDelegate(ctx, newTestMsgDelegate(addrs[2], addrs[1], 10), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+	assert.Equal(t, int64(1016), keeper.supplyKeeper.GetCoins(ctx, addrs[2]).AmountOf("steak"))
+	bond, _ = keeper.GetDelegatorBond(ctx, addrs[2], addrs[1])
+	assert.True(t, PendingRewards(bond, keeper.GetValidatorRewards(ctx, addrs[1])).IsZero())
+
+	// the fees which cannot be divided stay in the pool
+	feeHandler(ctx, nil, sdk.Coins{{"steak", 1}})
+	keeper.Tick(ctx)
+	assert.Equal(t, sdk.Coins{{"steak", 1}}, keeper.GetFeePool(ctx).Fees)
+}
+
+func TestWithdrawWithoutBond(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 1000)
+
+	got := handleMsgWithdraw(ctx, NewMsgWithdraw(addrs[2], addrs[1]), keeper)
+	assert.False(t, got.IsOK(), "%v", got)
+}
@@ -3,7 +3,7 @@ package stake
 import (
 	"bytes"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 	abci "github.com/tendermint/abci/types"
 )
 
@@ -13,6 +13,8 @@ const (
 	GasEditCandidacy    int64 = 20
 	GasDelegate         int64 = 20
 	GasUnbond           int64 = 20
+	GasRedelegate       int64 = 20
+	GasWithdraw         int64 = 20
 )
 
 //_______________________________________________________________________
@@ -29,6 +31,10 @@ func NewHandler(k Keeper) sdk.Handler {
 			return handleMsgDelegate(ctx, msg, k)
 		case MsgUnbond:
 			return handleMsgUnbond(ctx, msg, k)
+		case MsgRedelegate:
+			return handleMsgRedelegate(ctx, msg, k)
+		case MsgWithdraw:
+			return handleMsgWithdraw(ctx, msg, k)
 		default:
 			return sdk.ErrTxDecode("invalid message parse in staking module").Result()
 		}
@@ -58,22 +64,62 @@ func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
 	for _, bond := range data.Bonds {
 		k.setDelegatorBond(ctx, bond)
 	}
+	k.setFeePool(ctx, data.FeePool)
+	for _, rewards := range data.Rewards {
+		k.setValidatorRewards(ctx, rewards)
+	}
+	for _, ubd := range data.UnbondingDelegations {
+		k.setUnbondingDelegation(ctx, ubd)
+		for _, entry := range ubd.Entries {
+			k.insertUnbondingQueue(ctx, ubd, entry.CompletionTime)
+		}
+	}
+	for _, red := range data.Redelegations {
+		k.setRedelegation(ctx, red)
+		for _, entry := range red.Entries {
+			k.insertRedelegationQueue(ctx, red, entry.CompletionTime)
+		}
+	}
 }
 
 // WriteGenesis - output genesis parameters
 func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
 	pool := k.GetPool(ctx)
 	params := k.GetParams(ctx)
-	candidates := k.GetCandidates(ctx, 32767)
-	bonds := k.getBonds(ctx, 32767)
+	candidates := k.getAllCandidates(ctx)
+	bonds := k.getBonds(ctx)
+	feePool := k.GetFeePool(ctx)
+	rewards := k.getAllValidatorRewards(ctx)
+	unbondingDelegations := k.getAllUnbondingDelegations(ctx)
+	redelegations := k.getAllRedelegations(ctx)
 	return GenesisState{
 		pool,
 		params,
 		candidates,
 		bonds,
+		feePool,
+		rewards,
+		unbondingDelegations,
+		redelegations,
 	}
 }
 
+// GenesisEscrow - the coins of the genesis held by stake: the tokens of the
+// pool and of the unbonding delegations, and the fees not yet distributed
+func GenesisEscrow(data GenesisState) sdk.Coins {
+	tokens := data.Pool.BondedPool + data.Pool.UnbondedPool
+	for _, ubd := range data.UnbondingDelegations {
+		for _, entry := range ubd.Entries {
+			tokens += entry.Balance
+		}
+	}
+	escrow := data.FeePool.Fees
+	if tokens > 0 {
+		escrow = escrow.Plus(sdk.Coins{{data.Params.BondDenom, tokens}})
+	}
+	return escrow
+}
+
 //_____________________________________________________________________
 
 // These functions assume everything has been authenticated,
@@ -86,9 +132,19 @@ func handleMsgDeclareCandidacy(ctx sdk.Context, msg MsgDeclareCandidacy, k Keepe
 	if found {
 		return ErrCandidateExistsAddr(k.codespace).Result()
 	}
+	if msg.PubKey == nil {
+		return ErrCandidatePubKeyEmpty(k.codespace).Result()
+	}
+	_, found = k.GetCandidateByPubKeyAddress(ctx, msg.PubKey.Address())
+	if found {
+		return ErrCandidateExistsPubKey(k.codespace).Result()
+	}
 	if msg.Bond.Denom != k.GetParams(ctx).BondDenom {
 		return ErrBadBondingDenom(k.codespace).Result()
 	}
+	if err := msg.Commission.Validate(k.codespace); err != nil {
+		return err.Result()
+	}
 	if ctx.IsCheckTx() {
 		return sdk.Result{
 			GasUsed: GasDeclareCandidacy,
@@ -96,6 +152,8 @@ func handleMsgDeclareCandidacy(ctx sdk.Context, msg MsgDeclareCandidacy, k Keepe
 	}
 
 	candidate := NewCandidate(msg.CandidateAddr, msg.PubKey, msg.Description)
+	candidate.Commission = msg.Commission
+	candidate.Commission.UpdateTime = ctx.BlockHeader().Time
 	k.setCandidate(ctx, candidate)
 
 	// move coins from the msg.Address account to a (self-bond) delegator account
@@ -114,6 +172,13 @@ func handleMsgEditCandidacy(ctx sdk.Context, msg MsgEditCandidacy, k Keeper) sdk
 	if !found {
 		return ErrBadCandidateAddr(k.codespace).Result()
 	}
+	now := ctx.BlockHeader().Time
+	if msg.CommissionRate != nil {
+		err := candidate.Commission.ValidateNewRate(k.codespace, *msg.CommissionRate, now)
+		if err != nil {
+			return err.Result()
+		}
+	}
 	if ctx.IsCheckTx() {
 		return sdk.Result{
 			GasUsed: GasEditCandidacy,
@@ -122,10 +187,19 @@ func handleMsgEditCandidacy(ctx sdk.Context, msg MsgEditCandidacy, k Keeper) sdk
 
 	// XXX move to types
 	// replace all editable fields (clients should autofill existing values)
-	candidate.Description.Moniker = msg.Description.Moniker
-	candidate.Description.Identity = msg.Description.Identity
-	candidate.Description.Website = msg.Description.Website
-	candidate.Description.Details = msg.Description.Details
+	empty := Description{}
+	if msg.Description != empty {
+		candidate.Description.Moniker = msg.Description.Moniker
+		candidate.Description.Identity = msg.Description.Identity
+		candidate.Description.Website = msg.Description.Website
+		candidate.Description.Details = msg.Description.Details
+	}
+
+	// the rewards distributed so far keep the old rate
+	if msg.CommissionRate != nil {
+		candidate.Commission.Rate = *msg.CommissionRate
+		candidate.Commission.UpdateTime = now
+	}
 
 	k.setCandidate(ctx, candidate)
 	return sdk.Result{}
@@ -159,7 +233,20 @@ func handleMsgDelegate(ctx sdk.Context, msg MsgDelegate, k Keeper) sdk.Result {
 func delegate(ctx sdk.Context, k Keeper, delegatorAddr sdk.Address,
 	bondAmt sdk.Coin, candidate Candidate) sdk.Error {
 
-	// Get or create the delegator bond
+	_, err := k.supplyKeeper.EscrowCoins(ctx, MsgType, delegatorAddr, sdk.Coins{bondAmt})
+	if err != nil {
+		return err
+	}
+	_, err = bondTokens(ctx, k, delegatorAddr, bondAmt.Amount, candidate)
+	return err
+}
+
+// bond the tokens to the candidate, returning the shares they bought
+func bondTokens(ctx sdk.Context, k Keeper, delegatorAddr sdk.Address,
+	amount int64, candidate Candidate) (sdk.Rat, sdk.Error) {
+
+	// Get or create the delegator bond,
+	// the rewards of the current shares are withdrawn first
 	bond, found := k.GetDelegatorBond(ctx, delegatorAddr, candidate.Address)
 	if !found {
 		bond = DelegatorBond{
@@ -168,14 +255,14 @@ func delegate(ctx sdk.Context, k Keeper, delegatorAddr sdk.Address,
 			Shares:        sdk.ZeroRat(),
 		}
 	}
+	bond, _, err := k.withdrawBondRewards(ctx, bond)
+	if err != nil {
+		return sdk.ZeroRat(), err
+	}
 
 	// Account new shares, save
 	pool := k.GetPool(ctx)
-	_, err := k.coinKeeper.SubtractCoins(ctx, bond.DelegatorAddr, sdk.Coins{bondAmt})
-	if err != nil {
-		return err
-	}
-	pool, candidate, newShares := pool.candidateAddTokens(candidate, bondAmt.Amount)
+	pool, candidate, newShares := pool.candidateAddTokens(candidate, amount)
 	bond.Shares = bond.Shares.Add(newShares)
 
 	// Update bond height
@@ -184,7 +271,25 @@ func delegate(ctx sdk.Context, k Keeper, delegatorAddr sdk.Address,
 	k.setDelegatorBond(ctx, bond)
 	k.setCandidate(ctx, candidate)
 	k.setPool(ctx, pool)
-	return nil
+	return newShares, nil
+}
+
+// parse the shares to unbond from the bond, MAX unbonds all of them
+func getUnbondShares(k Keeper, bond DelegatorBond, sharesStr string) (sdk.Rat, sdk.Error) {
+	if !bond.Shares.GT(sdk.ZeroRat()) { // bond shares < msg shares
+		return sdk.ZeroRat(), ErrInsufficientFunds(k.codespace)
+	}
+	if sharesStr == "MAX" {
+		return bond.Shares, nil
+	}
+	shares, err := sdk.NewRatFromDecimal(sharesStr)
+	if err != nil {
+		return sdk.ZeroRat(), err
+	}
+	if bond.Shares.LT(shares) {
+		return sdk.ZeroRat(), ErrNotEnoughBondShares(k.codespace, sharesStr)
+	}
+	return shares, nil
 }
 
 func handleMsgUnbond(ctx sdk.Context, msg MsgUnbond, k Keeper) sdk.Result {
@@ -194,26 +299,11 @@ func handleMsgUnbond(ctx sdk.Context, msg MsgUnbond, k Keeper) sdk.Result {
 	if !found {
 		return ErrNoDelegatorForAddress(k.codespace).Result()
 	}
-	if !bond.Shares.GT(sdk.ZeroRat()) { // bond shares < msg shares
-		return ErrInsufficientFunds(k.codespace).Result()
-	}
-
-	var shares sdk.Rat
 
 	// test that there are enough shares to unbond
-	if msg.Shares == "MAX" {
-		if !bond.Shares.GT(sdk.ZeroRat()) {
-			return ErrNotEnoughBondShares(k.codespace, msg.Shares).Result()
-		}
-	} else {
-		var err sdk.Error
-		shares, err = sdk.NewRatFromDecimal(msg.Shares)
-		if err != nil {
-			return err.Result()
-		}
-		if bond.Shares.LT(shares) {
-			return ErrNotEnoughBondShares(k.codespace, msg.Shares).Result()
-		}
+	shares, err := getUnbondShares(k, bond, msg.Shares)
+	if err != nil {
+		return err.Result()
 	}
 
 	// get candidate
@@ -228,9 +318,72 @@ func handleMsgUnbond(ctx sdk.Context, msg MsgUnbond, k Keeper) sdk.Result {
 		}
 	}
 
-	// retrieve the amount of bonds to remove (TODO remove redundancy already serialized)
-	if msg.Shares == "MAX" {
-		shares = bond.Shares
+	returnAmount, err := unbond(ctx, k, bond, candidate, shares)
+	if err != nil {
+		return err.Result()
+	}
+
+	// the coins are returned once the unbonding period is over,
+	// until then they can be slashed for the infractions of the candidate
+	k.addUnbondingDelegationEntry(ctx, bond.DelegatorAddr, candidate.Address, returnAmount)
+	return sdk.Result{}
+}
+
+func handleMsgRedelegate(ctx sdk.Context, msg MsgRedelegate, k Keeper) sdk.Result {
+
+	bond, found := k.GetDelegatorBond(ctx, msg.DelegatorAddr, msg.CandidateSrcAddr)
+	if !found {
+		return ErrNoDelegatorForAddress(k.codespace).Result()
+	}
+	shares, err := getUnbondShares(k, bond, msg.Shares)
+	if err != nil {
+		return err.Result()
+	}
+	srcCandidate, found := k.GetCandidate(ctx, msg.CandidateSrcAddr)
+	if !found {
+		return ErrNoCandidateForAddress(k.codespace).Result()
+	}
+	dstCandidate, found := k.GetCandidate(ctx, msg.CandidateDstAddr)
+	if !found {
+		return ErrBadCandidateAddr(k.codespace).Result()
+	}
+	if dstCandidate.Status == Revoked {
+		return ErrCandidateRevoked(k.codespace).Result()
+	}
+
+	// tokens redelegated to the source candidate must complete their
+	// redelegation first, else they could hop away from any slashing
+	if k.hasReceivingRedelegation(ctx, msg.DelegatorAddr, msg.CandidateSrcAddr) {
+		return ErrTransitiveRedelegation(k.codespace).Result()
+	}
+
+	if ctx.IsCheckTx() {
+		return sdk.Result{
+			GasUsed: GasRedelegate,
+		}
+	}
+
+	tokens, err := unbond(ctx, k, bond, srcCandidate, shares)
+	if err != nil {
+		return err.Result()
+	}
+	sharesDst, err := bondTokens(ctx, k, msg.DelegatorAddr, tokens, dstCandidate)
+	if err != nil {
+		return err.Result()
+	}
+	k.addRedelegationEntry(ctx, msg.DelegatorAddr, msg.CandidateSrcAddr, msg.CandidateDstAddr, tokens, sharesDst)
+	return sdk.Result{}
+}
+
+// remove the shares from the bond with the candidate, returning the tokens
+// they were worth, the candidate is revoked if its owner unbonds all its
+// shares and removed once it has no shares left
+func unbond(ctx sdk.Context, k Keeper, bond DelegatorBond, candidate Candidate, shares sdk.Rat) (int64, sdk.Error) {
+
+	// withdraw the rewards of the bond before its shares change
+	bond, _, err := k.withdrawBondRewards(ctx, bond)
+	if err != nil {
+		return 0, err
 	}
 
 	// subtract bond tokens from delegator bond
@@ -254,11 +407,9 @@ func handleMsgUnbond(ctx sdk.Context, msg MsgUnbond, k Keeper) sdk.Result {
 		k.setDelegatorBond(ctx, bond)
 	}
 
-	// Add the coins
+	// remove the shares from the candidate
 	p := k.GetPool(ctx)
 	p, candidate, returnAmount := p.candidateRemoveShares(candidate, shares)
-	returnCoins := sdk.Coins{{k.GetParams(ctx).BondDenom, returnAmount}}
-	k.coinKeeper.AddCoins(ctx, bond.DelegatorAddr, returnCoins)
 
 	/////////////////////////////////////
 
@@ -276,10 +427,44 @@ func handleMsgUnbond(ctx sdk.Context, msg MsgUnbond, k Keeper) sdk.Result {
 
 	// deduct shares from the candidate
 	if candidate.Liabilities.IsZero() {
+		// the commission left goes to the owner with the candidate
+		_, err = k.withdrawCommission(ctx, candidate.Address)
+		if err != nil {
+			return 0, err
+		}
+		k.removeValidatorRewards(ctx, candidate.Address)
 		k.removeCandidate(ctx, candidate.Address)
 	} else {
 		k.setCandidate(ctx, candidate)
 	}
 	k.setPool(ctx, p)
+	return returnAmount, nil
+}
+
+func handleMsgWithdraw(ctx sdk.Context, msg MsgWithdraw, k Keeper) sdk.Result {
+
+	bond, found := k.GetDelegatorBond(ctx, msg.DelegatorAddr, msg.CandidateAddr)
+	if !found {
+		return ErrNoDelegatorForAddress(k.codespace).Result()
+	}
+	if ctx.IsCheckTx() {
+		return sdk.Result{
+			GasUsed: GasWithdraw,
+		}
+	}
+
+	bond, _, err := k.withdrawBondRewards(ctx, bond)
+	if err != nil {
+		return err.Result()
+	}
+	k.setDelegatorBond(ctx, bond)
+
+	// the owner of the candidate also withdraws its commission
+	if bytes.Equal(msg.DelegatorAddr, msg.CandidateAddr) {
+		_, err = k.withdrawCommission(ctx, msg.CandidateAddr)
+		if err != nil {
+			return err.Result()
+		}
+	}
 	return sdk.Result{}
 }
@@ -7,9 +7,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	abci "github.com/tendermint/abci/types"
 	crypto "github.com/tendermint/go-crypto"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 //______________________________________________________________________
@@ -20,6 +21,7 @@ func newTestMsgDeclareCandidacy(address sdk.Address, pubKey crypto.PubKey, amt i
 		CandidateAddr: address,
 		Bond:          sdk.Coin{"steak", amt},
 		PubKey:        pubKey,
+		Commission:    NewCommission(sdk.ZeroRat(), sdk.ZeroRat(), sdk.ZeroRat()),
 	}
 }
 
@@ -54,6 +56,54 @@ func TestDuplicatesMsgDeclareCandidacy(t *testing.T) {
 	msgDeclareCandidacy.PubKey = pks[1]
 	got = handleMsgDeclareCandidacy(ctx, msgDeclareCandidacy, keeper)
 	assert.False(t, got.IsOK(), "%v", got)
+
+	// nor can two candidates share a validator pubkey
+	msgDeclareCandidacy = newTestMsgDeclareCandidacy(addrs[1], pk, 10)
+	got = handleMsgDeclareCandidacy(ctx, msgDeclareCandidacy, keeper)
+	assert.False(t, got.IsOK(), "%v", got)
+}
+
+func TestEditCandidacyCommission(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 1000)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+
+	msgDeclareCandidacy := newTestMsgDeclareCandidacy(addrs[0], pks[0], 10)
+	msgDeclareCandidacy.Description = NewDescription("moniker", "", "", "")
+	msgDeclareCandidacy.Commission = NewCommission(sdk.NewRat(10, 100), sdk.NewRat(20, 100), sdk.NewRat(5, 100))
+	got := handleMsgDeclareCandidacy(ctx, msgDeclareCandidacy, keeper)
+	require.True(t, got.IsOK(), "%v", got)
+	candidate, _ := keeper.GetCandidate(ctx, addrs[0])
+	assert.Equal(t, int64(1000), candidate.Commission.UpdateTime)
+
+	// the rate can't change within a day of the candidacy, the msg points to rate
+	rate := sdk.NewRat(12, 100)
+	msgEdit := NewMsgEditCandidacy(addrs[0], Description{}, &rate)
+	got = handleMsgEditCandidacy(ctx, msgEdit, keeper)
+	assert.False(t, got.IsOK(), "%v", got)
+
+	// nor by more than the max change rate, nor above the max rate
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + CommissionChangePeriod})
+	rate = sdk.NewRat(16, 100)
+	got = handleMsgEditCandidacy(ctx, msgEdit, keeper)
+	assert.False(t, got.IsOK(), "%v", got)
+
+	rate = sdk.NewRat(12, 100)
+	got = handleMsgEditCandidacy(ctx, msgEdit, keeper)
+	require.True(t, got.IsOK(), "%v", got)
+	candidate, _ = keeper.GetCandidate(ctx, addrs[0])
+	assert.True(t, candidate.Commission.Rate.Equal(sdk.NewRat(12, 100)))
+	assert.Equal(t, int64(1000+CommissionChangePeriod), candidate.Commission.UpdateTime)
+	assert.Equal(t, "moniker", candidate.Description.Moniker)
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + 2*CommissionChangePeriod})
+	rate = sdk.NewRat(17, 100)
+	got = handleMsgEditCandidacy(ctx, msgEdit, keeper)
+	require.True(t, got.IsOK(), "%v", got)
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + 3*CommissionChangePeriod})
+	rate = sdk.NewRat(21, 100)
+	got = handleMsgEditCandidacy(ctx, msgEdit, keeper)
+	assert.False(t, got.IsOK(), "%v", got)
 }
 
 func TestIncrementsMsgDelegate(t *testing.T) {
@@ -147,12 +197,20 @@ func TestIncrementsMsgUnbond(t *testing.T) {
 		bond, found := keeper.GetDelegatorBond(ctx, delegatorAddr, candidateAddr)
 		require.True(t, found)
 
+		ubd, found := keeper.GetUnbondingDelegation(ctx, delegatorAddr, candidateAddr)
+		require.True(t, found)
+
 		expBond := initBond - int64(i+1)*unbondShares
 		expLiabilities := 2*initBond - int64(i+1)*unbondShares
-		expDelegatorAcc := initBond - expBond
+		expUnbonding := initBond - expBond
+		expDelegatorAcc := int64(0) // the coins are returned after the unbonding period
 
 		gotBond := bond.Shares.Evaluate()
 		gotLiabilities := candidate.Liabilities.Evaluate()
+		gotUnbonding := int64(0)
+		for _, entry := range ubd.Entries {
+			gotUnbonding += entry.Balance
+		}
 		gotDelegatorAcc := accMapper.GetAccount(ctx, delegatorAddr).GetCoins().AmountOf(params.BondDenom)
 
 		require.Equal(t, expBond, gotBond,
@@ -161,11 +219,22 @@ func TestIncrementsMsgUnbond(t *testing.T) {
 		require.Equal(t, expLiabilities, gotLiabilities,
 			"i: %v\nexpLiabilities: %v\ngotLiabilities: %v\ncandidate: %v\nbond: %v\n",
 			i, expLiabilities, gotLiabilities, candidate, bond)
+		require.Equal(t, expUnbonding, gotUnbonding,
+			"i: %v\nexpUnbonding: %v\ngotUnbonding: %v\ncandidate: %v\nbond: %v\n",
+			i, expUnbonding, gotUnbonding, candidate, bond)
 		require.Equal(t, expDelegatorAcc, gotDelegatorAcc,
 			"i: %v\nexpDelegatorAcc: %v\ngotDelegatorAcc: %v\ncandidate: %v\nbond: %v\n",
 			i, expDelegatorAcc, gotDelegatorAcc, candidate, bond)
 	}
 
+	// the coins are returned once the unbonding period is over
+	ctx = ctx.WithBlockHeader(abci.Header{Time: params.UnbondingTime})
+	keeper.completeMatured(ctx)
+	gotDelegatorAcc := accMapper.GetAccount(ctx, delegatorAddr).GetCoins().AmountOf(params.BondDenom)
+	assert.Equal(t, unbondShares*int64(numUnbonds), gotDelegatorAcc)
+	_, found = keeper.GetUnbondingDelegation(ctx, delegatorAddr, candidateAddr)
+	assert.False(t, found)
+
 	// these are more than we have bonded now
 	errorCases := []int64{
 		//1<<64 - 1, // more than int64
@@ -211,7 +280,7 @@ func TestMultipleMsgDeclareCandidacy(t *testing.T) {
 		require.True(t, got.IsOK(), "expected msg %d to be ok, got %v", i, got)
 
 		//Check that the account is bonded
-		candidates := keeper.GetCandidates(ctx, 100)
+		candidates := keeper.getAllCandidates(ctx)
 		require.Equal(t, (i + 1), len(candidates))
 		val := candidates[i]
 		balanceExpd := initBond - 10
@@ -230,17 +299,26 @@ func TestMultipleMsgDeclareCandidacy(t *testing.T) {
 		require.True(t, got.IsOK(), "expected msg %d to be ok, got %v", i, got)
 
 		//Check that the account is unbonded
-		candidates := keeper.GetCandidates(ctx, 100)
+		candidates := keeper.getAllCandidates(ctx)
 		require.Equal(t, len(candidateAddrs)-(i+1), len(candidates),
 			"expected %d candidates got %d", len(candidateAddrs)-(i+1), len(candidates))
 
 		_, found = keeper.GetCandidate(ctx, candidateAddr)
 		require.False(t, found)
 
-		expBalance := initBond
+		expBalance := initBond - 10
 		gotBalance := accMapper.GetAccount(ctx, candidatePre.Address).GetCoins().AmountOf(params.BondDenom)
 		require.Equal(t, expBalance, gotBalance, "expected account to have %d, got %d", expBalance, gotBalance)
 	}
+
+	// the bonds are returned after the unbonding period
+	ctx = ctx.WithBlockHeader(abci.Header{Time: params.UnbondingTime})
+	keeper.completeMatured(ctx)
+	for _, candidateAddr := range candidateAddrs {
+		expBalance := initBond
+		gotBalance := accMapper.GetAccount(ctx, candidateAddr).GetCoins().AmountOf(params.BondDenom)
+		require.Equal(t, expBalance, gotBalance, "expected account to have %d, got %d", expBalance, gotBalance)
+	}
 }
 
 func TestMultipleMsgDelegate(t *testing.T) {
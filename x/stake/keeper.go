@@ -2,18 +2,20 @@ package stake
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/bank"
 	abci "github.com/tendermint/abci/types"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/bank"
 )
 
 // keeper of the staking store
 type Keeper struct {
-	storeKey   sdk.StoreKey
-	cdc        *wire.Codec
-	coinKeeper bank.Keeper
+	storeKey     sdk.StoreKey
+	cdc          *wire.Codec
+	supplyKeeper bank.SupplyKeeper
 
 	// caches
 	pool   Pool
@@ -23,12 +25,12 @@ type Keeper struct {
 	codespace sdk.CodespaceType
 }
 
-func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper, codespace sdk.CodespaceType) Keeper {
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, sk bank.SupplyKeeper, codespace sdk.CodespaceType) Keeper {
 	keeper := Keeper{
-		storeKey:   key,
-		cdc:        cdc,
-		coinKeeper: ck,
-		codespace:  codespace,
+		storeKey:     key,
+		cdc:          cdc,
+		supplyKeeper: sk,
+		codespace:    codespace,
 	}
 	return keeper
 }
@@ -74,28 +76,41 @@ func (k Keeper) GetCandidate(ctx sdk.Context, addr sdk.Address) (candidate Candi
 	return candidate, true
 }
 
-// Get the set of all candidates, retrieve a maxRetrieve number of records
-func (k Keeper) GetCandidates(ctx sdk.Context, maxRetrieve int16) (candidates Candidates) {
-	store := ctx.KVStore(k.storeKey)
-	iterator := store.Iterator(subspace(CandidatesKey))
-
-	candidates = make([]Candidate, maxRetrieve)
-	i := 0
-	for ; ; i++ {
-		if !iterator.Valid() || i > int(maxRetrieve-1) {
-			iterator.Close()
-			break
-		}
-		bz := iterator.Value()
+// GetCandidates returns a page of at most limit candidates, in address order
+// from the start address on, and the address the next page starts at, nil
+// after the last page. A nil start is the first page, a zero limit no limit.
+func (k Keeper) GetCandidates(ctx sdk.Context, start sdk.Address, limit int) (candidates Candidates, next sdk.Address) {
+	next = k.iteratePage(ctx, CandidatesKey, start, limit, func(value []byte) {
 		var candidate Candidate
-		err := k.cdc.UnmarshalJSON(bz, &candidate)
+		err := k.cdc.UnmarshalJSON(value, &candidate)
 		if err != nil {
 			panic(err)
 		}
-		candidates[i] = candidate
-		iterator.Next()
+		candidates = append(candidates, candidate)
+	})
+	return candidates, next
+}
+
+// load all candidates
+func (k Keeper) getAllCandidates(ctx sdk.Context) Candidates {
+	candidates, _ := k.GetCandidates(ctx, nil, 0)
+	return candidates
+}
+
+// iterate a page of at most limit values of the subspace of the prefix, from
+// the key of the prefix and start on, and return the start of the next page
+func (k Keeper) iteratePage(ctx sdk.Context, prefix []byte, start sdk.Address, limit int, process func(value []byte)) (next sdk.Address) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(append(append([]byte{}, prefix...), start...), sdk.PrefixEndBytes(prefix))
+	defer iterator.Close()
+	for i := 0; iterator.Valid(); iterator.Next() {
+		if limit > 0 && i == limit {
+			return sdk.Address(iterator.Key()[len(prefix):])
+		}
+		process(iterator.Value())
+		i++
 	}
-	return candidates[:i] // trim
+	return nil
 }
 
 func (k Keeper) setCandidate(ctx sdk.Context, candidate Candidate) {
@@ -117,9 +132,28 @@ func (k Keeper) setCandidate(ctx sdk.Context, candidate Candidate) {
 		panic(err)
 	}
 	store.Set(GetCandidateKey(candidate.Address), bz)
+	store.Set(GetCandidateByPubKeyKey(candidate.PubKey.Address()), address)
 
 	// if the voting power is the same no need to update any of the other indexes
-	if oldFound && oldCandidate.Assets.Equal(candidate.Assets) {
+	if oldFound && oldCandidate.Assets.Equal(candidate.Assets) && oldCandidate.Jailed == candidate.Jailed {
+		return
+	}
+
+	// jailed candidates are kept out of the power index, if the candidate
+	// was a validator add it with zero power to the validator updates
+	if candidate.Jailed {
+		if !oldFound {
+			return
+		}
+		wasValidator := k.isNewValidator(ctx, store, address) || store.Get(GetRecentValidatorKey(address)) != nil
+		store.Delete(GetValidatorKey(address, oldCandidate.Assets, oldCandidate.ValidatorBondHeight, oldCandidate.ValidatorBondCounter, k.cdc))
+		if wasValidator {
+			bz, err = k.cdc.MarshalJSON(candidate.validator().abciValidatorZero(k.cdc))
+			if err != nil {
+				panic(err)
+			}
+			store.Set(GetAccUpdateValidatorKey(address), bz)
+		}
 		return
 	}
 
@@ -193,6 +227,7 @@ func (k Keeper) removeCandidate(ctx sdk.Context, address sdk.Address) {
 	// delete the old candidate record
 	store := ctx.KVStore(k.storeKey)
 	store.Delete(GetCandidateKey(address))
+	store.Delete(GetCandidateByPubKeyKey(candidate.PubKey.Address()))
 	store.Delete(GetValidatorKey(address, candidate.Assets, candidate.ValidatorBondHeight, candidate.ValidatorBondCounter, k.cdc))
 
 	// delete from recent and power weighted validator groups if the validator
@@ -208,6 +243,64 @@ func (k Keeper) removeCandidate(ctx sdk.Context, address sdk.Address) {
 	store.Delete(GetRecentValidatorKey(address))
 }
 
+// get the candidate whose consensus pubkey has the provided address
+func (k Keeper) GetCandidateByPubKeyAddress(ctx sdk.Context, pubKeyAddr sdk.Address) (candidate Candidate, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	address := store.Get(GetCandidateByPubKeyKey(pubKeyAddr))
+	if address == nil {
+		return candidate, false
+	}
+	return k.GetCandidate(ctx, address)
+}
+
+// slash a fraction of the tokens backing a candidate, and of the tokens
+// unbonding or redelegated away from it since the infraction height,
+// the tokens are burned
+func (k Keeper) Slash(ctx sdk.Context, candidateAddr sdk.Address, infractionHeight int64, fraction sdk.Rat) sdk.Error {
+	candidate, found := k.GetCandidate(ctx, candidateAddr)
+	if !found {
+		return ErrBadCandidateAddr(k.codespace)
+	}
+	pool := k.GetPool(ctx)
+	pool, candidate, burned := pool.candidateSlash(candidate, fraction)
+	k.setPool(ctx, pool)
+	k.setCandidate(ctx, candidate)
+	k.burnTokens(ctx, burned)
+	k.slashUnbondingAndRedelegations(ctx, candidateAddr, infractionHeight, fraction)
+	return nil
+}
+
+// burn slashed tokens held by the pool, decreasing the supply of the bond denom
+func (k Keeper) burnTokens(ctx sdk.Context, amount int64) {
+	if amount == 0 {
+		return
+	}
+	err := k.supplyKeeper.BurnEscrow(ctx, MsgType, sdk.Coins{{k.GetParams(ctx).BondDenom, amount}})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// remove a candidate from the validator set until it is unjailed
+func (k Keeper) Jail(ctx sdk.Context, candidateAddr sdk.Address) sdk.Error {
+	return k.setJailed(ctx, candidateAddr, true)
+}
+
+// allow a jailed candidate back into the validator set
+func (k Keeper) Unjail(ctx sdk.Context, candidateAddr sdk.Address) sdk.Error {
+	return k.setJailed(ctx, candidateAddr, false)
+}
+
+func (k Keeper) setJailed(ctx sdk.Context, candidateAddr sdk.Address, jailed bool) sdk.Error {
+	candidate, found := k.GetCandidate(ctx, candidateAddr)
+	if !found {
+		return ErrBadCandidateAddr(k.codespace)
+	}
+	candidate.Jailed = jailed
+	k.setCandidate(ctx, candidate)
+	return nil
+}
+
 //___________________________________________________________________________
 
 // Get the validator set from the candidates. The correct subset is retrieved
@@ -372,52 +465,53 @@ func (k Keeper) GetDelegatorBond(ctx sdk.Context,
 }
 
 // load all bonds
-func (k Keeper) getBonds(ctx sdk.Context, maxRetrieve int16) (bonds []DelegatorBond) {
+func (k Keeper) getBonds(ctx sdk.Context) (bonds []DelegatorBond) {
 	store := ctx.KVStore(k.storeKey)
 	iterator := store.Iterator(subspace(DelegatorBondKeyPrefix))
-
-	bonds = make([]DelegatorBond, maxRetrieve)
-	i := 0
-	for ; ; i++ {
-		if !iterator.Valid() || i > int(maxRetrieve-1) {
-			iterator.Close()
-			break
-		}
-		bondBytes := iterator.Value()
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
 		var bond DelegatorBond
-		err := k.cdc.UnmarshalJSON(bondBytes, &bond)
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &bond)
 		if err != nil {
 			panic(err)
 		}
-		bonds[i] = bond
-		iterator.Next()
+		bonds = append(bonds, bond)
 	}
-	return bonds[:i] // trim
+	return bonds
 }
 
-// load all bonds of a delegator
-func (k Keeper) GetDelegatorBonds(ctx sdk.Context, delegator sdk.Address, maxRetrieve int16) (bonds []DelegatorBond) {
-	store := ctx.KVStore(k.storeKey)
-	delegatorPrefixKey := GetDelegatorBondsKey(delegator, k.cdc)
-	iterator := store.Iterator(subspace(delegatorPrefixKey)) //smallest to largest
-
-	bonds = make([]DelegatorBond, maxRetrieve)
-	i := 0
-	for ; ; i++ {
-		if !iterator.Valid() || i > int(maxRetrieve-1) {
-			iterator.Close()
-			break
+// GetDelegatorBonds returns a page of at most limit bonds of a delegator, in
+// candidate address order from the start candidate on, and the candidate the
+// next page starts at, nil after the last page. A nil start is the first
+// page, a zero limit no limit.
+func (k Keeper) GetDelegatorBonds(ctx sdk.Context, delegator, start sdk.Address, limit int) (bonds []DelegatorBond, next sdk.Address) {
+	prefix := GetDelegatorBondsKey(delegator, k.cdc)
+	next = k.iteratePage(ctx, prefix, start, limit, func(value []byte) {
+		var bond DelegatorBond
+		err := k.cdc.UnmarshalJSON(value, &bond)
+		if err != nil {
+			panic(err)
 		}
-		bondBytes := iterator.Value()
+		bonds = append(bonds, bond)
+	})
+	return bonds, next
+}
+
+// iterate through the bonds of a delegator until process returns true
+func (k Keeper) IterateDelegatorBonds(ctx sdk.Context, delegator sdk.Address, process func(bond DelegatorBond) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(subspace(GetDelegatorBondsKey(delegator, k.cdc)))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
 		var bond DelegatorBond
-		err := k.cdc.UnmarshalJSON(bondBytes, &bond)
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &bond)
 		if err != nil {
 			panic(err)
 		}
-		bonds[i] = bond
-		iterator.Next()
+		if process(bond) {
+			return
+		}
 	}
-	return bonds[:i] // trim
 }
 
 func (k Keeper) setDelegatorBond(ctx sdk.Context, bond DelegatorBond) {
@@ -464,6 +558,41 @@ func (k Keeper) setParams(ctx sdk.Context, params Params) {
 	k.params = Params{} // clear the cache
 }
 
+// SetParam sets a single param from its JSON name and value, the bond denom
+// can't be changed once tokens are bonded
+func (k Keeper) SetParam(ctx sdk.Context, key, value string) sdk.Error {
+	if key == "bond_denom" {
+		return ErrBadParam(k.codespace, "the bond denom cannot be changed")
+	}
+	params := k.GetParams(ctx)
+	bz, err := k.cdc.MarshalJSON(params)
+	if err != nil {
+		panic(err)
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(bz, &fields)
+	if err != nil {
+		panic(err)
+	}
+	if _, ok := fields[key]; !ok {
+		return ErrBadParam(k.codespace, fmt.Sprintf("unknown stake param %s", key))
+	}
+	fields[key] = json.RawMessage(value)
+	bz, err = json.Marshal(fields)
+	if err != nil {
+		return ErrBadParam(k.codespace, fmt.Sprintf("invalid value for stake param %s", key))
+	}
+	err = k.cdc.UnmarshalJSON(bz, &params)
+	if err != nil {
+		return ErrBadParam(k.codespace, fmt.Sprintf("invalid value for stake param %s: %v", key, err))
+	}
+	if params.MaxValidators == 0 || params.UnbondingTime < 0 {
+		return ErrBadParam(k.codespace, "max validators must be positive and the unbonding time not negative")
+	}
+	k.setParams(ctx, params)
+	return nil
+}
+
 //_______________________________________________________________________
 
 // load/save the pool
@@ -484,6 +613,12 @@ func (k Keeper) GetPool(ctx sdk.Context) (pool Pool) {
 	return
 }
 
+// GetTotalSupply - the supply of the bond denom, recorded by the supply keeper
+// so that the minted and burned tokens of every module are accounted for
+func (k Keeper) GetTotalSupply(ctx sdk.Context) int64 {
+	return k.supplyKeeper.GetSupply(ctx, k.GetParams(ctx).BondDenom)
+}
+
 func (k Keeper) setPool(ctx sdk.Context, p Pool) {
 	store := ctx.KVStore(k.storeKey)
 	b, err := k.cdc.MarshalJSON(p)
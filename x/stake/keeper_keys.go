@@ -3,13 +3,13 @@ package stake
 import (
 	"encoding/binary"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 )
 
 // TODO remove some of these prefixes once have working multistore
 
-//nolint
+// nolint
 var (
 	// Keys for store prefixes
 	ParamKey               = []byte{0x00} // key for global parameters relating to staking
@@ -24,6 +24,18 @@ var (
 	DelegatorBondKeyPrefix = []byte{0x07} // prefix for each key to a delegator's bond
 
 	CounterKey = []byte{0x08} // key for block-local tx index
+
+	FeePoolKey          = []byte{0x09} // key for the fees collected and not yet distributed
+	ValidatorRewardsKey = []byte{0x0A} // prefix for each key to the rewards of a candidate
+
+	CandidatesByPubKeyKey = []byte{0x0B} // prefix for each key to a candidate address by consensus pubkey address
+
+	UnbondingDelegationKey            = []byte{0x0C} // prefix for each key to an unbonding delegation
+	UnbondingDelegationByCandidateKey = []byte{0x0D} // prefix for each key to an unbonding delegation, by candidate
+	UnbondingQueueKey                 = []byte{0x0E} // prefix for the queue of unbonding delegations, by completion time
+	RedelegationKey                   = []byte{0x0F} // prefix for each key to a redelegation
+	RedelegationBySrcKey              = []byte{0x10} // prefix for each key to a redelegation, by source candidate
+	RedelegationQueueKey              = []byte{0x11} // prefix for the queue of redelegations, by completion time
 )
 
 const maxDigitsForAccount = 12 // ~220,000,000 atoms created at launch
@@ -33,6 +45,11 @@ func GetCandidateKey(addr sdk.Address) []byte {
 	return append(CandidatesKey, addr.Bytes()...)
 }
 
+// get the key for the candidate address with the address of its consensus pubkey
+func GetCandidateByPubKeyKey(pubKeyAddr sdk.Address) []byte {
+	return append(CandidatesByPubKeyKey, pubKeyAddr.Bytes()...)
+}
+
 // get the key for the validator used in the power-store
 func GetValidatorKey(addr sdk.Address, power sdk.Rat, height int64, counter int16, cdc *wire.Codec) []byte {
 	powerBytes := []byte(power.ToLeftPadded(maxDigitsForAccount)) // power big-endian (more powerful validators first)
@@ -63,6 +80,11 @@ func GetToKickOutValidatorKey(addr sdk.Address) []byte {
 	return append(ToKickOutValidatorsKey, addr.Bytes()...)
 }
 
+// get the key for the rewards of a candidate
+func GetValidatorRewardsKey(addr sdk.Address) []byte {
+	return append(ValidatorRewardsKey, addr.Bytes()...)
+}
+
 // get the key for delegator bond with candidate
 func GetDelegatorBondKey(delegatorAddr, candidateAddr sdk.Address, cdc *wire.Codec) []byte {
 	return append(GetDelegatorBondsKey(delegatorAddr, cdc), candidateAddr.Bytes()...)
@@ -76,3 +98,51 @@ func GetDelegatorBondsKey(delegatorAddr sdk.Address, cdc *wire.Codec) []byte {
 	}
 	return append(DelegatorBondKeyPrefix, res...)
 }
+
+// get the key for the unbonding delegation of the delegator from the candidate
+func GetUnbondingDelegationKey(delegatorAddr, candidateAddr sdk.Address) []byte {
+	return append(GetUnbondingDelegationsKey(delegatorAddr), candidateAddr.Bytes()...)
+}
+
+// get the prefix for the unbonding delegations of a delegator
+func GetUnbondingDelegationsKey(delegatorAddr sdk.Address) []byte {
+	return append(UnbondingDelegationKey, delegatorAddr.Bytes()...)
+}
+
+// get the index key for the unbonding delegation of the delegator from the candidate
+func GetUnbondingDelegationByCandidateKey(candidateAddr, delegatorAddr sdk.Address) []byte {
+	return append(GetUnbondingDelegationsByCandidateKey(candidateAddr), delegatorAddr.Bytes()...)
+}
+
+// get the index prefix for the unbonding delegations from a candidate
+func GetUnbondingDelegationsByCandidateKey(candidateAddr sdk.Address) []byte {
+	return append(UnbondingDelegationByCandidateKey, candidateAddr.Bytes()...)
+}
+
+// get the key for the redelegation of the delegator from the source to the destination candidate
+func GetRedelegationKey(delegatorAddr, srcAddr, dstAddr sdk.Address) []byte {
+	return append(GetRedelegationsKey(delegatorAddr), append(srcAddr.Bytes(), dstAddr.Bytes()...)...)
+}
+
+// get the prefix for the redelegations of a delegator
+func GetRedelegationsKey(delegatorAddr sdk.Address) []byte {
+	return append(RedelegationKey, delegatorAddr.Bytes()...)
+}
+
+// get the index key for the redelegation of the delegator from the source to the destination candidate
+func GetRedelegationBySrcKey(srcAddr, delegatorAddr, dstAddr sdk.Address) []byte {
+	return append(GetRedelegationsBySrcKey(srcAddr), append(delegatorAddr.Bytes(), dstAddr.Bytes()...)...)
+}
+
+// get the index prefix for the redelegations from a source candidate
+func GetRedelegationsBySrcKey(srcAddr sdk.Address) []byte {
+	return append(RedelegationBySrcKey, srcAddr.Bytes()...)
+}
+
+// get the prefix of a queue for the entries completing at the time,
+// the big-endian time orders the queue by completion time
+func getQueueTimeKey(queueKey []byte, completionTime int64) []byte {
+	timeBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(timeBytes, uint64(completionTime))
+	return append(append([]byte{}, queueKey...), timeBytes...)
+}
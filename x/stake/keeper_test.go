@@ -3,7 +3,7 @@ package stake
 import (
 	"testing"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,7 +42,7 @@ func TestCandidate(t *testing.T) {
 	// check the empty keeper first
 	_, found := keeper.GetCandidate(ctx, addrVals[0])
 	assert.False(t, found)
-	resCands := keeper.GetCandidates(ctx, 100)
+	resCands := keeper.getAllCandidates(ctx)
 	assert.Zero(t, len(resCands))
 
 	// set and retrieve a record
@@ -59,7 +59,7 @@ func TestCandidate(t *testing.T) {
 	assert.True(t, candidatesEqual(candidates[0], resCand))
 
 	// also test that the address has been added to address list
-	resCands = keeper.GetCandidates(ctx, 100)
+	resCands = keeper.getAllCandidates(ctx)
 	require.Equal(t, 1, len(resCands))
 	assert.Equal(t, addrVals[0], resCands[0].Address)
 
@@ -72,12 +72,22 @@ func TestCandidate(t *testing.T) {
 	resCand, found = keeper.GetCandidate(ctx, addrVals[2])
 	require.True(t, found)
 	assert.True(t, candidatesEqual(candidates[2], resCand), "%v \n %v", resCand, candidates[2])
-	resCands = keeper.GetCandidates(ctx, 100)
+	resCands = keeper.getAllCandidates(ctx)
 	require.Equal(t, 3, len(resCands))
 	assert.True(t, candidatesEqual(candidates[0], resCands[0]), "%v \n %v", resCands[0], candidates[0])
 	assert.True(t, candidatesEqual(candidates[1], resCands[1]), "%v \n %v", resCands[1], candidates[1])
 	assert.True(t, candidatesEqual(candidates[2], resCands[2]), "%v \n %v", resCands[2], candidates[2])
 
+	// page through the candidates
+	resCands, next := keeper.GetCandidates(ctx, nil, 2)
+	require.Equal(t, 2, len(resCands))
+	assert.Equal(t, addrVals[0], resCands[0].Address)
+	assert.Equal(t, addrVals[2], next)
+	resCands, next = keeper.GetCandidates(ctx, next, 2)
+	require.Equal(t, 1, len(resCands))
+	assert.Equal(t, addrVals[2], resCands[0].Address)
+	assert.Nil(t, next)
+
 	// remove a record
 	keeper.removeCandidate(ctx, candidates[1].Address)
 	_, found = keeper.GetCandidate(ctx, addrVals[1])
@@ -129,11 +139,11 @@ func TestBond(t *testing.T) {
 	// add some more records
 	keeper.setCandidate(ctx, candidates[1])
 	keeper.setCandidate(ctx, candidates[2])
-	bond1to2 := DelegatorBond{addrDels[0], addrVals[1], sdk.NewRat(9), 0}
-	bond1to3 := DelegatorBond{addrDels[0], addrVals[2], sdk.NewRat(9), 1}
-	bond2to1 := DelegatorBond{addrDels[1], addrVals[0], sdk.NewRat(9), 2}
-	bond2to2 := DelegatorBond{addrDels[1], addrVals[1], sdk.NewRat(9), 3}
-	bond2to3 := DelegatorBond{addrDels[1], addrVals[2], sdk.NewRat(9), 4}
+	bond1to2 := DelegatorBond{addrDels[0], addrVals[1], sdk.NewRat(9), 0, nil}
+	bond1to3 := DelegatorBond{addrDels[0], addrVals[2], sdk.NewRat(9), 1, nil}
+	bond2to1 := DelegatorBond{addrDels[1], addrVals[0], sdk.NewRat(9), 2, nil}
+	bond2to2 := DelegatorBond{addrDels[1], addrVals[1], sdk.NewRat(9), 3, nil}
+	bond2to3 := DelegatorBond{addrDels[1], addrVals[2], sdk.NewRat(9), 4, nil}
 	keeper.setDelegatorBond(ctx, bond1to2)
 	keeper.setDelegatorBond(ctx, bond1to3)
 	keeper.setDelegatorBond(ctx, bond2to1)
@@ -141,21 +151,27 @@ func TestBond(t *testing.T) {
 	keeper.setDelegatorBond(ctx, bond2to3)
 
 	// test all bond retrieve capabilities
-	resBonds := keeper.GetDelegatorBonds(ctx, addrDels[0], 5)
+	resBonds, _ := keeper.GetDelegatorBonds(ctx, addrDels[0], nil, 0)
 	require.Equal(t, 3, len(resBonds))
 	assert.True(t, bondsEqual(bond1to1, resBonds[0]))
 	assert.True(t, bondsEqual(bond1to2, resBonds[1]))
 	assert.True(t, bondsEqual(bond1to3, resBonds[2]))
-	resBonds = keeper.GetDelegatorBonds(ctx, addrDels[0], 3)
+	resBonds, next := keeper.GetDelegatorBonds(ctx, addrDels[0], nil, 3)
 	require.Equal(t, 3, len(resBonds))
-	resBonds = keeper.GetDelegatorBonds(ctx, addrDels[0], 2)
+	assert.Nil(t, next)
+	resBonds, next = keeper.GetDelegatorBonds(ctx, addrDels[0], nil, 2)
 	require.Equal(t, 2, len(resBonds))
-	resBonds = keeper.GetDelegatorBonds(ctx, addrDels[1], 5)
+	assert.Equal(t, addrVals[2], next)
+	resBonds, next = keeper.GetDelegatorBonds(ctx, addrDels[0], next, 2)
+	require.Equal(t, 1, len(resBonds))
+	assert.True(t, bondsEqual(bond1to3, resBonds[0]))
+	assert.Nil(t, next)
+	resBonds, _ = keeper.GetDelegatorBonds(ctx, addrDels[1], nil, 0)
 	require.Equal(t, 3, len(resBonds))
 	assert.True(t, bondsEqual(bond2to1, resBonds[0]))
 	assert.True(t, bondsEqual(bond2to2, resBonds[1]))
 	assert.True(t, bondsEqual(bond2to3, resBonds[2]))
-	allBonds := keeper.getBonds(ctx, 1000)
+	allBonds := keeper.getBonds(ctx)
 	require.Equal(t, 6, len(allBonds))
 	assert.True(t, bondsEqual(bond1to1, allBonds[0]))
 	assert.True(t, bondsEqual(bond1to2, allBonds[1]))
@@ -168,7 +184,7 @@ func TestBond(t *testing.T) {
 	keeper.removeDelegatorBond(ctx, bond2to3)
 	_, found = keeper.GetDelegatorBond(ctx, addrDels[1], addrVals[2])
 	assert.False(t, found)
-	resBonds = keeper.GetDelegatorBonds(ctx, addrDels[1], 5)
+	resBonds, _ = keeper.GetDelegatorBonds(ctx, addrDels[1], nil, 0)
 	require.Equal(t, 2, len(resBonds))
 	assert.True(t, bondsEqual(bond2to1, resBonds[0]))
 	assert.True(t, bondsEqual(bond2to2, resBonds[1]))
@@ -180,7 +196,7 @@ func TestBond(t *testing.T) {
 	assert.False(t, found)
 	_, found = keeper.GetDelegatorBond(ctx, addrDels[1], addrVals[1])
 	assert.False(t, found)
-	resBonds = keeper.GetDelegatorBonds(ctx, addrDels[1], 5)
+	resBonds, _ = keeper.GetDelegatorBonds(ctx, addrDels[1], nil, 0)
 	require.Equal(t, 0, len(resBonds))
 }
 
@@ -428,7 +444,7 @@ func TestGetAccUpdateValidators(t *testing.T) {
 	//  candidate set: {} -> {c1, c3}
 	//  validator set: {} -> {c1, c3}
 	//  accUpdate set: {} -> {c1, c3}
-	assert.Equal(t, 0, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 0, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 0, len(keeper.GetValidators(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
@@ -439,7 +455,7 @@ func TestGetAccUpdateValidators(t *testing.T) {
 	require.Equal(t, 2, len(vals))
 	acc := keeper.getAccUpdateValidators(ctx)
 	require.Equal(t, 2, len(acc))
-	candidates := keeper.GetCandidates(ctx, 5)
+	candidates := keeper.getAllCandidates(ctx)
 	require.Equal(t, 2, len(candidates))
 	assert.Equal(t, candidates[0].validator().abciValidator(keeper.cdc), acc[0])
 	assert.Equal(t, candidates[1].validator().abciValidator(keeper.cdc), acc[1])
@@ -450,26 +466,26 @@ func TestGetAccUpdateValidators(t *testing.T) {
 	//  candidate set: {c1, c3} -> {c1, c3}
 	//  accUpdate set: {} -> {}
 	keeper.clearAccUpdateValidators(ctx)
-	assert.Equal(t, 2, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 2, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
 	keeper.setCandidate(ctx, candidates[0])
 	keeper.setCandidate(ctx, candidates[1])
 
-	require.Equal(t, 2, len(keeper.GetCandidates(ctx, 5)))
+	require.Equal(t, 2, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
 	// test single value change
 	//  candidate set: {c1, c3} -> {c1', c3}
 	//  accUpdate set: {} -> {c1'}
 	keeper.clearAccUpdateValidators(ctx)
-	assert.Equal(t, 2, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 2, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
 	candidates[0].Assets = sdk.NewRat(600)
 	keeper.setCandidate(ctx, candidates[0])
 
-	candidates = keeper.GetCandidates(ctx, 5)
+	candidates = keeper.getAllCandidates(ctx)
 	require.Equal(t, 2, len(candidates))
 	assert.True(t, candidates[0].Assets.Equal(sdk.NewRat(600)))
 	acc = keeper.getAccUpdateValidators(ctx)
@@ -480,7 +496,7 @@ func TestGetAccUpdateValidators(t *testing.T) {
 	//  candidate set: {c1, c3} -> {c1', c3'}
 	//  accUpdate set: {c1, c3} -> {c1', c3'}
 	keeper.clearAccUpdateValidators(ctx)
-	assert.Equal(t, 2, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 2, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
 	candidates[0].Assets = sdk.NewRat(200)
@@ -490,7 +506,7 @@ func TestGetAccUpdateValidators(t *testing.T) {
 
 	acc = keeper.getAccUpdateValidators(ctx)
 	require.Equal(t, 2, len(acc))
-	candidates = keeper.GetCandidates(ctx, 5)
+	candidates = keeper.getAllCandidates(ctx)
 	require.Equal(t, 2, len(candidates))
 	require.Equal(t, candidates[0].validator().abciValidator(keeper.cdc), acc[0])
 	require.Equal(t, candidates[1].validator().abciValidator(keeper.cdc), acc[1])
@@ -499,13 +515,13 @@ func TestGetAccUpdateValidators(t *testing.T) {
 	//  candidate set: {c1, c3} -> {c0, c1, c3}
 	//  accUpdate set: {} -> {c0}
 	keeper.clearAccUpdateValidators(ctx)
-	assert.Equal(t, 2, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 2, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
 	keeper.setCandidate(ctx, candidatesIn[0])
 	acc = keeper.getAccUpdateValidators(ctx)
 	require.Equal(t, 1, len(acc))
-	candidates = keeper.GetCandidates(ctx, 5)
+	candidates = keeper.getAllCandidates(ctx)
 	require.Equal(t, 3, len(candidates))
 	assert.Equal(t, candidates[0].validator().abciValidator(keeper.cdc), acc[0])
 
@@ -513,13 +529,13 @@ func TestGetAccUpdateValidators(t *testing.T) {
 	//  candidate set: {c0, c1, c3} -> {c0, c1, c2, c3]
 	//  accUpdate set: {} -> {c2}
 	keeper.clearAccUpdateValidators(ctx)
-	assert.Equal(t, 3, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 3, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
 	keeper.setCandidate(ctx, candidatesIn[2])
 	acc = keeper.getAccUpdateValidators(ctx)
 	require.Equal(t, 1, len(acc))
-	candidates = keeper.GetCandidates(ctx, 5)
+	candidates = keeper.getAllCandidates(ctx)
 	require.Equal(t, 4, len(candidates))
 	assert.Equal(t, candidates[2].validator().abciValidator(keeper.cdc), acc[0])
 
@@ -528,13 +544,13 @@ func TestGetAccUpdateValidators(t *testing.T) {
 	//  validator set: {c0, c1, c2, c3} -> {c0, c1, c2, c3}
 	//  accUpdate set: {} -> {}
 	keeper.clearAccUpdateValidators(ctx)
-	assert.Equal(t, 4, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 4, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 4, len(keeper.GetValidators(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
 	keeper.setCandidate(ctx, candidatesIn[4])
 
-	assert.Equal(t, 5, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 5, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 4, len(keeper.GetValidators(ctx)))
 	require.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx))) // max validator number is 4
 
@@ -543,14 +559,14 @@ func TestGetAccUpdateValidators(t *testing.T) {
 	//  validator set: {c0, c1, c2, c3}     -> {c0, c1, c2, c3}
 	//  accUpdate set: {}     -> {}
 	keeper.clearAccUpdateValidators(ctx)
-	assert.Equal(t, 5, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 5, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 4, len(keeper.GetValidators(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
 	candidatesIn[4].Assets = sdk.NewRat(1)
 	keeper.setCandidate(ctx, candidatesIn[4])
 
-	assert.Equal(t, 5, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 5, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 4, len(keeper.GetValidators(ctx)))
 	require.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx))) // max validator number is 4
 
@@ -559,14 +575,14 @@ func TestGetAccUpdateValidators(t *testing.T) {
 	//  validator set: {c0, c1, c2, c3}     -> {c1, c2, c3, c4}
 	//  accUpdate set: {}     -> {c0, c4}
 	keeper.clearAccUpdateValidators(ctx)
-	assert.Equal(t, 5, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 5, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 4, len(keeper.GetValidators(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
 	candidatesIn[4].Assets = sdk.NewRat(1000)
 	keeper.setCandidate(ctx, candidatesIn[4])
 
-	candidates = keeper.GetCandidates(ctx, 5)
+	candidates = keeper.getAllCandidates(ctx)
 	require.Equal(t, 5, len(candidates))
 	vals = keeper.GetValidators(ctx)
 	require.Equal(t, 4, len(vals))
@@ -587,7 +603,7 @@ func TestGetAccUpdateValidators(t *testing.T) {
 	//  validator set: {c1, c2, c3, c4}  -> {}
 	//  accUpdate set: {} -> {c1, c2, c3, c4}
 	keeper.clearAccUpdateValidators(ctx)
-	assert.Equal(t, 5, len(keeper.GetCandidates(ctx, 5)))
+	assert.Equal(t, 5, len(keeper.getAllCandidates(ctx)))
 	assert.Equal(t, 4, len(keeper.GetValidators(ctx)))
 	assert.Equal(t, 0, len(keeper.getAccUpdateValidators(ctx)))
 
@@ -599,7 +615,7 @@ func TestGetAccUpdateValidators(t *testing.T) {
 
 	vals = keeper.GetValidators(ctx)
 	assert.Equal(t, 0, len(vals), "%v", vals)
-	candidates = keeper.GetCandidates(ctx, 5)
+	candidates = keeper.getAllCandidates(ctx)
 	require.Equal(t, 0, len(candidates))
 	acc = keeper.getAccUpdateValidators(ctx)
 	require.Equal(t, 4, len(acc))
@@ -680,7 +696,7 @@ func TestPool(t *testing.T) {
 	assert.Equal(t, expPool, resPool)
 
 	//modify a params, save, and retrieve
-	expPool.TotalSupply = 777
+	expPool.BondedPool = 777
 	keeper.setPool(ctx, expPool)
 	resPool = keeper.GetPool(ctx)
 	assert.Equal(t, expPool, resPool)
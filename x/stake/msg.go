@@ -1,9 +1,10 @@
 package stake
 
 import (
+	"bytes"
 	"encoding/json"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 	crypto "github.com/tendermint/go-crypto"
 )
 
@@ -17,7 +18,7 @@ const MsgType = "stake"
 const StakingToken = "steak"
 
 //Verify interface at compile time
-var _, _, _, _ sdk.Msg = &MsgDeclareCandidacy{}, &MsgEditCandidacy{}, &MsgDelegate{}, &MsgUnbond{}
+var _, _, _, _, _, _ sdk.Msg = &MsgDeclareCandidacy{}, &MsgEditCandidacy{}, &MsgDelegate{}, &MsgUnbond{}, &MsgRedelegate{}, &MsgWithdraw{}
 
 //______________________________________________________________________
 
@@ -27,15 +28,17 @@ type MsgDeclareCandidacy struct {
 	CandidateAddr sdk.Address   `json:"address"`
 	PubKey        crypto.PubKey `json:"pubkey"`
 	Bond          sdk.Coin      `json:"bond"`
+	Commission    Commission    `json:"commission"`
 }
 
 func NewMsgDeclareCandidacy(candidateAddr sdk.Address, pubkey crypto.PubKey,
-	bond sdk.Coin, description Description) MsgDeclareCandidacy {
+	bond sdk.Coin, description Description, commission Commission) MsgDeclareCandidacy {
 	return MsgDeclareCandidacy{
 		Description:   description,
 		CandidateAddr: candidateAddr,
 		PubKey:        pubkey,
 		Bond:          bond,
+		Commission:    commission,
 	}
 }
 
@@ -67,21 +70,24 @@ func (msg MsgDeclareCandidacy) ValidateBasic() sdk.Error {
 	if msg.Description == empty {
 		return newError(DefaultCodespace, CodeInvalidInput, "description must be included")
 	}
-	return nil
+	return msg.Commission.Validate(DefaultCodespace)
 }
 
 //______________________________________________________________________
 
-// MsgEditCandidacy - struct for editing a candidate
+// MsgEditCandidacy - struct for editing a candidate, the commission rate
+// is left unchanged when nil
 type MsgEditCandidacy struct {
 	Description
-	CandidateAddr sdk.Address `json:"address"`
+	CandidateAddr  sdk.Address `json:"address"`
+	CommissionRate *sdk.Rat    `json:"commission_rate,omitempty"`
 }
 
-func NewMsgEditCandidacy(candidateAddr sdk.Address, description Description) MsgEditCandidacy {
+func NewMsgEditCandidacy(candidateAddr sdk.Address, description Description, commissionRate *sdk.Rat) MsgEditCandidacy {
 	return MsgEditCandidacy{
-		Description:   description,
-		CandidateAddr: candidateAddr,
+		Description:    description,
+		CandidateAddr:  candidateAddr,
+		CommissionRate: commissionRate,
 	}
 }
 
@@ -104,9 +110,17 @@ func (msg MsgEditCandidacy) ValidateBasic() sdk.Error {
 		return ErrCandidateEmpty(DefaultCodespace)
 	}
 	empty := Description{}
-	if msg.Description == empty {
+	if msg.Description == empty && msg.CommissionRate == nil {
 		return newError(DefaultCodespace, CodeInvalidInput, "Transaction must include some information to modify")
 	}
+	if msg.CommissionRate != nil {
+		if msg.CommissionRate.LT(sdk.ZeroRat()) {
+			return ErrCommissionNegative(DefaultCodespace)
+		}
+		if msg.CommissionRate.GT(sdk.OneRat()) {
+			return ErrCommissionHuge(DefaultCodespace)
+		}
+	}
 	return nil
 }
 
@@ -206,3 +220,99 @@ func (msg MsgUnbond) ValidateBasic() sdk.Error {
 	}
 	return nil
 }
+
+//______________________________________________________________________
+
+// MsgRedelegate - struct for moving the shares of a bond from one candidate
+// to another without waiting for the unbonding period
+type MsgRedelegate struct {
+	DelegatorAddr    sdk.Address `json:"delegator"`
+	CandidateSrcAddr sdk.Address `json:"candidate_src"`
+	CandidateDstAddr sdk.Address `json:"candidate_dst"`
+	Shares           string      `json:"shares"`
+}
+
+func NewMsgRedelegate(delegatorAddr, candidateSrcAddr, candidateDstAddr sdk.Address, shares string) MsgRedelegate {
+	return MsgRedelegate{
+		DelegatorAddr:    delegatorAddr,
+		CandidateSrcAddr: candidateSrcAddr,
+		CandidateDstAddr: candidateDstAddr,
+		Shares:           shares,
+	}
+}
+
+//nolint
+func (msg MsgRedelegate) Type() string              { return MsgType }
+func (msg MsgRedelegate) GetSigners() []sdk.Address { return []sdk.Address{msg.DelegatorAddr} }
+
+// get the bytes for the message signer to sign on
+func (msg MsgRedelegate) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// quick validity check
+func (msg MsgRedelegate) ValidateBasic() sdk.Error {
+	if msg.DelegatorAddr == nil {
+		return ErrBadDelegatorAddr(DefaultCodespace)
+	}
+	if msg.CandidateSrcAddr == nil || msg.CandidateDstAddr == nil {
+		return ErrBadCandidateAddr(DefaultCodespace)
+	}
+	if bytes.Equal(msg.CandidateSrcAddr, msg.CandidateDstAddr) {
+		return ErrSelfRedelegation(DefaultCodespace)
+	}
+	if msg.Shares != "MAX" {
+		rat, err := sdk.NewRatFromDecimal(msg.Shares)
+		if err != nil {
+			return ErrBadShares(DefaultCodespace)
+		}
+		if rat.IsZero() || rat.LT(sdk.ZeroRat()) {
+			return ErrBadShares(DefaultCodespace)
+		}
+	}
+	return nil
+}
+
+//______________________________________________________________________
+
+// MsgWithdraw - struct for withdrawing the fee rewards of a bond,
+// and the commission when the delegator owns the candidate
+type MsgWithdraw struct {
+	DelegatorAddr sdk.Address `json:"delegator"`
+	CandidateAddr sdk.Address `json:"candidate"`
+}
+
+func NewMsgWithdraw(delegatorAddr, candidateAddr sdk.Address) MsgWithdraw {
+	return MsgWithdraw{
+		DelegatorAddr: delegatorAddr,
+		CandidateAddr: candidateAddr,
+	}
+}
+
+//nolint
+func (msg MsgWithdraw) Type() string              { return MsgType }
+func (msg MsgWithdraw) GetSigners() []sdk.Address { return []sdk.Address{msg.DelegatorAddr} }
+
+// get the bytes for the message signer to sign on
+func (msg MsgWithdraw) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// quick validity check
+func (msg MsgWithdraw) ValidateBasic() sdk.Error {
+	if msg.DelegatorAddr == nil {
+		return ErrBadDelegatorAddr(DefaultCodespace)
+	}
+	if msg.CandidateAddr == nil {
+		return ErrBadCandidateAddr(DefaultCodespace)
+	}
+	return nil
+}
@@ -5,8 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	crypto "github.com/tendermint/go-crypto"
+	sdk "inschain-tendermint/types"
 )
 
 var (
@@ -40,7 +40,34 @@ func TestMsgDeclareCandidacy(t *testing.T) {
 
 	for _, tc := range tests {
 		description := NewDescription(tc.moniker, tc.identity, tc.website, tc.details)
-		msg := NewMsgDeclareCandidacy(tc.candidateAddr, tc.pubkey, tc.bond, description)
+		commission := NewCommission(sdk.NewRat(1, 10), sdk.NewRat(2, 10), sdk.NewRat(1, 100))
+		msg := NewMsgDeclareCandidacy(tc.candidateAddr, tc.pubkey, tc.bond, description, commission)
+		if tc.expectPass {
+			assert.Nil(t, msg.ValidateBasic(), "test: %v", tc.name)
+		} else {
+			assert.NotNil(t, msg.ValidateBasic(), "test: %v", tc.name)
+		}
+	}
+}
+
+// test ValidateBasic of the commission of MsgDeclareCandidacy
+func TestMsgDeclareCandidacyCommission(t *testing.T) {
+	tests := []struct {
+		name                         string
+		rate, maxRate, maxChangeRate sdk.Rat
+		expectPass                   bool
+	}{
+		{"basic good", sdk.NewRat(1, 10), sdk.NewRat(2, 10), sdk.NewRat(1, 100), true},
+		{"zero commission", sdk.ZeroRat(), sdk.ZeroRat(), sdk.ZeroRat(), true},
+		{"negative rate", sdk.NewRat(-1, 10), sdk.NewRat(2, 10), sdk.NewRat(1, 100), false},
+		{"max rate above 100%", sdk.NewRat(1, 10), sdk.NewRat(11, 10), sdk.NewRat(1, 100), false},
+		{"rate above max rate", sdk.NewRat(3, 10), sdk.NewRat(2, 10), sdk.NewRat(1, 100), false},
+		{"change rate above max rate", sdk.NewRat(1, 10), sdk.NewRat(2, 10), sdk.NewRat(3, 10), false},
+	}
+
+	for _, tc := range tests {
+		commission := NewCommission(tc.rate, tc.maxRate, tc.maxChangeRate)
+		msg := NewMsgDeclareCandidacy(addrs[0], pks[0], coinPos, NewDescription("a", "", "", ""), commission)
 		if tc.expectPass {
 			assert.Nil(t, msg.ValidateBasic(), "test: %v", tc.name)
 		} else {
@@ -64,13 +91,19 @@ func TestMsgEditCandidacy(t *testing.T) {
 
 	for _, tc := range tests {
 		description := NewDescription(tc.moniker, tc.identity, tc.website, tc.details)
-		msg := NewMsgEditCandidacy(tc.candidateAddr, description)
+		msg := NewMsgEditCandidacy(tc.candidateAddr, description, nil)
 		if tc.expectPass {
 			assert.Nil(t, msg.ValidateBasic(), "test: %v", tc.name)
 		} else {
 			assert.NotNil(t, msg.ValidateBasic(), "test: %v", tc.name)
 		}
 	}
+
+	// the commission rate alone can be edited, within 0 and 100%
+	rate := sdk.NewRat(1, 10)
+	assert.Nil(t, NewMsgEditCandidacy(addrs[0], Description{}, &rate).ValidateBasic())
+	rate = sdk.NewRat(11, 10)
+	assert.NotNil(t, NewMsgEditCandidacy(addrs[0], Description{}, &rate).ValidateBasic())
 }
 
 // test ValidateBasic for MsgDelegate
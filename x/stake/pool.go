@@ -1,13 +1,13 @@
 package stake
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
-// get the bond ratio of the global state
-func (p Pool) bondedRatio() sdk.Rat {
-	if p.TotalSupply > 0 {
-		return sdk.NewRat(p.BondedPool, p.TotalSupply)
+// get the bond ratio of the global state, out of the total supply
+func (p Pool) bondedRatio(totalSupply int64) sdk.Rat {
+	if totalSupply > 0 {
+		return sdk.NewRat(p.BondedPool, totalSupply)
 	}
 	return sdk.ZeroRat()
 }
@@ -116,3 +116,19 @@ func (p Pool) candidateRemoveShares(candidate Candidate,
 	candidate.Liabilities = candidate.Liabilities.Sub(shares)
 	return p, candidate, createdCoins
 }
+
+// slash a fraction of the global shares held by a candidate, the tokens
+// backing those shares are burned and the candidate's delegator shares
+// become worth less
+func (p Pool) candidateSlash(candidate Candidate,
+	fraction sdk.Rat) (p2 Pool, candidate2 Candidate, burnedTokens int64) {
+
+	globalPoolSharesToRemove := candidate.Assets.Mul(fraction)
+	if candidate.Status == Bonded {
+		p, burnedTokens = p.removeSharesBonded(globalPoolSharesToRemove)
+	} else {
+		p, burnedTokens = p.removeSharesUnbonded(globalPoolSharesToRemove)
+	}
+	candidate.Assets = candidate.Assets.Sub(globalPoolSharesToRemove)
+	return p, candidate, burnedTokens
+}
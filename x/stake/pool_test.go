@@ -8,21 +8,19 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 func TestBondedRatio(t *testing.T) {
 	ctx, _, keeper := createTestInput(t, false, 0)
 	pool := keeper.GetPool(ctx)
-	pool.TotalSupply = 3
 	pool.BondedPool = 2
 
 	// bonded pool / total supply
-	require.Equal(t, pool.bondedRatio(), sdk.NewRat(2).Quo(sdk.NewRat(3)))
-	pool.TotalSupply = 0
+	require.Equal(t, pool.bondedRatio(3), sdk.NewRat(2).Quo(sdk.NewRat(3)))
 
 	// avoids divide-by-zero
-	require.Equal(t, pool.bondedRatio(), sdk.ZeroRat())
+	require.Equal(t, pool.bondedRatio(0), sdk.ZeroRat())
 }
 
 func TestBondedShareExRate(t *testing.T) {
@@ -234,7 +232,6 @@ func TestCandidateRemoveShares(t *testing.T) {
 		Liabilities: liabilities,
 	}
 	pool := Pool{
-		TotalSupply:       0,
 		BondedShares:      sdk.NewRat(248305),
 		UnbondedShares:    sdk.NewRat(232147),
 		BondedPool:        248305,
@@ -253,6 +250,29 @@ func TestCandidateRemoveShares(t *testing.T) {
 		"Tokens were not conserved: %s", msg)
 }
 
+func TestCandidateSlash(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+
+	poolA := keeper.GetPool(ctx)
+	candA := Candidate{
+		Status:      Bonded,
+		Address:     addrs[0],
+		PubKey:      pks[0],
+		Assets:      sdk.NewRat(100),
+		Liabilities: sdk.NewRat(100),
+	}
+	poolA.BondedPool = candA.Assets.Evaluate()
+	poolA.BondedShares = candA.Assets
+	poolB, candB, burned := poolA.candidateSlash(candA, sdk.NewRat(1, 10))
+
+	// tokens were burned
+	assert.Equal(t, int64(10), burned)
+	assert.Equal(t, int64(90), poolB.BondedPool)
+	// the delegators keep their shares but each is worth less
+	assert.True(t, candB.Liabilities.Equal(candA.Liabilities))
+	assert.True(t, candB.delegatorShareExRate().Equal(sdk.NewRat(9, 10)))
+}
+
 /////////////////////////////////////
 // TODO Make all random tests less obfuscated!
 
@@ -278,7 +298,6 @@ func randomCandidate(r *rand.Rand) Candidate {
 // generate a random staking state
 func randomSetup(r *rand.Rand, numCandidates int) (Pool, Candidates) {
 	pool := Pool{
-		TotalSupply:       0,
 		BondedShares:      sdk.ZeroRat(),
 		UnbondedShares:    sdk.ZeroRat(),
 		BondedPool:        0,
@@ -443,7 +462,6 @@ func TestPossibleOverflow(t *testing.T) {
 		Liabilities: liabilities,
 	}
 	pool := Pool{
-		TotalSupply:       0,
 		BondedShares:      assets,
 		UnbondedShares:    sdk.ZeroRat(),
 		BondedPool:        assets.Evaluate(),
@@ -0,0 +1,144 @@
+package stake
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+// query endpoints supported by the staking querier, at /custom/stake/<route>
+const (
+	QueryCandidates     = "candidates"
+	QueryCandidate      = "candidate"
+	QueryDelegatorBonds = "delegator-bonds"
+	QueryPool           = "pool"
+	QueryParams         = "params"
+)
+
+// bounds of the number of candidates or bonds in a page
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// parameters of the candidates query, the page starts at the Start candidate
+// included, or at the first candidate
+type QueryCandidatesParams struct {
+	Start sdk.Address `json:"start"`
+	Limit int         `json:"limit"`
+}
+
+// parameters of the candidate query
+type QueryCandidateParams struct {
+	CandidateAddr sdk.Address `json:"address"`
+}
+
+// parameters of the delegator bonds query, the page starts at the bond to the
+// Start candidate included, or at the first bond of the delegator
+type QueryDelegatorBondsParams struct {
+	DelegatorAddr sdk.Address `json:"delegator"`
+	Start         sdk.Address `json:"start"`
+	Limit         int         `json:"limit"`
+}
+
+// a page of candidates, Next is the start of the next page if any
+type CandidatesPage struct {
+	Candidates Candidates  `json:"candidates"`
+	Next       sdk.Address `json:"next"`
+}
+
+// a page of bonds, Next is the candidate of the next page if any
+type DelegatorBondsPage struct {
+	Bonds []DelegatorBond `json:"bonds"`
+	Next  sdk.Address     `json:"next"`
+}
+
+// NewQuerier returns the querier of the staking module
+func NewQuerier(k Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("No stake query endpoint specified")
+		}
+		switch path[0] {
+		case QueryCandidates:
+			return queryCandidates(ctx, req, k)
+		case QueryCandidate:
+			return queryCandidate(ctx, req, k)
+		case QueryDelegatorBonds:
+			return queryDelegatorBonds(ctx, req, k)
+		case QueryPool:
+			return k.marshalQueryResult(k.GetPool(ctx))
+		case QueryParams:
+			return k.marshalQueryResult(k.GetParams(ctx))
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Unknown stake query endpoint %s", path[0]))
+		}
+	}
+}
+
+func queryCandidates(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryCandidatesParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	page := CandidatesPage{}
+	page.Candidates, page.Next = k.GetCandidates(ctx, params.Start, pageLimit(params.Limit))
+	if page.Candidates == nil {
+		page.Candidates = Candidates{}
+	}
+	return k.marshalQueryResult(page)
+}
+
+func queryCandidate(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryCandidateParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	candidate, found := k.GetCandidate(ctx, params.CandidateAddr)
+	if !found {
+		return nil, ErrNoCandidateForAddress(k.codespace)
+	}
+	return k.marshalQueryResult(candidate)
+}
+
+func queryDelegatorBonds(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryDelegatorBondsParams
+	if err := k.unmarshalQueryParams(req, &params); err != nil {
+		return nil, err
+	}
+	page := DelegatorBondsPage{}
+	page.Bonds, page.Next = k.GetDelegatorBonds(ctx, params.DelegatorAddr, params.Start, pageLimit(params.Limit))
+	if page.Bonds == nil {
+		page.Bonds = []DelegatorBond{}
+	}
+	return k.marshalQueryResult(page)
+}
+
+// bound the limit of a page, the keeper returns everything for a zero limit
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		return maxPageLimit
+	}
+	return limit
+}
+
+func (k Keeper) unmarshalQueryParams(req abci.RequestQuery, params interface{}) sdk.Error {
+	err := k.cdc.UnmarshalJSON(req.Data, params)
+	if err != nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("Incorrectly formatted query data: %s", err.Error()))
+	}
+	return nil
+}
+
+func (k Keeper) marshalQueryResult(res interface{}) ([]byte, sdk.Error) {
+	bz, err := k.cdc.MarshalJSON(res)
+	if err != nil {
+		panic(err)
+	}
+	return bz, nil
+}
@@ -0,0 +1,62 @@
+package stake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+func query(t *testing.T, ctx sdk.Context, keeper Keeper, route string, params interface{}, res interface{}) sdk.Error {
+	data, err := keeper.cdc.MarshalJSON(params)
+	require.Nil(t, err)
+	bz, sdkErr := NewQuerier(keeper)(ctx, []string{route}, abci.RequestQuery{Data: data})
+	if sdkErr != nil {
+		return sdkErr
+	}
+	require.Nil(t, keeper.cdc.UnmarshalJSON(bz, res))
+	return nil
+}
+
+func TestQuerier(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	for i := 0; i < 3; i++ {
+		keeper.setCandidate(ctx, NewCandidate(addrVals[i], pks[i], Description{}))
+		keeper.setDelegatorBond(ctx, DelegatorBond{DelegatorAddr: addrDels[0], CandidateAddr: addrVals[i], Shares: sdk.NewRat(9)})
+	}
+
+	// the candidates are paginated
+	var candidates CandidatesPage
+	require.Nil(t, query(t, ctx, keeper, QueryCandidates, QueryCandidatesParams{nil, 2}, &candidates))
+	require.Equal(t, 2, len(candidates.Candidates))
+	assert.Equal(t, addrVals[2], candidates.Next)
+	require.Nil(t, query(t, ctx, keeper, QueryCandidates, QueryCandidatesParams{candidates.Next, 2}, &candidates))
+	require.Equal(t, 1, len(candidates.Candidates))
+	assert.Equal(t, addrVals[2], candidates.Candidates[0].Address)
+	assert.Empty(t, candidates.Next)
+
+	var candidate Candidate
+	require.Nil(t, query(t, ctx, keeper, QueryCandidate, QueryCandidateParams{addrVals[1]}, &candidate))
+	assert.Equal(t, addrVals[1], candidate.Address)
+	assert.NotNil(t, query(t, ctx, keeper, QueryCandidate, QueryCandidateParams{addrVals[3]}, &candidate))
+
+	// so are the bonds of a delegator
+	var bonds DelegatorBondsPage
+	require.Nil(t, query(t, ctx, keeper, QueryDelegatorBonds, QueryDelegatorBondsParams{addrDels[0], addrVals[1], 0}, &bonds))
+	require.Equal(t, 2, len(bonds.Bonds))
+	assert.Equal(t, addrVals[1], bonds.Bonds[0].CandidateAddr)
+	assert.Empty(t, bonds.Next)
+	require.Nil(t, query(t, ctx, keeper, QueryDelegatorBonds, QueryDelegatorBondsParams{addrDels[1], nil, 0}, &bonds))
+	assert.Equal(t, 0, len(bonds.Bonds))
+
+	var pool Pool
+	require.Nil(t, query(t, ctx, keeper, QueryPool, struct{}{}, &pool))
+	assert.Equal(t, keeper.GetPool(ctx), pool)
+	var params Params
+	require.Nil(t, query(t, ctx, keeper, QueryParams, struct{}{}, &params))
+	assert.Equal(t, keeper.GetParams(ctx), params)
+}
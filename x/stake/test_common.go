@@ -12,11 +12,11 @@ import (
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
-	"github.com/cosmos/cosmos-sdk/store"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
-	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/bank"
+	"inschain-tendermint/store"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+	"inschain-tendermint/x/auth"
+	"inschain-tendermint/x/bank"
 )
 
 // dummy addresses used for testing
@@ -85,13 +85,13 @@ func defaultParams() Params {
 		GoalBonded:          sdk.NewRat(67, 100),
 		MaxValidators:       100,
 		BondDenom:           "steak",
+		UnbondingTime:       60 * 60 * 24 * 21,
 	}
 }
 
 // initial pool for testing
 func initialPool() Pool {
 	return Pool{
-		TotalSupply:       0,
 		BondedShares:      sdk.ZeroRat(),
 		UnbondedShares:    sdk.ZeroRat(),
 		BondedPool:        0,
@@ -127,6 +127,8 @@ func makeTestCodec() *wire.Codec {
 	cdc.RegisterConcrete(MsgDeclareCandidacy{}, "test/stake/DeclareCandidacy", nil)
 	cdc.RegisterConcrete(MsgEditCandidacy{}, "test/stake/EditCandidacy", nil)
 	cdc.RegisterConcrete(MsgUnbond{}, "test/stake/Unbond", nil)
+	cdc.RegisterConcrete(MsgRedelegate{}, "test/stake/Redelegate", nil)
+	cdc.RegisterConcrete(MsgWithdraw{}, "test/stake/Withdraw", nil)
 
 	// Register AppAccount
 	cdc.RegisterInterface((*sdk.Account)(nil), nil)
@@ -144,6 +146,7 @@ func paramsNoInflation() Params {
 		GoalBonded:          sdk.NewRat(67, 100),
 		MaxValidators:       100,
 		BondDenom:           "steak",
+		UnbondingTime:       60 * 60 * 24 * 21,
 	}
 }
 
@@ -152,9 +155,11 @@ func createTestInput(t *testing.T, isCheckTx bool, initCoins int64) (sdk.Context
 	db := dbm.NewMemDB()
 	keyStake := sdk.NewKVStoreKey("stake")
 	keyMain := keyStake //sdk.NewKVStoreKey("main") //TODO fix multistore
+	keySupply := sdk.NewKVStoreKey("supply")
 
 	ms := store.NewCommitMultiStore(db)
 	ms.MountStoreWithDB(keyStake, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keySupply, sdk.StoreTypeIAVL, db)
 	err := ms.LoadLatestVersion()
 	require.Nil(t, err)
 
@@ -166,13 +171,14 @@ func createTestInput(t *testing.T, isCheckTx bool, initCoins int64) (sdk.Context
 		&auth.BaseAccount{}, // prototype
 	)
 	ck := bank.NewKeeper(accountMapper)
-	keeper := NewKeeper(cdc, keyStake, ck, DefaultCodespace)
+	sk := bank.NewSupplyKeeper(cdc, keySupply, ck, bank.DefaultCodespace)
+	keeper := NewKeeper(cdc, keyStake, sk, DefaultCodespace)
 	keeper.setPool(ctx, initialPool())
 	keeper.setParams(ctx, defaultParams())
 
 	// fill all the addresses with some coins
 	for _, addr := range addrs {
-		ck.AddCoins(ctx, addr, sdk.Coins{
+		sk.Mint(ctx, addr, sdk.Coins{
 			{keeper.GetParams(ctx).BondDenom, initCoins},
 		})
 	}
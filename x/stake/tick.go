@@ -1,8 +1,8 @@
 package stake
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	abci "github.com/tendermint/abci/types"
+	sdk "inschain-tendermint/types"
 )
 
 const (
@@ -26,6 +26,12 @@ func (k Keeper) Tick(ctx sdk.Context) (change []abci.Validator) {
 	// save the params
 	k.setPool(ctx, p)
 
+	// distribute the fees of the block
+	k.distributeFees(ctx)
+
+	// return the unbonded tokens which are no longer slashable
+	k.completeMatured(ctx)
+
 	// reset the counter
 	k.setCounter(ctx, 0)
 
@@ -44,9 +50,14 @@ func (k Keeper) processProvisions(ctx sdk.Context) Pool {
 	// more bonded tokens are added proportionally to all validators the only term
 	// which needs to be updated is the `BondedPool`. So for each previsions cycle:
 
-	provisions := pool.Inflation.Mul(sdk.NewRat(pool.TotalSupply)).Quo(hrsPerYrRat).Evaluate()
+	provisions := pool.Inflation.Mul(sdk.NewRat(k.GetTotalSupply(ctx))).Quo(hrsPerYrRat).Evaluate()
 	pool.BondedPool += provisions
-	pool.TotalSupply += provisions
+	if provisions > 0 {
+		err := k.supplyKeeper.MintEscrow(ctx, MsgType, sdk.Coins{{k.GetParams(ctx).BondDenom, provisions}})
+		if err != nil {
+			panic(err)
+		}
+	}
 	return pool
 }
 
@@ -62,7 +73,7 @@ func (k Keeper) nextInflation(ctx sdk.Context) (inflation sdk.Rat) {
 	// 7% and 20%.
 
 	// (1 - bondedRatio/GoalBonded) * InflationRateChange
-	inflationRateChangePerYear := sdk.OneRat().Sub(pool.bondedRatio().Quo(params.GoalBonded)).Mul(params.InflationRateChange)
+	inflationRateChangePerYear := sdk.OneRat().Sub(pool.bondedRatio(k.GetTotalSupply(ctx)).Quo(params.GoalBonded)).Mul(params.InflationRateChange)
 	inflationRateChange := inflationRateChangePerYear.Quo(hrsPerYrRat)
 
 	// increase the new annual inflation for this next cycle
@@ -3,9 +3,9 @@ package stake
 import (
 	"testing"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdk "inschain-tendermint/types"
 )
 
 func TestGetInflation(t *testing.T) {
@@ -47,9 +47,10 @@ func TestGetInflation(t *testing.T) {
 		{"test 8", 67, 100, sdk.NewRat(15, 100), sdk.ZeroRat()},
 	}
 	for _, tc := range tests {
-		pool.BondedPool, pool.TotalSupply = tc.setBondedPool, tc.setTotalSupply
+		pool.BondedPool = tc.setBondedPool
 		pool.Inflation = tc.setInflation
 		keeper.setPool(ctx, pool)
+		setTotalSupply(t, ctx, keeper, tc.setTotalSupply)
 
 		inflation := keeper.nextInflation(ctx)
 		diffInflation := inflation.Sub(tc.setInflation)
@@ -79,7 +80,7 @@ func TestProcessProvisions(t *testing.T) {
 			c.Status = Bonded
 		}
 		mintedTokens := int64((i + 1) * 10000000)
-		pool.TotalSupply += mintedTokens
+		require.Nil(t, keeper.supplyKeeper.MintEscrow(ctx, MsgType, sdk.Coins{{params.BondDenom, mintedTokens}}))
 		pool, c, _ = pool.candidateAddTokens(c, mintedTokens)
 
 		keeper.setCandidate(ctx, c)
@@ -89,45 +90,78 @@ func TestProcessProvisions(t *testing.T) {
 	var totalSupply int64 = 550000000
 	var bondedShares int64 = 150000000
 	var unbondedShares int64 = 400000000
-	assert.Equal(t, totalSupply, pool.TotalSupply)
+	assert.Equal(t, totalSupply, keeper.GetTotalSupply(ctx))
 	assert.Equal(t, bondedShares, pool.BondedPool)
 	assert.Equal(t, unbondedShares, pool.UnbondedPool)
 
 	// initial bonded ratio ~ 27%
-	assert.True(t, pool.bondedRatio().Equal(sdk.NewRat(bondedShares, totalSupply)), "%v", pool.bondedRatio())
+	assert.True(t, pool.bondedRatio(totalSupply).Equal(sdk.NewRat(bondedShares, totalSupply)), "%v", pool.bondedRatio(totalSupply))
 
 	// test the value of candidate shares
 	assert.True(t, pool.bondedShareExRate().Equal(sdk.OneRat()), "%v", pool.bondedShareExRate())
 
-	initialSupply := pool.TotalSupply
-	initialUnbonded := pool.TotalSupply - pool.BondedPool
+	initialSupply := keeper.GetTotalSupply(ctx)
+	initialUnbonded := initialSupply - pool.BondedPool
 
 	// process the provisions a year
 	for hr := 0; hr < 8766; hr++ {
 		pool := keeper.GetPool(ctx)
 		expInflation := keeper.nextInflation(ctx).Round(1000000000)
-		expProvisions := (expInflation.Mul(sdk.NewRat(pool.TotalSupply)).Quo(hrsPerYrRat)).Evaluate()
+		expProvisions := (expInflation.Mul(sdk.NewRat(keeper.GetTotalSupply(ctx))).Quo(hrsPerYrRat)).Evaluate()
 		startBondedPool := pool.BondedPool
-		startTotalSupply := pool.TotalSupply
+		startTotalSupply := keeper.GetTotalSupply(ctx)
 		pool = keeper.processProvisions(ctx)
 		keeper.setPool(ctx, pool)
 		//fmt.Printf("hr %v, startBondedPool %v, expProvisions %v, pool.BondedPool %v\n", hr, startBondedPool, expProvisions, pool.BondedPool)
 		require.Equal(t, startBondedPool+expProvisions, pool.BondedPool, "hr %v", hr)
-		require.Equal(t, startTotalSupply+expProvisions, pool.TotalSupply)
+		require.Equal(t, startTotalSupply+expProvisions, keeper.GetTotalSupply(ctx))
 	}
 	pool = keeper.GetPool(ctx)
-	assert.NotEqual(t, initialSupply, pool.TotalSupply)
+	assert.NotEqual(t, initialSupply, keeper.GetTotalSupply(ctx))
 	assert.Equal(t, initialUnbonded, pool.UnbondedPool)
 	//panic(fmt.Sprintf("debug total %v, bonded  %v, diff %v\n", p.TotalSupply, p.BondedPool, pool.TotalSupply-pool.BondedPool))
 
 	// initial bonded ratio ~ from 27% to 40% increase for bonded holders ownership of total supply
-	assert.True(t, pool.bondedRatio().Equal(sdk.NewRat(211813022, 611813022)), "%v", pool.bondedRatio())
+	assert.True(t, pool.bondedRatio(keeper.GetTotalSupply(ctx)).Equal(sdk.NewRat(211813022, 611813022)), "%v", pool.bondedRatio(keeper.GetTotalSupply(ctx)))
 
 	// global supply
-	assert.Equal(t, int64(611813022), pool.TotalSupply)
+	assert.Equal(t, int64(611813022), keeper.GetTotalSupply(ctx))
 	assert.Equal(t, int64(211813022), pool.BondedPool)
 	assert.Equal(t, unbondedShares, pool.UnbondedPool)
 
 	// test the value of candidate shares
 	assert.True(t, pool.bondedShareExRate().Mul(sdk.NewRat(bondedShares)).Equal(sdk.NewRat(211813022)), "%v", pool.bondedShareExRate())
 }
+
+func TestProvisionsFollowSupply(t *testing.T) {
+	var initCoins int64 = 1000000000
+	ctx, _, keeper := createTestInput(t, false, initCoins)
+	params := keeper.GetParams(ctx)
+	supply := keeper.GetTotalSupply(ctx)
+	require.Equal(t, int64(len(addrs))*initCoins, supply)
+
+	// tokens burned out of the stake module, like an IBC transfer, leave the
+	// supply the provisions are computed from
+	_, err := keeper.supplyKeeper.Burn(ctx, addrs[0], sdk.Coins{{params.BondDenom, initCoins}})
+	require.Nil(t, err)
+	supply -= initCoins
+	assert.Equal(t, supply, keeper.GetTotalSupply(ctx))
+
+	inflation := keeper.nextInflation(ctx)
+	pool := keeper.processProvisions(ctx)
+	expProvisions := inflation.Mul(sdk.NewRat(supply)).Quo(hrsPerYrRat).Evaluate()
+	require.True(t, expProvisions > 0)
+	assert.Equal(t, expProvisions, pool.BondedPool)
+	assert.Equal(t, supply+expProvisions, keeper.GetTotalSupply(ctx))
+}
+
+// set the supply of the bond denom, minting or burning tokens held by the pool
+func setTotalSupply(t *testing.T, ctx sdk.Context, keeper Keeper, supply int64) {
+	denom := keeper.GetParams(ctx).BondDenom
+	diff := supply - keeper.GetTotalSupply(ctx)
+	if diff > 0 {
+		require.Nil(t, keeper.supplyKeeper.MintEscrow(ctx, MsgType, sdk.Coins{{denom, diff}}))
+	} else if diff < 0 {
+		require.Nil(t, keeper.supplyKeeper.BurnEscrow(ctx, MsgType, sdk.Coins{{denom, -diff}}))
+	}
+}
@@ -1,18 +1,23 @@
 package stake
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
 	abci "github.com/tendermint/abci/types"
 	crypto "github.com/tendermint/go-crypto"
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
 )
 
 // GenesisState - all staking state that must be provided at genesis
 type GenesisState struct {
-	Pool       Pool            `json:"pool"`
-	Params     Params          `json:"params"`
-	Candidates []Candidate     `json:"candidates"`
-	Bonds      []DelegatorBond `json:"bonds"`
+	Pool       Pool               `json:"pool"`
+	Params     Params             `json:"params"`
+	Candidates []Candidate        `json:"candidates"`
+	Bonds      []DelegatorBond    `json:"bonds"`
+	FeePool    FeePool            `json:"fee_pool"`
+	Rewards    []ValidatorRewards `json:"rewards"`
+
+	UnbondingDelegations []UnbondingDelegation `json:"unbonding_delegations"`
+	Redelegations        []Redelegation        `json:"redelegations"`
 }
 
 //_________________________________________________________________________
@@ -26,6 +31,8 @@ type Params struct {
 
 	MaxValidators uint16 `json:"max_validators"` // maximum number of validators
 	BondDenom     string `json:"bond_denom"`     // bondable coin denomination
+
+	UnbondingTime int64 `json:"unbonding_time"` // seconds unbonded tokens stay slashable before they are returned
 }
 
 func (p Params) equal(p2 Params) bool {
@@ -34,14 +41,14 @@ func (p Params) equal(p2 Params) bool {
 		p.InflationMin.Equal(p2.InflationMin) &&
 		p.GoalBonded.Equal(p2.GoalBonded) &&
 		p.MaxValidators == p2.MaxValidators &&
-		p.BondDenom == p2.BondDenom
+		p.BondDenom == p2.BondDenom &&
+		p.UnbondingTime == p2.UnbondingTime
 }
 
 //_________________________________________________________________________
 
 // Pool - dynamic parameters of the current state
 type Pool struct {
-	TotalSupply       int64   `json:"total_supply"`        // total supply of all tokens
 	BondedShares      sdk.Rat `json:"bonded_shares"`       // sum of all shares distributed for the Bonded Pool
 	UnbondedShares    sdk.Rat `json:"unbonded_shares"`     // sum of all shares distributed for the Unbonded Pool
 	BondedPool        int64   `json:"bonded_pool"`         // reserve of bonded tokens
@@ -54,7 +61,6 @@ func (p Pool) equal(p2 Pool) bool {
 	return p.BondedShares.Equal(p2.BondedShares) &&
 		p.UnbondedShares.Equal(p2.UnbondedShares) &&
 		p.Inflation.Equal(p2.Inflation) &&
-		p.TotalSupply == p2.TotalSupply &&
 		p.BondedPool == p2.BondedPool &&
 		p.UnbondedPool == p2.UnbondedPool &&
 		p.InflationLastTime == p2.InflationLastTime
@@ -86,8 +92,10 @@ type Candidate struct {
 	Assets               sdk.Rat         `json:"assets"`                 // total shares of a global hold pools
 	Liabilities          sdk.Rat         `json:"liabilities"`            // total shares issued to a candidate's delegators
 	Description          Description     `json:"description"`            // Description terms for the candidate
+	Commission           Commission      `json:"commission"`             // Share of the rewards kept by the owner
 	ValidatorBondHeight  int64           `json:"validator_bond_height"`  // Earliest height as a bonded validator
 	ValidatorBondCounter int16           `json:"validator_bond_counter"` // Block-local tx index of validator change
+	Jailed               bool            `json:"jailed"`                 // Jailed candidates are kept out of the validator set
 }
 
 // Candidates - list of Candidates
@@ -102,6 +110,7 @@ func NewCandidate(address sdk.Address, pubKey crypto.PubKey, description Descrip
 		Assets:               sdk.ZeroRat(),
 		Liabilities:          sdk.ZeroRat(),
 		Description:          description,
+		Commission:           NewCommission(sdk.ZeroRat(), sdk.ZeroRat(), sdk.ZeroRat()),
 		ValidatorBondHeight:  int64(0),
 		ValidatorBondCounter: int16(0),
 	}
@@ -124,6 +133,68 @@ func NewDescription(moniker, identity, website, details string) Description {
 	}
 }
 
+// seconds which must pass between two changes of a commission rate
+const CommissionChangePeriod = 60 * 60 * 24
+
+// Commission - the share of the rewards of a candidate kept by its owner,
+// the rate can only move within the limits set at the candidacy
+type Commission struct {
+	Rate          sdk.Rat `json:"rate"`            // share of the rewards kept by the owner
+	MaxRate       sdk.Rat `json:"max_rate"`        // rate the owner can never exceed
+	MaxChangeRate sdk.Rat `json:"max_change_rate"` // maximum change of the rate per day
+	UpdateTime    int64   `json:"update_time"`     // unix time the rate last changed
+}
+
+func NewCommission(rate, maxRate, maxChangeRate sdk.Rat) Commission {
+	return Commission{
+		Rate:          rate,
+		MaxRate:       maxRate,
+		MaxChangeRate: maxChangeRate,
+	}
+}
+
+// Validate checks the rates are between 0 and 1, and the rate and its
+// maximum change don't exceed the max rate
+func (c Commission) Validate(codespace sdk.CodespaceType) sdk.Error {
+	for _, rate := range []sdk.Rat{c.Rate, c.MaxRate, c.MaxChangeRate} {
+		if rate.LT(sdk.ZeroRat()) {
+			return ErrCommissionNegative(codespace)
+		}
+		if rate.GT(sdk.OneRat()) {
+			return ErrCommissionHuge(codespace)
+		}
+	}
+	if c.Rate.GT(c.MaxRate) {
+		return ErrCommissionGTMaxRate(codespace)
+	}
+	if c.MaxChangeRate.GT(c.MaxRate) {
+		return ErrCommissionChangeRateGTMaxRate(codespace)
+	}
+	return nil
+}
+
+// ValidateNewRate checks the rate can be changed to the new rate at the time,
+// at most once a day and by at most the max change rate
+func (c Commission) ValidateNewRate(codespace sdk.CodespaceType, newRate sdk.Rat, now int64) sdk.Error {
+	if now-c.UpdateTime < CommissionChangePeriod {
+		return ErrCommissionUpdateTime(codespace)
+	}
+	if newRate.LT(sdk.ZeroRat()) {
+		return ErrCommissionNegative(codespace)
+	}
+	if newRate.GT(c.MaxRate) {
+		return ErrCommissionGTMaxRate(codespace)
+	}
+	change := newRate.Sub(c.Rate)
+	if change.LT(sdk.ZeroRat()) {
+		change = sdk.ZeroRat().Sub(change)
+	}
+	if change.GT(c.MaxChangeRate) {
+		return ErrCommissionGTMaxChangeRate(codespace)
+	}
+	return nil
+}
+
 // get the exchange rate of global pool shares over delegator shares
 func (c Candidate) delegatorShareExRate() sdk.Rat {
 	if c.Liabilities.IsZero() {
@@ -186,4 +257,21 @@ type DelegatorBond struct {
 	CandidateAddr sdk.Address `json:"candidate_addr"`
 	Shares        sdk.Rat     `json:"shares"`
 	Height        int64       `json:"height"` // Last height bond updated
+
+	// rewards per share of the candidate at the last withdrawal
+	RewardsPerShare RatCoins `json:"rewards_per_share"`
+}
+
+//_________________________________________________________________________
+
+// FeePool - fees collected and not yet distributed to the validators
+type FeePool struct {
+	Fees sdk.Coins `json:"fees"`
+}
+
+// ValidatorRewards - fees distributed to a candidate and not yet withdrawn
+type ValidatorRewards struct {
+	CandidateAddr   sdk.Address `json:"candidate_addr"`
+	Commission      sdk.Coins   `json:"commission"`        // commission of the owner
+	RewardsPerShare RatCoins    `json:"rewards_per_share"` // accumulated rewards per delegator share
 }
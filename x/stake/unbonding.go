@@ -0,0 +1,417 @@
+package stake
+
+import (
+	"bytes"
+
+	sdk "inschain-tendermint/types"
+)
+
+// Unbonded tokens are not returned to the delegator right away: they stay
+// in an unbonding delegation until Params.UnbondingTime has passed, so that
+// they can still be slashed for the infractions their candidate committed
+// while they were bonded. Matured entries are completed in the EndBlocker.
+//
+// A redelegation moves bonded tokens from one candidate to another without
+// waiting. It is remembered for the unbonding time too, so the tokens can be
+// slashed for the infractions of the source candidate, and they can't be
+// redelegated again before it completed.
+
+// UnbondingDelegation - the tokens of a delegator unbonding from a candidate
+type UnbondingDelegation struct {
+	DelegatorAddr sdk.Address                `json:"delegator_addr"`
+	CandidateAddr sdk.Address                `json:"candidate_addr"`
+	Entries       []UnbondingDelegationEntry `json:"entries"`
+}
+
+// UnbondingDelegationEntry - the tokens of a single unbond
+type UnbondingDelegationEntry struct {
+	CreationHeight int64 `json:"creation_height"` // height the unbond happened at
+	CompletionTime int64 `json:"completion_time"` // unix time the tokens are returned at
+	InitialBalance int64 `json:"initial_balance"` // tokens unbonded
+	Balance        int64 `json:"balance"`         // tokens left to return after slashing
+}
+
+// Redelegation - the tokens of a delegator moved from a candidate to another
+type Redelegation struct {
+	DelegatorAddr    sdk.Address         `json:"delegator_addr"`
+	CandidateSrcAddr sdk.Address         `json:"candidate_src_addr"`
+	CandidateDstAddr sdk.Address         `json:"candidate_dst_addr"`
+	Entries          []RedelegationEntry `json:"entries"`
+}
+
+// RedelegationEntry - the tokens of a single redelegation
+type RedelegationEntry struct {
+	CreationHeight int64   `json:"creation_height"` // height the redelegation happened at
+	CompletionTime int64   `json:"completion_time"` // unix time the redelegation stops being slashable
+	InitialBalance int64   `json:"initial_balance"` // tokens redelegated
+	SharesDst      sdk.Rat `json:"shares_dst"`      // shares of the destination bond left from the redelegation
+}
+
+//_______________________________________________________________________
+
+// load an unbonding delegation
+func (k Keeper) GetUnbondingDelegation(ctx sdk.Context,
+	delegatorAddr, candidateAddr sdk.Address) (ubd UnbondingDelegation, found bool) {
+
+	store := ctx.KVStore(k.storeKey)
+	return k.getUnbondingDelegation(store, GetUnbondingDelegationKey(delegatorAddr, candidateAddr))
+}
+
+func (k Keeper) getUnbondingDelegation(store sdk.KVStore, key []byte) (ubd UnbondingDelegation, found bool) {
+	b := store.Get(key)
+	if b == nil {
+		return ubd, false
+	}
+	err := k.cdc.UnmarshalJSON(b, &ubd)
+	if err != nil {
+		panic(err)
+	}
+	return ubd, true
+}
+
+// load the unbonding delegations of a delegator
+func (k Keeper) GetUnbondingDelegations(ctx sdk.Context, delegatorAddr sdk.Address) (ubds []UnbondingDelegation) {
+	return k.iterateUnbondingDelegations(ctx, GetUnbondingDelegationsKey(delegatorAddr))
+}
+
+// load all the unbonding delegations
+func (k Keeper) getAllUnbondingDelegations(ctx sdk.Context) (ubds []UnbondingDelegation) {
+	return k.iterateUnbondingDelegations(ctx, UnbondingDelegationKey)
+}
+
+func (k Keeper) iterateUnbondingDelegations(ctx sdk.Context, prefix []byte) (ubds []UnbondingDelegation) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.SubspaceIterator(prefix)
+	for ; iterator.Valid(); iterator.Next() {
+		var ubd UnbondingDelegation
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &ubd)
+		if err != nil {
+			panic(err)
+		}
+		ubds = append(ubds, ubd)
+	}
+	iterator.Close()
+	return
+}
+
+// load the unbonding delegations from a candidate
+func (k Keeper) getUnbondingDelegationsFromCandidate(ctx sdk.Context, candidateAddr sdk.Address) (ubds []UnbondingDelegation) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.SubspaceIterator(GetUnbondingDelegationsByCandidateKey(candidateAddr))
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Value())
+	}
+	iterator.Close()
+	for _, key := range keys {
+		ubd, found := k.getUnbondingDelegation(store, key)
+		if found {
+			ubds = append(ubds, ubd)
+		}
+	}
+	return
+}
+
+func (k Keeper) setUnbondingDelegation(ctx sdk.Context, ubd UnbondingDelegation) {
+	store := ctx.KVStore(k.storeKey)
+	b, err := k.cdc.MarshalJSON(ubd)
+	if err != nil {
+		panic(err)
+	}
+	key := GetUnbondingDelegationKey(ubd.DelegatorAddr, ubd.CandidateAddr)
+	store.Set(key, b)
+	store.Set(GetUnbondingDelegationByCandidateKey(ubd.CandidateAddr, ubd.DelegatorAddr), key)
+}
+
+func (k Keeper) removeUnbondingDelegation(ctx sdk.Context, ubd UnbondingDelegation) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(GetUnbondingDelegationKey(ubd.DelegatorAddr, ubd.CandidateAddr))
+	store.Delete(GetUnbondingDelegationByCandidateKey(ubd.CandidateAddr, ubd.DelegatorAddr))
+}
+
+// add the unbonded tokens to the unbonding delegation of the delegator,
+// returning the time they will be returned at
+func (k Keeper) addUnbondingDelegationEntry(ctx sdk.Context,
+	delegatorAddr, candidateAddr sdk.Address, tokens int64) (completionTime int64) {
+
+	completionTime = ctx.BlockHeader().Time + k.GetParams(ctx).UnbondingTime
+	ubd, found := k.GetUnbondingDelegation(ctx, delegatorAddr, candidateAddr)
+	if !found {
+		ubd = UnbondingDelegation{
+			DelegatorAddr: delegatorAddr,
+			CandidateAddr: candidateAddr,
+		}
+	}
+	ubd.Entries = append(ubd.Entries, UnbondingDelegationEntry{
+		CreationHeight: ctx.BlockHeight(),
+		CompletionTime: completionTime,
+		InitialBalance: tokens,
+		Balance:        tokens,
+	})
+	k.setUnbondingDelegation(ctx, ubd)
+	k.insertUnbondingQueue(ctx, ubd, completionTime)
+	return completionTime
+}
+
+func (k Keeper) insertUnbondingQueue(ctx sdk.Context, ubd UnbondingDelegation, completionTime int64) {
+	store := ctx.KVStore(k.storeKey)
+	key := GetUnbondingDelegationKey(ubd.DelegatorAddr, ubd.CandidateAddr)
+	store.Set(append(getQueueTimeKey(UnbondingQueueKey, completionTime), key...), key)
+}
+
+// return the tokens of the matured entries to the delegator
+func (k Keeper) completeUnbonding(ctx sdk.Context, ubd UnbondingDelegation) {
+	now := ctx.BlockHeader().Time
+	denom := k.GetParams(ctx).BondDenom
+	var entries []UnbondingDelegationEntry
+	for _, entry := range ubd.Entries {
+		if entry.CompletionTime > now {
+			entries = append(entries, entry)
+			continue
+		}
+		if entry.Balance > 0 {
+			_, err := k.supplyKeeper.RefundCoins(ctx, MsgType, ubd.DelegatorAddr, sdk.Coins{{denom, entry.Balance}})
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+	if len(entries) == 0 {
+		k.removeUnbondingDelegation(ctx, ubd)
+		return
+	}
+	ubd.Entries = entries
+	k.setUnbondingDelegation(ctx, ubd)
+}
+
+//_______________________________________________________________________
+
+// load a redelegation
+func (k Keeper) GetRedelegation(ctx sdk.Context,
+	delegatorAddr, srcAddr, dstAddr sdk.Address) (red Redelegation, found bool) {
+
+	store := ctx.KVStore(k.storeKey)
+	return k.getRedelegation(store, GetRedelegationKey(delegatorAddr, srcAddr, dstAddr))
+}
+
+func (k Keeper) getRedelegation(store sdk.KVStore, key []byte) (red Redelegation, found bool) {
+	b := store.Get(key)
+	if b == nil {
+		return red, false
+	}
+	err := k.cdc.UnmarshalJSON(b, &red)
+	if err != nil {
+		panic(err)
+	}
+	return red, true
+}
+
+// load the redelegations of a delegator
+func (k Keeper) GetRedelegations(ctx sdk.Context, delegatorAddr sdk.Address) (reds []Redelegation) {
+	return k.iterateRedelegations(ctx, GetRedelegationsKey(delegatorAddr))
+}
+
+// load all the redelegations
+func (k Keeper) getAllRedelegations(ctx sdk.Context) (reds []Redelegation) {
+	return k.iterateRedelegations(ctx, RedelegationKey)
+}
+
+func (k Keeper) iterateRedelegations(ctx sdk.Context, prefix []byte) (reds []Redelegation) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.SubspaceIterator(prefix)
+	for ; iterator.Valid(); iterator.Next() {
+		var red Redelegation
+		err := k.cdc.UnmarshalJSON(iterator.Value(), &red)
+		if err != nil {
+			panic(err)
+		}
+		reds = append(reds, red)
+	}
+	iterator.Close()
+	return
+}
+
+// load the redelegations from a source candidate
+func (k Keeper) getRedelegationsFromCandidate(ctx sdk.Context, srcAddr sdk.Address) (reds []Redelegation) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.SubspaceIterator(GetRedelegationsBySrcKey(srcAddr))
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Value())
+	}
+	iterator.Close()
+	for _, key := range keys {
+		red, found := k.getRedelegation(store, key)
+		if found {
+			reds = append(reds, red)
+		}
+	}
+	return
+}
+
+// whether tokens of the delegator were redelegated to the candidate and are still slashable
+func (k Keeper) hasReceivingRedelegation(ctx sdk.Context, delegatorAddr, dstAddr sdk.Address) bool {
+	for _, red := range k.GetRedelegations(ctx, delegatorAddr) {
+		if bytes.Equal(red.CandidateDstAddr, dstAddr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (k Keeper) setRedelegation(ctx sdk.Context, red Redelegation) {
+	store := ctx.KVStore(k.storeKey)
+	b, err := k.cdc.MarshalJSON(red)
+	if err != nil {
+		panic(err)
+	}
+	key := GetRedelegationKey(red.DelegatorAddr, red.CandidateSrcAddr, red.CandidateDstAddr)
+	store.Set(key, b)
+	store.Set(GetRedelegationBySrcKey(red.CandidateSrcAddr, red.DelegatorAddr, red.CandidateDstAddr), key)
+}
+
+func (k Keeper) removeRedelegation(ctx sdk.Context, red Redelegation) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(GetRedelegationKey(red.DelegatorAddr, red.CandidateSrcAddr, red.CandidateDstAddr))
+	store.Delete(GetRedelegationBySrcKey(red.CandidateSrcAddr, red.DelegatorAddr, red.CandidateDstAddr))
+}
+
+// remember the redelegated tokens and the destination shares they bought
+func (k Keeper) addRedelegationEntry(ctx sdk.Context, delegatorAddr, srcAddr, dstAddr sdk.Address,
+	tokens int64, sharesDst sdk.Rat) (completionTime int64) {
+
+	completionTime = ctx.BlockHeader().Time + k.GetParams(ctx).UnbondingTime
+	red, found := k.GetRedelegation(ctx, delegatorAddr, srcAddr, dstAddr)
+	if !found {
+		red = Redelegation{
+			DelegatorAddr:    delegatorAddr,
+			CandidateSrcAddr: srcAddr,
+			CandidateDstAddr: dstAddr,
+		}
+	}
+	red.Entries = append(red.Entries, RedelegationEntry{
+		CreationHeight: ctx.BlockHeight(),
+		CompletionTime: completionTime,
+		InitialBalance: tokens,
+		SharesDst:      sharesDst,
+	})
+	k.setRedelegation(ctx, red)
+	k.insertRedelegationQueue(ctx, red, completionTime)
+	return completionTime
+}
+
+func (k Keeper) insertRedelegationQueue(ctx sdk.Context, red Redelegation, completionTime int64) {
+	store := ctx.KVStore(k.storeKey)
+	key := GetRedelegationKey(red.DelegatorAddr, red.CandidateSrcAddr, red.CandidateDstAddr)
+	store.Set(append(getQueueTimeKey(RedelegationQueueKey, completionTime), key...), key)
+}
+
+// forget the matured entries, their tokens are no longer slashable
+func (k Keeper) completeRedelegation(ctx sdk.Context, red Redelegation) {
+	now := ctx.BlockHeader().Time
+	var entries []RedelegationEntry
+	for _, entry := range red.Entries {
+		if entry.CompletionTime > now {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		k.removeRedelegation(ctx, red)
+		return
+	}
+	red.Entries = entries
+	k.setRedelegation(ctx, red)
+}
+
+//_______________________________________________________________________
+
+// complete the unbonding delegations and redelegations matured by the block time
+func (k Keeper) completeMatured(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	now := ctx.BlockHeader().Time
+	for _, key := range k.dequeueMatured(store, UnbondingQueueKey, now) {
+		ubd, found := k.getUnbondingDelegation(store, key)
+		if found {
+			k.completeUnbonding(ctx, ubd)
+		}
+	}
+	for _, key := range k.dequeueMatured(store, RedelegationQueueKey, now) {
+		red, found := k.getRedelegation(store, key)
+		if found {
+			k.completeRedelegation(ctx, red)
+		}
+	}
+}
+
+// remove the queue entries completing by the time, returning the keys they point to
+func (k Keeper) dequeueMatured(store sdk.KVStore, queueKey []byte, now int64) (keys [][]byte) {
+	iterator := store.Iterator(queueKey, getQueueTimeKey(queueKey, now+1))
+	var queued [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		queued = append(queued, iterator.Key())
+		keys = append(keys, iterator.Value())
+	}
+	iterator.Close()
+	for _, key := range queued {
+		store.Delete(key)
+	}
+	return keys
+}
+
+//_______________________________________________________________________
+
+// slash the unbonding delegations and redelegations away from the candidate
+// which started at or after the infraction height, their tokens were still
+// bonded to the candidate when it misbehaved
+func (k Keeper) slashUnbondingAndRedelegations(ctx sdk.Context, candidateAddr sdk.Address,
+	infractionHeight int64, fraction sdk.Rat) {
+
+	for _, ubd := range k.getUnbondingDelegationsFromCandidate(ctx, candidateAddr) {
+		var burned int64
+		for i, entry := range ubd.Entries {
+			if entry.CreationHeight < infractionHeight {
+				continue
+			}
+			slashAmount := sdk.NewRat(entry.InitialBalance).Mul(fraction).Evaluate()
+			if slashAmount > entry.Balance {
+				slashAmount = entry.Balance
+			}
+			ubd.Entries[i].Balance -= slashAmount
+			burned += slashAmount
+		}
+		k.setUnbondingDelegation(ctx, ubd)
+		k.burnTokens(ctx, burned)
+	}
+
+	for _, red := range k.getRedelegationsFromCandidate(ctx, candidateAddr) {
+		for i, entry := range red.Entries {
+			if entry.CreationHeight < infractionHeight {
+				continue
+			}
+			bond, found := k.GetDelegatorBond(ctx, red.DelegatorAddr, red.CandidateDstAddr)
+			if !found {
+				continue
+			}
+			candidate, found := k.GetCandidate(ctx, red.CandidateDstAddr)
+			if !found {
+				continue
+			}
+
+			// unbond the share of the destination bond bought by the slashed tokens and burn them
+			shares := entry.SharesDst.Mul(fraction)
+			if shares.GT(bond.Shares) {
+				shares = bond.Shares
+			}
+			if shares.IsZero() {
+				continue
+			}
+			burned, err := unbond(ctx, k, bond, candidate, shares)
+			if err != nil {
+				panic(err)
+			}
+			red.Entries[i].SharesDst = entry.SharesDst.Sub(shares)
+			k.burnTokens(ctx, burned)
+		}
+		k.setRedelegation(ctx, red)
+	}
+}
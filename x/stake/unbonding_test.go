@@ -0,0 +1,152 @@
+package stake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+
+	sdk "inschain-tendermint/types"
+)
+
+func TestUnbondingDelegationMatures(t *testing.T) {
+	initBond := int64(1000)
+	ctx, accMapper, keeper := createTestInput(t, false, initBond)
+	params := keeper.GetParams(ctx)
+	candidateAddr, delegatorAddr := addrs[0], addrs[1]
+
+	got := handleMsgDeclareCandidacy(ctx, newTestMsgDeclareCandidacy(candidateAddr, pks[0], 10), keeper)
+	require.True(t, got.IsOK(), "expected declare-candidacy to be ok, got %v", got)
+	got = handleMsgDelegate(ctx, newTestMsgDelegate(delegatorAddr, candidateAddr, 100), keeper)
+	require.True(t, got.IsOK(), "expected delegation to be ok, got %v", got)
+
+	// unbond twice, a day apart
+	got = handleMsgUnbond(ctx, NewMsgUnbond(delegatorAddr, candidateAddr, "30"), keeper)
+	require.True(t, got.IsOK(), "expected unbond to be ok, got %v", got)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 60 * 60 * 24})
+	got = handleMsgUnbond(ctx, NewMsgUnbond(delegatorAddr, candidateAddr, "20"), keeper)
+	require.True(t, got.IsOK(), "expected unbond to be ok, got %v", got)
+
+	ubd, found := keeper.GetUnbondingDelegation(ctx, delegatorAddr, candidateAddr)
+	require.True(t, found)
+	require.Equal(t, 2, len(ubd.Entries))
+	assert.Equal(t, params.UnbondingTime, ubd.Entries[0].CompletionTime)
+	assert.Equal(t, 60*60*24+params.UnbondingTime, ubd.Entries[1].CompletionTime)
+	require.Equal(t, 1, len(keeper.GetUnbondingDelegations(ctx, delegatorAddr)))
+
+	// nothing is returned before the first entry matures
+	ctx = ctx.WithBlockHeader(abci.Header{Time: params.UnbondingTime - 1})
+	keeper.completeMatured(ctx)
+	assert.Equal(t, initBond-100, accMapper.GetAccount(ctx, delegatorAddr).GetCoins().AmountOf(params.BondDenom))
+
+	// the first entry matures
+	ctx = ctx.WithBlockHeader(abci.Header{Time: params.UnbondingTime})
+	keeper.completeMatured(ctx)
+	assert.Equal(t, initBond-70, accMapper.GetAccount(ctx, delegatorAddr).GetCoins().AmountOf(params.BondDenom))
+	ubd, found = keeper.GetUnbondingDelegation(ctx, delegatorAddr, candidateAddr)
+	require.True(t, found)
+	require.Equal(t, 1, len(ubd.Entries))
+
+	// the second one too, which removes the unbonding delegation
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 60*60*24*2 + params.UnbondingTime})
+	keeper.completeMatured(ctx)
+	assert.Equal(t, initBond-50, accMapper.GetAccount(ctx, delegatorAddr).GetCoins().AmountOf(params.BondDenom))
+	_, found = keeper.GetUnbondingDelegation(ctx, delegatorAddr, candidateAddr)
+	assert.False(t, found)
+}
+
+func TestRedelegation(t *testing.T) {
+	initBond := int64(1000)
+	ctx, accMapper, keeper := createTestInput(t, false, initBond)
+	params := keeper.GetParams(ctx)
+	srcAddr, dstAddr, otherAddr, delegatorAddr := addrs[0], addrs[1], addrs[2], addrs[3]
+
+	for i, candidateAddr := range []sdk.Address{srcAddr, dstAddr, otherAddr} {
+		got := handleMsgDeclareCandidacy(ctx, newTestMsgDeclareCandidacy(candidateAddr, pks[i], 10), keeper)
+		require.True(t, got.IsOK(), "expected declare-candidacy %d to be ok, got %v", i, got)
+	}
+	got := handleMsgDelegate(ctx, newTestMsgDelegate(delegatorAddr, srcAddr, 100), keeper)
+	require.True(t, got.IsOK(), "expected delegation to be ok, got %v", got)
+
+	// can't redelegate to the same candidate
+	msgRedelegate := NewMsgRedelegate(delegatorAddr, srcAddr, srcAddr, "10")
+	assert.NotNil(t, msgRedelegate.ValidateBasic())
+
+	// the tokens move to the destination without any waiting
+	got = handleMsgRedelegate(ctx, NewMsgRedelegate(delegatorAddr, srcAddr, dstAddr, "40"), keeper)
+	require.True(t, got.IsOK(), "expected redelegation to be ok, got %v", got)
+	srcBond, found := keeper.GetDelegatorBond(ctx, delegatorAddr, srcAddr)
+	require.True(t, found)
+	assert.Equal(t, int64(60), srcBond.Shares.Evaluate())
+	dstBond, found := keeper.GetDelegatorBond(ctx, delegatorAddr, dstAddr)
+	require.True(t, found)
+	assert.Equal(t, int64(40), dstBond.Shares.Evaluate())
+	assert.Equal(t, initBond-100, accMapper.GetAccount(ctx, delegatorAddr).GetCoins().AmountOf(params.BondDenom))
+
+	red, found := keeper.GetRedelegation(ctx, delegatorAddr, srcAddr, dstAddr)
+	require.True(t, found)
+	require.Equal(t, 1, len(red.Entries))
+	assert.Equal(t, int64(40), red.Entries[0].InitialBalance)
+	require.Equal(t, 1, len(keeper.GetRedelegations(ctx, delegatorAddr)))
+
+	// the redelegated tokens can't hop again before the redelegation completed
+	got = handleMsgRedelegate(ctx, NewMsgRedelegate(delegatorAddr, dstAddr, otherAddr, "10"), keeper)
+	assert.False(t, got.IsOK(), "expected transitive redelegation to fail")
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: params.UnbondingTime})
+	keeper.completeMatured(ctx)
+	_, found = keeper.GetRedelegation(ctx, delegatorAddr, srcAddr, dstAddr)
+	require.False(t, found)
+
+	got = handleMsgRedelegate(ctx, NewMsgRedelegate(delegatorAddr, dstAddr, otherAddr, "10"), keeper)
+	assert.True(t, got.IsOK(), "expected redelegation to be ok, got %v", got)
+}
+
+func TestSlashUnbondingAndRedelegations(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 1000)
+	srcAddr, dstAddr, delegatorAddr := addrs[0], addrs[1], addrs[2]
+
+	for i, candidateAddr := range []sdk.Address{srcAddr, dstAddr} {
+		got := handleMsgDeclareCandidacy(ctx, newTestMsgDeclareCandidacy(candidateAddr, pks[i], 100), keeper)
+		require.True(t, got.IsOK(), "expected declare-candidacy %d to be ok, got %v", i, got)
+	}
+	got := handleMsgDelegate(ctx, newTestMsgDelegate(delegatorAddr, srcAddr, 400), keeper)
+	require.True(t, got.IsOK(), "expected delegation to be ok, got %v", got)
+
+	// unbond and redelegate before and after the infraction height
+	got = handleMsgUnbond(ctx.WithBlockHeight(1), NewMsgUnbond(delegatorAddr, srcAddr, "100"), keeper)
+	require.True(t, got.IsOK(), "expected unbond to be ok, got %v", got)
+	got = handleMsgUnbond(ctx.WithBlockHeight(10), NewMsgUnbond(delegatorAddr, srcAddr, "100"), keeper)
+	require.True(t, got.IsOK(), "expected unbond to be ok, got %v", got)
+	got = handleMsgRedelegate(ctx.WithBlockHeight(10), NewMsgRedelegate(delegatorAddr, srcAddr, dstAddr, "100"), keeper)
+	require.True(t, got.IsOK(), "expected redelegation to be ok, got %v", got)
+	supply := keeper.GetTotalSupply(ctx)
+	escrow := keeper.supplyKeeper.GetEscrow(ctx, MsgType).AmountOf("steak")
+
+	err := keeper.Slash(ctx.WithBlockHeight(12), srcAddr, 5, sdk.NewRat(1, 2))
+	require.Nil(t, err)
+
+	// only the entries created after the infraction are slashed
+	ubd, found := keeper.GetUnbondingDelegation(ctx, delegatorAddr, srcAddr)
+	require.True(t, found)
+	require.Equal(t, 2, len(ubd.Entries))
+	assert.Equal(t, int64(100), ubd.Entries[0].Balance)
+	assert.Equal(t, int64(50), ubd.Entries[1].Balance)
+
+	// half of the redelegated shares are unbonded from the destination and burned
+	dstBond, found := keeper.GetDelegatorBond(ctx, delegatorAddr, dstAddr)
+	require.True(t, found)
+	assert.Equal(t, int64(50), dstBond.Shares.Evaluate())
+	red, found := keeper.GetRedelegation(ctx, delegatorAddr, srcAddr, dstAddr)
+	require.True(t, found)
+	assert.Equal(t, int64(50), red.Entries[0].SharesDst.Evaluate())
+
+	// the source candidate lost half its tokens too
+	assert.Equal(t, supply-50-50-100, keeper.GetTotalSupply(ctx))
+
+	// the burned tokens left the escrow and the supply
+	assert.Equal(t, escrow-50-50-100, keeper.supplyKeeper.GetEscrow(ctx, MsgType).AmountOf("steak"))
+	assert.Equal(t, 1000*int64(len(addrs))-50-50-100, keeper.supplyKeeper.GetSupply(ctx, "steak"))
+}
@@ -1,7 +1,7 @@
 package stake
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 )
 
 // keeper to view information & slash validators
@@ -22,8 +22,46 @@ func (v ViewSlashKeeper) GetDelegatorBond(ctx sdk.Context,
 	return v.keeper.GetDelegatorBond(ctx, delegatorAddr, candidateAddr)
 }
 
-// load n delegator bonds
+// load a page of delegator bonds
 func (v ViewSlashKeeper) GetDelegatorBonds(ctx sdk.Context,
-	delegator sdk.Address, maxRetrieve int16) (bonds []DelegatorBond) {
-	return v.keeper.GetDelegatorBonds(ctx, delegator, maxRetrieve)
+	delegator, start sdk.Address, limit int) (bonds []DelegatorBond, next sdk.Address) {
+	return v.keeper.GetDelegatorBonds(ctx, delegator, start, limit)
+}
+
+// iterate through the bonds of a delegator until process returns true
+func (v ViewSlashKeeper) IterateDelegatorBonds(ctx sdk.Context,
+	delegator sdk.Address, process func(bond DelegatorBond) (stop bool)) {
+	v.keeper.IterateDelegatorBonds(ctx, delegator, process)
+}
+
+// load a candidate
+func (v ViewSlashKeeper) GetCandidate(ctx sdk.Context, addr sdk.Address) (candidate Candidate, found bool) {
+	return v.keeper.GetCandidate(ctx, addr)
+}
+
+// load a candidate by the address of its consensus pubkey
+func (v ViewSlashKeeper) GetCandidateByPubKeyAddress(ctx sdk.Context,
+	pubKeyAddr sdk.Address) (candidate Candidate, found bool) {
+	return v.keeper.GetCandidateByPubKeyAddress(ctx, pubKeyAddr)
+}
+
+// load the validators currently bonded, largest power first
+func (v ViewSlashKeeper) GetBondedValidators(ctx sdk.Context) []Validator {
+	return v.keeper.getBondedValidators(ctx)
+}
+
+// slash a fraction of the tokens backing a candidate at the infraction height
+func (v ViewSlashKeeper) Slash(ctx sdk.Context, candidateAddr sdk.Address,
+	infractionHeight int64, fraction sdk.Rat) sdk.Error {
+	return v.keeper.Slash(ctx, candidateAddr, infractionHeight, fraction)
+}
+
+// remove a candidate from the validator set
+func (v ViewSlashKeeper) Jail(ctx sdk.Context, candidateAddr sdk.Address) sdk.Error {
+	return v.keeper.Jail(ctx, candidateAddr)
+}
+
+// allow a jailed candidate back into the validator set
+func (v ViewSlashKeeper) Unjail(ctx sdk.Context, candidateAddr sdk.Address) sdk.Error {
+	return v.keeper.Unjail(ctx, candidateAddr)
 }
@@ -3,7 +3,7 @@ package stake
 import (
 	"testing"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdk "inschain-tendermint/types"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -56,11 +56,11 @@ func TestViewSlashBond(t *testing.T) {
 	// add some more records
 	keeper.setCandidate(ctx, candidates[1])
 	keeper.setCandidate(ctx, candidates[2])
-	bond1to2 := DelegatorBond{addrDels[0], addrVals[1], sdk.NewRat(9), 0}
-	bond1to3 := DelegatorBond{addrDels[0], addrVals[2], sdk.NewRat(9), 1}
-	bond2to1 := DelegatorBond{addrDels[1], addrVals[0], sdk.NewRat(9), 2}
-	bond2to2 := DelegatorBond{addrDels[1], addrVals[1], sdk.NewRat(9), 3}
-	bond2to3 := DelegatorBond{addrDels[1], addrVals[2], sdk.NewRat(9), 4}
+	bond1to2 := DelegatorBond{addrDels[0], addrVals[1], sdk.NewRat(9), 0, nil}
+	bond1to3 := DelegatorBond{addrDels[0], addrVals[2], sdk.NewRat(9), 1, nil}
+	bond2to1 := DelegatorBond{addrDels[1], addrVals[0], sdk.NewRat(9), 2, nil}
+	bond2to2 := DelegatorBond{addrDels[1], addrVals[1], sdk.NewRat(9), 3, nil}
+	bond2to3 := DelegatorBond{addrDels[1], addrVals[2], sdk.NewRat(9), 4, nil}
 	keeper.setDelegatorBond(ctx, bond1to2)
 	keeper.setDelegatorBond(ctx, bond1to3)
 	keeper.setDelegatorBond(ctx, bond2to1)
@@ -68,19 +68,69 @@ func TestViewSlashBond(t *testing.T) {
 	keeper.setDelegatorBond(ctx, bond2to3)
 
 	// test all bond retrieve capabilities
-	resBonds := viewSlashKeeper.GetDelegatorBonds(ctx, addrDels[0], 5)
+	resBonds, _ := viewSlashKeeper.GetDelegatorBonds(ctx, addrDels[0], nil, 0)
 	require.Equal(t, 3, len(resBonds))
 	assert.True(t, bondsEqual(bond1to1, resBonds[0]))
 	assert.True(t, bondsEqual(bond1to2, resBonds[1]))
 	assert.True(t, bondsEqual(bond1to3, resBonds[2]))
-	resBonds = viewSlashKeeper.GetDelegatorBonds(ctx, addrDels[0], 3)
+	resBonds, _ = viewSlashKeeper.GetDelegatorBonds(ctx, addrDels[0], nil, 3)
 	require.Equal(t, 3, len(resBonds))
-	resBonds = viewSlashKeeper.GetDelegatorBonds(ctx, addrDels[0], 2)
+	resBonds, _ = viewSlashKeeper.GetDelegatorBonds(ctx, addrDels[0], nil, 2)
 	require.Equal(t, 2, len(resBonds))
-	resBonds = viewSlashKeeper.GetDelegatorBonds(ctx, addrDels[1], 5)
+	resBonds, _ = viewSlashKeeper.GetDelegatorBonds(ctx, addrDels[1], nil, 0)
 	require.Equal(t, 3, len(resBonds))
 	assert.True(t, bondsEqual(bond2to1, resBonds[0]))
 	assert.True(t, bondsEqual(bond2to2, resBonds[1]))
 	assert.True(t, bondsEqual(bond2to3, resBonds[2]))
 
 }
+
+// tests Slash, Jail, Unjail
+func TestViewSlashJail(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	viewSlashKeeper := NewViewSlashKeeper(keeper)
+
+	pool := keeper.GetPool(ctx)
+	pool.UnbondedPool = 100
+	pool.UnbondedShares = sdk.NewRat(100)
+	keeper.setPool(ctx, pool)
+	require.Nil(t, keeper.supplyKeeper.MintEscrow(ctx, MsgType, sdk.Coins{{"steak", 100}}))
+	candidate := Candidate{
+		Status:      Unbonded,
+		Address:     addrVals[0],
+		PubKey:      pks[0],
+		Assets:      sdk.NewRat(100),
+		Liabilities: sdk.NewRat(100),
+	}
+	keeper.setCandidate(ctx, candidate)
+	keeper.clearAccUpdateValidators(ctx)
+
+	resCand, found := viewSlashKeeper.GetCandidateByPubKeyAddress(ctx, pks[0].Address())
+	require.True(t, found)
+	assert.Equal(t, addrVals[0], resCand.Address)
+	require.Equal(t, 1, len(viewSlashKeeper.GetBondedValidators(ctx)))
+
+	// slashing burns tokens and lowers the exchange rate
+	require.Nil(t, viewSlashKeeper.Slash(ctx, addrVals[0], 0, sdk.NewRat(1, 4)))
+	resCand, _ = viewSlashKeeper.GetCandidate(ctx, addrVals[0])
+	assert.True(t, resCand.delegatorShareExRate().Equal(sdk.NewRat(3, 4)))
+	assert.Equal(t, int64(75), keeper.GetTotalSupply(ctx))
+	keeper.clearAccUpdateValidators(ctx)
+
+	// jailed candidates leave the validator set
+	require.Nil(t, viewSlashKeeper.Jail(ctx, addrVals[0]))
+	assert.Equal(t, 0, len(viewSlashKeeper.GetBondedValidators(ctx)))
+	acc := keeper.getAccUpdateValidators(ctx)
+	require.Equal(t, 1, len(acc))
+	assert.Equal(t, int64(0), acc[0].Power)
+	keeper.clearAccUpdateValidators(ctx)
+
+	// and come back once unjailed
+	require.Nil(t, viewSlashKeeper.Unjail(ctx, addrVals[0]))
+	require.Equal(t, 1, len(viewSlashKeeper.GetBondedValidators(ctx)))
+	acc = keeper.getAccUpdateValidators(ctx)
+	require.Equal(t, 1, len(acc))
+	assert.Equal(t, int64(75), acc[0].Power)
+
+	assert.NotNil(t, viewSlashKeeper.Jail(ctx, addrVals[1]))
+}
@@ -1,7 +1,7 @@
 package stake
 
 import (
-	"github.com/cosmos/cosmos-sdk/wire"
+	"inschain-tendermint/wire"
 )
 
 // Register concrete types on wire codec
@@ -10,4 +10,6 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(MsgEditCandidacy{}, "cosmos-sdk/MsgEditCandidacy", nil)
 	cdc.RegisterConcrete(MsgDelegate{}, "cosmos-sdk/MsgDelegate", nil)
 	cdc.RegisterConcrete(MsgUnbond{}, "cosmos-sdk/MsgUnbond", nil)
+	cdc.RegisterConcrete(MsgRedelegate{}, "cosmos-sdk/MsgRedelegate", nil)
+	cdc.RegisterConcrete(MsgWithdraw{}, "cosmos-sdk/MsgWithdraw", nil)
 }
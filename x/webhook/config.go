@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"time"
+
+	bam "inschain-tendermint/baseapp"
+)
+
+// default delivery settings
+const (
+	DefaultMaxAttempts    = 10
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = 10 * time.Minute
+	DefaultTimeout        = 10 * time.Second
+)
+
+// Subscription maps an event filter to an HTTP endpoint
+type Subscription struct {
+	Name   string          `json:"name"`
+	URL    string          `json:"url"`
+	Secret string          `json:"secret"` // HMAC-SHA256 key for the payload signature
+	Filter bam.EventFilter `json:"filter"`
+}
+
+// Config is the webhook configuration file, eg.
+//
+//	{
+//	  "subscriptions": [{
+//	    "name": "claims",
+//	    "url": "https://backoffice.example.com/hooks/claims",
+//	    "secret": "...",
+//	    "filter": {"modules": ["mutual"], "tags": {"action": "collect-claim"}}
+//	  }]
+//	}
+//
+// Durations are given in nanoseconds, zero values use the defaults.
+type Config struct {
+	Subscriptions  []Subscription `json:"subscriptions"`
+	MaxAttempts    int            `json:"max_attempts"`
+	InitialBackoff time.Duration  `json:"initial_backoff"`
+	MaxBackoff     time.Duration  `json:"max_backoff"`
+	Timeout        time.Duration  `json:"timeout"`
+}
+
+// LoadConfig reads and validates a configuration file
+func LoadConfig(path string) (Config, error) {
+	var config Config
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	err = json.Unmarshal(bz, &config)
+	if err != nil {
+		return config, err
+	}
+	config = config.withDefaults()
+	return config, config.Validate()
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultMaxAttempts
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = DefaultInitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultMaxBackoff
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	return c
+}
+
+// Validate checks the subscriptions have unique names and valid endpoints
+func (c Config) Validate() error {
+	names := make(map[string]bool)
+	for _, sub := range c.Subscriptions {
+		if sub.Name == "" {
+			return fmt.Errorf("subscription without a name")
+		}
+		if names[sub.Name] {
+			return fmt.Errorf("duplicate subscription %s", sub.Name)
+		}
+		names[sub.Name] = true
+		u, err := url.Parse(sub.URL)
+		if err != nil {
+			return fmt.Errorf("subscription %s: %v", sub.Name, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("subscription %s: unsupported url %s", sub.Name, sub.URL)
+		}
+	}
+	return nil
+}
+
+// backoff returns the delay before the next attempt of a delivery
+func (c Config) backoff(attempts int) time.Duration {
+	delay := c.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= c.MaxBackoff {
+			return c.MaxBackoff
+		}
+	}
+	return delay
+}
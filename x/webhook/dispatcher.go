@@ -0,0 +1,307 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/tendermint/tmlibs/log"
+
+	"inschain-tendermint/wire"
+
+	bam "inschain-tendermint/baseapp"
+	"inschain-tendermint/x/indexer"
+)
+
+// HTTP headers set on every delivery
+const (
+	HeaderSignature = "X-Inschain-Signature"
+	HeaderDelivery  = "X-Inschain-Delivery"
+	HeaderEvent     = "X-Inschain-Event"
+
+	// name of the dispatcher on the event bus
+	SubscriberName = "webhook"
+
+	// name of the outbox database
+	DBName = "webhook"
+
+	// interval between two scans of the outbox
+	pollInterval = 500 * time.Millisecond
+
+	// delay between two attempts to reach the node
+	retryInterval = time.Second
+)
+
+// Payload is the JSON body posted to the subscription endpoints
+type Payload struct {
+	ID           string            `json:"id"`
+	Subscription string            `json:"subscription"`
+	Height       int64             `json:"height"`
+	TxIndex      int               `json:"tx_index"`
+	MsgIndex     int               `json:"msg_index"`
+	MsgType      string            `json:"msg_type"`
+	Module       string            `json:"module"`
+	Code         uint32            `json:"code"`
+	Tags         map[string]string `json:"tags"`
+	Msg          json.RawMessage   `json:"msg"`
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint: errcheck
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a body, for use by the receiving endpoints
+func Verify(secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint: errcheck
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// Dispatcher turns committed events into signed HTTP notifications.
+// Matching events are written to the outbox as soon as they are published
+// by the event bus, then delivered with exponential backoff until the
+// endpoint answers with a 2xx status. The events missed while the process
+// was down, or which couldn't be added to the outbox, are replayed from
+// the node.
+type Dispatcher struct {
+	config Config
+	subs   map[string]Subscription
+	outbox *Outbox
+	cdc    *wire.Codec
+	node   rpcclient.Client
+	client *http.Client
+	logger log.Logger
+	now    func() time.Time
+
+	// set once the blocks committed before the start are replayed
+	ready int32
+
+	// whether every event since the last replay has been added.
+	// only accessed from the subscriber goroutine.
+	following bool
+}
+
+// NewDispatcher returns a Dispatcher for a validated configuration
+func NewDispatcher(config Config, db dbm.DB, cdc *wire.Codec, logger log.Logger) *Dispatcher {
+	config = config.withDefaults()
+	subs := make(map[string]Subscription, len(config.Subscriptions))
+	for _, sub := range config.Subscriptions {
+		subs[sub.Name] = sub
+	}
+	return &Dispatcher{
+		config: config,
+		subs:   subs,
+		outbox: NewOutbox(db),
+		cdc:    cdc,
+		client: &http.Client{Timeout: config.Timeout},
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// OpenDB opens the outbox database in dir
+func OpenDB(dir string) (dbm.DB, error) {
+	return dbm.NewGoLevelDB(DBName, dir)
+}
+
+// Outbox returns the outbox of the dispatcher
+func (d *Dispatcher) Outbox() *Outbox {
+	return d.outbox
+}
+
+// Subscribe registers the dispatcher on the event bus of an app. The blocks
+// committed since the last event added to the outbox are first replayed
+// from the node, the live events are skipped until then.
+func (d *Dispatcher) Subscribe(bus *bam.EventBus, node rpcclient.Client) error {
+	d.node = node
+	// no event may be lost, a full buffer holds the app back
+	err := bus.SubscribeBlocking(SubscriberName, bam.EventFilter{}, 0, d.handle)
+	if err != nil {
+		return err
+	}
+	go d.catchUp()
+	return nil
+}
+
+// replay the blocks of the node until the latest one
+func (d *Dispatcher) catchUp() {
+	for {
+		status, err := d.node.Status()
+		if err == nil {
+			err = d.replay(status.SyncInfo.LatestBlockHeight)
+		}
+		if err == nil {
+			break
+		}
+		d.logger.Info("Waiting for node to replay the webhook events", "err", err)
+		time.Sleep(retryInterval)
+	}
+	atomic.StoreInt32(&d.ready, 1)
+}
+
+// replay enqueues the events of the blocks following the last complete one
+// up to the height. Events already in the outbox are skipped. Their payloads
+// are those of the live events; the msgs of a tx whose tags can't be
+// attributed carry none, so only subscriptions without tag filters get them.
+func (d *Dispatcher) replay(height int64) error {
+	for h := d.outbox.SyncedHeight() + 1; h <= height; h++ {
+		events, err := indexer.BlockEvents(d.node, d.cdc, h)
+		if err != nil {
+			return err
+		}
+		for _, ev := range events {
+			err = d.Enqueue(ev)
+			if err != nil {
+				return err
+			}
+		}
+		d.outbox.MarkSynced(h)
+	}
+	return nil
+}
+
+func (d *Dispatcher) handle(ev bam.TxEvent) error {
+	if atomic.LoadInt32(&d.ready) == 0 {
+		// the block is replayed from the node once caught up
+		return nil
+	}
+	if !d.following {
+		// replay the blocks committed since the last event added, up to the
+		// block of the event: the node saves the block and its results
+		// before the app commits it
+		err := d.replay(ev.Height)
+		if err != nil {
+			return fmt.Errorf("couldn't replay the webhook events up to height %d: %v", ev.Height, err)
+		}
+		d.following = true
+	}
+
+	err := d.Enqueue(ev)
+	if err != nil {
+		// the block is replayed from the node with the next event
+		d.following = false
+		return err
+	}
+	// no event is dropped, the previous blocks are complete
+	d.outbox.MarkSynced(ev.Height - 1)
+	return nil
+}
+
+// Enqueue adds a delivery to the outbox for every subscription matching the
+// event. Events at or before the cursor of the outbox are skipped.
+func (d *Dispatcher) Enqueue(ev bam.TxEvent) error {
+	if !d.outbox.Cursor().After(ev.Height, ev.TxIndex, ev.MsgIndex) {
+		return nil
+	}
+	deliveries := []Delivery{}
+	for _, sub := range d.config.Subscriptions {
+		if !sub.Filter.Matches(ev) {
+			continue
+		}
+		payload, err := d.payload(sub, ev)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, Delivery{
+			ID:           payload.ID,
+			Subscription: sub.Name,
+			Payload:      mustMarshal(payload),
+			NextAttempt:  d.now(),
+		})
+	}
+	d.outbox.Add(Cursor{Height: ev.Height, TxIndex: ev.TxIndex, MsgIndex: ev.MsgIndex}, deliveries...)
+	return nil
+}
+
+func (d *Dispatcher) payload(sub Subscription, ev bam.TxEvent) (Payload, error) {
+	msg, err := d.cdc.MarshalJSON(ev.Msg)
+	if err != nil {
+		return Payload{}, err
+	}
+	tags := make(map[string]string, len(ev.Result.Tags))
+	for _, tag := range ev.Result.Tags {
+		tags[string(tag.Key)] = string(tag.Value)
+	}
+	return Payload{
+		ID:           fmt.Sprintf("%s/%d/%d/%d", sub.Name, ev.Height, ev.TxIndex, ev.MsgIndex),
+		Subscription: sub.Name,
+		Height:       ev.Height,
+		TxIndex:      ev.TxIndex,
+		MsgIndex:     ev.MsgIndex,
+		MsgType:      ev.MsgType,
+		Module:       ev.Module,
+		Code:         uint32(ev.Result.Code),
+		Tags:         tags,
+		Msg:          msg,
+	}, nil
+}
+
+// Run delivers the due deliveries until quit is closed
+func (d *Dispatcher) Run(quit <-chan struct{}) {
+	for {
+		d.Flush()
+		select {
+		case <-quit:
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// Flush attempts every due delivery once
+func (d *Dispatcher) Flush() {
+	for _, delivery := range d.outbox.Due(d.now()) {
+		sub, ok := d.subs[delivery.Subscription]
+		if !ok {
+			// the subscription was removed from the configuration
+			d.outbox.Retry(delivery, fmt.Errorf("unknown subscription %s", delivery.Subscription), d.now(), 0)
+			continue
+		}
+		err := d.send(sub, delivery)
+		if err != nil {
+			next := d.now().Add(d.config.backoff(delivery.Attempts + 1))
+			d.logger.Info("Webhook delivery failed", "id", delivery.ID, "attempt", delivery.Attempts+1, "err", err)
+			d.outbox.Retry(delivery, err, next, d.config.MaxAttempts)
+			continue
+		}
+		d.outbox.Done(delivery.ID)
+	}
+}
+
+func (d *Dispatcher) send(sub Subscription, delivery Delivery) error {
+	req, err := http.NewRequest("POST", sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	var payload Payload
+	mustUnmarshal(delivery.Payload, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, delivery.Payload))
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderEvent, payload.MsgType)
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() // nolint: errcheck
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("endpoint answered %s", res.Status)
+	}
+	return nil
+}
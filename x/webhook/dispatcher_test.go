@@ -0,0 +1,251 @@
+package webhook
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/state"
+	tmtypes "github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/tendermint/tmlibs/log"
+
+	sdk "inschain-tendermint/types"
+	"inschain-tendermint/wire"
+
+	bam "inschain-tendermint/baseapp"
+	"inschain-tendermint/x/indexer"
+	"inschain-tendermint/x/mutual"
+)
+
+func makeTestCodec() *wire.Codec {
+	cdc := wire.NewCodec()
+	cdc.RegisterInterface((*sdk.Msg)(nil), nil)
+	mutual.RegisterWire(cdc)
+	return cdc
+}
+
+func bondEvent(height int64) bam.TxEvent {
+	msg := mutual.NewMutualBondMsg(sdk.Address([]byte("policy")), sdk.Address([]byte("member")), sdk.Coin{"getx", 10})
+	return bam.TxEvent{
+		Height:  height,
+		MsgType: bam.MsgTypeName(msg),
+		Module:  msg.Type(),
+		Msg:     msg,
+		Result:  sdk.Result{Tags: sdk.NewTags(mutual.TagAction, mutual.ActionBond)},
+	}
+}
+
+func TestDispatcherDelivers(t *testing.T) {
+	received := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	config := Config{Subscriptions: []Subscription{
+		{Name: "bonds", URL: server.URL, Secret: "secret",
+			Filter: bam.EventFilter{Tags: map[string]string{mutual.TagAction: string(mutual.ActionBond)}}},
+		{Name: "claims", URL: server.URL, Secret: "secret",
+			Filter: bam.EventFilter{Tags: map[string]string{mutual.TagAction: string(mutual.ActionClaim)}}},
+	}}
+	require.Nil(t, config.Validate())
+	d := NewDispatcher(config, dbm.NewMemDB(), makeTestCodec(), log.NewNopLogger())
+
+	require.Nil(t, d.Enqueue(bondEvent(1)))
+	// enqueueing the same event twice is a no-op
+	require.Nil(t, d.Enqueue(bondEvent(1)))
+	assert.Equal(t, 1, len(d.Outbox().Pending()))
+
+	d.Flush()
+	require.Equal(t, 1, len(received))
+	req, body := <-received, <-bodies
+	assert.True(t, Verify("secret", body, req.Header.Get(HeaderSignature)))
+	assert.False(t, Verify("other", body, req.Header.Get(HeaderSignature)))
+	assert.Equal(t, "bonds/1/0/0", req.Header.Get(HeaderDelivery))
+	assert.Equal(t, "MutualBondMsg", req.Header.Get(HeaderEvent))
+	assert.Equal(t, 0, len(d.Outbox().Pending()))
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	failures := 2
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	config := Config{
+		Subscriptions: []Subscription{{Name: "all", URL: server.URL, Secret: "secret"}},
+		MaxAttempts:   3,
+	}
+	d := NewDispatcher(config, dbm.NewMemDB(), makeTestCodec(), log.NewNopLogger())
+	now := time.Unix(1000, 0)
+	d.now = func() time.Time { return now }
+
+	require.Nil(t, d.Enqueue(bondEvent(1)))
+	d.Flush()
+	pending := d.Outbox().Pending()
+	require.Equal(t, 1, len(pending))
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.True(t, now.Add(DefaultInitialBackoff).Equal(pending[0].NextAttempt))
+
+	// nothing is due before the backoff
+	d.Flush()
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(time.Second)
+	d.Flush()
+	assert.Equal(t, 2, d.Outbox().Pending()[0].Attempts)
+
+	now = now.Add(2 * time.Second)
+	d.Flush()
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 0, len(d.Outbox().Pending()))
+	assert.Equal(t, 0, len(d.Outbox().Dead()))
+}
+
+func TestDispatcherDeadLetters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	config := Config{
+		Subscriptions: []Subscription{{Name: "all", URL: server.URL}},
+		MaxAttempts:   1,
+	}
+	d := NewDispatcher(config, dbm.NewMemDB(), makeTestCodec(), log.NewNopLogger())
+	require.Nil(t, d.Enqueue(bondEvent(1)))
+	d.Flush()
+	assert.Equal(t, 0, len(d.Outbox().Pending()))
+	dead := d.Outbox().Dead()
+	require.Equal(t, 1, len(dead))
+
+	require.Nil(t, d.Outbox().Requeue(dead[0].ID))
+	assert.Equal(t, 1, len(d.Outbox().Pending()))
+}
+
+func TestDispatcherCursor(t *testing.T) {
+	config := Config{Subscriptions: []Subscription{{Name: "all", URL: "http://localhost"}}}
+	db := dbm.NewMemDB()
+	d := NewDispatcher(config, db, makeTestCodec(), log.NewNopLogger())
+	d.ready, d.following = 1, true
+
+	require.Nil(t, d.handle(bondEvent(1)))
+	require.Nil(t, d.handle(bondEvent(3)))
+	assert.Equal(t, Cursor{3, 0, 0}, d.Outbox().Cursor())
+	assert.Equal(t, int64(2), d.Outbox().SyncedHeight())
+	assert.Equal(t, 2, len(d.Outbox().Pending()))
+
+	// the cursor survives a restart, replayed events are skipped
+	d = NewDispatcher(config, db, makeTestCodec(), log.NewNopLogger())
+	d.Outbox().Done("all/1/0/0")
+	require.Nil(t, d.Enqueue(bondEvent(1)))
+	require.Nil(t, d.Enqueue(bondEvent(3)))
+	assert.Equal(t, 1, len(d.Outbox().Pending()))
+	assert.Equal(t, Cursor{3, 0, 0}, d.Outbox().Cursor())
+}
+
+// a node serving a single block
+type testNode struct {
+	rpcclient.Client
+	txs     tmtypes.Txs
+	results []*abci.ResponseDeliverTx
+}
+
+func (n testNode) Block(height *int64) (*ctypes.ResultBlock, error) {
+	return &ctypes.ResultBlock{Block: &tmtypes.Block{Data: &tmtypes.Data{Txs: n.txs}}}, nil
+}
+
+func (n testNode) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
+	return &ctypes.ResultBlockResults{Height: *height, Results: &state.ABCIResponses{DeliverTx: n.results}}, nil
+}
+
+func TestDispatcherReplayMatchesLive(t *testing.T) {
+	cdc := makeTestCodec()
+	app := bam.NewBaseApp("webhook", cdc, log.NewNopLogger(), dbm.NewMemDB())
+	key := sdk.NewKVStoreKey("main")
+	app.MountStoresIAVL(key)
+	app.Router().AddRoute("mutual", func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		bond := msg.(mutual.MutualBondMsg)
+		return sdk.Result{
+			Data: bond.Address,
+			Tags: sdk.NewTags(mutual.TagAction, mutual.ActionBond, mutual.TagMember, []byte(bond.Address.String())),
+		}
+	})
+	require.Nil(t, app.LoadLatestVersion(key))
+
+	policy := sdk.Address([]byte("policy"))
+	member0, member1 := sdk.Address([]byte("member0")), sdk.Address([]byte("member1"))
+	config := Config{Subscriptions: []Subscription{
+		{Name: "all", URL: "http://localhost"},
+		{Name: "member1", URL: "http://localhost",
+			Filter: bam.EventFilter{Tags: map[string]string{mutual.TagMember: member1.String()}}},
+	}}
+	now := func() time.Time { return time.Unix(1527379200, 0) }
+	live := NewDispatcher(config, dbm.NewMemDB(), cdc, log.NewNopLogger())
+	live.now = now
+	require.Nil(t, app.EventBus().SubscribeBlocking("live", bam.EventFilter{}, 0, live.Enqueue))
+
+	tx := sdk.StdTx{Msgs: []sdk.Msg{
+		mutual.NewMutualBondMsg(policy, member0, sdk.Coin{"getx", 10}),
+		mutual.NewMutualBondMsg(policy, member1, sdk.Coin{"getx", 10}),
+	}}
+	txBytes, err := cdc.MarshalBinary(tx)
+	require.Nil(t, err)
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+	res := app.DeliverTx(txBytes)
+	require.Equal(t, uint32(0), res.Code, res.Log)
+	app.EndBlock(abci.RequestEndBlock{})
+	app.Commit()
+	app.EventBus().Stop()
+
+	// the events replayed from the node carry the tags of their msg
+	replayed := NewDispatcher(config, dbm.NewMemDB(), cdc, log.NewNopLogger())
+	replayed.now = now
+	events, err := indexer.BlockEvents(testNode{txs: tmtypes.Txs{txBytes}, results: []*abci.ResponseDeliverTx{&res}}, cdc, 1)
+	require.Nil(t, err)
+	for _, ev := range events {
+		require.Nil(t, replayed.Enqueue(ev))
+	}
+	require.Equal(t, 3, len(live.Outbox().Pending()))
+	assert.Equal(t, live.Outbox().Pending(), replayed.Outbox().Pending())
+
+	// tags which can't be attributed to a msg don't match any filter
+	res.Log = ""
+	replayed = NewDispatcher(config, dbm.NewMemDB(), cdc, log.NewNopLogger())
+	events, err = indexer.BlockEvents(testNode{txs: tmtypes.Txs{txBytes}, results: []*abci.ResponseDeliverTx{&res}}, cdc, 1)
+	require.Nil(t, err)
+	for _, ev := range events {
+		require.Nil(t, replayed.Enqueue(ev))
+	}
+	for _, delivery := range replayed.Outbox().Pending() {
+		assert.Equal(t, "all", delivery.Subscription)
+	}
+}
+
+func TestConfigBackoff(t *testing.T) {
+	config := Config{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, config.backoff(1))
+	assert.Equal(t, 2*time.Second, config.backoff(2))
+	assert.Equal(t, 4*time.Second, config.backoff(3))
+	assert.Equal(t, 5*time.Second, config.backoff(4))
+
+	assert.NotNil(t, Config{Subscriptions: []Subscription{{Name: "a", URL: "ftp://x"}}}.Validate())
+	assert.NotNil(t, Config{Subscriptions: []Subscription{{Name: "a", URL: "http://x"}, {Name: "a", URL: "http://y"}}}.Validate())
+}
@@ -0,0 +1,215 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	sdk "inschain-tendermint/types"
+)
+
+var (
+	cursorKey     = []byte("meta/cursor")
+	syncedKey     = []byte("meta/synced")
+	pendingPrefix = []byte("pending/")
+	deadPrefix    = []byte("dead/")
+)
+
+// Cursor is the position of the last event added to the outbox
+type Cursor struct {
+	Height   int64 `json:"height"`
+	TxIndex  int   `json:"tx_index"`
+	MsgIndex int   `json:"msg_index"`
+}
+
+// After returns whether the position is strictly after the cursor
+func (c Cursor) After(height int64, txIndex, msgIndex int) bool {
+	if height != c.Height {
+		return height > c.Height
+	}
+	if txIndex != c.TxIndex {
+		return txIndex > c.TxIndex
+	}
+	return msgIndex > c.MsgIndex
+}
+
+// Delivery is a payload waiting to be sent to a subscription endpoint
+type Delivery struct {
+	ID           string          `json:"id"`
+	Subscription string          `json:"subscription"`
+	Payload      json.RawMessage `json:"payload"`
+	Attempts     int             `json:"attempts"`
+	NextAttempt  time.Time       `json:"next_attempt"`
+	LastError    string          `json:"last_error,omitempty"`
+}
+
+// Outbox stores the deliveries on disk until they are acknowledged,
+// so notifications survive a restart of the process.
+// Deliveries which exhaust their attempts are moved to a dead letter list.
+// The position of the last event added is stored with its deliveries, so the
+// events missed while the process was down can be replayed from the node.
+type Outbox struct {
+	mtx sync.Mutex
+	db  dbm.DB
+}
+
+// NewOutbox returns an Outbox backed by the database
+func NewOutbox(db dbm.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+func pendingKey(id string) []byte { return append(append([]byte{}, pendingPrefix...), id...) }
+func deadKey(id string) []byte    { return append(append([]byte{}, deadPrefix...), id...) }
+
+// Add stores the deliveries of the event at the cursor, skipping those which
+// already exist, and moves the cursor
+func (o *Outbox) Add(cursor Cursor, deliveries ...Delivery) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	batch := o.db.NewBatch()
+	for _, d := range deliveries {
+		if o.db.Has(pendingKey(d.ID)) || o.db.Has(deadKey(d.ID)) {
+			continue
+		}
+		batch.Set(pendingKey(d.ID), mustMarshal(d))
+	}
+	batch.Set(cursorKey, mustMarshal(cursor))
+	batch.WriteSync()
+}
+
+// Cursor returns the position of the last event added
+func (o *Outbox) Cursor() Cursor {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	var cursor Cursor
+	if bz := o.db.Get(cursorKey); bz != nil {
+		mustUnmarshal(bz, &cursor)
+	}
+	return cursor
+}
+
+// SyncedHeight returns the height of the last block whose events were all added
+func (o *Outbox) SyncedHeight() int64 {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	var height int64
+	if bz := o.db.Get(syncedKey); bz != nil {
+		mustUnmarshal(bz, &height)
+	}
+	return height
+}
+
+// MarkSynced records that the events of every block up to the height were added
+func (o *Outbox) MarkSynced(height int64) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	var synced int64
+	if bz := o.db.Get(syncedKey); bz != nil {
+		mustUnmarshal(bz, &synced)
+	}
+	if height <= synced {
+		return
+	}
+	o.db.SetSync(syncedKey, mustMarshal(height))
+}
+
+// Due returns the pending deliveries whose next attempt is before now
+func (o *Outbox) Due(now time.Time) []Delivery {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	due := []Delivery{}
+	for _, d := range o.list(pendingPrefix) {
+		if !d.NextAttempt.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due
+}
+
+// Pending returns all the pending deliveries
+func (o *Outbox) Pending() []Delivery {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return o.list(pendingPrefix)
+}
+
+// Dead returns the deliveries which exhausted their attempts
+func (o *Outbox) Dead() []Delivery {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return o.list(deadPrefix)
+}
+
+// Done removes an acknowledged delivery
+func (o *Outbox) Done(id string) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.db.DeleteSync(pendingKey(id))
+}
+
+// Retry records a failed attempt, and moves the delivery to the dead letters
+// once it has been attempted maxAttempts times
+func (o *Outbox) Retry(d Delivery, err error, next time.Time, maxAttempts int) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	d.Attempts++
+	d.LastError = err.Error()
+	d.NextAttempt = next
+	if d.Attempts >= maxAttempts {
+		batch := o.db.NewBatch()
+		batch.Delete(pendingKey(d.ID))
+		batch.Set(deadKey(d.ID), mustMarshal(d))
+		batch.Write()
+		return
+	}
+	o.db.SetSync(pendingKey(d.ID), mustMarshal(d))
+}
+
+// Requeue moves a dead delivery back to the pending list
+func (o *Outbox) Requeue(id string) error {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	bz := o.db.Get(deadKey(id))
+	if bz == nil {
+		return fmt.Errorf("no dead delivery %s", id)
+	}
+	var d Delivery
+	mustUnmarshal(bz, &d)
+	d.Attempts = 0
+	d.NextAttempt = time.Time{}
+	batch := o.db.NewBatch()
+	batch.Delete(deadKey(id))
+	batch.Set(pendingKey(id), mustMarshal(d))
+	batch.Write()
+	return nil
+}
+
+func (o *Outbox) list(prefix []byte) []Delivery {
+	deliveries := []Delivery{}
+	iter := o.db.Iterator(prefix, sdk.PrefixEndBytes(prefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var d Delivery
+		mustUnmarshal(iter.Value(), &d)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries
+}
+
+func mustMarshal(o interface{}) []byte {
+	bz, err := json.Marshal(o)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+func mustUnmarshal(bz []byte, ptr interface{}) {
+	err := json.Unmarshal(bz, ptr)
+	if err != nil {
+		panic(err)
+	}
+}